@@ -0,0 +1,140 @@
+package bitcoin
+
+import (
+	"crypto/sha256"
+	"errors"
+	"strings"
+)
+
+// Network holds the address encoding parameters of a Bitcoin network.
+type Network struct {
+	Name             string
+	PubKeyHashPrefix byte
+	ScriptHashPrefix byte
+	Bech32HRP        string
+}
+
+var (
+	// MainNet is the Bitcoin main network.
+	MainNet = &Network{Name: "mainnet", PubKeyHashPrefix: 0x00, ScriptHashPrefix: 0x05, Bech32HRP: "bc"}
+	// TestNet is the Bitcoin test network (testnet3/testnet4/signet share encodings).
+	TestNet = &Network{Name: "testnet", PubKeyHashPrefix: 0x6f, ScriptHashPrefix: 0xc4, Bech32HRP: "tb"}
+)
+
+// ScriptType names a standard output script template.
+type ScriptType string
+
+const (
+	// ScriptP2PKH is pay-to-public-key-hash.
+	ScriptP2PKH ScriptType = "p2pkh"
+	// ScriptP2SH is pay-to-script-hash.
+	ScriptP2SH ScriptType = "p2sh"
+	// ScriptP2SHP2WPKH is P2WPKH nested in P2SH (BIP49).
+	ScriptP2SHP2WPKH ScriptType = "p2sh-p2wpkh"
+	// ScriptP2SHP2WSH is P2WSH nested in P2SH.
+	ScriptP2SHP2WSH ScriptType = "p2sh-p2wsh"
+	// ScriptP2WPKH is native segwit v0 pay-to-witness-public-key-hash.
+	ScriptP2WPKH ScriptType = "p2wpkh"
+	// ScriptP2WSH is native segwit v0 pay-to-witness-script-hash.
+	ScriptP2WSH ScriptType = "p2wsh"
+	// ScriptP2TR is segwit v1 pay-to-taproot.
+	ScriptP2TR ScriptType = "p2tr"
+)
+
+// ErrUnsupportedAddress is returned when an address or script cannot be encoded.
+var ErrUnsupportedAddress = errors.New("unsupported address")
+
+// P2PKHScript builds OP_DUP OP_HASH160 <hash> OP_EQUALVERIFY OP_CHECKSIG.
+func P2PKHScript(pubKeyHash []byte) []byte {
+	script := []byte{0x76, 0xa9, 0x14}
+	script = append(script, pubKeyHash...)
+	return append(script, 0x88, 0xac)
+}
+
+// P2SHScript builds OP_HASH160 <hash> OP_EQUAL.
+func P2SHScript(scriptHash []byte) []byte {
+	script := []byte{0xa9, 0x14}
+	script = append(script, scriptHash...)
+	return append(script, 0x87)
+}
+
+// WitnessScript builds a segwit output script OP_n <program>.
+func WitnessScript(version byte, program []byte) []byte {
+	op := byte(0x00)
+	if version > 0 {
+		op = 0x50 + version
+	}
+	return append([]byte{op, byte(len(program))}, program...)
+}
+
+// WitnessScriptHash returns the SHA256 of a witness script, as used by P2WSH.
+func WitnessScriptHash(script []byte) []byte {
+	h := sha256.Sum256(script)
+	return h[:]
+}
+
+// MultisigScript builds OP_k <key>... OP_n OP_CHECKMULTISIG. Counts above 16, allowed in
+// witness scripts, are pushed as one byte of data.
+func MultisigScript(threshold int, keys [][]byte) []byte {
+	script := pushNumber(nil, threshold)
+	for _, k := range keys {
+		script = append(script, byte(len(k)))
+		script = append(script, k...)
+	}
+	return append(pushNumber(script, len(keys)), 0xae)
+}
+
+// pushNumber appends the minimal push of a small positive number: OP_1..OP_16, otherwise a
+// one-byte data push.
+func pushNumber(script []byte, n int) []byte {
+	if n >= 1 && n <= 16 {
+		return append(script, 0x50+byte(n))
+	}
+	return append(script, 0x01, byte(n))
+}
+
+// AddressFromScript encodes a standard output script as an address for the given network.
+func AddressFromScript(script []byte, net *Network) (string, error) {
+	switch {
+	case len(script) == 25 && script[0] == 0x76 && script[1] == 0xa9 && script[2] == 0x14 && script[23] == 0x88 && script[24] == 0xac:
+		return Base58CheckEncode(append([]byte{net.PubKeyHashPrefix}, script[3:23]...)), nil
+	case len(script) == 23 && script[0] == 0xa9 && script[1] == 0x14 && script[22] == 0x87:
+		return Base58CheckEncode(append([]byte{net.ScriptHashPrefix}, script[2:22]...)), nil
+	case len(script) >= 4 && len(script) <= 42 && int(script[1]) == len(script)-2 &&
+		(script[0] == 0x00 || (script[0] >= 0x51 && script[0] <= 0x60)):
+		version := byte(0)
+		if script[0] != 0x00 {
+			version = script[0] - 0x50
+		}
+		return EncodeSegwitAddress(net.Bech32HRP, version, script[2:])
+	default:
+		return "", ErrUnsupportedAddress
+	}
+}
+
+// ScriptFromAddress decodes an address into its output script and network.
+func ScriptFromAddress(address string) ([]byte, *Network, error) {
+	for _, net := range []*Network{MainNet, TestNet} {
+		if strings.HasPrefix(strings.ToLower(address), net.Bech32HRP+"1") {
+			hrp, version, program, err := DecodeSegwitAddress(address)
+			if err != nil || hrp != net.Bech32HRP {
+				return nil, nil, ErrUnsupportedAddress
+			}
+			return WitnessScript(version, program), net, nil
+		}
+	}
+
+	payload, err := Base58CheckDecode(address)
+	if err != nil || len(payload) != 21 {
+		return nil, nil, ErrUnsupportedAddress
+	}
+	for _, net := range []*Network{MainNet, TestNet} {
+		switch payload[0] {
+		case net.PubKeyHashPrefix:
+			return P2PKHScript(payload[1:]), net, nil
+		case net.ScriptHashPrefix:
+			return P2SHScript(payload[1:]), net, nil
+		}
+	}
+	return nil, nil, ErrUnsupportedAddress
+}
@@ -0,0 +1,86 @@
+package bitcoin
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var (
+	// ErrInvalidBase58 is returned when a string contains characters outside the base58 alphabet.
+	ErrInvalidBase58 = errors.New("invalid base58 string")
+	// ErrInvalidChecksum is returned when a base58check payload fails checksum verification.
+	ErrInvalidChecksum = errors.New("invalid checksum")
+)
+
+// Base58Encode encodes bytes using the Bitcoin base58 alphabet.
+func Base58Encode(input []byte) string {
+	x := new(big.Int).SetBytes(input)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+
+	var out []byte
+	for x.Sign() > 0 {
+		x.DivMod(x, radix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range input {
+		if b != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// Base58Decode decodes a base58 string into bytes.
+func Base58Decode(input string) ([]byte, error) {
+	x := new(big.Int)
+	radix := big.NewInt(58)
+	for _, r := range input {
+		idx := bytes.IndexRune([]byte(base58Alphabet), r)
+		if idx < 0 {
+			return nil, ErrInvalidBase58
+		}
+		x.Mul(x, radix)
+		x.Add(x, big.NewInt(int64(idx)))
+	}
+
+	decoded := x.Bytes()
+	leading := 0
+	for _, r := range input {
+		if r != rune(base58Alphabet[0]) {
+			break
+		}
+		leading++
+	}
+	return append(make([]byte, leading), decoded...), nil
+}
+
+// Base58CheckEncode appends a 4-byte double-SHA256 checksum and base58-encodes the payload.
+func Base58CheckEncode(payload []byte) string {
+	checksum := DoubleSHA256(payload)[:4]
+	return Base58Encode(append(append([]byte{}, payload...), checksum...))
+}
+
+// Base58CheckDecode decodes a base58check string and verifies its checksum.
+func Base58CheckDecode(input string) ([]byte, error) {
+	decoded, err := Base58Decode(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded) < 4 {
+		return nil, ErrInvalidChecksum
+	}
+	payload, checksum := decoded[:len(decoded)-4], decoded[len(decoded)-4:]
+	if !bytes.Equal(DoubleSHA256(payload)[:4], checksum) {
+		return nil, ErrInvalidChecksum
+	}
+	return payload, nil
+}
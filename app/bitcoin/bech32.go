@@ -0,0 +1,151 @@
+package bitcoin
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+// ErrInvalidBech32 is returned when a bech32/bech32m string cannot be decoded.
+var ErrInvalidBech32 = errors.New("invalid bech32 string")
+
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	out := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+func bech32Encode(hrp string, data []byte, constant uint32) string {
+	values := append(bech32HRPExpand(hrp), data...)
+	polymod := bech32Polymod(append(values, 0, 0, 0, 0, 0, 0)) ^ constant
+
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, d := range data {
+		sb.WriteByte(bech32Charset[d])
+	}
+	for i := 0; i < 6; i++ {
+		sb.WriteByte(bech32Charset[(polymod>>uint(5*(5-i)))&31])
+	}
+	return sb.String()
+}
+
+func bech32Decode(s string) (hrp string, data []byte, constant uint32, err error) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, 0, ErrInvalidBech32
+	}
+	s = strings.ToLower(s)
+	pos := strings.LastIndexByte(s, '1')
+	if pos < 1 || pos+7 > len(s) || len(s) > 90 {
+		return "", nil, 0, ErrInvalidBech32
+	}
+
+	hrp = s[:pos]
+	for _, c := range s[pos+1:] {
+		idx := strings.IndexRune(bech32Charset, c)
+		if idx < 0 {
+			return "", nil, 0, ErrInvalidBech32
+		}
+		data = append(data, byte(idx))
+	}
+
+	constant = bech32Polymod(append(bech32HRPExpand(hrp), data...))
+	if constant != bech32Const && constant != bech32mConst {
+		return "", nil, 0, ErrInvalidBech32
+	}
+	return hrp, data[:len(data)-6], constant, nil
+}
+
+// convertBits regroups a byte slice from one bit width to another.
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	acc, bits := uint32(0), uint(0)
+	maxv := uint32(1)<<toBits - 1
+	var out []byte
+	for _, b := range data {
+		if uint32(b)>>fromBits != 0 {
+			return nil, ErrInvalidBech32
+		}
+		acc = acc<<fromBits | uint32(b)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			out = append(out, byte((acc>>bits)&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			out = append(out, byte((acc<<(toBits-bits))&maxv))
+		}
+	} else if bits >= fromBits || (acc<<(toBits-bits))&maxv != 0 {
+		return nil, ErrInvalidBech32
+	}
+	return out, nil
+}
+
+// EncodeSegwitAddress encodes a witness program as a bech32 (v0) or bech32m (v1+) address.
+func EncodeSegwitAddress(hrp string, version byte, program []byte) (string, error) {
+	if version > 16 {
+		return "", fmt.Errorf("invalid witness version %d", version)
+	}
+	conv, err := convertBits(program, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	constant := uint32(bech32Const)
+	if version > 0 {
+		constant = bech32mConst
+	}
+	return bech32Encode(hrp, append([]byte{version}, conv...), constant), nil
+}
+
+// DecodeSegwitAddress decodes a bech32/bech32m address into its witness version and program.
+func DecodeSegwitAddress(address string) (hrp string, version byte, program []byte, err error) {
+	hrp, data, constant, err := bech32Decode(address)
+	if err != nil {
+		return "", 0, nil, err
+	}
+	if len(data) < 1 || data[0] > 16 {
+		return "", 0, nil, ErrInvalidBech32
+	}
+	version = data[0]
+	if (version == 0 && constant != bech32Const) || (version > 0 && constant != bech32mConst) {
+		return "", 0, nil, ErrInvalidBech32
+	}
+	program, err = convertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return "", 0, nil, err
+	}
+	if len(program) < 2 || len(program) > 40 || (version == 0 && len(program) != 20 && len(program) != 32) {
+		return "", 0, nil, ErrInvalidBech32
+	}
+	return hrp, version, program, nil
+}
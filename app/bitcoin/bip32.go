@@ -0,0 +1,173 @@
+package bitcoin
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// HardenedOffset is the first hardened BIP32 child index.
+const HardenedOffset uint32 = 0x80000000
+
+var (
+	// ErrInvalidExtendedKey is returned when a string is not a valid serialized extended key.
+	ErrInvalidExtendedKey = errors.New("invalid extended key")
+	// ErrPrivateExtendedKey is returned when a private extended key is supplied where only public keys are accepted.
+	ErrPrivateExtendedKey = errors.New("private extended keys are not accepted")
+	// ErrHardenedFromPublic is returned when hardened derivation is requested from a public key.
+	ErrHardenedFromPublic = errors.New("cannot derive hardened child from a public key")
+)
+
+// ExtendedKey is a BIP32 extended public key.
+type ExtendedKey struct {
+	Version           uint32
+	Depth             byte
+	ParentFingerprint [4]byte
+	ChildNumber       uint32
+	ChainCode         []byte
+	PublicKey         []byte
+}
+
+// ParseExtendedKey decodes a base58check serialized extended public key in any SLIP-132 flavour.
+func ParseExtendedKey(s string) (*ExtendedKey, error) {
+	payload, err := Base58CheckDecode(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidExtendedKey, err)
+	}
+	if len(payload) != 78 {
+		return nil, ErrInvalidExtendedKey
+	}
+
+	version := binary.BigEndian.Uint32(payload[0:4])
+	if isPrivateVersion(version) {
+		return nil, ErrPrivateExtendedKey
+	}
+	if FormatByVersion(version) == nil {
+		return nil, fmt.Errorf("%w: unknown version bytes %08x", ErrInvalidExtendedKey, version)
+	}
+
+	key := &ExtendedKey{
+		Version:     version,
+		Depth:       payload[4],
+		ChildNumber: binary.BigEndian.Uint32(payload[9:13]),
+		ChainCode:   append([]byte{}, payload[13:45]...),
+		PublicKey:   append([]byte{}, payload[45:78]...),
+	}
+	copy(key.ParentFingerprint[:], payload[5:9])
+
+	if !IsValidPublicKey(key.PublicKey) {
+		return nil, ErrInvalidExtendedKey
+	}
+	return key, nil
+}
+
+// String serializes the key with its current version bytes.
+func (k *ExtendedKey) String() string {
+	payload := make([]byte, 0, 78)
+	payload = binary.BigEndian.AppendUint32(payload, k.Version)
+	payload = append(payload, k.Depth)
+	payload = append(payload, k.ParentFingerprint[:]...)
+	payload = binary.BigEndian.AppendUint32(payload, k.ChildNumber)
+	payload = append(payload, k.ChainCode...)
+	payload = append(payload, k.PublicKey...)
+	return Base58CheckEncode(payload)
+}
+
+// Format returns the SLIP-132 format matching the key's version bytes.
+func (k *ExtendedKey) Format() *KeyFormat {
+	return FormatByVersion(k.Version)
+}
+
+// Fingerprint returns the first four bytes of HASH160 of the public key.
+func (k *ExtendedKey) Fingerprint() []byte {
+	return Hash160(k.PublicKey)[:4]
+}
+
+// Child performs BIP32 public child key derivation.
+func (k *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	if index >= HardenedOffset {
+		return nil, ErrHardenedFromPublic
+	}
+
+	data := make([]byte, 0, 37)
+	data = append(data, k.PublicKey...)
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	mac := hmac.New(sha512.New, k.ChainCode)
+	_, _ = mac.Write(data)
+	sum := mac.Sum(nil)
+
+	parent, err := parsePublicKey(k.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	child, err := tweakAdd(parent, sum[:32])
+	if err != nil {
+		return nil, fmt.Errorf("invalid child at index %d", index)
+	}
+
+	derived := &ExtendedKey{
+		Version:     k.Version,
+		Depth:       k.Depth + 1,
+		ChildNumber: index,
+		ChainCode:   append([]byte{}, sum[32:]...),
+		PublicKey:   child.SerializeCompressed(),
+	}
+	copy(derived.ParentFingerprint[:], k.Fingerprint())
+	return derived, nil
+}
+
+// DerivePath derives a descendant key along a non-hardened path.
+func (k *ExtendedKey) DerivePath(path []uint32) (*ExtendedKey, error) {
+	current := k
+	for _, index := range path {
+		next, err := current.Child(index)
+		if err != nil {
+			return nil, err
+		}
+		current = next
+	}
+	return current, nil
+}
+
+// ParseDerivationPath parses a path such as "84'/0'/0'/0" or "m/0h/1".
+func ParseDerivationPath(path string) ([]uint32, error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "m"), "/")
+	if path == "" {
+		return nil, nil
+	}
+
+	var out []uint32
+	for _, part := range strings.Split(path, "/") {
+		hardened := strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h") || strings.HasSuffix(part, "H")
+		if hardened {
+			part = part[:len(part)-1]
+		}
+		index, err := strconv.ParseUint(part, 10, 32)
+		if err != nil || uint32(index) >= HardenedOffset {
+			return nil, fmt.Errorf("invalid derivation path element %q", part)
+		}
+		if hardened {
+			index += uint64(HardenedOffset)
+		}
+		out = append(out, uint32(index))
+	}
+	return out, nil
+}
+
+// FormatDerivationPath renders a path using the apostrophe hardened marker.
+func FormatDerivationPath(path []uint32) string {
+	parts := make([]string, len(path))
+	for i, index := range path {
+		if index >= HardenedOffset {
+			parts[i] = strconv.FormatUint(uint64(index-HardenedOffset), 10) + "'"
+		} else {
+			parts[i] = strconv.FormatUint(uint64(index), 10)
+		}
+	}
+	return strings.Join(parts, "/")
+}
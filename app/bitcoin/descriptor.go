@@ -0,0 +1,439 @@
+package bitcoin
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	descriptorInputCharset = "0123456789()[],'/*abcdefgh@:$%{}" +
+		"IJKLMNOPQRSTUVWXYZ&+-.;<=>?!^_|~" +
+		"ijklmnopqrstuvwxyzABCDEFGH`#\"\\ "
+	descriptorChecksumCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+)
+
+var (
+	// ErrInvalidDescriptor is returned when an output descriptor cannot be parsed.
+	ErrInvalidDescriptor = errors.New("invalid descriptor")
+	// ErrDescriptorChecksum is returned when a descriptor checksum does not match its body.
+	ErrDescriptorChecksum = errors.New("descriptor checksum mismatch")
+)
+
+func descriptorPolymod(symbols []uint64) uint64 {
+	generator := [5]uint64{0xf5dee51989, 0xa9fdca3312, 0x1bab10e32d, 0x3706b1677a, 0x644d626ffd}
+	chk := uint64(1)
+	for _, value := range symbols {
+		top := chk >> 35
+		chk = (chk&0x7ffffffff)<<5 ^ value
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+// DescriptorChecksum computes the 8-character BIP380 checksum of a descriptor body.
+func DescriptorChecksum(desc string) (string, error) {
+	var symbols []uint64
+	var groups []uint64
+	for _, c := range desc {
+		pos := strings.IndexRune(descriptorInputCharset, c)
+		if pos < 0 {
+			return "", fmt.Errorf("%w: character %q not allowed", ErrInvalidDescriptor, c)
+		}
+		symbols = append(symbols, uint64(pos&31))
+		groups = append(groups, uint64(pos>>5))
+		if len(groups) == 3 {
+			symbols = append(symbols, groups[0]*9+groups[1]*3+groups[2])
+			groups = groups[:0]
+		}
+	}
+	switch len(groups) {
+	case 1:
+		symbols = append(symbols, groups[0])
+	case 2:
+		symbols = append(symbols, groups[0]*3+groups[1])
+	}
+
+	symbols = append(symbols, 0, 0, 0, 0, 0, 0, 0, 0)
+	checksum := descriptorPolymod(symbols) ^ 1
+
+	out := make([]byte, 8)
+	for i := 0; i < 8; i++ {
+		out[i] = descriptorChecksumCharset[(checksum>>uint(5*(7-i)))&31]
+	}
+	return string(out), nil
+}
+
+// DescriptorKey is a key expression inside a descriptor, optionally with key origin
+// information and a derivation suffix.
+type DescriptorKey struct {
+	OriginFingerprint string
+	OriginPath        []uint32
+	PublicKey         []byte
+	Extended          *ExtendedKey
+	Path              []uint32
+	Wildcard          bool
+}
+
+// String renders the key expression using canonical xpub/tpub serialization.
+func (k *DescriptorKey) String() string {
+	var sb strings.Builder
+	if k.OriginFingerprint != "" {
+		sb.WriteString("[" + k.OriginFingerprint)
+		if len(k.OriginPath) > 0 {
+			sb.WriteString("/" + FormatDerivationPath(k.OriginPath))
+		}
+		sb.WriteString("]")
+	}
+	if k.Extended == nil {
+		sb.WriteString(hex.EncodeToString(k.PublicKey))
+		return sb.String()
+	}
+	sb.WriteString(k.Extended.CanonicalKey().String())
+	if len(k.Path) > 0 {
+		sb.WriteString("/" + FormatDerivationPath(k.Path))
+	}
+	if k.Wildcard {
+		sb.WriteString("/*")
+	}
+	return sb.String()
+}
+
+// PublicKeyAt returns the serialized public key for the given wildcard index.
+func (k *DescriptorKey) PublicKeyAt(index uint32) ([]byte, error) {
+	if k.Extended == nil {
+		return k.PublicKey, nil
+	}
+	path := append([]uint32{}, k.Path...)
+	if k.Wildcard {
+		path = append(path, index)
+	}
+	derived, err := k.Extended.DerivePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return derived.PublicKey, nil
+}
+
+func (k *DescriptorKey) network() *Network {
+	if k.Extended != nil {
+		return k.Extended.Format().Network
+	}
+	return nil
+}
+
+// Descriptor is a parsed output script descriptor.
+type Descriptor struct {
+	ScriptType ScriptType
+	Threshold  int
+	Sorted     bool
+	Keys       []*DescriptorKey
+	Network    *Network
+}
+
+// ParseDescriptor parses a descriptor, verifying its checksum when one is present.
+// Supported forms are pkh, wpkh, sh(wpkh), tr (key path only) and multi/sortedmulti
+// wrapped in sh, wsh or sh(wsh).
+func ParseDescriptor(input string) (*Descriptor, error) {
+	input = strings.TrimSpace(input)
+	body := input
+	if i := strings.LastIndexByte(input, '#'); i >= 0 {
+		body = input[:i]
+		expected, err := DescriptorChecksum(body)
+		if err != nil {
+			return nil, err
+		}
+		if input[i+1:] != expected {
+			return nil, ErrDescriptorChecksum
+		}
+	} else if _, err := DescriptorChecksum(body); err != nil {
+		return nil, err
+	}
+
+	desc := &Descriptor{}
+	switch {
+	case unwrap(&body, "pkh"):
+		desc.ScriptType = ScriptP2PKH
+	case unwrap(&body, "wpkh"):
+		desc.ScriptType = ScriptP2WPKH
+	case unwrap(&body, "tr"):
+		desc.ScriptType = ScriptP2TR
+		if strings.Contains(body, ",") {
+			return nil, fmt.Errorf("%w: taproot script trees are not supported", ErrInvalidDescriptor)
+		}
+	case unwrap(&body, "wsh"):
+		desc.ScriptType = ScriptP2WSH
+	case unwrap(&body, "sh"):
+		switch {
+		case unwrap(&body, "wpkh"):
+			desc.ScriptType = ScriptP2SHP2WPKH
+		case unwrap(&body, "wsh"):
+			desc.ScriptType = ScriptP2SHP2WSH
+		default:
+			desc.ScriptType = ScriptP2SH
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported script expression", ErrInvalidDescriptor)
+	}
+
+	var keyExprs []string
+	switch desc.ScriptType {
+	case ScriptP2SH, ScriptP2WSH, ScriptP2SHP2WSH:
+		switch {
+		case unwrap(&body, "multi"):
+		case unwrap(&body, "sortedmulti"):
+			desc.Sorted = true
+		default:
+			return nil, fmt.Errorf("%w: only multi and sortedmulti scripts are supported", ErrInvalidDescriptor)
+		}
+		parts := strings.Split(body, ",")
+		threshold, err := strconv.Atoi(parts[0])
+		if err != nil || len(parts) < 2 || threshold < 1 || threshold > len(parts)-1 {
+			return nil, fmt.Errorf("%w: invalid multisig threshold", ErrInvalidDescriptor)
+		}
+		if limit := maxMultisigKeys(desc.ScriptType); len(parts)-1 > limit {
+			return nil, fmt.Errorf("%w: %s multisig allows at most %d keys", ErrInvalidDescriptor, desc.ScriptType, limit)
+		}
+		desc.Threshold = threshold
+		keyExprs = parts[1:]
+	default:
+		keyExprs = []string{body}
+	}
+
+	for _, expr := range keyExprs {
+		key, err := parseDescriptorKey(expr, desc.ScriptType)
+		if err != nil {
+			return nil, err
+		}
+		if net := key.network(); net != nil {
+			if desc.Network != nil && desc.Network != net {
+				return nil, fmt.Errorf("%w: keys from different networks", ErrInvalidDescriptor)
+			}
+			desc.Network = net
+		}
+		desc.Keys = append(desc.Keys, key)
+	}
+	if desc.Network == nil {
+		desc.Network = MainNet
+	}
+	return desc, nil
+}
+
+// maxMultisigKeys returns the number of keys a multisig script may have in the given script
+// context (BIP383): a P2SH redeem script must fit in 520 bytes, which allows 15 compressed keys,
+// while witness scripts are bounded by the 20 keys of OP_CHECKMULTISIG.
+func maxMultisigKeys(scriptType ScriptType) int {
+	if scriptType == ScriptP2SH {
+		return 15
+	}
+	return 20
+}
+
+// unwrap strips name( ... ) from s when it matches.
+func unwrap(s *string, name string) bool {
+	if strings.HasPrefix(*s, name+"(") && strings.HasSuffix(*s, ")") {
+		*s = (*s)[len(name)+1 : len(*s)-1]
+		return true
+	}
+	return false
+}
+
+func parseDescriptorKey(expr string, scriptType ScriptType) (*DescriptorKey, error) {
+	key := &DescriptorKey{}
+
+	if strings.HasPrefix(expr, "[") {
+		end := strings.IndexByte(expr, ']')
+		if end < 0 {
+			return nil, fmt.Errorf("%w: unterminated key origin", ErrInvalidDescriptor)
+		}
+		origin := strings.SplitN(expr[1:end], "/", 2)
+		if fp, err := hex.DecodeString(origin[0]); err != nil || len(fp) != 4 {
+			return nil, fmt.Errorf("%w: invalid key origin fingerprint", ErrInvalidDescriptor)
+		}
+		key.OriginFingerprint = strings.ToLower(origin[0])
+		if len(origin) == 2 {
+			path, err := ParseDerivationPath(origin[1])
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidDescriptor, err)
+			}
+			key.OriginPath = path
+		}
+		expr = expr[end+1:]
+	}
+
+	segments := strings.Split(expr, "/")
+	if raw, err := hex.DecodeString(segments[0]); err == nil {
+		if len(segments) > 1 {
+			return nil, fmt.Errorf("%w: derivation on a plain public key", ErrInvalidDescriptor)
+		}
+		switch {
+		case scriptType == ScriptP2TR && len(raw) == 32:
+		case len(raw) == 33 && IsValidPublicKey(raw):
+		case len(raw) == 65 && IsValidPublicKey(raw) && scriptType != ScriptP2WPKH &&
+			scriptType != ScriptP2WSH && scriptType != ScriptP2SHP2WSH && scriptType != ScriptP2SHP2WPKH && scriptType != ScriptP2TR:
+		default:
+			return nil, fmt.Errorf("%w: invalid public key", ErrInvalidDescriptor)
+		}
+		key.PublicKey = raw
+		return key, nil
+	}
+
+	extended, err := ParseExtendedKey(segments[0])
+	if err != nil {
+		return nil, err
+	}
+	key.Extended = extended
+
+	for i, seg := range segments[1:] {
+		if seg == "*" && i == len(segments)-2 {
+			key.Wildcard = true
+			break
+		}
+		if strings.HasPrefix(seg, "<") {
+			return nil, fmt.Errorf("%w: multipath key expressions are not supported, use /0/* and /1/*", ErrInvalidDescriptor)
+		}
+		path, err := ParseDerivationPath(seg)
+		if err != nil || len(path) != 1 {
+			return nil, fmt.Errorf("%w: invalid derivation step %q", ErrInvalidDescriptor, seg)
+		}
+		if path[0] >= HardenedOffset {
+			return nil, ErrHardenedFromPublic
+		}
+		key.Path = append(key.Path, path[0])
+	}
+	return key, nil
+}
+
+// String renders the descriptor body without checksum.
+func (d *Descriptor) String() string {
+	keys := make([]string, len(d.Keys))
+	for i, k := range d.Keys {
+		keys[i] = k.String()
+	}
+
+	multi := func() string {
+		name := "multi"
+		if d.Sorted {
+			name = "sortedmulti"
+		}
+		return fmt.Sprintf("%s(%d,%s)", name, d.Threshold, strings.Join(keys, ","))
+	}
+
+	switch d.ScriptType {
+	case ScriptP2PKH:
+		return "pkh(" + keys[0] + ")"
+	case ScriptP2WPKH:
+		return "wpkh(" + keys[0] + ")"
+	case ScriptP2SHP2WPKH:
+		return "sh(wpkh(" + keys[0] + "))"
+	case ScriptP2TR:
+		return "tr(" + keys[0] + ")"
+	case ScriptP2SH:
+		return "sh(" + multi() + ")"
+	case ScriptP2WSH:
+		return "wsh(" + multi() + ")"
+	case ScriptP2SHP2WSH:
+		return "sh(wsh(" + multi() + "))"
+	default:
+		return ""
+	}
+}
+
+// StringWithChecksum renders the canonical descriptor followed by its checksum.
+func (d *Descriptor) StringWithChecksum() string {
+	body := d.String()
+	checksum, _ := DescriptorChecksum(body)
+	return body + "#" + checksum
+}
+
+// IsRange reports whether the descriptor contains a wildcard derivation step.
+func (d *Descriptor) IsRange() bool {
+	for _, k := range d.Keys {
+		if k.Wildcard {
+			return true
+		}
+	}
+	return false
+}
+
+// ScriptAt returns the output script (scriptPubKey) for the given wildcard index.
+func (d *Descriptor) ScriptAt(index uint32) ([]byte, error) {
+	pubKeys := make([][]byte, len(d.Keys))
+	for i, k := range d.Keys {
+		pk, err := k.PublicKeyAt(index)
+		if err != nil {
+			return nil, err
+		}
+		pubKeys[i] = pk
+	}
+
+	multisig := func() []byte {
+		if d.Sorted {
+			sort.Slice(pubKeys, func(i, j int) bool { return bytes.Compare(pubKeys[i], pubKeys[j]) < 0 })
+		}
+		return MultisigScript(d.Threshold, pubKeys)
+	}
+
+	switch d.ScriptType {
+	case ScriptP2PKH:
+		return P2PKHScript(Hash160(pubKeys[0])), nil
+	case ScriptP2WPKH:
+		return WitnessScript(0, Hash160(pubKeys[0])), nil
+	case ScriptP2SHP2WPKH:
+		return P2SHScript(Hash160(WitnessScript(0, Hash160(pubKeys[0])))), nil
+	case ScriptP2TR:
+		outputKey, err := TaprootOutputKey(pubKeys[0])
+		if err != nil {
+			return nil, err
+		}
+		return WitnessScript(1, outputKey), nil
+	case ScriptP2SH:
+		return P2SHScript(Hash160(multisig())), nil
+	case ScriptP2WSH:
+		return WitnessScript(0, WitnessScriptHash(multisig())), nil
+	case ScriptP2SHP2WSH:
+		return P2SHScript(Hash160(WitnessScript(0, WitnessScriptHash(multisig())))), nil
+	default:
+		return nil, ErrInvalidDescriptor
+	}
+}
+
+// AddressAt returns the address for the given wildcard index.
+func (d *Descriptor) AddressAt(index uint32) (string, error) {
+	script, err := d.ScriptAt(index)
+	if err != nil {
+		return "", err
+	}
+	return AddressFromScript(script, d.Network)
+}
+
+// DescriptorFromExtendedKey builds the single-sig descriptor implied by a SLIP-132 key,
+// e.g. zpub -> wpkh(xpub/0/*). The change flag selects the /1/* branch.
+func DescriptorFromExtendedKey(s string, change bool) (*Descriptor, error) {
+	key, err := ParseExtendedKey(s)
+	if err != nil {
+		return nil, err
+	}
+	format := key.Format()
+	if format.Multisig {
+		return nil, fmt.Errorf("%s keys describe multisig wallets; supply a sortedmulti descriptor instead", format.Prefix)
+	}
+
+	branch := uint32(0)
+	if change {
+		branch = 1
+	}
+	return &Descriptor{
+		ScriptType: format.ScriptType,
+		Keys:       []*DescriptorKey{{Extended: key, Path: []uint32{branch}, Wildcard: true}},
+		Network:    format.Network,
+	}, nil
+}
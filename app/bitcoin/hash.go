@@ -0,0 +1,36 @@
+// Package bitcoin provides the Bitcoin primitives used by the wallet explorer:
+// key and address encodings, BIP32 derivation and output descriptors.
+package bitcoin
+
+import (
+	"crypto/sha256"
+
+	"golang.org/x/crypto/ripemd160" //nolint:staticcheck // RIPEMD-160 is mandated by Bitcoin address formats
+)
+
+// Hash160 returns RIPEMD160(SHA256(data)), as used by P2PKH and P2SH.
+func Hash160(data []byte) []byte {
+	sha := sha256.Sum256(data)
+	h := ripemd160.New()
+	_, _ = h.Write(sha[:])
+	return h.Sum(nil)
+}
+
+// DoubleSHA256 returns SHA256(SHA256(data)).
+func DoubleSHA256(data []byte) []byte {
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+	return second[:]
+}
+
+// TaggedHash implements the BIP340 tagged hash SHA256(SHA256(tag) || SHA256(tag) || data).
+func TaggedHash(tag string, data ...[]byte) []byte {
+	tagHash := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	_, _ = h.Write(tagHash[:])
+	_, _ = h.Write(tagHash[:])
+	for _, d := range data {
+		_, _ = h.Write(d)
+	}
+	return h.Sum(nil)
+}
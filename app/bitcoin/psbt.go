@@ -0,0 +1,332 @@
+package bitcoin
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// psbtMagic prefixes every serialized PSBT ("psbt" followed by 0xff).
+var psbtMagic = []byte{0x70, 0x73, 0x62, 0x74, 0xff}
+
+// ErrInvalidPSBT is returned when bytes cannot be parsed as a BIP174/BIP370 PSBT.
+var ErrInvalidPSBT = errors.New("invalid psbt")
+
+// PSBT global, input and output key types used by the decoder.
+const (
+	psbtGlobalUnsignedTx     = 0x00
+	psbtGlobalTxVersion      = 0x02
+	psbtGlobalFallbackLock   = 0x03
+	psbtGlobalInputCount     = 0x04
+	psbtGlobalOutputCount    = 0x05
+	psbtGlobalVersion        = 0xfb
+	psbtInNonWitnessUTXO     = 0x00
+	psbtInWitnessUTXO        = 0x01
+	psbtInPartialSig         = 0x02
+	psbtInSighashType        = 0x03
+	psbtInRedeemScript       = 0x04
+	psbtInWitnessScript      = 0x05
+	psbtInFinalScriptSig     = 0x07
+	psbtInFinalScriptWitness = 0x08
+	psbtInPreviousTxID       = 0x0e
+	psbtInOutputIndex        = 0x0f
+	psbtInSequence           = 0x10
+	psbtInRequiredTimeLock   = 0x11
+	psbtInRequiredHeightLock = 0x12
+	psbtInTapKeySig          = 0x13
+	psbtInTapScriptSig       = 0x14
+	psbtOutAmount            = 0x03
+	psbtOutScript            = 0x04
+)
+
+// PSBTInput holds the per-input fields of a PSBT that the explorer understands.
+type PSBTInput struct {
+	NonWitnessUTXO     *Tx
+	WitnessUTXO        *TxOut
+	PartialSigs        map[string][]byte // hex pubkey -> signature
+	SighashType        *uint32
+	RedeemScript       []byte
+	WitnessScript      []byte
+	FinalScriptSig     []byte
+	FinalScriptWitness [][]byte
+	TapKeySig          []byte
+	TapScriptSigs      [][]byte
+}
+
+// IsFinalized reports whether the input carries a final scriptSig or witness.
+func (in *PSBTInput) IsFinalized() bool {
+	return in.FinalScriptSig != nil || in.FinalScriptWitness != nil
+}
+
+// PSBT is a parsed partially signed Bitcoin transaction (version 0 or 2).
+type PSBT struct {
+	Version uint32
+	Tx      *Tx
+	Inputs  []*PSBTInput
+}
+
+// IsPSBT reports whether raw starts with the PSBT magic bytes.
+func IsPSBT(raw []byte) bool {
+	return bytes.HasPrefix(raw, psbtMagic)
+}
+
+type psbtPair struct {
+	keyType byte
+	keyData []byte
+	value   []byte
+}
+
+func readPSBTMap(r *bytes.Reader) ([]psbtPair, error) {
+	var pairs []psbtPair
+	for {
+		key, err := readVarBytes(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(key) == 0 {
+			return pairs, nil
+		}
+		value, err := readVarBytes(r)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, psbtPair{keyType: key[0], keyData: key[1:], value: value})
+	}
+}
+
+// ParsePSBT decodes a binary PSBT. Version 0 carries the unsigned transaction in the
+// global map; version 2 (BIP370) reconstructs it from per-input and per-output fields.
+func ParsePSBT(raw []byte) (*PSBT, error) {
+	if !IsPSBT(raw) {
+		return nil, fmt.Errorf("%w: missing magic bytes", ErrInvalidPSBT)
+	}
+	r := bytes.NewReader(raw[len(psbtMagic):])
+	fail := func(err error) (*PSBT, error) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPSBT, err)
+	}
+
+	global, err := readPSBTMap(r)
+	if err != nil {
+		return fail(err)
+	}
+
+	p := &PSBT{}
+	var inputCount, outputCount uint64
+	var fallbackLock uint32
+	tx := &Tx{Version: 2}
+	for _, kv := range global {
+		switch kv.keyType {
+		case psbtGlobalUnsignedTx:
+			if tx, err = ParseTransaction(kv.value); err != nil {
+				return fail(err)
+			}
+			p.Tx = tx
+		case psbtGlobalTxVersion:
+			if len(kv.value) != 4 {
+				return fail(errors.New("invalid tx version"))
+			}
+			tx.Version = int32(binary.LittleEndian.Uint32(kv.value))
+		case psbtGlobalFallbackLock:
+			if len(kv.value) != 4 {
+				return fail(errors.New("invalid fallback locktime"))
+			}
+			fallbackLock = binary.LittleEndian.Uint32(kv.value)
+		case psbtGlobalInputCount:
+			if inputCount, err = readCompactSize(bytes.NewReader(kv.value)); err != nil {
+				return fail(err)
+			}
+		case psbtGlobalOutputCount:
+			if outputCount, err = readCompactSize(bytes.NewReader(kv.value)); err != nil {
+				return fail(err)
+			}
+		case psbtGlobalVersion:
+			if len(kv.value) != 4 {
+				return fail(errors.New("invalid psbt version"))
+			}
+			p.Version = binary.LittleEndian.Uint32(kv.value)
+		}
+	}
+
+	switch p.Version {
+	case 0:
+		if p.Tx == nil {
+			return fail(errors.New("missing unsigned transaction"))
+		}
+		inputCount, outputCount = uint64(len(p.Tx.Inputs)), uint64(len(p.Tx.Outputs))
+	case 2:
+		if p.Tx != nil {
+			return fail(errors.New("unsigned transaction not allowed in version 2"))
+		}
+		p.Tx = tx
+	default:
+		return fail(fmt.Errorf("unsupported version %d", p.Version))
+	}
+	if inputCount > uint64(r.Len()) || outputCount > uint64(r.Len()) {
+		return fail(errors.New("map count exceeds payload"))
+	}
+
+	var heightLock, timeLock uint32
+	for i := uint64(0); i < inputCount; i++ {
+		pairs, err := readPSBTMap(r)
+		if err != nil {
+			return fail(err)
+		}
+		in := &PSBTInput{PartialSigs: map[string][]byte{}}
+		txIn := &TxIn{Sequence: 0xffffffff}
+		for _, kv := range pairs {
+			switch kv.keyType {
+			case psbtInNonWitnessUTXO:
+				if in.NonWitnessUTXO, err = ParseTransaction(kv.value); err != nil {
+					return fail(err)
+				}
+			case psbtInWitnessUTXO:
+				out, err := parseTxOut(kv.value)
+				if err != nil {
+					return fail(err)
+				}
+				in.WitnessUTXO = out
+			case psbtInPartialSig:
+				in.PartialSigs[fmt.Sprintf("%x", kv.keyData)] = kv.value
+			case psbtInSighashType:
+				if len(kv.value) != 4 {
+					return fail(errors.New("invalid sighash type"))
+				}
+				sighash := binary.LittleEndian.Uint32(kv.value)
+				in.SighashType = &sighash
+			case psbtInRedeemScript:
+				in.RedeemScript = kv.value
+			case psbtInWitnessScript:
+				in.WitnessScript = kv.value
+			case psbtInFinalScriptSig:
+				in.FinalScriptSig = kv.value
+			case psbtInFinalScriptWitness:
+				if in.FinalScriptWitness, err = ParseWitness(kv.value); err != nil {
+					return fail(err)
+				}
+			case psbtInTapKeySig:
+				in.TapKeySig = kv.value
+			case psbtInTapScriptSig:
+				in.TapScriptSigs = append(in.TapScriptSigs, kv.value)
+			case psbtInPreviousTxID:
+				if len(kv.value) != 32 {
+					return fail(errors.New("invalid previous txid"))
+				}
+				copy(txIn.PrevTxID[:], kv.value)
+			case psbtInOutputIndex:
+				if len(kv.value) != 4 {
+					return fail(errors.New("invalid output index"))
+				}
+				txIn.PrevIndex = binary.LittleEndian.Uint32(kv.value)
+			case psbtInSequence:
+				if len(kv.value) != 4 {
+					return fail(errors.New("invalid sequence"))
+				}
+				txIn.Sequence = binary.LittleEndian.Uint32(kv.value)
+			case psbtInRequiredTimeLock, psbtInRequiredHeightLock:
+				if len(kv.value) != 4 {
+					return fail(errors.New("invalid required locktime"))
+				}
+				lock := binary.LittleEndian.Uint32(kv.value)
+				if kv.keyType == psbtInRequiredHeightLock && lock > heightLock {
+					heightLock = lock
+				} else if kv.keyType == psbtInRequiredTimeLock && lock > timeLock {
+					timeLock = lock
+				}
+			}
+		}
+		if p.Version == 2 {
+			p.Tx.Inputs = append(p.Tx.Inputs, txIn)
+		}
+		p.Inputs = append(p.Inputs, in)
+	}
+
+	for i := uint64(0); i < outputCount; i++ {
+		pairs, err := readPSBTMap(r)
+		if err != nil {
+			return fail(err)
+		}
+		if p.Version != 2 {
+			continue
+		}
+		out := &TxOut{}
+		for _, kv := range pairs {
+			switch kv.keyType {
+			case psbtOutAmount:
+				if len(kv.value) != 8 {
+					return fail(errors.New("invalid output amount"))
+				}
+				out.Value = int64(binary.LittleEndian.Uint64(kv.value))
+			case psbtOutScript:
+				out.Script = kv.value
+			}
+		}
+		p.Tx.Outputs = append(p.Tx.Outputs, out)
+	}
+
+	for i, in := range p.Inputs {
+		if err := checkNonWitnessUTXO(in, p.Tx.Inputs[i]); err != nil {
+			return fail(fmt.Errorf("input %d: %w", i, err))
+		}
+	}
+
+	if p.Version == 2 {
+		// BIP370 locktime determination: prefer height-based requirements
+		switch {
+		case heightLock > 0:
+			p.Tx.LockTime = heightLock
+		case timeLock > 0:
+			p.Tx.LockTime = timeLock
+		default:
+			p.Tx.LockTime = fallbackLock
+		}
+	}
+	return p, nil
+}
+
+// checkNonWitnessUTXO verifies that the previous transaction of an input is the one the input
+// spends, and that a witness UTXO given alongside matches its output, so that neither can lie
+// about the value spent.
+func checkNonWitnessUTXO(in *PSBTInput, txIn *TxIn) error {
+	if in.NonWitnessUTXO == nil {
+		return nil
+	}
+	if !bytes.Equal(DoubleSHA256(in.NonWitnessUTXO.Serialize(false)), txIn.PrevTxID[:]) {
+		return errors.New("non-witness utxo does not match the previous txid")
+	}
+	if int(txIn.PrevIndex) >= len(in.NonWitnessUTXO.Outputs) {
+		return errors.New("non-witness utxo has no output at the previous index")
+	}
+	spent := in.NonWitnessUTXO.Outputs[txIn.PrevIndex]
+	if in.WitnessUTXO != nil && (in.WitnessUTXO.Value != spent.Value || !bytes.Equal(in.WitnessUTXO.Script, spent.Script)) {
+		return errors.New("witness utxo does not match the non-witness utxo")
+	}
+	return nil
+}
+
+func parseTxOut(raw []byte) (*TxOut, error) {
+	r := bytes.NewReader(raw)
+	out := &TxOut{}
+	if err := binary.Read(r, binary.LittleEndian, &out.Value); err != nil {
+		return nil, err
+	}
+	script, err := readVarBytes(r)
+	if err != nil {
+		return nil, err
+	}
+	out.Script = script
+	return out, nil
+}
+
+// SpentOutput returns the output being spent by input i, when the PSBT carries it. The
+// previous transaction, checked against the input's txid by ParsePSBT, is preferred.
+func (p *PSBT) SpentOutput(i int) *TxOut {
+	in := p.Inputs[i]
+	if in.NonWitnessUTXO != nil {
+		index := p.Tx.Inputs[i].PrevIndex
+		if int(index) < len(in.NonWitnessUTXO.Outputs) {
+			return in.NonWitnessUTXO.Outputs[index]
+		}
+	}
+	return in.WitnessUTXO
+}
@@ -0,0 +1,217 @@
+package bitcoin
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Script opcodes referenced by the classifier.
+const (
+	Op0             byte = 0x00
+	OpPushData1     byte = 0x4c
+	OpPushData2     byte = 0x4d
+	OpPushData4     byte = 0x4e
+	Op1Negate       byte = 0x4f
+	Op1             byte = 0x51
+	Op16            byte = 0x60
+	OpReturn        byte = 0x6a
+	OpDup           byte = 0x76
+	OpEqual         byte = 0x87
+	OpEqualVerify   byte = 0x88
+	OpHash160       byte = 0xa9
+	OpCheckSig      byte = 0xac
+	OpCheckMultiSig byte = 0xae
+)
+
+// Additional script types that cannot be expressed as addresses.
+const (
+	// ScriptP2PK is bare pay-to-public-key.
+	ScriptP2PK ScriptType = "p2pk"
+	// ScriptMultisig is bare m-of-n OP_CHECKMULTISIG.
+	ScriptMultisig ScriptType = "multisig"
+	// ScriptNullData is an OP_RETURN data carrier output.
+	ScriptNullData ScriptType = "op_return"
+	// ScriptWitnessUnknown is a segwit output with an unassigned version or program length.
+	ScriptWitnessUnknown ScriptType = "witness_unknown"
+	// ScriptNonStandard is anything else.
+	ScriptNonStandard ScriptType = "nonstandard"
+)
+
+// ErrMalformedScript is returned when a push runs past the end of a script.
+var ErrMalformedScript = errors.New("malformed script")
+
+// ScriptOp is a single parsed script element: an opcode and, for pushes, its data.
+type ScriptOp struct {
+	Opcode byte
+	Data   []byte
+}
+
+// IsPush reports whether the op pushes data (including OP_0 and OP_1NEGATE..OP_16).
+func (op ScriptOp) IsPush() bool {
+	return op.Opcode <= OpPushData4 || op.Opcode == Op1Negate || (op.Opcode >= Op1 && op.Opcode <= Op16)
+}
+
+// ParseScript splits a script into opcodes and push data. The ops parsed before a
+// malformed push are returned along with ErrMalformedScript.
+func ParseScript(script []byte) ([]ScriptOp, error) {
+	var ops []ScriptOp
+	for i := 0; i < len(script); {
+		opcode := script[i]
+		i++
+
+		var size int
+		switch {
+		case opcode > Op0 && opcode < OpPushData1:
+			size = int(opcode)
+		case opcode == OpPushData1:
+			if i+1 > len(script) {
+				return ops, ErrMalformedScript
+			}
+			size = int(script[i])
+			i++
+		case opcode == OpPushData2:
+			if i+2 > len(script) {
+				return ops, ErrMalformedScript
+			}
+			size = int(binary.LittleEndian.Uint16(script[i:]))
+			i += 2
+		case opcode == OpPushData4:
+			if i+4 > len(script) {
+				return ops, ErrMalformedScript
+			}
+			size = int(binary.LittleEndian.Uint32(script[i:]))
+			i += 4
+		default:
+			ops = append(ops, ScriptOp{Opcode: opcode})
+			continue
+		}
+
+		if size < 0 || i+size > len(script) {
+			return ops, ErrMalformedScript
+		}
+		ops = append(ops, ScriptOp{Opcode: opcode, Data: script[i : i+size]})
+		i += size
+	}
+	return ops, nil
+}
+
+// smallInt returns the value of OP_1..OP_16, or -1.
+func smallInt(opcode byte) int {
+	if opcode >= Op1 && opcode <= Op16 {
+		return int(opcode-Op1) + 1
+	}
+	return -1
+}
+
+// multisigCount returns the key count pushed by op, as OP_1..OP_16 or as the one-byte data push
+// witness scripts use for 17 to 20 keys, or -1.
+func multisigCount(op ScriptOp) int {
+	if n := smallInt(op.Opcode); n > 0 {
+		return n
+	}
+	if op.Opcode == 0x01 && len(op.Data) == 1 && op.Data[0] > 16 && op.Data[0] <= 20 {
+		return int(op.Data[0])
+	}
+	return -1
+}
+
+// ScriptClass is the result of classifying an output script.
+type ScriptClass struct {
+	Type         ScriptType
+	RequiredSigs int
+	TotalKeys    int
+	PubKeys      [][]byte
+}
+
+// ClassifyScript identifies the standard template of an output (or redeem/witness) script.
+func ClassifyScript(script []byte) ScriptClass {
+	n := len(script)
+	switch {
+	case n == 25 && script[0] == OpDup && script[1] == OpHash160 && script[2] == 0x14 && script[23] == OpEqualVerify && script[24] == OpCheckSig:
+		return ScriptClass{Type: ScriptP2PKH, RequiredSigs: 1}
+	case n == 23 && script[0] == OpHash160 && script[1] == 0x14 && script[22] == OpEqual:
+		return ScriptClass{Type: ScriptP2SH}
+	case n == 22 && script[0] == Op0 && script[1] == 0x14:
+		return ScriptClass{Type: ScriptP2WPKH, RequiredSigs: 1}
+	case n == 34 && script[0] == Op0 && script[1] == 0x20:
+		return ScriptClass{Type: ScriptP2WSH}
+	case n == 34 && script[0] == Op1 && script[1] == 0x20:
+		return ScriptClass{Type: ScriptP2TR, RequiredSigs: 1}
+	case n >= 4 && n <= 42 && smallInt(script[0]) > 0 && int(script[1]) == n-2:
+		return ScriptClass{Type: ScriptWitnessUnknown}
+	case n > 0 && script[0] == OpReturn:
+		return ScriptClass{Type: ScriptNullData}
+	}
+
+	ops, err := ParseScript(script)
+	if err != nil {
+		return ScriptClass{Type: ScriptNonStandard}
+	}
+
+	if len(ops) == 2 && ops[1].Opcode == OpCheckSig && (len(ops[0].Data) == 33 || len(ops[0].Data) == 65) {
+		return ScriptClass{Type: ScriptP2PK, RequiredSigs: 1, TotalKeys: 1, PubKeys: [][]byte{ops[0].Data}}
+	}
+
+	if len(ops) >= 4 && ops[len(ops)-1].Opcode == OpCheckMultiSig {
+		m, total := multisigCount(ops[0]), multisigCount(ops[len(ops)-2])
+		keys := ops[1 : len(ops)-2]
+		if m > 0 && total == len(keys) && m <= total {
+			class := ScriptClass{Type: ScriptMultisig, RequiredSigs: m, TotalKeys: total}
+			for _, k := range keys {
+				if len(k.Data) != 33 && len(k.Data) != 65 {
+					return ScriptClass{Type: ScriptNonStandard}
+				}
+				class.PubKeys = append(class.PubKeys, k.Data)
+			}
+			return class
+		}
+	}
+
+	return ScriptClass{Type: ScriptNonStandard}
+}
+
+// IsSignature reports whether b looks like an ECDSA DER signature with a trailing
+// sighash byte, or a 64/65-byte Schnorr signature when schnorr is set.
+func IsSignature(b []byte, schnorr bool) bool {
+	if schnorr {
+		return len(b) == 64 || len(b) == 65
+	}
+	if len(b) < 9 || len(b) > 73 || b[0] != 0x30 || int(b[1]) != len(b)-3 {
+		return false
+	}
+	return true
+}
+
+// SignatureHashType returns the sighash flag carried by a signature.
+// 64-byte Schnorr signatures use SIGHASH_DEFAULT (0x00).
+func SignatureHashType(sig []byte) byte {
+	if len(sig) == 64 {
+		return 0x00
+	}
+	return sig[len(sig)-1]
+}
+
+// SighashName renders a sighash flag, e.g. 0x81 -> "ALL|ANYONECANPAY".
+func SighashName(flag byte) string {
+	if flag == 0x00 {
+		return "DEFAULT"
+	}
+
+	var base string
+	switch flag & 0x1f {
+	case 0x01:
+		base = "ALL"
+	case 0x02:
+		base = "NONE"
+	case 0x03:
+		base = "SINGLE"
+	default:
+		return fmt.Sprintf("UNKNOWN(0x%02x)", flag)
+	}
+	if flag&0x80 != 0 {
+		return strings.Join([]string{base, "ANYONECANPAY"}, "|")
+	}
+	return base
+}
@@ -0,0 +1,173 @@
+package bitcoin
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Additional opcodes used by the envelope parser.
+const (
+	OpIf    byte = 0x63
+	OpEndIf byte = 0x68
+)
+
+// opcodeNames maps non-push opcodes to their canonical names.
+var opcodeNames = map[byte]string{
+	0x50: "OP_RESERVED", 0x61: "OP_NOP", 0x62: "OP_VER", 0x63: "OP_IF", 0x64: "OP_NOTIF",
+	0x65: "OP_VERIF", 0x66: "OP_VERNOTIF", 0x67: "OP_ELSE", 0x68: "OP_ENDIF", 0x69: "OP_VERIFY",
+	0x6a: "OP_RETURN", 0x6b: "OP_TOALTSTACK", 0x6c: "OP_FROMALTSTACK", 0x6d: "OP_2DROP",
+	0x6e: "OP_2DUP", 0x6f: "OP_3DUP", 0x70: "OP_2OVER", 0x71: "OP_2ROT", 0x72: "OP_2SWAP",
+	0x73: "OP_IFDUP", 0x74: "OP_DEPTH", 0x75: "OP_DROP", 0x76: "OP_DUP", 0x77: "OP_NIP",
+	0x78: "OP_OVER", 0x79: "OP_PICK", 0x7a: "OP_ROLL", 0x7b: "OP_ROT", 0x7c: "OP_SWAP",
+	0x7d: "OP_TUCK", 0x7e: "OP_CAT", 0x7f: "OP_SUBSTR", 0x80: "OP_LEFT", 0x81: "OP_RIGHT",
+	0x82: "OP_SIZE", 0x83: "OP_INVERT", 0x84: "OP_AND", 0x85: "OP_OR", 0x86: "OP_XOR",
+	0x87: "OP_EQUAL", 0x88: "OP_EQUALVERIFY", 0x89: "OP_RESERVED1", 0x8a: "OP_RESERVED2",
+	0x8b: "OP_1ADD", 0x8c: "OP_1SUB", 0x8d: "OP_2MUL", 0x8e: "OP_2DIV", 0x8f: "OP_NEGATE",
+	0x90: "OP_ABS", 0x91: "OP_NOT", 0x92: "OP_0NOTEQUAL", 0x93: "OP_ADD", 0x94: "OP_SUB",
+	0x95: "OP_MUL", 0x96: "OP_DIV", 0x97: "OP_MOD", 0x98: "OP_LSHIFT", 0x99: "OP_RSHIFT",
+	0x9a: "OP_BOOLAND", 0x9b: "OP_BOOLOR", 0x9c: "OP_NUMEQUAL", 0x9d: "OP_NUMEQUALVERIFY",
+	0x9e: "OP_NUMNOTEQUAL", 0x9f: "OP_LESSTHAN", 0xa0: "OP_GREATERTHAN",
+	0xa1: "OP_LESSTHANOREQUAL", 0xa2: "OP_GREATERTHANOREQUAL", 0xa3: "OP_MIN", 0xa4: "OP_MAX",
+	0xa5: "OP_WITHIN", 0xa6: "OP_RIPEMD160", 0xa7: "OP_SHA1", 0xa8: "OP_SHA256",
+	0xa9: "OP_HASH160", 0xaa: "OP_HASH256", 0xab: "OP_CODESEPARATOR", 0xac: "OP_CHECKSIG",
+	0xad: "OP_CHECKSIGVERIFY", 0xae: "OP_CHECKMULTISIG", 0xaf: "OP_CHECKMULTISIGVERIFY",
+	0xb0: "OP_NOP1", 0xb1: "OP_CHECKLOCKTIMEVERIFY", 0xb2: "OP_CHECKSEQUENCEVERIFY",
+	0xb3: "OP_NOP4", 0xb4: "OP_NOP5", 0xb5: "OP_NOP6", 0xb6: "OP_NOP7", 0xb7: "OP_NOP8",
+	0xb8: "OP_NOP9", 0xb9: "OP_NOP10", 0xba: "OP_CHECKSIGADD",
+}
+
+// OpcodeName returns the canonical name of an opcode, e.g. 0xac -> "OP_CHECKSIG".
+func OpcodeName(opcode byte) string {
+	switch {
+	case opcode == Op0:
+		return "OP_0"
+	case opcode == Op1Negate:
+		return "OP_1NEGATE"
+	case opcode >= Op1 && opcode <= Op16:
+		return "OP_" + strconv.Itoa(smallInt(opcode))
+	case opcode < OpPushData1:
+		return fmt.Sprintf("OP_PUSHBYTES_%d", opcode)
+	case opcode == OpPushData1:
+		return "OP_PUSHDATA1"
+	case opcode == OpPushData2:
+		return "OP_PUSHDATA2"
+	case opcode == OpPushData4:
+		return "OP_PUSHDATA4"
+	}
+	if name, ok := opcodeNames[opcode]; ok {
+		return name
+	}
+	return "OP_UNKNOWN"
+}
+
+// Disassemble renders a script in the ASM notation used by Bitcoin Core: pushes are
+// shown as hex, small integers as numbers and everything else by opcode name. A
+// malformed trailing push is rendered as "[error]".
+func Disassemble(script []byte) string {
+	ops, err := ParseScript(script)
+	parts := make([]string, 0, len(ops)+1)
+	for _, op := range ops {
+		switch {
+		case op.Opcode == Op0:
+			parts = append(parts, "0")
+		case op.Opcode == Op1Negate:
+			parts = append(parts, "-1")
+		case op.Opcode >= Op1 && op.Opcode <= Op16:
+			parts = append(parts, strconv.Itoa(smallInt(op.Opcode)))
+		case op.Opcode <= OpPushData4:
+			parts = append(parts, hex.EncodeToString(op.Data))
+		default:
+			parts = append(parts, OpcodeName(op.Opcode))
+		}
+	}
+	if err != nil {
+		parts = append(parts, "[error]")
+	}
+	return strings.Join(parts, " ")
+}
+
+// NullDataPayload returns the concatenated pushes following OP_RETURN.
+func NullDataPayload(script []byte) ([]byte, bool) {
+	if len(script) == 0 || script[0] != OpReturn {
+		return nil, false
+	}
+	ops, err := ParseScript(script[1:])
+	if err != nil {
+		return nil, false
+	}
+	var payload []byte
+	for _, op := range ops {
+		if !op.IsPush() {
+			return nil, false
+		}
+		payload = append(payload, op.Data...)
+	}
+	return payload, true
+}
+
+// PrintableText returns b as a string when it is valid UTF-8 made only of printable characters.
+func PrintableText(b []byte) (string, bool) {
+	if len(b) == 0 || !utf8.Valid(b) {
+		return "", false
+	}
+	s := string(b)
+	for _, r := range s {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return "", false
+		}
+	}
+	return s, true
+}
+
+// Inscription describes an ordinals-style envelope found in a taproot script-path witness.
+type Inscription struct {
+	Protocol    string
+	ContentType string
+	Body        []byte
+}
+
+// FindInscription looks for an OP_FALSE OP_IF "ord" ... OP_ENDIF envelope in the
+// tapscript of a script-path spend witness.
+func FindInscription(witness [][]byte) *Inscription {
+	if len(witness) < 2 {
+		return nil
+	}
+	// Strip the annex when present (last element starting with 0x50)
+	if len(witness[len(witness)-1]) > 0 && witness[len(witness)-1][0] == 0x50 {
+		witness = witness[:len(witness)-1]
+		if len(witness) < 2 {
+			return nil
+		}
+	}
+
+	ops, _ := ParseScript(witness[len(witness)-2])
+	for i := 0; i+2 < len(ops); i++ {
+		if ops[i].Opcode != Op0 || ops[i+1].Opcode != OpIf || !bytes.Equal(ops[i+2].Data, []byte("ord")) {
+			continue
+		}
+
+		inscription := &Inscription{Protocol: "ord"}
+		inBody := false
+		for j := i + 3; j < len(ops) && ops[j].Opcode != OpEndIf; j++ {
+			op := ops[j]
+			switch {
+			case inBody:
+				inscription.Body = append(inscription.Body, op.Data...)
+			case op.Opcode == Op0:
+				inBody = true
+			case (op.Opcode == Op1 || bytes.Equal(op.Data, []byte{0x01})) && j+1 < len(ops):
+				inscription.ContentType = string(ops[j+1].Data)
+				j++
+			case op.IsPush() && j+1 < len(ops):
+				j++ // skip unknown tag and its value
+			}
+		}
+		return inscription
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package bitcoin
+
+import (
+	"errors"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// ErrInvalidPublicKey is returned when bytes do not describe a point on secp256k1.
+var ErrInvalidPublicKey = errors.New("invalid public key")
+
+// parsePublicKey decodes a 33-byte compressed or 65-byte uncompressed public key.
+func parsePublicKey(b []byte) (*secp256k1.PublicKey, error) {
+	if len(b) != secp256k1.PubKeyBytesLenCompressed && len(b) != secp256k1.PubKeyBytesLenUncompressed {
+		return nil, ErrInvalidPublicKey
+	}
+	key, err := secp256k1.ParsePubKey(b)
+	if err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+	return key, nil
+}
+
+// liftX returns the point with the given x coordinate and an even y, as used by BIP340.
+func liftX(x []byte) (*secp256k1.PublicKey, error) {
+	if len(x) != 32 {
+		return nil, ErrInvalidPublicKey
+	}
+	return parsePublicKey(append([]byte{secp256k1.PubKeyFormatCompressedEven}, x...))
+}
+
+// tweakAdd returns key + tweak*G. Only public data goes through it, so the variable time
+// operations of the library are used.
+func tweakAdd(key *secp256k1.PublicKey, tweak []byte) (*secp256k1.PublicKey, error) {
+	var scalar secp256k1.ModNScalar
+	if overflow := scalar.SetByteSlice(tweak); overflow {
+		return nil, ErrInvalidPublicKey
+	}
+
+	var tweakPoint, keyPoint, sum secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(&scalar, &tweakPoint)
+	key.AsJacobian(&keyPoint)
+	secp256k1.AddNonConst(&tweakPoint, &keyPoint, &sum)
+	if (sum.X.IsZero() && sum.Y.IsZero()) || sum.Z.IsZero() {
+		return nil, ErrInvalidPublicKey
+	}
+	sum.ToAffine()
+	return secp256k1.NewPublicKey(&sum.X, &sum.Y), nil
+}
+
+// xOnly serializes the x coordinate of a key as used by BIP340 / taproot.
+func xOnly(key *secp256k1.PublicKey) []byte {
+	return key.SerializeCompressed()[1:]
+}
+
+// IsValidPublicKey reports whether b is a valid SEC1-encoded secp256k1 public key.
+func IsValidPublicKey(b []byte) bool {
+	_, err := parsePublicKey(b)
+	return err == nil
+}
+
+// TaprootOutputKey computes the BIP86 key-path-only output key for an internal public key.
+func TaprootOutputKey(internalKey []byte) ([]byte, error) {
+	var x []byte
+	if len(internalKey) == 32 {
+		x = internalKey
+	} else {
+		key, err := parsePublicKey(internalKey)
+		if err != nil {
+			return nil, err
+		}
+		x = xOnly(key)
+	}
+	internal, err := liftX(x)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := tweakAdd(internal, TaggedHash("TapTweak", x))
+	if err != nil {
+		return nil, err
+	}
+	return xOnly(output), nil
+}
@@ -0,0 +1,101 @@
+package bitcoin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// KeyFormat describes a SLIP-132 extended public key serialization.
+type KeyFormat struct {
+	Prefix     string
+	Version    uint32
+	Network    *Network
+	ScriptType ScriptType
+	Multisig   bool
+}
+
+// keyFormats lists the SLIP-132 public key versions understood by the explorer.
+var keyFormats = []*KeyFormat{
+	{Prefix: "xpub", Version: 0x0488b21e, Network: MainNet, ScriptType: ScriptP2PKH},
+	{Prefix: "ypub", Version: 0x049d7cb2, Network: MainNet, ScriptType: ScriptP2SHP2WPKH},
+	{Prefix: "zpub", Version: 0x04b24746, Network: MainNet, ScriptType: ScriptP2WPKH},
+	{Prefix: "Ypub", Version: 0x0295b43f, Network: MainNet, ScriptType: ScriptP2SHP2WSH, Multisig: true},
+	{Prefix: "Zpub", Version: 0x02aa7ed3, Network: MainNet, ScriptType: ScriptP2WSH, Multisig: true},
+	{Prefix: "tpub", Version: 0x043587cf, Network: TestNet, ScriptType: ScriptP2PKH},
+	{Prefix: "upub", Version: 0x044a5262, Network: TestNet, ScriptType: ScriptP2SHP2WPKH},
+	{Prefix: "vpub", Version: 0x045f1cf6, Network: TestNet, ScriptType: ScriptP2WPKH},
+	{Prefix: "Upub", Version: 0x024289ef, Network: TestNet, ScriptType: ScriptP2SHP2WSH, Multisig: true},
+	{Prefix: "Vpub", Version: 0x02575483, Network: TestNet, ScriptType: ScriptP2WSH, Multisig: true},
+}
+
+// privateVersions lists the matching private versions so they can be rejected explicitly.
+var privateVersions = map[uint32]bool{
+	0x0488ade4: true, 0x049d7878: true, 0x04b2430c: true, 0x0295b005: true, 0x02aa7a99: true,
+	0x04358394: true, 0x044a4e28: true, 0x045f18bc: true, 0x024285b5: true, 0x02575048: true,
+}
+
+func isPrivateVersion(version uint32) bool {
+	return privateVersions[version]
+}
+
+// FormatByVersion returns the SLIP-132 format for version bytes, or nil if unknown.
+func FormatByVersion(version uint32) *KeyFormat {
+	for _, f := range keyFormats {
+		if f.Version == version {
+			return f
+		}
+	}
+	return nil
+}
+
+// FormatByPrefix returns the SLIP-132 format for a prefix such as "zpub", or nil if unknown.
+func FormatByPrefix(prefix string) *KeyFormat {
+	for _, f := range keyFormats {
+		if f.Prefix == prefix {
+			return f
+		}
+	}
+	return nil
+}
+
+// ConvertExtendedKey re-serializes an extended public key with the version bytes of another
+// SLIP-132 prefix on the same network, e.g. zpub -> xpub.
+func ConvertExtendedKey(key, targetPrefix string) (string, error) {
+	parsed, err := ParseExtendedKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	target := FormatByPrefix(strings.TrimSpace(targetPrefix))
+	if target == nil {
+		return "", fmt.Errorf("unknown extended key prefix %q", targetPrefix)
+	}
+	if target.Network != parsed.Format().Network {
+		return "", fmt.Errorf("cannot convert %s key to %s: network mismatch", parsed.Format().Prefix, target.Prefix)
+	}
+
+	parsed.Version = target.Version
+	return parsed.String(), nil
+}
+
+// CanonicalKey returns the key re-serialized with the plain xpub/tpub version used by descriptors.
+func (k *ExtendedKey) CanonicalKey() *ExtendedKey {
+	canonical := *k
+	if k.Format().Network == TestNet {
+		canonical.Version = FormatByPrefix("tpub").Version
+	} else {
+		canonical.Version = FormatByPrefix("xpub").Version
+	}
+	return &canonical
+}
+
+// FormatForScript returns the SLIP-132 format used for a script type on a network, or nil
+// when no prefix exists for it (e.g. taproot).
+func FormatForScript(scriptType ScriptType, net *Network, multisig bool) *KeyFormat {
+	for _, f := range keyFormats {
+		if f.ScriptType == scriptType && f.Network == net && f.Multisig == multisig {
+			return f
+		}
+	}
+	return nil
+}
@@ -0,0 +1,309 @@
+package bitcoin
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrInvalidTransaction is returned when bytes cannot be parsed as a serialized transaction.
+var ErrInvalidTransaction = errors.New("invalid transaction")
+
+// TxIn is a transaction input.
+type TxIn struct {
+	PrevTxID  [32]byte // internal byte order
+	PrevIndex uint32
+	ScriptSig []byte
+	Sequence  uint32
+	Witness   [][]byte
+}
+
+// PrevTxIDHex returns the previous txid in the usual display (reversed) order.
+func (in *TxIn) PrevTxIDHex() string {
+	return reversedHex(in.PrevTxID[:])
+}
+
+// TxOut is a transaction output.
+type TxOut struct {
+	Value  int64
+	Script []byte
+}
+
+// Tx is a deserialized Bitcoin transaction.
+type Tx struct {
+	Version  int32
+	Inputs   []*TxIn
+	Outputs  []*TxOut
+	LockTime uint32
+}
+
+// ParseTransaction decodes a transaction in legacy or BIP144 segwit serialization.
+func ParseTransaction(raw []byte) (*Tx, error) {
+	r := bytes.NewReader(raw)
+	tx, err := readTransaction(r)
+	if err != nil {
+		return nil, err
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrInvalidTransaction, r.Len())
+	}
+	return tx, nil
+}
+
+func readTransaction(r *bytes.Reader) (*Tx, error) {
+	tx := &Tx{}
+	fail := func(err error) (*Tx, error) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+
+	var version uint32
+	if err := binary.Read(r, binary.LittleEndian, &version); err != nil {
+		return fail(err)
+	}
+	tx.Version = int32(version)
+
+	inputCount, err := readCompactSize(r)
+	if err != nil {
+		return fail(err)
+	}
+
+	segwit := false
+	if inputCount == 0 {
+		flag, err := r.ReadByte()
+		if err != nil || flag != 0x01 {
+			return fail(errors.New("invalid segwit flag"))
+		}
+		segwit = true
+		if inputCount, err = readCompactSize(r); err != nil {
+			return fail(err)
+		}
+	}
+	if inputCount > uint64(r.Len()) {
+		return fail(errors.New("input count exceeds payload"))
+	}
+
+	for i := uint64(0); i < inputCount; i++ {
+		in := &TxIn{}
+		if _, err := io.ReadFull(r, in.PrevTxID[:]); err != nil {
+			return fail(err)
+		}
+		if err := binary.Read(r, binary.LittleEndian, &in.PrevIndex); err != nil {
+			return fail(err)
+		}
+		if in.ScriptSig, err = readVarBytes(r); err != nil {
+			return fail(err)
+		}
+		if err := binary.Read(r, binary.LittleEndian, &in.Sequence); err != nil {
+			return fail(err)
+		}
+		tx.Inputs = append(tx.Inputs, in)
+	}
+
+	outputCount, err := readCompactSize(r)
+	if err != nil {
+		return fail(err)
+	}
+	if outputCount > uint64(r.Len()) {
+		return fail(errors.New("output count exceeds payload"))
+	}
+	for i := uint64(0); i < outputCount; i++ {
+		out := &TxOut{}
+		if err := binary.Read(r, binary.LittleEndian, &out.Value); err != nil {
+			return fail(err)
+		}
+		if out.Script, err = readVarBytes(r); err != nil {
+			return fail(err)
+		}
+		tx.Outputs = append(tx.Outputs, out)
+	}
+
+	if segwit {
+		for _, in := range tx.Inputs {
+			items, err := readCompactSize(r)
+			if err != nil {
+				return fail(err)
+			}
+			if items > uint64(r.Len()) {
+				return fail(errors.New("witness item count exceeds payload"))
+			}
+			for j := uint64(0); j < items; j++ {
+				item, err := readVarBytes(r)
+				if err != nil {
+					return fail(err)
+				}
+				in.Witness = append(in.Witness, item)
+			}
+		}
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &tx.LockTime); err != nil {
+		return fail(err)
+	}
+	return tx, nil
+}
+
+// HasWitness reports whether any input carries witness data.
+func (tx *Tx) HasWitness() bool {
+	for _, in := range tx.Inputs {
+		if len(in.Witness) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Serialize encodes the transaction, including witness data when withWitness is set and present.
+func (tx *Tx) Serialize(withWitness bool) []byte {
+	withWitness = withWitness && tx.HasWitness()
+
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(tx.Version))
+	if withWitness {
+		buf.Write([]byte{0x00, 0x01})
+	}
+	writeCompactSize(&buf, uint64(len(tx.Inputs)))
+	for _, in := range tx.Inputs {
+		buf.Write(in.PrevTxID[:])
+		_ = binary.Write(&buf, binary.LittleEndian, in.PrevIndex)
+		writeVarBytes(&buf, in.ScriptSig)
+		_ = binary.Write(&buf, binary.LittleEndian, in.Sequence)
+	}
+	writeCompactSize(&buf, uint64(len(tx.Outputs)))
+	for _, out := range tx.Outputs {
+		_ = binary.Write(&buf, binary.LittleEndian, out.Value)
+		writeVarBytes(&buf, out.Script)
+	}
+	if withWitness {
+		for _, in := range tx.Inputs {
+			buf.Write(SerializeWitness(in.Witness))
+		}
+	}
+	_ = binary.Write(&buf, binary.LittleEndian, tx.LockTime)
+	return buf.Bytes()
+}
+
+// TxID returns the transaction id in display order.
+func (tx *Tx) TxID() string {
+	return reversedHex(DoubleSHA256(tx.Serialize(false)))
+}
+
+// WTxID returns the witness transaction id in display order.
+func (tx *Tx) WTxID() string {
+	return reversedHex(DoubleSHA256(tx.Serialize(true)))
+}
+
+// Size returns the serialized size in bytes including witness data.
+func (tx *Tx) Size() int {
+	return len(tx.Serialize(true))
+}
+
+// Weight returns the BIP141 weight.
+func (tx *Tx) Weight() int {
+	return len(tx.Serialize(false))*3 + tx.Size()
+}
+
+// VSize returns the virtual size (weight / 4, rounded up).
+func (tx *Tx) VSize() int {
+	return (tx.Weight() + 3) / 4
+}
+
+// IsCoinbase reports whether the transaction is a coinbase transaction.
+func (tx *Tx) IsCoinbase() bool {
+	return len(tx.Inputs) == 1 && tx.Inputs[0].PrevIndex == 0xffffffff && tx.Inputs[0].PrevTxID == [32]byte{}
+}
+
+// SerializeWitness encodes a witness stack as item count followed by length-prefixed items.
+func SerializeWitness(witness [][]byte) []byte {
+	var buf bytes.Buffer
+	writeCompactSize(&buf, uint64(len(witness)))
+	for _, item := range witness {
+		writeVarBytes(&buf, item)
+	}
+	return buf.Bytes()
+}
+
+// ParseWitness decodes a serialized witness stack.
+func ParseWitness(raw []byte) ([][]byte, error) {
+	r := bytes.NewReader(raw)
+	count, err := readCompactSize(r)
+	if err != nil || count > uint64(r.Len()) {
+		return nil, ErrInvalidTransaction
+	}
+	witness := make([][]byte, 0, count)
+	for i := uint64(0); i < count; i++ {
+		item, err := readVarBytes(r)
+		if err != nil {
+			return nil, ErrInvalidTransaction
+		}
+		witness = append(witness, item)
+	}
+	return witness, nil
+}
+
+func readCompactSize(r *bytes.Reader) (uint64, error) {
+	prefix, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	switch prefix {
+	case 0xfd:
+		var v uint16
+		err = binary.Read(r, binary.LittleEndian, &v)
+		return uint64(v), err
+	case 0xfe:
+		var v uint32
+		err = binary.Read(r, binary.LittleEndian, &v)
+		return uint64(v), err
+	case 0xff:
+		var v uint64
+		err = binary.Read(r, binary.LittleEndian, &v)
+		return v, err
+	default:
+		return uint64(prefix), nil
+	}
+}
+
+func writeCompactSize(buf *bytes.Buffer, n uint64) {
+	switch {
+	case n < 0xfd:
+		buf.WriteByte(byte(n))
+	case n <= 0xffff:
+		buf.WriteByte(0xfd)
+		_ = binary.Write(buf, binary.LittleEndian, uint16(n))
+	case n <= 0xffffffff:
+		buf.WriteByte(0xfe)
+		_ = binary.Write(buf, binary.LittleEndian, uint32(n))
+	default:
+		buf.WriteByte(0xff)
+		_ = binary.Write(buf, binary.LittleEndian, n)
+	}
+}
+
+func readVarBytes(r *bytes.Reader) ([]byte, error) {
+	n, err := readCompactSize(r)
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(r.Len()) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	b := make([]byte, n)
+	_, err = io.ReadFull(r, b)
+	return b, err
+}
+
+func writeVarBytes(buf *bytes.Buffer, b []byte) {
+	writeCompactSize(buf, uint64(len(b)))
+	buf.Write(b)
+}
+
+func reversedHex(b []byte) string {
+	reversed := make([]byte, len(b))
+	for i := range b {
+		reversed[i] = b[len(b)-1-i]
+	}
+	return hex.EncodeToString(reversed)
+}
@@ -0,0 +1,197 @@
+// Package cache provides response caching for the external API services, with in-memory and
+// SQL backends, request coalescing and stale-while-revalidate refreshes.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"cry-api/app/logger"
+	UserModel "cry-api/app/models"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Bounds of the stored entries. Values that do not fit are served without being cached.
+const (
+	MaxKeyLength = UserModel.MaxCacheKeyLength
+	MaxEntrySize = UserModel.MaxCacheValueSize
+)
+
+// ErrEntryTooLarge is returned by a store asked to keep an entry beyond MaxKeyLength or MaxEntrySize
+var ErrEntryTooLarge = errors.New("cache entry too large")
+
+// Entry is a cached value, stored as JSON
+type Entry struct {
+	Value      []byte
+	ExpiresAt  time.Time // fresh until
+	StaleUntil time.Time // may still be served, while it is refreshed, until
+}
+
+// Store is a cache backend
+type Store interface {
+	// Get returns the entry stored under key, or nil when there is none.
+	Get(key string) (*Entry, error)
+
+	// Set stores an entry under key, replacing any previous one.
+	Set(key string, entry *Entry) error
+
+	// Delete removes the entry stored under key.
+	Delete(key string) error
+}
+
+// Policy controls how long a value stays fresh, and how long after that it is still served
+// while a refresh runs in the background. A zero TTL disables caching of the value.
+type Policy struct {
+	TTL      time.Duration
+	StaleTTL time.Duration
+}
+
+// Fixed returns a policy function that applies p whatever the value
+func Fixed[T any](p Policy) func(T) Policy {
+	return func(T) Policy { return p }
+}
+
+// Cache sits in front of a Store. Concurrent lookups of the same key share a single call to
+// upstream, and a stale value is served at once while it is refreshed in the background, so
+// that upstream latency and failures only show once the value is too stale.
+type Cache struct {
+	store Store
+	group singleflight.Group
+	now   func() time.Time
+
+	refreshing sync.Map // keys being refreshed in the background
+	refreshes  sync.WaitGroup
+}
+
+// New returns a Cache backed by store
+func New(store Store) *Cache {
+	return &Cache{store: store, now: time.Now}
+}
+
+// SetClock replaces the time source, for tests
+func (c *Cache) SetClock(now func() time.Time) {
+	c.now = now
+}
+
+// Invalidate removes a cached value
+func (c *Cache) Invalidate(key string) error {
+	return c.store.Delete(key)
+}
+
+// Wait blocks until the background refreshes in flight are done
+func (c *Cache) Wait() {
+	c.refreshes.Wait()
+}
+
+// Fetch returns the value cached under key, calling load when there is no fresh value.
+// policy decides how long the loaded value is kept, so it can depend on the value itself
+// (e.g. a transaction becomes immutable once deeply confirmed). A nil cache always loads.
+// Every caller receives its own copy of the value, so callers may modify it.
+//
+// A value past its TTL but within its StaleTTL is returned at once and refreshed in the
+// background. load runs on a context detached from the cancellation of ctx, since its result
+// is shared with the other callers of the key and stored; a caller whose ctx is done stops
+// waiting with the error of its ctx, without cancelling the load.
+func Fetch[T any](ctx context.Context, c *Cache, key string, policy func(T) Policy, load func(context.Context) (T, error)) (T, error) {
+	var zero T
+	if c == nil {
+		return load(ctx)
+	}
+
+	log := logger.FromContext(ctx).WithField("cache_key", key)
+
+	entry, err := c.store.Get(key)
+	if err != nil {
+		log.WithError(err).Warn("Cache read failed")
+		entry = nil
+	}
+
+	now := c.now()
+	if entry != nil && now.Before(entry.StaleUntil) {
+		var value T
+		if err := json.Unmarshal(entry.Value, &value); err == nil {
+			if !now.Before(entry.ExpiresAt) {
+				refresh(ctx, c, key, policy, load)
+			}
+			return value, nil
+		}
+		log.Warn("Discarding unreadable cache entry")
+	}
+
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case result := <-c.group.DoChan(key, loadAndStore(ctx, c, key, policy, load)):
+		if result.Err != nil {
+			return zero, result.Err
+		}
+		var value T
+		if err := json.Unmarshal(result.Val.([]byte), &value); err != nil {
+			return zero, err
+		}
+		return value, nil
+	}
+}
+
+// refresh reloads a stale value in the background, unless a refresh of key is already running
+func refresh[T any](ctx context.Context, c *Cache, key string, policy func(T) Policy, load func(context.Context) (T, error)) {
+	if _, running := c.refreshing.LoadOrStore(key, struct{}{}); running {
+		return
+	}
+
+	c.refreshes.Add(1)
+	go func() {
+		defer c.refreshes.Done()
+		defer c.refreshing.Delete(key)
+
+		if _, err, _ := c.group.Do(key, loadAndStore(ctx, c, key, policy, load)); err != nil {
+			logger.FromContext(ctx).WithField("cache_key", key).WithError(err).Warn("Upstream failed, serving stale value")
+		}
+	}()
+}
+
+// loadAndStore returns the shared call loading the value of key and storing it as JSON
+func loadAndStore[T any](ctx context.Context, c *Cache, key string, policy func(T) Policy, load func(context.Context) (T, error)) func() (any, error) {
+	return func() (any, error) {
+		loadCtx := context.WithoutCancel(ctx)
+		value, err := load(loadCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(key) > MaxKeyLength || len(raw) > MaxEntrySize {
+			logger.FromContext(loadCtx).WithField("cache_key", truncateKey(key)).WithField("size", len(raw)).Warn("Value too large to cache")
+			return raw, nil
+		}
+
+		if p := policy(value); p.TTL > 0 {
+			now := c.now()
+			stored := &Entry{
+				Value:      raw,
+				ExpiresAt:  now.Add(p.TTL),
+				StaleUntil: now.Add(p.TTL + p.StaleTTL),
+			}
+			if err := c.store.Set(key, stored); err != nil {
+				logger.FromContext(loadCtx).WithField("cache_key", key).WithError(err).Warn("Cache write failed")
+			}
+		}
+		return raw, nil
+	}
+}
+
+// truncateKey shortens an oversized key for the logs
+func truncateKey(key string) string {
+	if len(key) <= MaxKeyLength {
+		return key
+	}
+	return key[:MaxKeyLength] + "..."
+}
@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// DefaultMemorySize is the number of entries kept by the in-memory store by default
+const DefaultMemorySize = 10000
+
+// MemoryStore is an in-process LRU store. Entries are dropped once they can no longer be
+// served, even stale, or when the store is full and they are the least recently used.
+type MemoryStore struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List
+	now      func() time.Time
+}
+
+type memoryItem struct {
+	key   string
+	entry *Entry
+}
+
+// NewMemoryStore returns a MemoryStore holding at most capacity entries
+func NewMemoryStore(capacity int) *MemoryStore {
+	if capacity <= 0 {
+		capacity = DefaultMemorySize
+	}
+	return &MemoryStore{
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+// Get returns the entry stored under key and marks it as recently used
+func (s *MemoryStore) Get(key string) (*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.items[key]
+	if !ok {
+		return nil, nil
+	}
+
+	item := elem.Value.(*memoryItem)
+	if !s.now().Before(item.entry.StaleUntil) {
+		s.remove(elem)
+		return nil, nil
+	}
+
+	s.order.MoveToFront(elem)
+	return item.entry, nil
+}
+
+// Set stores an entry, evicting the least recently used one when the store is full. Entries
+// beyond MaxKeyLength or MaxEntrySize are refused with ErrEntryTooLarge.
+func (s *MemoryStore) Set(key string, entry *Entry) error {
+	if len(key) > MaxKeyLength || len(entry.Value) > MaxEntrySize {
+		return ErrEntryTooLarge
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, ok := s.items[key]; ok {
+		elem.Value.(*memoryItem).entry = entry
+		s.order.MoveToFront(elem)
+		return nil
+	}
+
+	s.items[key] = s.order.PushFront(&memoryItem{key: key, entry: entry})
+	for s.order.Len() > s.capacity {
+		s.remove(s.order.Back())
+	}
+	return nil
+}
+
+// Delete removes the entry stored under key
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, ok := s.items[key]; ok {
+		s.remove(elem)
+	}
+	return nil
+}
+
+// Len returns the number of entries held
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+func (s *MemoryStore) remove(elem *list.Element) {
+	s.order.Remove(elem)
+	delete(s.items, elem.Value.(*memoryItem).key)
+}
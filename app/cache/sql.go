@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"sync/atomic"
+	"time"
+
+	UserModel "cry-api/app/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// pruneEvery is how many writes pass between two purges of dead entries
+const pruneEvery = 500
+
+// SQLStore keeps entries in the cache_entries table, so they survive restarts and are shared
+// between API instances.
+type SQLStore struct {
+	db     *gorm.DB
+	writes atomic.Int64
+	now    func() time.Time
+}
+
+// NewSQLStore returns a SQLStore using db
+func NewSQLStore(db *gorm.DB) *SQLStore {
+	return &SQLStore{db: db, now: time.Now}
+}
+
+// Get returns the entry stored under key, ignoring entries that can no longer be served
+func (s *SQLStore) Get(key string) (*Entry, error) {
+	var row UserModel.CacheEntry
+	err := s.db.Where(keyIs(key)).Where("stale_until > ?", s.now()).Take(&row).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &Entry{Value: row.Value, ExpiresAt: row.ExpiresAt, StaleUntil: row.StaleUntil}, nil
+}
+
+// Set upserts an entry and periodically purges dead ones. Entries beyond MaxKeyLength or
+// MaxEntrySize are refused with ErrEntryTooLarge.
+func (s *SQLStore) Set(key string, entry *Entry) error {
+	if len(key) > MaxKeyLength || len(entry.Value) > MaxEntrySize {
+		return ErrEntryTooLarge
+	}
+
+	row := UserModel.CacheEntry{
+		Key:        key,
+		Value:      entry.Value,
+		ExpiresAt:  entry.ExpiresAt,
+		StaleUntil: entry.StaleUntil,
+	}
+	if err := s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&row).Error; err != nil {
+		return err
+	}
+
+	if s.writes.Add(1)%pruneEvery == 0 {
+		return s.Prune()
+	}
+	return nil
+}
+
+// Delete removes the entry stored under key
+func (s *SQLStore) Delete(key string) error {
+	return s.db.Where(keyIs(key)).Delete(&UserModel.CacheEntry{}).Error
+}
+
+// Prune removes every entry that can no longer be served
+func (s *SQLStore) Prune() error {
+	return s.db.Where("stale_until <= ?", s.now()).Delete(&UserModel.CacheEntry{}).Error
+}
+
+// keyIs matches the key column, quoted for the dialect since KEY is reserved in MySQL
+func keyIs(key string) clause.Eq {
+	return clause.Eq{Column: clause.Column{Name: "key"}, Value: key}
+}
@@ -2,7 +2,14 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
 	"strconv"
+	"sync"
+	"syscall"
 	"time"
 
 	"cry-api/app/config"
@@ -17,6 +24,9 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// shutdownTimeout bounds the wait for the in-flight requests once a stop signal is received
+const shutdownTimeout = 30 * time.Second
+
 func main() {
 	// Initialize logger
 	appLogger := logger.GetLogger()
@@ -49,27 +59,96 @@ func main() {
 	container := container.InitializeContainer(cfg, db)
 	appLogger.Info("Dependency injection container initialized")
 
-	// Setup Gin router
-	router := gin.Default()
-
-	// Add middleware
-	router.Use(middleware.ErrorHandler())
-	router.Use(middleware.SecurityMiddleware())
-	router.Use(middleware.RequestLoggerMiddleware())
-	router.Use(middleware.RateLimitMiddleware())
-	router.Use(middleware.ContentTypeMiddleware())
-	router.Use(middleware.RequestSizeMiddleware(10 * 1024 * 1024)) // 10MB limit
-	router.Use(middleware.HealthCheckMiddleware())
+	// The background jobs and the server stop on SIGINT or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var jobs sync.WaitGroup
+	startJob := func(run func(context.Context)) {
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			run(ctx)
+		}()
+	}
+
+	// Start the watched wallet notification job
+	if cfg.WatcherConfig.Interval > 0 {
+		startJob(container.GetWatcherService().Run)
+		appLogger.WithField("interval_seconds", cfg.WatcherConfig.Interval).Info("Watchlist watcher started")
+	}
+
+	// Start the fear and greed history sync
+	if cfg.CoinMarketCapConfig.FearGreedSyncInterval > 0 {
+		startJob(container.GetFearGreedService().Run)
+		appLogger.WithField("interval_seconds", cfg.CoinMarketCapConfig.FearGreedSyncInterval).Info("Fear and greed sync started")
+	}
+
+	// Start the BTC price history sync
+	if cfg.CoinMarketCapConfig.PriceHistorySyncInterval > 0 {
+		startJob(container.GetPriceHistoryService().Run)
+		appLogger.WithField("interval_seconds", cfg.CoinMarketCapConfig.PriceHistorySyncInterval).Info("Price history sync started")
+	}
+
+	// Start the market data alert evaluator
+	if cfg.AlertConfig.Interval > 0 {
+		startJob(container.GetAlertEvaluatorService().Run)
+		appLogger.WithField("interval_seconds", cfg.AlertConfig.Interval).Info("Alert evaluator started")
+	}
+
+	// Start the realtime market data feed
+	if cfg.RealtimeConfig.PriceInterval > 0 {
+		startJob(container.GetMarketFeedService().Run)
+		appLogger.WithField("interval_seconds", cfg.RealtimeConfig.PriceInterval).Info("Realtime market feed started")
+	}
+
+	// Setup Gin router with the shared middleware chain
+	router := gin.New()
+	middleware.UseGlobal(router, container.GetMetrics())
 	router.Use(SetupCORS(cfg))
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(middleware.NoRoute)
+	router.NoMethod(middleware.NoMethod)
 
 	// Register routes with container
 	routes.RegisterAllRoutes(router, container)
 
-	appLogger.WithField("port", cfg.APIPort).Info("Server starting on port")
+	// Shutdown does not wait for the hijacked WebSocket connections, nor end the SSE streams;
+	// closing the hub ends both
+	server := &http.Server{
+		Addr:              ":" + strconv.Itoa(cfg.APIPort),
+		Handler:           router,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	server.RegisterOnShutdown(container.GetRealtimeHub().Close)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		appLogger.WithField("port", cfg.APIPort).Info("Server starting on port")
+		serveErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			appLogger.WithError(err).Fatal("Failed to run server")
+		}
+	case <-ctx.Done():
+		stop()
+		appLogger.Info("Shutting down")
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			appLogger.WithError(err).Error("Failed to shut down the server gracefully")
+		}
+	}
 
-	if err := router.Run(":" + strconv.Itoa(cfg.APIPort)); err != nil {
-		appLogger.WithError(err).Fatal("Failed to run server")
+	jobs.Wait()
+	if err := dbConn.Close(); err != nil {
+		appLogger.WithError(err).Warn("Failed to close the database connection")
 	}
+	appLogger.Info("Server stopped")
 }
 
 // SetupCORS funcs provides config and setups CORS
@@ -77,8 +156,8 @@ func SetupCORS(cfg *Env.EnvConfig) gin.HandlerFunc {
 	return cors.New(cors.Config{
 		AllowOrigins:     []string{cfg.CryAppURL},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Authorization", "Content-Type"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Authorization", "Content-Type", middleware.RequestIDHeader},
+		ExposeHeaders:    []string{"Content-Length", middleware.RequestIDHeader},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 
@@ -83,9 +83,62 @@ func Load() *types.EnvConfig {
 	// Load BLOCKCHAIN_API
 	blockChainAPI := os.Getenv("BLOCKCHAIN_API")
 
+	// Load MEMPOOL_API
+	mempoolAPI := getEnv("MEMPOOL_API", "https://mempool.space/api")
+
+	// Load ESPLORA_API
+	esploraAPI := getEnv("ESPLORA_API", "https://blockstream.info/api")
+
+	// Load the fee, tip and mempool status providers, tried in order
+	mempoolProviders := getEnvAsList("MEMPOOL_PROVIDERS", []string{"mempool", "esplora"})
+
+	// Load the transaction broadcast providers, tried in order
+	broadcastProviders := getEnvAsList("BROADCAST_PROVIDERS", []string{"mempool", "blockchain"})
+
+	// Load the EVM chain nodes as chain=url pairs
+	evmNodes := getEnvAsMap("EVM_RPC_URLS", map[string]string{"ethereum": "https://ethereum-rpc.publicnode.com"})
+	evmLogBlockRange := getEnvAsInt("EVM_LOG_BLOCK_RANGE", 5000)
+
+	// Load the watched wallet notification job settings
+	watcherInterval := getEnvAsInt("WATCHER_INTERVAL", 300)
+	watcherConfirmations := getEnvAsInt("WATCHER_CONFIRMATIONS", 6)
+	watcherLargeMovement := getEnvAsInt("WATCHER_LARGE_MOVEMENT_SATS", 100000000)
+
+	// Load the market data alert settings
+	alertInterval := getEnvAsInt("ALERT_INTERVAL", 60)
+	alertDefaultCooldown := getEnvAsInt("ALERT_DEFAULT_COOLDOWN", 60)
+	alertMaxRules := getEnvAsInt("ALERT_MAX_RULES", 50)
+
+	// Load realtime gateway settings
+	realtimeHeartbeatInterval := getEnvAsInt("REALTIME_HEARTBEAT_INTERVAL", 25)
+	realtimeBufferSize := getEnvAsInt("REALTIME_BUFFER_SIZE", 64)
+	realtimeMaxSubscriptions := getEnvAsInt("REALTIME_MAX_SUBSCRIPTIONS", 20)
+	realtimePriceInterval := getEnvAsInt("REALTIME_PRICE_INTERVAL", 15)
+
 	// Load CoinMarketCap API
 	coinMarketCapAPI := os.Getenv("COIN_MARKET_CAP_API")
 	coinMarketCapAPIKey := os.Getenv("COIN_MARKET_CAP_API_KEY")
+	fearGreedSyncInterval := getEnvAsInt("FEAR_GREED_SYNC_INTERVAL", 21600)
+	priceHistorySyncInterval := getEnvAsInt("PRICE_HISTORY_SYNC_INTERVAL", 21600)
+
+	// Load the market data providers, tried in order
+	marketDataProviders := getEnvAsList("MARKET_DATA_PROVIDERS", []string{"coinmarketcap", "coingecko", "alternative"})
+	coinGeckoAPI := getEnv("COINGECKO_API", "https://api.coingecko.com/api/v3")
+	coinGeckoAPIKey := os.Getenv("COINGECKO_API_KEY")
+	alternativeMeAPI := getEnv("ALTERNATIVE_ME_API", "https://api.alternative.me")
+
+	// Load the response cache settings
+	cacheBackend := getEnv("CACHE_BACKEND", "memory")
+	cacheSize := getEnvAsInt("CACHE_SIZE", 10000)
+
+	// Load the outbound HTTP client settings
+	httpMaxRetries := getEnvAsInt("HTTP_MAX_RETRIES", 2)
+	httpBreakerThreshold := getEnvAsInt("HTTP_BREAKER_THRESHOLD", 5)
+	httpBreakerCooldown := getEnvAsInt("HTTP_BREAKER_COOLDOWN", 30)
+
+	// Load the metrics endpoint settings
+	metricsEnabled := getEnvAsBool("METRICS_ENABLED", false)
+	metricsToken := os.Getenv("METRICS_TOKEN")
 
 	// Set the config instance
 	configInstance = &types.EnvConfig{
@@ -109,9 +162,58 @@ func Load() *types.EnvConfig {
 		BlockchainConfig: types.BlockchainConfig{
 			API: blockChainAPI,
 		},
+		MempoolConfig: types.MempoolConfig{
+			API:        mempoolAPI,
+			EsploraAPI: esploraAPI,
+			Providers:  mempoolProviders,
+		},
+		BroadcastConfig: types.BroadcastConfig{
+			Providers: broadcastProviders,
+		},
+		EVMConfig: types.EVMConfig{
+			Nodes:         evmNodes,
+			LogBlockRange: evmLogBlockRange,
+		},
+		WatcherConfig: types.WatcherConfig{
+			Interval:          watcherInterval,
+			Confirmations:     watcherConfirmations,
+			LargeMovementSats: int64(watcherLargeMovement),
+		},
+		AlertConfig: types.AlertConfig{
+			Interval:        alertInterval,
+			DefaultCooldown: alertDefaultCooldown,
+			MaxRules:        alertMaxRules,
+		},
+		RealtimeConfig: types.RealtimeConfig{
+			HeartbeatInterval: realtimeHeartbeatInterval,
+			BufferSize:        realtimeBufferSize,
+			MaxSubscriptions:  realtimeMaxSubscriptions,
+			PriceInterval:     realtimePriceInterval,
+		},
 		CoinMarketCapConfig: types.CoinMarketCapConfig{
-			API:    coinMarketCapAPI,
-			APIKey: coinMarketCapAPIKey,
+			API:                      coinMarketCapAPI,
+			APIKey:                   coinMarketCapAPIKey,
+			FearGreedSyncInterval:    fearGreedSyncInterval,
+			PriceHistorySyncInterval: priceHistorySyncInterval,
+		},
+		MarketDataConfig: types.MarketDataConfig{
+			Providers:        marketDataProviders,
+			CoinGeckoAPI:     coinGeckoAPI,
+			CoinGeckoAPIKey:  coinGeckoAPIKey,
+			AlternativeMeAPI: alternativeMeAPI,
+		},
+		CacheConfig: types.CacheConfig{
+			Backend: cacheBackend,
+			Size:    cacheSize,
+		},
+		HTTPClientConfig: types.HTTPClientConfig{
+			MaxRetries:       httpMaxRetries,
+			BreakerThreshold: httpBreakerThreshold,
+			BreakerCooldown:  httpBreakerCooldown,
+		},
+		MetricsConfig: types.MetricsConfig{
+			Enabled: metricsEnabled,
+			Token:   metricsToken,
 		},
 	}
 
@@ -160,3 +262,43 @@ func getEnvAsInt(key string, fallback int) int {
 	}
 	return intValue
 }
+
+// Helper function to get an environment variable as a boolean with a fallback value
+func getEnvAsBool(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	boolValue, err := strconv.ParseBool(value)
+	if err != nil {
+		return fallback
+	}
+	return boolValue
+}
+
+// Helper function to get a comma-separated environment variable as a list with a fallback value
+func getEnvAsList(key string, fallback []string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return fallback
+	}
+	return values
+}
+
+// Helper function to get a comma-separated list of key=value pairs as a map with a fallback value
+func getEnvAsMap(key string, fallback map[string]string) map[string]string {
+	values := make(map[string]string)
+	for _, pair := range getEnvAsList(key, nil) {
+		name, value, _ := strings.Cut(pair, "=")
+		values[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	if len(values) == 0 {
+		return fallback
+	}
+	return values
+}
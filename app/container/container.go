@@ -30,10 +30,36 @@ func (c *Container) Get(name string) interface{} {
 		return c.GetDB()
 	case "config":
 		return c.GetConfig()
+	case "cache":
+		return c.GetCache()
+	case "httpClient":
+		return c.GetHTTPClient()
+	case "metrics":
+		return c.GetMetrics()
 	case "userRepository":
 		return c.GetUserRepository()
 	case "userTokenRepository":
 		return c.GetUserTokenRepository()
+	case "watchedWalletRepository":
+		return c.GetWatchedWalletRepository()
+	case "transferTagRepository":
+		return c.GetTransferTagRepository()
+	case "labelRepository":
+		return c.GetLabelRepository()
+	case "broadcastAuditRepository":
+		return c.GetBroadcastAuditRepository()
+	case "notificationRepository":
+		return c.GetNotificationRepository()
+	case "walletCursorRepository":
+		return c.GetWalletCursorRepository()
+	case "fearGreedRepository":
+		return c.GetFearGreedRepository()
+	case "priceHistoryRepository":
+		return c.GetPriceHistoryRepository()
+	case "alertRuleRepository":
+		return c.GetAlertRuleRepository()
+	case "alertTriggerRepository":
+		return c.GetAlertTriggerRepository()
 	case "passwordService":
 		return c.GetPasswordService()
 	case "emailService":
@@ -48,8 +74,48 @@ func (c *Container) Get(name string) interface{} {
 		return c.GetTwoFactorService()
 	case "coinMarketCapService":
 		return c.GetCoinMarketCapService()
+	case "fxService":
+		return c.GetFXService()
 	case "transactionService":
 		return c.GetTransactionService()
+	case "descriptorService":
+		return c.GetDescriptorService()
+	case "decoderService":
+		return c.GetDecoderService()
+	case "mempoolService":
+		return c.GetMempoolService()
+	case "broadcastService":
+		return c.GetBroadcastService()
+	case "evmService":
+		return c.GetEVMService()
+	case "watchlistService":
+		return c.GetWatchlistService()
+	case "portfolioService":
+		return c.GetPortfolioService()
+	case "taxService":
+		return c.GetTaxService()
+	case "exportService":
+		return c.GetExportService()
+	case "labelService":
+		return c.GetLabelService()
+	case "notificationService":
+		return c.GetNotificationService()
+	case "watcherService":
+		return c.GetWatcherService()
+	case "fearGreedService":
+		return c.GetFearGreedService()
+	case "priceHistoryService":
+		return c.GetPriceHistoryService()
+	case "fearGreedAnalyticsService":
+		return c.GetFearGreedAnalyticsService()
+	case "alertService":
+		return c.GetAlertService()
+	case "alertEvaluatorService":
+		return c.GetAlertEvaluatorService()
+	case "realtimeHub":
+		return c.GetRealtimeHub()
+	case "marketFeedService":
+		return c.GetMarketFeedService()
 	default:
 		return nil
 	}
@@ -2,16 +2,34 @@
 package container
 
 import (
+	"net/url"
+	"time"
+
+	"cry-api/app/cache"
 	"cry-api/app/config"
+	"cry-api/app/database"
 	Email "cry-api/app/email"
+	"cry-api/app/httpclient"
+	"cry-api/app/metrics"
 	UserRepository "cry-api/app/repositories"
 	TwoFactorService "cry-api/app/services/2fa"
+	AlertService "cry-api/app/services/alert"
 	AuthService "cry-api/app/services/auth"
 	PasswordService "cry-api/app/services/auth/password"
 	CoinMarketCapService "cry-api/app/services/coin_market_cap"
 	EmailService "cry-api/app/services/email"
+	EVMService "cry-api/app/services/evm"
+	ExportService "cry-api/app/services/export"
+	FXService "cry-api/app/services/fx"
+	LabelService "cry-api/app/services/label"
+	MarketDataService "cry-api/app/services/market_data"
+	NotificationService "cry-api/app/services/notification"
+	PortfolioService "cry-api/app/services/portfolio"
+	RealtimeService "cry-api/app/services/realtime"
+	TaxService "cry-api/app/services/tax"
 	UserService "cry-api/app/services/users"
 	WalletExplorerService "cry-api/app/services/wallet_explorer"
+	WatchlistService "cry-api/app/services/watchlist"
 	EnvTypes "cry-api/app/types/env"
 
 	"gorm.io/gorm"
@@ -20,12 +38,25 @@ import (
 // ServiceContainer is an improved type-safe dependency injection container
 type ServiceContainer struct {
 	// Core dependencies
-	db     *gorm.DB
-	config *EnvTypes.EnvConfig
+	db      *gorm.DB
+	config  *EnvTypes.EnvConfig
+	cache   *cache.Cache
+	http    *httpclient.Client
+	metrics *metrics.Metrics
 
 	// Repositories
 	userRepo      UserRepository.UserRepository
 	userTokenRepo UserRepository.UserTokenRepository
+	watchedRepo   UserRepository.WatchedWalletRepository
+	transferRepo  UserRepository.TransferTagRepository
+	labelRepo     UserRepository.LabelRepository
+	broadcastRepo UserRepository.BroadcastAuditRepository
+	notifyRepo    UserRepository.NotificationRepository
+	cursorRepo    UserRepository.WalletCursorRepository
+	fearGreedRepo UserRepository.FearGreedRepository
+	priceRepo     UserRepository.PriceHistoryRepository
+	alertRuleRepo UserRepository.AlertRuleRepository
+	triggerRepo   UserRepository.AlertTriggerRepository
 
 	// Services
 	passwordService      PasswordService.PasswordServiceInterface
@@ -35,7 +66,27 @@ type ServiceContainer struct {
 	userService          UserService.UserServiceInterface
 	twoFactorService     TwoFactorService.TwoFactorServiceInterface
 	coinMarketCapService CoinMarketCapService.CoinMarketCapServiceInterface
+	fxService            FXService.FXServiceInterface
 	transactionService   WalletExplorerService.TransactionServiceInterface
+	descriptorService    WalletExplorerService.DescriptorServiceInterface
+	decoderService       WalletExplorerService.DecoderServiceInterface
+	mempoolService       WalletExplorerService.MempoolServiceInterface
+	broadcastService     WalletExplorerService.BroadcastServiceInterface
+	evmService           EVMService.EVMServiceInterface
+	watchlistService     WatchlistService.WatchlistServiceInterface
+	portfolioService     PortfolioService.PortfolioServiceInterface
+	taxService           TaxService.TaxServiceInterface
+	exportService        ExportService.ExportServiceInterface
+	labelService         LabelService.LabelServiceInterface
+	notificationService  NotificationService.NotificationServiceInterface
+	watcherService       NotificationService.WatcherServiceInterface
+	fearGreedService     CoinMarketCapService.FearGreedServiceInterface
+	priceHistoryService  CoinMarketCapService.PriceHistoryServiceInterface
+	fearGreedAnalytics   CoinMarketCapService.FearGreedAnalyticsServiceInterface
+	alertService         AlertService.AlertServiceInterface
+	alertEvaluator       AlertService.AlertEvaluatorServiceInterface
+	realtimeHub          RealtimeService.HubInterface
+	marketFeed           RealtimeService.MarketFeedServiceInterface
 }
 
 // NewServiceContainer creates a new service container with all dependencies initialized
@@ -49,14 +100,29 @@ func NewServiceContainer(cfg *EnvTypes.EnvConfig, db *gorm.DB) *ServiceContainer
 	// Set config globally for backward compatibility
 	config.Set(cfg)
 
+	container.metrics = newMetrics(db)
+
 	// Initialize repositories
 	container.userRepo = UserRepository.NewGormUserRepository(db)
 	container.userTokenRepo = UserRepository.NewGormUserTokenRepository(db)
+	container.watchedRepo = UserRepository.NewGormWatchedWalletRepository(db)
+	container.transferRepo = UserRepository.NewGormTransferTagRepository(db)
+	container.labelRepo = UserRepository.NewGormLabelRepository(db)
+	container.broadcastRepo = UserRepository.NewGormBroadcastAuditRepository(db)
+	container.notifyRepo = UserRepository.NewGormNotificationRepository(db)
+	container.cursorRepo = UserRepository.NewGormWalletCursorRepository(db)
+	container.fearGreedRepo = UserRepository.NewGormFearGreedRepository(db)
+	container.priceRepo = UserRepository.NewGormPriceHistoryRepository(db)
+	container.alertRuleRepo = UserRepository.NewGormAlertRuleRepository(db)
+	container.triggerRepo = UserRepository.NewGormAlertTriggerRepository(db)
 
 	// Initialize services in dependency order
 	container.passwordService = PasswordService.NewPasswordService()
 
-	emailSender := Email.NewSMTPEmailSender(cfg.SMTPConfig.Host, cfg.SMTPConfig.Port)
+	emailSender := metrics.NewInstrumentedEmailSender(
+		Email.NewSMTPEmailSender(cfg.SMTPConfig.Host, cfg.SMTPConfig.Port),
+		container.metrics,
+	)
 	emailCreator := &EmailService.EmailCreatorImpl{}
 	container.emailService = EmailService.NewEmailService(emailSender, emailCreator)
 
@@ -75,12 +141,152 @@ func NewServiceContainer(cfg *EnvTypes.EnvConfig, db *gorm.DB) *ServiceContainer
 	)
 
 	container.twoFactorService = TwoFactorService.NewTwoFactorService()
-	container.coinMarketCapService = CoinMarketCapService.NewCoinMarketCapServiceService(cfg)
-	container.transactionService = WalletExplorerService.NewTransactionService(cfg)
+	container.cache = newResponseCache(cfg, db)
+	container.http = newHTTPClient(cfg, container.metrics)
+	marketData := MarketDataService.NewMarketDataService(MarketDataService.NewProviders(cfg, container.http))
+	container.coinMarketCapService = CoinMarketCapService.NewCachedCoinMarketCapService(marketData, container.cache)
+	container.fxService = FXService.NewFXService(container.coinMarketCapService, container.cache)
+	container.fearGreedService = CoinMarketCapService.NewFearGreedService(cfg, marketData, container.fearGreedRepo)
+	container.priceHistoryService = CoinMarketCapService.NewPriceHistoryService(cfg, marketData, container.priceRepo, container.fearGreedRepo)
+	container.fearGreedAnalytics = CoinMarketCapService.NewFearGreedAnalyticsService(container.fearGreedRepo, container.priceRepo)
+	container.transactionService = WalletExplorerService.NewCachedTransactionService(
+		WalletExplorerService.NewTransactionService(cfg, container.http),
+		container.cache,
+	)
+	container.mempoolService = WalletExplorerService.NewCachedMempoolService(
+		WalletExplorerService.NewMempoolService(WalletExplorerService.NewMempoolProviders(cfg, container.http)),
+		container.cache,
+	)
+	container.broadcastService = WalletExplorerService.NewBroadcastService(
+		WalletExplorerService.NewBroadcasters(cfg, container.http),
+		container.mempoolService,
+		container.broadcastRepo,
+	)
+	container.evmService = EVMService.NewEVMService(cfg, container.http)
+	container.descriptorService = WalletExplorerService.NewDescriptorService()
+	container.decoderService = WalletExplorerService.NewDecoderService()
+	container.watchlistService = WatchlistService.NewWatchlistService(
+		container.watchedRepo,
+		container.transactionService,
+	)
+	container.portfolioService = PortfolioService.NewPortfolioService(
+		container.watchlistService,
+		container.coinMarketCapService,
+	)
+	container.taxService = TaxService.NewTaxService(
+		container.transferRepo,
+		container.watchlistService,
+		container.coinMarketCapService,
+	)
+	container.exportService = ExportService.NewExportService(
+		container.transactionService,
+		container.watchlistService,
+		container.coinMarketCapService,
+	)
+	container.labelService = LabelService.NewLabelService(container.labelRepo)
+	container.notificationService = NotificationService.NewNotificationService(container.notifyRepo)
+	hub := RealtimeService.NewHub(cfg)
+	container.realtimeHub = hub
+	container.marketFeed = RealtimeService.NewMarketFeedService(cfg, hub, container.coinMarketCapService)
+	watcher := NotificationService.NewWatcherService(
+		cfg,
+		container.watchedRepo,
+		container.cursorRepo,
+		container.notifyRepo,
+		container.userRepo,
+		container.watchlistService,
+		container.transactionService,
+		container.emailService,
+	)
+	watcher.SetPublisher(hub)
+	container.watcherService = watcher
+	container.alertService = AlertService.NewAlertService(cfg, container.alertRuleRepo, container.triggerRepo)
+	evaluator := AlertService.NewAlertEvaluatorService(
+		cfg,
+		container.alertRuleRepo,
+		container.triggerRepo,
+		container.userRepo,
+		container.coinMarketCapService,
+		container.emailService,
+	)
+	evaluator.SetPublisher(hub)
+	container.alertEvaluator = evaluator
 
 	return container
 }
 
+// newResponseCache builds the upstream response cache selected by the configuration, or nil
+// when caching is disabled
+func newResponseCache(cfg *EnvTypes.EnvConfig, db *gorm.DB) *cache.Cache {
+	switch cfg.CacheConfig.Backend {
+	case "none":
+		return nil
+	case "sql":
+		return cache.New(cache.NewSQLStore(db))
+	default:
+		return cache.New(cache.NewMemoryStore(cfg.CacheConfig.Size))
+	}
+}
+
+// newMetrics creates the application metrics, exposing the connection pool of db
+func newMetrics(db *gorm.DB) *metrics.Metrics {
+	m := metrics.New()
+	if db != nil {
+		m.ObserveDatabase(&database.Database{DB: db})
+	}
+	return m
+}
+
+// newHTTPClient builds the outbound HTTP client shared by the upstream services. The block
+// explorers serve large address pages and get a longer timeout than the market data APIs.
+// Every attempt is recorded in m under the name of its provider.
+func newHTTPClient(cfg *EnvTypes.EnvConfig, m *metrics.Metrics) *httpclient.Client {
+	clientCfg := httpclient.DefaultConfig()
+	clientCfg.Default.MaxRetries = cfg.HTTPClientConfig.MaxRetries
+	clientCfg.Default.BreakerThreshold = cfg.HTTPClientConfig.BreakerThreshold
+	clientCfg.Default.BreakerCooldown = time.Duration(cfg.HTTPClientConfig.BreakerCooldown) * time.Second
+
+	timeouts := map[string]time.Duration{
+		cfg.BlockchainConfig.API:              30 * time.Second,
+		cfg.WalletExplorerConfig.API:          30 * time.Second,
+		cfg.MempoolConfig.API:                 15 * time.Second,
+		cfg.CoinMarketCapConfig.API:           10 * time.Second,
+		cfg.MarketDataConfig.CoinGeckoAPI:     10 * time.Second,
+		cfg.MarketDataConfig.AlternativeMeAPI: 10 * time.Second,
+	}
+	providers := map[string]string{
+		cfg.BlockchainConfig.API:              "blockchain",
+		cfg.WalletExplorerConfig.API:          "wallet_explorer",
+		cfg.MempoolConfig.API:                 "mempool",
+		cfg.CoinMarketCapConfig.API:           "coinmarketcap",
+		cfg.MarketDataConfig.CoinGeckoAPI:     "coingecko",
+		cfg.MarketDataConfig.AlternativeMeAPI: "alternative",
+	}
+	for chain, node := range cfg.EVMConfig.Nodes {
+		timeouts[node] = 15 * time.Second
+		providers[node] = "evm_" + chain
+	}
+	for api, timeout := range timeouts {
+		u, err := url.Parse(api)
+		if err != nil || u.Host == "" {
+			continue
+		}
+		host := clientCfg.Default
+		host.Timeout = timeout
+		clientCfg.Hosts[u.Host] = host
+	}
+
+	providerHosts := make(map[string]string, len(providers))
+	for api, provider := range providers {
+		if u, err := url.Parse(api); err == nil && u.Host != "" {
+			providerHosts[u.Host] = provider
+		}
+	}
+	clientCfg.Transport = metrics.NewInstrumentedTransport(nil, m, providerHosts)
+
+	return httpclient.New(clientCfg)
+}
+
 // GetDB returns the database connection
 func (c *ServiceContainer) GetDB() *gorm.DB {
 	return c.db
@@ -101,6 +307,71 @@ func (c *ServiceContainer) GetUserTokenRepository() UserRepository.UserTokenRepo
 	return c.userTokenRepo
 }
 
+// GetWatchedWalletRepository returns the watched wallet repository
+func (c *ServiceContainer) GetWatchedWalletRepository() UserRepository.WatchedWalletRepository {
+	return c.watchedRepo
+}
+
+// GetCache returns the upstream response cache, nil when caching is disabled
+func (c *ServiceContainer) GetCache() *cache.Cache {
+	return c.cache
+}
+
+// GetMetrics returns the application metrics
+func (c *ServiceContainer) GetMetrics() *metrics.Metrics {
+	return c.metrics
+}
+
+// GetHTTPClient returns the outbound HTTP client shared by the upstream services
+func (c *ServiceContainer) GetHTTPClient() *httpclient.Client {
+	return c.http
+}
+
+// GetTransferTagRepository returns the transfer tag repository
+func (c *ServiceContainer) GetTransferTagRepository() UserRepository.TransferTagRepository {
+	return c.transferRepo
+}
+
+// GetLabelRepository returns the BIP-329 label repository
+func (c *ServiceContainer) GetLabelRepository() UserRepository.LabelRepository {
+	return c.labelRepo
+}
+
+// GetBroadcastAuditRepository returns the transaction broadcast audit repository
+func (c *ServiceContainer) GetBroadcastAuditRepository() UserRepository.BroadcastAuditRepository {
+	return c.broadcastRepo
+}
+
+// GetNotificationRepository returns the in-app notification feed repository
+func (c *ServiceContainer) GetNotificationRepository() UserRepository.NotificationRepository {
+	return c.notifyRepo
+}
+
+// GetWalletCursorRepository returns the repository of the watchlist watcher's wallet cursors
+func (c *ServiceContainer) GetWalletCursorRepository() UserRepository.WalletCursorRepository {
+	return c.cursorRepo
+}
+
+// GetFearGreedRepository returns the repository of the local fear and greed history
+func (c *ServiceContainer) GetFearGreedRepository() UserRepository.FearGreedRepository {
+	return c.fearGreedRepo
+}
+
+// GetPriceHistoryRepository returns the repository of the local BTC price history
+func (c *ServiceContainer) GetPriceHistoryRepository() UserRepository.PriceHistoryRepository {
+	return c.priceRepo
+}
+
+// GetAlertRuleRepository returns the repository of the users' alert rules
+func (c *ServiceContainer) GetAlertRuleRepository() UserRepository.AlertRuleRepository {
+	return c.alertRuleRepo
+}
+
+// GetAlertTriggerRepository returns the repository of the history of triggered alerts
+func (c *ServiceContainer) GetAlertTriggerRepository() UserRepository.AlertTriggerRepository {
+	return c.triggerRepo
+}
+
 // GetPasswordService returns the password service
 func (c *ServiceContainer) GetPasswordService() PasswordService.PasswordServiceInterface {
 	return c.passwordService
@@ -136,7 +407,107 @@ func (c *ServiceContainer) GetCoinMarketCapService() CoinMarketCapService.CoinMa
 	return c.coinMarketCapService
 }
 
+// GetFXService returns the fiat currency conversion service
+func (c *ServiceContainer) GetFXService() FXService.FXServiceInterface {
+	return c.fxService
+}
+
 // GetTransactionService returns the transaction/wallet explorer service
 func (c *ServiceContainer) GetTransactionService() WalletExplorerService.TransactionServiceInterface {
 	return c.transactionService
 }
+
+// GetDescriptorService returns the descriptor/extended key service
+func (c *ServiceContainer) GetDescriptorService() WalletExplorerService.DescriptorServiceInterface {
+	return c.descriptorService
+}
+
+// GetDecoderService returns the raw transaction/PSBT decoder service
+func (c *ServiceContainer) GetDecoderService() WalletExplorerService.DecoderServiceInterface {
+	return c.decoderService
+}
+
+// GetMempoolService returns the mempool, fee estimation and chain tip service
+func (c *ServiceContainer) GetMempoolService() WalletExplorerService.MempoolServiceInterface {
+	return c.mempoolService
+}
+
+// GetBroadcastService returns the signed transaction broadcast service
+func (c *ServiceContainer) GetBroadcastService() WalletExplorerService.BroadcastServiceInterface {
+	return c.broadcastService
+}
+
+// GetEVMService returns the EVM chain explorer service
+func (c *ServiceContainer) GetEVMService() EVMService.EVMServiceInterface {
+	return c.evmService
+}
+
+// GetWatchlistService returns the user watchlist service
+func (c *ServiceContainer) GetWatchlistService() WatchlistService.WatchlistServiceInterface {
+	return c.watchlistService
+}
+
+// GetPortfolioService returns the portfolio valuation service
+func (c *ServiceContainer) GetPortfolioService() PortfolioService.PortfolioServiceInterface {
+	return c.portfolioService
+}
+
+// GetTaxService returns the capital-gains report service
+func (c *ServiceContainer) GetTaxService() TaxService.TaxServiceInterface {
+	return c.taxService
+}
+
+// GetExportService returns the transaction history export service
+func (c *ServiceContainer) GetExportService() ExportService.ExportServiceInterface {
+	return c.exportService
+}
+
+// GetLabelService returns the BIP-329 label service
+func (c *ServiceContainer) GetLabelService() LabelService.LabelServiceInterface {
+	return c.labelService
+}
+
+// GetNotificationService returns the in-app notification feed service
+func (c *ServiceContainer) GetNotificationService() NotificationService.NotificationServiceInterface {
+	return c.notificationService
+}
+
+// GetWatcherService returns the job notifying users about activity on their watched wallets
+func (c *ServiceContainer) GetWatcherService() NotificationService.WatcherServiceInterface {
+	return c.watcherService
+}
+
+// GetFearGreedService returns the local fear and greed history service and its sync job
+func (c *ServiceContainer) GetFearGreedService() CoinMarketCapService.FearGreedServiceInterface {
+	return c.fearGreedService
+}
+
+// GetPriceHistoryService returns the local BTC price history service and its sync job
+func (c *ServiceContainer) GetPriceHistoryService() CoinMarketCapService.PriceHistoryServiceInterface {
+	return c.priceHistoryService
+}
+
+// GetFearGreedAnalyticsService returns the service relating the fear and greed index to the BTC price
+func (c *ServiceContainer) GetFearGreedAnalyticsService() CoinMarketCapService.FearGreedAnalyticsServiceInterface {
+	return c.fearGreedAnalytics
+}
+
+// GetAlertService returns the service managing the users' alert rules and their history
+func (c *ServiceContainer) GetAlertService() AlertService.AlertServiceInterface {
+	return c.alertService
+}
+
+// GetAlertEvaluatorService returns the job evaluating the alert rules against the market data
+func (c *ServiceContainer) GetAlertEvaluatorService() AlertService.AlertEvaluatorServiceInterface {
+	return c.alertEvaluator
+}
+
+// GetRealtimeHub returns the hub fanning events out to the realtime connections
+func (c *ServiceContainer) GetRealtimeHub() RealtimeService.HubInterface {
+	return c.realtimeHub
+}
+
+// GetMarketFeedService returns the job publishing the subscribed market data to the realtime hub
+func (c *ServiceContainer) GetMarketFeedService() RealtimeService.MarketFeedServiceInterface {
+	return c.marketFeed
+}
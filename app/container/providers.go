@@ -4,14 +4,26 @@ package container
 import (
 	"cry-api/app/config"
 	Email "cry-api/app/email"
+	"cry-api/app/metrics"
 	UserRepository "cry-api/app/repositories"
 	TwoFactorService "cry-api/app/services/2fa"
+	AlertService "cry-api/app/services/alert"
 	AuthService "cry-api/app/services/auth"
 	PasswordService "cry-api/app/services/auth/password"
 	CoinMarketCapService "cry-api/app/services/coin_market_cap"
 	EmailService "cry-api/app/services/email"
+	EVMService "cry-api/app/services/evm"
+	ExportService "cry-api/app/services/export"
+	FXService "cry-api/app/services/fx"
+	LabelService "cry-api/app/services/label"
+	MarketDataService "cry-api/app/services/market_data"
+	NotificationService "cry-api/app/services/notification"
+	PortfolioService "cry-api/app/services/portfolio"
+	RealtimeService "cry-api/app/services/realtime"
+	TaxService "cry-api/app/services/tax"
 	UserService "cry-api/app/services/users"
 	WalletExplorerService "cry-api/app/services/wallet_explorer"
+	WatchlistService "cry-api/app/services/watchlist"
 	EnvTypes "cry-api/app/types/env"
 
 	"gorm.io/gorm"
@@ -30,6 +42,16 @@ func (p *UserServiceProvider) Register(c *ServiceContainer) {
 	c.userRepo = UserRepository.NewGormUserRepository(c.db)
 	c.userTokenRepo = UserRepository.NewGormUserTokenRepository(c.db)
 	c.userTokenService = UserService.NewUserTokenService(c.userTokenRepo)
+	c.watchedRepo = UserRepository.NewGormWatchedWalletRepository(c.db)
+	c.transferRepo = UserRepository.NewGormTransferTagRepository(c.db)
+	c.labelRepo = UserRepository.NewGormLabelRepository(c.db)
+	c.broadcastRepo = UserRepository.NewGormBroadcastAuditRepository(c.db)
+	c.notifyRepo = UserRepository.NewGormNotificationRepository(c.db)
+	c.cursorRepo = UserRepository.NewGormWalletCursorRepository(c.db)
+	c.fearGreedRepo = UserRepository.NewGormFearGreedRepository(c.db)
+	c.priceRepo = UserRepository.NewGormPriceHistoryRepository(c.db)
+	c.alertRuleRepo = UserRepository.NewGormAlertRuleRepository(c.db)
+	c.triggerRepo = UserRepository.NewGormAlertTriggerRepository(c.db)
 }
 
 // AuthServiceProvider registers authentication-related services
@@ -49,7 +71,10 @@ type EmailServiceProvider struct{}
 
 // Register initializes email sender and creator services
 func (p *EmailServiceProvider) Register(c *ServiceContainer) {
-	emailSender := Email.NewSMTPEmailSender(c.config.SMTPConfig.Host, c.config.SMTPConfig.Port)
+	emailSender := metrics.NewInstrumentedEmailSender(
+		Email.NewSMTPEmailSender(c.config.SMTPConfig.Host, c.config.SMTPConfig.Port),
+		c.metrics,
+	)
 	emailCreator := &EmailService.EmailCreatorImpl{}
 	c.emailService = EmailService.NewEmailService(emailSender, emailCreator)
 }
@@ -78,10 +103,99 @@ func (p *TwoFactorServiceProvider) Register(c *ServiceContainer) {
 // ExternalAPIServiceProvider registers external API services
 type ExternalAPIServiceProvider struct{}
 
-// Register initializes external API services (CoinMarketCap and Wallet Explorer)
+// Register initializes external API services (market data providers and the FX rates built on them, Wallet Explorer, mempool and EVM nodes) on the shared HTTP client, behind the response cache.
+// The fear and greed and price history syncs read the market data providers directly, past the cache.
 func (p *ExternalAPIServiceProvider) Register(c *ServiceContainer) {
-	c.coinMarketCapService = CoinMarketCapService.NewCoinMarketCapServiceService(c.config)
-	c.transactionService = WalletExplorerService.NewTransactionService(c.config)
+	c.cache = newResponseCache(c.config, c.db)
+	c.http = newHTTPClient(c.config, c.metrics)
+	marketData := MarketDataService.NewMarketDataService(MarketDataService.NewProviders(c.config, c.http))
+	c.coinMarketCapService = CoinMarketCapService.NewCachedCoinMarketCapService(marketData, c.cache)
+	c.fxService = FXService.NewFXService(c.coinMarketCapService, c.cache)
+	c.fearGreedService = CoinMarketCapService.NewFearGreedService(c.config, marketData, c.fearGreedRepo)
+	c.priceHistoryService = CoinMarketCapService.NewPriceHistoryService(c.config, marketData, c.priceRepo, c.fearGreedRepo)
+	c.fearGreedAnalytics = CoinMarketCapService.NewFearGreedAnalyticsService(c.fearGreedRepo, c.priceRepo)
+	c.transactionService = WalletExplorerService.NewCachedTransactionService(
+		WalletExplorerService.NewTransactionService(c.config, c.http),
+		c.cache,
+	)
+	c.mempoolService = WalletExplorerService.NewCachedMempoolService(
+		WalletExplorerService.NewMempoolService(WalletExplorerService.NewMempoolProviders(c.config, c.http)),
+		c.cache,
+	)
+	c.broadcastService = WalletExplorerService.NewBroadcastService(
+		WalletExplorerService.NewBroadcasters(c.config, c.http),
+		c.mempoolService,
+		c.broadcastRepo,
+	)
+	c.evmService = EVMService.NewEVMService(c.config, c.http)
+	c.descriptorService = WalletExplorerService.NewDescriptorService()
+	c.decoderService = WalletExplorerService.NewDecoderService()
+}
+
+// WatchlistServiceProvider registers the user watchlist, portfolio, tax report and export services
+type WatchlistServiceProvider struct{}
+
+// Register initializes the watchlist, portfolio, tax report and export services on top of the external API services
+func (p *WatchlistServiceProvider) Register(c *ServiceContainer) {
+	c.watchlistService = WatchlistService.NewWatchlistService(c.watchedRepo, c.transactionService)
+	c.portfolioService = PortfolioService.NewPortfolioService(c.watchlistService, c.coinMarketCapService)
+	c.taxService = TaxService.NewTaxService(c.transferRepo, c.watchlistService, c.coinMarketCapService)
+	c.exportService = ExportService.NewExportService(c.transactionService, c.watchlistService, c.coinMarketCapService)
+}
+
+// LabelServiceProvider registers the BIP-329 label service
+type LabelServiceProvider struct{}
+
+// Register initializes the label service
+func (p *LabelServiceProvider) Register(c *ServiceContainer) {
+	c.labelService = LabelService.NewLabelService(c.labelRepo)
+}
+
+// RealtimeServiceProvider registers the realtime hub and the market data feed publishing to it
+type RealtimeServiceProvider struct{}
+
+// Register initializes the realtime hub and the market data feed on top of the external API services
+func (p *RealtimeServiceProvider) Register(c *ServiceContainer) {
+	c.realtimeHub = RealtimeService.NewHub(c.config)
+	c.marketFeed = RealtimeService.NewMarketFeedService(c.config, c.realtimeHub, c.coinMarketCapService)
+}
+
+// NotificationServiceProvider registers the notification feed and the watched wallet watcher
+type NotificationServiceProvider struct{}
+
+// Register initializes the notification feed and the watcher on top of the watchlist, email and realtime services
+func (p *NotificationServiceProvider) Register(c *ServiceContainer) {
+	c.notificationService = NotificationService.NewNotificationService(c.notifyRepo)
+	watcher := NotificationService.NewWatcherService(
+		c.config,
+		c.watchedRepo,
+		c.cursorRepo,
+		c.notifyRepo,
+		c.userRepo,
+		c.watchlistService,
+		c.transactionService,
+		c.emailService,
+	)
+	watcher.SetPublisher(c.realtimeHub)
+	c.watcherService = watcher
+}
+
+// AlertServiceProvider registers the market data alerts and their evaluator
+type AlertServiceProvider struct{}
+
+// Register initializes the alert rules service and the evaluator on top of the market data, email and realtime services
+func (p *AlertServiceProvider) Register(c *ServiceContainer) {
+	c.alertService = AlertService.NewAlertService(c.config, c.alertRuleRepo, c.triggerRepo)
+	evaluator := AlertService.NewAlertEvaluatorService(
+		c.config,
+		c.alertRuleRepo,
+		c.triggerRepo,
+		c.userRepo,
+		c.coinMarketCapService,
+		c.emailService,
+	)
+	evaluator.SetPublisher(c.realtimeHub)
+	c.alertEvaluator = evaluator
 }
 
 // registerAllProviders registers all service providers in the correct order
@@ -93,6 +207,11 @@ func registerAllProviders(container *ServiceContainer) {
 		&UserBusinessServiceProvider{},
 		&TwoFactorServiceProvider{},
 		&ExternalAPIServiceProvider{},
+		&WatchlistServiceProvider{},
+		&LabelServiceProvider{},
+		&RealtimeServiceProvider{},
+		&NotificationServiceProvider{},
+		&AlertServiceProvider{},
 	}
 
 	for _, provider := range providers {
@@ -111,6 +230,8 @@ func NewServiceContainerWithProviders(cfg *EnvTypes.EnvConfig, db *gorm.DB) *Ser
 	// Set config globally for backward compatibility
 	config.Set(cfg)
 
+	container.metrics = newMetrics(db)
+
 	// Register all services using providers
 	registerAllProviders(container)
 
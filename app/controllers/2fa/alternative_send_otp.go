@@ -3,14 +3,16 @@
 package controllers
 
 import (
-	"log"
 	"net/http"
 	"time"
 
 	"cry-api/app/config"
 	"cry-api/app/factories"
+	"cry-api/app/logger"
+	"cry-api/app/middleware"
 	UserModel "cry-api/app/models"
 	TwoFactorTypes "cry-api/app/types/2fa"
+	app_errors "cry-api/app/types/errors"
 	TokenTypes "cry-api/app/types/token_purpose"
 
 	"github.com/gin-gonic/gin"
@@ -24,25 +26,25 @@ func (h *TwoFactorController) AlternativeSendOtp(c *gin.Context) {
 
 	// 1️⃣ Parse request
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		middleware.AbortWithError(c, app_errors.NewBindingError(err, &req))
 		return
 	}
 
 	// 2️⃣ Find user
 	user, err := h.UserService.FindUserByEmail(req.Email)
 	if err != nil || user == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		middleware.AbortWithError(c, app_errors.NewNotFoundError("User", "User not found"))
 		return
 	}
 	if !user.IsVerified {
-		c.JSON(http.StatusForbidden, gin.H{"error": "User not verified"})
+		middleware.AbortWithError(c, app_errors.NewCodedError(http.StatusForbidden, app_errors.CodeAuthUserNotVerified, "User not verified", ""))
 		return
 	}
 
 	// 3️⃣ Check if an unexpired OTP already exists
 	existingToken, err := h.UserTokenService.FindLatestValidToken(user.ID, string(TokenTypes.TwoFactorAuthAlternativeOTP))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		middleware.AbortWithError(c, app_errors.NewInternalServerError("Internal server error"))
 		return
 	}
 
@@ -50,17 +52,18 @@ func (h *TwoFactorController) AlternativeSendOtp(c *gin.Context) {
 	if existingToken == nil {
 		otpToken, err := factories.NewUserToken(user.ID, string(TokenTypes.TwoFactorAuthAlternativeOTP), 5*time.Minute, factories.OTP)
 		if err != nil {
-			log.Printf("[AlternativeSendOtp] could not generate OTP: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate OTP"})
+			logger.FromContext(c.Request.Context()).WithError(err).Error("Could not generate alternative OTP")
+			middleware.AbortWithError(c, app_errors.NewInternalServerError("Could not generate OTP"))
 			return
 		}
 
 		if err := h.UserTokenService.Save(otpToken); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not save OTP"})
+			middleware.AbortWithError(c, app_errors.NewInternalServerError("Could not save OTP"))
 			return
 		}
 
 		// 5️⃣ Send OTP asynchronously
+		log := logger.FromContext(c.Request.Context())
 		go func(u *UserModel.User, token string) {
 			cfg := config.Get()
 			if err := h.EmailService.SendTwoFactorAlternativeEmail(
@@ -70,11 +73,11 @@ func (h *TwoFactorController) AlternativeSendOtp(c *gin.Context) {
 				token,
 				5,
 			); err != nil {
-				log.Printf("[AlternativeSendOtp] failed to send email to %s: %v", u.Email, err)
+				log.WithError(err).WithField("user_uuid", u.UUID).Error("Failed to send alternative OTP email")
 			}
 		}(user, otpToken.Token)
 	} else {
-		log.Printf("[AlternativeSendOtp] existing valid OTP found for user: %s", req.Email)
+		logger.FromContext(c.Request.Context()).WithField("user_uuid", user.UUID).Info("Existing valid alternative OTP found")
 	}
 
 	c.JSON(http.StatusOK, gin.H{"success": true, "message": "OTP sent successfully"})
@@ -5,8 +5,10 @@ package controllers
 import (
 	"net/http"
 
+	"cry-api/app/middleware"
 	JWT "cry-api/app/services/jwt"
 	Types "cry-api/app/types/2fa"
+	app_errors "cry-api/app/types/errors"
 	TokenType "cry-api/app/types/token_purpose"
 
 	"github.com/gin-gonic/gin"
@@ -16,63 +18,63 @@ import (
 func (h *TwoFactorController) AlternativeVerifyOTP(c *gin.Context) {
 	var req Types.ITwoFactorVerifyRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		middleware.AbortWithError(c, app_errors.NewBindingError(err, &req))
 		return
 	}
 
 	if req.UserUUID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User UUID is required"})
+		middleware.AbortWithError(c, app_errors.NewValidationError("userUUID", "", "User UUID is required"))
 		return
 	}
 
 	if req.OTP == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "OTP is required for verification"})
+		middleware.AbortWithError(c, app_errors.NewValidationError("otp", "", "OTP is required for verification"))
 		return
 	}
 
 	// Fetch user
 	user, err := h.UserService.GetUserByUUID(req.UserUUID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		middleware.AbortWithError(c, app_errors.NewInternalServerError("Failed to get user"))
 		return
 	}
 	if user == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		middleware.AbortWithError(c, app_errors.NewNotFoundError("User", "User not found"))
 		return
 	}
 
 	if !user.TwoFAEnabled {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User has not enabled 2FA"})
+		middleware.AbortWithError(c, app_errors.NewCodedError(http.StatusBadRequest, app_errors.CodeTwoFactorNotEnabled, "User has not enabled 2FA", ""))
 		return
 	}
 
 	// Verify OTP
 	existingToken, err := h.UserTokenService.FindLatestValidToken(user.ID, string(TokenType.TwoFactorAuthAlternativeOTP))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal error"})
+		middleware.AbortWithError(c, app_errors.NewInternalServerError("Internal server error"))
 		return
 	}
 
 	if existingToken == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired OTP"})
+		middleware.AbortWithError(c, app_errors.NewCodedError(http.StatusUnauthorized, app_errors.CodeOTPExpired, "Invalid or expired OTP", ""))
 		return
 	}
 
 	if existingToken.Token != req.OTP {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired OTP"})
+		middleware.AbortWithError(c, app_errors.NewCodedError(http.StatusUnauthorized, app_errors.CodeOTPInvalid, "Invalid or expired OTP", ""))
 		return
 	}
 
 	// Consume the token to prevent reuse
 	if err := h.UserTokenService.ConsumeToken(user.ID, req.OTP, string(TokenType.TwoFactorAuthAlternativeOTP)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to consume OTP token"})
+		middleware.AbortWithError(c, app_errors.NewInternalServerError("Failed to consume OTP token"))
 		return
 	}
 
 	// Generate JWT
 	newJWT, err := JWT.GenerateJWT(user.UUID, user.Email, user.TwoFAEnabled, true)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate JWT"})
+		middleware.AbortWithError(c, app_errors.ErrTokenGeneration)
 		return
 	}
 
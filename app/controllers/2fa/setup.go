@@ -5,7 +5,9 @@ package controllers
 import (
 	"net/http"
 
+	"cry-api/app/middleware"
 	TwoFactorTypes "cry-api/app/types/2fa"
+	app_errors "cry-api/app/types/errors"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,18 +17,18 @@ func (h *TwoFactorController) Setup(c *gin.Context) {
 	var req TwoFactorTypes.ITwoFactorSetupRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		middleware.AbortWithError(c, app_errors.NewBindingError(err, &req))
 		return
 	}
 
 	if req.UserUUID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User UUID is required"})
+		middleware.AbortWithError(c, app_errors.NewValidationError("uuid", "", "User UUID is required"))
 		return
 	}
 
 	user, err := h.UserService.GetUserByUUID(req.UserUUID)
 	if err != nil || user == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		middleware.AbortWithError(c, app_errors.NewNotFoundError("User", "User not found"))
 		return
 	}
 
@@ -36,7 +38,7 @@ func (h *TwoFactorController) Setup(c *gin.Context) {
 
 		qrCode, err := h.TwoFactorService.GenerateQRCodeBase64(otpauthURL)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate QR code"})
+			middleware.AbortWithError(c, app_errors.NewInternalServerError("Failed to generate QR code"))
 			return
 		}
 
@@ -50,19 +52,19 @@ func (h *TwoFactorController) Setup(c *gin.Context) {
 	// Generate new secret
 	secret, otpauthURL, err := h.TwoFactorService.GenerateTOTP(user.Email)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate 2FA secret"})
+		middleware.AbortWithError(c, app_errors.NewInternalServerError("Failed to generate 2FA secret"))
 		return
 	}
 
 	user.TwoFASecret = &secret
 	if err := h.UserService.UpdateUser(user); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save 2FA secret"})
+		middleware.AbortWithError(c, app_errors.NewInternalServerError("Failed to save 2FA secret"))
 		return
 	}
 
 	qrCode, err := h.TwoFactorService.GenerateQRCodeBase64(otpauthURL)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate QR code"})
+		middleware.AbortWithError(c, app_errors.NewInternalServerError("Failed to generate QR code"))
 		return
 	}
 
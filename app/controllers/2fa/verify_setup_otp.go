@@ -5,8 +5,10 @@ package controllers
 import (
 	"net/http"
 
+	"cry-api/app/middleware"
 	JWT "cry-api/app/services/jwt"
 	TwoFactorTypes "cry-api/app/types/2fa"
+	app_errors "cry-api/app/types/errors"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,40 +17,40 @@ import (
 func (h *TwoFactorController) VerifySetUpOTP(c *gin.Context) {
 	var req TwoFactorTypes.ITwoFactorSetupRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		middleware.AbortWithError(c, app_errors.NewBindingError(err, &req))
 		return
 	}
 
 	if req.UserUUID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User UUID is required"})
+		middleware.AbortWithError(c, app_errors.NewValidationError("uuid", "", "User UUID is required"))
 		return
 	}
 
 	if req.OTP == nil || *req.OTP == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "OTP is required for verification"})
+		middleware.AbortWithError(c, app_errors.NewValidationError("otp", "", "OTP is required for verification"))
 		return
 	}
 
 	if req.Secret == nil || *req.Secret == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "TOTP secret is required"})
+		middleware.AbortWithError(c, app_errors.NewValidationError("secret", "", "TOTP secret is required"))
 		return
 	}
 
 	// Verify OTP using provided secret
 	isValid, err := h.AuthService.VerifyOTP(*req.Secret, *req.OTP)
 	if err != nil || !isValid {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid OTP"})
+		middleware.AbortWithError(c, app_errors.NewCodedError(http.StatusUnauthorized, app_errors.CodeOTPInvalid, "Invalid OTP", ""))
 		return
 	}
 
 	// Fetch user
 	user, err := h.UserService.GetUserByUUID(req.UserUUID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		middleware.AbortWithError(c, app_errors.NewInternalServerError("Failed to get user"))
 		return
 	}
 	if user == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		middleware.AbortWithError(c, app_errors.NewNotFoundError("User", "User not found"))
 		return
 	}
 
@@ -56,7 +58,7 @@ func (h *TwoFactorController) VerifySetUpOTP(c *gin.Context) {
 	if !user.TwoFAEnabled {
 		user.TwoFAEnabled = true
 		if err := h.UserService.UpdateUser(user); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enable 2FA"})
+			middleware.AbortWithError(c, app_errors.NewInternalServerError("Failed to enable 2FA"))
 			return
 		}
 	}
@@ -64,7 +66,7 @@ func (h *TwoFactorController) VerifySetUpOTP(c *gin.Context) {
 	// Generate JWT with 2FA verified after successful setup
 	jwt, err := JWT.GenerateJWT(user.UUID, user.Email, user.TwoFAEnabled, true)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate JWT"})
+		middleware.AbortWithError(c, app_errors.ErrTokenGeneration)
 		return
 	}
 
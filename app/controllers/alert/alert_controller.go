@@ -0,0 +1,26 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"cry-api/app/container"
+	AlertService "cry-api/app/services/alert"
+	FXService "cry-api/app/services/fx"
+	UserService "cry-api/app/services/users"
+)
+
+// AlertController handles the authenticated user's market data alerts.
+type AlertController struct {
+	UserService  UserService.UserServiceInterface
+	AlertService AlertService.AlertServiceInterface
+	FXService    FXService.FXServiceInterface
+}
+
+// NewAlertController initializes a new AlertController with dependencies from the container.
+func NewAlertController(container *container.Container) *AlertController {
+	return &AlertController{
+		UserService:  container.GetUserService(),
+		AlertService: container.GetAlertService(),
+		FXService:    container.GetFXService(),
+	}
+}
@@ -0,0 +1,172 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"cry-api/app/logger"
+	"cry-api/app/middleware"
+	AlertTypes "cry-api/app/types/alert"
+	app_errors "cry-api/app/types/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListRules returns every alert rule of the user.
+func (h *AlertController) ListRules(c *gin.Context) {
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	rules, err := h.AlertService.ListRules(user.ID)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	h.convertRules(c.Request.Context(), user, rules)
+	c.JSON(http.StatusOK, gin.H{"alerts": rules})
+}
+
+// CreateRule creates an alert rule on a price, a percent change or the fear and greed index.
+func (h *AlertController) CreateRule(c *gin.Context) {
+	logger := logger.FromContext(c.Request.Context())
+
+	var input AlertTypes.ICreateAlertRuleRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.WithError(err).Warn("Alert rule validation failed")
+		middleware.AbortWithError(c, app_errors.NewBindingError(err, &input))
+		return
+	}
+
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	rule, err := h.AlertService.CreateRule(user.ID, input)
+	if err != nil {
+		logger.WithError(err).WithField("user_uuid", user.UUID).Warn("Failed to create alert rule")
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	h.convertRule(c.Request.Context(), user, rule)
+	c.JSON(http.StatusCreated, gin.H{"alert": rule})
+}
+
+// GetRule returns a single alert rule.
+func (h *AlertController) GetRule(c *gin.Context) {
+	id, ok := ruleID(c)
+	if !ok {
+		return
+	}
+
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	rule, err := h.AlertService.GetRule(user.ID, id)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	h.convertRule(c.Request.Context(), user, rule)
+	c.JSON(http.StatusOK, gin.H{"alert": rule})
+}
+
+// UpdateRule changes the settings of an alert rule, or pauses and resumes it.
+func (h *AlertController) UpdateRule(c *gin.Context) {
+	logger := logger.FromContext(c.Request.Context())
+
+	id, ok := ruleID(c)
+	if !ok {
+		return
+	}
+
+	var input AlertTypes.IUpdateAlertRuleRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.WithError(err).Warn("Alert rule update validation failed")
+		middleware.AbortWithError(c, app_errors.NewBindingError(err, &input))
+		return
+	}
+
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	rule, err := h.AlertService.UpdateRule(user.ID, id, input)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	h.convertRule(c.Request.Context(), user, rule)
+	c.JSON(http.StatusOK, gin.H{"alert": rule})
+}
+
+// DeleteRule removes an alert rule. Its past triggers stay in the history.
+func (h *AlertController) DeleteRule(c *gin.Context) {
+	id, ok := ruleID(c)
+	if !ok {
+		return
+	}
+
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	if err := h.AlertService.DeleteRule(user.ID, id); err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Alert rule removed",
+	})
+}
+
+// ListTriggers returns the latest triggers of the user's alerts, or of one rule when the
+// route has an :id.
+func (h *AlertController) ListTriggers(c *gin.Context) {
+	id := 0
+	if c.Param("id") != "" {
+		var ok bool
+		if id, ok = ruleID(c); !ok {
+			return
+		}
+	}
+
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	triggers, err := h.AlertService.ListTriggers(user.ID, id)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	h.convertTriggers(c.Request.Context(), user, triggers)
+	c.JSON(http.StatusOK, gin.H{"triggers": triggers})
+}
+
+// ruleID parses the :id path parameter, aborting the request when it is invalid.
+func ruleID(c *gin.Context) (int, bool) {
+	raw := c.Param("id")
+	id, err := strconv.Atoi(raw)
+	if err != nil || id <= 0 {
+		middleware.AbortWithError(c, app_errors.NewValidationError("id", raw, "Invalid alert rule id"))
+		return 0, false
+	}
+	return id, true
+}
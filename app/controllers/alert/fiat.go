@@ -0,0 +1,55 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"context"
+
+	"cry-api/app/logger"
+	UserModel "cry-api/app/models"
+	"cry-api/app/money"
+	FXService "cry-api/app/services/fx"
+)
+
+// convertRules fills in the threshold of the price rules in the user's fiat currency. A rate
+// that cannot be fetched leaves the rules unconverted rather than failing the request.
+func (h *AlertController) convertRules(ctx context.Context, user *UserModel.User, rules []UserModel.AlertRule) {
+	for i := range rules {
+		h.convertRule(ctx, user, &rules[i])
+	}
+}
+
+// convertRule fills in the threshold of a price rule in the user's fiat currency
+func (h *AlertController) convertRule(ctx context.Context, user *UserModel.User, rule *UserModel.AlertRule) {
+	if rule.Condition == UserModel.AlertConditionPriceAbove || rule.Condition == UserModel.AlertConditionPriceBelow {
+		rule.Fiat = h.convert(ctx, user, rule.Threshold, rule.Currency)
+	}
+}
+
+// convertTriggers fills in the observed price of the price triggers in the user's fiat currency
+func (h *AlertController) convertTriggers(ctx context.Context, user *UserModel.User, triggers []UserModel.AlertTrigger) {
+	for i := range triggers {
+		if trigger := &triggers[i]; trigger.Currency != "" {
+			trigger.Fiat = h.convert(ctx, user, trigger.Value, trigger.Currency)
+		}
+	}
+}
+
+// convert converts a decimal price to the user's fiat currency. An empty or invalid price is
+// left unconverted.
+func (h *AlertController) convert(ctx context.Context, user *UserModel.User, value string, currency string) *money.Amount {
+	if h.FXService == nil {
+		return nil
+	}
+	price, err := money.ParseDecimal(value)
+	if err != nil {
+		return nil
+	}
+
+	amount, err := h.FXService.Convert(ctx, price, currency, FXService.PreferredCurrency(user))
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).WithField("user_uuid", user.UUID).Warn("Skipping fiat conversion of alert")
+		return nil
+	}
+	return amount
+}
@@ -12,6 +12,8 @@ import (
 type CoinMarketCapController struct {
 	Cfg                  *EnvTypes.EnvConfig
 	CoinMarketCapService coinMarketCapService.CoinMarketCapServiceInterface
+	FearGreedService     coinMarketCapService.FearGreedServiceInterface
+	AnalyticsService     coinMarketCapService.FearGreedAnalyticsServiceInterface
 }
 
 // NewCoinMarketCapController initializes a new CoinMarketCapController with dependencies from the container.
@@ -19,5 +21,7 @@ func NewCoinMarketCapController(container *container.Container) *CoinMarketCapCo
 	return &CoinMarketCapController{
 		Cfg:                  container.GetConfig(),
 		CoinMarketCapService: container.GetCoinMarketCapService(),
+		FearGreedService:     container.GetFearGreedService(),
+		AnalyticsService:     container.GetFearGreedAnalyticsService(),
 	}
 }
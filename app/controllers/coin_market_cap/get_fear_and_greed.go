@@ -0,0 +1,24 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"net/http"
+
+	"cry-api/app/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetFearAndGreed returns the locally stored Fear and Greed history between ?from= and ?to=
+// (YYYY-MM-DD), aggregated by ?interval= (daily, weekly or monthly), with range statistics
+func (h *CoinMarketCapController) GetFearAndGreed(c *gin.Context) {
+	data, err := h.FearGreedService.GetRange(c.Query("from"), c.Query("to"), c.Query("interval"))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"fear_and_greed": data,
+	})
+}
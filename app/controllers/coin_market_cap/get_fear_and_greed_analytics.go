@@ -0,0 +1,25 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"net/http"
+
+	"cry-api/app/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetFearAndGreedAnalytics returns the locally stored Fear and Greed history joined with the
+// BTC price between ?from= and ?to= (YYYY-MM-DD): forward returns after extreme days, rolling
+// correlation over ?window= days, and time spent in each classification
+func (h *CoinMarketCapController) GetFearAndGreedAnalytics(c *gin.Context) {
+	data, err := h.AnalyticsService.GetAnalytics(c.Query("from"), c.Query("to"), c.Query("window"))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"analytics": data,
+	})
+}
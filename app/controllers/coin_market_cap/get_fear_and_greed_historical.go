@@ -5,16 +5,19 @@ package controllers
 import (
 	"net/http"
 
+	"cry-api/app/logger"
+	"cry-api/app/middleware"
+	app_errors "cry-api/app/types/errors"
+
 	"github.com/gin-gonic/gin"
 )
 
 // GetFearAndGreedHistorical retrieves the Fear and Greed index
 func (h *CoinMarketCapController) GetFearAndGreedHistorical(c *gin.Context) {
-	data, err := h.CoinMarketCapService.GetFearAndGreedHistorical(1, 500)
+	data, err := h.CoinMarketCapService.GetFearAndGreedHistorical(c.Request.Context(), 1, 500)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-		})
+		logger.FromContext(c.Request.Context()).WithError(err).Warn("Failed to fetch the fear and greed history")
+		middleware.AbortWithError(c, app_errors.NewUpstreamError("Failed to fetch the fear and greed history", err))
 		return
 	}
 	// Success
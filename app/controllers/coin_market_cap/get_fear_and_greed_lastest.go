@@ -5,16 +5,19 @@ package controllers
 import (
 	"net/http"
 
+	"cry-api/app/logger"
+	"cry-api/app/middleware"
+	app_errors "cry-api/app/types/errors"
+
 	"github.com/gin-gonic/gin"
 )
 
 // GetFearAndGreedLastest retrieves the Fear and Greed index
 func (h *CoinMarketCapController) GetFearAndGreedLastest(c *gin.Context) {
-	data, err := h.CoinMarketCapService.GetFearAndGreedLastest()
+	data, err := h.CoinMarketCapService.GetFearAndGreedLastest(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-		})
+		logger.FromContext(c.Request.Context()).WithError(err).Warn("Failed to fetch the fear and greed index")
+		middleware.AbortWithError(c, app_errors.NewUpstreamError("Failed to fetch the fear and greed index", err))
 		return
 	}
 	// Success
@@ -0,0 +1,104 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"cry-api/app/middleware"
+	"cry-api/app/money"
+	app_errors "cry-api/app/types/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetQuotes returns the latest quotes of the assets listed in ?symbols= or ?ids=
+// (comma-separated), in the ?convert= fiat currency
+func (h *CoinMarketCapController) GetQuotes(c *gin.Context) {
+	var ids []int
+	for _, raw := range splitList(c.Query("ids")) {
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			middleware.AbortWithError(c, app_errors.NewValidationError("ids", raw, "Ids must be positive integers"))
+			return
+		}
+		ids = append(ids, id)
+	}
+
+	quotes, err := h.CoinMarketCapService.GetLatestQuotes(c.Request.Context(), splitList(c.Query("symbols")), ids, c.Query("convert"))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"quotes": quotes})
+}
+
+// GetOHLCV returns the ?interval= candles of ?symbol= between ?from= and ?to= (dates or
+// RFC 3339 times), in the ?convert= fiat currency
+func (h *CoinMarketCapController) GetOHLCV(c *gin.Context) {
+	from, err := parseTime("from", c.Query("from"))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+	to, err := parseTime("to", c.Query("to"))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	ohlcv, err := h.CoinMarketCapService.GetOHLCV(c.Request.Context(), c.Query("symbol"), c.Query("convert"), c.Query("interval"), from, to)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"ohlcv": ohlcv})
+}
+
+// ConvertPrice values ?amount= of ?symbol= in the ?convert= fiat currency
+func (h *CoinMarketCapController) ConvertPrice(c *gin.Context) {
+	amount, err := money.ParseDecimal(c.Query("amount"))
+	if err != nil {
+		middleware.AbortWithError(c, app_errors.NewValidationError("amount", c.Query("amount"), "Amount must be a positive number"))
+		return
+	}
+
+	conversion, err := h.CoinMarketCapService.ConvertPrice(c.Request.Context(), amount, c.Query("symbol"), c.Query("convert"))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"conversion": conversion})
+}
+
+// splitList splits a comma-separated query parameter, dropping empty items
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseTime reads a YYYY-MM-DD date or an RFC 3339 time. An empty value is the zero time.
+func parseTime(field, raw string) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if day, err := time.Parse(time.DateOnly, raw); err == nil {
+		return day, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, app_errors.NewValidationError(field, raw, field+" must be a date like 2024-01-31 or an RFC 3339 time")
+	}
+	return t, nil
+}
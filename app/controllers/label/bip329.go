@@ -0,0 +1,79 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"cry-api/app/logger"
+	"cry-api/app/middleware"
+	LabelService "cry-api/app/services/label"
+	app_errors "cry-api/app/types/errors"
+	"cry-api/app/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImportLabels imports a BIP-329 JSON Lines file, sent either as the raw request body or
+// as the "file" field of a multipart form.
+func (h *LabelController) ImportLabels(c *gin.Context) {
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, LabelService.MaxImportBytes)
+
+	var body io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		file, err := c.FormFile("file")
+		if err != nil {
+			middleware.AbortWithError(c, app_errors.NewValidationError("file", "", "Missing file field"))
+			return
+		}
+		f, err := file.Open()
+		if err != nil {
+			middleware.AbortWithError(c, app_errors.NewValidationError("file", file.Filename, "File could not be read"))
+			return
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		body = f
+	}
+
+	result, err := h.LabelService.ImportLabels(user.ID, body)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).WithError(err).WithField("user_uuid", user.UUID).Warn("Label import failed")
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"import": result})
+}
+
+// ExportLabels downloads every label of the user as BIP-329 JSON Lines.
+func (h *LabelController) ExportLabels(c *gin.Context) {
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	out := utils.NewDownloadWriter(c.Writer, "application/jsonl", "labels.jsonl")
+	if err := h.LabelService.ExportLabels(user.ID, out); err != nil {
+		if out.Started() {
+			logger.FromContext(c.Request.Context()).WithError(err).WithField("user_uuid", user.UUID).Error("Label export aborted after streaming started")
+			c.Abort()
+			return
+		}
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	// An empty export still downloads as an empty file
+	if !out.Started() {
+		_, _ = out.Write(nil)
+	}
+}
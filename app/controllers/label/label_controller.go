@@ -0,0 +1,23 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"cry-api/app/container"
+	LabelService "cry-api/app/services/label"
+	UserService "cry-api/app/services/users"
+)
+
+// LabelController handles BIP-329 transaction and address labels.
+type LabelController struct {
+	UserService  UserService.UserServiceInterface
+	LabelService LabelService.LabelServiceInterface
+}
+
+// NewLabelController initializes a new LabelController with dependencies from the container.
+func NewLabelController(container *container.Container) *LabelController {
+	return &LabelController{
+		UserService:  container.GetUserService(),
+		LabelService: container.GetLabelService(),
+	}
+}
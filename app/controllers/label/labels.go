@@ -0,0 +1,81 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"cry-api/app/logger"
+	"cry-api/app/middleware"
+	app_errors "cry-api/app/types/errors"
+	LabelTypes "cry-api/app/types/label"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListLabels returns the user's labels, optionally filtered with ?type=.
+func (h *LabelController) ListLabels(c *gin.Context) {
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	labels, err := h.LabelService.ListLabels(user.ID, c.Query("type"))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"labels": labels})
+}
+
+// SetLabel creates or replaces the label of a transaction, address, key or outpoint.
+func (h *LabelController) SetLabel(c *gin.Context) {
+	logger := logger.FromContext(c.Request.Context())
+
+	var input LabelTypes.ILabelRecord
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.WithError(err).Warn("Label validation failed")
+		middleware.AbortWithError(c, app_errors.NewBindingError(err, &input))
+		return
+	}
+
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	label, err := h.LabelService.SetLabel(user.ID, input)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"label": label})
+}
+
+// DeleteLabel removes a label.
+func (h *LabelController) DeleteLabel(c *gin.Context) {
+	raw := c.Param("id")
+	id, err := strconv.Atoi(raw)
+	if err != nil || id <= 0 {
+		middleware.AbortWithError(c, app_errors.NewValidationError("id", raw, "Invalid label id"))
+		return
+	}
+
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	if err := h.LabelService.DeleteLabel(user.ID, id); err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Label removed",
+	})
+}
@@ -0,0 +1,23 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"cry-api/app/container"
+	NotificationService "cry-api/app/services/notification"
+	UserService "cry-api/app/services/users"
+)
+
+// NotificationController handles the authenticated user's in-app notification feed.
+type NotificationController struct {
+	UserService         UserService.UserServiceInterface
+	NotificationService NotificationService.NotificationServiceInterface
+}
+
+// NewNotificationController initializes a new NotificationController with dependencies from the container.
+func NewNotificationController(container *container.Container) *NotificationController {
+	return &NotificationController{
+		UserService:         container.GetUserService(),
+		NotificationService: container.GetNotificationService(),
+	}
+}
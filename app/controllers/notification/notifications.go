@@ -0,0 +1,80 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"cry-api/app/middleware"
+	app_errors "cry-api/app/types/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListNotifications returns the user's latest notifications, only the unread ones with ?unread=true.
+func (h *NotificationController) ListNotifications(c *gin.Context) {
+	unreadOnly := false
+	if raw := c.Query("unread"); raw != "" {
+		var err error
+		if unreadOnly, err = strconv.ParseBool(raw); err != nil {
+			middleware.AbortWithError(c, app_errors.NewValidationError("unread", raw, "unread must be true or false"))
+			return
+		}
+	}
+
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	notifications, err := h.NotificationService.ListNotifications(user.ID, unreadOnly)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"notifications": notifications})
+}
+
+// MarkRead marks a notification as read.
+func (h *NotificationController) MarkRead(c *gin.Context) {
+	raw := c.Param("id")
+	id, err := strconv.Atoi(raw)
+	if err != nil || id <= 0 {
+		middleware.AbortWithError(c, app_errors.NewValidationError("id", raw, "Invalid notification id"))
+		return
+	}
+
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	notification, err := h.NotificationService.MarkRead(user.ID, id)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"notification": notification})
+}
+
+// MarkAllRead marks every unread notification as read.
+func (h *NotificationController) MarkAllRead(c *gin.Context) {
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	count, err := h.NotificationService.MarkAllRead(user.ID)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"updated": count,
+	})
+}
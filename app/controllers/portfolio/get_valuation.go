@@ -0,0 +1,50 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"cry-api/app/logger"
+	"cry-api/app/middleware"
+	FXService "cry-api/app/services/fx"
+	app_errors "cry-api/app/types/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetValuation returns the value over time, cost basis and unrealized P&L of the user's watchlist,
+// in the currency query parameter, else the user's fiat currency, else USD.
+func (h *PortfolioController) GetValuation(c *gin.Context) {
+	logger := logger.FromContext(c.Request.Context())
+
+	days := 0
+	if raw := c.Query("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			middleware.AbortWithError(c, app_errors.NewValidationError("days", raw, "Days must be a number"))
+			return
+		}
+		days = parsed
+	}
+
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	currency := c.Query("currency")
+	if currency == "" {
+		currency = FXService.PreferredCurrency(user)
+	}
+
+	valuation, err := h.PortfolioService.GetValuation(c.Request.Context(), user.ID, currency, days)
+	if err != nil {
+		logger.WithError(err).WithField("user_uuid", user.UUID).Warn("Failed to value portfolio")
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"portfolio": valuation})
+}
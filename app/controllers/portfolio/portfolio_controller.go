@@ -0,0 +1,23 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"cry-api/app/container"
+	PortfolioService "cry-api/app/services/portfolio"
+	UserService "cry-api/app/services/users"
+)
+
+// PortfolioController handles portfolio valuation requests.
+type PortfolioController struct {
+	UserService      UserService.UserServiceInterface
+	PortfolioService PortfolioService.PortfolioServiceInterface
+}
+
+// NewPortfolioController initializes a new PortfolioController with dependencies from the container.
+func NewPortfolioController(container *container.Container) *PortfolioController {
+	return &PortfolioController{
+		UserService:      container.GetUserService(),
+		PortfolioService: container.GetPortfolioService(),
+	}
+}
@@ -0,0 +1,26 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"cry-api/app/container"
+	RealtimeService "cry-api/app/services/realtime"
+	UserService "cry-api/app/services/users"
+	EnvTypes "cry-api/app/types/env"
+)
+
+// RealtimeController serves the realtime gateway's SSE and WebSocket streams.
+type RealtimeController struct {
+	UserService UserService.UserServiceInterface
+	Hub         RealtimeService.HubInterface
+	Config      *EnvTypes.EnvConfig
+}
+
+// NewRealtimeController initializes a new RealtimeController with dependencies from the container.
+func NewRealtimeController(container *container.Container) *RealtimeController {
+	return &RealtimeController{
+		UserService: container.GetUserService(),
+		Hub:         container.GetRealtimeHub(),
+		Config:      container.GetConfig(),
+	}
+}
@@ -0,0 +1,291 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"cry-api/app/logger"
+	"cry-api/app/middleware"
+	RealtimeService "cry-api/app/services/realtime"
+	app_errors "cry-api/app/types/errors"
+	RealtimeTypes "cry-api/app/types/realtime"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/gin-gonic/gin"
+)
+
+// maxMessageSize caps the messages a WebSocket client can send, subscription changes being
+// small
+const maxMessageSize = 4096
+
+// tokenExpiredMessage is sent before a stream is closed because its access token expired
+const tokenExpiredMessage = "Access token expired"
+
+// Stream serves the events of the topics listed in the topics query parameter as
+// Server-Sent Events. A comment line is sent every heartbeat to keep idle proxies from
+// closing the stream. The stream ends when the access token expires.
+func (h *RealtimeController) Stream(c *gin.Context) {
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	client := h.Hub.Register(user.ID)
+	defer h.Hub.Unregister(client)
+
+	topics, err := h.Hub.Subscribe(client, splitTopics(c.Query("topics")))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	w := c.Writer
+	if err := writeSSE(w, RealtimeTypes.MessageSubscribed, 0, RealtimeTypes.IControlMessage{Type: RealtimeTypes.MessageSubscribed, Topics: topics}); err != nil {
+		return
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat())
+	defer heartbeat.Stop()
+	expired, stopExpiry := tokenExpiry(c)
+	defer stopExpiry()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-expired:
+			_ = writeSSE(w, RealtimeTypes.MessageError, 0, RealtimeTypes.IControlMessage{Type: RealtimeTypes.MessageError, Message: tokenExpiredMessage})
+			w.Flush()
+			return
+		case <-client.Done():
+			if reason := client.Reason(); reason != "" {
+				_ = writeSSE(w, RealtimeTypes.MessageError, 0, RealtimeTypes.IControlMessage{Type: RealtimeTypes.MessageError, Message: reason})
+				w.Flush()
+			}
+			return
+		case event := <-client.Events():
+			if err := writeSSE(w, event.Topic, event.ID, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+		w.Flush()
+	}
+}
+
+// WebSocket upgrades the request to a WebSocket connection serving the events of its topics.
+// Topics can be given in the topics query parameter, and changed with subscribe and
+// unsubscribe messages. The server pings every heartbeat and drops a connection that does
+// not answer within a heartbeat. When the access token expires the connection is closed
+// with the code 1008.
+func (h *RealtimeController) WebSocket(c *gin.Context) {
+	logger := logger.FromContext(c.Request.Context())
+
+	if !c.IsWebsocket() {
+		middleware.AbortWithError(c, app_errors.NewAppError(http.StatusBadRequest, "Expected a WebSocket upgrade request", "The Connection: Upgrade and Upgrade: websocket headers are required"))
+		return
+	}
+
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	client := h.Hub.Register(user.ID)
+	defer h.Hub.Unregister(client)
+
+	if raw := c.Query("topics"); raw != "" {
+		if _, err := h.Hub.Subscribe(client, splitTopics(raw)); err != nil {
+			middleware.AbortWithError(c, err)
+			return
+		}
+	}
+
+	// The frontend is served from its own origin
+	conn, err := websocket.Accept(c.Writer, c.Request, &websocket.AcceptOptions{OriginPatterns: h.originPatterns()})
+	if err != nil {
+		logger.WithError(err).WithField("user_uuid", user.UUID).Warn("Failed to upgrade to WebSocket")
+		return
+	}
+	defer func() {
+		_ = conn.CloseNow()
+	}()
+	conn.SetReadLimit(maxMessageSize)
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	heartbeat := h.heartbeat()
+	if err := writeJSON(ctx, conn, heartbeat, RealtimeTypes.IControlMessage{Type: RealtimeTypes.MessageSubscribed, Topics: h.Hub.Topics(client)}); err != nil {
+		return
+	}
+
+	readErr := make(chan error, 1)
+	go h.readMessages(ctx, conn, client, heartbeat, readErr)
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+	expired, stopExpiry := tokenExpiry(c)
+	defer stopExpiry()
+
+	for {
+		select {
+		case event := <-client.Events():
+			if err := writeJSON(ctx, conn, heartbeat, event); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := ping(ctx, conn, heartbeat); err != nil {
+				return
+			}
+		case <-expired:
+			_ = writeJSON(ctx, conn, heartbeat, RealtimeTypes.IControlMessage{Type: RealtimeTypes.MessageError, Message: tokenExpiredMessage})
+			_ = conn.Close(websocket.StatusPolicyViolation, "access token expired")
+			return
+		case <-client.Done():
+			if reason := client.Reason(); reason != "" {
+				_ = writeJSON(ctx, conn, heartbeat, RealtimeTypes.IControlMessage{Type: RealtimeTypes.MessageError, Message: reason})
+				if reason == RealtimeService.ReasonShutdown {
+					_ = conn.Close(websocket.StatusGoingAway, "server shutting down")
+				} else {
+					_ = conn.Close(websocket.StatusTryAgainLater, "too slow")
+				}
+			}
+			return
+		case err := <-readErr:
+			if websocket.CloseStatus(err) == -1 && !errors.Is(err, io.EOF) && !errors.Is(err, context.Canceled) {
+				logger.WithError(err).WithField("user_uuid", user.UUID).Debug("WebSocket connection ended")
+			}
+			return
+		}
+	}
+}
+
+// readMessages applies the subscription changes sent by a WebSocket client until the
+// connection fails, and reports the failure on errs
+func (h *RealtimeController) readMessages(ctx context.Context, conn *websocket.Conn, client *RealtimeService.Client, timeout time.Duration, errs chan<- error) {
+	for {
+		typ, data, err := conn.Read(ctx)
+		if err != nil {
+			errs <- err
+			return
+		}
+
+		var msg RealtimeTypes.IClientMessage
+		if typ != websocket.MessageText || json.Unmarshal(data, &msg) != nil {
+			_ = writeJSON(ctx, conn, timeout, RealtimeTypes.IControlMessage{Type: RealtimeTypes.MessageError, Message: "Messages must be JSON objects with an action and topics"})
+			continue
+		}
+
+		var topics []string
+		reply := RealtimeTypes.MessageSubscribed
+		switch strings.ToLower(msg.Action) {
+		case "subscribe":
+			topics, err = h.Hub.Subscribe(client, msg.Topics)
+		case "unsubscribe":
+			reply = RealtimeTypes.MessageUnsubscribed
+			topics, err = h.Hub.Unsubscribe(client, msg.Topics)
+		default:
+			err = app_errors.NewValidationError("action", msg.Action, "Action must be subscribe or unsubscribe")
+		}
+		if err != nil {
+			_ = writeJSON(ctx, conn, timeout, RealtimeTypes.IControlMessage{Type: RealtimeTypes.MessageError, Message: errorMessage(err)})
+			continue
+		}
+		_ = writeJSON(ctx, conn, timeout, RealtimeTypes.IControlMessage{Type: reply, Topics: topics})
+	}
+}
+
+// originPatterns allows the frontend's origin on top of the API's own host
+func (h *RealtimeController) originPatterns() []string {
+	frontend, err := url.Parse(h.Config.CryAppURL)
+	if err != nil || frontend.Host == "" {
+		return nil
+	}
+	return []string{frontend.Host}
+}
+
+func (h *RealtimeController) heartbeat() time.Duration {
+	return time.Duration(h.Config.RealtimeConfig.HeartbeatInterval) * time.Second
+}
+
+// tokenExpiry fires when the access token of the request expires. It never fires for a
+// token without an expiry.
+func tokenExpiry(c *gin.Context) (<-chan time.Time, func() bool) {
+	claims, ok := middleware.CurrentUserClaims(c)
+	if !ok || claims.ExpiresAt == nil {
+		return nil, func() bool { return false }
+	}
+	timer := time.NewTimer(time.Until(claims.ExpiresAt.Time))
+	return timer.C, timer.Stop
+}
+
+// writeJSON writes v as a JSON text message, giving up after timeout
+func writeJSON(ctx context.Context, conn *websocket.Conn, timeout time.Duration, v any) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return wsjson.Write(ctx, conn, v)
+}
+
+// ping sends a ping and waits for its pong, giving up after timeout
+func ping(ctx context.Context, conn *websocket.Conn, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return conn.Ping(ctx)
+}
+
+// writeSSE writes a Server-Sent Event whose data is v encoded as JSON
+func writeSSE(w io.Writer, event string, id uint64, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	if id > 0 {
+		fmt.Fprintf(&b, "id: %d\n", id)
+	}
+	fmt.Fprintf(&b, "event: %s\ndata: %s\n\n", event, data)
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// splitTopics reads a comma separated list of topics
+func splitTopics(raw string) []string {
+	var topics []string
+	for _, topic := range strings.Split(raw, ",") {
+		if topic = strings.TrimSpace(topic); topic != "" {
+			topics = append(topics, topic)
+		}
+	}
+	return topics
+}
+
+// errorMessage returns the message of an application error
+func errorMessage(err error) string {
+	var validation *app_errors.ValidationError
+	if errors.As(err, &validation) {
+		return validation.Message
+	}
+	return "Internal server error"
+}
@@ -0,0 +1,85 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"cry-api/app/logger"
+	"cry-api/app/middleware"
+	FXService "cry-api/app/services/fx"
+	TaxService "cry-api/app/services/tax"
+	app_errors "cry-api/app/types/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetReport returns realized gains per year as JSON, or as CSV when format=csv, in the
+// currency query parameter or else the user's fiat currency.
+func (h *TaxController) GetReport(c *gin.Context) {
+	logger := logger.FromContext(c.Request.Context())
+
+	year, ok := intQuery(c, "year")
+	if !ok {
+		return
+	}
+	walletID, ok := intQuery(c, "wallet_id")
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		middleware.AbortWithError(c, app_errors.NewValidationError("format", format, "Format must be json or csv"))
+		return
+	}
+
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	currency := c.Query("currency")
+	if currency == "" {
+		currency = FXService.PreferredCurrency(user)
+	}
+
+	report, err := h.TaxService.GetReport(c.Request.Context(), user.ID, walletID, c.Query("method"), currency, year)
+	if err != nil {
+		logger.WithError(err).WithField("user_uuid", user.UUID).Warn("Failed to build tax report")
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	if format == "json" {
+		c.JSON(http.StatusOK, gin.H{"report": report})
+		return
+	}
+
+	filename := fmt.Sprintf("capital-gains-%s.csv", report.Method)
+	if year != 0 {
+		filename = fmt.Sprintf("capital-gains-%d-%s.csv", year, report.Method)
+	}
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+	if err := TaxService.WriteReportCSV(c.Writer, report); err != nil {
+		logger.WithError(err).Error("Failed to write CSV tax report")
+	}
+}
+
+// intQuery parses an optional integer query parameter, aborting the request when it is invalid.
+func intQuery(c *gin.Context, name string) (int, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return 0, true
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		middleware.AbortWithError(c, app_errors.NewValidationError(name, raw, fmt.Sprintf("%s must be a positive number", name)))
+		return 0, false
+	}
+	return value, true
+}
@@ -0,0 +1,23 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"cry-api/app/container"
+	TaxService "cry-api/app/services/tax"
+	UserService "cry-api/app/services/users"
+)
+
+// TaxController handles capital-gains reports and own-wallet transfer tags.
+type TaxController struct {
+	UserService UserService.UserServiceInterface
+	TaxService  TaxService.TaxServiceInterface
+}
+
+// NewTaxController initializes a new TaxController with dependencies from the container.
+func NewTaxController(container *container.Container) *TaxController {
+	return &TaxController{
+		UserService: container.GetUserService(),
+		TaxService:  container.GetTaxService(),
+	}
+}
@@ -0,0 +1,81 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"cry-api/app/logger"
+	"cry-api/app/middleware"
+	app_errors "cry-api/app/types/errors"
+	TaxTypes "cry-api/app/types/tax"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListTransfers returns the transactions the user tagged as own-wallet transfers.
+func (h *TaxController) ListTransfers(c *gin.Context) {
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	tags, err := h.TaxService.ListTransfers(user.ID)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"transfers": tags})
+}
+
+// TagTransfer tags a transaction as a transfer between the user's own wallets.
+func (h *TaxController) TagTransfer(c *gin.Context) {
+	logger := logger.FromContext(c.Request.Context())
+
+	var input TaxTypes.ITransferTagRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.WithError(err).Warn("Transfer tag validation failed")
+		middleware.AbortWithError(c, app_errors.NewBindingError(err, &input))
+		return
+	}
+
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	tag, err := h.TaxService.TagTransfer(user.ID, input)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"transfer": tag})
+}
+
+// UntagTransfer removes a transfer tag.
+func (h *TaxController) UntagTransfer(c *gin.Context) {
+	raw := c.Param("id")
+	id, err := strconv.Atoi(raw)
+	if err != nil || id <= 0 {
+		middleware.AbortWithError(c, app_errors.NewValidationError("id", raw, "Invalid transfer id"))
+		return
+	}
+
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	if err := h.TaxService.UntagTransfer(user.ID, id); err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Transfer tag removed",
+	})
+}
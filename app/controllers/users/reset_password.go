@@ -2,13 +2,15 @@ package controllers
 
 import (
 	"fmt"
-	"log"
 	"net/http"
 	"time"
 
 	"cry-api/app/config"
 	"cry-api/app/factories"
+	"cry-api/app/logger"
+	"cry-api/app/middleware"
 	UserModel "cry-api/app/models"
+	app_errors "cry-api/app/types/errors"
 	types "cry-api/app/types/token_purpose"
 	UserTypes "cry-api/app/types/users"
 
@@ -21,31 +23,31 @@ func (h *UserController) HandleResetPasswordRequest(c *gin.Context) {
 
 	// 1️⃣ Parse request
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		middleware.AbortWithError(c, app_errors.NewBindingError(err, &req))
 		return
 	}
 
 	// 2️⃣ Find user by email
 	user, err := h.UserService.FindUserByEmail(req.Email)
 	if err != nil {
-		log.Printf("internal error finding user: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Failed to find user")
+		middleware.AbortWithError(c, app_errors.NewInternalServerError("Internal server error"))
 		return
 	}
 	if user == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		middleware.AbortWithError(c, app_errors.NewNotFoundError("User", "User not found"))
 		return
 	}
 	if !user.IsVerified {
-		c.JSON(http.StatusForbidden, gin.H{"error": "user not verified"})
+		middleware.AbortWithError(c, app_errors.NewCodedError(http.StatusForbidden, app_errors.CodeAuthUserNotVerified, "User not verified", ""))
 		return
 	}
 
 	// 3️⃣ Check for existing valid reset password token
 	existingToken, err := h.UserService.FindUserTokenByPurpose(user.ID, string(types.ResetPassword))
 	if err != nil {
-		log.Printf("error checking existing reset token: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Failed to check existing reset password token")
+		middleware.AbortWithError(c, app_errors.NewInternalServerError("Internal server error"))
 		return
 	}
 
@@ -54,15 +56,15 @@ func (h *UserController) HandleResetPasswordRequest(c *gin.Context) {
 		// 4️⃣ Generate new reset password token
 		resetTokenObj, err = factories.NewUserToken(user.ID, string(types.ResetPassword), time.Hour, factories.LongLink)
 		if err != nil {
-			log.Printf("failed to generate reset password token: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not generate token"})
+			logger.FromContext(c.Request.Context()).WithError(err).Error("Failed to generate reset password token")
+			middleware.AbortWithError(c, app_errors.ErrTokenGeneration)
 			return
 		}
 
 		// 5️⃣ Save new token
 		if err := h.UserTokenService.Save(resetTokenObj); err != nil {
-			log.Printf("error saving reset password token: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not save token"})
+			logger.FromContext(c.Request.Context()).WithError(err).Error("Failed to save reset password token")
+			middleware.AbortWithError(c, app_errors.NewInternalServerError("Could not save token"))
 			return
 		}
 	} else {
@@ -71,6 +73,7 @@ func (h *UserController) HandleResetPasswordRequest(c *gin.Context) {
 	}
 
 	// 6️⃣ Send reset password email asynchronously
+	log := logger.FromContext(c.Request.Context())
 	go func(user *UserModel.User, token *UserModel.UserToken) {
 		cfg := config.Get()
 		resetPasswordLink := fmt.Sprintf("%s/auth/reset-password/%s", cfg.CryAppURL, token.Token)
@@ -82,7 +85,7 @@ func (h *UserController) HandleResetPasswordRequest(c *gin.Context) {
 			resetPasswordLink,
 			cfg.CryAPIURL,
 		); err != nil {
-			log.Printf("Failed to send reset password email to %s: %v", user.Email, err)
+			log.WithError(err).WithField("user_uuid", user.UUID).Error("Failed to send reset password email")
 		}
 	}(user, resetTokenObj)
 
@@ -5,6 +5,7 @@ package controllers
 import (
 	"net/http"
 
+	"cry-api/app/middleware"
 	JWT "cry-api/app/services/jwt"
 	SignInError "cry-api/app/types/errors"
 	UserTypes "cry-api/app/types/users"
@@ -17,7 +18,7 @@ func (h *UserController) SignIn(c *gin.Context) {
 	var req UserTypes.IUserSigninRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		middleware.AbortWithError(c, SignInError.NewBindingError(err, &req))
 		return
 	}
 
@@ -25,16 +26,18 @@ func (h *UserController) SignIn(c *gin.Context) {
 	if err != nil {
 		switch err {
 		case SignInError.ErrUserNotFound, SignInError.ErrInvalidPassword:
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid username or password"})
+			middleware.AbortWithError(c, SignInError.NewCodedError(http.StatusUnauthorized, SignInError.CodeAuthInvalidCredentials, "Invalid username or password", ""))
+		case SignInError.ErrUserNotVerified:
+			middleware.AbortWithError(c, err)
 		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+			middleware.AbortWithError(c, SignInError.NewInternalServerError("Something went wrong"))
 		}
 		return
 	}
 
 	jwt, err := JWT.GenerateJWT(user.UUID, user.Email, user.TwoFAEnabled, false)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		middleware.AbortWithError(c, SignInError.ErrTokenGeneration)
 		return
 	}
 
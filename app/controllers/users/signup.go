@@ -30,7 +30,7 @@ and sends a verification email containing both asynchronously.
 */
 func (h *UserController) Signup(c *gin.Context) {
 	cfg := config.Get()
-	logger := logger.GetLogger()
+	logger := logger.FromContext(c.Request.Context())
 
 	// Validate request input
 	input, err := validators.ValidateUserSignup(c)
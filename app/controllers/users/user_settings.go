@@ -8,6 +8,7 @@ import (
 
 	"cry-api/app/logger"
 	"cry-api/app/middleware"
+	FXService "cry-api/app/services/fx"
 	services "cry-api/app/services/jwt"
 	app_errors "cry-api/app/types/errors"
 	UserTypes "cry-api/app/types/users"
@@ -22,13 +23,13 @@ It validates the incoming request, extracts the user from JWT context,
 and updates the user's fullname in the database.
 */
 func (h *UserController) UpdateAccountName(c *gin.Context) {
-	logger := logger.GetLogger()
+	logger := logger.FromContext(c.Request.Context())
 
 	// Validate request input
 	var input UserTypes.IUserUpdateAccountNameRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
 		logger.WithError(err).Warn("User account name update validation failed")
-		middleware.AbortWithError(c, app_errors.ErrInvalidInput)
+		middleware.AbortWithError(c, app_errors.NewBindingError(err, &input))
 		return
 	}
 
@@ -100,3 +101,43 @@ func (h *UserController) UpdateAccountName(c *gin.Context) {
 		"message": "Username updated successfully",
 	})
 }
+
+/*
+UpdateFiatCurrency handles requests to change the fiat currency a user's balances,
+portfolio and alerts are converted to. The currency must be a supported ISO-4217 code.
+*/
+func (h *UserController) UpdateFiatCurrency(c *gin.Context) {
+	logger := logger.FromContext(c.Request.Context())
+
+	var input UserTypes.IUserUpdateFiatCurrencyRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.WithError(err).Warn("User fiat currency update validation failed")
+		middleware.AbortWithError(c, app_errors.NewBindingError(err, &input))
+		return
+	}
+
+	currency, err := FXService.NormalizeCurrency("fiat_currency", input.FiatCurrency)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	user.FiatCurrency = currency
+	if err := h.UserService.UpdateUser(user); err != nil {
+		logger.WithError(err).WithField("user_uuid", user.UUID).Error("Failed to update user fiat currency")
+		middleware.AbortWithError(c, app_errors.NewInternalServerError("Failed to update fiat currency"))
+		return
+	}
+
+	logger.WithField("user_uuid", user.UUID).Info("User fiat currency updated successfully")
+	c.JSON(http.StatusOK, gin.H{
+		"success":       true,
+		"message":       "Fiat currency updated successfully",
+		"fiat_currency": currency,
+	})
+}
@@ -3,10 +3,12 @@
 package controllers
 
 import (
-	"log"
 	"net/http"
 	"time"
 
+	"cry-api/app/logger"
+	"cry-api/app/middleware"
+	app_errors "cry-api/app/types/errors"
 	types "cry-api/app/types/token_purpose"
 	UserTypes "cry-api/app/types/users"
 
@@ -19,31 +21,31 @@ func (h *UserController) VerifyAccountToken(c *gin.Context) {
 	var req UserTypes.IUserVerifyAccountTokenRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		middleware.AbortWithError(c, app_errors.NewBindingError(err, &req))
 		return
 	}
 
 	tokenValue := req.Token
 	if tokenValue == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Token is required"})
+		middleware.AbortWithError(c, app_errors.NewValidationError("token", "", "Token is required"))
 		return
 	}
 
 	// 1️⃣ Find the long-link account_verification token
 	userTokenObj, err := h.UserTokenService.FindValidToken(tokenValue, string(types.AccountVerification))
 	if err != nil {
-		log.Printf("error finding token: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Failed to find token")
+		middleware.AbortWithError(c, app_errors.NewInternalServerError("Internal server error"))
 		return
 	}
 	if userTokenObj == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Token is invalid or expired"})
+		middleware.AbortWithError(c, app_errors.NewCodedError(http.StatusBadRequest, app_errors.CodeVerificationTokenInvalid, "Token is invalid or expired", ""))
 		return
 	}
 
 	// 2️⃣ Ensure the token is not older than 24 hours (optional if your token repo handles expires_at)
 	if userTokenObj.ExpiresAt.Before(time.Now()) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Token has expired"})
+		middleware.AbortWithError(c, app_errors.NewCodedError(http.StatusBadRequest, app_errors.CodeVerificationTokenExpired, "Token has expired", ""))
 		return
 	}
 
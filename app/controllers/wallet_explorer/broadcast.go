@@ -0,0 +1,37 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"net/http"
+
+	"cry-api/app/logger"
+	"cry-api/app/middleware"
+	app_errors "cry-api/app/types/errors"
+	WalletExplorerTypes "cry-api/app/types/wallet_explorer"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BroadcastTransaction validates a signed raw transaction and broadcasts it for the signed-in user.
+func (h *WalletExplorerController) BroadcastTransaction(c *gin.Context) {
+	var req WalletExplorerTypes.IBroadcastRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, app_errors.NewBindingError(err, &req))
+		return
+	}
+
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	result, err := h.BroadcastService.Broadcast(c.Request.Context(), user.ID, c.ClientIP(), req.Tx)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).WithError(err).WithField("user_uuid", user.UUID).Warn("Transaction broadcast failed")
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"broadcast": result})
+}
@@ -0,0 +1,35 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"net/http"
+
+	"cry-api/app/middleware"
+	app_errors "cry-api/app/types/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConvertExtendedKey converts an extended public key between SLIP-132 formats (xpub, ypub, zpub, ...).
+func (h *WalletExplorerController) ConvertExtendedKey(c *gin.Context) {
+	xpub := c.Query("xpub")
+	if xpub == "" {
+		middleware.AbortWithError(c, app_errors.NewValidationError("xpub", "", "Missing xpub parameter"))
+		return
+	}
+
+	to := c.Query("to")
+	if to == "" {
+		middleware.AbortWithError(c, app_errors.NewValidationError("to", "", "Missing to parameter"))
+		return
+	}
+
+	data, err := h.DescriptorService.ConvertExtendedKey(xpub, to)
+	if err != nil {
+		middleware.AbortWithError(c, app_errors.NewValidationError("xpub", xpub, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"extended_key": data})
+}
@@ -0,0 +1,35 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"net/http"
+
+	"cry-api/app/middleware"
+	app_errors "cry-api/app/types/errors"
+	WalletExplorerTypes "cry-api/app/types/wallet_explorer"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DecodeTransaction decodes a raw transaction hex or a PSBT locally, without any upstream call.
+func (h *WalletExplorerController) DecodeTransaction(c *gin.Context) {
+	var req WalletExplorerTypes.IDecodeTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.AbortWithError(c, app_errors.NewBindingError(err, &req))
+		return
+	}
+
+	if req.Network != "" && req.Network != "mainnet" && req.Network != "testnet" {
+		middleware.AbortWithError(c, app_errors.NewValidationError("network", req.Network, "Network must be mainnet or testnet"))
+		return
+	}
+
+	data, err := h.DecoderService.DecodeTransaction(req.Payload, req.Network)
+	if err != nil {
+		middleware.AbortWithError(c, app_errors.NewValidationError("payload", "", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"decoded_transaction": data})
+}
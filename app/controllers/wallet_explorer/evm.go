@@ -0,0 +1,60 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"cry-api/app/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetEVMTransaction returns a transaction of an EVM chain with its receipt and ERC-20 transfers
+func (h *WalletExplorerController) GetEVMTransaction(c *gin.Context) {
+	tx, err := h.EVMService.GetTransaction(c.Request.Context(), c.Param("chain"), c.Param("hash"))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"transaction": tx})
+}
+
+// GetEVMBalances returns the native and token balances of an address on an EVM chain. The
+// optional tokens query parameter lists token contracts to include, comma-separated. Balances
+// are valued in the currency query parameter or the signed-in user's fiat currency.
+func (h *WalletExplorerController) GetEVMBalances(c *gin.Context) {
+	var tokens []string
+	for _, token := range strings.Split(c.Query("tokens"), ",") {
+		if token = strings.TrimSpace(token); token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+
+	currency, ok := h.fiatCurrency(c)
+	if !ok {
+		return
+	}
+
+	balances, err := h.EVMService.GetBalances(c.Request.Context(), c.Param("chain"), c.Param("address"), tokens)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+	h.valueEVMBalances(c, currency, balances)
+
+	c.JSON(http.StatusOK, gin.H{"balances": balances})
+}
+
+// GetEVMTransfers returns the recent ERC-20 transfers of an address on an EVM chain
+func (h *WalletExplorerController) GetEVMTransfers(c *gin.Context) {
+	transfers, err := h.EVMService.GetTokenTransfers(c.Request.Context(), c.Param("chain"), c.Param("address"))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"transfers": transfers})
+}
@@ -0,0 +1,77 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"fmt"
+
+	"cry-api/app/logger"
+	"cry-api/app/middleware"
+	ExportService "cry-api/app/services/export"
+	FXService "cry-api/app/services/fx"
+	app_errors "cry-api/app/types/errors"
+	"cry-api/app/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportAddressHistory streams the history of an address as CSV, JSON Lines or OFX, valued in
+// the currency query parameter or else the signed-in user's fiat currency
+func (h *WalletExplorerController) ExportAddressHistory(c *gin.Context) {
+	address := c.Query("address")
+	if address == "" {
+		middleware.AbortWithError(c, app_errors.NewValidationError("address", "", "Missing address parameter"))
+		return
+	}
+
+	opts, err := ExportService.ParseExportOptions(c.Query("format"), c.Query("columns"), h.exportCurrency(c))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	out := utils.NewDownloadWriter(c.Writer, ExportService.ContentType(opts.Format), fmt.Sprintf("address-history.%s", opts.Format))
+	if err := h.ExportService.ExportAddress(c.Request.Context(), out, address, opts); err != nil {
+		exportFailed(c, out, err)
+	}
+}
+
+// ExportXPUBHistory streams the history of an extended public key as CSV, JSON Lines or OFX,
+// valued in the currency query parameter or else the signed-in user's fiat currency
+func (h *WalletExplorerController) ExportXPUBHistory(c *gin.Context) {
+	xpub := c.Query("xpub")
+	if xpub == "" {
+		middleware.AbortWithError(c, app_errors.NewValidationError("xpub", "", "Missing xpub parameter"))
+		return
+	}
+
+	opts, err := ExportService.ParseExportOptions(c.Query("format"), c.Query("columns"), h.exportCurrency(c))
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	out := utils.NewDownloadWriter(c.Writer, ExportService.ContentType(opts.Format), fmt.Sprintf("xpub-history.%s", opts.Format))
+	if err := h.ExportService.ExportXPUB(c.Request.Context(), out, xpub, opts); err != nil {
+		exportFailed(c, out, err)
+	}
+}
+
+// exportCurrency returns the currency query parameter, else the signed-in user's fiat currency
+func (h *WalletExplorerController) exportCurrency(c *gin.Context) string {
+	if currency := c.Query("currency"); currency != "" {
+		return currency
+	}
+	return FXService.PreferredCurrency(h.optionalUser(c))
+}
+
+// exportFailed answers with an error while nothing has been sent, or logs and drops the
+// download once streaming has started.
+func exportFailed(c *gin.Context, out *utils.DownloadWriter, err error) {
+	if out.Started() {
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Export aborted after streaming started")
+		c.Abort()
+		return
+	}
+	middleware.AbortWithError(c, err)
+}
@@ -0,0 +1,95 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"math/big"
+
+	"cry-api/app/logger"
+	"cry-api/app/middleware"
+	"cry-api/app/money"
+	EVMService "cry-api/app/services/evm"
+	FXService "cry-api/app/services/fx"
+	EVMTypes "cry-api/app/types/evm"
+	WalletExplorer "cry-api/app/types/wallet_explorer"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fiatCurrency returns the currency balances are valued in: the currency query parameter,
+// else the signed-in user's fiat currency, else USD. It aborts the request on an unsupported
+// currency, and returns "" when no FX service is configured.
+func (h *WalletExplorerController) fiatCurrency(c *gin.Context) (string, bool) {
+	if h.FXService == nil {
+		return "", true
+	}
+
+	if raw := c.Query("currency"); raw != "" {
+		currency, err := FXService.NormalizeCurrency("currency", raw)
+		if err != nil {
+			middleware.AbortWithError(c, err)
+			return "", false
+		}
+		return currency, true
+	}
+	return FXService.PreferredCurrency(h.optionalUser(c)), true
+}
+
+// valueXPUB fills in the value of the balance of an extended public key
+func (h *WalletExplorerController) valueXPUB(c *gin.Context, currency string, data *WalletExplorer.ITransactionXPUB) {
+	if currency == "" || data == nil {
+		return
+	}
+
+	balance := new(big.Rat)
+	for _, tx := range data.Transactions {
+		balance.Add(balance, money.FromFloat(tx.BalanceDiff))
+	}
+	data.Fiat = h.value(c, "BTC", balance, currency)
+}
+
+// valueEVMBalances fills in the value of the native coin and of the known tokens of the
+// chain. Tokens are recognized by contract address, never by the symbol they declare.
+func (h *WalletExplorerController) valueEVMBalances(c *gin.Context, currency string, balances *EVMTypes.IEVMBalances) {
+	if currency == "" || balances == nil {
+		return
+	}
+
+	h.valueAsset(c, balances.Native.Symbol, &balances.Native, currency)
+	for i := range balances.Tokens {
+		if symbol, ok := EVMService.PricedSymbol(balances.Chain, balances.Tokens[i].Token); ok {
+			h.valueAsset(c, symbol, &balances.Tokens[i], currency)
+		}
+	}
+}
+
+// valueAsset fills in the value of an EVM balance at the price of symbol
+func (h *WalletExplorerController) valueAsset(c *gin.Context, symbol string, asset *EVMTypes.IAssetBalance, currency string) {
+	if symbol == "" || asset.Amount == "" {
+		return
+	}
+	quantity, err := money.ParseDecimal(asset.Amount)
+	if err != nil {
+		return
+	}
+	asset.Fiat = h.value(c, symbol, quantity, currency)
+}
+
+// value values a quantity of an asset, or returns nil when it has no price. A zero balance
+// is worth zero without asking for a price.
+func (h *WalletExplorerController) value(c *gin.Context, symbol string, quantity *big.Rat, currency string) *money.Amount {
+	if quantity.Sign() == 0 {
+		amount, err := money.FromMinor(0, currency)
+		if err != nil {
+			return nil
+		}
+		return &amount
+	}
+
+	amount, err := h.FXService.Value(c.Request.Context(), symbol, quantity, currency)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).WithError(err).WithField("symbol", symbol).Warn("Skipping fiat value of balance")
+		return nil
+	}
+	return amount
+}
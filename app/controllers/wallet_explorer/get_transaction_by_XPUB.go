@@ -3,24 +3,46 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 
+	"cry-api/app/logger"
+	"cry-api/app/middleware"
+	app_errors "cry-api/app/types/errors"
+
 	"github.com/gin-gonic/gin"
 )
 
-// GetTransactionByXPUB retrieves transactions by transaction XPUB.
+// GetTransactionByXPUB retrieves transactions by transaction XPUB, with the value of its balance
+// in the currency query parameter or the signed-in user's fiat currency.
 func (h *WalletExplorerController) GetTransactionByXPUB(c *gin.Context) {
 	xpub := c.Query("xpub")
 	if xpub == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing xpub parameter"})
+		middleware.AbortWithError(c, app_errors.NewValidationError("xpub", "", "Missing xpub parameter"))
+		return
+	}
+
+	currency, ok := h.fiatCurrency(c)
+	if !ok {
 		return
 	}
 
-	data, err := h.TransactionService.GetTransactionByXPUB(xpub)
+	data, err := h.TransactionService.GetTransactionByXPUB(c.Request.Context(), xpub)
+	var validationErr *app_errors.ValidationError
+	if errors.As(err, &validationErr) {
+		middleware.AbortWithError(c, err)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		logger.FromContext(c.Request.Context()).WithError(err).Warn("Failed to fetch xpub transactions")
+		middleware.AbortWithError(c, app_errors.NewUpstreamError("Failed to fetch xpub transactions", err))
 		return
 	}
 
+	h.annotate(c, func(userID int) error {
+		return h.LabelService.AnnotateXPUB(userID, xpub, data)
+	})
+	h.valueXPUB(c, currency, data)
+
 	c.JSON(http.StatusOK, gin.H{"xpub": data})
 }
@@ -3,8 +3,13 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 
+	"cry-api/app/logger"
+	"cry-api/app/middleware"
+	app_errors "cry-api/app/types/errors"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -13,18 +18,26 @@ func (h *WalletExplorerController) GetTransactionInfo(c *gin.Context) {
 	// Query param
 	txid := c.Query("txid")
 	if txid == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing txid parameter"})
+		middleware.AbortWithError(c, app_errors.NewValidationError("txid", "", "Missing txid parameter"))
 		return
 	}
 
-	data, err := h.TransactionService.GetTransactionByTxID(txid)
+	data, err := h.TransactionService.GetTransactionByTxID(c.Request.Context(), txid)
+	var validationErr *app_errors.ValidationError
+	if errors.As(err, &validationErr) {
+		middleware.AbortWithError(c, err)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-		})
+		logger.FromContext(c.Request.Context()).WithError(err).WithField("txid", txid).Warn("Failed to fetch transaction")
+		middleware.AbortWithError(c, app_errors.NewUpstreamError("Failed to fetch transaction", err))
 		return
 	}
 
+	h.annotate(c, func(userID int) error {
+		return h.LabelService.AnnotateTransaction(userID, data)
+	})
+
 	// Success
 	c.JSON(http.StatusOK, gin.H{
 		"transaction_data": data,
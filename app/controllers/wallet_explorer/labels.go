@@ -0,0 +1,52 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"cry-api/app/logger"
+	"cry-api/app/middleware"
+	UserModel "cry-api/app/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// optionalUserKey is the context key of the user resolved by optionalUser
+const optionalUserKey = "explorer_user"
+
+// annotate runs fn with the signed-in user's id so their labels can be attached to the
+// response. Anonymous requests are left untouched, and label failures never fail the lookup.
+func (h *WalletExplorerController) annotate(c *gin.Context, fn func(userID int) error) {
+	if h.LabelService == nil {
+		return
+	}
+
+	user := h.optionalUser(c)
+	if user == nil {
+		return
+	}
+
+	if err := fn(user.ID); err != nil {
+		logger.FromContext(c.Request.Context()).WithField("user_uuid", user.UUID).WithError(err).Warn("Failed to attach labels")
+	}
+}
+
+// optionalUser returns the signed-in user, or nil for anonymous requests. The user is looked
+// up once per request.
+func (h *WalletExplorerController) optionalUser(c *gin.Context) *UserModel.User {
+	if cached, ok := c.Get(optionalUserKey); ok {
+		user, _ := cached.(*UserModel.User)
+		return user
+	}
+
+	var user *UserModel.User
+	if claims, ok := middleware.CurrentUserClaims(c); ok {
+		found, err := h.UserService.GetUserByUUID(claims.UUID)
+		if err != nil || found == nil {
+			logger.FromContext(c.Request.Context()).WithField("user_uuid", claims.UUID).WithError(err).Warn("Skipping user data: user not found")
+		} else {
+			user = found
+		}
+	}
+	c.Set(optionalUserKey, user)
+	return user
+}
@@ -0,0 +1,51 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"net/http"
+
+	"cry-api/app/middleware"
+	app_errors "cry-api/app/types/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetFeeEstimates returns the recommended fee rates in sat/vB
+func (h *WalletExplorerController) GetFeeEstimates(c *gin.Context) {
+	fees, err := h.MempoolService.GetFeeEstimates(c.Request.Context())
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"fees": fees})
+}
+
+// GetTip returns the height and hash of the block at the tip of the chain
+func (h *WalletExplorerController) GetTip(c *gin.Context) {
+	tip, err := h.MempoolService.GetTip(c.Request.Context())
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tip": tip})
+}
+
+// GetMempoolStatus reports whether a transaction is confirmed, pending or replaced
+func (h *WalletExplorerController) GetMempoolStatus(c *gin.Context) {
+	txid := c.Query("txid")
+	if txid == "" {
+		middleware.AbortWithError(c, app_errors.NewValidationError("txid", "", "Missing txid parameter"))
+		return
+	}
+
+	status, err := h.MempoolService.GetMempoolStatus(c.Request.Context(), txid)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"mempool_status": status})
+}
@@ -0,0 +1,47 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"cry-api/app/middleware"
+	app_errors "cry-api/app/types/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NormalizeDescriptor converts an xpub/ypub/zpub or output descriptor into a canonical
+// descriptor and its first derived addresses.
+func (h *WalletExplorerController) NormalizeDescriptor(c *gin.Context) {
+	input := c.Query("input")
+	if input == "" {
+		middleware.AbortWithError(c, app_errors.NewValidationError("input", "", "Missing input parameter"))
+		return
+	}
+
+	count := 0
+	if raw := c.Query("count"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			middleware.AbortWithError(c, app_errors.NewValidationError("count", c.Query("count"), "Invalid count parameter"))
+			return
+		}
+		count = parsed
+	}
+
+	data, err := h.DescriptorService.NormalizeDescriptor(input, count)
+	var validationErr *app_errors.ValidationError
+	if errors.As(err, &validationErr) {
+		middleware.AbortWithError(c, err)
+		return
+	}
+	if err != nil {
+		middleware.AbortWithError(c, app_errors.NewValidationError("input", input, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"descriptor": data})
+}
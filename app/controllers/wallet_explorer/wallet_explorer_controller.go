@@ -4,17 +4,40 @@ package controllers
 
 import (
 	"cry-api/app/container"
+	EVMService "cry-api/app/services/evm"
+	ExportService "cry-api/app/services/export"
+	FXService "cry-api/app/services/fx"
+	LabelService "cry-api/app/services/label"
+	UserService "cry-api/app/services/users"
 	walletExplorerService "cry-api/app/services/wallet_explorer"
 )
 
 // WalletExplorerController handles wallet explorer related requests.
 type WalletExplorerController struct {
 	TransactionService walletExplorerService.TransactionServiceInterface
+	DescriptorService  walletExplorerService.DescriptorServiceInterface
+	DecoderService     walletExplorerService.DecoderServiceInterface
+	MempoolService     walletExplorerService.MempoolServiceInterface
+	BroadcastService   walletExplorerService.BroadcastServiceInterface
+	EVMService         EVMService.EVMServiceInterface
+	ExportService      ExportService.ExportServiceInterface
+	UserService        UserService.UserServiceInterface
+	LabelService       LabelService.LabelServiceInterface
+	FXService          FXService.FXServiceInterface
 }
 
 // NewWalletExplorer initializes a new WalletExplorerController with dependencies from the container.
 func NewWalletExplorer(container *container.Container) *WalletExplorerController {
 	return &WalletExplorerController{
 		TransactionService: container.GetTransactionService(),
+		DescriptorService:  container.GetDescriptorService(),
+		DecoderService:     container.GetDecoderService(),
+		MempoolService:     container.GetMempoolService(),
+		BroadcastService:   container.GetBroadcastService(),
+		EVMService:         container.GetEVMService(),
+		ExportService:      container.GetExportService(),
+		UserService:        container.GetUserService(),
+		LabelService:       container.GetLabelService(),
+		FXService:          container.GetFXService(),
 	}
 }
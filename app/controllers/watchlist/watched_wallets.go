@@ -0,0 +1,226 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+
+	"cry-api/app/logger"
+	"cry-api/app/middleware"
+	UserModel "cry-api/app/models"
+	"cry-api/app/money"
+	ExportService "cry-api/app/services/export"
+	FXService "cry-api/app/services/fx"
+	app_errors "cry-api/app/types/errors"
+	WatchlistTypes "cry-api/app/types/watchlist"
+	"cry-api/app/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListWallets returns every entry on the user's watchlist.
+func (h *WatchlistController) ListWallets(c *gin.Context) {
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	wallets, err := h.WatchlistService.ListWallets(user.ID)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"wallets": wallets})
+}
+
+// AddWallet adds an address, extended public key or descriptor to the user's watchlist.
+func (h *WatchlistController) AddWallet(c *gin.Context) {
+	logger := logger.FromContext(c.Request.Context())
+
+	var input WatchlistTypes.ICreateWatchedWalletRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.WithError(err).Warn("Watchlist entry validation failed")
+		middleware.AbortWithError(c, app_errors.NewBindingError(err, &input))
+		return
+	}
+
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	wallet, err := h.WatchlistService.AddWallet(user.ID, input)
+	if err != nil {
+		logger.WithError(err).WithField("user_uuid", user.UUID).Warn("Failed to add watchlist entry")
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"wallet": wallet})
+}
+
+// GetWallet returns a single watchlist entry.
+func (h *WatchlistController) GetWallet(c *gin.Context) {
+	id, ok := walletID(c)
+	if !ok {
+		return
+	}
+
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	wallet, err := h.WatchlistService.GetWallet(user.ID, id)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"wallet": wallet})
+}
+
+// UpdateWallet changes the label and/or color of a watchlist entry.
+func (h *WatchlistController) UpdateWallet(c *gin.Context) {
+	logger := logger.FromContext(c.Request.Context())
+
+	id, ok := walletID(c)
+	if !ok {
+		return
+	}
+
+	var input WatchlistTypes.IUpdateWatchedWalletRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.WithError(err).Warn("Watchlist update validation failed")
+		middleware.AbortWithError(c, app_errors.NewBindingError(err, &input))
+		return
+	}
+
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	wallet, err := h.WatchlistService.UpdateWallet(user.ID, id, input)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"wallet": wallet})
+}
+
+// DeleteWallet removes an entry from the user's watchlist.
+func (h *WatchlistController) DeleteWallet(c *gin.Context) {
+	id, ok := walletID(c)
+	if !ok {
+		return
+	}
+
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	if err := h.WatchlistService.DeleteWallet(user.ID, id); err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Watched wallet removed",
+	})
+}
+
+// GetSummary returns the combined balance and recent activity across the watchlist, with the
+// balances valued in the user's fiat currency.
+func (h *WatchlistController) GetSummary(c *gin.Context) {
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	summary, err := h.WatchlistService.GetSummary(c.Request.Context(), user.ID)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+	h.valueSummary(c, user, summary)
+
+	c.JSON(http.StatusOK, gin.H{"summary": summary})
+}
+
+// walletID parses the :id path parameter, aborting the request when it is invalid.
+func walletID(c *gin.Context) (int, bool) {
+	raw := c.Param("id")
+	id, err := strconv.Atoi(raw)
+	if err != nil || id <= 0 {
+		middleware.AbortWithError(c, app_errors.NewValidationError("id", raw, "Invalid watched wallet id"))
+		return 0, false
+	}
+	return id, true
+}
+
+// ExportHistory streams the merged history of the watchlist as CSV, JSON Lines or OFX, valued
+// in the currency query parameter or else the user's fiat currency.
+func (h *WatchlistController) ExportHistory(c *gin.Context) {
+	user := middleware.CurrentUser(c, h.UserService)
+	if user == nil {
+		return
+	}
+
+	currency := c.Query("currency")
+	if currency == "" {
+		currency = FXService.PreferredCurrency(user)
+	}
+	opts, err := ExportService.ParseExportOptions(c.Query("format"), c.Query("columns"), currency)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	out := utils.NewDownloadWriter(c.Writer, ExportService.ContentType(opts.Format), fmt.Sprintf("watchlist-history.%s", opts.Format))
+	if err := h.ExportService.ExportWatchlist(c.Request.Context(), out, user.ID, opts); err != nil {
+		if out.Started() {
+			logger.FromContext(c.Request.Context()).WithError(err).WithField("user_uuid", user.UUID).Error("Export aborted after streaming started")
+			c.Abort()
+			return
+		}
+		middleware.AbortWithError(c, err)
+	}
+}
+
+// valueSummary fills in the value of the balances in the user's fiat currency. A price that
+// cannot be fetched leaves the summary unvalued rather than failing the request.
+func (h *WatchlistController) valueSummary(c *gin.Context, user *UserModel.User, summary *WatchlistTypes.IWatchlistSummary) {
+	if h.FXService == nil || summary == nil {
+		return
+	}
+
+	currency := FXService.PreferredCurrency(user)
+	price, err := h.FXService.Price(c.Request.Context(), "BTC", currency)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).WithError(err).WithField("user_uuid", user.UUID).Warn("Skipping fiat value of watchlist")
+		return
+	}
+
+	value := func(btc float64) *money.Amount {
+		amount, err := money.FromRat(new(big.Rat).Mul(money.FromFloat(btc), price), currency)
+		if err != nil {
+			return nil
+		}
+		return &amount
+	}
+
+	for i := range summary.Wallets {
+		if wallet := &summary.Wallets[i]; wallet.Error == nil {
+			wallet.Fiat = value(wallet.Balance)
+		}
+	}
+	summary.TotalFiat = value(summary.TotalBalance)
+}
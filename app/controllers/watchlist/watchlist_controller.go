@@ -0,0 +1,29 @@
+// Package controllers handles incoming HTTP requests, orchestrates business logic
+// through services and repositories, and returns appropriate HTTP responses.
+package controllers
+
+import (
+	"cry-api/app/container"
+	ExportService "cry-api/app/services/export"
+	FXService "cry-api/app/services/fx"
+	UserService "cry-api/app/services/users"
+	WatchlistService "cry-api/app/services/watchlist"
+)
+
+// WatchlistController handles requests for the authenticated user's watchlist.
+type WatchlistController struct {
+	UserService      UserService.UserServiceInterface
+	WatchlistService WatchlistService.WatchlistServiceInterface
+	ExportService    ExportService.ExportServiceInterface
+	FXService        FXService.FXServiceInterface
+}
+
+// NewWatchlistController initializes a new WatchlistController with dependencies from the container.
+func NewWatchlistController(container *container.Container) *WatchlistController {
+	return &WatchlistController{
+		UserService:      container.GetUserService(),
+		WatchlistService: container.GetWatchlistService(),
+		ExportService:    container.GetExportService(),
+		FXService:        container.GetFXService(),
+	}
+}
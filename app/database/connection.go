@@ -1,6 +1,7 @@
 package database
 
 import (
+	"database/sql"
 	"time"
 
 	"gorm.io/gorm"
@@ -63,11 +64,11 @@ func (db *Database) Close() error {
 	return sqlDB.Close()
 }
 
-// Stats returns database connection statistics
-func (db *Database) Stats() (interface{}, error) {
+// Stats returns database connection pool statistics
+func (db *Database) Stats() (sql.DBStats, error) {
 	sqlDB, err := db.DB.DB()
 	if err != nil {
-		return nil, err
+		return sql.DBStats{}, err
 	}
 	return sqlDB.Stats(), nil
 }
@@ -0,0 +1,45 @@
+// Package mail provides functionality for creating and sending email messages,
+// including templated emails for triggered price and sentiment alerts.
+package mail
+
+import (
+	"fmt"
+	"time"
+
+	"cry-api/app/utils"
+)
+
+// CreateAlertEmail generates an EmailMessage about one of the user's alert rules firing,
+// e.g. BTC crossing a price or the fear and greed index dropping into extreme fear.
+//
+// Parameters:
+//   - to: recipient email address
+//   - from: sender email address
+//   - userName: recipient's username to personalize the email
+//   - title: short description of the alert, used as the subject
+//   - message: details of the market data that triggered it
+//   - alertsLink: URL of the user's alert rules
+//
+// Returns:
+//   - an EmailMessage with the title as subject and the rendered HTML body
+//   - an error if the template rendering fails
+func CreateAlertEmail(to, from, userName, title, message, alertsLink string) (EmailMessage, error) {
+	data := map[string]any{
+		"UserName":   userName,
+		"AppName":    "420Cry",
+		"Title":      title,
+		"Message":    message,
+		"AlertsLink": alertsLink,
+		"Year":       time.Now().Year(),
+	}
+
+	templatePrefix := utils.GenerateEmailTemplatePrefix()
+	templatePath := fmt.Sprintf("%s/alert.html", templatePrefix)
+
+	htmlBody, err := RenderTemplate(templatePath, data)
+	if err != nil {
+		return EmailMessage{}, fmt.Errorf("template render error: %w", err)
+	}
+
+	return NewEmailMessage(to, from, title, htmlBody), nil
+}
@@ -3,8 +3,9 @@ package mail
 
 import (
 	"fmt"
-	"log"
 	"net/smtp"
+
+	"cry-api/app/logger"
 )
 
 // SMTPEmailSender implements an email sender using SMTP protocol.
@@ -50,7 +51,7 @@ func (s *SMTPEmailSender) Send(email EmailMessage) error {
 
 	addr := fmt.Sprintf("%s:%s", s.smtpHost, s.smtpPort)
 	if err := smtp.SendMail(addr, nil, email.From, to, msg); err != nil {
-		log.Printf("Failed to send email: %v", err)
+		logger.GetLogger().WithError(err).WithField("smtp_host", s.smtpHost).Error("Failed to send email")
 		return err
 	}
 	return nil
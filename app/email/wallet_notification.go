@@ -0,0 +1,45 @@
+// Package mail provides functionality for creating and sending email messages,
+// including templated emails for watched wallet activity.
+package mail
+
+import (
+	"fmt"
+	"time"
+
+	"cry-api/app/utils"
+)
+
+// CreateWalletNotificationEmail generates an EmailMessage about activity on one of the
+// user's watched wallets, e.g. an incoming transaction or a confirmation.
+//
+// Parameters:
+//   - to: recipient email address
+//   - from: sender email address
+//   - userName: recipient's username to personalize the email
+//   - title: short description of the event, used as the subject
+//   - message: details of the event
+//   - notificationsLink: URL of the user's notification feed
+//
+// Returns:
+//   - an EmailMessage with the title as subject and the rendered HTML body
+//   - an error if the template rendering fails
+func CreateWalletNotificationEmail(to, from, userName, title, message, notificationsLink string) (EmailMessage, error) {
+	data := map[string]any{
+		"UserName":          userName,
+		"AppName":           "420Cry",
+		"Title":             title,
+		"Message":           message,
+		"NotificationsLink": notificationsLink,
+		"Year":              time.Now().Year(),
+	}
+
+	templatePrefix := utils.GenerateEmailTemplatePrefix()
+	templatePath := fmt.Sprintf("%s/wallet_notification.html", templatePrefix)
+
+	htmlBody, err := RenderTemplate(templatePath, data)
+	if err != nil {
+		return EmailMessage{}, fmt.Errorf("template render error: %w", err)
+	}
+
+	return NewEmailMessage(to, from, title, htmlBody), nil
+}
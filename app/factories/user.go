@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"cry-api/app/models"
+	"cry-api/app/money"
 	PasswordService "cry-api/app/services/auth/password"
 
 	"github.com/google/uuid"
@@ -21,13 +22,14 @@ func NewUser(fullname, username, email, password string) (*models.User, error) {
 	}
 
 	user := &models.User{
-		UUID:       uuid.New().String(),
-		Username:   username,
-		Fullname:   fullname,
-		Email:      email,
-		Password:   hashedPassword,
-		IsVerified: false,
-		CreatedAt:  time.Now(),
+		UUID:         uuid.New().String(),
+		Username:     username,
+		Fullname:     fullname,
+		Email:        email,
+		Password:     hashedPassword,
+		IsVerified:   false,
+		FiatCurrency: money.DefaultCurrency,
+		CreatedAt:    time.Now(),
 	}
 
 	return user, nil
@@ -0,0 +1,98 @@
+package httpclient
+
+import (
+	"sync"
+	"time"
+)
+
+// Circuit breaker states
+const (
+	StateClosed   = "closed"
+	StateOpen     = "open"
+	StateHalfOpen = "half-open"
+)
+
+// breaker stops calling a host after threshold consecutive failures. Once the cooldown has
+// passed a single probe is let through: its success closes the breaker, its failure reopens it.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// newBreaker creates a closed breaker. A threshold of zero never opens.
+func newBreaker(threshold int, cooldown time.Duration) *breaker {
+	return &breaker{threshold: threshold, cooldown: cooldown, state: StateClosed}
+}
+
+// allow reports whether a request may be sent at now
+func (b *breaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if now.Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = StateHalfOpen
+		b.probing = true
+		return true
+	case StateHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// success closes the breaker
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = StateClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// failure counts a failed request, opening the breaker at the threshold or after a failed probe
+func (b *breaker) failure(now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.threshold <= 0 {
+		return
+	}
+
+	b.failures++
+	if b.state == StateHalfOpen || b.failures >= b.threshold {
+		b.state = StateOpen
+		b.openedAt = now
+		b.probing = false
+	}
+}
+
+// release gives up a probe whose outcome is unknown, letting the next request probe instead
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// current returns the state of the breaker
+func (b *breaker) current() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
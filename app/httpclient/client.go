@@ -0,0 +1,324 @@
+// Package httpclient provides the shared outbound HTTP client used to call upstream APIs.
+// It applies per-host timeouts, retries idempotent requests with jittered backoff, honors
+// Retry-After, trips a circuit breaker on failing hosts and counts latency and errors per host.
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"cry-api/app/logger"
+)
+
+// RequestIDHeader carries the id of the incoming request the outbound requests are sent for,
+// so that upstream logs can be correlated with ours
+const RequestIDHeader = "X-Request-ID"
+
+// ErrCircuitOpen is returned without contacting the upstream while its circuit breaker is open
+var ErrCircuitOpen = errors.New("upstream circuit breaker is open")
+
+// errRewind marks a request body that could not be read again for a retry; it is not retried
+var errRewind = errors.New("failed to rewind request body")
+
+// Doer is the part of the client the services depend on; *http.Client satisfies it too
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// HostConfig tunes the behaviour towards one upstream host
+type HostConfig struct {
+	Timeout          time.Duration // per attempt, including reading the body
+	MaxRetries       int           // extra attempts of idempotent requests
+	BreakerThreshold int           // consecutive failures opening the breaker, 0 disables it
+	BreakerCooldown  time.Duration // time an open breaker waits before letting a probe through
+}
+
+// Config configures a Client
+type Config struct {
+	Default       HostConfig
+	Hosts         map[string]HostConfig // keyed by URL host, e.g. "blockchain.info"
+	BaseBackoff   time.Duration         // backoff before the first retry, doubled on each retry
+	MaxBackoff    time.Duration         // upper bound of a single backoff
+	MaxRetryAfter time.Duration         // longer Retry-After delays are not waited for
+	Transport     http.RoundTripper     // defaults to http.DefaultTransport
+}
+
+// DefaultConfig returns the settings used for hosts without their own configuration
+func DefaultConfig() Config {
+	return Config{
+		Default: HostConfig{
+			Timeout:          15 * time.Second,
+			MaxRetries:       2,
+			BreakerThreshold: 5,
+			BreakerCooldown:  30 * time.Second,
+		},
+		Hosts:         map[string]HostConfig{},
+		BaseBackoff:   200 * time.Millisecond,
+		MaxBackoff:    5 * time.Second,
+		MaxRetryAfter: 10 * time.Second,
+	}
+}
+
+// Client is a resilient HTTP client shared by all upstream services
+type Client struct {
+	cfg  Config
+	http *http.Client
+
+	mu        sync.Mutex
+	upstreams map[string]*upstream
+	rand      *rand.Rand
+}
+
+// New creates a Client from cfg
+func New(cfg Config) *Client {
+	transport := cfg.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &Client{
+		cfg:       cfg,
+		http:      &http.Client{Transport: transport},
+		upstreams: make(map[string]*upstream),
+		rand:      rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Do sends req, retrying idempotent requests on network errors, 429 and 5xx responses. The
+// request context cancels pending attempts and backoffs. The returned body must be closed.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	cfg := c.hostConfig(host)
+	up := c.upstream(host, cfg)
+	ctx := req.Context()
+
+	retries := 0
+	if isIdempotent(req) && (req.Body == nil || req.Body == http.NoBody || req.GetBody != nil) {
+		retries = cfg.MaxRetries
+	}
+
+	for attempt := 0; ; attempt++ {
+		if !up.breaker.allow(time.Now()) {
+			up.reject()
+			return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, host)
+		}
+
+		resp, cancel, err := c.attempt(req, cfg, attempt)
+		if attempt < retries && ctx.Err() == nil && !errors.Is(err, errRewind) && isRetryable(resp, err) {
+			wait := c.backoff(attempt)
+			if delay, ok := retryAfter(resp); ok {
+				if delay > c.cfg.MaxRetryAfter {
+					return finish(resp, cancel, err)
+				}
+				wait = delay
+			}
+			discard(resp)
+			cancel()
+
+			up.retry()
+			if err := sleep(ctx, wait); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		return finish(resp, cancel, err)
+	}
+}
+
+// attempt performs one try of req under the host timeout and records its outcome
+func (c *Client) attempt(req *http.Request, cfg HostConfig, n int) (*http.Response, context.CancelFunc, error) {
+	host := req.URL.Host
+	up := c.upstream(host, cfg)
+
+	var body io.ReadCloser
+	if n > 0 && req.GetBody != nil {
+		rewound, err := req.GetBody()
+		if err != nil {
+			// Nothing was sent, which says nothing about the upstream
+			up.breaker.release()
+			return nil, nil, fmt.Errorf("%w: %w", errRewind, err)
+		}
+		body = rewound
+	}
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if cfg.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(req.Context(), cfg.Timeout)
+	} else {
+		ctx, cancel = context.WithCancel(req.Context())
+	}
+
+	try := req.Clone(ctx)
+	if id := logger.RequestID(req.Context()); id != "" && try.Header.Get(RequestIDHeader) == "" {
+		try.Header.Set(RequestIDHeader, id)
+	}
+	if body != nil {
+		try.Body = body
+	}
+
+	start := time.Now()
+	resp, err := c.http.Do(try)
+	elapsed := time.Since(start)
+
+	switch {
+	case err != nil && req.Context().Err() != nil:
+		// The caller went away, which says nothing about the upstream
+		up.breaker.release()
+	case err != nil || resp.StatusCode >= http.StatusInternalServerError:
+		up.breaker.failure(time.Now())
+	default:
+		up.breaker.success()
+	}
+	up.observe(elapsed, isFailure(resp, err))
+
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	logger.GetLogger().LogUpstreamRequest(req.Context(), host, req.Method, status, n+1, elapsed, err)
+
+	return resp, cancel, err
+}
+
+// hostConfig returns the configuration of host, falling back to the default
+func (c *Client) hostConfig(host string) HostConfig {
+	if cfg, ok := c.cfg.Hosts[host]; ok {
+		return cfg
+	}
+	return c.cfg.Default
+}
+
+// upstream returns the breaker and counters of host, creating them on first use
+func (c *Client) upstream(host string, cfg HostConfig) *upstream {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	up, ok := c.upstreams[host]
+	if !ok {
+		up = &upstream{breaker: newBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown)}
+		c.upstreams[host] = up
+	}
+	return up
+}
+
+// backoff returns a full-jitter exponential delay before retry number attempt+1
+func (c *Client) backoff(attempt int) time.Duration {
+	ceiling := c.cfg.BaseBackoff << attempt
+	if ceiling <= 0 || ceiling > c.cfg.MaxBackoff {
+		ceiling = c.cfg.MaxBackoff
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return time.Duration(c.rand.Int63n(int64(ceiling) + 1))
+}
+
+// finish hands the final response to the caller, tying the attempt context to its body
+func finish(resp *http.Response, cancel context.CancelFunc, err error) (*http.Response, error) {
+	if err != nil {
+		if cancel != nil {
+			cancel()
+		}
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose releases the attempt context once the caller is done with the body
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and cancels its context
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// discard drains and closes the body of a response that will be retried
+func discard(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	_ = resp.Body.Close()
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// isIdempotent reports whether req may safely be sent again. Requests of other methods opt
+// in with an Idempotency-Key header, e.g. read-only JSON-RPC calls.
+func isIdempotent(req *http.Request) bool {
+	if req.Header.Get("Idempotency-Key") != "" {
+		return true
+	}
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isRetryable reports whether an attempt may succeed when repeated
+func isRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isFailure reports whether an attempt counts as an upstream error
+func isFailure(resp *http.Response, err error) bool {
+	return err != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// retryAfter parses the Retry-After header of resp, given in seconds or as an HTTP date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		delay := time.Until(at)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
@@ -0,0 +1,92 @@
+package httpclient
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// UpstreamStats is a snapshot of the counters of one upstream host
+type UpstreamStats struct {
+	Host         string        `json:"host"`
+	Requests     int64         `json:"requests"` // attempts sent, retries included
+	Errors       int64         `json:"errors"`   // network errors, 429 and 5xx responses
+	Retries      int64         `json:"retries"`
+	Rejected     int64         `json:"rejected"` // requests refused by the open breaker
+	TotalLatency time.Duration `json:"total_latency"`
+	MaxLatency   time.Duration `json:"max_latency"`
+	Breaker      string        `json:"breaker"`
+}
+
+// upstream holds the breaker and counters of one host
+type upstream struct {
+	breaker *breaker
+
+	mu       sync.Mutex
+	requests int64
+	errors   int64
+	retries  int64
+	rejected int64
+	total    time.Duration
+	max      time.Duration
+}
+
+// observe records one attempt
+func (u *upstream) observe(latency time.Duration, failed bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.requests++
+	if failed {
+		u.errors++
+	}
+	u.total += latency
+	if latency > u.max {
+		u.max = latency
+	}
+}
+
+// retry records a retried attempt
+func (u *upstream) retry() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.retries++
+}
+
+// reject records a request refused by the breaker
+func (u *upstream) reject() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.rejected++
+}
+
+// Stats returns the counters of every upstream host contacted so far, sorted by host
+func (c *Client) Stats() []UpstreamStats {
+	c.mu.Lock()
+	hosts := make(map[string]*upstream, len(c.upstreams))
+	for host, up := range c.upstreams {
+		hosts[host] = up
+	}
+	c.mu.Unlock()
+
+	stats := make([]UpstreamStats, 0, len(hosts))
+	for host, up := range hosts {
+		up.mu.Lock()
+		stats = append(stats, UpstreamStats{
+			Host:         host,
+			Requests:     up.requests,
+			Errors:       up.errors,
+			Retries:      up.retries,
+			Rejected:     up.rejected,
+			TotalLatency: up.total,
+			MaxLatency:   up.max,
+			Breaker:      up.breaker.current(),
+		})
+		up.mu.Unlock()
+	}
+
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Host < stats[j].Host })
+	return stats
+}
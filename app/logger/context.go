@@ -0,0 +1,67 @@
+// Package logger provides structured logging functionality for the application.
+package logger
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// contextKey is the type of the keys of the request values stored in a context
+type contextKey string
+
+const (
+	requestIDKey contextKey = "request_id"
+	userUUIDKey  contextKey = "user_uuid"
+)
+
+// WithRequestID returns a copy of ctx carrying the id of the request it serves
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestID returns the id of the request ctx serves, or "" outside of a request
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// WithUserUUID returns a copy of ctx carrying the UUID of the authenticated user
+func WithUserUUID(ctx context.Context, uuid string) context.Context {
+	return context.WithValue(ctx, userUUIDKey, uuid)
+}
+
+// UserUUID returns the UUID of the authenticated user of ctx, or "" when anonymous
+func UserUUID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	uuid, _ := ctx.Value(userUUIDKey).(string)
+	return uuid
+}
+
+// contextFields returns the request id and user UUID carried by ctx as log fields
+func contextFields(ctx context.Context) logrus.Fields {
+	fields := logrus.Fields{}
+	if id := RequestID(ctx); id != "" {
+		fields["request_id"] = id
+	}
+	if uuid := UserUUID(ctx); uuid != "" {
+		fields["user_uuid"] = uuid
+	}
+	return fields
+}
+
+// WithContext returns an entry of the logger tagged with the request id and user UUID of ctx,
+// so that the lines logged while serving a request can be correlated
+func (l *Logger) WithContext(ctx context.Context) *logrus.Entry {
+	return l.Logger.WithContext(ctx).WithFields(contextFields(ctx))
+}
+
+// FromContext returns an entry of the global logger tagged with the request values of ctx
+func FromContext(ctx context.Context) *logrus.Entry {
+	return GetLogger().WithContext(ctx)
+}
@@ -2,6 +2,7 @@
 package logger
 
 import (
+	"context"
 	"os"
 	"strings"
 	"time"
@@ -86,16 +87,26 @@ func (l *Logger) WithError(err error) *logrus.Entry {
 	return l.Logger.WithError(err)
 }
 
-// LogRequest logs HTTP request information
-func (l *Logger) LogRequest(method, path, clientIP string, statusCode int, duration time.Duration) {
-	l.WithFields(logrus.Fields{
+// LogRequest logs HTTP request information, tagged with the request id and user UUID of ctx.
+// Server errors are logged at error level and client errors at warn level.
+func (l *Logger) LogRequest(ctx context.Context, method, path, clientIP string, statusCode int, duration time.Duration) {
+	entry := l.WithContext(ctx).WithFields(logrus.Fields{
 		"type":        "http_request",
 		"method":      method,
 		"path":        path,
 		"client_ip":   clientIP,
 		"status_code": statusCode,
 		"duration_ms": duration.Milliseconds(),
-	}).Info("HTTP request processed")
+	})
+
+	switch {
+	case statusCode >= 500:
+		entry.Error("HTTP request processed")
+	case statusCode >= 400:
+		entry.Warn("HTTP request processed")
+	default:
+		entry.Info("HTTP request processed")
+	}
 }
 
 // LogDatabaseOperation logs database operation information
@@ -114,6 +125,29 @@ func (l *Logger) LogDatabaseOperation(operation, table string, duration time.Dur
 	}
 }
 
+// LogUpstreamRequest logs one attempt of an outbound request to an external API, tagged with
+// the request values of ctx. Successful attempts are only logged at debug level.
+func (l *Logger) LogUpstreamRequest(ctx context.Context, host, method string, statusCode, attempt int, duration time.Duration, err error) {
+	fields := logrus.Fields{
+		"type":        "upstream_request",
+		"host":        host,
+		"method":      method,
+		"status_code": statusCode,
+		"attempt":     attempt,
+		"duration_ms": duration.Milliseconds(),
+	}
+
+	entry := l.WithContext(ctx).WithFields(fields)
+	switch {
+	case err != nil:
+		entry.WithError(err).Warn("Upstream request failed")
+	case statusCode == 429 || statusCode >= 500:
+		entry.Warn("Upstream request failed")
+	default:
+		entry.Debug("Upstream request completed")
+	}
+}
+
 // LogBusinessOperation logs business logic operation information
 func (l *Logger) LogBusinessOperation(operation string, userID interface{}, duration time.Duration, err error) {
 	fields := logrus.Fields{
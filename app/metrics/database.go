@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DBStatsSource is the database the connection pool statistics are read from
+type DBStatsSource interface {
+	Stats() (sql.DBStats, error)
+}
+
+// dbStat is a connection pool statistic exposed as a metric
+type dbStat struct {
+	desc      *prometheus.Desc
+	valueType prometheus.ValueType
+	read      func(sql.DBStats) float64
+}
+
+// dbStatsCollector reads the connection pool statistics on every scrape. Nothing is exposed
+// while the statistics are unavailable, when the database is closed for instance.
+type dbStatsCollector struct {
+	db    DBStatsSource
+	stats []dbStat
+}
+
+func newDBStatsCollector(db DBStatsSource) *dbStatsCollector {
+	stat := func(name, help string, valueType prometheus.ValueType, read func(sql.DBStats) float64) dbStat {
+		return dbStat{desc: prometheus.NewDesc(name, help, nil, nil), valueType: valueType, read: read}
+	}
+
+	return &dbStatsCollector{db: db, stats: []dbStat{
+		stat("cry_db_max_open_connections", "Maximum number of open connections to the database.", prometheus.GaugeValue,
+			func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) }),
+		stat("cry_db_open_connections", "Established connections to the database, in use and idle.", prometheus.GaugeValue,
+			func(s sql.DBStats) float64 { return float64(s.OpenConnections) }),
+		stat("cry_db_in_use_connections", "Connections to the database currently in use.", prometheus.GaugeValue,
+			func(s sql.DBStats) float64 { return float64(s.InUse) }),
+		stat("cry_db_idle_connections", "Idle connections to the database.", prometheus.GaugeValue,
+			func(s sql.DBStats) float64 { return float64(s.Idle) }),
+		stat("cry_db_wait_count_total", "Connections waited for because the pool was exhausted.", prometheus.CounterValue,
+			func(s sql.DBStats) float64 { return float64(s.WaitCount) }),
+		stat("cry_db_wait_duration_seconds_total", "Time spent waiting for a connection.", prometheus.CounterValue,
+			func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() }),
+		stat("cry_db_max_idle_closed_total", "Connections closed because the pool had too many idle ones.", prometheus.CounterValue,
+			func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) }),
+		stat("cry_db_max_lifetime_closed_total", "Connections closed because they reached their maximum lifetime.", prometheus.CounterValue,
+			func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) }),
+	}}
+}
+
+// Describe implements prometheus.Collector
+func (c *dbStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, s := range c.stats {
+		ch <- s.desc
+	}
+}
+
+// Collect implements prometheus.Collector
+func (c *dbStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats, err := c.db.Stats()
+	if err != nil {
+		return
+	}
+	for _, s := range c.stats {
+		ch <- prometheus.MustNewConstMetric(s.desc, s.valueType, s.read(stats))
+	}
+}
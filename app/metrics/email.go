@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	Email "cry-api/app/email"
+)
+
+// EmailSender sends an email; it is the sender the email service depends on
+type EmailSender interface {
+	Send(email Email.EmailMessage) error
+}
+
+// InstrumentedEmailSender counts the emails sent and failed by the wrapped sender
+type InstrumentedEmailSender struct {
+	EmailSender
+	metrics *Metrics
+}
+
+// NewInstrumentedEmailSender wraps sender with m
+func NewInstrumentedEmailSender(sender EmailSender, m *Metrics) *InstrumentedEmailSender {
+	return &InstrumentedEmailSender{EmailSender: sender, metrics: m}
+}
+
+// Send sends email and records the outcome
+func (s *InstrumentedEmailSender) Send(email Email.EmailMessage) error {
+	err := s.EmailSender.Send(email)
+	s.metrics.ObserveEmail(err)
+	return err
+}
@@ -0,0 +1,195 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	app_errors "cry-api/app/types/errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Results of the business events and outbound calls
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// UnmatchedRoute labels the requests that matched no route, so that unknown paths do not
+// create a series each
+const UnmatchedRoute = "unmatched"
+
+// Routes of the business events counted from the HTTP responses
+const (
+	signupRoute       = "POST /api/v1/users/signup"
+	verificationRoute = "POST /api/v1/users/verify-email-token"
+	loginRoute        = "POST /api/v1/users/signin"
+)
+
+// streamRoutes are the long-lived realtime streams. Their duration is the lifetime of the
+// connection, so they are tracked by a gauge of open streams instead of the latency histogram.
+var streamRoutes = map[string]bool{
+	"/api/v1/realtime/sse": true,
+	"/api/v1/realtime/ws":  true,
+}
+
+// IsStream reports whether route is a realtime stream
+func IsStream(route string) bool {
+	return streamRoutes[route]
+}
+
+// Metrics holds the metrics of the application: HTTP requests, outbound upstream calls,
+// database pool and business events
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	httpErrors   *prometheus.CounterVec
+	openStreams  *prometheus.GaugeVec
+
+	upstreamDuration *prometheus.HistogramVec
+	upstreamErrors   *prometheus.CounterVec
+
+	signups       *prometheus.CounterVec
+	verifications *prometheus.CounterVec
+	logins        *prometheus.CounterVec
+	otpFailures   *prometheus.CounterVec
+	emails        *prometheus.CounterVec
+	rateLimited   prometheus.Counter
+}
+
+// New creates the application metrics on a new registry, along with the Go runtime and
+// process metrics
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "cry_http_requests_total",
+			Help: "HTTP requests served, by method, route template and status."}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "cry_http_request_duration_seconds",
+			Help:    "Latency of the HTTP requests, realtime streams excluded, by method, route template and status.",
+			Buckets: prometheus.DefBuckets}, []string{"method", "route", "status"}),
+		httpErrors: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "cry_http_errors_total",
+			Help: "HTTP error responses, by error code."}, []string{"code"}),
+		openStreams: prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "cry_realtime_open_streams",
+			Help: "Realtime streams currently open, by route template."}, []string{"route"}),
+
+		upstreamDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "cry_upstream_request_duration_seconds",
+			Help:    "Latency of the outbound calls to the upstream APIs, retries included, by provider.",
+			Buckets: prometheus.DefBuckets}, []string{"provider"}),
+		upstreamErrors: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "cry_upstream_errors_total",
+			Help: "Outbound calls that failed with a network error, a 429 or a 5xx response, by provider."}, []string{"provider"}),
+
+		signups: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "cry_signups_total",
+			Help: "Sign up attempts, by result."}, []string{"result"}),
+		verifications: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "cry_verifications_total",
+			Help: "Email verification attempts, by result."}, []string{"result"}),
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "cry_logins_total",
+			Help: "Sign in attempts, by result."}, []string{"result"}),
+		otpFailures: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "cry_otp_failures_total",
+			Help: "Rejected one-time passwords, by error code."}, []string{"code"}),
+		emails: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "cry_emails_total",
+			Help: "Emails handed to the SMTP server, by result."}, []string{"result"}),
+		rateLimited: prometheus.NewCounter(prometheus.CounterOpts{Name: "cry_rate_limit_rejections_total",
+			Help: "Requests rejected by the rate limiter."}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests, m.httpDuration, m.httpErrors, m.openStreams,
+		m.upstreamDuration, m.upstreamErrors,
+		m.signups, m.verifications, m.logins, m.otpFailures, m.emails, m.rateLimited,
+	)
+	return m
+}
+
+// Registry returns the registry the metrics are exposed from
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// Handler serves the metrics of the registry to Prometheus
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveRequest records a served HTTP request. route is the route template, such as
+// /api/v1/watchlist/:id, and code the error code of the response, if any. The realtime streams
+// are counted but left out of the latency histogram. The business events are derived from the
+// route and the outcome, so that the controllers are not instrumented.
+func (m *Metrics) ObserveRequest(method, route string, status int, code string, duration time.Duration) {
+	if route == "" {
+		route = UnmatchedRoute
+	}
+	statusLabel := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(method, route, statusLabel).Inc()
+	if !IsStream(route) {
+		m.httpDuration.WithLabelValues(method, route, statusLabel).Observe(duration.Seconds())
+	}
+
+	if code != "" {
+		m.httpErrors.WithLabelValues(code).Inc()
+	}
+	switch code {
+	case app_errors.CodeOTPInvalid, app_errors.CodeOTPExpired:
+		m.otpFailures.WithLabelValues(code).Inc()
+	case app_errors.CodeRateLimited:
+		m.rateLimited.Inc()
+	}
+
+	// Requests rejected before reaching the handler, by the rate limiter for instance, are not
+	// attempts of the event
+	if code == app_errors.CodeRateLimited || code == app_errors.CodeUnsupportedMediaType || code == app_errors.CodePayloadTooLarge {
+		return
+	}
+	switch method + " " + route {
+	case signupRoute:
+		m.signups.WithLabelValues(result(status)).Inc()
+	case verificationRoute:
+		m.verifications.WithLabelValues(result(status)).Inc()
+	case loginRoute:
+		m.logins.WithLabelValues(result(status)).Inc()
+	}
+}
+
+// ObserveUpstream records one outbound call to provider
+func (m *Metrics) ObserveUpstream(provider string, duration time.Duration, failed bool) {
+	m.upstreamDuration.WithLabelValues(provider).Observe(duration.Seconds())
+	if failed {
+		m.upstreamErrors.WithLabelValues(provider).Inc()
+	}
+}
+
+// OpenStream records a realtime stream opened on route, and returns the function recording
+// its closing
+func (m *Metrics) OpenStream(route string) func() {
+	gauge := m.openStreams.WithLabelValues(route)
+	gauge.Inc()
+	return gauge.Dec
+}
+
+// ObserveEmail records the outcome of sending an email
+func (m *Metrics) ObserveEmail(err error) {
+	if err != nil {
+		m.emails.WithLabelValues(ResultFailure).Inc()
+		return
+	}
+	m.emails.WithLabelValues(ResultSuccess).Inc()
+}
+
+// ObserveDatabase exposes the connection pool statistics of db, read on every scrape
+func (m *Metrics) ObserveDatabase(db DBStatsSource) {
+	m.registry.MustRegister(newDBStatsCollector(db))
+}
+
+func result(status int) string {
+	if status >= http.StatusBadRequest {
+		return ResultFailure
+	}
+	return ResultSuccess
+}
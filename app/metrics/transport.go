@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+)
+
+// InstrumentedTransport is an http.RoundTripper recording the latency, up to the response
+// headers, and the failures of every outbound call, retries included, per upstream provider
+type InstrumentedTransport struct {
+	next      http.RoundTripper
+	metrics   *Metrics
+	providers map[string]string
+}
+
+// NewInstrumentedTransport wraps next, which defaults to http.DefaultTransport. providers
+// names the provider of each URL host; the calls to other hosts are labelled by host.
+func NewInstrumentedTransport(next http.RoundTripper, m *Metrics, providers map[string]string) *InstrumentedTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &InstrumentedTransport{next: next, metrics: m, providers: providers}
+}
+
+// RoundTrip sends req through the wrapped transport and records the outcome
+func (t *InstrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start)
+
+	// A call the caller gave up on says nothing about the upstream
+	failed := err != nil && req.Context().Err() == nil
+	if err == nil {
+		failed = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+	}
+	t.metrics.ObserveUpstream(t.provider(req.URL.Host), elapsed, failed)
+
+	return resp, err
+}
+
+func (t *InstrumentedTransport) provider(host string) string {
+	if name, ok := t.providers[host]; ok {
+		return name
+	}
+	return host
+}
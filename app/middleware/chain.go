@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"cry-api/app/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaxRequestBodyBytes is the request size limit applied to every route
+const MaxRequestBodyBytes = 10 * 1024 * 1024 // 10MB
+
+// UseGlobal installs the middleware chain shared by every route, in order. RequestLoggerMiddleware
+// and RecoveryMiddleware replace gin's logger and recovery, which would log the access tokens of
+// the realtime streams and miss the request id.
+func UseGlobal(router *gin.Engine, m *metrics.Metrics) {
+	router.Use(RequestIDMiddleware())
+	router.Use(MetricsMiddleware(m))
+	router.Use(RecoveryMiddleware())
+	router.Use(ErrorHandler())
+	router.Use(SecurityMiddleware())
+	router.Use(RequestLoggerMiddleware())
+	router.Use(RateLimitMiddleware())
+	router.Use(ContentTypeMiddleware())
+	router.Use(RequestSizeMiddleware(MaxRequestBodyBytes))
+	router.Use(HealthCheckMiddleware())
+}
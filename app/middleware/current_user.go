@@ -0,0 +1,54 @@
+// Package middleware provides helpers to resolve the authenticated user of a request.
+package middleware
+
+import (
+	"cry-api/app/logger"
+	UserModel "cry-api/app/models"
+	services "cry-api/app/services/jwt"
+	app_errors "cry-api/app/types/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserLookup is the subset of the user service needed to resolve the authenticated user
+type UserLookup interface {
+	GetUserByUUID(uuid string) (*UserModel.User, error)
+}
+
+// CurrentUserClaims returns the JWT claims stored by JWTAuthMiddleware, or false when the
+// request is not authenticated.
+func CurrentUserClaims(c *gin.Context) (*services.Claims, bool) {
+	value, exists := c.Get("user")
+	if !exists {
+		return nil, false
+	}
+	claims, ok := value.(*services.Claims)
+	return claims, ok
+}
+
+// CurrentUser resolves the authenticated user from the JWT claims. On failure it aborts
+// the request with the matching error and returns nil.
+func CurrentUser(c *gin.Context, users UserLookup) *UserModel.User {
+	logger := logger.FromContext(c.Request.Context())
+
+	claims, ok := CurrentUserClaims(c)
+	if !ok {
+		logger.Warn("User claims not found in context")
+		AbortWithError(c, app_errors.NewUnauthorizedError("User not authenticated"))
+		return nil
+	}
+
+	user, err := users.GetUserByUUID(claims.UUID)
+	if err != nil {
+		logger.WithError(err).WithField("user_uuid", claims.UUID).Error("Failed to find user")
+		AbortWithError(c, app_errors.NewInternalServerError("User not found"))
+		return nil
+	}
+	if user == nil {
+		logger.WithField("user_uuid", claims.UUID).Error("User not found in database")
+		AbortWithError(c, app_errors.NewNotFoundError("user", "User not found"))
+		return nil
+	}
+
+	return user
+}
@@ -2,63 +2,61 @@
 package middleware
 
 import (
-	"log"
 	"net/http"
 
+	"cry-api/app/logger"
 	app_errors "cry-api/app/types/errors"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 )
 
+// errorCodeKey is the gin context key of the code of the error envelope sent
+const errorCodeKey = "error_code"
+
 // ErrorHandler provides centralized error handling middleware
 func ErrorHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Next()
 
-		if len(c.Errors) > 0 {
+		if len(c.Errors) > 0 && !c.Writer.Written() {
 			err := c.Errors.Last()
 			handleError(c, err.Err)
 		}
 	}
 }
 
-// handleError processes different types of errors and returns appropriate responses
+// handleError logs err with the request values and responds with the error envelope
+// describing it. Server errors are logged at error level and client errors at debug level, as
+// the access log already records them.
 func handleError(c *gin.Context, err error) {
-	log.Printf("Error occurred: %v", err)
-
-	switch e := err.(type) {
-	case *app_errors.ValidationError:
-		c.JSON(e.Code, gin.H{
-			"error": e.Message,
-			"field": e.Field,
-			"value": e.Value,
-		})
-	case *app_errors.NotFoundError:
-		c.JSON(e.Code, gin.H{
-			"error": e.Message,
-		})
-	case *app_errors.UnauthorizedError:
-		c.JSON(e.Code, gin.H{
-			"error": e.Message,
-		})
-	case *app_errors.ConflictError:
-		c.JSON(e.Code, gin.H{
-			"error": e.Message,
-		})
-	case *app_errors.InternalServerError:
-		c.JSON(e.Code, gin.H{
-			"error": e.Message,
-		})
-	case *app_errors.AppError:
-		c.JSON(e.Code, gin.H{
-			"error": e.Message,
-		})
-	default:
-		// Generic error handling
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Internal server error",
-		})
+	status, body := app_errors.Envelope(err)
+	entry := logger.FromContext(c.Request.Context()).WithError(err).WithFields(logrus.Fields{
+		"code":        body.Code,
+		"status_code": status,
+		"path":        c.FullPath(),
+	})
+	if status >= http.StatusInternalServerError {
+		entry.Error("Request failed")
+	} else {
+		entry.Debug("Request failed")
 	}
+	RespondWithError(c, err)
+}
+
+// RespondWithError aborts the request with the error envelope describing err. Middlewares use
+// it to respond at once; handlers use AbortWithError and let ErrorHandler respond.
+func RespondWithError(c *gin.Context, err error) {
+	status, body := app_errors.Envelope(err)
+	body.RequestID = RequestID(c)
+	c.Set(errorCodeKey, body.Code)
+	c.AbortWithStatusJSON(status, app_errors.ErrorResponse{Error: body})
+}
+
+// ErrorCode returns the code of the error envelope the request was answered with, or "" when
+// it succeeded
+func ErrorCode(c *gin.Context) string {
+	return c.GetString(errorCodeKey)
 }
 
 // AbortWithError aborts the request with a specific error
@@ -66,3 +64,13 @@ func AbortWithError(c *gin.Context, err error) {
 	_ = c.Error(err)
 	c.Abort()
 }
+
+// NoRoute responds to the requests matching no route with the error envelope
+func NoRoute(c *gin.Context) {
+	RespondWithError(c, app_errors.NewCodedError(http.StatusNotFound, app_errors.CodeRouteNotFound, "Route not found", ""))
+}
+
+// NoMethod responds to the requests whose method a route does not handle with the error envelope
+func NoMethod(c *gin.Context) {
+	RespondWithError(c, app_errors.NewCodedError(http.StatusMethodNotAllowed, app_errors.CodeMethodNotAllowed, "Method not allowed", ""))
+}
@@ -0,0 +1,45 @@
+// Package middleware provides the metrics instrumentation of the application.
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+
+	"cry-api/app/metrics"
+	app_errors "cry-api/app/types/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsMiddleware records every request by method, route template and status, along with
+// the business events derived from them. The realtime streams are tracked as open streams for as
+// long as they last. It must run outside ErrorHandler and RecoveryMiddleware to see the status
+// of the responses they write.
+func MetricsMiddleware(m *metrics.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		if route := c.FullPath(); metrics.IsStream(route) {
+			defer m.OpenStream(route)()
+		}
+
+		c.Next()
+
+		m.ObserveRequest(c.Request.Method, c.FullPath(), c.Writer.Status(), ErrorCode(c), time.Since(start))
+	}
+}
+
+// MetricsAuthMiddleware protects the metrics endpoint with the bearer token of the
+// configuration. An empty token rejects every request.
+func MetricsAuthMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			RespondWithError(c, app_errors.NewCodedError(http.StatusUnauthorized, app_errors.CodeUnauthorized, "Invalid metrics token", ""))
+			return
+		}
+
+		c.Next()
+	}
+}
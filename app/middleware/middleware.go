@@ -5,28 +5,31 @@ import (
 	"net/http"
 	"strings"
 
+	"cry-api/app/logger"
 	services "cry-api/app/services/jwt"
+	app_errors "cry-api/app/types/errors"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// AccessTokenParam is the query parameter carrying the JWT of the realtime streams
+const AccessTokenParam = "access_token"
+
 // JWTAuthMiddleware verifies JWT tokens in Authorization header.
 func JWTAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header missing"})
-			c.Abort()
+			RespondWithError(c, app_errors.NewCodedError(http.StatusUnauthorized, app_errors.CodeAuthMissingToken, "Authorization header missing", ""))
 			return
 		}
 
 		// Expect format: Bearer <token>
 		tokenParts := strings.SplitN(authHeader, " ", 2)
 		if len(tokenParts) != 2 || strings.ToLower(tokenParts[0]) != "bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header format must be Bearer {token}"})
-			c.Abort()
+			RespondWithError(c, app_errors.NewCodedError(http.StatusUnauthorized, app_errors.CodeAuthInvalidToken, "Authorization header format must be Bearer {token}", ""))
 			return
 		}
 
@@ -38,27 +41,53 @@ func JWTAuthMiddleware() gin.HandlerFunc {
 		})
 
 		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-			c.Abort()
+			RespondWithError(c, app_errors.ErrTokenValidation)
 			return
 		}
 
 		// Store claims in context for later use and enforce 2FA completion when enabled
 		claims, ok := token.Claims.(*services.Claims)
 		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
-			c.Abort()
+			RespondWithError(c, app_errors.NewCodedError(http.StatusUnauthorized, app_errors.CodeAuthInvalidToken, "Invalid token claims", ""))
 			return
 		}
 
 		if claims.TwoFAEnabled && !claims.TwoFAVerified {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Two-factor authentication required"})
-			c.Abort()
+			RespondWithError(c, app_errors.NewCodedError(http.StatusForbidden, app_errors.CodeAuthTwoFactorRequired, "Two-factor authentication required", ""))
 			return
 		}
 
 		c.Set("user", claims)
+		c.Request = c.Request.WithContext(logger.WithUserUUID(c.Request.Context(), claims.UUID))
 
 		c.Next()
 	}
 }
+
+// OptionalJWTAuthMiddleware authenticates requests that carry an Authorization header and
+// lets anonymous requests through, so public routes can personalize their responses.
+func OptionalJWTAuthMiddleware() gin.HandlerFunc {
+	authenticate := JWTAuthMiddleware()
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		authenticate(c)
+	}
+}
+
+// StreamJWTAuthMiddleware authenticates the realtime streams. Browsers cannot set headers on
+// EventSource and WebSocket requests, so without an Authorization header the token is read
+// from the access_token query parameter.
+func StreamJWTAuthMiddleware() gin.HandlerFunc {
+	authenticate := JWTAuthMiddleware()
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			if token := c.Query(AccessTokenParam); token != "" {
+				c.Request.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		authenticate(c)
+	}
+}
@@ -0,0 +1,44 @@
+// Package middleware provides panic recovery for the application.
+package middleware
+
+import (
+	"net/http"
+	"runtime/debug"
+
+	"cry-api/app/logger"
+	app_errors "cry-api/app/types/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RecoveryMiddleware recovers from the panics of the handlers, logs them with their stack
+// through the structured logger and answers with the internal error envelope. It replaces
+// gin.Recovery, which writes plain text to stderr without the request id.
+func RecoveryMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// net/http uses this panic to abort a response on purpose, let it through
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			logger.FromContext(c.Request.Context()).
+				WithField("panic", recovered).
+				WithField("path", c.Request.URL.Path).
+				WithField("stack", string(debug.Stack())).
+				Error("Recovered from panic")
+
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			RespondWithError(c, app_errors.NewInternalServerError(""))
+		}()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,62 @@
+// Package middleware provides request id correlation for the application.
+package middleware
+
+import (
+	"cry-api/app/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader is the header carrying the id of a request
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey is the gin context key of the request id
+const requestIDKey = "request_id"
+
+// maxRequestIDLength bounds the ids accepted from clients
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware gives every request an id, taken from the X-Request-ID header when the
+// client sent a valid one and generated otherwise. The id is echoed in the response header,
+// stored on the gin context and carried by the request context, so that the logger, the
+// services and the outbound HTTP calls tag their work with it.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.New().String()
+		}
+
+		c.Set(requestIDKey, id)
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), id))
+
+		c.Next()
+	}
+}
+
+// RequestID returns the id of the request, as set by RequestIDMiddleware or sent by the client
+func RequestID(c *gin.Context) string {
+	if id := c.GetString(requestIDKey); id != "" {
+		return id
+	}
+	return c.GetHeader(RequestIDHeader)
+}
+
+// validRequestID reports whether a client supplied id is short and only made of letters,
+// digits and the - _ . : separators, so that it can be logged and echoed safely
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -2,10 +2,14 @@
 package middleware
 
 import (
-	"fmt"
+	"mime"
 	"net/http"
+	"net/url"
 	"time"
 
+	"cry-api/app/logger"
+	app_errors "cry-api/app/types/errors"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -27,7 +31,8 @@ func SecurityMiddleware() gin.HandlerFunc {
 	}
 }
 
-// RequestLoggerMiddleware logs HTTP requests with timing information
+// RequestLoggerMiddleware logs HTTP requests with timing information through the structured
+// logger, tagged with the request id and, once authenticated, the user UUID
 func RequestLoggerMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
@@ -37,28 +42,28 @@ func RequestLoggerMiddleware() gin.HandlerFunc {
 		// Process request
 		c.Next()
 
-		// Calculate request duration
-		latency := time.Since(start)
-
-		// Get client IP
-		clientIP := c.ClientIP()
-		method := c.Request.Method
-		statusCode := c.Writer.Status()
-
 		if raw != "" {
-			path = path + "?" + raw
+			path = path + "?" + redactQuery(raw)
 		}
 
-		// Log request details
-		_, _ = fmt.Fprintf(gin.DefaultWriter, "[GIN] %v | %3d | %13v | %15s | %-7s %#v\n",
-			time.Now().Format("2006/01/02 - 15:04:05"),
-			statusCode,
-			latency,
-			clientIP,
-			method,
-			path,
-		)
+		// The authentication middlewares replace the request, so its context now carries the
+		// user UUID too
+		logger.GetLogger().LogRequest(c.Request.Context(), c.Request.Method, path, c.ClientIP(), c.Writer.Status(), time.Since(start))
+	}
+}
+
+// redactQuery hides the access token the realtime streams accept in their query. A query
+// that does not parse is dropped whole, as the token could hide anywhere in it.
+func redactQuery(raw string) string {
+	query, err := url.ParseQuery(raw)
+	if err != nil {
+		return "REDACTED"
 	}
+	if !query.Has(AccessTokenParam) {
+		return raw
+	}
+	query.Set(AccessTokenParam, "REDACTED")
+	return query.Encode()
 }
 
 // RateLimitMiddleware provides basic rate limiting (simple implementation)
@@ -83,10 +88,7 @@ func RateLimitMiddleware() gin.HandlerFunc {
 
 		// Check rate limit (100 requests per minute)
 		if len(clients[clientIP]) >= 100 {
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error": "Rate limit exceeded. Try again later.",
-			})
-			c.Abort()
+			RespondWithError(c, app_errors.NewAppError(http.StatusTooManyRequests, "Rate limit exceeded. Try again later.", ""))
 			return
 		}
 
@@ -97,16 +99,22 @@ func RateLimitMiddleware() gin.HandlerFunc {
 	}
 }
 
+// allowedContentTypes are the media types accepted for request bodies. Besides JSON the
+// label import takes BIP-329 JSON Lines, raw or as a multipart file upload.
+var allowedContentTypes = map[string]bool{
+	"application/json":     true,
+	"application/jsonl":    true,
+	"application/x-ndjson": true,
+	"multipart/form-data":  true,
+}
+
 // ContentTypeMiddleware validates content type for POST/PUT requests
 func ContentTypeMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if c.Request.Method == "POST" || c.Request.Method == "PUT" || c.Request.Method == "PATCH" {
-			contentType := c.GetHeader("Content-Type")
-			if contentType != "application/json" {
-				c.JSON(http.StatusUnsupportedMediaType, gin.H{
-					"error": "Content-Type must be application/json",
-				})
-				c.Abort()
+			mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+			if err != nil || !allowedContentTypes[mediaType] {
+				RespondWithError(c, app_errors.NewAppError(http.StatusUnsupportedMediaType, "Content-Type must be application/json, application/jsonl or multipart/form-data", ""))
 				return
 			}
 		}
@@ -118,10 +126,7 @@ func ContentTypeMiddleware() gin.HandlerFunc {
 func RequestSizeMiddleware(maxSize int64) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if c.Request.ContentLength > maxSize {
-			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
-				"error": "Request body too large",
-			})
-			c.Abort()
+			RespondWithError(c, app_errors.NewAppError(http.StatusRequestEntityTooLarge, "Request body too large", ""))
 			return
 		}
 		c.Next()
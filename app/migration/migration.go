@@ -14,8 +14,8 @@ func main() {
 		log.Fatal("Database connection failed: ", err)
 	}
 
-	// Run AutoMigrate for the User and UserToken models
-	err = dbConn.AutoMigrate(&UserModel.User{}, &UserModel.UserToken{})
+	// Run AutoMigrate for the User, UserToken, WatchedWallet, TransferTag, Label, CacheEntry, BroadcastAudit, Notification, WalletCursor, FearGreedIndex, PriceHistory, AlertRule and AlertTrigger models
+	err = dbConn.AutoMigrate(&UserModel.User{}, &UserModel.UserToken{}, &UserModel.WatchedWallet{}, &UserModel.TransferTag{}, &UserModel.Label{}, &UserModel.CacheEntry{}, &UserModel.BroadcastAudit{}, &UserModel.Notification{}, &UserModel.WalletCursor{}, &UserModel.FearGreedIndex{}, &UserModel.PriceHistory{}, &UserModel.AlertRule{}, &UserModel.AlertTrigger{})
 	if err != nil {
 		log.Fatal("Auto-migration failed: ", err)
 	}
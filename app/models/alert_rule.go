@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"cry-api/app/money"
+)
+
+// Alert rule conditions
+const (
+	AlertConditionPriceAbove              = "price_above"
+	AlertConditionPriceBelow              = "price_below"
+	AlertConditionPercentChange           = "percent_change"
+	AlertConditionFearGreedAbove          = "fear_greed_above"
+	AlertConditionFearGreedBelow          = "fear_greed_below"
+	AlertConditionFearGreedClassification = "fear_greed_classification"
+)
+
+// Alert rule modes
+const (
+	AlertModeOnce      = "once"      // the rule is paused after firing
+	AlertModeRecurring = "recurring" // the rule fires again once its cooldown is over
+)
+
+// AlertRule is a user's alert on market data, e.g. BTC above a price or the fear and greed
+// index below a value. Symbol and Currency apply to the price conditions, Window to the
+// percent change, and Classification to the classification change, where it is the
+// classification to wait for (any change when empty).
+type AlertRule struct {
+	ID                 int        `json:"id"`
+	UserID             int        `json:"user_id" gorm:"not null;index"`
+	Condition          string     `json:"condition" gorm:"type:varchar(32);not null"`
+	Symbol             string     `json:"symbol,omitempty" gorm:"type:varchar(20)"`
+	Currency           string     `json:"currency,omitempty" gorm:"type:varchar(3)"`
+	Threshold          string     `json:"threshold,omitempty" gorm:"type:varchar(40)"` // decimal price, signed percent change or index value
+	Window             string     `json:"window,omitempty" gorm:"type:varchar(3)"`     // "1h", "24h" or "7d"
+	Classification     string     `json:"classification,omitempty" gorm:"type:varchar(32)"`
+	Mode               string     `json:"mode" gorm:"type:varchar(16);not null"`
+	CooldownMinutes    int        `json:"cooldown_minutes" gorm:"not null"`
+	Active             bool       `json:"active" gorm:"not null;index"`
+	LastClassification string     `json:"last_classification,omitempty" gorm:"type:varchar(32)"` // classification seen by the last check
+	LastTriggeredAt    *time.Time `json:"last_triggered_at" gorm:"type:timestamp;default:NULL"`
+	CreatedAt          time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt          time.Time  `json:"updated_at" gorm:"type:timestamp;default:NULL;autoUpdateTime"`
+
+	Fiat *money.Amount `json:"fiat,omitempty" gorm:"-"` // threshold of a price rule in the user's fiat currency, filled in by the API
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"cry-api/app/money"
+)
+
+// AlertTrigger is an entry of the history of a user's alerts: a rule firing, with the
+// market data that triggered it. It is kept when its rule is deleted.
+type AlertTrigger struct {
+	ID          int        `json:"id"`
+	AlertRuleID int        `json:"alert_rule_id" gorm:"not null;index"`
+	UserID      int        `json:"user_id" gorm:"not null;index"`
+	Condition   string     `json:"condition" gorm:"type:varchar(32);not null"`
+	Title       string     `json:"title" gorm:"type:varchar(255);not null"`
+	Message     string     `json:"message" gorm:"type:text;not null"`
+	Value       string     `json:"value" gorm:"type:varchar(40)"`             // decimal observed price, percent change or index value
+	Currency    string     `json:"currency,omitempty" gorm:"type:varchar(3)"` // currency of the observed price
+	Source      string     `json:"source,omitempty" gorm:"type:varchar(32)"`
+	EmailedAt   *time.Time `json:"emailed_at,omitempty" gorm:"type:timestamp;default:NULL"`
+	TriggeredAt time.Time  `json:"triggered_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+
+	Fiat *money.Amount `json:"fiat,omitempty" gorm:"-"` // observed price in the user's fiat currency, filled in by the API
+}
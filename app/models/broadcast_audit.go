@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// Outcomes of a broadcast request
+const (
+	BroadcastStatusBroadcast = "broadcast"
+	BroadcastStatusRejected  = "rejected" // refused by local validation or by the provider
+	BroadcastStatusFailed    = "failed"   // no provider could be reached
+)
+
+// BroadcastAudit records a user's request to broadcast a signed transaction and its outcome
+type BroadcastAudit struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id" gorm:"not null;index"`
+	TxID      string    `json:"txid" gorm:"type:varchar(64);index"`
+	RawTx     string    `json:"raw_tx" gorm:"type:text;not null"`
+	Status    string    `json:"status" gorm:"type:varchar(16);not null"`
+	Provider  string    `json:"provider,omitempty" gorm:"type:varchar(32)"`
+	FeeRate   float64   `json:"fee_rate,omitempty"` // sat/vB
+	Error     string    `json:"error,omitempty" gorm:"type:varchar(512)"`
+	IPAddress string    `json:"ip_address,omitempty" gorm:"type:varchar(45)"`
+	CreatedAt time.Time `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+// Bounds of a cache entry: the length of the key column, and the largest value in bytes. The
+// cache does not store larger values, they are served uncached.
+const (
+	MaxCacheKeyLength = 255
+	MaxCacheValueSize = 1 << 20
+)
+
+// CacheEntry is a cached upstream API response, used by the SQL cache backend
+type CacheEntry struct {
+	Key        string    `gorm:"primaryKey;type:varchar(255)"`
+	Value      []byte    `gorm:"type:longblob;not null"`
+	ExpiresAt  time.Time `gorm:"not null"`
+	StaleUntil time.Time `gorm:"not null;index"`
+	UpdatedAt  time.Time
+}
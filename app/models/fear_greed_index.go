@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// FearGreedIndex is a daily value of the fear and greed index, synced from the market data
+// providers so that range queries do not spend API credits
+type FearGreedIndex struct {
+	ID             int       `json:"-"`
+	Date           string    `json:"date" gorm:"type:varchar(10);not null;uniqueIndex"` // YYYY-MM-DD (UTC)
+	Value          int       `json:"value" gorm:"not null"`
+	Classification string    `json:"classification" gorm:"type:varchar(32);not null"`
+	Source         string    `json:"source" gorm:"type:varchar(32)"` // market data provider the value was synced from
+	CreatedAt      time.Time `json:"-" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt      time.Time `json:"-" gorm:"type:timestamp;default:NULL;autoUpdateTime"`
+}
+
+// TableName keeps the table name singular, as the index is a single series
+func (FearGreedIndex) TableName() string {
+	return "fear_greed_index"
+}
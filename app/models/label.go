@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+)
+
+// BIP-329 label types
+const (
+	LabelTypeTx     = "tx"
+	LabelTypeAddr   = "addr"
+	LabelTypePubkey = "pubkey"
+	LabelTypeInput  = "input"
+	LabelTypeOutput = "output"
+	LabelTypeXPUB   = "xpub"
+)
+
+// Label is a user's BIP-329 label on a transaction, address, public key, input, output or
+// extended public key. Ref holds the txid, address, key or "txid:index" outpoint.
+type Label struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id" gorm:"not null;uniqueIndex:idx_labels_user_type_ref"`
+	Type      string    `json:"type" gorm:"type:varchar(8);not null;uniqueIndex:idx_labels_user_type_ref"`
+	Ref       string    `json:"ref" gorm:"type:varchar(255);not null;uniqueIndex:idx_labels_user_type_ref"`
+	Label     string    `json:"label" gorm:"type:varchar(255)"`
+	Origin    string    `json:"origin,omitempty" gorm:"type:varchar(255)"`
+	Spendable *bool     `json:"spendable,omitempty"` // outputs only
+	CreatedAt time.Time `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
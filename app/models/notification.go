@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+// Notification types emitted by the watchlist watcher
+const (
+	NotificationTypeIncoming      = "incoming"
+	NotificationTypeOutgoing      = "outgoing"
+	NotificationTypeConfirmed     = "confirmed"
+	NotificationTypeLargeMovement = "large_movement"
+)
+
+// Notification is an entry of a user's in-app notification feed about a watched wallet. A
+// wallet gets at most one notification of each type per transaction, which keeps alerts from
+// being sent twice.
+type Notification struct {
+	ID              int        `json:"id"`
+	UserID          int        `json:"user_id" gorm:"not null;index"`
+	WatchedWalletID int        `json:"watched_wallet_id" gorm:"not null;uniqueIndex:idx_notifications_wallet_tx_type"`
+	TxID            string     `json:"txid" gorm:"type:varchar(64);not null;uniqueIndex:idx_notifications_wallet_tx_type"`
+	Type            string     `json:"type" gorm:"type:varchar(16);not null;uniqueIndex:idx_notifications_wallet_tx_type"`
+	Title           string     `json:"title" gorm:"type:varchar(255);not null"`
+	Message         string     `json:"message" gorm:"type:text;not null"`
+	Amount          float64    `json:"amount"` // balance change of the wallet in BTC
+	BlockHeight     int        `json:"block_height"`
+	ReadAt          *time.Time `json:"read_at" gorm:"type:timestamp;default:NULL"`
+	EmailedAt       *time.Time `json:"emailed_at,omitempty" gorm:"type:timestamp;default:NULL"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
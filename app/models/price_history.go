@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// PriceHistory is a daily closing price of an asset in a fiat currency, synced from the market
+// data providers so that analytics over long ranges do not spend API credits
+type PriceHistory struct {
+	ID        int       `json:"-"`
+	Symbol    string    `json:"symbol" gorm:"type:varchar(16);not null;uniqueIndex:idx_price_history_day"`
+	Currency  string    `json:"currency" gorm:"type:varchar(3);not null;uniqueIndex:idx_price_history_day"`
+	Date      string    `json:"date" gorm:"type:varchar(10);not null;uniqueIndex:idx_price_history_day"` // YYYY-MM-DD (UTC)
+	Price     float64   `json:"price" gorm:"not null"`
+	CreatedAt time.Time `json:"-" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time `json:"-" gorm:"type:timestamp;default:NULL;autoUpdateTime"`
+}
+
+// TableName keeps the table name singular, as each asset and currency is a single series
+func (PriceHistory) TableName() string {
+	return "price_history"
+}
@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+)
+
+// TransferTag marks a transaction as a transfer between wallets the user owns, so that
+// tax reports do not treat it as a disposal
+type TransferTag struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id" gorm:"not null;uniqueIndex:idx_transfer_tags_user_txid"`
+	TxID      string    `json:"txid" gorm:"type:varchar(64);not null;uniqueIndex:idx_transfer_tags_user_txid"`
+	Note      string    `json:"note" gorm:"type:varchar(255)"`
+	CreatedAt time.Time `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
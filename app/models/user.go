@@ -16,9 +16,13 @@ type User struct {
 	IsVerified   bool      `json:"is_verified" gorm:"not null;default:false"`
 	TwoFASecret  *string   `json:"two_fa_secret,omitempty" gorm:"column:two_fa_secret"`
 	TwoFAEnabled bool      `json:"two_fa_enabled" gorm:"not null;default:false"`
+	FiatCurrency string    `json:"fiat_currency" gorm:"type:varchar(3);not null;default:'USD'"` // ISO-4217 code money values are converted to
 	CreatedAt    time.Time `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
 	UpdatedAt    time.Time `json:"updated_at" gorm:"type:timestamp;default:NULL;autoUpdateTime"`
 
 	// Relations
-	Tokens []UserToken `json:"tokens" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
+	Tokens         []UserToken     `json:"tokens" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
+	WatchedWallets []WatchedWallet `json:"watched_wallets,omitempty" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
+	TransferTags   []TransferTag   `json:"transfer_tags,omitempty" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
+	Labels         []Label         `json:"labels,omitempty" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
 }
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+)
+
+// WalletCursor records how far the watchlist watcher has processed a watched wallet. A
+// transaction of the recent activity is new when it is not one of LastTxIDs, and it reaches
+// its confirmation target when the tip moves past TipHeight.
+type WalletCursor struct {
+	ID              int       `json:"id"`
+	WatchedWalletID int       `json:"watched_wallet_id" gorm:"not null;uniqueIndex"`
+	TipHeight       int       `json:"tip_height"`                   // chain tip at the last check
+	LastTxIDs       string    `json:"last_tx_ids" gorm:"type:text"` // comma-separated txids of the recent activity at the last check
+	CheckedAt       time.Time `json:"checked_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
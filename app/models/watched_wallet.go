@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+// Watched wallet kinds
+const (
+	WatchedWalletKindAddress    = "address"
+	WatchedWalletKindXPUB       = "xpub"
+	WatchedWalletKindDescriptor = "descriptor"
+)
+
+// WatchedWallet represents an address, extended public key or output descriptor
+// a user keeps on their watchlist
+type WatchedWallet struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id" gorm:"not null;index"`
+	Kind      string    `json:"kind" gorm:"type:varchar(16);not null"` // "address", "xpub" or "descriptor"
+	Value     string    `json:"value" gorm:"type:text;not null"`
+	Label     string    `json:"label" gorm:"type:varchar(100);not null"`
+	Color     string    `json:"color" gorm:"type:varchar(7);not null;default:'#8b5cf6'"`
+	CreatedAt time.Time `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"type:timestamp;default:NULL;autoUpdateTime"`
+}
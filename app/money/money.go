@@ -0,0 +1,217 @@
+// Package money provides exact fiat amounts. Amounts are counted in the minor units of their
+// ISO-4217 currency (cents, yen, fils...) and computed with rational numbers, so no float64
+// rounding error ever reaches a money value.
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the fiat currency used when a user has not chosen one
+const DefaultCurrency = "USD"
+
+// SatoshisPerBitcoin is the number of satoshis in one bitcoin
+const SatoshisPerBitcoin = 100_000_000
+
+// minorUnits maps the supported ISO-4217 currencies to their number of decimal digits. They
+// are the fiat currencies the market data providers quote prices in.
+var minorUnits = map[string]int{
+	"AED": 2, "ARS": 2, "AUD": 2, "BDT": 2, "BHD": 3, "BRL": 2, "CAD": 2, "CHF": 2,
+	"CLP": 0, "CNY": 2, "CZK": 2, "DKK": 2, "EUR": 2, "GBP": 2, "HKD": 2, "HUF": 2,
+	"IDR": 2, "ILS": 2, "INR": 2, "JPY": 0, "KRW": 0, "KWD": 3, "MXN": 2, "MYR": 2,
+	"NGN": 2, "NOK": 2, "NZD": 2, "PHP": 2, "PKR": 2, "PLN": 2, "RUB": 2, "SAR": 2,
+	"SEK": 2, "SGD": 2, "THB": 2, "TRY": 2, "TWD": 2, "UAH": 2, "USD": 2, "VND": 0,
+	"ZAR": 2,
+}
+
+// decimalPattern matches a plain decimal number, without exponent, fraction or base prefix
+var decimalPattern = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)$`)
+
+// ErrUnsupportedCurrency is returned for a currency outside of the supported ISO-4217 codes
+var ErrUnsupportedCurrency = errors.New("unsupported currency")
+
+// Amount is an exact amount of a fiat currency. Value is the decimal amount with exactly as
+// many decimals as the currency has minor units, and MinorUnits the same amount counted in
+// minor units, e.g. "1234.56" and 123456 for EUR, "1235" and 1235 for JPY.
+type Amount struct {
+	Currency   string `json:"currency"`
+	Value      string `json:"amount"`
+	MinorUnits int64  `json:"minor_units"`
+}
+
+// IsSupported reports whether currency is a supported ISO-4217 code, in upper case
+func IsSupported(currency string) bool {
+	_, ok := minorUnits[currency]
+	return ok
+}
+
+// Currencies returns the supported ISO-4217 codes, sorted
+func Currencies() []string {
+	codes := make([]string, 0, len(minorUnits))
+	for code := range minorUnits {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Exponent returns the number of minor unit digits of a supported currency
+func Exponent(currency string) (int, error) {
+	exp, ok := minorUnits[currency]
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnsupportedCurrency, currency)
+	}
+	return exp, nil
+}
+
+// FromMinor returns the amount of minor units of a currency
+func FromMinor(minor int64, currency string) (Amount, error) {
+	exp, err := Exponent(currency)
+	if err != nil {
+		return Amount{}, err
+	}
+	return Amount{Currency: currency, Value: formatMinor(big.NewInt(minor), exp), MinorUnits: minor}, nil
+}
+
+// FromRat rounds an exact value to the minor units of a currency, half away from zero
+func FromRat(value *big.Rat, currency string) (Amount, error) {
+	exp, err := Exponent(currency)
+	if err != nil {
+		return Amount{}, err
+	}
+
+	scaled := new(big.Rat).Mul(value, new(big.Rat).SetInt(pow10(exp)))
+	minor := roundHalfAwayFromZero(scaled)
+	if !minor.IsInt64() {
+		return Amount{}, fmt.Errorf("amount of %s out of range", currency)
+	}
+	return Amount{Currency: currency, Value: formatMinor(minor, exp), MinorUnits: minor.Int64()}, nil
+}
+
+// Rat returns the exact value of the amount
+func (a Amount) Rat() *big.Rat {
+	exp, err := Exponent(a.Currency)
+	if err != nil {
+		exp = 0
+	}
+	return new(big.Rat).SetFrac(big.NewInt(a.MinorUnits), pow10(exp))
+}
+
+// ParseDecimal parses a decimal string such as "0.00012" exactly
+func ParseDecimal(s string) (*big.Rat, error) {
+	s = strings.TrimSpace(s)
+	if !decimalPattern.MatchString(s) {
+		return nil, fmt.Errorf("invalid decimal %q", s)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, fmt.Errorf("invalid decimal %q", s)
+	}
+	return r, nil
+}
+
+// FromFloat returns the decimal a float64 reads as, its shortest representation. Upstream
+// APIs send prices and balances as JSON numbers: reading them back as the decimal they were
+// written as, rather than as the binary fraction a float64 holds, keeps the arithmetic exact.
+func FromFloat(f float64) *big.Rat {
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(f, 'f', -1, 64))
+	if !ok {
+		return new(big.Rat)
+	}
+	return r
+}
+
+// FromNumber reads a JSON number exactly, exponent included
+func FromNumber(n json.Number) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(n.String())
+	if !ok {
+		return nil, fmt.Errorf("invalid number %q", n)
+	}
+	return r, nil
+}
+
+// Decimal reads a JSON number exactly and writes it as a plain decimal, e.g. 6.1e4 as
+// "61000". A number the upstream left out or sent as null reads as "0".
+func Decimal(n json.Number) (string, error) {
+	if n == "" {
+		return "0", nil
+	}
+	r, err := FromNumber(n)
+	if err != nil {
+		return "", err
+	}
+	return FormatDecimal(r), nil
+}
+
+// Decimals reads JSON numbers with Decimal, in order
+func Decimals(numbers ...json.Number) ([]string, error) {
+	decimals := make([]string, len(numbers))
+	for i, n := range numbers {
+		d, err := Decimal(n)
+		if err != nil {
+			return nil, err
+		}
+		decimals[i] = d
+	}
+	return decimals, nil
+}
+
+// Sats returns the exact amount of bitcoin of a number of satoshis
+func Sats(sats int64) *big.Rat {
+	return big.NewRat(sats, SatoshisPerBitcoin)
+}
+
+// maxDecimals bounds the digits FormatDecimal writes after the decimal point
+const maxDecimals = 18
+
+// FormatDecimal writes an exact value as a plain decimal without trailing zeros, e.g.
+// "65000.5". A value whose expansion does not end within 18 decimals is rounded there.
+func FormatDecimal(r *big.Rat) string {
+	scaled := new(big.Rat)
+	for n := 0; n < maxDecimals; n++ {
+		if scaled.Mul(r, new(big.Rat).SetInt(pow10(n))).IsInt() {
+			return r.FloatString(n)
+		}
+	}
+	s := strings.TrimRight(r.FloatString(maxDecimals), "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+func pow10(exp int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil)
+}
+
+func roundHalfAwayFromZero(r *big.Rat) *big.Int {
+	num := new(big.Int).Abs(r.Num())
+	den := r.Denom()
+
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Lsh(rem, 1).Cmp(den) >= 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	if r.Sign() < 0 {
+		quo.Neg(quo)
+	}
+	return quo
+}
+
+func formatMinor(minor *big.Int, exp int) string {
+	digits := new(big.Int).Abs(minor).String()
+	if exp > 0 {
+		if len(digits) <= exp {
+			digits = strings.Repeat("0", exp-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+	}
+	if minor.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
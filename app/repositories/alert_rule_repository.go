@@ -0,0 +1,93 @@
+// Package repositorie provides methods for interacting with alert rules.
+package repositorie
+
+import (
+	"fmt"
+
+	UserModel "cry-api/app/models"
+
+	"gorm.io/gorm"
+)
+
+// AlertRuleRepository defines methods for interacting with the users' alert rules.
+type AlertRuleRepository interface {
+	// Save persists an alert rule.
+	Save(rule *UserModel.AlertRule) error
+
+	// FindByUserID retrieves all alert rules of a user.
+	FindByUserID(userID int) ([]UserModel.AlertRule, error)
+
+	// FindByIDAndUserID retrieves a single alert rule owned by the given user.
+	FindByIDAndUserID(id, userID int) (*UserModel.AlertRule, error)
+
+	// FindActive retrieves the active alert rules of every user.
+	FindActive() ([]UserModel.AlertRule, error)
+
+	// CountByUserID returns the number of alert rules of a user.
+	CountByUserID(userID int) (int64, error)
+
+	// Delete removes an alert rule owned by the given user and reports whether it existed.
+	Delete(id, userID int) (bool, error)
+}
+
+// GormAlertRuleRepository implements AlertRuleRepository using GORM
+type GormAlertRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewGormAlertRuleRepository returns a new GormAlertRuleRepository
+func NewGormAlertRuleRepository(db *gorm.DB) *GormAlertRuleRepository {
+	return &GormAlertRuleRepository{db: db}
+}
+
+// Save inserts or updates an alert rule
+func (repo *GormAlertRuleRepository) Save(rule *UserModel.AlertRule) error {
+	return repo.db.Save(rule).Error
+}
+
+// FindByUserID retrieves the alert rules of a user, oldest first
+func (repo *GormAlertRuleRepository) FindByUserID(userID int) ([]UserModel.AlertRule, error) {
+	var rules []UserModel.AlertRule
+	if err := repo.db.Where("user_id = ?", userID).Order("id ASC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// FindByIDAndUserID retrieves an alert rule by ID scoped to its owner
+func (repo *GormAlertRuleRepository) FindByIDAndUserID(id, userID int) (*UserModel.AlertRule, error) {
+	var rule UserModel.AlertRule
+	err := repo.db.Where("id = ? AND user_id = ?", id, userID).First(&rule).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// FindActive retrieves every active alert rule
+func (repo *GormAlertRuleRepository) FindActive() ([]UserModel.AlertRule, error) {
+	var rules []UserModel.AlertRule
+	if err := repo.db.Where("active = ?", true).Order("id ASC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// CountByUserID counts the alert rules of a user
+func (repo *GormAlertRuleRepository) CountByUserID(userID int) (int64, error) {
+	var count int64
+	err := repo.db.Model(&UserModel.AlertRule{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// Delete removes an alert rule scoped to its owner
+func (repo *GormAlertRuleRepository) Delete(id, userID int) (bool, error) {
+	result := repo.db.Where("id = ? AND user_id = ?", id, userID).Delete(&UserModel.AlertRule{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete alert rule: %v", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,55 @@
+// Package repositorie provides methods for interacting with the alert history.
+package repositorie
+
+import (
+	UserModel "cry-api/app/models"
+
+	"gorm.io/gorm"
+)
+
+// AlertTriggerRepository defines methods for interacting with the history of triggered alerts.
+type AlertTriggerRepository interface {
+	// Create inserts a trigger.
+	Create(trigger *UserModel.AlertTrigger) error
+
+	// Save updates a trigger.
+	Save(trigger *UserModel.AlertTrigger) error
+
+	// FindByUserID retrieves the latest triggers of a user, newest first, optionally only the
+	// ones of a rule (ruleID > 0).
+	FindByUserID(userID, ruleID, limit int) ([]UserModel.AlertTrigger, error)
+}
+
+// GormAlertTriggerRepository implements AlertTriggerRepository using GORM
+type GormAlertTriggerRepository struct {
+	db *gorm.DB
+}
+
+// NewGormAlertTriggerRepository returns a new GormAlertTriggerRepository
+func NewGormAlertTriggerRepository(db *gorm.DB) *GormAlertTriggerRepository {
+	return &GormAlertTriggerRepository{db: db}
+}
+
+// Create inserts a trigger
+func (repo *GormAlertTriggerRepository) Create(trigger *UserModel.AlertTrigger) error {
+	return repo.db.Create(trigger).Error
+}
+
+// Save updates a trigger
+func (repo *GormAlertTriggerRepository) Save(trigger *UserModel.AlertTrigger) error {
+	return repo.db.Save(trigger).Error
+}
+
+// FindByUserID retrieves the latest triggers of a user
+func (repo *GormAlertTriggerRepository) FindByUserID(userID, ruleID, limit int) ([]UserModel.AlertTrigger, error) {
+	query := repo.db.Where("user_id = ?", userID)
+	if ruleID > 0 {
+		query = query.Where("alert_rule_id = ?", ruleID)
+	}
+
+	var triggers []UserModel.AlertTrigger
+	if err := query.Order("id DESC").Limit(limit).Find(&triggers).Error; err != nil {
+		return nil, err
+	}
+	return triggers, nil
+}
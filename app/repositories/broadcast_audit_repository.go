@@ -0,0 +1,41 @@
+// Package repositorie provides methods for interacting with the broadcast audit trail.
+package repositorie
+
+import (
+	UserModel "cry-api/app/models"
+
+	"gorm.io/gorm"
+)
+
+// BroadcastAuditRepository defines methods for interacting with the broadcast audit trail.
+type BroadcastAuditRepository interface {
+	// Save persists an audit record.
+	Save(audit *UserModel.BroadcastAudit) error
+
+	// FindByUserID retrieves the audit records of a user, newest first.
+	FindByUserID(userID int) ([]UserModel.BroadcastAudit, error)
+}
+
+// GormBroadcastAuditRepository implements BroadcastAuditRepository using GORM
+type GormBroadcastAuditRepository struct {
+	db *gorm.DB
+}
+
+// NewGormBroadcastAuditRepository returns a new GormBroadcastAuditRepository
+func NewGormBroadcastAuditRepository(db *gorm.DB) *GormBroadcastAuditRepository {
+	return &GormBroadcastAuditRepository{db: db}
+}
+
+// Save inserts an audit record
+func (repo *GormBroadcastAuditRepository) Save(audit *UserModel.BroadcastAudit) error {
+	return repo.db.Create(audit).Error
+}
+
+// FindByUserID retrieves the audit records of a user, newest first
+func (repo *GormBroadcastAuditRepository) FindByUserID(userID int) ([]UserModel.BroadcastAudit, error) {
+	var audits []UserModel.BroadcastAudit
+	if err := repo.db.Where("user_id = ?", userID).Order("id DESC").Find(&audits).Error; err != nil {
+		return nil, err
+	}
+	return audits, nil
+}
@@ -0,0 +1,87 @@
+// Package repositorie provides methods for interacting with the local fear and greed history.
+package repositorie
+
+import (
+	UserModel "cry-api/app/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FearGreedRepository defines methods for interacting with the local fear and greed history.
+type FearGreedRepository interface {
+	// Upsert stores daily values, replacing the values already stored for the same days.
+	Upsert(points []UserModel.FearGreedIndex) error
+
+	// FindRange retrieves the values between two YYYY-MM-DD days (inclusive), oldest first.
+	FindRange(from, to string) ([]UserModel.FearGreedIndex, error)
+
+	// Latest retrieves the most recent value, nil when the history is empty.
+	Latest() (*UserModel.FearGreedIndex, error)
+
+	// Earliest retrieves the oldest value, nil when the history is empty.
+	Earliest() (*UserModel.FearGreedIndex, error)
+
+	// Count returns the number of stored values.
+	Count() (int64, error)
+}
+
+// GormFearGreedRepository implements FearGreedRepository using GORM
+type GormFearGreedRepository struct {
+	db *gorm.DB
+}
+
+// NewGormFearGreedRepository returns a new GormFearGreedRepository
+func NewGormFearGreedRepository(db *gorm.DB) *GormFearGreedRepository {
+	return &GormFearGreedRepository{db: db}
+}
+
+// Upsert inserts daily values, updating the days that already exist
+func (repo *GormFearGreedRepository) Upsert(points []UserModel.FearGreedIndex) error {
+	if len(points) == 0 {
+		return nil
+	}
+	return repo.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "classification", "source", "updated_at"}),
+	}).Create(&points).Error
+}
+
+// FindRange retrieves the values between two days
+func (repo *GormFearGreedRepository) FindRange(from, to string) ([]UserModel.FearGreedIndex, error) {
+	var points []UserModel.FearGreedIndex
+	err := repo.db.Where("date >= ? AND date <= ?", from, to).Order("date ASC").Find(&points).Error
+	if err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// Latest retrieves the most recent value
+func (repo *GormFearGreedRepository) Latest() (*UserModel.FearGreedIndex, error) {
+	return repo.first("date DESC")
+}
+
+// Earliest retrieves the oldest value
+func (repo *GormFearGreedRepository) Earliest() (*UserModel.FearGreedIndex, error) {
+	return repo.first("date ASC")
+}
+
+func (repo *GormFearGreedRepository) first(order string) (*UserModel.FearGreedIndex, error) {
+	var point UserModel.FearGreedIndex
+	err := repo.db.Order(order).First(&point).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &point, nil
+}
+
+// Count returns the number of stored values
+func (repo *GormFearGreedRepository) Count() (int64, error) {
+	var count int64
+	err := repo.db.Model(&UserModel.FearGreedIndex{}).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,92 @@
+// Package repositorie provides methods for interacting with BIP-329 labels.
+package repositorie
+
+import (
+	"fmt"
+
+	UserModel "cry-api/app/models"
+
+	"gorm.io/gorm"
+)
+
+// LabelRepository defines methods for interacting with a user's labels.
+type LabelRepository interface {
+	// Save persists a label.
+	Save(label *UserModel.Label) error
+
+	// FindByUserID retrieves all labels of a user, optionally restricted to one type.
+	FindByUserID(userID int, labelType string) ([]UserModel.Label, error)
+
+	// FindByRefs retrieves the user's labels attached to any of the given references.
+	FindByRefs(userID int, refs []string) ([]UserModel.Label, error)
+
+	// FindByRef retrieves a user's label of a given type for a reference.
+	FindByRef(userID int, labelType, ref string) (*UserModel.Label, error)
+
+	// Delete removes a label owned by the given user and reports whether it existed.
+	Delete(id, userID int) (bool, error)
+}
+
+// GormLabelRepository implements LabelRepository using GORM
+type GormLabelRepository struct {
+	db *gorm.DB
+}
+
+// NewGormLabelRepository returns a new GormLabelRepository
+func NewGormLabelRepository(db *gorm.DB) *GormLabelRepository {
+	return &GormLabelRepository{db: db}
+}
+
+// Save inserts or updates a label
+func (repo *GormLabelRepository) Save(label *UserModel.Label) error {
+	return repo.db.Save(label).Error
+}
+
+// FindByUserID retrieves the labels of a user, optionally filtered by type
+func (repo *GormLabelRepository) FindByUserID(userID int, labelType string) ([]UserModel.Label, error) {
+	query := repo.db.Where("user_id = ?", userID)
+	if labelType != "" {
+		query = query.Where("type = ?", labelType)
+	}
+
+	var labels []UserModel.Label
+	if err := query.Order("id ASC").Find(&labels).Error; err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// FindByRefs retrieves the user's labels attached to any of the given references
+func (repo *GormLabelRepository) FindByRefs(userID int, refs []string) ([]UserModel.Label, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+
+	var labels []UserModel.Label
+	if err := repo.db.Where("user_id = ? AND ref IN ?", userID, refs).Find(&labels).Error; err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// FindByRef retrieves a user's label of a given type for a reference
+func (repo *GormLabelRepository) FindByRef(userID int, labelType, ref string) (*UserModel.Label, error) {
+	var label UserModel.Label
+	err := repo.db.Where("user_id = ? AND type = ? AND ref = ?", userID, labelType, ref).First(&label).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &label, nil
+}
+
+// Delete removes a label scoped to its owner
+func (repo *GormLabelRepository) Delete(id, userID int) (bool, error) {
+	result := repo.db.Where("id = ? AND user_id = ?", id, userID).Delete(&UserModel.Label{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete label: %v", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
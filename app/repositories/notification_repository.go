@@ -0,0 +1,90 @@
+// Package repositorie provides methods for interacting with the notification feed.
+package repositorie
+
+import (
+	"time"
+
+	UserModel "cry-api/app/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationRepository defines methods for interacting with the in-app notification feed.
+type NotificationRepository interface {
+	// Create inserts a notification unless the wallet already has one of the same type for the
+	// same transaction. It reports whether the notification was inserted.
+	Create(notification *UserModel.Notification) (bool, error)
+
+	// Save updates a notification.
+	Save(notification *UserModel.Notification) error
+
+	// FindByUserID retrieves the latest notifications of a user, newest first, optionally
+	// only the unread ones.
+	FindByUserID(userID int, unreadOnly bool, limit int) ([]UserModel.Notification, error)
+
+	// FindByIDAndUserID retrieves a single notification owned by the given user.
+	FindByIDAndUserID(id, userID int) (*UserModel.Notification, error)
+
+	// MarkAllRead marks every unread notification of a user as read and returns how many were.
+	MarkAllRead(userID int, at time.Time) (int64, error)
+}
+
+// GormNotificationRepository implements NotificationRepository using GORM
+type GormNotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewGormNotificationRepository returns a new GormNotificationRepository
+func NewGormNotificationRepository(db *gorm.DB) *GormNotificationRepository {
+	return &GormNotificationRepository{db: db}
+}
+
+// Create inserts a notification, doing nothing when it already exists
+func (repo *GormNotificationRepository) Create(notification *UserModel.Notification) (bool, error) {
+	result := repo.db.Clauses(clause.OnConflict{DoNothing: true}).Create(notification)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Save updates a notification
+func (repo *GormNotificationRepository) Save(notification *UserModel.Notification) error {
+	return repo.db.Save(notification).Error
+}
+
+// FindByUserID retrieves the latest notifications of a user
+func (repo *GormNotificationRepository) FindByUserID(userID int, unreadOnly bool, limit int) ([]UserModel.Notification, error) {
+	query := repo.db.Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var notifications []UserModel.Notification
+	if err := query.Order("id DESC").Limit(limit).Find(&notifications).Error; err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// FindByIDAndUserID retrieves a notification by ID scoped to its owner
+func (repo *GormNotificationRepository) FindByIDAndUserID(id, userID int) (*UserModel.Notification, error) {
+	var notification UserModel.Notification
+	err := repo.db.Where("id = ? AND user_id = ?", id, userID).First(&notification).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// MarkAllRead marks the unread notifications of a user as read
+func (repo *GormNotificationRepository) MarkAllRead(userID int, at time.Time) (int64, error) {
+	result := repo.db.Model(&UserModel.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", at)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,79 @@
+// Package repositorie provides methods for interacting with the local price history.
+package repositorie
+
+import (
+	UserModel "cry-api/app/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PriceHistoryRepository defines methods for interacting with the local price history.
+type PriceHistoryRepository interface {
+	// Upsert stores daily prices, replacing the prices already stored for the same days.
+	Upsert(points []UserModel.PriceHistory) error
+
+	// FindRange retrieves the prices of an asset in a currency between two YYYY-MM-DD days
+	// (inclusive), oldest first.
+	FindRange(symbol, currency, from, to string) ([]UserModel.PriceHistory, error)
+
+	// Latest retrieves the most recent price of an asset in a currency, nil when none is stored.
+	Latest(symbol, currency string) (*UserModel.PriceHistory, error)
+
+	// Earliest retrieves the oldest price of an asset in a currency, nil when none is stored.
+	Earliest(symbol, currency string) (*UserModel.PriceHistory, error)
+}
+
+// GormPriceHistoryRepository implements PriceHistoryRepository using GORM
+type GormPriceHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewGormPriceHistoryRepository returns a new GormPriceHistoryRepository
+func NewGormPriceHistoryRepository(db *gorm.DB) *GormPriceHistoryRepository {
+	return &GormPriceHistoryRepository{db: db}
+}
+
+// Upsert inserts daily prices, updating the days that already exist
+func (repo *GormPriceHistoryRepository) Upsert(points []UserModel.PriceHistory) error {
+	if len(points) == 0 {
+		return nil
+	}
+	return repo.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "symbol"}, {Name: "currency"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"price", "updated_at"}),
+	}).Create(&points).Error
+}
+
+// FindRange retrieves the prices between two days
+func (repo *GormPriceHistoryRepository) FindRange(symbol, currency, from, to string) ([]UserModel.PriceHistory, error) {
+	var points []UserModel.PriceHistory
+	err := repo.db.Where("symbol = ? AND currency = ? AND date >= ? AND date <= ?", symbol, currency, from, to).
+		Order("date ASC").Find(&points).Error
+	if err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// Latest retrieves the most recent price
+func (repo *GormPriceHistoryRepository) Latest(symbol, currency string) (*UserModel.PriceHistory, error) {
+	return repo.first(symbol, currency, "date DESC")
+}
+
+// Earliest retrieves the oldest price
+func (repo *GormPriceHistoryRepository) Earliest(symbol, currency string) (*UserModel.PriceHistory, error) {
+	return repo.first(symbol, currency, "date ASC")
+}
+
+func (repo *GormPriceHistoryRepository) first(symbol, currency, order string) (*UserModel.PriceHistory, error) {
+	var point UserModel.PriceHistory
+	err := repo.db.Where("symbol = ? AND currency = ?", symbol, currency).Order(order).First(&point).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &point, nil
+}
@@ -0,0 +1,71 @@
+// Package repositorie provides methods for interacting with transfer tags.
+package repositorie
+
+import (
+	"fmt"
+
+	UserModel "cry-api/app/models"
+
+	"gorm.io/gorm"
+)
+
+// TransferTagRepository defines methods for interacting with a user's transfer tags.
+type TransferTagRepository interface {
+	// Save persists a transfer tag.
+	Save(tag *UserModel.TransferTag) error
+
+	// FindByUserID retrieves all transfer tags of a user.
+	FindByUserID(userID int) ([]UserModel.TransferTag, error)
+
+	// FindByTxID retrieves a user's transfer tag for a transaction.
+	FindByTxID(userID int, txid string) (*UserModel.TransferTag, error)
+
+	// Delete removes a transfer tag owned by the given user and reports whether it existed.
+	Delete(id, userID int) (bool, error)
+}
+
+// GormTransferTagRepository implements TransferTagRepository using GORM
+type GormTransferTagRepository struct {
+	db *gorm.DB
+}
+
+// NewGormTransferTagRepository returns a new GormTransferTagRepository
+func NewGormTransferTagRepository(db *gorm.DB) *GormTransferTagRepository {
+	return &GormTransferTagRepository{db: db}
+}
+
+// Save inserts or updates a transfer tag
+func (repo *GormTransferTagRepository) Save(tag *UserModel.TransferTag) error {
+	return repo.db.Save(tag).Error
+}
+
+// FindByUserID retrieves all transfer tags of a user
+func (repo *GormTransferTagRepository) FindByUserID(userID int) ([]UserModel.TransferTag, error) {
+	var tags []UserModel.TransferTag
+	if err := repo.db.Where("user_id = ?", userID).Order("id ASC").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// FindByTxID retrieves a user's transfer tag for a transaction
+func (repo *GormTransferTagRepository) FindByTxID(userID int, txid string) (*UserModel.TransferTag, error) {
+	var tag UserModel.TransferTag
+	err := repo.db.Where("user_id = ? AND tx_id = ?", userID, txid).First(&tag).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// Delete removes a transfer tag scoped to its owner
+func (repo *GormTransferTagRepository) Delete(id, userID int) (bool, error) {
+	result := repo.db.Where("id = ? AND user_id = ?", id, userID).Delete(&UserModel.TransferTag{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete transfer tag: %v", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,46 @@
+// Package repositorie provides methods for interacting with the watcher's wallet cursors.
+package repositorie
+
+import (
+	UserModel "cry-api/app/models"
+
+	"gorm.io/gorm"
+)
+
+// WalletCursorRepository defines methods for interacting with the watchlist watcher's
+// per-wallet cursors.
+type WalletCursorRepository interface {
+	// FindByWalletID retrieves the cursor of a watched wallet, nil when it was never checked.
+	FindByWalletID(walletID int) (*UserModel.WalletCursor, error)
+
+	// Save persists a cursor. It creates a new entry or updates an existing one.
+	Save(cursor *UserModel.WalletCursor) error
+}
+
+// GormWalletCursorRepository implements WalletCursorRepository using GORM
+type GormWalletCursorRepository struct {
+	db *gorm.DB
+}
+
+// NewGormWalletCursorRepository returns a new GormWalletCursorRepository
+func NewGormWalletCursorRepository(db *gorm.DB) *GormWalletCursorRepository {
+	return &GormWalletCursorRepository{db: db}
+}
+
+// FindByWalletID retrieves the cursor of a watched wallet
+func (repo *GormWalletCursorRepository) FindByWalletID(walletID int) (*UserModel.WalletCursor, error) {
+	var cursor UserModel.WalletCursor
+	err := repo.db.Where("watched_wallet_id = ?", walletID).First(&cursor).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+// Save inserts or updates a cursor
+func (repo *GormWalletCursorRepository) Save(cursor *UserModel.WalletCursor) error {
+	return repo.db.Save(cursor).Error
+}
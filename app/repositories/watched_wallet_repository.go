@@ -0,0 +1,99 @@
+// Package repositorie provides methods for interacting with watched wallets.
+package repositorie
+
+import (
+	"fmt"
+
+	UserModel "cry-api/app/models"
+
+	"gorm.io/gorm"
+)
+
+// WatchedWalletRepository defines methods for interacting with a user's watchlist.
+type WatchedWalletRepository interface {
+	// Save persists a watched wallet. It creates a new entry or updates an existing one.
+	Save(wallet *UserModel.WatchedWallet) error
+
+	// FindByUserID retrieves all watched wallets of a user, oldest first.
+	FindByUserID(userID int) ([]UserModel.WatchedWallet, error)
+
+	// FindAll retrieves the watched wallets of every user, oldest first.
+	FindAll() ([]UserModel.WatchedWallet, error)
+
+	// FindByIDAndUserID retrieves a single watched wallet owned by the given user.
+	FindByIDAndUserID(id, userID int) (*UserModel.WatchedWallet, error)
+
+	// FindByValue retrieves a user's watched wallet by its address, key or descriptor.
+	FindByValue(userID int, value string) (*UserModel.WatchedWallet, error)
+
+	// Delete removes a watched wallet owned by the given user.
+	Delete(id, userID int) error
+}
+
+// GormWatchedWalletRepository implements WatchedWalletRepository using GORM
+type GormWatchedWalletRepository struct {
+	db *gorm.DB
+}
+
+// NewGormWatchedWalletRepository returns a new GormWatchedWalletRepository
+func NewGormWatchedWalletRepository(db *gorm.DB) *GormWatchedWalletRepository {
+	return &GormWatchedWalletRepository{db: db}
+}
+
+// Save inserts or updates a watched wallet
+func (repo *GormWatchedWalletRepository) Save(wallet *UserModel.WatchedWallet) error {
+	return repo.db.Save(wallet).Error
+}
+
+// FindByUserID retrieves all watched wallets of a user
+func (repo *GormWatchedWalletRepository) FindByUserID(userID int) ([]UserModel.WatchedWallet, error) {
+	var wallets []UserModel.WatchedWallet
+	err := repo.db.Where("user_id = ?", userID).Order("id ASC").Find(&wallets).Error
+	if err != nil {
+		return nil, err
+	}
+	return wallets, nil
+}
+
+// FindAll retrieves the watched wallets of every user
+func (repo *GormWatchedWalletRepository) FindAll() ([]UserModel.WatchedWallet, error) {
+	var wallets []UserModel.WatchedWallet
+	if err := repo.db.Order("id ASC").Find(&wallets).Error; err != nil {
+		return nil, err
+	}
+	return wallets, nil
+}
+
+// FindByIDAndUserID retrieves a watched wallet by ID scoped to its owner
+func (repo *GormWatchedWalletRepository) FindByIDAndUserID(id, userID int) (*UserModel.WatchedWallet, error) {
+	var wallet UserModel.WatchedWallet
+	err := repo.db.Where("id = ? AND user_id = ?", id, userID).First(&wallet).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+// FindByValue retrieves a user's watched wallet by value
+func (repo *GormWatchedWalletRepository) FindByValue(userID int, value string) (*UserModel.WatchedWallet, error) {
+	var wallet UserModel.WatchedWallet
+	err := repo.db.Where("user_id = ? AND value = ?", userID, value).First(&wallet).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+// Delete removes a watched wallet scoped to its owner
+func (repo *GormWatchedWalletRepository) Delete(id, userID int) error {
+	if err := repo.db.Where("id = ? AND user_id = ?", id, userID).Delete(&UserModel.WatchedWallet{}).Error; err != nil {
+		return fmt.Errorf("failed to delete watched wallet: %v", err)
+	}
+	return nil
+}
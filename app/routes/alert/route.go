@@ -0,0 +1,25 @@
+// Package routes sets up the HTTP routing for the application.
+package routes
+
+import (
+	"cry-api/app/container"
+	AlertController "cry-api/app/controllers/alert"
+	"cry-api/app/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes sets up the market data alert routes. All of them require authentication.
+func RegisterRoutes(rg *gin.RouterGroup, container *container.Container) {
+	alertController := AlertController.NewAlertController(container)
+
+	rg.Use(middleware.JWTAuthMiddleware())
+
+	rg.GET("", alertController.ListRules)
+	rg.POST("", alertController.CreateRule)
+	rg.GET("/triggers", alertController.ListTriggers)
+	rg.GET("/:id", alertController.GetRule)
+	rg.PUT("/:id", alertController.UpdateRule)
+	rg.DELETE("/:id", alertController.DeleteRule)
+	rg.GET("/:id/triggers", alertController.ListTriggers)
+}
@@ -15,4 +15,9 @@ func RegisterRoutes(rg *gin.RouterGroup, container *container.Container) {
 	// Public routes (no authentication required)
 	rg.GET("/fear-and-greed-lastest", coinMarketCapController.GetFearAndGreedLastest)
 	rg.GET("/fear-and-greed-historical", coinMarketCapController.GetFearAndGreedHistorical)
+	rg.GET("/fear-and-greed", coinMarketCapController.GetFearAndGreed)
+	rg.GET("/fear-and-greed/analytics", coinMarketCapController.GetFearAndGreedAnalytics)
+	rg.GET("/quotes", coinMarketCapController.GetQuotes)
+	rg.GET("/ohlcv", coinMarketCapController.GetOHLCV)
+	rg.GET("/convert", coinMarketCapController.ConvertPrice)
 }
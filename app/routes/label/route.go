@@ -0,0 +1,23 @@
+// Package routes sets up the HTTP routing for the application.
+package routes
+
+import (
+	"cry-api/app/container"
+	LabelController "cry-api/app/controllers/label"
+	"cry-api/app/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes sets up the BIP-329 label routes. All of them require authentication.
+func RegisterRoutes(rg *gin.RouterGroup, container *container.Container) {
+	labelController := LabelController.NewLabelController(container)
+
+	rg.Use(middleware.JWTAuthMiddleware())
+
+	rg.GET("", labelController.ListLabels)
+	rg.PUT("", labelController.SetLabel)
+	rg.GET("/export", labelController.ExportLabels)
+	rg.POST("/import", labelController.ImportLabels)
+	rg.DELETE("/:id", labelController.DeleteLabel)
+}
@@ -0,0 +1,20 @@
+// Package routes sets up the HTTP routing for the application.
+package routes
+
+import (
+	"cry-api/app/container"
+	"cry-api/app/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes sets up the Prometheus metrics endpoint when it is enabled. Scrapers must
+// send the configured token in the Authorization header.
+func RegisterRoutes(rg *gin.RouterGroup, container *container.Container) {
+	cfg := container.GetConfig().MetricsConfig
+	if !cfg.Enabled {
+		return
+	}
+
+	rg.GET("/metrics", middleware.MetricsAuthMiddleware(cfg.Token), gin.WrapH(container.GetMetrics().Handler()))
+}
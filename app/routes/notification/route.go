@@ -0,0 +1,21 @@
+// Package routes sets up the HTTP routing for the application.
+package routes
+
+import (
+	"cry-api/app/container"
+	NotificationController "cry-api/app/controllers/notification"
+	"cry-api/app/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes sets up the notification feed routes. All of them require authentication.
+func RegisterRoutes(rg *gin.RouterGroup, container *container.Container) {
+	notificationController := NotificationController.NewNotificationController(container)
+
+	rg.Use(middleware.JWTAuthMiddleware())
+
+	rg.GET("", notificationController.ListNotifications)
+	rg.POST("/read", notificationController.MarkAllRead)
+	rg.POST("/:id/read", notificationController.MarkRead)
+}
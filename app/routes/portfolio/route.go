@@ -0,0 +1,19 @@
+// Package routes sets up the HTTP routing for the application.
+package routes
+
+import (
+	"cry-api/app/container"
+	PortfolioController "cry-api/app/controllers/portfolio"
+	"cry-api/app/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes sets up the portfolio routes. All of them require authentication.
+func RegisterRoutes(rg *gin.RouterGroup, container *container.Container) {
+	portfolioController := PortfolioController.NewPortfolioController(container)
+
+	rg.Use(middleware.JWTAuthMiddleware())
+
+	rg.GET("", portfolioController.GetValuation)
+}
@@ -0,0 +1,21 @@
+// Package routes sets up the HTTP routing for the application.
+package routes
+
+import (
+	"cry-api/app/container"
+	RealtimeController "cry-api/app/controllers/realtime"
+	"cry-api/app/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes sets up the realtime gateway streams. Both require authentication, with the
+// JWT in the Authorization header or the access_token query parameter.
+func RegisterRoutes(rg *gin.RouterGroup, container *container.Container) {
+	realtimeController := RealtimeController.NewRealtimeController(container)
+
+	rg.Use(middleware.StreamJWTAuthMiddleware())
+
+	rg.GET("/sse", realtimeController.Stream)
+	rg.GET("/ws", realtimeController.WebSocket)
+}
@@ -4,9 +4,17 @@ package routes
 import (
 	"cry-api/app/container"
 	TwoFactorRoute "cry-api/app/routes/2fa"
+	AlertRoute "cry-api/app/routes/alert"
 	CoinMarketRoute "cry-api/app/routes/coin_market_cap"
+	LabelRoute "cry-api/app/routes/label"
+	MetricsRoute "cry-api/app/routes/metrics"
+	NotificationRoute "cry-api/app/routes/notification"
+	PortfolioRoute "cry-api/app/routes/portfolio"
+	RealtimeRoute "cry-api/app/routes/realtime"
+	TaxRoute "cry-api/app/routes/tax"
 	UserRoute "cry-api/app/routes/users"
 	WalletExplorerRoute "cry-api/app/routes/wallet_explorer"
+	WatchlistRoute "cry-api/app/routes/watchlist"
 
 	"github.com/gin-gonic/gin"
 )
@@ -20,4 +28,14 @@ func RegisterAllRoutes(r *gin.Engine, container *container.Container) {
 	TwoFactorRoute.RegisterRoutes(v1.Group("/2fa"), container)
 	WalletExplorerRoute.RegisterRoutes(v1.Group("/wallet-explorer"), container)
 	CoinMarketRoute.RegisterRoutes(v1.Group("/coin-market-cap"), container)
+	WatchlistRoute.RegisterRoutes(v1.Group("/watchlist"), container)
+	PortfolioRoute.RegisterRoutes(v1.Group("/portfolio"), container)
+	TaxRoute.RegisterRoutes(v1.Group("/tax"), container)
+	LabelRoute.RegisterRoutes(v1.Group("/labels"), container)
+	NotificationRoute.RegisterRoutes(v1.Group("/notifications"), container)
+	AlertRoute.RegisterRoutes(v1.Group("/alerts"), container)
+	RealtimeRoute.RegisterRoutes(v1.Group("/realtime"), container)
+
+	// Operational endpoints live outside the API versioning
+	MetricsRoute.RegisterRoutes(r.Group(""), container)
 }
@@ -0,0 +1,22 @@
+// Package routes sets up the HTTP routing for the application.
+package routes
+
+import (
+	"cry-api/app/container"
+	TaxController "cry-api/app/controllers/tax"
+	"cry-api/app/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes sets up the capital-gains report routes. All of them require authentication.
+func RegisterRoutes(rg *gin.RouterGroup, container *container.Container) {
+	taxController := TaxController.NewTaxController(container)
+
+	rg.Use(middleware.JWTAuthMiddleware())
+
+	rg.GET("/report", taxController.GetReport)
+	rg.GET("/transfers", taxController.ListTransfers)
+	rg.POST("/transfers", taxController.TagTransfer)
+	rg.DELETE("/transfers/:id", taxController.UntagTransfer)
+}
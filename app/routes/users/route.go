@@ -39,4 +39,5 @@ func RegisterRoutes(rg *gin.RouterGroup, container *container.Container) {
 
 	// Protected routes for user settings
 	authGroup.PUT("/update-account-name", userController.UpdateAccountName)
+	authGroup.PUT("/fiat-currency", userController.UpdateFiatCurrency)
 }
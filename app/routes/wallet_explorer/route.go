@@ -4,6 +4,7 @@ package routes
 import (
 	"cry-api/app/container"
 	WalletExplorerController "cry-api/app/controllers/wallet_explorer"
+	"cry-api/app/middleware"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,7 +13,23 @@ import (
 func RegisterRoutes(rg *gin.RouterGroup, container *container.Container) {
 	walletExplorerController := WalletExplorerController.NewWalletExplorer(container)
 
-	// Public routes (no authentication required)
+	// Public routes (no authentication required) apart from broadcasting. A valid token adds the user's labels.
+	rg.Use(middleware.OptionalJWTAuthMiddleware())
+
 	rg.GET("/tx", walletExplorerController.GetTransactionInfo)
 	rg.GET("/xpub", walletExplorerController.GetTransactionByXPUB)
+	rg.GET("/mempool", walletExplorerController.GetMempoolStatus)
+	rg.GET("/fees", walletExplorerController.GetFeeEstimates)
+	rg.GET("/tip", walletExplorerController.GetTip)
+	rg.GET("/xpub/convert", walletExplorerController.ConvertExtendedKey)
+	rg.GET("/descriptor", walletExplorerController.NormalizeDescriptor)
+	rg.POST("/decode", walletExplorerController.DecodeTransaction)
+	rg.POST("/broadcast", middleware.JWTAuthMiddleware(), walletExplorerController.BroadcastTransaction)
+	rg.GET("/export/address", walletExplorerController.ExportAddressHistory)
+	rg.GET("/export/xpub", walletExplorerController.ExportXPUBHistory)
+
+	// EVM chains, named as in EVM_RPC_URLS
+	rg.GET("/evm/:chain/tx/:hash", walletExplorerController.GetEVMTransaction)
+	rg.GET("/evm/:chain/address/:address", walletExplorerController.GetEVMBalances)
+	rg.GET("/evm/:chain/address/:address/transfers", walletExplorerController.GetEVMTransfers)
 }
@@ -0,0 +1,25 @@
+// Package routes sets up the HTTP routing for the application.
+package routes
+
+import (
+	"cry-api/app/container"
+	WatchlistController "cry-api/app/controllers/watchlist"
+	"cry-api/app/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes sets up the watchlist routes. All of them require authentication.
+func RegisterRoutes(rg *gin.RouterGroup, container *container.Container) {
+	watchlistController := WatchlistController.NewWatchlistController(container)
+
+	rg.Use(middleware.JWTAuthMiddleware())
+
+	rg.GET("", watchlistController.ListWallets)
+	rg.POST("", watchlistController.AddWallet)
+	rg.GET("/summary", watchlistController.GetSummary)
+	rg.GET("/export", watchlistController.ExportHistory)
+	rg.GET("/:id", watchlistController.GetWallet)
+	rg.PUT("/:id", watchlistController.UpdateWallet)
+	rg.DELETE("/:id", watchlistController.DeleteWallet)
+}
@@ -0,0 +1,299 @@
+// Package services provides the users' market data alerts and the job evaluating them.
+package services
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	UserModel "cry-api/app/models"
+	"cry-api/app/money"
+	AlertRepository "cry-api/app/repositories"
+	AlertTypes "cry-api/app/types/alert"
+	EnvTypes "cry-api/app/types/env"
+	app_errors "cry-api/app/types/errors"
+)
+
+const (
+	// DefaultCurrency is used when a price rule is created without a currency
+	DefaultCurrency = "USD"
+	// DefaultWindow is used when a percent change rule is created without a window
+	DefaultWindow = "24h"
+	// MaxCooldownMinutes is the longest cooldown accepted for a rule (30 days)
+	MaxCooldownMinutes = 43200
+	// HistoryLimit caps the number of triggers returned by the history
+	HistoryLimit = 100
+)
+
+// Windows over which a percent change rule can be set, as served by the latest quotes
+var Windows = []string{"1h", "24h", "7d"}
+
+// Classifications of the fear and greed index, from the most fearful
+var Classifications = []string{"Extreme Fear", "Fear", "Neutral", "Greed", "Extreme Greed"}
+
+var (
+	symbolPattern   = regexp.MustCompile(`^[A-Z0-9]{1,20}$`)
+	currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// AlertService manages the users' alert rules and serves the history of their triggers.
+type AlertService struct {
+	config   *EnvTypes.EnvConfig
+	rules    AlertRepository.AlertRuleRepository
+	triggers AlertRepository.AlertTriggerRepository
+}
+
+// AlertServiceInterface defines the methods for the AlertService.
+type AlertServiceInterface interface {
+	CreateRule(userID int, req AlertTypes.ICreateAlertRuleRequest) (*UserModel.AlertRule, error)
+	ListRules(userID int) ([]UserModel.AlertRule, error)
+	GetRule(userID, id int) (*UserModel.AlertRule, error)
+	UpdateRule(userID, id int, req AlertTypes.IUpdateAlertRuleRequest) (*UserModel.AlertRule, error)
+	DeleteRule(userID, id int) error
+	ListTriggers(userID, ruleID int) ([]UserModel.AlertTrigger, error)
+}
+
+// NewAlertService initializes and returns an AlertService instance
+func NewAlertService(
+	cfg *EnvTypes.EnvConfig,
+	rules AlertRepository.AlertRuleRepository,
+	triggers AlertRepository.AlertTriggerRepository,
+) *AlertService {
+	return &AlertService{
+		config:   cfg,
+		rules:    rules,
+		triggers: triggers,
+	}
+}
+
+// CreateRule validates and stores a new active alert rule for the user
+func (s *AlertService) CreateRule(userID int, req AlertTypes.ICreateAlertRuleRequest) (*UserModel.AlertRule, error) {
+	rule := &UserModel.AlertRule{
+		UserID:          userID,
+		Condition:       strings.ToLower(strings.TrimSpace(req.Condition)),
+		Symbol:          req.Symbol,
+		Currency:        req.Currency,
+		Window:          req.Window,
+		Classification:  req.Classification,
+		Mode:            req.Mode,
+		CooldownMinutes: s.config.AlertConfig.DefaultCooldown,
+		Active:          true,
+		CreatedAt:       time.Now(),
+	}
+	if req.Threshold != nil {
+		rule.Threshold = req.Threshold.String()
+	}
+	if req.CooldownMinutes != nil {
+		rule.CooldownMinutes = *req.CooldownMinutes
+	}
+	if err := normalizeRule(rule); err != nil {
+		return nil, err
+	}
+
+	count, err := s.rules.CountByUserID(userID)
+	if err != nil {
+		return nil, app_errors.ErrDatabaseError
+	}
+	if count >= int64(s.config.AlertConfig.MaxRules) {
+		return nil, app_errors.NewConflictError("alert_rule", fmt.Sprintf("You can have at most %d alert rules", s.config.AlertConfig.MaxRules))
+	}
+
+	if err := s.rules.Save(rule); err != nil {
+		return nil, app_errors.ErrDatabaseError
+	}
+	return rule, nil
+}
+
+// ListRules returns every alert rule of the user
+func (s *AlertService) ListRules(userID int) ([]UserModel.AlertRule, error) {
+	rules, err := s.rules.FindByUserID(userID)
+	if err != nil {
+		return nil, app_errors.ErrDatabaseError
+	}
+	if rules == nil {
+		rules = []UserModel.AlertRule{}
+	}
+	return rules, nil
+}
+
+// GetRule returns a single alert rule owned by the user
+func (s *AlertService) GetRule(userID, id int) (*UserModel.AlertRule, error) {
+	rule, err := s.rules.FindByIDAndUserID(id, userID)
+	if err != nil {
+		return nil, app_errors.ErrDatabaseError
+	}
+	if rule == nil {
+		return nil, app_errors.NewNotFoundError("alert_rule", "Alert rule not found")
+	}
+	return rule, nil
+}
+
+// UpdateRule changes the settings of an alert rule, or pauses and resumes it
+func (s *AlertService) UpdateRule(userID, id int, req AlertTypes.IUpdateAlertRuleRequest) (*UserModel.AlertRule, error) {
+	if req.Threshold == nil && req.Window == nil && req.Classification == nil && req.Mode == nil && req.CooldownMinutes == nil && req.Active == nil {
+		return nil, app_errors.NewValidationError("threshold", "", "Nothing to update")
+	}
+
+	rule, err := s.GetRule(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Threshold != nil {
+		rule.Threshold = req.Threshold.String()
+	}
+	if req.Window != nil {
+		rule.Window = *req.Window
+	}
+	if req.Classification != nil {
+		rule.Classification = *req.Classification
+	}
+	if req.Mode != nil {
+		rule.Mode = *req.Mode
+	}
+	if req.CooldownMinutes != nil {
+		rule.CooldownMinutes = *req.CooldownMinutes
+	}
+	if req.Active != nil {
+		rule.Active = *req.Active
+	}
+	if err := normalizeRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.rules.Save(rule); err != nil {
+		return nil, app_errors.ErrDatabaseError
+	}
+	return rule, nil
+}
+
+// DeleteRule removes an alert rule owned by the user. Its triggers stay in the history.
+func (s *AlertService) DeleteRule(userID, id int) error {
+	deleted, err := s.rules.Delete(id, userID)
+	if err != nil {
+		return app_errors.ErrDatabaseError
+	}
+	if !deleted {
+		return app_errors.NewNotFoundError("alert_rule", "Alert rule not found")
+	}
+	return nil
+}
+
+// ListTriggers returns the latest triggers of the user's alerts, newest first, optionally only
+// the ones of a rule (ruleID > 0)
+func (s *AlertService) ListTriggers(userID, ruleID int) ([]UserModel.AlertTrigger, error) {
+	if ruleID > 0 {
+		if _, err := s.GetRule(userID, ruleID); err != nil {
+			return nil, err
+		}
+	}
+
+	triggers, err := s.triggers.FindByUserID(userID, ruleID, HistoryLimit)
+	if err != nil {
+		return nil, app_errors.ErrDatabaseError
+	}
+	if triggers == nil {
+		triggers = []UserModel.AlertTrigger{}
+	}
+	return triggers, nil
+}
+
+// normalizeRule validates a rule and normalizes its fields, clearing the ones its condition
+// does not use
+func normalizeRule(rule *UserModel.AlertRule) error {
+	// A missing threshold reads as 0, which no condition using a threshold accepts
+	raw := strings.TrimSpace(rule.Threshold)
+	threshold := new(big.Rat)
+	if raw != "" && rule.Condition != UserModel.AlertConditionFearGreedClassification {
+		parsed, err := money.ParseDecimal(raw)
+		if err != nil {
+			return app_errors.NewValidationError("threshold", raw, "Threshold must be a decimal number")
+		}
+		threshold = parsed
+	}
+	rule.Threshold = money.FormatDecimal(threshold)
+
+	switch rule.Condition {
+	case UserModel.AlertConditionPriceAbove, UserModel.AlertConditionPriceBelow, UserModel.AlertConditionPercentChange:
+		rule.Symbol = strings.ToUpper(strings.TrimSpace(rule.Symbol))
+		if !symbolPattern.MatchString(rule.Symbol) {
+			return app_errors.NewValidationError("symbol", rule.Symbol, "Symbol must be 1 to 20 letters or digits")
+		}
+		rule.Currency = strings.ToUpper(strings.TrimSpace(rule.Currency))
+		if rule.Currency == "" {
+			rule.Currency = DefaultCurrency
+		}
+		if !currencyPattern.MatchString(rule.Currency) {
+			return app_errors.NewValidationError("currency", rule.Currency, "Currency must be an ISO-4217 code like USD")
+		}
+		rule.Classification = ""
+
+		if rule.Condition == UserModel.AlertConditionPercentChange {
+			rule.Window = strings.ToLower(strings.TrimSpace(rule.Window))
+			if rule.Window == "" {
+				rule.Window = DefaultWindow
+			}
+			if !slices.Contains(Windows, rule.Window) {
+				return app_errors.NewValidationError("window", rule.Window, "Window must be one of 1h, 24h or 7d")
+			}
+			if threshold.Sign() == 0 {
+				return app_errors.NewValidationError("threshold", raw, "Threshold must be a percent change other than 0, negative for a drop")
+			}
+		} else {
+			rule.Window = ""
+			if threshold.Sign() <= 0 {
+				return app_errors.NewValidationError("threshold", raw, "Threshold must be a positive price")
+			}
+		}
+
+	case UserModel.AlertConditionFearGreedAbove, UserModel.AlertConditionFearGreedBelow:
+		rule.Symbol, rule.Currency, rule.Window, rule.Classification = "", "", "", ""
+		if threshold.Sign() <= 0 || threshold.Cmp(big.NewRat(100, 1)) >= 0 {
+			return app_errors.NewValidationError("threshold", raw, "Threshold must be an index value between 0 and 100")
+		}
+
+	case UserModel.AlertConditionFearGreedClassification:
+		rule.Symbol, rule.Currency, rule.Window, rule.Threshold = "", "", "", ""
+		rule.Classification = strings.TrimSpace(rule.Classification)
+		if rule.Classification != "" {
+			classification, ok := canonicalClassification(rule.Classification)
+			if !ok {
+				return app_errors.NewValidationError("classification", rule.Classification,
+					"Classification must be one of Extreme Fear, Fear, Neutral, Greed or Extreme Greed")
+			}
+			rule.Classification = classification
+		}
+
+	default:
+		return app_errors.NewValidationError("condition", rule.Condition,
+			"Condition must be one of price_above, price_below, percent_change, fear_greed_above, fear_greed_below or fear_greed_classification")
+	}
+
+	rule.Mode = strings.ToLower(strings.TrimSpace(rule.Mode))
+	if rule.Mode == "" {
+		rule.Mode = UserModel.AlertModeOnce
+	}
+	if rule.Mode != UserModel.AlertModeOnce && rule.Mode != UserModel.AlertModeRecurring {
+		return app_errors.NewValidationError("mode", rule.Mode, "Mode must be once or recurring")
+	}
+
+	if rule.CooldownMinutes < 1 || rule.CooldownMinutes > MaxCooldownMinutes {
+		return app_errors.NewValidationError("cooldown_minutes", strconv.Itoa(rule.CooldownMinutes),
+			fmt.Sprintf("Cooldown must be between 1 and %d minutes", MaxCooldownMinutes))
+	}
+	return nil
+}
+
+// canonicalClassification returns the classification matching s, whatever its case
+func canonicalClassification(s string) (string, bool) {
+	for _, classification := range Classifications {
+		if strings.EqualFold(classification, s) {
+			return classification, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,412 @@
+// Package services provides the users' market data alerts and the job evaluating them.
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"cry-api/app/logger"
+	UserModel "cry-api/app/models"
+	"cry-api/app/money"
+	Repository "cry-api/app/repositories"
+	CoinMarketCapService "cry-api/app/services/coin_market_cap"
+	EmailService "cry-api/app/services/email"
+	RealtimeService "cry-api/app/services/realtime"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+	EnvTypes "cry-api/app/types/env"
+)
+
+// AlertEvaluatorService periodically checks the active alert rules against the market data
+// service and fires the ones whose condition holds: the trigger is stored in the history and
+// emailed to the rule's owner.
+//
+// A recurring rule fires again once its cooldown is over if its condition still holds, and a
+// one-shot rule is paused after firing. A classification rule fires when the classification
+// changes between two checks, so its first check only records the current one. The market
+// data of a check is fetched once for all rules: one quotes request per currency and one
+// fear and greed request.
+type AlertEvaluatorService struct {
+	config    *EnvTypes.EnvConfig
+	rules     Repository.AlertRuleRepository
+	triggers  Repository.AlertTriggerRepository
+	users     Repository.UserRepository
+	market    CoinMarketCapService.CoinMarketCapServiceInterface
+	email     EmailService.EmailServiceInterface
+	publisher RealtimeService.Publisher
+	now       func() time.Time
+}
+
+// AlertEvaluatorServiceInterface defines the methods for the AlertEvaluatorService.
+type AlertEvaluatorServiceInterface interface {
+	Run(ctx context.Context)
+	CheckAll(ctx context.Context) ([]UserModel.AlertTrigger, error)
+}
+
+// NewAlertEvaluatorService initializes and returns an AlertEvaluatorService instance
+func NewAlertEvaluatorService(
+	cfg *EnvTypes.EnvConfig,
+	rules Repository.AlertRuleRepository,
+	triggers Repository.AlertTriggerRepository,
+	users Repository.UserRepository,
+	market CoinMarketCapService.CoinMarketCapServiceInterface,
+	email EmailService.EmailServiceInterface,
+) *AlertEvaluatorService {
+	return &AlertEvaluatorService{
+		config:   cfg,
+		rules:    rules,
+		triggers: triggers,
+		users:    users,
+		market:   market,
+		email:    email,
+		now:      time.Now,
+	}
+}
+
+// SetClock replaces the time source, for tests
+func (s *AlertEvaluatorService) SetClock(now func() time.Time) {
+	s.now = now
+}
+
+// SetPublisher sets where the triggers are published live, on the alerts topic of the
+// realtime gateway
+func (s *AlertEvaluatorService) SetPublisher(publisher RealtimeService.Publisher) {
+	s.publisher = publisher
+}
+
+// Run checks the alert rules every AlertConfig.Interval seconds until ctx is done. It returns
+// at once when the interval is 0.
+func (s *AlertEvaluatorService) Run(ctx context.Context) {
+	interval := time.Duration(s.config.AlertConfig.Interval) * time.Second
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.CheckAll(ctx); err != nil && ctx.Err() == nil {
+			logger.GetLogger().WithError(err).Error("Alert evaluation failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckAll evaluates every active rule and returns the triggers that fired. Rules whose market
+// data could not be fetched, or that cannot be saved, are logged and checked again on the next
+// run.
+func (s *AlertEvaluatorService) CheckAll(ctx context.Context) ([]UserModel.AlertTrigger, error) {
+	rules, err := s.rules.FindActive()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the alert rules: %w", err)
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	data := s.fetch(ctx, rules)
+
+	var fired []UserModel.AlertTrigger
+	for i := range rules {
+		if err := ctx.Err(); err != nil {
+			return fired, err
+		}
+		trigger, err := s.evaluate(&rules[i], data)
+		if err != nil {
+			logger.GetLogger().WithError(err).WithField("alert_rule_id", rules[i].ID).Warn("Failed to evaluate alert rule")
+			continue
+		}
+		if trigger != nil {
+			fired = append(fired, *trigger)
+		}
+	}
+	return fired, nil
+}
+
+// marketData holds the market data of one check
+type marketData struct {
+	quotes    map[string]map[string]CoinMarketCap.IQuote // by currency, then symbol
+	fearGreed *CoinMarketCap.FearGreedData
+}
+
+// observation is the outcome of checking a rule against the market data
+type observation struct {
+	holds   bool
+	value   *big.Rat // observed price, percent change or index value, as sent upstream
+	source  string
+	state   string // current classification, for classification rules
+	title   string
+	message string
+}
+
+// fetch gets the market data the rules need. Failures are logged and leave the data out.
+func (s *AlertEvaluatorService) fetch(ctx context.Context, rules []UserModel.AlertRule) *marketData {
+	data := &marketData{quotes: make(map[string]map[string]CoinMarketCap.IQuote)}
+
+	symbols := make(map[string][]string)
+	seen := make(map[string]bool)
+	needsIndex := false
+	for _, rule := range rules {
+		if rule.Symbol == "" {
+			needsIndex = true
+			continue
+		}
+		if key := rule.Currency + ":" + rule.Symbol; !seen[key] {
+			seen[key] = true
+			symbols[rule.Currency] = append(symbols[rule.Currency], rule.Symbol)
+		}
+	}
+
+	for currency, list := range symbols {
+		data.quotes[currency] = make(map[string]CoinMarketCap.IQuote)
+		for start := 0; start < len(list); start += CoinMarketCapService.MaxQuoteAssets {
+			batch := list[start:min(start+CoinMarketCapService.MaxQuoteAssets, len(list))]
+			quotes, err := s.market.GetLatestQuotes(ctx, batch, nil, currency)
+			if err != nil {
+				logger.GetLogger().WithError(err).WithField("currency", currency).Warn("Failed to fetch quotes for alerts")
+				continue
+			}
+			for _, quote := range quotes {
+				data.quotes[currency][quote.Symbol] = quote
+			}
+		}
+	}
+
+	if needsIndex {
+		latest, err := s.market.GetFearAndGreedLastest(ctx)
+		if err != nil {
+			logger.GetLogger().WithError(err).Warn("Failed to fetch the fear and greed index for alerts")
+		} else {
+			data.fearGreed = latest
+		}
+	}
+	return data
+}
+
+// evaluate checks a rule against the market data and fires it when its condition holds and
+// its cooldown is over. It returns the trigger, or nil when the rule did not fire.
+func (s *AlertEvaluatorService) evaluate(rule *UserModel.AlertRule, data *marketData) (*UserModel.AlertTrigger, error) {
+	obs, ok := observe(rule, data)
+	if !ok {
+		return nil, nil
+	}
+
+	now := s.now().UTC()
+	stateChanged := false
+	if obs.state != "" && obs.state != rule.LastClassification {
+		rule.LastClassification = obs.state
+		stateChanged = true
+	}
+
+	cooling := rule.LastTriggeredAt != nil && now.Before(rule.LastTriggeredAt.Add(time.Duration(rule.CooldownMinutes)*time.Minute))
+	if !obs.holds || cooling {
+		if stateChanged {
+			if err := s.rules.Save(rule); err != nil {
+				return nil, fmt.Errorf("failed to save the alert rule: %w", err)
+			}
+		}
+		return nil, nil
+	}
+
+	// The rule is saved first, so that a storage failure cannot make it fire twice
+	rule.LastTriggeredAt = &now
+	if rule.Mode == UserModel.AlertModeOnce {
+		rule.Active = false
+	}
+	if err := s.rules.Save(rule); err != nil {
+		return nil, fmt.Errorf("failed to save the alert rule: %w", err)
+	}
+
+	trigger := &UserModel.AlertTrigger{
+		AlertRuleID: rule.ID,
+		UserID:      rule.UserID,
+		Condition:   rule.Condition,
+		Title:       obs.title,
+		Message:     obs.message,
+		Value:       money.FormatDecimal(obs.value),
+		Source:      obs.source,
+		TriggeredAt: now,
+	}
+	if rule.Condition == UserModel.AlertConditionPriceAbove || rule.Condition == UserModel.AlertConditionPriceBelow {
+		trigger.Currency = rule.Currency
+	}
+	if err := s.triggers.Create(trigger); err != nil {
+		return nil, fmt.Errorf("failed to store the alert trigger: %w", err)
+	}
+
+	s.sendEmail(trigger)
+	if s.publisher != nil {
+		s.publisher.PublishToUser(trigger.UserID, RealtimeService.TopicAlerts, *trigger)
+	}
+	return trigger, nil
+}
+
+// sendEmail emails a trigger to its verified owner and records when it was sent. Failures are
+// only logged: the trigger stays in the history and is not sent again.
+func (s *AlertEvaluatorService) sendEmail(trigger *UserModel.AlertTrigger) {
+	log := logger.GetLogger().WithField("alert_trigger_id", trigger.ID)
+
+	user, err := s.users.FindByID(trigger.UserID)
+	if err != nil || user == nil {
+		log.WithError(err).WithField("user_id", trigger.UserID).Warn("Failed to load the owner of an alert rule")
+		return
+	}
+	if !user.IsVerified || user.Email == "" {
+		return
+	}
+
+	err = s.email.SendAlertEmail(
+		user.Email,
+		s.config.NoReplyEmail,
+		user.Username,
+		trigger.Title,
+		trigger.Message,
+		strings.TrimRight(s.config.CryAppURL, "/")+"/alerts",
+	)
+	if err != nil {
+		log.WithError(err).Warn("Failed to email alert")
+		return
+	}
+
+	emailedAt := s.now().UTC()
+	trigger.EmailedAt = &emailedAt
+	if err := s.triggers.Save(trigger); err != nil {
+		log.WithError(err).Warn("Failed to record alert email")
+	}
+}
+
+// observe checks a rule against the market data. It reports false when the data the rule
+// needs is missing.
+func observe(rule *UserModel.AlertRule, data *marketData) (observation, bool) {
+	threshold := rule.Threshold
+	limit := new(big.Rat)
+	if rule.Condition != UserModel.AlertConditionFearGreedClassification {
+		parsed, err := money.ParseDecimal(rule.Threshold)
+		if err != nil {
+			return observation{}, false
+		}
+		limit = parsed
+	}
+
+	if rule.Symbol != "" {
+		quote, ok := data.quotes[rule.Currency][rule.Symbol]
+		if !ok {
+			return observation{}, false
+		}
+
+		value, err := money.ParseDecimal(quote.Price)
+		if err != nil {
+			return observation{}, false
+		}
+		obs := observation{value: value, source: quote.Source}
+		price := formatNumber(value)
+		switch rule.Condition {
+		case UserModel.AlertConditionPriceAbove:
+			obs.holds = obs.value.Cmp(limit) > 0
+			obs.title = fmt.Sprintf("%s is above %s %s", rule.Symbol, threshold, rule.Currency)
+			obs.message = fmt.Sprintf("%s is at %s %s, above your alert at %s %s.", rule.Symbol, price, rule.Currency, threshold, rule.Currency)
+		case UserModel.AlertConditionPriceBelow:
+			obs.holds = obs.value.Cmp(limit) < 0
+			obs.title = fmt.Sprintf("%s is below %s %s", rule.Symbol, threshold, rule.Currency)
+			obs.message = fmt.Sprintf("%s is at %s %s, below your alert at %s %s.", rule.Symbol, price, rule.Currency, threshold, rule.Currency)
+		case UserModel.AlertConditionPercentChange:
+			change, err := money.ParseDecimal(percentChange(quote, rule.Window))
+			if err != nil {
+				return observation{}, false
+			}
+			obs.value = change
+			verb := "rose"
+			obs.holds = obs.value.Cmp(limit) >= 0
+			if limit.Sign() < 0 {
+				verb = "fell"
+				obs.holds = obs.value.Cmp(limit) <= 0
+			}
+			if limit.Sign() > 0 {
+				threshold = "+" + threshold
+			}
+			obs.title = fmt.Sprintf("%s %s %s%% in %s", rule.Symbol, verb, formatNumber(new(big.Rat).Abs(change)), rule.Window)
+			obs.message = fmt.Sprintf("%s changed by %s%% over the last %s and is at %s %s, past your alert at %s%%.",
+				rule.Symbol, signed(change), rule.Window, price, rule.Currency, threshold)
+		}
+		return obs, true
+	}
+
+	if data.fearGreed == nil {
+		return observation{}, false
+	}
+	index := data.fearGreed.Data
+	classification, ok := canonicalClassification(index.ValueClassification)
+	if !ok {
+		classification = CoinMarketCapService.Classify(index.Value)
+	}
+
+	obs := observation{value: big.NewRat(int64(index.Value), 1), source: data.fearGreed.Source}
+	switch rule.Condition {
+	case UserModel.AlertConditionFearGreedAbove:
+		obs.holds = obs.value.Cmp(limit) > 0
+		obs.title = fmt.Sprintf("Fear & greed index is above %s", threshold)
+		obs.message = fmt.Sprintf("The fear and greed index is at %d (%s), above your alert at %s.", index.Value, classification, threshold)
+	case UserModel.AlertConditionFearGreedBelow:
+		obs.holds = obs.value.Cmp(limit) < 0
+		obs.title = fmt.Sprintf("Fear & greed index is below %s", threshold)
+		obs.message = fmt.Sprintf("The fear and greed index is at %d (%s), below your alert at %s.", index.Value, classification, threshold)
+	case UserModel.AlertConditionFearGreedClassification:
+		previous := rule.LastClassification
+		obs.holds = previous != "" && classification != previous &&
+			(rule.Classification == "" || rule.Classification == classification)
+		obs.state = classification
+		obs.title = "Fear & greed index is now " + classification
+		obs.message = fmt.Sprintf("The fear and greed index moved from %s to %s (%d).", previous, classification, index.Value)
+	}
+	return obs, true
+}
+
+// percentChange returns the change of a quote over a rule window
+func percentChange(quote CoinMarketCap.IQuote, window string) string {
+	switch window {
+	case "1h":
+		return quote.PercentChange1h
+	case "7d":
+		return quote.PercentChange7d
+	default:
+		return quote.PercentChange24h
+	}
+}
+
+// formatNumber renders a price, percent or index value with two decimals, or four
+// significant digits below 1
+func formatNumber(v *big.Rat) string {
+	decimals := 2
+	abs := new(big.Rat).Abs(v)
+	if abs.Sign() > 0 && abs.Cmp(big.NewRat(1, 1)) < 0 {
+		// One more decimal for each leading zero after the point
+		for ten := big.NewRat(10, 1); abs.Cmp(big.NewRat(1, 10)) < 0; decimals++ {
+			abs.Mul(abs, ten)
+		}
+		decimals += 2
+	}
+	s := v.FloatString(decimals)
+	if strings.Contains(s, ".") {
+		s = strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+	}
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+// signed renders a percent change with its sign
+func signed(v *big.Rat) string {
+	if v.Sign() > 0 {
+		return "+" + formatNumber(v)
+	}
+	return formatNumber(v)
+}
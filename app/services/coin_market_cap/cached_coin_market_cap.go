@@ -0,0 +1,127 @@
+// Package services provides  coin market cap services for external API interactions.
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"cry-api/app/cache"
+	"cry-api/app/money"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+)
+
+// Cache policies of the CoinMarketCap lookups. The fear and greed index only moves a few
+// times a day, the prices of past days never change, and latest quotes are refreshed every
+// minute by CoinMarketCap.
+var (
+	FearAndGreedLatestPolicy     = cache.Policy{TTL: time.Hour, StaleTTL: 24 * time.Hour}
+	FearAndGreedHistoricalPolicy = cache.Policy{TTL: 6 * time.Hour, StaleTTL: 7 * 24 * time.Hour}
+	CurrentPricesPolicy          = cache.Policy{TTL: time.Hour, StaleTTL: 24 * time.Hour}
+	ClosedPricesPolicy           = cache.Policy{TTL: 30 * 24 * time.Hour, StaleTTL: 30 * 24 * time.Hour}
+	LatestQuotesPolicy           = cache.Policy{TTL: time.Minute, StaleTTL: time.Hour}
+	OpenCandlesPolicy            = cache.Policy{TTL: 5 * time.Minute, StaleTTL: time.Hour}
+)
+
+// CachedCoinMarketCapService caches the lookups of a CoinMarketCapServiceInterface, saving
+// API credits on repeated page views.
+type CachedCoinMarketCapService struct {
+	CoinMarketCapServiceInterface
+	cache *cache.Cache
+}
+
+// NewCachedCoinMarketCapService wraps upstream with c. A nil cache disables caching.
+func NewCachedCoinMarketCapService(upstream CoinMarketCapServiceInterface, c *cache.Cache) *CachedCoinMarketCapService {
+	return &CachedCoinMarketCapService{CoinMarketCapServiceInterface: upstream, cache: c}
+}
+
+// GetFearAndGreedLastest returns the cached latest fear and greed index
+func (s *CachedCoinMarketCapService) GetFearAndGreedLastest(ctx context.Context) (*CoinMarketCap.FearGreedData, error) {
+	return cache.Fetch(ctx, s.cache, "cmc:fear-greed:latest", cache.Fixed[*CoinMarketCap.FearGreedData](FearAndGreedLatestPolicy), func(ctx context.Context) (*CoinMarketCap.FearGreedData, error) {
+		return s.CoinMarketCapServiceInterface.GetFearAndGreedLastest(ctx)
+	})
+}
+
+// GetFearAndGreedHistorical returns a cached page of the fear and greed history
+func (s *CachedCoinMarketCapService) GetFearAndGreedHistorical(ctx context.Context, start, limit int) (*CoinMarketCap.FearGreedHistorical, error) {
+	key := fmt.Sprintf("cmc:fear-greed:historical:%d:%d", start, limit)
+	return cache.Fetch(ctx, s.cache, key, cache.Fixed[*CoinMarketCap.FearGreedHistorical](FearAndGreedHistoricalPolicy), func(ctx context.Context) (*CoinMarketCap.FearGreedHistorical, error) {
+		return s.CoinMarketCapServiceInterface.GetFearAndGreedHistorical(ctx, start, limit)
+	})
+}
+
+// GetHistoricalPrices returns cached daily prices. Ranges ending before today are final.
+func (s *CachedCoinMarketCapService) GetHistoricalPrices(ctx context.Context, symbol, convert string, from, to time.Time) ([]CoinMarketCap.IPricePoint, error) {
+	key := fmt.Sprintf("cmc:prices:%s:%s:%s:%s", strings.ToUpper(symbol), strings.ToUpper(convert),
+		from.UTC().Format("2006-01-02"), to.UTC().Format("2006-01-02"))
+
+	policy := CurrentPricesPolicy
+	if to.UTC().Format("2006-01-02") < time.Now().UTC().Format("2006-01-02") {
+		policy = ClosedPricesPolicy
+	}
+
+	return cache.Fetch(ctx, s.cache, key, cache.Fixed[[]CoinMarketCap.IPricePoint](policy), func(ctx context.Context) ([]CoinMarketCap.IPricePoint, error) {
+		return s.CoinMarketCapServiceInterface.GetHistoricalPrices(ctx, symbol, convert, from, to)
+	})
+}
+
+// GetLatestQuotes returns cached latest quotes
+func (s *CachedCoinMarketCapService) GetLatestQuotes(ctx context.Context, symbols []string, ids []int, convert string) ([]CoinMarketCap.IQuote, error) {
+	symbols, ids, convert, err := NormalizeQuotesQuery(symbols, ids, convert)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("cmc:quotes:%s:symbol:%s", convert, strings.Join(symbols, ","))
+	if len(ids) > 0 {
+		key = fmt.Sprintf("cmc:quotes:%s:id:%s", convert, strings.Trim(fmt.Sprint(ids), "[]"))
+	}
+	return cache.Fetch(ctx, s.cache, key, cache.Fixed[[]CoinMarketCap.IQuote](LatestQuotesPolicy), func(ctx context.Context) ([]CoinMarketCap.IQuote, error) {
+		return s.CoinMarketCapServiceInterface.GetLatestQuotes(ctx, symbols, ids, convert)
+	})
+}
+
+// GetOHLCV returns cached candles. Ranges ending before the current period are final.
+func (s *CachedCoinMarketCapService) GetOHLCV(ctx context.Context, symbol, convert, interval string, from, to time.Time) (*CoinMarketCap.IOHLCV, error) {
+	now := time.Now().UTC()
+	q, err := NormalizeOHLCVQuery(symbol, convert, interval, from, to, now)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("cmc:ohlcv:%s:%s:%s:%d:%d", q.Symbol, q.Convert, q.Interval, q.From.Unix(), q.To.Unix())
+
+	policy := OpenCandlesPolicy
+	if q.To.Before(now.Truncate(24 * time.Hour)) {
+		policy = ClosedPricesPolicy
+	}
+
+	return cache.Fetch(ctx, s.cache, key, cache.Fixed[*CoinMarketCap.IOHLCV](policy), func(ctx context.Context) (*CoinMarketCap.IOHLCV, error) {
+		return s.CoinMarketCapServiceInterface.GetOHLCV(ctx, q.Symbol, q.Convert, q.Interval, q.From, q.To)
+	})
+}
+
+// ConvertPrice values an amount at the cached price of one unit, so that every amount of a
+// pair shares a single cache entry
+func (s *CachedCoinMarketCapService) ConvertPrice(ctx context.Context, amount *big.Rat, symbol, convert string) (*CoinMarketCap.IConversion, error) {
+	symbol, convert, err := NormalizeConversion(amount, symbol, convert)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("cmc:convert:%s:%s", symbol, convert)
+	unit, err := cache.Fetch(ctx, s.cache, key, cache.Fixed[*CoinMarketCap.IConversion](LatestQuotesPolicy), func(ctx context.Context) (*CoinMarketCap.IConversion, error) {
+		return s.CoinMarketCapServiceInterface.ConvertPrice(ctx, big.NewRat(1, 1), symbol, convert)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rate, err := money.ParseDecimal(unit.Rate)
+	if err != nil {
+		return nil, upstreamError(ctx, "Failed to convert price", err)
+	}
+	return NewConversion(symbol, convert, amount, rate, unit.LastUpdated, unit.Source)
+}
@@ -2,12 +2,19 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/big"
 	"net/http"
+	"net/url"
+	"sort"
+	"strings"
 	"time"
 
+	"cry-api/app/httpclient"
+	"cry-api/app/money"
 	CoinMarketCap "cry-api/app/types/coin_market_cap"
 	EnvTypes "cry-api/app/types/env"
 )
@@ -15,27 +22,41 @@ import (
 // CoinMarketCapService interacts with external wallet explorer APIs.
 type CoinMarketCapService struct {
 	Config *EnvTypes.EnvConfig
+	Client httpclient.Doer
 }
 
 // CoinMarketCapServiceInterface defines the methods for the CoinMarketCapService.
 type CoinMarketCapServiceInterface interface {
-	GetFearAndGreedLastest() (*CoinMarketCap.FearGreedData, error)
-	GetFearAndGreedHistorical(start, limit int) (*CoinMarketCap.FearGreedHistorical, error)
+	GetFearAndGreedLastest(ctx context.Context) (*CoinMarketCap.FearGreedData, error)
+	GetFearAndGreedHistorical(ctx context.Context, start, limit int) (*CoinMarketCap.FearGreedHistorical, error)
+	GetHistoricalPrices(ctx context.Context, symbol, convert string, from, to time.Time) ([]CoinMarketCap.IPricePoint, error)
+	GetLatestQuotes(ctx context.Context, symbols []string, ids []int, convert string) ([]CoinMarketCap.IQuote, error)
+	GetOHLCV(ctx context.Context, symbol, convert, interval string, from, to time.Time) (*CoinMarketCap.IOHLCV, error)
+	ConvertPrice(ctx context.Context, amount *big.Rat, symbol, convert string) (*CoinMarketCap.IConversion, error)
 }
 
+// ProviderName is the name of CoinMarketCap in MARKET_DATA_PROVIDERS and in the source of responses
+const ProviderName = "coinmarketcap"
+
 // NewCoinMarketCapServiceService initializes and returns an CoinMarketCapService instance
-func NewCoinMarketCapServiceService(cfg *EnvTypes.EnvConfig) *CoinMarketCapService {
+func NewCoinMarketCapServiceService(cfg *EnvTypes.EnvConfig, client httpclient.Doer) *CoinMarketCapService {
 	return &CoinMarketCapService{
 		Config: cfg,
+		Client: client,
 	}
 }
 
+// Name returns the market data provider name
+func (s *CoinMarketCapService) Name() string {
+	return ProviderName
+}
+
 // GetFearAndGreedLastest fetches fear and greed index data from CoinMarketCap API.
-func (s *CoinMarketCapService) GetFearAndGreedLastest() (*CoinMarketCap.FearGreedData, error) {
+func (s *CoinMarketCapService) GetFearAndGreedLastest(ctx context.Context) (*CoinMarketCap.FearGreedData, error) {
 	baseURL := s.Config.CoinMarketCapConfig.API
 	url := fmt.Sprintf("%s/v3/fear-and-greed/latest", baseURL)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
@@ -43,8 +64,7 @@ func (s *CoinMarketCapService) GetFearAndGreedLastest() (*CoinMarketCap.FearGree
 	// Use the correct custom header for API key authentication
 	req.Header.Set("X-CMC_PRO_API_KEY", s.Config.CoinMarketCapConfig.APIKey)
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := s.Client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to perform HTTP request: %w", err)
 	}
@@ -54,20 +74,20 @@ func (s *CoinMarketCapService) GetFearAndGreedLastest() (*CoinMarketCap.FearGree
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, apiError(resp)
 	}
 
 	var data CoinMarketCap.FearGreedData
 	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
 		return nil, fmt.Errorf("failed to decode response body: %w", err)
 	}
+	data.Source = ProviderName
 
 	return &data, nil
 }
 
 // GetFearAndGreedHistorical fetches fear and greed index data from CoinMarketCap API.
-func (s *CoinMarketCapService) GetFearAndGreedHistorical(start, limit int) (*CoinMarketCap.FearGreedHistorical, error) {
+func (s *CoinMarketCapService) GetFearAndGreedHistorical(ctx context.Context, start, limit int) (*CoinMarketCap.FearGreedHistorical, error) {
 	// Ensure the limit does not exceed 500
 	if limit > 500 {
 		limit = 500
@@ -82,15 +102,14 @@ func (s *CoinMarketCapService) GetFearAndGreedHistorical(start, limit int) (*Coi
 	baseURL := s.Config.CoinMarketCapConfig.API
 	url := fmt.Sprintf("%s/v3/fear-and-greed/historical?start=%d&limit=%d", baseURL, start, limit)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 
 	req.Header.Set("X-CMC_PRO_API_KEY", s.Config.CoinMarketCapConfig.APIKey)
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := s.Client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to perform HTTP request: %w", err)
 	}
@@ -100,14 +119,96 @@ func (s *CoinMarketCapService) GetFearAndGreedHistorical(start, limit int) (*Coi
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, apiError(resp)
 	}
 
 	var data CoinMarketCap.FearGreedHistorical
 	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
 		return nil, fmt.Errorf("failed to decode response body: %w", err)
 	}
+	data.Source = ProviderName
 
 	return &data, nil
 }
+
+// GetHistoricalPrices fetches daily prices of an asset in a fiat currency between two dates
+// (inclusive) from CoinMarketCap API. Points are returned oldest first, one per UTC day.
+func (s *CoinMarketCapService) GetHistoricalPrices(ctx context.Context, symbol, convert string, from, to time.Time) ([]CoinMarketCap.IPricePoint, error) {
+	symbol = strings.ToUpper(symbol)
+	convert = strings.ToUpper(convert)
+
+	query := url.Values{}
+	query.Set("symbol", symbol)
+	query.Set("convert", convert)
+	query.Set("interval", "daily")
+	query.Set("time_start", from.UTC().Format("2006-01-02"))
+	query.Set("time_end", to.UTC().Format("2006-01-02"))
+
+	baseURL := s.Config.CoinMarketCapConfig.API
+	endpoint := fmt.Sprintf("%s/v2/cryptocurrency/quotes/historical?%s", baseURL, query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	req.Header.Set("X-CMC_PRO_API_KEY", s.Config.CoinMarketCapConfig.APIKey)
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to perform HTTP request: %w", err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, apiError(resp)
+	}
+
+	var data CoinMarketCap.HistoricalQuotesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to decode response body: %w", err)
+	}
+
+	assets := data.Data[symbol]
+	if len(assets) == 0 {
+		return nil, fmt.Errorf("no historical quotes returned for %s", symbol)
+	}
+
+	// Keep the last quote of each day
+	byDay := make(map[string]*big.Rat)
+	for _, q := range assets[0].Quotes {
+		fx, ok := q.Quote[convert]
+		if !ok {
+			continue
+		}
+		price, err := money.FromNumber(fx.Price)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the price of %s: %w", q.Timestamp.UTC().Format(time.DateOnly), err)
+		}
+		byDay[q.Timestamp.UTC().Format("2006-01-02")] = price
+	}
+
+	points := make([]CoinMarketCap.IPricePoint, 0, len(byDay))
+	for day, price := range byDay {
+		points = append(points, CoinMarketCap.IPricePoint{Date: day, Price: price})
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Date < points[j].Date })
+
+	return points, nil
+}
+
+// apiError describes a failed response, using the message of the CoinMarketCap status block
+// when the body carries one
+func apiError(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+
+	var payload CoinMarketCap.ErrorResponse
+	if err := json.Unmarshal(body, &payload); err == nil && payload.Status.ErrorMessage != "" {
+		return fmt.Errorf("API request failed with status %d: %s (error code %d)",
+			resp.StatusCode, payload.Status.ErrorMessage, payload.Status.ErrorCode)
+	}
+	return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+}
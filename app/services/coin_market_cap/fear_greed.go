@@ -0,0 +1,371 @@
+// Package services provides  coin market cap services for external API interactions.
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"cry-api/app/logger"
+	UserModel "cry-api/app/models"
+	FearGreedRepository "cry-api/app/repositories"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+	EnvTypes "cry-api/app/types/env"
+	app_errors "cry-api/app/types/errors"
+)
+
+const (
+	// SyncPageSize is the number of points requested per page, the most CoinMarketCap serves
+	SyncPageSize = 500
+	// DefaultRangeDays is the length of the range served when no from date is given
+	DefaultRangeDays = 30
+	// maxSyncPages bounds the pages requested by a single sync
+	maxSyncPages = 50
+)
+
+// FearGreedService keeps a local copy of the fear and greed history and answers range
+// queries from it. Sync backfills the history once and then pulls the new days only.
+type FearGreedService struct {
+	config     *EnvTypes.EnvConfig
+	upstream   CoinMarketCapServiceInterface
+	repo       FearGreedRepository.FearGreedRepository
+	now        func() time.Time
+	backfilled bool
+}
+
+// FearGreedServiceInterface defines the methods for the FearGreedService.
+type FearGreedServiceInterface interface {
+	Run(ctx context.Context)
+	Sync(ctx context.Context) (int, error)
+	GetRange(from, to, interval string) (*CoinMarketCap.IFearGreedRange, error)
+}
+
+// NewFearGreedService initializes and returns a FearGreedService instance. upstream should
+// not be cached, so that syncs see the latest values.
+func NewFearGreedService(
+	cfg *EnvTypes.EnvConfig,
+	upstream CoinMarketCapServiceInterface,
+	repo FearGreedRepository.FearGreedRepository,
+) *FearGreedService {
+	return &FearGreedService{
+		config:   cfg,
+		upstream: upstream,
+		repo:     repo,
+		now:      time.Now,
+	}
+}
+
+// SetClock replaces the time source, for tests
+func (s *FearGreedService) SetClock(now func() time.Time) {
+	s.now = now
+}
+
+// Run syncs the history every CoinMarketCapConfig.FearGreedSyncInterval seconds until ctx is
+// done. It returns at once when the interval is 0.
+func (s *FearGreedService) Run(ctx context.Context) {
+	interval := time.Duration(s.config.CoinMarketCapConfig.FearGreedSyncInterval) * time.Second
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		stored, err := s.Sync(ctx)
+		if err != nil && ctx.Err() == nil {
+			logger.GetLogger().WithError(err).WithField("stored", stored).Error("Fear and greed sync failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync pulls the days newer than the latest stored one, then the older days until the start
+// of the history when it has not been reached yet. It returns the number of days stored.
+//
+// CoinMarketCap pages the history newest first, and the local copy always extends from the
+// newest day back without gaps, so the backfill resumes at the offset of the oldest stored day.
+// To keep it so, the new days are only stored once every page down to the latest stored day
+// has been fetched.
+func (s *FearGreedService) Sync(ctx context.Context) (int, error) {
+	latest, err := s.repo.Latest()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load the latest stored day: %w", err)
+	}
+
+	stored := 0
+	start := 1
+	var pending []UserModel.FearGreedIndex
+	reachedKnown := false
+	for page := 0; page < maxSyncPages; page++ {
+		points, fetched, err := s.fetchPage(ctx, start)
+		if err != nil {
+			return stored, err
+		}
+
+		if latest == nil {
+			// Without stored days every page extends the local copy back without a gap
+			if err := s.repo.Upsert(points); err != nil {
+				return stored, fmt.Errorf("failed to store fear and greed values: %w", err)
+			}
+			stored += len(points)
+		} else {
+			// The latest stored day is pulled again, as its value may have been updated since
+			for _, p := range points {
+				if p.Date >= latest.Date {
+					pending = append(pending, p)
+				}
+				if p.Date <= latest.Date {
+					reachedKnown = true
+				}
+			}
+		}
+
+		if fetched < SyncPageSize {
+			s.backfilled = true
+			reachedKnown = true
+			break
+		}
+		if reachedKnown {
+			break
+		}
+		start += fetched
+	}
+
+	if latest != nil {
+		if !reachedKnown {
+			return stored, fmt.Errorf("the new fear and greed days do not reach back to the latest stored day %s", latest.Date)
+		}
+		if err := s.repo.Upsert(pending); err != nil {
+			return stored, fmt.Errorf("failed to store fear and greed values: %w", err)
+		}
+		stored += len(pending)
+	}
+
+	if s.backfilled {
+		return stored, nil
+	}
+
+	count, err := s.repo.Count()
+	if err != nil {
+		return stored, fmt.Errorf("failed to count stored days: %w", err)
+	}
+	// Points that could not be read are not stored, so the count is a lower bound of the offset
+	// of the oldest stored day: the backfill may read some stored days again but never skips one
+	start = int(count) + 1
+	for page := 0; page < maxSyncPages; page++ {
+		points, fetched, err := s.fetchPage(ctx, start)
+		if err != nil {
+			return stored, err
+		}
+		if err := s.repo.Upsert(points); err != nil {
+			return stored, fmt.Errorf("failed to store fear and greed values: %w", err)
+		}
+		stored += len(points)
+
+		if fetched < SyncPageSize {
+			s.backfilled = true
+			break
+		}
+		start += fetched
+	}
+	return stored, nil
+}
+
+// fetchPage fetches a page of the history and converts it to daily values. Points whose
+// timestamp cannot be read are skipped; the number of points CoinMarketCap sent is returned
+// too, as it is what the next offset and the end of the history are counted in.
+func (s *FearGreedService) fetchPage(ctx context.Context, start int) ([]UserModel.FearGreedIndex, int, error) {
+	page, err := s.upstream.GetFearAndGreedHistorical(ctx, start, SyncPageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch the fear and greed history: %w", err)
+	}
+
+	points := make([]UserModel.FearGreedIndex, 0, len(page.Data))
+	for _, p := range page.Data {
+		day, err := parseTimestamp(p.Timestamp)
+		if err != nil {
+			logger.GetLogger().WithError(err).Warn("Skipping fear and greed point")
+			continue
+		}
+		points = append(points, UserModel.FearGreedIndex{
+			Date:           day.Format(time.DateOnly),
+			Value:          p.Value,
+			Classification: p.ValueClassification,
+			Source:         page.Source,
+		})
+	}
+	return points, len(page.Data), nil
+}
+
+// GetRange returns the stored history between two YYYY-MM-DD days (inclusive), aggregated by
+// interval, with statistics over the whole range. to defaults to today, from to 30 days
+// before to, and interval to daily.
+func (s *FearGreedService) GetRange(from, to, interval string) (*CoinMarketCap.IFearGreedRange, error) {
+	fromDay, toDay, err := parseRange(s.now(), from, to, DefaultRangeDays)
+	if err != nil {
+		return nil, err
+	}
+
+	if interval == "" {
+		interval = CoinMarketCap.IntervalDaily
+	}
+	if interval != CoinMarketCap.IntervalDaily && interval != CoinMarketCap.IntervalWeekly && interval != CoinMarketCap.IntervalMonthly {
+		return nil, app_errors.NewValidationError("interval", interval, "Interval must be one of daily, weekly or monthly")
+	}
+
+	result := &CoinMarketCap.IFearGreedRange{
+		From:     fromDay.Format(time.DateOnly),
+		To:       toDay.Format(time.DateOnly),
+		Interval: interval,
+		Points:   []CoinMarketCap.IFearGreedPoint{},
+		Sources:  []string{},
+	}
+
+	days, err := s.repo.FindRange(result.From, result.To)
+	if err != nil {
+		return nil, app_errors.ErrDatabaseError
+	}
+	if len(days) == 0 {
+		return result, nil
+	}
+
+	all := make([]int, 0, len(days))
+	var bucket []int
+	var bucketStart string
+	flush := func() {
+		if len(bucket) == 0 {
+			return
+		}
+		point := CoinMarketCap.IFearGreedPoint{Date: bucketStart, IFearGreedStats: stats(bucket)}
+		point.Classification = Classify(int(math.Round(point.Avg)))
+		result.Points = append(result.Points, point)
+		bucket = nil
+	}
+
+	for _, day := range days {
+		all = append(all, day.Value)
+		if day.Source != "" && !slices.Contains(result.Sources, day.Source) {
+			result.Sources = append(result.Sources, day.Source)
+		}
+		if interval == CoinMarketCap.IntervalDaily {
+			result.Points = append(result.Points, CoinMarketCap.IFearGreedPoint{
+				Date:            day.Date,
+				Classification:  day.Classification,
+				IFearGreedStats: stats([]int{day.Value}),
+			})
+			continue
+		}
+
+		start := periodStart(day.Date, interval)
+		if start != bucketStart {
+			flush()
+			bucketStart = start
+		}
+		bucket = append(bucket, day.Value)
+	}
+	flush()
+
+	total := stats(all)
+	result.Stats = &total
+	return result, nil
+}
+
+// parseRange reads a range of YYYY-MM-DD days. to defaults to the day of now, and from to
+// defaultDays before to.
+func parseRange(now time.Time, from, to string, defaultDays int) (time.Time, time.Time, error) {
+	toDay := now.UTC().Truncate(24 * time.Hour)
+	if to != "" {
+		parsed, err := time.Parse(time.DateOnly, to)
+		if err != nil {
+			return time.Time{}, time.Time{}, app_errors.NewValidationError("to", to, "to must be a date like 2024-01-31")
+		}
+		toDay = parsed
+	}
+
+	fromDay := toDay.AddDate(0, 0, -defaultDays)
+	if from != "" {
+		parsed, err := time.Parse(time.DateOnly, from)
+		if err != nil {
+			return time.Time{}, time.Time{}, app_errors.NewValidationError("from", from, "from must be a date like 2024-01-01")
+		}
+		fromDay = parsed
+	}
+	if fromDay.After(toDay) {
+		return time.Time{}, time.Time{}, app_errors.NewValidationError("from", from, "from must not be after to")
+	}
+	return fromDay, toDay, nil
+}
+
+// Classify returns the CoinMarketCap classification of an index value
+func Classify(value int) string {
+	switch {
+	case value < 25:
+		return "Extreme Fear"
+	case value < 45:
+		return "Fear"
+	case value <= 55:
+		return "Neutral"
+	case value <= 75:
+		return "Greed"
+	default:
+		return "Extreme Greed"
+	}
+}
+
+// periodStart returns the first day of the ISO week (Monday) or month of a YYYY-MM-DD day
+func periodStart(date, interval string) string {
+	day, _ := time.Parse(time.DateOnly, date)
+	if interval == CoinMarketCap.IntervalMonthly {
+		return day.Format("2006-01") + "-01"
+	}
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset).Format(time.DateOnly)
+}
+
+// stats computes the min, max, average and median of a non-empty set of values
+func stats(values []int) CoinMarketCap.IFearGreedStats {
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+
+	sum := 0
+	for _, v := range sorted {
+		sum += v
+	}
+
+	n := len(sorted)
+	median := float64(sorted[n/2])
+	if n%2 == 0 {
+		median = float64(sorted[n/2-1]+sorted[n/2]) / 2
+	}
+
+	return CoinMarketCap.IFearGreedStats{
+		Min:    sorted[0],
+		Max:    sorted[n-1],
+		Avg:    math.Round(float64(sum)/float64(n)*100) / 100,
+		Median: median,
+		Count:  n,
+	}
+}
+
+// parseTimestamp reads the timestamp of a history point, sent as UNIX seconds or as an ISO
+// date, and returns its UTC day
+func parseTimestamp(raw string) (time.Time, error) {
+	if seconds, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC().Truncate(24 * time.Hour), nil
+	}
+	parsed, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", raw)
+	}
+	return parsed.UTC().Truncate(24 * time.Hour), nil
+}
@@ -0,0 +1,340 @@
+// Package services provides  coin market cap services for external API interactions.
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	Repositories "cry-api/app/repositories"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+	app_errors "cry-api/app/types/errors"
+)
+
+const (
+	// DefaultAnalyticsDays is the length of the analysed range when no from date is given
+	DefaultAnalyticsDays = 365
+	// DefaultCorrelationWindow is the number of days of the rolling correlation
+	DefaultCorrelationWindow = 30
+	// MinCorrelationWindow and MaxCorrelationWindow bound the rolling correlation window
+	MinCorrelationWindow = 7
+	MaxCorrelationWindow = 365
+)
+
+// ForwardHorizons are the numbers of days the forward returns are measured over
+var ForwardHorizons = []int{1, 7, 30, 90}
+
+// Classifications are the classifications of the index, from the most fearful
+var Classifications = []string{"Extreme Fear", "Fear", "Neutral", "Greed", "Extreme Greed"}
+
+// extremes are the classifications forward returns are computed after
+var extremes = []string{"Extreme Fear", "Extreme Greed"}
+
+// FearGreedAnalyticsService relates the fear and greed index to the BTC price. It only reads
+// the local copies of both series kept by the FearGreedService and the PriceHistoryService.
+type FearGreedAnalyticsService struct {
+	fearGreed Repositories.FearGreedRepository
+	prices    Repositories.PriceHistoryRepository
+	now       func() time.Time
+}
+
+// FearGreedAnalyticsServiceInterface defines the methods for the FearGreedAnalyticsService.
+type FearGreedAnalyticsServiceInterface interface {
+	GetAnalytics(from, to, window string) (*CoinMarketCap.IFearGreedAnalytics, error)
+}
+
+// NewFearGreedAnalyticsService initializes and returns a FearGreedAnalyticsService instance
+func NewFearGreedAnalyticsService(
+	fearGreed Repositories.FearGreedRepository,
+	prices Repositories.PriceHistoryRepository,
+) *FearGreedAnalyticsService {
+	return &FearGreedAnalyticsService{
+		fearGreed: fearGreed,
+		prices:    prices,
+		now:       time.Now,
+	}
+}
+
+// SetClock replaces the time source, for tests
+func (s *FearGreedAnalyticsService) SetClock(now func() time.Time) {
+	s.now = now
+}
+
+// GetAnalytics joins the stored index values and BTC prices between two YYYY-MM-DD days
+// (inclusive), and computes the forward returns after the extreme days, the correlation
+// between the index and the daily return, and the time spent in each classification. to
+// defaults to today, from to 365 days before to, and window to 30 days.
+//
+// Forward returns use the prices after to when they are stored, so that the last days of the
+// range still count.
+func (s *FearGreedAnalyticsService) GetAnalytics(from, to, window string) (*CoinMarketCap.IFearGreedAnalytics, error) {
+	fromDay, toDay, err := parseRange(s.now(), from, to, DefaultAnalyticsDays)
+	if err != nil {
+		return nil, err
+	}
+
+	size := DefaultCorrelationWindow
+	if window != "" {
+		size, err = strconv.Atoi(window)
+		if err != nil || size < MinCorrelationWindow || size > MaxCorrelationWindow {
+			return nil, app_errors.NewValidationError("window", window,
+				fmt.Sprintf("Window must be a number of days between %d and %d", MinCorrelationWindow, MaxCorrelationWindow))
+		}
+	}
+
+	result := &CoinMarketCap.IFearGreedAnalytics{
+		From:                 fromDay.Format(time.DateOnly),
+		To:                   toDay.Format(time.DateOnly),
+		Symbol:               HistorySymbol,
+		Currency:             HistoryCurrency,
+		Window:               size,
+		Series:               []CoinMarketCap.IFearGreedAnalyticsPoint{},
+		ForwardReturns:       []CoinMarketCap.IForwardReturns{},
+		RollingCorrelation:   []CoinMarketCap.IRollingCorrelation{},
+		TimeInClassification: []CoinMarketCap.IClassificationTime{},
+	}
+
+	days, err := s.fearGreed.FindRange(result.From, result.To)
+	if err != nil {
+		return nil, app_errors.ErrDatabaseError
+	}
+	stored, err := s.prices.FindRange(HistorySymbol, HistoryCurrency,
+		fromDay.AddDate(0, 0, -1).Format(time.DateOnly),
+		toDay.AddDate(0, 0, ForwardHorizons[len(ForwardHorizons)-1]).Format(time.DateOnly))
+	if err != nil {
+		return nil, app_errors.ErrDatabaseError
+	}
+
+	prices := make(map[string]float64, len(stored))
+	for _, p := range stored {
+		prices[p.Date] = p.Price
+	}
+
+	for _, day := range days {
+		point := CoinMarketCap.IFearGreedAnalyticsPoint{
+			Date:           day.Date,
+			Value:          day.Value,
+			Classification: day.Classification,
+		}
+		if point.Classification == "" {
+			point.Classification = Classify(day.Value)
+		}
+		if price, ok := prices[day.Date]; ok {
+			point.Price = &price
+			if change, ok := priceChange(prices, day.Date, -1); ok {
+				change = round(change, 4)
+				point.Return = &change
+			}
+		} else {
+			result.MissingPrices++
+		}
+		result.Series = append(result.Series, point)
+	}
+
+	result.ForwardReturns = forwardReturns(result.Series, prices)
+	result.Correlation = correlation(result.Series)
+	result.RollingCorrelation = rollingCorrelation(result.Series, size)
+	result.TimeInClassification = timeInClassification(result.Series)
+	return result, nil
+}
+
+// priceChange returns the change in % of the price between a day and the day offset days
+// after it, a negative offset meaning before
+func priceChange(prices map[string]float64, date string, offset int) (float64, bool) {
+	day, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return 0, false
+	}
+	other, ok := prices[day.AddDate(0, 0, offset).Format(time.DateOnly)]
+	price, found := prices[date]
+	if !ok || !found || other <= 0 || price <= 0 {
+		return 0, false
+	}
+	if offset < 0 {
+		return (price/other - 1) * 100, true
+	}
+	return (other/price - 1) * 100, true
+}
+
+// forwardReturns measures the price changes after the days of the extreme classifications
+func forwardReturns(series []CoinMarketCap.IFearGreedAnalyticsPoint, prices map[string]float64) []CoinMarketCap.IForwardReturns {
+	results := make([]CoinMarketCap.IForwardReturns, 0, len(extremes))
+	for _, classification := range extremes {
+		result := CoinMarketCap.IForwardReturns{
+			Classification: classification,
+			Horizons:       make([]CoinMarketCap.IForwardReturn, 0, len(ForwardHorizons)),
+		}
+		for _, point := range series {
+			if point.Classification == classification {
+				result.Days++
+			}
+		}
+
+		for _, horizon := range ForwardHorizons {
+			var changes []float64
+			for _, point := range series {
+				if point.Classification != classification {
+					continue
+				}
+				if change, ok := priceChange(prices, point.Date, horizon); ok {
+					changes = append(changes, change)
+				}
+			}
+			result.Horizons = append(result.Horizons, summarize(horizon, changes))
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// summarize computes the statistics of the price changes over a horizon
+func summarize(horizon int, changes []float64) CoinMarketCap.IForwardReturn {
+	result := CoinMarketCap.IForwardReturn{Days: horizon, Count: len(changes)}
+	if len(changes) == 0 {
+		return result
+	}
+
+	sorted := append([]float64(nil), changes...)
+	sort.Float64s(sorted)
+
+	sum := 0.0
+	positive := 0
+	for _, change := range sorted {
+		sum += change
+		if change > 0 {
+			positive++
+		}
+	}
+
+	n := len(sorted)
+	median := sorted[n/2]
+	if n%2 == 0 {
+		median = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+
+	avg := round(sum/float64(n), 2)
+	median = round(median, 2)
+	share := round(float64(positive)/float64(n)*100, 2)
+	result.Avg = &avg
+	result.Median = &median
+	result.PositiveShare = &share
+	return result
+}
+
+// correlation returns the correlation between the index value and the daily return over the
+// days of the series that have one
+func correlation(series []CoinMarketCap.IFearGreedAnalyticsPoint) *float64 {
+	var values, returns []float64
+	for _, point := range series {
+		if point.Return != nil {
+			values = append(values, float64(point.Value))
+			returns = append(returns, *point.Return)
+		}
+	}
+	return pearson(values, returns)
+}
+
+// rollingCorrelation returns, for each day of the series, the correlation over the days of the
+// window ending on it. A window needs half of its days to have a return.
+func rollingCorrelation(series []CoinMarketCap.IFearGreedAnalyticsPoint, window int) []CoinMarketCap.IRollingCorrelation {
+	minPairs := max(3, window/2)
+	results := make([]CoinMarketCap.IRollingCorrelation, 0, len(series))
+	for i, point := range series {
+		day, _ := time.Parse(time.DateOnly, point.Date)
+		start := day.AddDate(0, 0, -window+1).Format(time.DateOnly)
+
+		var values, returns []float64
+		for j := i; j >= 0 && series[j].Date >= start; j-- {
+			if series[j].Return != nil {
+				values = append(values, float64(series[j].Value))
+				returns = append(returns, *series[j].Return)
+			}
+		}
+
+		result := CoinMarketCap.IRollingCorrelation{Date: point.Date}
+		if len(values) >= minPairs {
+			result.Correlation = pearson(values, returns)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// pearson returns the Pearson correlation coefficient of two series, nil when they have fewer
+// than two points or one of them is constant
+func pearson(xs, ys []float64) *float64 {
+	n := float64(len(xs))
+	if len(xs) < 2 {
+		return nil
+	}
+
+	var sumX, sumY float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var cov, varX, varY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return nil
+	}
+
+	r := round(cov/math.Sqrt(varX*varY), 4)
+	return &r
+}
+
+// timeInClassification counts the days and longest streak of each classification. Streaks
+// are broken by a missing day.
+func timeInClassification(series []CoinMarketCap.IFearGreedAnalyticsPoint) []CoinMarketCap.IClassificationTime {
+	buckets := make(map[string]*CoinMarketCap.IClassificationTime, len(Classifications))
+	order := append([]string(nil), Classifications...)
+	for _, classification := range Classifications {
+		buckets[classification] = &CoinMarketCap.IClassificationTime{Classification: classification}
+	}
+
+	streak := 0
+	var previous time.Time
+	for i, point := range series {
+		bucket, ok := buckets[point.Classification]
+		if !ok {
+			bucket = &CoinMarketCap.IClassificationTime{Classification: point.Classification}
+			buckets[point.Classification] = bucket
+			order = append(order, point.Classification)
+		}
+		bucket.Days++
+
+		day, _ := time.Parse(time.DateOnly, point.Date)
+		if i > 0 && series[i-1].Classification == point.Classification && day.Sub(previous) == 24*time.Hour {
+			streak++
+		} else {
+			streak = 1
+		}
+		bucket.LongestStreak = max(bucket.LongestStreak, streak)
+		previous = day
+	}
+
+	results := make([]CoinMarketCap.IClassificationTime, 0, len(order))
+	for _, classification := range order {
+		bucket := buckets[classification]
+		if len(series) > 0 {
+			bucket.Share = round(float64(bucket.Days)/float64(len(series))*100, 2)
+		}
+		results = append(results, *bucket)
+	}
+	return results
+}
+
+// round rounds a value to a number of decimals
+func round(value float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(value*scale) / scale
+}
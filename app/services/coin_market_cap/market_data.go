@@ -0,0 +1,430 @@
+// Package services provides  coin market cap services for external API interactions.
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"cry-api/app/logger"
+	"cry-api/app/money"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+	app_errors "cry-api/app/types/errors"
+)
+
+const (
+	// DefaultConvert is the fiat currency used when none is requested
+	DefaultConvert = "USD"
+	// MaxQuoteAssets is the most symbols or ids a quotes request may list
+	MaxQuoteAssets = 100
+	// DefaultCandleDays is the length of the OHLCV history served when no from time is given
+	DefaultCandleDays = 30
+	// MaxHourlyCandleDays is the longest range of hourly candles that can be requested
+	MaxHourlyCandleDays = 31
+	// MaxCandleDays is the longest range of daily, weekly or monthly candles that can be requested
+	MaxCandleDays = 3660
+)
+
+var (
+	symbolPattern   = regexp.MustCompile(`^[A-Z0-9]{1,20}$`)
+	currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// OHLCVQuery is a validated OHLCV history request
+type OHLCVQuery struct {
+	Symbol   string
+	Convert  string
+	Interval string
+	From     time.Time
+	To       time.Time
+}
+
+// GetLatestQuotes fetches the latest market data of assets, listed either by symbol or by
+// CoinMarketCap id, in a fiat currency (USD by default). Quotes follow the order of the
+// request, and assets CoinMarketCap does not know are left out. When several assets share a
+// symbol, the best ranked one is returned.
+func (s *CoinMarketCapService) GetLatestQuotes(ctx context.Context, symbols []string, ids []int, convert string) ([]CoinMarketCap.IQuote, error) {
+	symbols, ids, convert, err := NormalizeQuotesQuery(symbols, ids, convert)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("convert", convert)
+	query.Set("skip_invalid", "true")
+	keys := symbols
+	if len(symbols) > 0 {
+		query.Set("symbol", strings.Join(symbols, ","))
+	} else {
+		keys = make([]string, len(ids))
+		for i, id := range ids {
+			keys[i] = strconv.Itoa(id)
+		}
+		query.Set("id", strings.Join(keys, ","))
+	}
+
+	var data CoinMarketCap.AssetsResponse
+	if err := s.get(ctx, "/v2/cryptocurrency/quotes/latest", query, &data); err != nil {
+		return nil, upstreamError(ctx, "Failed to fetch quotes", err)
+	}
+
+	quotes := []CoinMarketCap.IQuote{}
+	for _, key := range keys {
+		assets, err := decodeAssets[CoinMarketCap.LatestQuoteAsset](data.Data[key])
+		if err != nil {
+			return nil, upstreamError(ctx, "Failed to fetch quotes", err)
+		}
+		asset := bestRanked(assets)
+		if asset == nil {
+			continue
+		}
+		fx, ok := asset.Quote[convert]
+		if !ok {
+			continue
+		}
+		values, err := money.Decimals(fx.Price, fx.Volume24h, fx.MarketCap, fx.PercentChange1h, fx.PercentChange24h, fx.PercentChange7d)
+		if err != nil {
+			return nil, upstreamError(ctx, "Failed to fetch quotes", err)
+		}
+		quotes = append(quotes, CoinMarketCap.IQuote{
+			ID:               asset.ID,
+			Symbol:           asset.Symbol,
+			Name:             asset.Name,
+			Slug:             asset.Slug,
+			Rank:             asset.CMCRank,
+			Currency:         convert,
+			Price:            values[0],
+			Volume24h:        values[1],
+			MarketCap:        values[2],
+			PercentChange1h:  values[3],
+			PercentChange24h: values[4],
+			PercentChange7d:  values[5],
+			LastUpdated:      fx.LastUpdated,
+			Source:           ProviderName,
+		})
+	}
+	return quotes, nil
+}
+
+// GetOHLCV fetches the candles of an asset between two times at an interval (daily by
+// default), oldest first. to defaults to now and from to 30 days before to.
+func (s *CoinMarketCapService) GetOHLCV(ctx context.Context, symbol, convert, interval string, from, to time.Time) (*CoinMarketCap.IOHLCV, error) {
+	q, err := NormalizeOHLCVQuery(symbol, convert, interval, from, to, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	timePeriod := CoinMarketCap.CandleDaily
+	if q.Interval == CoinMarketCap.CandleHourly {
+		timePeriod = CoinMarketCap.CandleHourly
+	}
+
+	query := url.Values{}
+	query.Set("symbol", q.Symbol)
+	query.Set("convert", q.Convert)
+	query.Set("time_period", timePeriod)
+	query.Set("interval", q.Interval)
+	query.Set("time_start", q.From.Format(time.RFC3339))
+	query.Set("time_end", q.To.Format(time.RFC3339))
+
+	var data CoinMarketCap.AssetsResponse
+	if err := s.get(ctx, "/v2/cryptocurrency/ohlcv/historical", query, &data); err != nil {
+		return nil, upstreamError(ctx, "Failed to fetch OHLCV history", err)
+	}
+
+	assets, err := decodeAssets[CoinMarketCap.OHLCVAsset](data.Data[q.Symbol])
+	if err != nil {
+		return nil, upstreamError(ctx, "Failed to fetch OHLCV history", err)
+	}
+	if len(assets) == 0 {
+		return nil, app_errors.NewNotFoundError("asset", fmt.Sprintf("No market data for %s", q.Symbol))
+	}
+
+	result := &CoinMarketCap.IOHLCV{
+		Symbol:   q.Symbol,
+		Currency: q.Convert,
+		Interval: q.Interval,
+		From:     q.From,
+		To:       q.To,
+		Candles:  []CoinMarketCap.ICandle{},
+		Source:   ProviderName,
+	}
+	for _, period := range assets[0].Quotes {
+		fx, ok := period.Quote[q.Convert]
+		if !ok {
+			continue
+		}
+		values, err := money.Decimals(fx.Open, fx.High, fx.Low, fx.Close, fx.Volume, fx.MarketCap)
+		if err != nil {
+			return nil, upstreamError(ctx, "Failed to fetch OHLCV history", err)
+		}
+		result.Candles = append(result.Candles, CoinMarketCap.ICandle{
+			TimeOpen:  period.TimeOpen.UTC(),
+			TimeClose: period.TimeClose.UTC(),
+			Open:      values[0],
+			High:      values[1],
+			Low:       values[2],
+			Close:     values[3],
+			Volume:    values[4],
+			MarketCap: values[5],
+		})
+	}
+	return result, nil
+}
+
+// ConvertPrice values an amount of an asset in a fiat currency (USD by default) at the latest price
+func (s *CoinMarketCapService) ConvertPrice(ctx context.Context, amount *big.Rat, symbol, convert string) (*CoinMarketCap.IConversion, error) {
+	symbol, convert, err := NormalizeConversion(amount, symbol, convert)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("amount", money.FormatDecimal(amount))
+	query.Set("symbol", symbol)
+	query.Set("convert", convert)
+
+	var data CoinMarketCap.PriceConversionResponse
+	if err := s.get(ctx, "/v2/tools/price-conversion", query, &data); err != nil {
+		return nil, upstreamError(ctx, "Failed to convert price", err)
+	}
+
+	assets, err := decodeAssets[CoinMarketCap.PriceConversionAsset](data.Data)
+	if err != nil {
+		return nil, upstreamError(ctx, "Failed to convert price", err)
+	}
+	if len(assets) == 0 {
+		return nil, app_errors.NewNotFoundError("asset", fmt.Sprintf("No market data for %s", symbol))
+	}
+	fx, ok := assets[0].Quote[convert]
+	if !ok {
+		return nil, app_errors.NewNotFoundError("asset", fmt.Sprintf("No %s price for %s", convert, symbol))
+	}
+
+	value, err := money.FromNumber(fx.Price)
+	if err != nil {
+		return nil, upstreamError(ctx, "Failed to convert price", err)
+	}
+	return NewConversion(symbol, convert, amount, new(big.Rat).Quo(value, amount), fx.LastUpdated, ProviderName)
+}
+
+// NewConversion values an amount of an asset at the price of one unit
+func NewConversion(symbol, convert string, amount, rate *big.Rat, lastUpdated time.Time, source string) (*CoinMarketCap.IConversion, error) {
+	value, err := money.FromRat(new(big.Rat).Mul(amount, rate), convert)
+	if err != nil {
+		return nil, app_errors.NewValidationError("amount", money.FormatDecimal(amount), "Amount is too large")
+	}
+	return &CoinMarketCap.IConversion{
+		Symbol:      symbol,
+		Amount:      money.FormatDecimal(amount),
+		Currency:    convert,
+		Rate:        money.FormatDecimal(rate),
+		Value:       value,
+		LastUpdated: lastUpdated,
+		Source:      source,
+	}, nil
+}
+
+// NormalizeQuotesQuery upper-cases and deduplicates the symbols, deduplicates the ids and
+// defaults the currency, checking that exactly one of symbols and ids is given
+func NormalizeQuotesQuery(symbols []string, ids []int, convert string) ([]string, []int, string, error) {
+	convert, err := normalizeConvert(convert)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	var cleanSymbols []string
+	seenSymbols := make(map[string]bool)
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if symbol == "" || seenSymbols[symbol] {
+			continue
+		}
+		if !symbolPattern.MatchString(symbol) {
+			return nil, nil, "", app_errors.NewValidationError("symbols", symbol, "Symbols must be 1 to 20 letters or digits")
+		}
+		seenSymbols[symbol] = true
+		cleanSymbols = append(cleanSymbols, symbol)
+	}
+
+	var cleanIDs []int
+	seenIDs := make(map[int]bool)
+	for _, id := range ids {
+		if id < 1 {
+			return nil, nil, "", app_errors.NewValidationError("ids", strconv.Itoa(id), "Ids must be positive integers")
+		}
+		if !seenIDs[id] {
+			seenIDs[id] = true
+			cleanIDs = append(cleanIDs, id)
+		}
+	}
+
+	switch {
+	case len(cleanSymbols) == 0 && len(cleanIDs) == 0:
+		return nil, nil, "", app_errors.NewValidationError("symbols", "", "Either symbols or ids is required")
+	case len(cleanSymbols) > 0 && len(cleanIDs) > 0:
+		return nil, nil, "", app_errors.NewValidationError("ids", "", "Symbols and ids cannot be combined")
+	case len(cleanSymbols)+len(cleanIDs) > MaxQuoteAssets:
+		return nil, nil, "", app_errors.NewValidationError("symbols", "", fmt.Sprintf("At most %d assets can be quoted at once", MaxQuoteAssets))
+	}
+	return cleanSymbols, cleanIDs, convert, nil
+}
+
+// NormalizeOHLCVQuery validates an OHLCV history request and applies its defaults. Times are
+// truncated to the hour for hourly candles and to the UTC day otherwise.
+func NormalizeOHLCVQuery(symbol, convert, interval string, from, to, now time.Time) (*OHLCVQuery, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if !symbolPattern.MatchString(symbol) {
+		return nil, app_errors.NewValidationError("symbol", symbol, "Symbol must be 1 to 20 letters or digits")
+	}
+	convert, err := normalizeConvert(convert)
+	if err != nil {
+		return nil, err
+	}
+
+	if interval == "" {
+		interval = CoinMarketCap.CandleDaily
+	}
+	maxDays := MaxCandleDays
+	precision := 24 * time.Hour
+	switch interval {
+	case CoinMarketCap.CandleHourly:
+		maxDays = MaxHourlyCandleDays
+		precision = time.Hour
+	case CoinMarketCap.CandleDaily, CoinMarketCap.CandleWeekly, CoinMarketCap.CandleMonthly:
+	default:
+		return nil, app_errors.NewValidationError("interval", interval, "Interval must be one of hourly, daily, weekly or monthly")
+	}
+
+	if to.IsZero() {
+		to = now
+	}
+	to = to.UTC().Truncate(precision)
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -DefaultCandleDays)
+	}
+	from = from.UTC().Truncate(precision)
+
+	if !from.Before(to) {
+		return nil, app_errors.NewValidationError("from", from.Format(time.RFC3339), "from must be before to")
+	}
+	if to.Sub(from) > time.Duration(maxDays)*24*time.Hour {
+		return nil, app_errors.NewValidationError("from", from.Format(time.RFC3339),
+			fmt.Sprintf("%s candles can span at most %d days", interval, maxDays))
+	}
+
+	return &OHLCVQuery{Symbol: symbol, Convert: convert, Interval: interval, From: from, To: to}, nil
+}
+
+// NormalizeConversion validates a price conversion request, returning the upper-cased symbol
+// and the currency, USD by default
+func NormalizeConversion(amount *big.Rat, symbol, convert string) (string, string, error) {
+	if amount == nil || amount.Sign() <= 0 {
+		value := ""
+		if amount != nil {
+			value = money.FormatDecimal(amount)
+		}
+		return "", "", app_errors.NewValidationError("amount", value, "Amount must be a positive number")
+	}
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if !symbolPattern.MatchString(symbol) {
+		return "", "", app_errors.NewValidationError("symbol", symbol, "Symbol must be 1 to 20 letters or digits")
+	}
+	convert, err := normalizeConvert(convert)
+	if err != nil {
+		return "", "", err
+	}
+	if !money.IsSupported(convert) {
+		return "", "", app_errors.NewValidationError("convert", convert, "Currency is not supported")
+	}
+	return symbol, convert, nil
+}
+
+func normalizeConvert(convert string) (string, error) {
+	convert = strings.ToUpper(strings.TrimSpace(convert))
+	if convert == "" {
+		return DefaultConvert, nil
+	}
+	if !currencyPattern.MatchString(convert) {
+		return "", app_errors.NewValidationError("convert", convert, "Currency must be an ISO-4217 code like USD")
+	}
+	return convert, nil
+}
+
+// get performs an authenticated GET request on the CoinMarketCap API and decodes its body
+func (s *CoinMarketCapService) get(ctx context.Context, path string, query url.Values, out any) error {
+	endpoint := fmt.Sprintf("%s%s?%s", s.Config.CoinMarketCapConfig.API, path, query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	req.Header.Set("X-CMC_PRO_API_KEY", s.Config.CoinMarketCapConfig.APIKey)
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to perform HTTP request: %w", err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return apiError(resp)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response body: %w", err)
+	}
+	return nil
+}
+
+// decodeAssets reads the assets of a payload entry, sent as an array when queried by symbol
+// and as a single object when queried by id. A missing entry has no assets.
+func decodeAssets[T any](raw json.RawMessage) ([]T, error) {
+	trimmed := strings.TrimSpace(string(raw))
+	if trimmed == "" || trimmed == "null" {
+		return nil, nil
+	}
+	if strings.HasPrefix(trimmed, "[") {
+		var assets []T
+		if err := json.Unmarshal(raw, &assets); err != nil {
+			return nil, fmt.Errorf("failed to decode assets: %w", err)
+		}
+		return assets, nil
+	}
+
+	var asset T
+	if err := json.Unmarshal(raw, &asset); err != nil {
+		return nil, fmt.Errorf("failed to decode asset: %w", err)
+	}
+	return []T{asset}, nil
+}
+
+// bestRanked returns the best ranked of the assets sharing a symbol, unranked assets last
+func bestRanked(assets []CoinMarketCap.LatestQuoteAsset) *CoinMarketCap.LatestQuoteAsset {
+	var best *CoinMarketCap.LatestQuoteAsset
+	for i := range assets {
+		asset := &assets[i]
+		if best == nil || (asset.CMCRank > 0 && (best.CMCRank == 0 || asset.CMCRank < best.CMCRank)) {
+			best = asset
+		}
+	}
+	return best
+}
+
+// upstreamError logs a failed CoinMarketCap call and reports it without the upstream error,
+// which can quote the response body
+func upstreamError(ctx context.Context, message string, err error) error {
+	logger.FromContext(ctx).WithError(err).Warn(message)
+	return app_errors.NewUpstreamError(message, err)
+}
@@ -0,0 +1,181 @@
+// Package services provides  coin market cap services for external API interactions.
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cry-api/app/logger"
+	UserModel "cry-api/app/models"
+	Repositories "cry-api/app/repositories"
+	EnvTypes "cry-api/app/types/env"
+)
+
+const (
+	// HistorySymbol is the asset whose daily prices are stored locally
+	HistorySymbol = "BTC"
+	// HistoryCurrency is the fiat currency of the stored prices
+	HistoryCurrency = "USD"
+	// PriceSyncChunkDays is the number of days requested at once
+	PriceSyncChunkDays = 90
+	// DefaultPriceBackfillDays is the length of the backfill when no fear and greed history is
+	// stored to align it with
+	DefaultPriceBackfillDays = 365
+)
+
+// PriceHistoryService keeps a local copy of the daily BTC prices in USD covering the local
+// fear and greed history, so that analytics joining both series never call the providers.
+// Sync pulls the new days, then backfills the days older than the oldest stored one.
+type PriceHistoryService struct {
+	config     *EnvTypes.EnvConfig
+	upstream   CoinMarketCapServiceInterface
+	repo       Repositories.PriceHistoryRepository
+	fearGreed  Repositories.FearGreedRepository
+	now        func() time.Time
+	backfilled bool
+}
+
+// PriceHistoryServiceInterface defines the methods for the PriceHistoryService.
+type PriceHistoryServiceInterface interface {
+	Run(ctx context.Context)
+	Sync(ctx context.Context) (int, error)
+}
+
+// NewPriceHistoryService initializes and returns a PriceHistoryService instance. upstream
+// should not be cached, so that syncs see the latest prices.
+func NewPriceHistoryService(
+	cfg *EnvTypes.EnvConfig,
+	upstream CoinMarketCapServiceInterface,
+	repo Repositories.PriceHistoryRepository,
+	fearGreed Repositories.FearGreedRepository,
+) *PriceHistoryService {
+	return &PriceHistoryService{
+		config:    cfg,
+		upstream:  upstream,
+		repo:      repo,
+		fearGreed: fearGreed,
+		now:       time.Now,
+	}
+}
+
+// SetClock replaces the time source, for tests
+func (s *PriceHistoryService) SetClock(now func() time.Time) {
+	s.now = now
+}
+
+// Run syncs the prices every CoinMarketCapConfig.PriceHistorySyncInterval seconds until ctx is
+// done. It returns at once when the interval is 0.
+func (s *PriceHistoryService) Run(ctx context.Context) {
+	interval := time.Duration(s.config.CoinMarketCapConfig.PriceHistorySyncInterval) * time.Second
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		stored, err := s.Sync(ctx)
+		if err != nil && ctx.Err() == nil {
+			logger.GetLogger().WithError(err).WithField("stored", stored).Error("Price history sync failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync pulls the days from the latest stored one to today, then the days between the start of
+// the fear and greed history and the oldest stored day. It returns the number of days stored.
+//
+// The latest stored day is pulled again, as its price may have been the intraday one. Once a
+// backfill completed, older days are not requested again, as the providers may not serve them.
+func (s *PriceHistoryService) Sync(ctx context.Context) (int, error) {
+	today := s.now().UTC().Truncate(24 * time.Hour)
+
+	start := today.AddDate(0, 0, -DefaultPriceBackfillDays)
+	first, err := s.fearGreed.Earliest()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load the oldest fear and greed day: %w", err)
+	}
+	if first != nil {
+		if day, err := time.Parse(time.DateOnly, first.Date); err == nil {
+			start = day
+		}
+	}
+
+	latest, err := s.repo.Latest(HistorySymbol, HistoryCurrency)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load the latest stored price: %w", err)
+	}
+	if latest == nil {
+		stored, err := s.fetch(ctx, start, today)
+		if err == nil {
+			s.backfilled = true
+		}
+		return stored, err
+	}
+
+	from, err := time.Parse(time.DateOnly, latest.Date)
+	if err != nil {
+		return 0, fmt.Errorf("invalid stored day %q", latest.Date)
+	}
+	stored, err := s.fetch(ctx, from, today)
+	if err != nil || s.backfilled {
+		return stored, err
+	}
+
+	earliest, err := s.repo.Earliest(HistorySymbol, HistoryCurrency)
+	if err != nil {
+		return stored, fmt.Errorf("failed to load the oldest stored price: %w", err)
+	}
+	if oldest, err := time.Parse(time.DateOnly, earliest.Date); err == nil && start.Before(oldest) {
+		backfill, err := s.fetch(ctx, start, oldest.AddDate(0, 0, -1))
+		stored += backfill
+		if err != nil {
+			return stored, err
+		}
+	}
+	s.backfilled = true
+	return stored, nil
+}
+
+// fetch pulls and stores the prices between two days (inclusive), PriceSyncChunkDays at a time
+func (s *PriceHistoryService) fetch(ctx context.Context, from, to time.Time) (int, error) {
+	stored := 0
+	for start := from; !start.After(to); start = start.AddDate(0, 0, PriceSyncChunkDays) {
+		end := start.AddDate(0, 0, PriceSyncChunkDays-1)
+		if end.After(to) {
+			end = to
+		}
+
+		points, err := s.upstream.GetHistoricalPrices(ctx, HistorySymbol, HistoryCurrency, start, end)
+		if err != nil {
+			return stored, fmt.Errorf("failed to fetch the price history: %w", err)
+		}
+
+		days := make([]UserModel.PriceHistory, 0, len(points))
+		for _, p := range points {
+			if p.Price == nil || p.Price.Sign() <= 0 {
+				continue
+			}
+			// The stored prices only feed the return and correlation statistics
+			price, _ := p.Price.Float64()
+			days = append(days, UserModel.PriceHistory{
+				Symbol:   HistorySymbol,
+				Currency: HistoryCurrency,
+				Date:     p.Date,
+				Price:    price,
+			})
+		}
+		if err := s.repo.Upsert(days); err != nil {
+			return stored, fmt.Errorf("failed to store prices: %w", err)
+		}
+		stored += len(days)
+	}
+	return stored, nil
+}
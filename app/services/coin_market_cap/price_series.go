@@ -0,0 +1,50 @@
+// Package services provides  coin market cap services for external API interactions.
+package services
+
+import (
+	"math/big"
+	"sort"
+
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+)
+
+// PriceSeries answers "what was the price on this day" for a list of daily price points,
+// falling back to the closest earlier day (or the first known day) when a point is missing.
+type PriceSeries struct {
+	dates  []string
+	prices map[string]*big.Rat
+}
+
+// NewPriceSeries indexes daily price points by date (YYYY-MM-DD)
+func NewPriceSeries(points []CoinMarketCap.IPricePoint) *PriceSeries {
+	series := &PriceSeries{prices: make(map[string]*big.Rat, len(points))}
+	for _, p := range points {
+		if _, ok := series.prices[p.Date]; !ok {
+			series.dates = append(series.dates, p.Date)
+		}
+		series.prices[p.Date] = p.Price
+	}
+	sort.Strings(series.dates)
+	return series
+}
+
+// Len returns the number of days in the series
+func (p *PriceSeries) Len() int {
+	return len(p.dates)
+}
+
+// On returns the price of a day (YYYY-MM-DD), or 0 when the series is empty. The price is
+// shared with the series and must not be modified.
+func (p *PriceSeries) On(day string) *big.Rat {
+	if len(p.dates) == 0 {
+		return new(big.Rat)
+	}
+	if price, ok := p.prices[day]; ok {
+		return price
+	}
+	i := sort.SearchStrings(p.dates, day)
+	if i == 0 {
+		return p.prices[p.dates[0]]
+	}
+	return p.prices[p.dates[i-1]]
+}
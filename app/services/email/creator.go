@@ -20,3 +20,13 @@ func (e *EmailCreatorImpl) CreateResetPasswordRequestEmail(to, from, userName, r
 func (e *EmailCreatorImpl) CreateTwoFactorAlternativeEmail(to, from, userName, otp string, expiryMinutes int) (Email.EmailMessage, error) {
 	return Email.CreateTwoFactorAlternativeEmail(to, from, userName, otp, expiryMinutes)
 }
+
+// CreateWalletNotificationEmail creates a watched wallet activity email
+func (e *EmailCreatorImpl) CreateWalletNotificationEmail(to, from, userName, title, message, notificationsLink string) (Email.EmailMessage, error) {
+	return Email.CreateWalletNotificationEmail(to, from, userName, title, message, notificationsLink)
+}
+
+// CreateAlertEmail creates a triggered alert email
+func (e *EmailCreatorImpl) CreateAlertEmail(to, from, userName, title, message, alertsLink string) (Email.EmailMessage, error) {
+	return Email.CreateAlertEmail(to, from, userName, title, message, alertsLink)
+}
@@ -2,9 +2,8 @@
 package services
 
 import (
-	"log"
-
 	Email "cry-api/app/email"
+	"cry-api/app/logger"
 	"cry-api/app/utils"
 )
 
@@ -13,6 +12,8 @@ type EmailServiceInterface interface {
 	SendVerifyAccountEmail(to, from, username, verificationLink, verificationToken string) error
 	SendResetPasswordEmail(to, from, username, resetPasswordLink, APIURL string) error
 	SendTwoFactorAlternativeEmail(to, from, username, otp string, expiryMinutes int) error
+	SendWalletNotificationEmail(to, from, username, title, message, notificationsLink string) error
+	SendAlertEmail(to, from, username, title, message, alertsLink string) error
 }
 
 // EmailSender is an interface for sending emails
@@ -25,6 +26,8 @@ type EmailCreator interface {
 	CreateVerifyAccountEmail(to, from, userName, verificationLink, verificationToken string) (Email.EmailMessage, error)
 	CreateResetPasswordRequestEmail(to, from, userName, resetPasswordLink, APIURL string) (Email.EmailMessage, error)
 	CreateTwoFactorAlternativeEmail(to, from, userName, otp string, expiryMinutes int) (Email.EmailMessage, error)
+	CreateWalletNotificationEmail(to, from, userName, title, message, notificationsLink string) (Email.EmailMessage, error)
+	CreateAlertEmail(to, from, userName, title, message, alertsLink string) (Email.EmailMessage, error)
 }
 
 // EmailService provides operations for sending emails
@@ -63,12 +66,12 @@ func (service *EmailService) SendResetPasswordEmail(to, from, userName, resetPas
 
 	email, err := service.emailCreator.CreateResetPasswordRequestEmail(to, from, userName, resetPasswordLink, APIURL)
 	if err != nil {
-		log.Printf("Error creating email template: %v", err)
+		logger.GetLogger().WithError(err).Error("Failed to create the reset password email")
 	}
 
 	err = service.emailSender.Send(email)
 	if err != nil {
-		log.Printf("error sending the email: %v", err)
+		logger.GetLogger().WithError(err).Error("Failed to send the reset password email")
 	}
 
 	return nil
@@ -81,16 +84,54 @@ func (service *EmailService) SendTwoFactorAlternativeEmail(to, from, userName, o
 
 	email, err := service.emailCreator.CreateTwoFactorAlternativeEmail(to, from, userName, otp, expiryMinutes)
 	if err != nil {
-		log.Printf("Error creating 2FA email template: %v", err)
+		logger.GetLogger().WithError(err).Error("Failed to create the 2FA email")
 		return err
 	}
 
 	err = service.emailSender.Send(email)
 	if err != nil {
-		log.Printf("Error sending 2FA email: %v", err)
+		logger.GetLogger().WithError(err).Error("Failed to send the 2FA email")
+		return err
+	}
+
+	logger.GetLogger().Info("2FA alternative email sent")
+	return nil
+}
+
+// SendWalletNotificationEmail creates the watched wallet activity email and sends it
+func (service *EmailService) SendWalletNotificationEmail(to, from, userName, title, message, notificationsLink string) error {
+	to = utils.SanitizeInput(to)
+	userName = utils.SanitizeInput(userName)
+
+	email, err := service.emailCreator.CreateWalletNotificationEmail(to, from, userName, title, message, notificationsLink)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("Failed to create the wallet notification email")
+		return err
+	}
+
+	if err := service.emailSender.Send(email); err != nil {
+		logger.GetLogger().WithError(err).Error("Failed to send the wallet notification email")
+		return err
+	}
+
+	return nil
+}
+
+// SendAlertEmail creates the triggered alert email and sends it
+func (service *EmailService) SendAlertEmail(to, from, userName, title, message, alertsLink string) error {
+	to = utils.SanitizeInput(to)
+	userName = utils.SanitizeInput(userName)
+
+	email, err := service.emailCreator.CreateAlertEmail(to, from, userName, title, message, alertsLink)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("Failed to create the alert email")
+		return err
+	}
+
+	if err := service.emailSender.Send(email); err != nil {
+		logger.GetLogger().WithError(err).Error("Failed to send the alert email")
 		return err
 	}
 
-	log.Printf("2FA alternative email sent successfully to %s", email.To)
 	return nil
 }
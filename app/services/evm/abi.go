@@ -0,0 +1,137 @@
+// Package services provides the EVM explorer services, talking JSON-RPC to EVM chain nodes.
+package services
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// ERC-20 function selectors
+const (
+	selectorBalanceOf = "0x70a08231"
+	selectorDecimals  = "0x313ce567"
+	selectorSymbol    = "0x95d89b41"
+)
+
+// transferTopic is the topic of the ERC-20 Transfer(address,address,uint256) event
+var transferTopic = "0x" + hex.EncodeToString(keccak256([]byte("Transfer(address,address,uint256)")))
+
+var (
+	addressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+	txHashPattern  = regexp.MustCompile(`^0x[0-9a-fA-F]{64}$`)
+)
+
+func keccak256(data []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// ChecksumAddress returns the EIP-55 mixed-case form of a hex address
+func ChecksumAddress(address string) string {
+	lower := strings.ToLower(strings.TrimPrefix(strings.ToLower(address), "0x"))
+	hash := hex.EncodeToString(keccak256([]byte(lower)))
+
+	out := []byte(lower)
+	for i, c := range out {
+		if c >= 'a' && c <= 'f' && hash[i] >= '8' {
+			out[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(out)
+}
+
+// parseQuantity decodes a hex encoded JSON-RPC quantity
+func parseQuantity(s string) (uint64, error) {
+	digits := strings.TrimPrefix(s, "0x")
+	if digits == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(digits, 16, 64)
+}
+
+// parseBig decodes a hex encoded quantity or 32-byte word of any size
+func parseBig(s string) (*big.Int, error) {
+	digits := strings.TrimPrefix(s, "0x")
+	if digits == "" {
+		return new(big.Int), nil
+	}
+	value, ok := new(big.Int).SetString(digits, 16)
+	if !ok {
+		return nil, fmt.Errorf("invalid hex number %q", s)
+	}
+	return value, nil
+}
+
+// addressTopic left-pads an address to a 32-byte log topic
+func addressTopic(address string) string {
+	return "0x" + strings.Repeat("0", 24) + strings.ToLower(strings.TrimPrefix(address, "0x"))
+}
+
+// topicAddress extracts the address held in the low 20 bytes of a log topic
+func topicAddress(topic string) (string, error) {
+	digits := strings.TrimPrefix(topic, "0x")
+	if len(digits) != 64 {
+		return "", fmt.Errorf("invalid address topic %q", topic)
+	}
+	return ChecksumAddress(digits[24:]), nil
+}
+
+// balanceOfCall returns the calldata of balanceOf(address)
+func balanceOfCall(address string) string {
+	return selectorBalanceOf + strings.TrimPrefix(addressTopic(address), "0x")
+}
+
+// decodeABIString decodes a string return value. Some early tokens return a bytes32 instead.
+func decodeABIString(s string) (string, error) {
+	data, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return "", err
+	}
+	if len(data) == 32 {
+		return strings.TrimRight(string(data), "\x00"), nil
+	}
+	if len(data) < 64 {
+		return "", errors.New("string return value too short")
+	}
+
+	offset := new(big.Int).SetBytes(data[:32])
+	if !offset.IsUint64() || offset.Uint64()+32 > uint64(len(data)) {
+		return "", errors.New("string offset out of range")
+	}
+	start := offset.Uint64() + 32
+	length := new(big.Int).SetBytes(data[offset.Uint64():start])
+	if !length.IsUint64() || start+length.Uint64() > uint64(len(data)) {
+		return "", errors.New("string length out of range")
+	}
+	return string(data[start : start+length.Uint64()]), nil
+}
+
+// FormatUnits renders an amount in base units as a decimal number with the given decimals,
+// without trailing zeros
+func FormatUnits(value *big.Int, decimals int) string {
+	digits := new(big.Int).Abs(value).String()
+	sign := ""
+	if value.Sign() < 0 {
+		sign = "-"
+	}
+	if decimals <= 0 {
+		return sign + digits
+	}
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+
+	whole, fraction := digits[:len(digits)-decimals], strings.TrimRight(digits[len(digits)-decimals:], "0")
+	if fraction == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + fraction
+}
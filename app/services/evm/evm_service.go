@@ -0,0 +1,503 @@
+// Package services provides the EVM explorer services, talking JSON-RPC to EVM chain nodes.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"cry-api/app/httpclient"
+	"cry-api/app/logger"
+	EnvTypes "cry-api/app/types/env"
+	app_errors "cry-api/app/types/errors"
+	EVMTypes "cry-api/app/types/evm"
+)
+
+// nativeDecimals is the number of decimals of the native coin of every EVM chain
+const nativeDecimals = 18
+
+// nativeSymbols maps the chain names of EVM_RPC_URLS to their native coin. Other chains are
+// assumed to be Ethereum rollups.
+var nativeSymbols = map[string]string{
+	"polygon":   "POL",
+	"bsc":       "BNB",
+	"avalanche": "AVAX",
+	"gnosis":    "XDAI",
+}
+
+// pricedTokens maps the chain names of EVM_RPC_URLS to the ERC-20 contracts whose balances
+// are valued in fiat, by lower-case address, with the symbol their price is looked up under.
+// Any contract can call itself USDC, so a token is never priced by the symbol it declares.
+var pricedTokens = map[string]map[string]string{
+	"ethereum": {
+		"0xdac17f958d2ee523a2206206994597c13d831ec7": "USDT",
+		"0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48": "USDC",
+		"0x6b175474e89094c44da98b954eedeac495271d0f": "DAI",
+		"0x2260fac5e5542a773aa44fbcfedf7c193bc2c599": "WBTC",
+		"0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2": "ETH",
+		"0x514910771af9ca656af840dff83e8264ecf986ca": "LINK",
+		"0x1f9840a85d5af5bf1d1762f925bdaddc4201f984": "UNI",
+	},
+	"polygon": {
+		"0x3c499c542cef5e3811e1192ce70d8cc03d5c3359": "USDC",
+		"0xc2132d05d31c914a87c6611c10748aeb04b58e8f": "USDT",
+		"0x7ceb23fd6bc0add59e62ac25578270cff1b9f619": "ETH",
+	},
+	"bsc": {
+		"0x55d398326f99059ff775485246999027b3197955": "USDT",
+		"0x8ac76a51cc950d9822d68b83fe1ad97b32cd580d": "USDC",
+	},
+	"arbitrum": {
+		"0xaf88d065e77c8cc2239327c5edb3a432268e5831": "USDC",
+		"0xfd086bc7cd5c481dcc9c85ebe478a1c0b69fcbb9": "USDT",
+		"0x912ce59144191c1204e64559fe8253a0e49e6548": "ARB",
+	},
+	"optimism": {
+		"0x0b2c639c533813f4aa9d7837caf62653d097ff85": "USDC",
+		"0x4200000000000000000000000000000000000042": "OP",
+	},
+	"base": {
+		"0x833589fcd6edb6e08f4c7c32d4f71b54bda02913": "USDC",
+	},
+}
+
+// tokenInfo is the symbol and decimals of an ERC-20 token, when the contract exposes them
+type tokenInfo struct {
+	symbol   string
+	decimals *int
+}
+
+// EVMService reads transactions, balances and ERC-20 transfers from the JSON-RPC nodes of
+// the configured EVM chains.
+type EVMService struct {
+	Config *EnvTypes.EnvConfig
+	Client httpclient.Doer
+	nodes  map[string]*RPCClient
+}
+
+// EVMServiceInterface defines the methods for the EVMService.
+type EVMServiceInterface interface {
+	GetTransaction(ctx context.Context, chain, hash string) (*EVMTypes.IEVMTransaction, error)
+	GetBalances(ctx context.Context, chain, address string, tokens []string) (*EVMTypes.IEVMBalances, error)
+	GetTokenTransfers(ctx context.Context, chain, address string) (*EVMTypes.IEVMTransfers, error)
+}
+
+// NewEVMService initializes and returns an EVMService instance
+func NewEVMService(cfg *EnvTypes.EnvConfig, client httpclient.Doer) *EVMService {
+	nodes := make(map[string]*RPCClient, len(cfg.EVMConfig.Nodes))
+	for chain, url := range cfg.EVMConfig.Nodes {
+		nodes[chain] = NewRPCClient(url, client)
+	}
+	return &EVMService{
+		Config: cfg,
+		Client: client,
+		nodes:  nodes,
+	}
+}
+
+// GetTransaction fetches a transaction with its receipt and decodes its ERC-20 transfers
+func (s *EVMService) GetTransaction(ctx context.Context, chain, hash string) (*EVMTypes.IEVMTransaction, error) {
+	node, err := s.node(chain)
+	if err != nil {
+		return nil, err
+	}
+	if !txHashPattern.MatchString(hash) {
+		return nil, app_errors.NewValidationError("hash", hash, "Transaction hash must be 0x followed by 64 hex characters")
+	}
+	hash = strings.ToLower(hash)
+
+	var tx EVMTypes.RPCTransaction
+	if err := node.Call(ctx, "eth_getTransactionByHash", &tx, hash); err != nil {
+		if errors.Is(err, errNullResult) {
+			return nil, app_errors.NewNotFoundError("transaction", "Transaction not found")
+		}
+		return nil, upstreamError(ctx, err)
+	}
+
+	value, err := parseBig(tx.Value)
+	if err != nil {
+		return nil, upstreamError(ctx, err)
+	}
+	nonce, _ := parseQuantity(tx.Nonce)
+	gasLimit, _ := parseQuantity(tx.Gas)
+
+	result := &EVMTypes.IEVMTransaction{
+		Chain:          chain,
+		Hash:           hash,
+		Status:         EVMTypes.TxStatusPending,
+		From:           ChecksumAddress(tx.From),
+		Nonce:          nonce,
+		Value:          value.String(),
+		ValueAmount:    FormatUnits(value, nativeDecimals),
+		Symbol:         nativeSymbol(chain),
+		GasLimit:       gasLimit,
+		TokenTransfers: []EVMTypes.ITokenTransfer{},
+	}
+	if tx.To != nil {
+		to := ChecksumAddress(*tx.To)
+		result.To = &to
+	}
+	if tx.BlockNumber == nil {
+		return result, nil
+	}
+
+	blockNumber, _ := parseQuantity(*tx.BlockNumber)
+	result.BlockNumber = &blockNumber
+	if tx.BlockHash != nil {
+		result.BlockHash = *tx.BlockHash
+	}
+
+	var receipt EVMTypes.RPCReceipt
+	if err := node.Call(ctx, "eth_getTransactionReceipt", &receipt, hash); err != nil {
+		if errors.Is(err, errNullResult) {
+			return result, nil
+		}
+		return nil, upstreamError(ctx, err)
+	}
+
+	result.Status = EVMTypes.TxStatusSuccess
+	if receipt.Status == "0x0" {
+		result.Status = EVMTypes.TxStatusFailed
+	}
+	if receipt.ContractAddress != nil {
+		contract := ChecksumAddress(*receipt.ContractAddress)
+		result.ContractAddress = &contract
+	}
+
+	gasUsed, _ := parseQuantity(receipt.GasUsed)
+	result.GasUsed = &gasUsed
+	gasPrice := receipt.EffectiveGasPrice
+	if gasPrice == "" {
+		gasPrice = tx.GasPrice // pre-London receipts
+	}
+	if price, err := parseBig(gasPrice); err == nil {
+		fee := new(big.Int).Mul(price, new(big.Int).SetUint64(gasUsed))
+		result.EffectiveGasPrice = price.String()
+		result.Fee = fee.String()
+		result.FeeAmount = FormatUnits(fee, nativeDecimals)
+	}
+
+	tokens := make(map[string]tokenInfo)
+	for _, log := range receipt.Logs {
+		transfer, ok := decodeTransfer(log)
+		if !ok {
+			continue
+		}
+		s.describe(ctx, node, &transfer, tokens)
+		result.TokenTransfers = append(result.TokenTransfers, transfer)
+	}
+	return result, nil
+}
+
+// GetBalances fetches the native balance of an address and its balance of the given tokens
+// and of every token it recently sent or received. Discovered tokens with a zero balance are
+// left out.
+func (s *EVMService) GetBalances(ctx context.Context, chain, address string, tokens []string) (*EVMTypes.IEVMBalances, error) {
+	node, err := s.node(chain)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateAddress("address", address); err != nil {
+		return nil, err
+	}
+	for _, token := range tokens {
+		if err := validateAddress("tokens", token); err != nil {
+			return nil, err
+		}
+	}
+
+	blockNumber, err := s.blockNumber(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+	block := fmt.Sprintf("0x%x", blockNumber)
+
+	var rawBalance string
+	if err := node.Call(ctx, "eth_getBalance", &rawBalance, strings.ToLower(address), block); err != nil {
+		return nil, upstreamError(ctx, err)
+	}
+	native, err := parseBig(rawBalance)
+	if err != nil {
+		return nil, upstreamError(ctx, err)
+	}
+	decimals := nativeDecimals
+
+	result := &EVMTypes.IEVMBalances{
+		Chain:       chain,
+		Address:     ChecksumAddress(address),
+		BlockNumber: blockNumber,
+		Native: EVMTypes.IAssetBalance{
+			Symbol:   nativeSymbol(chain),
+			Decimals: &decimals,
+			Balance:  native.String(),
+			Amount:   FormatUnits(native, nativeDecimals),
+		},
+		Tokens: []EVMTypes.IAssetBalance{},
+	}
+
+	requested := make(map[string]bool)
+	var contracts []string
+	for _, token := range tokens {
+		token = ChecksumAddress(token)
+		if !requested[token] {
+			requested[token] = true
+			contracts = append(contracts, token)
+		}
+	}
+
+	transfers, err := s.transferLogs(ctx, node, address, s.fromBlock(blockNumber), blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	discovered := make(map[string]bool)
+	for _, transfer := range transfers {
+		if !requested[transfer.Token] && !discovered[transfer.Token] {
+			discovered[transfer.Token] = true
+			contracts = append(contracts, transfer.Token)
+		}
+	}
+
+	tokenInfos := make(map[string]tokenInfo)
+	for _, token := range contracts {
+		var raw string
+		if err := node.Call(ctx, "eth_call", &raw, map[string]string{"to": strings.ToLower(token), "data": balanceOfCall(address)}, block); err != nil {
+			var rpcErr *RPCError
+			if errors.As(err, &rpcErr) {
+				continue // not an ERC-20 contract
+			}
+			return nil, upstreamError(ctx, err)
+		}
+		balance, err := parseBig(raw)
+		if err != nil || (balance.Sign() == 0 && !requested[token]) {
+			continue
+		}
+
+		info := s.token(ctx, node, token, tokenInfos)
+		asset := EVMTypes.IAssetBalance{Token: token, Symbol: info.symbol, Decimals: info.decimals, Balance: balance.String()}
+		if info.decimals != nil {
+			asset.Amount = FormatUnits(balance, *info.decimals)
+		}
+		result.Tokens = append(result.Tokens, asset)
+	}
+	return result, nil
+}
+
+// GetTokenTransfers fetches the ERC-20 transfers from and to an address over the last
+// EVMConfig.LogBlockRange blocks, newest first
+func (s *EVMService) GetTokenTransfers(ctx context.Context, chain, address string) (*EVMTypes.IEVMTransfers, error) {
+	node, err := s.node(chain)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateAddress("address", address); err != nil {
+		return nil, err
+	}
+
+	toBlock, err := s.blockNumber(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+	fromBlock := s.fromBlock(toBlock)
+
+	transfers, err := s.transferLogs(ctx, node, address, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	owner := ChecksumAddress(address)
+	tokens := make(map[string]tokenInfo)
+	for i := range transfers {
+		transfer := &transfers[i]
+		s.describe(ctx, node, transfer, tokens)
+		switch {
+		case transfer.From == owner && transfer.To == owner:
+			transfer.Direction = EVMTypes.DirectionSelf
+		case transfer.From == owner:
+			transfer.Direction = EVMTypes.DirectionOut
+		default:
+			transfer.Direction = EVMTypes.DirectionIn
+		}
+	}
+
+	return &EVMTypes.IEVMTransfers{
+		Chain:     chain,
+		Address:   owner,
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Transfers: transfers,
+	}, nil
+}
+
+// node returns the JSON-RPC client of a configured chain
+func (s *EVMService) node(chain string) (*RPCClient, error) {
+	node, ok := s.nodes[chain]
+	if !ok {
+		return nil, app_errors.NewNotFoundError("chain", fmt.Sprintf("Chain %q is not configured", chain))
+	}
+	return node, nil
+}
+
+// blockNumber fetches the number of the latest block
+func (s *EVMService) blockNumber(ctx context.Context, node *RPCClient) (uint64, error) {
+	var raw string
+	if err := node.Call(ctx, "eth_blockNumber", &raw); err != nil {
+		return 0, upstreamError(ctx, err)
+	}
+	number, err := parseQuantity(raw)
+	if err != nil {
+		return 0, upstreamError(ctx, err)
+	}
+	return number, nil
+}
+
+// fromBlock returns the first block of the log window ending at toBlock
+func (s *EVMService) fromBlock(toBlock uint64) uint64 {
+	window := uint64(s.Config.EVMConfig.LogBlockRange)
+	if window == 0 || toBlock < window {
+		return 0
+	}
+	return toBlock - window + 1
+}
+
+// transferLogs fetches the Transfer events sent or received by address between two blocks,
+// newest first
+func (s *EVMService) transferLogs(ctx context.Context, node *RPCClient, address string, fromBlock, toBlock uint64) ([]EVMTypes.ITokenTransfer, error) {
+	topic := addressTopic(address)
+	filters := [][]any{
+		{transferTopic, topic},
+		{transferTopic, nil, topic},
+	}
+
+	seen := make(map[string]bool)
+	transfers := []EVMTypes.ITokenTransfer{}
+	for _, topics := range filters {
+		var logs []EVMTypes.RPCLog
+		filter := map[string]any{
+			"fromBlock": fmt.Sprintf("0x%x", fromBlock),
+			"toBlock":   fmt.Sprintf("0x%x", toBlock),
+			"topics":    topics,
+		}
+		if err := node.Call(ctx, "eth_getLogs", &logs, filter); err != nil && !errors.Is(err, errNullResult) {
+			return nil, upstreamError(ctx, err)
+		}
+
+		for _, log := range logs {
+			transfer, ok := decodeTransfer(log)
+			key := fmt.Sprintf("%s:%d", transfer.TxHash, transfer.LogIndex)
+			if !ok || seen[key] {
+				continue
+			}
+			seen[key] = true
+			transfers = append(transfers, transfer)
+		}
+	}
+
+	sort.Slice(transfers, func(i, j int) bool {
+		if transfers[i].BlockNumber != transfers[j].BlockNumber {
+			return transfers[i].BlockNumber > transfers[j].BlockNumber
+		}
+		return transfers[i].LogIndex > transfers[j].LogIndex
+	})
+	return transfers, nil
+}
+
+// describe adds the token symbol, decimals and formatted amount to a transfer
+func (s *EVMService) describe(ctx context.Context, node *RPCClient, transfer *EVMTypes.ITokenTransfer, tokens map[string]tokenInfo) {
+	info := s.token(ctx, node, transfer.Token, tokens)
+	transfer.Symbol = info.symbol
+	transfer.Decimals = info.decimals
+	if info.decimals != nil {
+		if value, ok := new(big.Int).SetString(transfer.Value, 10); ok {
+			transfer.Amount = FormatUnits(value, *info.decimals)
+		}
+	}
+}
+
+// token returns the symbol and decimals of a token, remembered in tokens for the request.
+// Both are optional in ERC-20, so failed calls leave them empty.
+func (s *EVMService) token(ctx context.Context, node *RPCClient, token string, tokens map[string]tokenInfo) tokenInfo {
+	if info, ok := tokens[token]; ok {
+		return info
+	}
+
+	var info tokenInfo
+	var raw string
+	if err := node.Call(ctx, "eth_call", &raw, map[string]string{"to": strings.ToLower(token), "data": selectorSymbol}, "latest"); err == nil {
+		info.symbol, _ = decodeABIString(raw)
+	}
+	if err := node.Call(ctx, "eth_call", &raw, map[string]string{"to": strings.ToLower(token), "data": selectorDecimals}, "latest"); err == nil {
+		if decimals, err := parseBig(raw); err == nil && decimals.IsInt64() && decimals.Int64() <= 77 {
+			d := int(decimals.Int64())
+			info.decimals = &d
+		}
+	}
+
+	tokens[token] = info
+	return info
+}
+
+// decodeTransfer decodes an ERC-20 Transfer event. ERC-721 transfers, which index the token
+// id as a fourth topic, and removed logs are skipped.
+func decodeTransfer(log EVMTypes.RPCLog) (EVMTypes.ITokenTransfer, bool) {
+	if log.Removed || len(log.Topics) != 3 || !strings.EqualFold(log.Topics[0], transferTopic) {
+		return EVMTypes.ITokenTransfer{}, false
+	}
+
+	from, err := topicAddress(log.Topics[1])
+	if err != nil {
+		return EVMTypes.ITokenTransfer{}, false
+	}
+	to, err := topicAddress(log.Topics[2])
+	if err != nil {
+		return EVMTypes.ITokenTransfer{}, false
+	}
+	value, err := parseBig(log.Data)
+	if err != nil {
+		return EVMTypes.ITokenTransfer{}, false
+	}
+	blockNumber, _ := parseQuantity(log.BlockNumber)
+	logIndex, _ := parseQuantity(log.LogIndex)
+
+	return EVMTypes.ITokenTransfer{
+		Token:       ChecksumAddress(log.Address),
+		From:        from,
+		To:          to,
+		Value:       value.String(),
+		TxHash:      strings.ToLower(log.TransactionHash),
+		BlockNumber: blockNumber,
+		LogIndex:    logIndex,
+	}, true
+}
+
+// nativeSymbol returns the symbol of the native coin of a chain
+func nativeSymbol(chain string) string {
+	if symbol, ok := nativeSymbols[chain]; ok {
+		return symbol
+	}
+	return "ETH"
+}
+
+// PricedSymbol returns the symbol the price of an ERC-20 token is looked up under, and false
+// for the tokens that are not valued
+func PricedSymbol(chain, token string) (string, bool) {
+	symbol, ok := pricedTokens[chain][strings.ToLower(token)]
+	return symbol, ok
+}
+
+// validateAddress checks that value is a 0x prefixed 20-byte hex address
+func validateAddress(field, value string) error {
+	if !addressPattern.MatchString(value) {
+		return app_errors.NewValidationError(field, value, "Address must be 0x followed by 40 hex characters")
+	}
+	return nil
+}
+
+// upstreamError logs a failed JSON-RPC call and reports it without its cause, which can
+// name the node
+func upstreamError(ctx context.Context, err error) error {
+	logger.FromContext(ctx).WithError(err).Warn("EVM node call failed")
+	return app_errors.NewUpstreamError("Failed to query EVM node", err)
+}
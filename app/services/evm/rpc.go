@@ -0,0 +1,122 @@
+// Package services provides the EVM explorer services, talking JSON-RPC to EVM chain nodes.
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+
+	"cry-api/app/httpclient"
+)
+
+// errNullResult is returned by Call when the node answers with a null result, e.g. for an
+// unknown transaction
+var errNullResult = errors.New("null result")
+
+// RPCError is an error object returned by a JSON-RPC node
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("JSON-RPC error %d: %s", e.Code, e.Message)
+}
+
+// RPCClient calls the JSON-RPC 2.0 endpoint of a node
+type RPCClient struct {
+	URL    string
+	Client httpclient.Doer
+	nextID atomic.Int64
+}
+
+type rpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int64  `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+type rpcResponse struct {
+	ID     int64           `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+// NewRPCClient returns a client for the node at url
+func NewRPCClient(url string, client httpclient.Doer) *RPCClient {
+	return &RPCClient{URL: url, Client: client}
+}
+
+// Call invokes method with params and decodes its result into out. Only read-only methods
+// are called, so requests are marked idempotent and retried by the shared client.
+func (c *RPCClient) Call(ctx context.Context, method string, out any, params ...any) error {
+	if params == nil {
+		params = []any{}
+	}
+	id := c.nextID.Add(1)
+	payload, err := json.Marshal(rpcRequest{JSONRPC: "2.0", ID: id, Method: method, Params: params})
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", fmt.Sprintf("%s-%d", method, id))
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach node: %w", redactURL(err))
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("node returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var decoded rpcResponse
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return fmt.Errorf("failed to parse JSON-RPC response: %w", err)
+	}
+	if decoded.Error != nil {
+		return decoded.Error
+	}
+	if len(decoded.Result) == 0 || string(decoded.Result) == "null" {
+		return errNullResult
+	}
+	if err := json.Unmarshal(decoded.Result, out); err != nil {
+		return fmt.Errorf("failed to parse %s result: %w", method, err)
+	}
+	return nil
+}
+
+// redactURL strips the path and query of the node URL from a request error, as node URLs
+// often carry an API key
+func redactURL(err error) error {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return err
+	}
+	redacted := "node"
+	if parsed, parseErr := url.Parse(urlErr.URL); parseErr == nil && parsed.Host != "" {
+		redacted = parsed.Scheme + "://" + parsed.Host
+	}
+	return &url.Error{Op: urlErr.Op, URL: redacted, Err: urlErr.Err}
+}
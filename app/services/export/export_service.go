@@ -0,0 +1,337 @@
+// Package services provides streaming exports of wallet transaction history.
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"cry-api/app/bitcoin"
+	"cry-api/app/logger"
+	"cry-api/app/money"
+	CoinMarketCapService "cry-api/app/services/coin_market_cap"
+	FXService "cry-api/app/services/fx"
+	WalletExplorerService "cry-api/app/services/wallet_explorer"
+	WatchlistService "cry-api/app/services/watchlist"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+	app_errors "cry-api/app/types/errors"
+	ExportTypes "cry-api/app/types/export"
+	WalletExplorer "cry-api/app/types/wallet_explorer"
+)
+
+// DefaultCurrency is used when no fiat currency is requested
+const DefaultCurrency = money.DefaultCurrency
+
+// priceWindow is how many days of prices are fetched at once while streaming
+const priceWindow = 365
+
+// MaxBufferedRows caps the exports whose history is fetched as a whole before being written:
+// the upstream returns the history of an extended public key in a single response, and the
+// watchlist history is merged across wallets before it can be ordered
+const MaxBufferedRows = 10000
+
+// ExportService streams transaction histories as CSV, JSON Lines or OFX.
+type ExportService struct {
+	transactionService   WalletExplorerService.TransactionServiceInterface
+	watchlistService     WatchlistService.WatchlistServiceInterface
+	coinMarketCapService CoinMarketCapService.CoinMarketCapServiceInterface
+}
+
+// ExportServiceInterface defines the methods for the ExportService.
+type ExportServiceInterface interface {
+	ExportAddress(ctx context.Context, w io.Writer, address string, opts *ExportTypes.IExportOptions) error
+	ExportXPUB(ctx context.Context, w io.Writer, xpub string, opts *ExportTypes.IExportOptions) error
+	ExportWatchlist(ctx context.Context, w io.Writer, userID int, opts *ExportTypes.IExportOptions) error
+}
+
+// NewExportService initializes and returns an ExportService instance
+func NewExportService(
+	transactionService WalletExplorerService.TransactionServiceInterface,
+	watchlistService WatchlistService.WatchlistServiceInterface,
+	coinMarketCapService CoinMarketCapService.CoinMarketCapServiceInterface,
+) *ExportService {
+	return &ExportService{
+		transactionService:   transactionService,
+		watchlistService:     watchlistService,
+		coinMarketCapService: coinMarketCapService,
+	}
+}
+
+// ParseExportOptions validates the format, CSV column list and currency of an export request
+func ParseExportOptions(format, columns, currency string) (*ExportTypes.IExportOptions, error) {
+	opts := &ExportTypes.IExportOptions{
+		Format:  strings.ToLower(strings.TrimSpace(format)),
+		Columns: DefaultColumns,
+	}
+
+	if opts.Format == "" {
+		opts.Format = FormatCSV
+	}
+	if opts.Format != FormatCSV && opts.Format != FormatJSONL && opts.Format != FormatOFX {
+		return nil, app_errors.NewValidationError("format", format, "Format must be one of csv, jsonl or ofx")
+	}
+
+	if strings.TrimSpace(currency) == "" {
+		currency = DefaultCurrency
+	}
+	normalized, err := FXService.NormalizeCurrency("currency", currency)
+	if err != nil {
+		return nil, err
+	}
+	opts.Currency = normalized
+
+	if strings.TrimSpace(columns) != "" {
+		opts.Columns = nil
+		for _, col := range strings.Split(columns, ",") {
+			col = strings.ToLower(strings.TrimSpace(col))
+			if _, ok := csvColumns[col]; !ok {
+				return nil, app_errors.NewValidationError("columns", col, fmt.Sprintf("Unknown column %q", col))
+			}
+			opts.Columns = append(opts.Columns, col)
+		}
+	}
+
+	return opts, nil
+}
+
+// ExportAddress streams the full history of an address, newest first. Pages are fetched from
+// upstream as the export is written, so the history is never held in memory as a whole.
+func (s *ExportService) ExportAddress(ctx context.Context, w io.Writer, address string, opts *ExportTypes.IExportOptions) error {
+	if _, _, err := bitcoin.ScriptFromAddress(address); err != nil {
+		return app_errors.NewValidationError("address", address, "Invalid Bitcoin address")
+	}
+
+	prices, err := s.newPricer(ctx, opts.Currency)
+	if err != nil {
+		return err
+	}
+
+	out := newRowWriter(w, opts, address)
+	started := false
+	err = s.transactionService.StreamAddressTransactions(ctx, address, func(tx WalletExplorer.AddressTransaction) error {
+		if !started {
+			if err := out.begin(); err != nil {
+				return err
+			}
+			started = true
+		}
+
+		row, err := prices.row(tx.Time, tx.Hash, tx.BlockHeight, tx.Result, tx.Balance)
+		if err != nil {
+			return err
+		}
+		// The upstream fee is the fee of the whole transaction, only paid by the sender
+		if tx.Result < 0 {
+			fee := satsToBTC(tx.Fee)
+			row.Fee = &fee
+		}
+		return out.write(row)
+	})
+	if err != nil {
+		if !started {
+			logger.FromContext(ctx).WithError(err).Warn("Failed to fetch address history for export")
+			return app_errors.NewUpstreamError("Failed to fetch address history", err)
+		}
+		return err
+	}
+
+	if !started {
+		if err := out.begin(); err != nil {
+			return err
+		}
+	}
+	return out.end()
+}
+
+// ExportXPUB exports the history of an extended public key, newest first
+func (s *ExportService) ExportXPUB(ctx context.Context, w io.Writer, xpub string, opts *ExportTypes.IExportOptions) error {
+	if _, err := bitcoin.ParseExtendedKey(xpub); err != nil {
+		return app_errors.NewValidationError("xpub", xpub, "Invalid extended public key")
+	}
+
+	data, err := s.transactionService.GetTransactionByXPUB(ctx, xpub)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Warn("Failed to fetch xpub history for export")
+		return app_errors.NewUpstreamError("Failed to fetch xpub history", err)
+	}
+
+	if err := checkBufferedRows(len(data.Transactions)); err != nil {
+		return err
+	}
+
+	prices, err := s.newPricer(ctx, opts.Currency)
+	if err != nil {
+		return err
+	}
+
+	txs := data.Transactions
+	sort.SliceStable(txs, func(i, j int) bool { return txs[i].Time > txs[j].Time })
+
+	out := newRowWriter(w, opts, xpub)
+	if err := out.begin(); err != nil {
+		return err
+	}
+	for _, tx := range txs {
+		row, err := prices.row(tx.Time, tx.TxID, tx.BlockHeight, toSats(tx.BalanceDiff), toSats(tx.Balance))
+		if err != nil {
+			return err
+		}
+		if err := out.write(row); err != nil {
+			return err
+		}
+	}
+	return out.end()
+}
+
+// ExportWatchlist exports the merged history of the user's watchlist, newest first. Running
+// balances are computed across all watched wallets, walking back from the current total.
+func (s *ExportService) ExportWatchlist(ctx context.Context, w io.Writer, userID int, opts *ExportTypes.IExportOptions) error {
+	history, err := s.watchlistService.GetHistory(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := checkBufferedRows(len(history.Transactions)); err != nil {
+		return err
+	}
+
+	prices, err := s.newPricer(ctx, opts.Currency)
+	if err != nil {
+		return err
+	}
+
+	// Counted in satoshis so that walking back never accumulates float64 error
+	var balance int64
+	for _, tx := range history.Transactions {
+		balance += toSats(tx.BalanceDiff)
+	}
+
+	out := newRowWriter(w, opts, "watchlist")
+	if err := out.begin(); err != nil {
+		return err
+	}
+	for i := len(history.Transactions) - 1; i >= 0; i-- {
+		tx := history.Transactions[i]
+		diff := toSats(tx.BalanceDiff)
+		row, err := prices.row(tx.Time, tx.TxID, tx.BlockHeight, diff, balance)
+		if err != nil {
+			return err
+		}
+		row.Wallet = tx.Label
+		if err := out.write(row); err != nil {
+			return err
+		}
+		balance -= diff
+	}
+	return out.end()
+}
+
+// checkBufferedRows rejects the buffered exports larger than MaxBufferedRows
+func checkBufferedRows(n int) error {
+	if n <= MaxBufferedRows {
+		return nil
+	}
+	return app_errors.NewCodedError(http.StatusUnprocessableEntity, app_errors.CodeExportTooLarge,
+		fmt.Sprintf("History too large to export, at most %d transactions are supported", MaxBufferedRows),
+		fmt.Sprintf("%d transactions", n))
+}
+
+// pricer looks up daily prices, fetching older windows lazily as the export walks back in time
+type pricer struct {
+	ctx      context.Context
+	svc      CoinMarketCapService.CoinMarketCapServiceInterface
+	currency string
+	start    time.Time
+	points   []CoinMarketCap.IPricePoint
+	series   *CoinMarketCapService.PriceSeries
+}
+
+// newPricer prefetches the most recent window so upstream failures surface before anything is written
+func (s *ExportService) newPricer(ctx context.Context, currency string) (*pricer, error) {
+	p := &pricer{ctx: ctx, svc: s.coinMarketCapService, currency: currency}
+	today := truncateDay(time.Now().UTC())
+	if err := p.fetch(today.AddDate(0, 0, -(priceWindow-1)), today); err != nil {
+		logger.FromContext(ctx).WithError(err).WithField("currency", currency).Warn("Failed to fetch historical prices for export")
+		return nil, app_errors.NewUpstreamError("Failed to fetch historical prices", err)
+	}
+	return p, nil
+}
+
+func (p *pricer) fetch(from, to time.Time) error {
+	points, err := p.svc.GetHistoricalPrices(p.ctx, "BTC", p.currency, from, to)
+	if err != nil {
+		return err
+	}
+	p.points = append(points, p.points...)
+	p.series = CoinMarketCapService.NewPriceSeries(p.points)
+	p.start = from
+	return nil
+}
+
+func (p *pricer) priceOn(t time.Time) (*big.Rat, error) {
+	day := truncateDay(t)
+	if day.Before(p.start) {
+		from := p.start.AddDate(0, 0, -priceWindow)
+		if day.Before(from) {
+			from = day
+		}
+		if err := p.fetch(from, p.start.AddDate(0, 0, -1)); err != nil {
+			return nil, err
+		}
+	}
+	return p.series.On(day.Format("2006-01-02")), nil
+}
+
+// row builds an export row valued at the price of the transaction's day. The amount and
+// balance are in satoshis, and the fiat amounts are computed exactly before being rounded to
+// the minor units of the currency.
+func (p *pricer) row(ts int64, txid string, height int, amount, balance int64) (ExportTypes.IExportRow, error) {
+	t := time.Now().UTC()
+	if ts > 0 {
+		t = time.Unix(ts, 0).UTC()
+	}
+
+	price, err := p.priceOn(t)
+	if err != nil {
+		return ExportTypes.IExportRow{}, err
+	}
+
+	fiatPrice, err := money.FromRat(price, p.currency)
+	if err != nil {
+		return ExportTypes.IExportRow{}, err
+	}
+	value, err := money.FromRat(new(big.Rat).Mul(money.Sats(amount), price), p.currency)
+	if err != nil {
+		return ExportTypes.IExportRow{}, err
+	}
+
+	return ExportTypes.IExportRow{
+		Date:        t.Format(time.RFC3339),
+		Time:        t.Unix(),
+		TxID:        txid,
+		BlockHeight: height,
+		Amount:      satsToBTC(amount),
+		Balance:     satsToBTC(balance),
+		Price:       fiatPrice,
+		FiatValue:   value,
+		Currency:    p.currency,
+	}, nil
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func satsToBTC(sats int64) float64 {
+	return float64(sats) / money.SatoshisPerBitcoin
+}
+
+// toSats converts an amount of bitcoin read from upstream to satoshis
+func toSats(btc float64) int64 {
+	return int64(math.Round(btc * money.SatoshisPerBitcoin))
+}
@@ -0,0 +1,196 @@
+// Package services provides streaming exports of wallet transaction history.
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	ExportTypes "cry-api/app/types/export"
+)
+
+// Supported export formats
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+	FormatOFX   = "ofx"
+)
+
+// DefaultColumns are the CSV columns used when none are requested
+var DefaultColumns = []string{"date", "txid", "amount", "fee", "balance", "fiat_value"}
+
+// csvColumns maps every selectable CSV column to its value
+var csvColumns = map[string]func(ExportTypes.IExportRow) string{
+	"date":         func(r ExportTypes.IExportRow) string { return r.Date },
+	"time":         func(r ExportTypes.IExportRow) string { return strconv.FormatInt(r.Time, 10) },
+	"txid":         func(r ExportTypes.IExportRow) string { return r.TxID },
+	"block_height": func(r ExportTypes.IExportRow) string { return strconv.Itoa(r.BlockHeight) },
+	"amount":       func(r ExportTypes.IExportRow) string { return formatBTC(r.Amount) },
+	"fee": func(r ExportTypes.IExportRow) string {
+		if r.Fee == nil {
+			return ""
+		}
+		return formatBTC(*r.Fee)
+	},
+	"balance":    func(r ExportTypes.IExportRow) string { return formatBTC(r.Balance) },
+	"price":      func(r ExportTypes.IExportRow) string { return r.Price.Value },
+	"fiat_value": func(r ExportTypes.IExportRow) string { return r.FiatValue.Value },
+	"currency":   func(r ExportTypes.IExportRow) string { return r.Currency },
+	"wallet":     func(r ExportTypes.IExportRow) string { return r.Wallet },
+}
+
+// ContentType returns the MIME type of an export format
+func ContentType(format string) string {
+	switch format {
+	case FormatJSONL:
+		return "application/x-ndjson"
+	case FormatOFX:
+		return "application/x-ofx"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// rowWriter writes an export one row at a time
+type rowWriter interface {
+	begin() error
+	write(row ExportTypes.IExportRow) error
+	end() error
+}
+
+func newRowWriter(w io.Writer, opts *ExportTypes.IExportOptions, account string) rowWriter {
+	switch opts.Format {
+	case FormatJSONL:
+		return &jsonlWriter{enc: json.NewEncoder(w)}
+	case FormatOFX:
+		return &ofxWriter{w: w, account: account, now: time.Now().UTC()}
+	default:
+		return &csvWriter{w: csv.NewWriter(w), columns: opts.Columns}
+	}
+}
+
+// csvWriter writes the selected columns, flushing after every row
+type csvWriter struct {
+	w       *csv.Writer
+	columns []string
+}
+
+func (c *csvWriter) begin() error {
+	return c.flush(c.columns)
+}
+
+func (c *csvWriter) write(row ExportTypes.IExportRow) error {
+	record := make([]string, len(c.columns))
+	for i, col := range c.columns {
+		record[i] = csvColumns[col](row)
+	}
+	return c.flush(record)
+}
+
+func (c *csvWriter) end() error {
+	return nil
+}
+
+func (c *csvWriter) flush(record []string) error {
+	if err := c.w.Write(record); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonlWriter writes one JSON object per line
+type jsonlWriter struct {
+	enc *json.Encoder
+}
+
+func (j *jsonlWriter) begin() error { return nil }
+
+func (j *jsonlWriter) write(row ExportTypes.IExportRow) error {
+	return j.enc.Encode(row)
+}
+
+func (j *jsonlWriter) end() error { return nil }
+
+// ofxWriter writes an OFX 2.2 bank statement with amounts in BTC (currency code XBT).
+// The ledger balance is the running balance of the first row, since exports are newest first.
+type ofxWriter struct {
+	w       io.Writer
+	account string
+	now     time.Time
+	balance *float64
+}
+
+// ofxGenesis is the date of the Bitcoin genesis block, used as the statement start date
+// so the header can be written before the oldest transaction is known.
+const ofxGenesis = "20090103000000"
+
+func (o *ofxWriter) begin() error {
+	account := o.account
+	if len(account) > 22 {
+		account = account[:22]
+	}
+	now := ofxDate(o.now)
+	_, err := fmt.Fprintf(o.w, `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+<OFX>
+<SIGNONMSGSRSV1><SONRS><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS><DTSERVER>%s</DTSERVER><LANGUAGE>ENG</LANGUAGE></SONRS></SIGNONMSGSRSV1>
+<BANKMSGSRSV1><STMTTRNRS><TRNUID>1</TRNUID><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>
+<STMTRS><CURDEF>XBT</CURDEF>
+<BANKACCTFROM><BANKID>BITCOIN</BANKID><ACCTID>%s</ACCTID><ACCTTYPE>CHECKING</ACCTTYPE></BANKACCTFROM>
+<BANKTRANLIST><DTSTART>%s</DTSTART><DTEND>%s</DTEND>
+`, now, escapeXML(account), ofxGenesis, now)
+	return err
+}
+
+func (o *ofxWriter) write(row ExportTypes.IExportRow) error {
+	if o.balance == nil {
+		balance := row.Balance
+		o.balance = &balance
+	}
+
+	trnType := "CREDIT"
+	if row.Amount < 0 {
+		trnType = "DEBIT"
+	}
+	memo := fmt.Sprintf("%s %s @ %s %s", row.FiatValue.Value, row.Currency, row.Price.Value, row.Currency)
+	if row.Wallet != "" {
+		memo = row.Wallet + " - " + memo
+	}
+
+	_, err := fmt.Fprintf(o.w, "<STMTTRN><TRNTYPE>%s</TRNTYPE><DTPOSTED>%s</DTPOSTED><TRNAMT>%s</TRNAMT><FITID>%s</FITID><NAME>Bitcoin transaction</NAME><MEMO>%s</MEMO></STMTTRN>\n",
+		trnType, ofxDate(time.Unix(row.Time, 0)), formatBTC(row.Amount), escapeXML(row.TxID), escapeXML(memo))
+	return err
+}
+
+func (o *ofxWriter) end() error {
+	var balance float64
+	if o.balance != nil {
+		balance = *o.balance
+	}
+	_, err := fmt.Fprintf(o.w, `</BANKTRANLIST>
+<LEDGERBAL><BALAMT>%s</BALAMT><DTASOF>%s</DTASOF></LEDGERBAL>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`, formatBTC(balance), ofxDate(o.now))
+	return err
+}
+
+func ofxDate(t time.Time) string {
+	return t.UTC().Format("20060102150405")
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func formatBTC(v float64) string {
+	return strconv.FormatFloat(v, 'f', 8, 64)
+}
@@ -0,0 +1,156 @@
+// Package services provides fiat currency conversion from the exchange rates implied by the
+// market data provider's quotes.
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"cry-api/app/cache"
+	"cry-api/app/logger"
+	UserModel "cry-api/app/models"
+	"cry-api/app/money"
+	CoinMarketCapService "cry-api/app/services/coin_market_cap"
+	app_errors "cry-api/app/types/errors"
+)
+
+// ReferenceAsset is the asset whose quotes in two currencies give their exchange rate
+const ReferenceAsset = "BTC"
+
+// RatesPolicy caches the prices behind the exchange rates. They are display values, so a few
+// minutes of lag are fine, and a day old rate beats no conversion when upstream is down.
+var RatesPolicy = cache.Policy{TTL: 5 * time.Minute, StaleTTL: 24 * time.Hour}
+
+// FXService converts amounts between fiat currencies and values crypto assets in them. The
+// market data provider only quotes assets, so the rate between two fiat currencies is the
+// ratio of the BTC prices in both.
+type FXService struct {
+	market CoinMarketCapService.CoinMarketCapServiceInterface
+	cache  *cache.Cache
+}
+
+// FXServiceInterface defines the methods for the FXService.
+type FXServiceInterface interface {
+	Price(ctx context.Context, symbol, currency string) (*big.Rat, error)
+	Rate(ctx context.Context, from, to string) (*big.Rat, error)
+	Convert(ctx context.Context, value *big.Rat, from, to string) (*money.Amount, error)
+	Value(ctx context.Context, symbol string, quantity *big.Rat, currency string) (*money.Amount, error)
+}
+
+// NewFXService initializes and returns an FXService instance. A nil cache disables caching.
+func NewFXService(market CoinMarketCapService.CoinMarketCapServiceInterface, c *cache.Cache) *FXService {
+	return &FXService{market: market, cache: c}
+}
+
+// NormalizeCurrency upper-cases a currency code and checks it is supported. field names the
+// request field in the validation error.
+func NormalizeCurrency(field, currency string) (string, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if !money.IsSupported(currency) {
+		return "", app_errors.NewValidationError(field, currency,
+			fmt.Sprintf("Currency must be one of the supported ISO-4217 codes: %s", strings.Join(money.Currencies(), ", ")))
+	}
+	return currency, nil
+}
+
+// Price returns the latest price of one unit of an asset in a fiat currency
+func (s *FXService) Price(ctx context.Context, symbol, currency string) (*big.Rat, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	currency, err := NormalizeCurrency("currency", currency)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("fx:price:%s:%s", symbol, currency)
+	price, err := cache.Fetch(ctx, s.cache, key, cache.Fixed[string](RatesPolicy), func(ctx context.Context) (string, error) {
+		quotes, err := s.market.GetLatestQuotes(ctx, []string{symbol}, nil, currency)
+		if err != nil {
+			return "", err
+		}
+		for _, quote := range quotes {
+			if quote.Symbol != symbol {
+				continue
+			}
+			price, err := money.ParseDecimal(quote.Price)
+			if err == nil && price.Sign() > 0 {
+				return price.RatString(), nil
+			}
+		}
+		return "", fmt.Errorf("no %s price in %s", symbol, currency)
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).WithField("symbol", symbol).WithField("currency", currency).Warn("Failed to fetch exchange rates")
+		return nil, app_errors.NewUpstreamError("Failed to fetch exchange rates", err)
+	}
+
+	rat, ok := new(big.Rat).SetString(price)
+	if !ok {
+		logger.FromContext(ctx).WithField("price", price).Warn("Cached exchange rate is unreadable")
+		return nil, app_errors.NewUpstreamError("Failed to fetch exchange rates", nil)
+	}
+	return rat, nil
+}
+
+// Rate returns the amount of the to currency one unit of the from currency is worth
+func (s *FXService) Rate(ctx context.Context, from, to string) (*big.Rat, error) {
+	from, err := NormalizeCurrency("currency", from)
+	if err != nil {
+		return nil, err
+	}
+	to, err = NormalizeCurrency("currency", to)
+	if err != nil {
+		return nil, err
+	}
+	if from == to {
+		return big.NewRat(1, 1), nil
+	}
+
+	fromPrice, err := s.Price(ctx, ReferenceAsset, from)
+	if err != nil {
+		return nil, err
+	}
+	toPrice, err := s.Price(ctx, ReferenceAsset, to)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Rat).Quo(toPrice, fromPrice), nil
+}
+
+// Convert converts a value of the from currency to the to currency, rounded to its minor units
+func (s *FXService) Convert(ctx context.Context, value *big.Rat, from, to string) (*money.Amount, error) {
+	rate, err := s.Rate(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return round(new(big.Rat).Mul(value, rate), strings.ToUpper(strings.TrimSpace(to)))
+}
+
+// Value returns the value of a quantity of an asset in a fiat currency, rounded to its minor
+// units
+func (s *FXService) Value(ctx context.Context, symbol string, quantity *big.Rat, currency string) (*money.Amount, error) {
+	price, err := s.Price(ctx, symbol, currency)
+	if err != nil {
+		return nil, err
+	}
+	return round(new(big.Rat).Mul(quantity, price), strings.ToUpper(strings.TrimSpace(currency)))
+}
+
+func round(value *big.Rat, currency string) (*money.Amount, error) {
+	amount, err := money.FromRat(value, currency)
+	if err != nil {
+		return nil, app_errors.NewValidationError("currency", currency, err.Error())
+	}
+	return &amount, nil
+}
+
+// PreferredCurrency returns the fiat currency the user converts money values to, USD when
+// they have not chosen one
+func PreferredCurrency(user *UserModel.User) string {
+	if user == nil || user.FiatCurrency == "" {
+		return money.DefaultCurrency
+	}
+	return user.FiatCurrency
+}
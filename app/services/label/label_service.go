@@ -0,0 +1,380 @@
+// Package services provides BIP-329 labels on transactions, addresses, outputs and keys.
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"cry-api/app/bitcoin"
+	UserModel "cry-api/app/models"
+	LabelRepository "cry-api/app/repositories"
+	app_errors "cry-api/app/types/errors"
+	LabelTypes "cry-api/app/types/label"
+	WalletExplorer "cry-api/app/types/wallet_explorer"
+)
+
+const (
+	// MaxLabelLength is the longest label stored, as recommended by BIP-329
+	MaxLabelLength = 255
+	// MaxImportBytes caps the size of an import upload
+	MaxImportBytes = 10 << 20
+	// MaxImportLines caps the number of records accepted in one import
+	MaxImportLines = 10000
+	// maxLineBytes caps the size of a single import line
+	maxLineBytes = 64 * 1024
+)
+
+var (
+	txidPattern   = regexp.MustCompile(`^[0-9a-f]{64}$`)
+	pubkeyPattern = regexp.MustCompile(`^(0[23][0-9a-f]{64}|04[0-9a-f]{128})$`)
+)
+
+// labelTypes lists the BIP-329 types stored by the API
+var labelTypes = map[string]bool{
+	UserModel.LabelTypeTx:     true,
+	UserModel.LabelTypeAddr:   true,
+	UserModel.LabelTypePubkey: true,
+	UserModel.LabelTypeInput:  true,
+	UserModel.LabelTypeOutput: true,
+	UserModel.LabelTypeXPUB:   true,
+}
+
+// LabelService manages user labels and attaches them to explorer responses.
+type LabelService struct {
+	repo LabelRepository.LabelRepository
+}
+
+// LabelServiceInterface defines the methods for the LabelService.
+type LabelServiceInterface interface {
+	ListLabels(userID int, labelType string) ([]UserModel.Label, error)
+	SetLabel(userID int, req LabelTypes.ILabelRecord) (*UserModel.Label, error)
+	DeleteLabel(userID, id int) error
+	ImportLabels(userID int, r io.Reader) (*LabelTypes.IImportResult, error)
+	ExportLabels(userID int, w io.Writer) error
+	AnnotateTransaction(userID int, tx *WalletExplorer.ITransactionData) error
+	AnnotateXPUB(userID int, xpub string, data *WalletExplorer.ITransactionXPUB) error
+}
+
+// NewLabelService initializes and returns a LabelService instance
+func NewLabelService(repo LabelRepository.LabelRepository) *LabelService {
+	return &LabelService{repo: repo}
+}
+
+// ListLabels returns the user's labels, optionally restricted to one type
+func (s *LabelService) ListLabels(userID int, labelType string) ([]UserModel.Label, error) {
+	labelType = strings.ToLower(strings.TrimSpace(labelType))
+	if labelType != "" && !labelTypes[labelType] {
+		return nil, app_errors.NewValidationError("type", labelType, "Type must be one of tx, addr, pubkey, input, output or xpub")
+	}
+
+	labels, err := s.repo.FindByUserID(userID, labelType)
+	if err != nil {
+		return nil, app_errors.ErrDatabaseError
+	}
+	if labels == nil {
+		labels = []UserModel.Label{}
+	}
+	return labels, nil
+}
+
+// SetLabel creates the label of a reference, or replaces it when one already exists
+func (s *LabelService) SetLabel(userID int, req LabelTypes.ILabelRecord) (*UserModel.Label, error) {
+	record, err := normalizeRecord(req)
+	if err != nil {
+		return nil, err
+	}
+
+	label, err := s.repo.FindByRef(userID, record.Type, record.Ref)
+	if err != nil {
+		return nil, app_errors.ErrDatabaseError
+	}
+	if label == nil {
+		label = &UserModel.Label{UserID: userID, Type: record.Type, Ref: record.Ref, CreatedAt: time.Now()}
+	}
+	applyRecord(label, record)
+
+	if err := s.repo.Save(label); err != nil {
+		return nil, app_errors.ErrDatabaseError
+	}
+	return label, nil
+}
+
+// DeleteLabel removes a label owned by the user
+func (s *LabelService) DeleteLabel(userID, id int) error {
+	deleted, err := s.repo.Delete(id, userID)
+	if err != nil {
+		return app_errors.ErrDatabaseError
+	}
+	if !deleted {
+		return app_errors.NewNotFoundError("label", "Label not found")
+	}
+	return nil
+}
+
+// ImportLabels reads BIP-329 JSON Lines and upserts every valid record. Invalid lines are
+// reported and skipped; records of unknown types are ignored as the BIP requires.
+func (s *LabelService) ImportLabels(userID int, r io.Reader) (*LabelTypes.IImportResult, error) {
+	existing, err := s.repo.FindByUserID(userID, "")
+	if err != nil {
+		return nil, app_errors.ErrDatabaseError
+	}
+	byRef := make(map[string]*UserModel.Label, len(existing))
+	for i := range existing {
+		byRef[existing[i].Type+"|"+existing[i].Ref] = &existing[i]
+	}
+
+	result := &LabelTypes.IImportResult{Errors: []LabelTypes.IImportError{}}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxLineBytes)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		if line > MaxImportLines {
+			return nil, app_errors.NewValidationError("file", strconv.Itoa(line), fmt.Sprintf("Imports are limited to %d lines", MaxImportLines))
+		}
+
+		var req LabelTypes.ILabelRecord
+		if err := json.Unmarshal([]byte(text), &req); err != nil {
+			result.Errors = append(result.Errors, LabelTypes.IImportError{Line: line, Error: "Invalid JSON"})
+			continue
+		}
+		if !labelTypes[strings.ToLower(strings.TrimSpace(req.Type))] {
+			result.Skipped++
+			continue
+		}
+
+		// Other wallets may export longer labels; BIP-329 allows importers to truncate them
+		if len(req.Label) > MaxLabelLength {
+			req.Label = truncate(req.Label, MaxLabelLength)
+		}
+		record, err := normalizeRecord(req)
+		if err != nil {
+			result.Errors = append(result.Errors, LabelTypes.IImportError{Line: line, Error: err.Error()})
+			continue
+		}
+
+		label, found := byRef[record.Type+"|"+record.Ref]
+		if !found {
+			label = &UserModel.Label{UserID: userID, Type: record.Type, Ref: record.Ref, CreatedAt: time.Now()}
+		}
+		applyRecord(label, record)
+		if err := s.repo.Save(label); err != nil {
+			return nil, app_errors.ErrDatabaseError
+		}
+
+		if found {
+			result.Updated++
+		} else {
+			byRef[record.Type+"|"+record.Ref] = label
+			result.Imported++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, app_errors.NewValidationError("file", strconv.Itoa(line+1), "Import could not be read: "+err.Error())
+	}
+
+	return result, nil
+}
+
+// ExportLabels writes every label of the user as BIP-329 JSON Lines
+func (s *LabelService) ExportLabels(userID int, w io.Writer) error {
+	labels, err := s.repo.FindByUserID(userID, "")
+	if err != nil {
+		return app_errors.ErrDatabaseError
+	}
+
+	enc := json.NewEncoder(w)
+	for _, label := range labels {
+		record := LabelTypes.ILabelRecord{
+			Type:      label.Type,
+			Ref:       label.Ref,
+			Label:     label.Label,
+			Origin:    label.Origin,
+			Spendable: label.Spendable,
+		}
+		if err := enc.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AnnotateTransaction fills in the user's labels for a transaction, its outputs and the
+// addresses it spends from and pays to
+func (s *LabelService) AnnotateTransaction(userID int, tx *WalletExplorer.ITransactionData) error {
+	refs := []string{tx.Hash}
+	for _, in := range tx.Inputs {
+		if in.PrevOut != nil && in.PrevOut.Addr != nil {
+			refs = append(refs, *in.PrevOut.Addr)
+		}
+	}
+	for _, out := range tx.Out {
+		if out.N != nil {
+			refs = append(refs, outpoint(tx.Hash, *out.N))
+		}
+		if out.Addr != nil {
+			refs = append(refs, *out.Addr)
+		}
+	}
+
+	labels, err := s.lookup(userID, refs)
+	if err != nil {
+		return err
+	}
+
+	tx.Label = labels[UserModel.LabelTypeTx+"|"+tx.Hash]
+	for i := range tx.Inputs {
+		if prev := tx.Inputs[i].PrevOut; prev != nil && prev.Addr != nil {
+			tx.Inputs[i].AddressLabel = labels[UserModel.LabelTypeAddr+"|"+*prev.Addr]
+		}
+	}
+	for i := range tx.Out {
+		out := &tx.Out[i]
+		if out.N != nil {
+			out.Label = labels[UserModel.LabelTypeOutput+"|"+outpoint(tx.Hash, *out.N)]
+		}
+		if out.Addr != nil {
+			out.AddressLabel = labels[UserModel.LabelTypeAddr+"|"+*out.Addr]
+		}
+	}
+	return nil
+}
+
+// AnnotateXPUB fills in the user's labels for an extended public key and its transactions
+func (s *LabelService) AnnotateXPUB(userID int, xpub string, data *WalletExplorer.ITransactionXPUB) error {
+	refs := []string{xpub}
+	for _, tx := range data.Transactions {
+		refs = append(refs, tx.TxID)
+	}
+
+	labels, err := s.lookup(userID, refs)
+	if err != nil {
+		return err
+	}
+
+	data.Label = labels[UserModel.LabelTypeXPUB+"|"+xpub]
+	for i := range data.Transactions {
+		data.Transactions[i].Label = labels[UserModel.LabelTypeTx+"|"+data.Transactions[i].TxID]
+	}
+	return nil
+}
+
+// lookup returns the labels attached to refs, keyed by "type|ref"
+func (s *LabelService) lookup(userID int, refs []string) (map[string]string, error) {
+	labels, err := s.repo.FindByRefs(userID, refs)
+	if err != nil {
+		return nil, app_errors.ErrDatabaseError
+	}
+
+	byRef := make(map[string]string, len(labels))
+	for _, label := range labels {
+		byRef[label.Type+"|"+label.Ref] = label.Label
+	}
+	return byRef, nil
+}
+
+// normalizeRecord validates a record and returns it with its type and reference in
+// canonical form
+func normalizeRecord(req LabelTypes.ILabelRecord) (LabelTypes.ILabelRecord, error) {
+	record := LabelTypes.ILabelRecord{
+		Type:   strings.ToLower(strings.TrimSpace(req.Type)),
+		Ref:    strings.TrimSpace(req.Ref),
+		Label:  strings.TrimSpace(req.Label),
+		Origin: strings.TrimSpace(req.Origin),
+	}
+
+	if !labelTypes[record.Type] {
+		return record, app_errors.NewValidationError("type", req.Type, "Type must be one of tx, addr, pubkey, input, output or xpub")
+	}
+	if len(record.Label) > MaxLabelLength {
+		return record, app_errors.NewValidationError("label", record.Label, fmt.Sprintf("Label must be at most %d characters", MaxLabelLength))
+	}
+	if len(record.Origin) > 255 {
+		return record, app_errors.NewValidationError("origin", record.Origin, "Origin must be at most 255 characters")
+	}
+
+	switch record.Type {
+	case UserModel.LabelTypeTx:
+		record.Ref = strings.ToLower(record.Ref)
+		if !txidPattern.MatchString(record.Ref) {
+			return record, app_errors.NewValidationError("ref", req.Ref, "Transaction id must be 64 hex characters")
+		}
+	case UserModel.LabelTypeInput, UserModel.LabelTypeOutput:
+		ref, ok := normalizeOutpoint(record.Ref)
+		if !ok {
+			return record, app_errors.NewValidationError("ref", req.Ref, "Reference must be an outpoint like <txid>:<index>")
+		}
+		record.Ref = ref
+	case UserModel.LabelTypeAddr:
+		if _, _, err := bitcoin.ScriptFromAddress(record.Ref); err != nil {
+			return record, app_errors.NewValidationError("ref", req.Ref, "Invalid Bitcoin address")
+		}
+	case UserModel.LabelTypePubkey:
+		record.Ref = strings.ToLower(record.Ref)
+		if !pubkeyPattern.MatchString(record.Ref) {
+			return record, app_errors.NewValidationError("ref", req.Ref, "Public key must be a hex encoded compressed or uncompressed key")
+		}
+	case UserModel.LabelTypeXPUB:
+		if _, err := bitcoin.ParseExtendedKey(record.Ref); err != nil {
+			return record, app_errors.NewValidationError("ref", req.Ref, "Invalid extended public key")
+		}
+	}
+
+	// BIP-329 only defines spendable for outputs
+	if record.Type == UserModel.LabelTypeOutput {
+		record.Spendable = req.Spendable
+	}
+	return record, nil
+}
+
+// applyRecord copies the editable fields of a normalized record onto a label
+func applyRecord(label *UserModel.Label, record LabelTypes.ILabelRecord) {
+	label.Label = record.Label
+	label.Origin = record.Origin
+	label.Spendable = record.Spendable
+	label.UpdatedAt = time.Now()
+}
+
+// normalizeOutpoint parses "<txid>:<index>" and returns it with a lowercase txid
+func normalizeOutpoint(ref string) (string, bool) {
+	txid, index, found := strings.Cut(ref, ":")
+	txid = strings.ToLower(txid)
+	if !found || !txidPattern.MatchString(txid) {
+		return "", false
+	}
+	n, err := strconv.ParseUint(index, 10, 32)
+	if err != nil {
+		return "", false
+	}
+	return outpoint(txid, int(n)), true
+}
+
+func outpoint(txid string, index int) string {
+	return fmt.Sprintf("%s:%d", txid, index)
+}
+
+// truncate shortens s to at most max bytes without splitting a UTF-8 character
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	cut := 0
+	for i := range s {
+		if i > max {
+			break
+		}
+		cut = i
+	}
+	return s[:cut]
+}
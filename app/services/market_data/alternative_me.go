@@ -0,0 +1,111 @@
+// Package services provides the market data service, which serves prices and the fear and
+// greed index from the configured providers, falling back from one to the next.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"cry-api/app/httpclient"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+	MarketData "cry-api/app/types/market_data"
+)
+
+// AlternativeMeName is the name of alternative.me in MARKET_DATA_PROVIDERS and in the source of responses
+const AlternativeMeName = "alternative"
+
+// AlternativeMeProvider serves the free alternative.me fear and greed index. It has no price data.
+//
+// The index is alternative.me's own, computed from other inputs than CoinMarketCap's, so
+// the values of a day may differ between the two sources.
+type AlternativeMeProvider struct {
+	unsupported
+	API    string
+	Client httpclient.Doer
+}
+
+// NewAlternativeMeProvider initializes and returns an AlternativeMeProvider instance
+func NewAlternativeMeProvider(api string, client httpclient.Doer) *AlternativeMeProvider {
+	return &AlternativeMeProvider{API: api, Client: client}
+}
+
+// Name returns the market data provider name
+func (p *AlternativeMeProvider) Name() string {
+	return AlternativeMeName
+}
+
+// GetFearAndGreedLastest fetches the index of today
+func (p *AlternativeMeProvider) GetFearAndGreedLastest(ctx context.Context) (*CoinMarketCap.FearGreedData, error) {
+	points, err := p.fetch(ctx, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(points) == 0 {
+		return nil, errors.New("alternative.me returned no fear and greed value")
+	}
+
+	return &CoinMarketCap.FearGreedData{
+		Data: CoinMarketCap.FearGreedEntry{
+			Value:               points[0].Value,
+			ValueClassification: points[0].ValueClassification,
+			UpdateTime:          unixTime(points[0].Timestamp),
+		},
+		Source: AlternativeMeName,
+	}, nil
+}
+
+// GetFearAndGreedHistorical fetches a page of the history, newest first. alternative.me has
+// no offset, so the days before start are fetched too and dropped.
+func (p *AlternativeMeProvider) GetFearAndGreedHistorical(ctx context.Context, start, limit int) (*CoinMarketCap.FearGreedHistorical, error) {
+	limit = min(max(limit, 1), 500)
+	start = max(start, 1)
+
+	points, err := p.fetch(ctx, start+limit-1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &CoinMarketCap.FearGreedHistorical{Data: []CoinMarketCap.FearGreedDataPoint{}, Source: AlternativeMeName}
+	if start-1 < len(points) {
+		page.Data = points[start-1:]
+	}
+	return page, nil
+}
+
+// fetch returns the limit latest days of the index, newest first
+func (p *AlternativeMeProvider) fetch(ctx context.Context, limit int) ([]CoinMarketCap.FearGreedDataPoint, error) {
+	var data MarketData.AlternativeMeFearGreed
+	endpoint := fmt.Sprintf("%s/fng/?limit=%d", p.API, limit)
+	if err := getJSON(ctx, p.Client, endpoint, nil, &data); err != nil {
+		return nil, err
+	}
+	if data.Metadata.Error != nil && *data.Metadata.Error != "" {
+		return nil, fmt.Errorf("alternative.me error: %s", *data.Metadata.Error)
+	}
+
+	points := make([]CoinMarketCap.FearGreedDataPoint, 0, len(data.Data))
+	for _, point := range data.Data {
+		value, err := strconv.Atoi(point.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fear and greed value %q", point.Value)
+		}
+		points = append(points, CoinMarketCap.FearGreedDataPoint{
+			Timestamp:           point.Timestamp,
+			Value:               value,
+			ValueClassification: point.ValueClassification,
+		})
+	}
+	return points, nil
+}
+
+// unixTime reads a UNIX seconds string, the zero time when it is not one
+func unixTime(raw string) time.Time {
+	seconds, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0).UTC()
+}
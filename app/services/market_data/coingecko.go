@@ -0,0 +1,343 @@
+// Package services provides the market data service, which serves prices and the fear and
+// greed index from the configured providers, falling back from one to the next.
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"cry-api/app/httpclient"
+	"cry-api/app/money"
+	CoinMarketCapService "cry-api/app/services/coin_market_cap"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+	app_errors "cry-api/app/types/errors"
+	MarketData "cry-api/app/types/market_data"
+)
+
+// CoinGeckoName is the name of CoinGecko in MARKET_DATA_PROVIDERS and in the source of responses
+const CoinGeckoName = "coingecko"
+
+// CoinGeckoProvider serves prices from a CoinGecko compatible API. It has no fear and greed
+// index, and cannot look assets up by CoinMarketCap id.
+//
+// CoinGecko identifies assets by its own ids, resolved from symbols (the best ranked asset
+// wins) and remembered for the life of the process. Candles are built from the price samples
+// of the market chart, hourly for ranges up to 90 days and daily beyond, and carry no volume.
+type CoinGeckoProvider struct {
+	unsupported
+	API    string
+	APIKey string
+	Client httpclient.Doer
+
+	ids sync.Map // symbol to CoinGecko id
+}
+
+// NewCoinGeckoProvider initializes and returns a CoinGeckoProvider instance. apiKey is an
+// optional demo API key.
+func NewCoinGeckoProvider(api, apiKey string, client httpclient.Doer) *CoinGeckoProvider {
+	return &CoinGeckoProvider{API: api, APIKey: apiKey, Client: client}
+}
+
+// Name returns the market data provider name
+func (p *CoinGeckoProvider) Name() string {
+	return CoinGeckoName
+}
+
+// GetLatestQuotes fetches the latest market data of assets listed by symbol
+func (p *CoinGeckoProvider) GetLatestQuotes(ctx context.Context, symbols []string, ids []int, convert string) ([]CoinMarketCap.IQuote, error) {
+	symbols, ids, convert, err := CoinMarketCapService.NormalizeQuotesQuery(symbols, ids, convert)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) > 0 {
+		return nil, ErrUnsupported
+	}
+
+	markets, err := p.markets(ctx, symbols, convert)
+	if err != nil {
+		return nil, upstreamError(ctx, "Failed to fetch quotes", err)
+	}
+
+	quotes := []CoinMarketCap.IQuote{}
+	for _, symbol := range symbols {
+		market, ok := markets[symbol]
+		if !ok {
+			continue
+		}
+		values, err := money.Decimals(market.CurrentPrice, market.TotalVolume, market.MarketCap,
+			market.PriceChangePercentage1hInCurrency, market.PriceChangePercentage24hInCurrency, market.PriceChangePercentage7dInCurrency)
+		if err != nil {
+			return nil, upstreamError(ctx, "Failed to fetch quotes", err)
+		}
+		quotes = append(quotes, CoinMarketCap.IQuote{
+			Symbol:           symbol,
+			Name:             market.Name,
+			Slug:             market.ID,
+			Rank:             market.MarketCapRank,
+			Currency:         convert,
+			Price:            values[0],
+			Volume24h:        values[1],
+			MarketCap:        values[2],
+			PercentChange1h:  values[3],
+			PercentChange24h: values[4],
+			PercentChange7d:  values[5],
+			LastUpdated:      market.LastUpdated,
+			Source:           CoinGeckoName,
+		})
+	}
+	return quotes, nil
+}
+
+// GetHistoricalPrices fetches daily prices of an asset between two dates (inclusive), oldest
+// first, keeping the last sample of each day
+func (p *CoinGeckoProvider) GetHistoricalPrices(ctx context.Context, symbol, convert string, from, to time.Time) ([]CoinMarketCap.IPricePoint, error) {
+	symbol = strings.ToUpper(symbol)
+	convert = strings.ToUpper(convert)
+
+	from = from.UTC().Truncate(24 * time.Hour)
+	to = to.UTC().Truncate(24 * time.Hour).Add(24*time.Hour - time.Second)
+	chart, err := p.marketChart(ctx, symbol, convert, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	byDay := make(map[string]*big.Rat)
+	for _, sample := range chart.Prices {
+		at, err := sampleTime(sample)
+		if err != nil {
+			return nil, upstreamError(ctx, "Failed to fetch market chart", err)
+		}
+		price, err := money.FromNumber(sample[1])
+		if err != nil {
+			return nil, upstreamError(ctx, "Failed to fetch market chart", err)
+		}
+		byDay[at.Format(time.DateOnly)] = price
+	}
+
+	points := make([]CoinMarketCap.IPricePoint, 0, len(byDay))
+	for day, price := range byDay {
+		points = append(points, CoinMarketCap.IPricePoint{Date: day, Price: price})
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Date < points[j].Date })
+	return points, nil
+}
+
+// GetOHLCV builds the candles of an asset from the price samples of its market chart
+func (p *CoinGeckoProvider) GetOHLCV(ctx context.Context, symbol, convert, interval string, from, to time.Time) (*CoinMarketCap.IOHLCV, error) {
+	q, err := CoinMarketCapService.NormalizeOHLCVQuery(symbol, convert, interval, from, to, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// The candle opening at to is the last one, as with CoinMarketCap
+	_, end := period(q.To, q.Interval)
+	chart, err := p.marketChart(ctx, q.Symbol, q.Convert, q.From, end.Add(-time.Second))
+	if err != nil {
+		return nil, err
+	}
+
+	caps := make(map[int64]string, len(chart.MarketCaps))
+	for _, sample := range chart.MarketCaps {
+		at, err := sampleTime(sample)
+		if err != nil {
+			return nil, upstreamError(ctx, "Failed to fetch market chart", err)
+		}
+		if caps[at.UnixMilli()], err = money.Decimal(sample[1]); err != nil {
+			return nil, upstreamError(ctx, "Failed to fetch market chart", err)
+		}
+	}
+
+	result := &CoinMarketCap.IOHLCV{
+		Symbol:   q.Symbol,
+		Currency: q.Convert,
+		Interval: q.Interval,
+		From:     q.From,
+		To:       q.To,
+		Candles:  []CoinMarketCap.ICandle{},
+		Source:   CoinGeckoName,
+	}
+	var candle *CoinMarketCap.ICandle
+	var high, low *big.Rat
+	for _, sample := range chart.Prices {
+		at, err := sampleTime(sample)
+		if err != nil {
+			return nil, upstreamError(ctx, "Failed to fetch market chart", err)
+		}
+		if at.Before(q.From) || !at.Before(end) {
+			continue
+		}
+		price, err := money.FromNumber(sample[1])
+		if err != nil {
+			return nil, upstreamError(ctx, "Failed to fetch market chart", err)
+		}
+
+		open, end := period(at, q.Interval)
+		if candle == nil || !candle.TimeOpen.Equal(open) {
+			result.Candles = append(result.Candles, CoinMarketCap.ICandle{
+				TimeOpen:  open,
+				TimeClose: end.Add(-time.Millisecond),
+				Open:      money.FormatDecimal(price),
+				Volume:    "0",
+				MarketCap: "0",
+			})
+			candle = &result.Candles[len(result.Candles)-1]
+			high, low = price, price
+		}
+		if price.Cmp(high) > 0 {
+			high = price
+		}
+		if price.Cmp(low) < 0 {
+			low = price
+		}
+		candle.High = money.FormatDecimal(high)
+		candle.Low = money.FormatDecimal(low)
+		candle.Close = money.FormatDecimal(price)
+		if marketCap, ok := caps[at.UnixMilli()]; ok {
+			candle.MarketCap = marketCap
+		}
+	}
+	return result, nil
+}
+
+// ConvertPrice values an amount of an asset at its latest price
+func (p *CoinGeckoProvider) ConvertPrice(ctx context.Context, amount *big.Rat, symbol, convert string) (*CoinMarketCap.IConversion, error) {
+	symbol, convert, err := CoinMarketCapService.NormalizeConversion(amount, symbol, convert)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := p.resolve(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("ids", id)
+	query.Set("vs_currencies", strings.ToLower(convert))
+	query.Set("include_last_updated_at", "true")
+
+	var prices map[string]map[string]json.Number
+	if err := getJSON(ctx, p.Client, p.API+"/simple/price?"+query.Encode(), p.header(), &prices); err != nil {
+		return nil, upstreamError(ctx, "Failed to convert price", err)
+	}
+	price, ok := prices[id][strings.ToLower(convert)]
+	if !ok {
+		return nil, app_errors.NewNotFoundError("asset", fmt.Sprintf("No %s price for %s", convert, symbol))
+	}
+	rate, err := money.FromNumber(price)
+	if err != nil {
+		return nil, upstreamError(ctx, "Failed to convert price", err)
+	}
+
+	updatedAt, _ := prices[id]["last_updated_at"].Int64()
+	return CoinMarketCapService.NewConversion(symbol, convert, amount, rate, time.Unix(updatedAt, 0).UTC(), CoinGeckoName)
+}
+
+// markets fetches the market data of the best ranked asset of each symbol, keyed by
+// upper-case symbol, and remembers their ids
+func (p *CoinGeckoProvider) markets(ctx context.Context, symbols []string, convert string) (map[string]MarketData.CoinGeckoMarket, error) {
+	query := url.Values{}
+	query.Set("vs_currency", strings.ToLower(convert))
+	query.Set("symbols", strings.ToLower(strings.Join(symbols, ",")))
+	query.Set("price_change_percentage", "1h,24h,7d")
+	query.Set("per_page", "250")
+
+	var markets []MarketData.CoinGeckoMarket
+	if err := getJSON(ctx, p.Client, p.API+"/coins/markets?"+query.Encode(), p.header(), &markets); err != nil {
+		return nil, err
+	}
+
+	best := make(map[string]MarketData.CoinGeckoMarket)
+	for _, market := range markets {
+		symbol := strings.ToUpper(market.Symbol)
+		current, ok := best[symbol]
+		if !ok || (market.MarketCapRank > 0 && (current.MarketCapRank == 0 || market.MarketCapRank < current.MarketCapRank)) {
+			best[symbol] = market
+		}
+	}
+	for symbol, market := range best {
+		p.ids.Store(symbol, market.ID)
+	}
+	return best, nil
+}
+
+// resolve returns the CoinGecko id of a symbol
+func (p *CoinGeckoProvider) resolve(ctx context.Context, symbol string) (string, error) {
+	if id, ok := p.ids.Load(symbol); ok {
+		return id.(string), nil
+	}
+
+	markets, err := p.markets(ctx, []string{symbol}, CoinMarketCapService.DefaultConvert)
+	if err != nil {
+		return "", upstreamError(ctx, "Failed to look up asset", err)
+	}
+	market, ok := markets[symbol]
+	if !ok {
+		return "", app_errors.NewNotFoundError("asset", fmt.Sprintf("No market data for %s", symbol))
+	}
+	return market.ID, nil
+}
+
+// marketChart fetches the price and market cap samples of an asset between two times
+func (p *CoinGeckoProvider) marketChart(ctx context.Context, symbol, convert string, from, to time.Time) (*MarketData.CoinGeckoMarketChart, error) {
+	id, err := p.resolve(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("vs_currency", strings.ToLower(convert))
+	query.Set("from", fmt.Sprint(from.Unix()))
+	query.Set("to", fmt.Sprint(to.Unix()))
+
+	var chart MarketData.CoinGeckoMarketChart
+	endpoint := fmt.Sprintf("%s/coins/%s/market_chart/range?%s", p.API, url.PathEscape(id), query.Encode())
+	if err := getJSON(ctx, p.Client, endpoint, p.header(), &chart); err != nil {
+		return nil, upstreamError(ctx, "Failed to fetch market chart", err)
+	}
+	return &chart, nil
+}
+
+func (p *CoinGeckoProvider) header() http.Header {
+	header := http.Header{}
+	if p.APIKey != "" {
+		header.Set("x-cg-demo-api-key", p.APIKey)
+	}
+	return header
+}
+
+// sampleTime reads the UNIX milliseconds of a market chart sample
+func sampleTime(sample [2]json.Number) (time.Time, error) {
+	ms, err := sample[0].Float64()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid sample time %q", sample[0])
+	}
+	return time.UnixMilli(int64(ms)).UTC(), nil
+}
+
+// period returns the start of the candle holding t and the start of the next one
+func period(t time.Time, interval string) (time.Time, time.Time) {
+	switch interval {
+	case CoinMarketCap.CandleHourly:
+		start := t.Truncate(time.Hour)
+		return start, start.Add(time.Hour)
+	case CoinMarketCap.CandleWeekly:
+		day := t.Truncate(24 * time.Hour)
+		start := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		return start, start.AddDate(0, 0, 7)
+	case CoinMarketCap.CandleMonthly:
+		start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0)
+	default:
+		start := t.Truncate(24 * time.Hour)
+		return start, start.AddDate(0, 0, 1)
+	}
+}
@@ -0,0 +1,137 @@
+// Package services provides the market data service, which serves prices and the fear and
+// greed index from the configured providers, falling back from one to the next.
+package services
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"time"
+
+	"cry-api/app/httpclient"
+	"cry-api/app/logger"
+	CoinMarketCapService "cry-api/app/services/coin_market_cap"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+	EnvTypes "cry-api/app/types/env"
+	app_errors "cry-api/app/types/errors"
+)
+
+// ErrUnsupported is returned by a provider for the data it does not serve. The next provider
+// is tried without reporting a failure.
+var ErrUnsupported = errors.New("not supported by this market data provider")
+
+// MarketDataProvider is a source of market data. It serves the market data methods, in the
+// normalized types of the CoinMarketCap service, and returns ErrUnsupported for the data it
+// does not have.
+type MarketDataProvider interface {
+	CoinMarketCapService.CoinMarketCapServiceInterface
+	// Name returns the provider name used in the configuration and in the source of responses
+	Name() string
+}
+
+// NewProviders returns the configured market data providers in order of preference
+func NewProviders(cfg *EnvTypes.EnvConfig, client httpclient.Doer) []MarketDataProvider {
+	var providers []MarketDataProvider
+	for _, name := range cfg.MarketDataConfig.Providers {
+		switch name {
+		case CoinMarketCapService.ProviderName:
+			providers = append(providers, CoinMarketCapService.NewCoinMarketCapServiceService(cfg, client))
+		case CoinGeckoName:
+			providers = append(providers, NewCoinGeckoProvider(cfg.MarketDataConfig.CoinGeckoAPI, cfg.MarketDataConfig.CoinGeckoAPIKey, client))
+		case AlternativeMeName:
+			providers = append(providers, NewAlternativeMeProvider(cfg.MarketDataConfig.AlternativeMeAPI, client))
+		}
+	}
+	return providers
+}
+
+// MarketDataService serves market data from the first provider that has it. A provider that
+// fails is logged and the next one is tried; invalid requests are refused at once, as every
+// provider would refuse them. Responses name the provider that served them in their source.
+type MarketDataService struct {
+	Providers []MarketDataProvider
+}
+
+// NewMarketDataService initializes and returns a MarketDataService instance
+func NewMarketDataService(providers []MarketDataProvider) *MarketDataService {
+	return &MarketDataService{Providers: providers}
+}
+
+// GetFearAndGreedLastest returns the latest fear and greed index
+func (s *MarketDataService) GetFearAndGreedLastest(ctx context.Context) (*CoinMarketCap.FearGreedData, error) {
+	return fallback(ctx, s, "GetFearAndGreedLastest", func(p MarketDataProvider) (*CoinMarketCap.FearGreedData, error) {
+		return p.GetFearAndGreedLastest(ctx)
+	})
+}
+
+// GetFearAndGreedHistorical returns a page of the fear and greed history, newest first
+func (s *MarketDataService) GetFearAndGreedHistorical(ctx context.Context, start, limit int) (*CoinMarketCap.FearGreedHistorical, error) {
+	return fallback(ctx, s, "GetFearAndGreedHistorical", func(p MarketDataProvider) (*CoinMarketCap.FearGreedHistorical, error) {
+		return p.GetFearAndGreedHistorical(ctx, start, limit)
+	})
+}
+
+// GetHistoricalPrices returns the daily prices of an asset between two dates, oldest first
+func (s *MarketDataService) GetHistoricalPrices(ctx context.Context, symbol, convert string, from, to time.Time) ([]CoinMarketCap.IPricePoint, error) {
+	return fallback(ctx, s, "GetHistoricalPrices", func(p MarketDataProvider) ([]CoinMarketCap.IPricePoint, error) {
+		return p.GetHistoricalPrices(ctx, symbol, convert, from, to)
+	})
+}
+
+// GetLatestQuotes returns the latest quotes of assets
+func (s *MarketDataService) GetLatestQuotes(ctx context.Context, symbols []string, ids []int, convert string) ([]CoinMarketCap.IQuote, error) {
+	return fallback(ctx, s, "GetLatestQuotes", func(p MarketDataProvider) ([]CoinMarketCap.IQuote, error) {
+		return p.GetLatestQuotes(ctx, symbols, ids, convert)
+	})
+}
+
+// GetOHLCV returns the candles of an asset between two times
+func (s *MarketDataService) GetOHLCV(ctx context.Context, symbol, convert, interval string, from, to time.Time) (*CoinMarketCap.IOHLCV, error) {
+	return fallback(ctx, s, "GetOHLCV", func(p MarketDataProvider) (*CoinMarketCap.IOHLCV, error) {
+		return p.GetOHLCV(ctx, symbol, convert, interval, from, to)
+	})
+}
+
+// ConvertPrice values an amount of an asset in a fiat currency
+func (s *MarketDataService) ConvertPrice(ctx context.Context, amount *big.Rat, symbol, convert string) (*CoinMarketCap.IConversion, error) {
+	return fallback(ctx, s, "ConvertPrice", func(p MarketDataProvider) (*CoinMarketCap.IConversion, error) {
+		return p.ConvertPrice(ctx, amount, symbol, convert)
+	})
+}
+
+// fallback calls the providers in order until one serves the request. When a single provider
+// failed its error is returned as is, otherwise the failures are logged and reported together.
+func fallback[T any](ctx context.Context, s *MarketDataService, method string, call func(MarketDataProvider) (T, error)) (T, error) {
+	var zero T
+	failures := 0
+	var lastErr error
+	for _, provider := range s.Providers {
+		value, err := call(provider)
+		if err == nil {
+			return value, nil
+		}
+		if errors.Is(err, ErrUnsupported) {
+			continue
+		}
+
+		var validationErr *app_errors.ValidationError
+		if errors.As(err, &validationErr) {
+			return zero, err
+		}
+
+		logger.FromContext(ctx).WithError(err).WithField("provider", provider.Name()).WithField("method", method).
+			Warn("Market data provider failed, trying the next one")
+		failures++
+		lastErr = err
+	}
+
+	switch failures {
+	case 0:
+		return zero, app_errors.NewAppError(http.StatusServiceUnavailable, "No market data provider configured for this data", "")
+	case 1:
+		return zero, lastErr
+	default:
+		return zero, app_errors.NewUpstreamError("All market data providers failed", lastErr)
+	}
+}
@@ -0,0 +1,86 @@
+// Package services provides the market data service, which serves prices and the fear and
+// greed index from the configured providers, falling back from one to the next.
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"time"
+
+	"cry-api/app/httpclient"
+	"cry-api/app/logger"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+	app_errors "cry-api/app/types/errors"
+)
+
+// unsupported returns ErrUnsupported for every market data method. Providers embed it and
+// override the methods they serve.
+type unsupported struct{}
+
+func (unsupported) GetFearAndGreedLastest(context.Context) (*CoinMarketCap.FearGreedData, error) {
+	return nil, ErrUnsupported
+}
+
+func (unsupported) GetFearAndGreedHistorical(context.Context, int, int) (*CoinMarketCap.FearGreedHistorical, error) {
+	return nil, ErrUnsupported
+}
+
+func (unsupported) GetHistoricalPrices(context.Context, string, string, time.Time, time.Time) ([]CoinMarketCap.IPricePoint, error) {
+	return nil, ErrUnsupported
+}
+
+func (unsupported) GetLatestQuotes(context.Context, []string, []int, string) ([]CoinMarketCap.IQuote, error) {
+	return nil, ErrUnsupported
+}
+
+func (unsupported) GetOHLCV(context.Context, string, string, string, time.Time, time.Time) (*CoinMarketCap.IOHLCV, error) {
+	return nil, ErrUnsupported
+}
+
+func (unsupported) ConvertPrice(context.Context, *big.Rat, string, string) (*CoinMarketCap.IConversion, error) {
+	return nil, ErrUnsupported
+}
+
+// getJSON performs a GET request and decodes its JSON body into out
+func getJSON(ctx context.Context, client httpclient.Doer, endpoint string, header http.Header, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	for key, values := range header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to perform HTTP request: %w", err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response body: %w", err)
+	}
+	return nil
+}
+
+// upstreamError logs a failed provider call and reports it without the upstream error,
+// which can quote the response body
+func upstreamError(ctx context.Context, message string, err error) error {
+	logger.FromContext(ctx).WithError(err).Warn(message)
+	return app_errors.NewUpstreamError(message, err)
+}
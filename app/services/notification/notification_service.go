@@ -0,0 +1,74 @@
+// Package services provides the in-app notification feed and the job notifying users about
+// activity on their watched wallets.
+package services
+
+import (
+	"time"
+
+	UserModel "cry-api/app/models"
+	NotificationRepository "cry-api/app/repositories"
+	app_errors "cry-api/app/types/errors"
+)
+
+// FeedLimit caps the number of notifications returned by the feed
+const FeedLimit = 100
+
+// NotificationService serves the user's in-app notification feed.
+type NotificationService struct {
+	repo NotificationRepository.NotificationRepository
+	now  func() time.Time
+}
+
+// NotificationServiceInterface defines the methods for the NotificationService.
+type NotificationServiceInterface interface {
+	ListNotifications(userID int, unreadOnly bool) ([]UserModel.Notification, error)
+	MarkRead(userID, id int) (*UserModel.Notification, error)
+	MarkAllRead(userID int) (int64, error)
+}
+
+// NewNotificationService initializes and returns a NotificationService instance
+func NewNotificationService(repo NotificationRepository.NotificationRepository) *NotificationService {
+	return &NotificationService{repo: repo, now: time.Now}
+}
+
+// ListNotifications returns the latest notifications of the user, newest first
+func (s *NotificationService) ListNotifications(userID int, unreadOnly bool) ([]UserModel.Notification, error) {
+	notifications, err := s.repo.FindByUserID(userID, unreadOnly, FeedLimit)
+	if err != nil {
+		return nil, app_errors.ErrDatabaseError
+	}
+	if notifications == nil {
+		notifications = []UserModel.Notification{}
+	}
+	return notifications, nil
+}
+
+// MarkRead marks a notification of the user as read. Marking it again keeps the first time.
+func (s *NotificationService) MarkRead(userID, id int) (*UserModel.Notification, error) {
+	notification, err := s.repo.FindByIDAndUserID(id, userID)
+	if err != nil {
+		return nil, app_errors.ErrDatabaseError
+	}
+	if notification == nil {
+		return nil, app_errors.NewNotFoundError("notification", "Notification not found")
+	}
+	if notification.ReadAt != nil {
+		return notification, nil
+	}
+
+	readAt := s.now().UTC()
+	notification.ReadAt = &readAt
+	if err := s.repo.Save(notification); err != nil {
+		return nil, app_errors.ErrDatabaseError
+	}
+	return notification, nil
+}
+
+// MarkAllRead marks every unread notification of the user as read and returns how many were
+func (s *NotificationService) MarkAllRead(userID int) (int64, error) {
+	count, err := s.repo.MarkAllRead(userID, s.now().UTC())
+	if err != nil {
+		return 0, app_errors.ErrDatabaseError
+	}
+	return count, nil
+}
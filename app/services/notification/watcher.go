@@ -0,0 +1,355 @@
+// Package services provides the in-app notification feed and the job notifying users about
+// activity on their watched wallets.
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"cry-api/app/logger"
+	UserModel "cry-api/app/models"
+	Repository "cry-api/app/repositories"
+	EmailService "cry-api/app/services/email"
+	RealtimeService "cry-api/app/services/realtime"
+	WalletExplorerService "cry-api/app/services/wallet_explorer"
+	WatchlistService "cry-api/app/services/watchlist"
+	EnvTypes "cry-api/app/types/env"
+	WatchlistTypes "cry-api/app/types/watchlist"
+)
+
+const satoshisPerBitcoin = 1e8
+
+// WatcherService periodically checks every watched wallet for new and newly confirmed
+// transactions and notifies its owner in the notification feed and by email.
+//
+// Progress is kept in a cursor per wallet: the txids of its recent activity and the chain tip
+// at the last check. Transactions are told apart by txid rather than by time, as block times
+// are not monotonic. A wallet gets at most one notification of each type per transaction
+// (the unique index of the notifications), so alerts are not sent twice across restarts or by
+// several instances. The first check of a wallet only records its cursor, without notifying
+// its past transactions.
+type WatcherService struct {
+	config        *EnvTypes.EnvConfig
+	wallets       Repository.WatchedWalletRepository
+	cursors       Repository.WalletCursorRepository
+	notifications Repository.NotificationRepository
+	users         Repository.UserRepository
+	watchlist     WatchlistService.WatchlistServiceInterface
+	transactions  WalletExplorerService.TransactionServiceInterface
+	email         EmailService.EmailServiceInterface
+	publisher     RealtimeService.Publisher
+	now           func() time.Time
+}
+
+// WatcherServiceInterface defines the methods for the WatcherService.
+type WatcherServiceInterface interface {
+	Run(ctx context.Context)
+	CheckAll(ctx context.Context) error
+	CheckWallet(ctx context.Context, wallet *UserModel.WatchedWallet, tip int) ([]UserModel.Notification, error)
+}
+
+// NewWatcherService initializes and returns a WatcherService instance
+func NewWatcherService(
+	cfg *EnvTypes.EnvConfig,
+	wallets Repository.WatchedWalletRepository,
+	cursors Repository.WalletCursorRepository,
+	notifications Repository.NotificationRepository,
+	users Repository.UserRepository,
+	watchlist WatchlistService.WatchlistServiceInterface,
+	transactions WalletExplorerService.TransactionServiceInterface,
+	email EmailService.EmailServiceInterface,
+) *WatcherService {
+	return &WatcherService{
+		config:        cfg,
+		wallets:       wallets,
+		cursors:       cursors,
+		notifications: notifications,
+		users:         users,
+		watchlist:     watchlist,
+		transactions:  transactions,
+		email:         email,
+		now:           time.Now,
+	}
+}
+
+// SetClock replaces the time source, for tests
+func (s *WatcherService) SetClock(now func() time.Time) {
+	s.now = now
+}
+
+// SetPublisher sets where the delivered notifications are published live, on the wallets
+// topic of the realtime gateway
+func (s *WatcherService) SetPublisher(publisher RealtimeService.Publisher) {
+	s.publisher = publisher
+}
+
+// Run checks the watched wallets every WatcherConfig.Interval seconds until ctx is done. It
+// returns at once when the interval is 0.
+func (s *WatcherService) Run(ctx context.Context) {
+	interval := time.Duration(s.config.WatcherConfig.Interval) * time.Second
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.CheckAll(ctx); err != nil && ctx.Err() == nil {
+			logger.GetLogger().WithError(err).Error("Watchlist check failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckAll checks every watched wallet against the current chain tip. A wallet that cannot be
+// checked is logged and retried on the next run.
+func (s *WatcherService) CheckAll(ctx context.Context) error {
+	tip, err := s.transactions.GetBlockHeight(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch the chain tip: %w", err)
+	}
+
+	wallets, err := s.wallets.FindAll()
+	if err != nil {
+		return fmt.Errorf("failed to load the watched wallets: %w", err)
+	}
+
+	for i := range wallets {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := s.CheckWallet(ctx, &wallets[i], tip); err != nil {
+			logger.GetLogger().WithError(err).WithField("watched_wallet_id", wallets[i].ID).Warn("Failed to check watched wallet")
+		}
+	}
+	return nil
+}
+
+// CheckWallet looks for transactions of the wallet that are new or reached their confirmation
+// target since its last check, delivers the notifications they trigger and moves its cursor.
+// It returns the notifications that were delivered.
+func (s *WatcherService) CheckWallet(ctx context.Context, wallet *UserModel.WatchedWallet, tip int) ([]UserModel.Notification, error) {
+	history, err := s.watchlist.GetRecentWalletActivity(ctx, wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := s.cursors.FindByWalletID(wallet.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the wallet cursor: %w", err)
+	}
+
+	var delivered []UserModel.Notification
+	if cursor == nil {
+		cursor = &UserModel.WalletCursor{WatchedWalletID: wallet.ID, TipHeight: tip}
+	} else {
+		events, err := s.detect(ctx, wallet, cursor, history, tip)
+		if err != nil {
+			return nil, err
+		}
+		if delivered, err = s.deliver(wallet, events); err != nil {
+			return delivered, err
+		}
+	}
+
+	advance(cursor, history, tip)
+	cursor.CheckedAt = s.now().UTC()
+	if err := s.cursors.Save(cursor); err != nil {
+		return delivered, fmt.Errorf("failed to save the wallet cursor: %w", err)
+	}
+	return delivered, nil
+}
+
+// detect builds the notifications triggered by the wallet's recent activity since the cursor.
+// A transaction of the recent activity that was not in it at the last check is new. When none
+// of the last check's transactions are left, as many transactions as the recent activity holds
+// or more may have arrived: the whole history is read, and the transactions it has beyond the recent activity are
+// new if they are unconfirmed or were mined after the last check.
+func (s *WatcherService) detect(ctx context.Context, wallet *UserModel.WatchedWallet, cursor *UserModel.WalletCursor, recent []WatchlistTypes.IWatchlistActivity, tip int) ([]UserModel.Notification, error) {
+	confirmations := s.config.WatcherConfig.Confirmations
+	seen := make(map[string]bool)
+	for _, txid := range strings.Split(cursor.LastTxIDs, ",") {
+		if txid != "" {
+			seen[txid] = true
+		}
+	}
+
+	isNew := make(map[string]bool, len(recent))
+	overlaps := len(recent) == 0
+	for _, tx := range recent {
+		isNew[tx.TxID] = !seen[tx.TxID]
+		overlaps = overlaps || seen[tx.TxID]
+	}
+
+	history := recent
+	if !overlaps {
+		full, err := s.watchlist.GetWalletHistory(ctx, wallet)
+		if err != nil {
+			return nil, err
+		}
+		history = full
+		for _, tx := range history {
+			if _, ok := isNew[tx.TxID]; !ok {
+				isNew[tx.TxID] = !seen[tx.TxID] && (tx.BlockHeight <= 0 || tx.BlockHeight > cursor.TipHeight)
+			}
+		}
+	}
+
+	var events []UserModel.Notification
+	for _, tx := range history {
+		if isNew[tx.TxID] {
+			events = append(events, s.movementEvents(wallet, tx)...)
+		}
+
+		// The transaction reaches its target when the tip moves past its block + confirmations - 1
+		if tx.BlockHeight > 0 {
+			target := tx.BlockHeight + confirmations - 1
+			if target > cursor.TipHeight && target <= tip {
+				events = append(events, newNotification(wallet, tx, UserModel.NotificationTypeConfirmed,
+					fmt.Sprintf("Transaction confirmed on %s", wallet.Label),
+					fmt.Sprintf("Transaction %s of %s reached %d confirmations in block %d.", tx.TxID, wallet.Label, confirmations, tx.BlockHeight),
+				))
+			}
+		}
+	}
+	return events, nil
+}
+
+// movementEvents builds the incoming or outgoing notification of a new transaction, and the
+// large movement one when its amount reaches the threshold
+func (s *WatcherService) movementEvents(wallet *UserModel.WatchedWallet, tx WatchlistTypes.IWatchlistActivity) []UserModel.Notification {
+	if tx.BalanceDiff == 0 {
+		return nil
+	}
+
+	amount := formatBTC(tx.BalanceDiff)
+	var events []UserModel.Notification
+	if tx.BalanceDiff > 0 {
+		events = append(events, newNotification(wallet, tx, UserModel.NotificationTypeIncoming,
+			fmt.Sprintf("Received %s BTC on %s", amount, wallet.Label),
+			fmt.Sprintf("Transaction %s sent %s BTC to %s. %s", tx.TxID, amount, wallet.Label, confirmationStatus(tx)),
+		))
+	} else {
+		events = append(events, newNotification(wallet, tx, UserModel.NotificationTypeOutgoing,
+			fmt.Sprintf("Sent %s BTC from %s", amount, wallet.Label),
+			fmt.Sprintf("Transaction %s spent %s BTC from %s. %s", tx.TxID, amount, wallet.Label, confirmationStatus(tx)),
+		))
+	}
+
+	threshold := s.config.WatcherConfig.LargeMovementSats
+	if threshold > 0 && int64(math.Round(math.Abs(tx.BalanceDiff)*satoshisPerBitcoin)) >= threshold {
+		events = append(events, newNotification(wallet, tx, UserModel.NotificationTypeLargeMovement,
+			fmt.Sprintf("Large movement of %s BTC on %s", amount, wallet.Label),
+			fmt.Sprintf("Transaction %s changed the balance of %s by %s BTC.", tx.TxID, wallet.Label, strconv.FormatFloat(tx.BalanceDiff, 'f', -1, 64)),
+		))
+	}
+	return events
+}
+
+// deliver stores the notifications in the feed and emails the ones that were not stored
+// before. It stops at the first storage error so that the cursor is not moved past them.
+func (s *WatcherService) deliver(wallet *UserModel.WatchedWallet, events []UserModel.Notification) ([]UserModel.Notification, error) {
+	var delivered []UserModel.Notification
+	var user *UserModel.User
+	for i := range events {
+		notification := &events[i]
+		notification.CreatedAt = s.now().UTC()
+
+		created, err := s.notifications.Create(notification)
+		if err != nil {
+			return delivered, fmt.Errorf("failed to store notification: %w", err)
+		}
+		if !created {
+			continue
+		}
+
+		if user == nil {
+			if user, err = s.users.FindByID(wallet.UserID); err != nil || user == nil {
+				logger.GetLogger().WithError(err).WithField("user_id", wallet.UserID).Warn("Failed to load the owner of a watched wallet")
+				user = &UserModel.User{}
+			}
+		}
+		s.sendEmail(user, notification)
+		if s.publisher != nil {
+			s.publisher.PublishToUser(notification.UserID, RealtimeService.TopicWallets, *notification)
+		}
+		delivered = append(delivered, *notification)
+	}
+	return delivered, nil
+}
+
+// sendEmail emails a notification to a verified user and records when it was sent. Failures
+// are only logged: the notification stays in the feed and is not sent again.
+func (s *WatcherService) sendEmail(user *UserModel.User, notification *UserModel.Notification) {
+	if !user.IsVerified || user.Email == "" {
+		return
+	}
+
+	log := logger.GetLogger().WithField("notification_id", notification.ID)
+	err := s.email.SendWalletNotificationEmail(
+		user.Email,
+		s.config.NoReplyEmail,
+		user.Username,
+		notification.Title,
+		notification.Message,
+		strings.TrimRight(s.config.CryAppURL, "/")+"/notifications",
+	)
+	if err != nil {
+		log.WithError(err).Warn("Failed to email notification")
+		return
+	}
+
+	emailedAt := s.now().UTC()
+	notification.EmailedAt = &emailedAt
+	if err := s.notifications.Save(notification); err != nil {
+		log.WithError(err).Warn("Failed to record notification email")
+	}
+}
+
+// advance moves the cursor to the recent activity and the current tip
+func advance(cursor *UserModel.WalletCursor, recent []WatchlistTypes.IWatchlistActivity, tip int) {
+	txids := make([]string, 0, len(recent))
+	for _, tx := range recent {
+		txids = append(txids, tx.TxID)
+	}
+	cursor.LastTxIDs = strings.Join(txids, ",")
+
+	if tip > cursor.TipHeight {
+		cursor.TipHeight = tip
+	}
+}
+
+func newNotification(wallet *UserModel.WatchedWallet, tx WatchlistTypes.IWatchlistActivity, kind, title, message string) UserModel.Notification {
+	return UserModel.Notification{
+		UserID:          wallet.UserID,
+		WatchedWalletID: wallet.ID,
+		TxID:            tx.TxID,
+		Type:            kind,
+		Title:           title,
+		Message:         message,
+		Amount:          tx.BalanceDiff,
+		BlockHeight:     tx.BlockHeight,
+	}
+}
+
+func confirmationStatus(tx WatchlistTypes.IWatchlistActivity) string {
+	if tx.BlockHeight <= 0 {
+		return "It is not confirmed yet."
+	}
+	return fmt.Sprintf("It was confirmed in block %d.", tx.BlockHeight)
+}
+
+// formatBTC renders the absolute value of an amount in BTC without trailing zeros
+func formatBTC(amount float64) string {
+	return strconv.FormatFloat(math.Abs(amount), 'f', -1, 64)
+}
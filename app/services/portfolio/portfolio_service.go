@@ -0,0 +1,183 @@
+// Package services provides portfolio valuation on top of the watchlist and market data.
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"cry-api/app/logger"
+	"cry-api/app/money"
+	CoinMarketCapService "cry-api/app/services/coin_market_cap"
+	FXService "cry-api/app/services/fx"
+	WatchlistService "cry-api/app/services/watchlist"
+	app_errors "cry-api/app/types/errors"
+	PortfolioTypes "cry-api/app/types/portfolio"
+)
+
+const (
+	// DefaultCurrency is used when no fiat currency is requested
+	DefaultCurrency = money.DefaultCurrency
+	// DefaultDays is the default length of the value series
+	DefaultDays = 30
+	// MaxDays is the longest value series that can be requested
+	MaxDays = 365
+
+	dayLayout = "2006-01-02"
+)
+
+// PortfolioService values a user's watched wallets over time.
+type PortfolioService struct {
+	watchlistService     WatchlistService.WatchlistServiceInterface
+	coinMarketCapService CoinMarketCapService.CoinMarketCapServiceInterface
+}
+
+// PortfolioServiceInterface defines the methods for the PortfolioService.
+type PortfolioServiceInterface interface {
+	GetValuation(ctx context.Context, userID int, currency string, days int) (*PortfolioTypes.IPortfolioValuation, error)
+}
+
+// NewPortfolioService initializes and returns a PortfolioService instance
+func NewPortfolioService(
+	watchlistService WatchlistService.WatchlistServiceInterface,
+	coinMarketCapService CoinMarketCapService.CoinMarketCapServiceInterface,
+) *PortfolioService {
+	return &PortfolioService{
+		watchlistService:     watchlistService,
+		coinMarketCapService: coinMarketCapService,
+	}
+}
+
+// GetValuation reconstructs the daily balance of the user's watchlist over the last `days`
+// days and values it with historical prices. The cost basis uses the average cost method,
+// valuing every acquisition at the price of the day it happened.
+func (s *PortfolioService) GetValuation(ctx context.Context, userID int, currency string, days int) (*PortfolioTypes.IPortfolioValuation, error) {
+	if strings.TrimSpace(currency) == "" {
+		currency = DefaultCurrency
+	}
+	currency, err := FXService.NormalizeCurrency("currency", currency)
+	if err != nil {
+		return nil, err
+	}
+	if days == 0 {
+		days = DefaultDays
+	}
+	if days < 1 || days > MaxDays {
+		return nil, app_errors.NewValidationError("days", fmt.Sprint(days), fmt.Sprintf("Days must be between 1 and %d", MaxDays))
+	}
+
+	now := time.Now().UTC()
+	to := truncateDay(now)
+	from := to.AddDate(0, 0, -(days - 1))
+
+	valuation := &PortfolioTypes.IPortfolioValuation{
+		Currency:    currency,
+		From:        from.Format(dayLayout),
+		To:          to.Format(dayLayout),
+		Points:      []PortfolioTypes.IPortfolioPoint{},
+		GeneratedAt: now,
+	}
+
+	history, err := s.watchlistService.GetHistory(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	events := history.Transactions
+	valuation.Warnings = history.Warnings
+
+	priceFrom := from
+	if len(events) > 0 {
+		if first := truncateDay(time.Unix(events[0].Time, 0).UTC()); first.Before(priceFrom) {
+			priceFrom = first
+		}
+	}
+
+	points, err := s.coinMarketCapService.GetHistoricalPrices(ctx, "BTC", currency, priceFrom, to)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).WithField("currency", currency).Warn("Failed to fetch historical prices for portfolio")
+		return nil, app_errors.NewUpstreamError("Failed to fetch historical prices", err)
+	}
+	if len(points) == 0 {
+		return nil, app_errors.NewUpstreamError("Failed to fetch historical prices", nil)
+	}
+	prices := CoinMarketCapService.NewPriceSeries(points)
+
+	// Average cost basis over the full history, counted in satoshis and exact fiat amounts
+	var holdings int64
+	cost := new(big.Rat)
+	for _, e := range events {
+		day := time.Unix(e.Time, 0).UTC().Format(dayLayout)
+		diff := toSats(e.BalanceDiff)
+		if diff > 0 {
+			cost.Add(cost, new(big.Rat).Mul(money.Sats(diff), prices.On(day)))
+			holdings += diff
+			continue
+		}
+		if holdings > 0 {
+			spent := min(-diff, holdings)
+			cost.Sub(cost, new(big.Rat).Mul(cost, big.NewRat(spent, holdings)))
+		}
+		holdings = max(holdings+diff, 0)
+	}
+
+	// Daily balance series over the requested window
+	var balance int64
+	next := 0
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		endOfDay := day.AddDate(0, 0, 1).Unix()
+		for next < len(events) && events[next].Time < endOfDay {
+			balance += toSats(events[next].BalanceDiff)
+			next++
+		}
+
+		date := day.Format(dayLayout)
+		price, err := money.FromRat(prices.On(date), currency)
+		if err != nil {
+			return nil, app_errors.NewValidationError("currency", currency, err.Error())
+		}
+		value, err := money.FromRat(new(big.Rat).Mul(money.Sats(balance), prices.On(date)), currency)
+		if err != nil {
+			return nil, app_errors.NewValidationError("currency", currency, err.Error())
+		}
+		valuation.Points = append(valuation.Points, PortfolioTypes.IPortfolioPoint{
+			Date:    date,
+			Balance: float64(balance) / money.SatoshisPerBitcoin,
+			Price:   price,
+			Value:   value,
+		})
+	}
+
+	last := valuation.Points[len(valuation.Points)-1]
+	costBasis, err := money.FromRat(cost, currency)
+	if err != nil {
+		return nil, app_errors.NewValidationError("currency", currency, err.Error())
+	}
+	pnl, err := money.FromMinor(last.Value.MinorUnits-costBasis.MinorUnits, currency)
+	if err != nil {
+		return nil, app_errors.NewValidationError("currency", currency, err.Error())
+	}
+
+	valuation.Balance = last.Balance
+	valuation.Price = last.Price
+	valuation.Value = last.Value
+	valuation.CostBasis = costBasis
+	valuation.UnrealizedPnL = pnl
+	if costBasis.MinorUnits > 0 {
+		pct := math.Round(float64(pnl.MinorUnits)/float64(costBasis.MinorUnits)*10000) / 100
+		valuation.UnrealizedPnLPercent = &pct
+	}
+
+	return valuation, nil
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// toSats converts an amount of bitcoin read from upstream to satoshis
+func toSats(btc float64) int64 {
+	return int64(math.Round(btc * money.SatoshisPerBitcoin))
+}
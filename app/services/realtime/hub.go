@@ -0,0 +1,365 @@
+// Package services provides the realtime gateway: the hub fanning events out to the SSE and
+// WebSocket connections, and the feed publishing the market data they subscribe to.
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"cry-api/app/logger"
+	EnvTypes "cry-api/app/types/env"
+	app_errors "cry-api/app/types/errors"
+	RealtimeTypes "cry-api/app/types/realtime"
+)
+
+// Topics a connection can subscribe to. Price and fear and greed events are shared by every
+// subscriber; wallet and alert events only reach their owner's connections.
+const (
+	// TopicPricePrefix starts the price tick topic of a symbol, e.g. "price:BTC"
+	TopicPricePrefix = "price:"
+	// TopicFearGreed carries the updates of the fear and greed index
+	TopicFearGreed = "fear_greed"
+	// TopicWallets carries the notifications of the user's watched wallets
+	TopicWallets = "wallets"
+	// TopicAlerts carries the triggers of the user's alert rules
+	TopicAlerts = "alerts"
+)
+
+// reasonSlow is the reason given to a connection dropped for not keeping up with its events
+const reasonSlow = "Too slow to keep up with the events, please reconnect"
+
+// ReasonShutdown is the reason given to the connections dropped when the server shuts down
+const ReasonShutdown = "Server is shutting down, please reconnect"
+
+var symbolPattern = regexp.MustCompile(`^[A-Z0-9]{1,20}$`)
+
+// Publisher publishes the events of a user, for the services producing them
+type Publisher interface {
+	PublishToUser(userID int, topic string, data any)
+}
+
+// HubInterface defines the methods for the Hub.
+type HubInterface interface {
+	Publisher
+	Register(userID int) *Client
+	Unregister(client *Client)
+	Subscribe(client *Client, topics []string) ([]string, error)
+	Unsubscribe(client *Client, topics []string) ([]string, error)
+	Topics(client *Client) []string
+	Publish(topic string, data any)
+	SubscribedSymbols() []string
+	HasSubscribers(topic string) bool
+	Stats() RealtimeTypes.IHubStats
+	Close()
+}
+
+// Client is a connection registered in the hub. Its transport reads the events from Events()
+// and stops when Done() is closed.
+type Client struct {
+	UserID int
+
+	events chan RealtimeTypes.IEvent
+	topics map[string]bool // guarded by the hub's mutex
+	done   chan struct{}
+	once   sync.Once
+	reason string
+}
+
+// Events returns the events to send to the connection
+func (c *Client) Events() <-chan RealtimeTypes.IEvent {
+	return c.events
+}
+
+// Done is closed when the hub drops the connection or it is unregistered
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Reason tells why the hub dropped the connection, once Done is closed
+func (c *Client) Reason() string {
+	return c.reason
+}
+
+func (c *Client) close(reason string) {
+	c.once.Do(func() {
+		c.reason = reason
+		close(c.done)
+	})
+}
+
+// Hub fans the published events out to the subscribed connections.
+//
+// Publishing never blocks: each connection has a buffer of RealtimeConfig.BufferSize events,
+// and a connection whose buffer is full is dropped rather than slowing the publishers and the
+// other connections down. The last event of each shared topic is kept and sent to new
+// subscribers, so that they do not wait for the next update.
+type Hub struct {
+	config *EnvTypes.EnvConfig
+	now    func() time.Time
+
+	mu      sync.RWMutex
+	clients map[*Client]struct{}
+	last    map[string]RealtimeTypes.IEvent
+
+	seq     atomic.Uint64
+	dropped atomic.Uint64
+}
+
+// NewHub initializes and returns a Hub instance
+func NewHub(cfg *EnvTypes.EnvConfig) *Hub {
+	return &Hub{
+		config:  cfg,
+		now:     time.Now,
+		clients: make(map[*Client]struct{}),
+		last:    make(map[string]RealtimeTypes.IEvent),
+	}
+}
+
+// Register adds a connection of the user to the hub
+func (h *Hub) Register(userID int) *Client {
+	client := &Client{
+		UserID: userID,
+		events: make(chan RealtimeTypes.IEvent, h.config.RealtimeConfig.BufferSize),
+		topics: make(map[string]bool),
+		done:   make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	h.clients[client] = struct{}{}
+	h.mu.Unlock()
+	return client
+}
+
+// Unregister removes a connection from the hub
+func (h *Hub) Unregister(client *Client) {
+	h.mu.Lock()
+	delete(h.clients, client)
+	h.mu.Unlock()
+	client.close("")
+}
+
+// Close drops every connection with ReasonShutdown, so that the streams end when the server
+// shuts down. Connections registered afterwards are not affected.
+func (h *Hub) Close() {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for client := range h.clients {
+		client.close(ReasonShutdown)
+	}
+}
+
+// Subscribe adds topics to a connection, within the RealtimeConfig.MaxSubscriptions limit,
+// and sends it the last event of the shared ones. It returns the normalized topics.
+func (h *Hub) Subscribe(client *Client, topics []string) ([]string, error) {
+	normalized, err := normalizeTopics(topics)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	added := 0
+	for _, topic := range normalized {
+		if !client.topics[topic] {
+			added++
+		}
+	}
+	if limit := h.config.RealtimeConfig.MaxSubscriptions; len(client.topics)+added > limit {
+		h.mu.Unlock()
+		return nil, app_errors.NewValidationError("topics", strings.Join(topics, ","),
+			fmt.Sprintf("A connection can subscribe to at most %d topics", limit))
+	}
+
+	var replay []RealtimeTypes.IEvent
+	for _, topic := range normalized {
+		if client.topics[topic] {
+			continue
+		}
+		client.topics[topic] = true
+		if event, ok := h.last[topic]; ok {
+			replay = append(replay, event)
+		}
+	}
+	h.mu.Unlock()
+
+	for _, event := range replay {
+		h.deliver(client, event)
+	}
+	return normalized, nil
+}
+
+// Unsubscribe removes topics from a connection. It returns the normalized topics.
+func (h *Hub) Unsubscribe(client *Client, topics []string) ([]string, error) {
+	normalized, err := normalizeTopics(topics)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	for _, topic := range normalized {
+		delete(client.topics, topic)
+	}
+	h.mu.Unlock()
+	return normalized, nil
+}
+
+// Topics returns the topics of a connection, sorted
+func (h *Hub) Topics(client *Client) []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	topics := make([]string, 0, len(client.topics))
+	for topic := range client.topics {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+// Publish sends an event of a shared topic to its subscribers and keeps it for the next ones
+func (h *Hub) Publish(topic string, data any) {
+	event := h.event(topic, data)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.last[topic] = event
+	for client := range h.clients {
+		if client.topics[topic] {
+			h.deliver(client, event)
+		}
+	}
+}
+
+// PublishToUser sends an event of a user topic to the user's subscribed connections
+func (h *Hub) PublishToUser(userID int, topic string, data any) {
+	event := h.event(topic, data)
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for client := range h.clients {
+		if client.UserID == userID && client.topics[topic] {
+			h.deliver(client, event)
+		}
+	}
+}
+
+// SubscribedSymbols returns the symbols of the price topics with subscribers, sorted
+func (h *Hub) SubscribedSymbols() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	for client := range h.clients {
+		for topic := range client.topics {
+			if symbol, ok := strings.CutPrefix(topic, TopicPricePrefix); ok {
+				seen[symbol] = true
+			}
+		}
+	}
+
+	symbols := make([]string, 0, len(seen))
+	for symbol := range seen {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// HasSubscribers reports whether a connection is subscribed to the topic
+func (h *Hub) HasSubscribers(topic string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for client := range h.clients {
+		if client.topics[topic] {
+			return true
+		}
+	}
+	return false
+}
+
+// Stats counts the connections, their subscriptions and the connections dropped as too slow
+func (h *Hub) Stats() RealtimeTypes.IHubStats {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	stats := RealtimeTypes.IHubStats{Connections: len(h.clients), Dropped: h.dropped.Load()}
+	for client := range h.clients {
+		stats.Subscriptions += len(client.topics)
+	}
+	return stats
+}
+
+func (h *Hub) event(topic string, data any) RealtimeTypes.IEvent {
+	return RealtimeTypes.IEvent{
+		Type:  RealtimeTypes.MessageEvent,
+		ID:    h.seq.Add(1),
+		Topic: topic,
+		Data:  data,
+		Time:  h.now().UTC(),
+	}
+}
+
+// deliver queues an event for a connection without blocking, dropping the connection when
+// its buffer is full
+func (h *Hub) deliver(client *Client, event RealtimeTypes.IEvent) {
+	select {
+	case <-client.done:
+		return
+	default:
+	}
+
+	select {
+	case client.events <- event:
+	default:
+		h.dropped.Add(1)
+		client.close(reasonSlow)
+		logger.GetLogger().WithField("user_id", client.UserID).Warn("Dropped a realtime connection that could not keep up")
+	}
+}
+
+// NormalizeTopic validates a topic and returns its canonical form
+func NormalizeTopic(topic string) (string, error) {
+	topic = strings.TrimSpace(topic)
+	if prefix, symbol, ok := strings.Cut(topic, ":"); ok && strings.EqualFold(prefix+":", TopicPricePrefix) {
+		symbol = strings.ToUpper(symbol)
+		if !symbolPattern.MatchString(symbol) {
+			return "", app_errors.NewValidationError("topics", topic, "Price topics must name a symbol of 1 to 20 letters or digits, like price:BTC")
+		}
+		return TopicPricePrefix + symbol, nil
+	}
+
+	switch lower := strings.ToLower(topic); lower {
+	case TopicFearGreed, TopicWallets, TopicAlerts:
+		return lower, nil
+	}
+	return "", app_errors.NewValidationError("topics", topic, "Topic must be price:<SYMBOL>, fear_greed, wallets or alerts")
+}
+
+// normalizeTopics normalizes a list of topics, dropping duplicates
+func normalizeTopics(topics []string) ([]string, error) {
+	if len(topics) == 0 {
+		return nil, app_errors.NewValidationError("topics", "", "At least one topic is required")
+	}
+
+	var normalized []string
+	seen := make(map[string]bool)
+	for _, raw := range topics {
+		topic, err := NormalizeTopic(raw)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[topic] {
+			seen[topic] = true
+			normalized = append(normalized, topic)
+		}
+	}
+	return normalized, nil
+}
@@ -0,0 +1,103 @@
+// Package services provides the realtime gateway: the hub fanning events out to the SSE and
+// WebSocket connections, and the feed publishing the market data they subscribe to.
+package services
+
+import (
+	"context"
+	"time"
+
+	"cry-api/app/logger"
+	CoinMarketCapService "cry-api/app/services/coin_market_cap"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+	EnvTypes "cry-api/app/types/env"
+)
+
+// MarketFeedService polls the market data of the subscribed topics and publishes their
+// changes to the hub: USD quotes of the symbols of the price topics, and the fear and greed
+// index. Nothing is fetched while no connection subscribes, and an unchanged quote or index
+// is not published again.
+type MarketFeedService struct {
+	config *EnvTypes.EnvConfig
+	hub    HubInterface
+	market CoinMarketCapService.CoinMarketCapServiceInterface
+
+	prices    map[string]CoinMarketCap.IQuote
+	fearGreed *CoinMarketCap.FearGreedData
+}
+
+// MarketFeedServiceInterface defines the methods for the MarketFeedService.
+type MarketFeedServiceInterface interface {
+	Run(ctx context.Context)
+	Tick(ctx context.Context)
+}
+
+// NewMarketFeedService initializes and returns a MarketFeedService instance
+func NewMarketFeedService(
+	cfg *EnvTypes.EnvConfig,
+	hub HubInterface,
+	market CoinMarketCapService.CoinMarketCapServiceInterface,
+) *MarketFeedService {
+	return &MarketFeedService{
+		config: cfg,
+		hub:    hub,
+		market: market,
+		prices: make(map[string]CoinMarketCap.IQuote),
+	}
+}
+
+// Run polls the market data every RealtimeConfig.PriceInterval seconds until ctx is done. It
+// returns at once when the interval is 0.
+func (s *MarketFeedService) Run(ctx context.Context) {
+	interval := time.Duration(s.config.RealtimeConfig.PriceInterval) * time.Second
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.Tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick fetches and publishes the subscribed market data once. Failures are logged and
+// retried on the next tick.
+func (s *MarketFeedService) Tick(ctx context.Context) {
+	symbols := s.hub.SubscribedSymbols()
+	for start := 0; start < len(symbols); start += CoinMarketCapService.MaxQuoteAssets {
+		batch := symbols[start:min(start+CoinMarketCapService.MaxQuoteAssets, len(symbols))]
+		quotes, err := s.market.GetLatestQuotes(ctx, batch, nil, CoinMarketCapService.DefaultConvert)
+		if err != nil {
+			logger.GetLogger().WithError(err).Warn("Failed to fetch quotes for the realtime feed")
+			continue
+		}
+		for _, quote := range quotes {
+			if last, ok := s.prices[quote.Symbol]; ok && last.Price == quote.Price && last.LastUpdated.Equal(quote.LastUpdated) {
+				continue
+			}
+			s.prices[quote.Symbol] = quote
+			s.hub.Publish(TopicPricePrefix+quote.Symbol, quote)
+		}
+	}
+
+	if !s.hub.HasSubscribers(TopicFearGreed) {
+		return
+	}
+	latest, err := s.market.GetFearAndGreedLastest(ctx)
+	if err != nil {
+		logger.GetLogger().WithError(err).Warn("Failed to fetch the fear and greed index for the realtime feed")
+		return
+	}
+	if last := s.fearGreed; last != nil && last.Data.Value == latest.Data.Value && last.Data.UpdateTime.Equal(latest.Data.UpdateTime) {
+		return
+	}
+	s.fearGreed = latest
+	s.hub.Publish(TopicFearGreed, latest)
+}
@@ -0,0 +1,200 @@
+// Package services provides cost-basis and capital-gains reporting for watched wallets.
+package services
+
+import (
+	"math/big"
+	"sort"
+	"time"
+
+	"cry-api/app/money"
+	TaxTypes "cry-api/app/types/tax"
+)
+
+// Method is a lot-selection strategy for matching disposals against acquisitions
+type Method string
+
+// Supported lot-selection methods
+const (
+	MethodFIFO Method = "fifo" // first in, first out
+	MethodLIFO Method = "lifo" // last in, first out
+	MethodHIFO Method = "hifo" // highest cost in, first out
+)
+
+// Term classifications of a realized gain
+const (
+	TermShort = "short"
+	TermLong  = "long"
+)
+
+// Event is a balance change of the tracked wallets, valued at the price of its day
+type Event struct {
+	TxID     string
+	Time     time.Time
+	Sats     int64    // positive for acquisitions, negative for disposals
+	Price    *big.Rat // fiat per BTC on the day of the event
+	Transfer bool     // tagged as a move between the user's own wallets
+}
+
+// lot is a still-held acquisition
+type lot struct {
+	txid     string
+	acquired time.Time
+	sats     int64
+	price    *big.Rat
+}
+
+// MatchLots replays events in chronological order and matches every disposal against the
+// open lots chosen by method. A transaction tagged as an own-wallet transfer is given as an
+// outgoing leg and, when the receiving wallet is tracked, an incoming leg with the same TxID:
+// the lots consumed by the outgoing leg move to the receiving wallet with their acquisition
+// date and price, and only the difference between the legs, the network fee, is disposed of.
+// An outgoing leg without an incoming one removes lots without realizing a gain, and an incoming
+// leg without an outgoing one opens a lot at the market price of its day.
+// A disposal larger than the open lots is reported with a zero cost basis and flagged as unmatched.
+// Proceeds and cost bases are computed exactly and rounded to the minor units of currency.
+func MatchLots(events []Event, method Method, currency string) ([]TaxTypes.IDisposal, bool, error) {
+	sorted := make([]Event, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	// Sats sent and received by the legs of every tagged transfer
+	sent := make(map[string]int64)
+	received := make(map[string]int64)
+	for _, e := range sorted {
+		switch {
+		case e.Transfer && e.Sats < 0:
+			sent[e.TxID] -= e.Sats
+		case e.Transfer && e.Sats > 0:
+			received[e.TxID] += e.Sats
+		}
+	}
+
+	var lots []*lot
+	var disposals []TaxTypes.IDisposal
+	unmatched := false
+
+	for _, e := range sorted {
+		if e.Sats > 0 {
+			// The incoming leg of a transfer receives the lots of its outgoing leg
+			if !e.Transfer || sent[e.TxID] == 0 {
+				lots = append(lots, &lot{txid: e.TxID, acquired: e.Time, sats: e.Sats, price: e.Price})
+			}
+			continue
+		}
+
+		paired := e.Transfer && received[e.TxID] > 0
+		carried := int64(0)
+		if paired {
+			carried = min(received[e.TxID], -e.Sats)
+			received[e.TxID] -= carried
+		}
+
+		consumed, remaining := takeLots(&lots, -e.Sats, method)
+		for _, part := range consumed {
+			keep := min(carried, part.sats)
+			if keep > 0 {
+				lots = append(lots, &lot{txid: part.txid, acquired: part.acquired, sats: keep, price: part.price})
+				carried -= keep
+			}
+			if disposed := part.sats - keep; disposed > 0 && (!e.Transfer || paired) {
+				disposal, err := newDisposal(e, part.txid, part.acquired, disposed, part.price, false, currency)
+				if err != nil {
+					return nil, false, err
+				}
+				disposals = append(disposals, disposal)
+			}
+		}
+
+		if carried > 0 {
+			// More was received than the open lots cover; the history is incomplete
+			lots = append(lots, &lot{txid: e.TxID, acquired: e.Time, sats: carried, price: e.Price})
+			remaining -= carried
+		}
+		if remaining > 0 && (!e.Transfer || paired) {
+			unmatched = true
+			disposal, err := newDisposal(e, "", e.Time, remaining, new(big.Rat), true, currency)
+			if err != nil {
+				return nil, false, err
+			}
+			disposals = append(disposals, disposal)
+		}
+	}
+
+	return disposals, unmatched, nil
+}
+
+// takeLots removes sats from the open lots chosen by method and returns the parts taken, along
+// with the sats the open lots could not cover
+func takeLots(lots *[]*lot, sats int64, method Method) ([]lot, int64) {
+	var taken []lot
+	for sats > 0 && len(*lots) > 0 {
+		idx := selectLot(*lots, method)
+		l := (*lots)[idx]
+		take := min(sats, l.sats)
+		taken = append(taken, lot{txid: l.txid, acquired: l.acquired, sats: take, price: l.price})
+
+		l.sats -= take
+		sats -= take
+		if l.sats == 0 {
+			*lots = append((*lots)[:idx], (*lots)[idx+1:]...)
+		}
+	}
+	return taken, sats
+}
+
+// selectLot returns the index of the lot to consume next. Lots carried by a transfer keep their
+// acquisition date, so FIFO and LIFO order by it rather than by position.
+func selectLot(lots []*lot, method Method) int {
+	best := 0
+	for i, l := range lots {
+		switch method {
+		case MethodLIFO:
+			if !l.acquired.Before(lots[best].acquired) {
+				best = i
+			}
+		case MethodHIFO:
+			if l.price.Cmp(lots[best].price) > 0 {
+				best = i
+			}
+		default:
+			if l.acquired.Before(lots[best].acquired) {
+				best = i
+			}
+		}
+	}
+	return best
+}
+
+func newDisposal(e Event, acquiredTx string, acquired time.Time, sats int64, costPrice *big.Rat, unmatched bool, currency string) (TaxTypes.IDisposal, error) {
+	proceeds, err := money.FromRat(new(big.Rat).Mul(money.Sats(sats), e.Price), currency)
+	if err != nil {
+		return TaxTypes.IDisposal{}, err
+	}
+	cost, err := money.FromRat(new(big.Rat).Mul(money.Sats(sats), costPrice), currency)
+	if err != nil {
+		return TaxTypes.IDisposal{}, err
+	}
+	gain, err := money.FromMinor(proceeds.MinorUnits-cost.MinorUnits, currency)
+	if err != nil {
+		return TaxTypes.IDisposal{}, err
+	}
+
+	term := TermShort
+	if e.Time.After(acquired.AddDate(1, 0, 0)) {
+		term = TermLong
+	}
+
+	return TaxTypes.IDisposal{
+		TxID:        e.TxID,
+		AcquiredTx:  acquiredTx,
+		Acquired:    acquired.UTC().Format("2006-01-02"),
+		Disposed:    e.Time.UTC().Format("2006-01-02"),
+		Amount:      float64(sats) / money.SatoshisPerBitcoin,
+		Proceeds:    proceeds,
+		CostBasis:   cost,
+		Gain:        gain,
+		Term:        term,
+		Unmatched:   unmatched,
+		HoldingDays: int(e.Time.Sub(acquired).Hours() / 24),
+	}, nil
+}
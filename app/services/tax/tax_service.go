@@ -0,0 +1,398 @@
+// Package services provides cost-basis and capital-gains reporting for watched wallets.
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cry-api/app/logger"
+	UserModel "cry-api/app/models"
+	"cry-api/app/money"
+	TransferTagRepository "cry-api/app/repositories"
+	CoinMarketCapService "cry-api/app/services/coin_market_cap"
+	FXService "cry-api/app/services/fx"
+	WatchlistService "cry-api/app/services/watchlist"
+	app_errors "cry-api/app/types/errors"
+	TaxTypes "cry-api/app/types/tax"
+	WatchlistTypes "cry-api/app/types/watchlist"
+)
+
+// DefaultCurrency is used when no fiat currency is requested
+const DefaultCurrency = money.DefaultCurrency
+
+var txidPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// CSVHeader lists the columns of the CSV report, one row per matched disposal
+var CSVHeader = []string{
+	"year", "txid", "acquired_txid", "acquired", "disposed", "amount_btc",
+	"proceeds", "cost_basis", "gain", "term", "holding_days",
+}
+
+// TaxService computes realized gains from watched wallet history and historical prices.
+type TaxService struct {
+	transferRepo         TransferTagRepository.TransferTagRepository
+	watchlistService     WatchlistService.WatchlistServiceInterface
+	coinMarketCapService CoinMarketCapService.CoinMarketCapServiceInterface
+}
+
+// TaxServiceInterface defines the methods for the TaxService.
+type TaxServiceInterface interface {
+	ListTransfers(userID int) ([]UserModel.TransferTag, error)
+	TagTransfer(userID int, req TaxTypes.ITransferTagRequest) (*UserModel.TransferTag, error)
+	UntagTransfer(userID, id int) error
+	GetReport(ctx context.Context, userID, walletID int, method, currency string, year int) (*TaxTypes.ITaxReport, error)
+}
+
+// NewTaxService initializes and returns a TaxService instance
+func NewTaxService(
+	transferRepo TransferTagRepository.TransferTagRepository,
+	watchlistService WatchlistService.WatchlistServiceInterface,
+	coinMarketCapService CoinMarketCapService.CoinMarketCapServiceInterface,
+) *TaxService {
+	return &TaxService{
+		transferRepo:         transferRepo,
+		watchlistService:     watchlistService,
+		coinMarketCapService: coinMarketCapService,
+	}
+}
+
+// ListTransfers returns the transactions the user tagged as own-wallet transfers
+func (s *TaxService) ListTransfers(userID int) ([]UserModel.TransferTag, error) {
+	tags, err := s.transferRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, app_errors.ErrDatabaseError
+	}
+	if tags == nil {
+		tags = []UserModel.TransferTag{}
+	}
+	return tags, nil
+}
+
+// TagTransfer marks a transaction as a transfer between the user's own wallets
+func (s *TaxService) TagTransfer(userID int, req TaxTypes.ITransferTagRequest) (*UserModel.TransferTag, error) {
+	txid := strings.ToLower(strings.TrimSpace(req.TxID))
+	if !txidPattern.MatchString(txid) {
+		return nil, app_errors.NewValidationError("txid", req.TxID, "Transaction id must be 64 hex characters")
+	}
+	note := strings.TrimSpace(req.Note)
+	if len(note) > 255 {
+		return nil, app_errors.NewValidationError("note", note, "Note must be at most 255 characters")
+	}
+
+	existing, err := s.transferRepo.FindByTxID(userID, txid)
+	if err != nil {
+		return nil, app_errors.ErrDatabaseError
+	}
+	if existing != nil {
+		return nil, app_errors.NewConflictError("transfer", "Transaction is already tagged as a transfer")
+	}
+
+	tag := &UserModel.TransferTag{
+		UserID:    userID,
+		TxID:      txid,
+		Note:      note,
+		CreatedAt: time.Now(),
+	}
+	if err := s.transferRepo.Save(tag); err != nil {
+		return nil, app_errors.ErrDatabaseError
+	}
+	return tag, nil
+}
+
+// UntagTransfer removes a transfer tag owned by the user
+func (s *TaxService) UntagTransfer(userID, id int) error {
+	deleted, err := s.transferRepo.Delete(id, userID)
+	if err != nil {
+		return app_errors.ErrDatabaseError
+	}
+	if !deleted {
+		return app_errors.NewNotFoundError("transfer", "Transfer tag not found")
+	}
+	return nil
+}
+
+// GetReport computes realized gains per calendar year. walletID restricts the report to a
+// single watched wallet (0 covers the whole watchlist) and year to a single year (0 for all).
+func (s *TaxService) GetReport(ctx context.Context, userID, walletID int, method, currency string, year int) (*TaxTypes.ITaxReport, error) {
+	lotMethod := Method(strings.ToLower(strings.TrimSpace(method)))
+	if lotMethod == "" {
+		lotMethod = MethodFIFO
+	}
+	if lotMethod != MethodFIFO && lotMethod != MethodLIFO && lotMethod != MethodHIFO {
+		return nil, app_errors.NewValidationError("method", method, "Method must be one of fifo, lifo or hifo")
+	}
+
+	if strings.TrimSpace(currency) == "" {
+		currency = DefaultCurrency
+	}
+	currency, err := FXService.NormalizeCurrency("currency", currency)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &TaxTypes.ITaxReport{
+		Method:      string(lotMethod),
+		Currency:    currency,
+		Years:       []TaxTypes.ITaxYear{},
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	tags, err := s.ListTransfers(userID)
+	if err != nil {
+		return nil, err
+	}
+	transfers := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		transfers[tag.TxID] = true
+	}
+
+	activity, warnings, err := s.activity(ctx, userID, walletID)
+	if err != nil {
+		return nil, err
+	}
+	report.Warnings = warnings
+	if walletID > 0 {
+		report.WalletID = &walletID
+	}
+	legs := transferLegs(activity, transfers)
+	if len(legs) == 0 {
+		return report, nil
+	}
+
+	first := time.Unix(legs[0].Time, 0).UTC()
+	points, err := s.coinMarketCapService.GetHistoricalPrices(ctx, "BTC", currency, first, time.Now().UTC())
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).WithField("currency", currency).Warn("Failed to fetch historical prices for tax report")
+		return nil, app_errors.NewUpstreamError("Failed to fetch historical prices", err)
+	}
+	prices := CoinMarketCapService.NewPriceSeries(points)
+	if prices.Len() == 0 {
+		return nil, app_errors.NewUpstreamError("Failed to fetch historical prices", nil)
+	}
+
+	events := make([]Event, 0, len(legs))
+	for _, tx := range legs {
+		sats := int64(math.Round(tx.BalanceDiff * 1e8))
+		if sats == 0 {
+			continue
+		}
+		ts := time.Unix(tx.Time, 0).UTC()
+		events = append(events, Event{
+			TxID:     tx.TxID,
+			Time:     ts,
+			Sats:     sats,
+			Price:    prices.On(ts.Format("2006-01-02")),
+			Transfer: transfers[strings.ToLower(tx.TxID)],
+		})
+	}
+
+	disposals, unmatched, err := MatchLots(events, lotMethod, currency)
+	if err != nil {
+		return nil, app_errors.NewValidationError("currency", currency, err.Error())
+	}
+	if unmatched {
+		report.Warnings = append(report.Warnings, "More BTC was disposed of than acquired; history may be incomplete and unmatched disposals use a zero cost basis")
+	}
+
+	if walletID > 0 {
+		disposals = walletDisposals(disposals, activity, walletID)
+	}
+
+	report.Years, err = groupByYear(disposals, year, currency)
+	if err != nil {
+		return nil, app_errors.NewValidationError("currency", currency, err.Error())
+	}
+	return report, nil
+}
+
+// activity returns the transactions of every watched wallet of the user, one entry per wallet
+// involved. The whole watchlist is replayed for a single wallet's report too, so that the lots
+// moved into the wallet by a transfer keep their cost basis; only the failure of that wallet's
+// history fails the report, the others are reported as warnings. A history missing transactions
+// fails the report whichever wallet it belongs to, as its disposals would lose their acquisitions.
+func (s *TaxService) activity(ctx context.Context, userID, walletID int) ([]WatchlistTypes.IWatchlistActivity, []string, error) {
+	if walletID > 0 {
+		if _, err := s.watchlistService.GetWallet(userID, walletID); err != nil {
+			return nil, nil, err
+		}
+	}
+	wallets, err := s.watchlistService.ListWallets(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now().Unix()
+	var activity []WatchlistTypes.IWatchlistActivity
+	var warnings []string
+	for i := range wallets {
+		transactions, err := s.watchlistService.GetWalletHistory(ctx, &wallets[i])
+		if err != nil {
+			logger.FromContext(ctx).WithError(err).WithField("watched_wallet_id", wallets[i].ID).Warn("Failed to fetch wallet history for tax report")
+		}
+		if errors.Is(err, WatchlistService.ErrIncompleteHistory) {
+			return nil, nil, app_errors.NewAppError(http.StatusBadGateway, "Wallet history is incomplete", wallets[i].Label)
+		}
+		if err != nil {
+			if wallets[i].ID == walletID {
+				return nil, nil, app_errors.NewUpstreamError("Failed to fetch wallet history", err)
+			}
+			warnings = append(warnings, wallets[i].Label+": history could not be fetched")
+			continue
+		}
+		for _, tx := range transactions {
+			if tx.Time == 0 {
+				tx.Time = now
+			}
+			tx.WalletID = wallets[i].ID
+			activity = append(activity, tx)
+		}
+	}
+	return activity, warnings, nil
+}
+
+// transferLegs merges the entries of a transaction across wallets into its net balance change,
+// so that untagged moves between watched wallets cancel out, except for tagged transfers whose
+// outgoing and incoming legs are kept apart for MatchLots. The result is in chronological order.
+func transferLegs(activity []WatchlistTypes.IWatchlistActivity, transfers map[string]bool) []WatchlistTypes.IWatchlistActivity {
+	var legs []WatchlistTypes.IWatchlistActivity
+	byKey := make(map[string]int)
+	for _, tx := range activity {
+		key := tx.TxID
+		if transfers[strings.ToLower(tx.TxID)] {
+			key += "/" + strconv.Itoa(tx.WalletID)
+		}
+		idx, ok := byKey[key]
+		if !ok {
+			byKey[key] = len(legs)
+			legs = append(legs, tx)
+			continue
+		}
+		merged := &legs[idx]
+		merged.BalanceDiff = math.Round((merged.BalanceDiff+tx.BalanceDiff)*1e8) / 1e8
+		merged.Time = min(merged.Time, tx.Time)
+	}
+
+	sort.SliceStable(legs, func(i, j int) bool {
+		if legs[i].Time != legs[j].Time {
+			return legs[i].Time < legs[j].Time
+		}
+		return legs[i].TxID < legs[j].TxID
+	})
+	return legs
+}
+
+// walletDisposals keeps the disposals of the transactions the wallet spent from
+func walletDisposals(disposals []TaxTypes.IDisposal, activity []WatchlistTypes.IWatchlistActivity, walletID int) []TaxTypes.IDisposal {
+	involved := make(map[string]bool)
+	for _, tx := range activity {
+		if tx.WalletID == walletID && tx.BalanceDiff < 0 {
+			involved[tx.TxID] = true
+		}
+	}
+
+	kept := make([]TaxTypes.IDisposal, 0, len(disposals))
+	for _, d := range disposals {
+		if involved[d.TxID] {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
+
+// groupByYear splits disposals into calendar years with short/long-term totals. The totals
+// add up the rounded amounts of the disposals, so they match the rows of the CSV report.
+func groupByYear(disposals []TaxTypes.IDisposal, only int, currency string) ([]TaxTypes.ITaxYear, error) {
+	zero, err := money.FromMinor(0, currency)
+	if err != nil {
+		return nil, err
+	}
+	none := TaxTypes.IGainTotals{Proceeds: zero, CostBasis: zero, Gain: zero}
+
+	byYear := make(map[int]*TaxTypes.ITaxYear)
+	for _, d := range disposals {
+		y, _ := strconv.Atoi(d.Disposed[:4])
+		if only != 0 && y != only {
+			continue
+		}
+
+		entry, ok := byYear[y]
+		if !ok {
+			entry = &TaxTypes.ITaxYear{Year: y, ShortTerm: none, LongTerm: none, Total: none, Disposals: []TaxTypes.IDisposal{}}
+			byYear[y] = entry
+		}
+		entry.Disposals = append(entry.Disposals, d)
+
+		term := &entry.ShortTerm
+		if d.Term == TermLong {
+			term = &entry.LongTerm
+		}
+		if err := addTotals(term, d); err != nil {
+			return nil, err
+		}
+		if err := addTotals(&entry.Total, d); err != nil {
+			return nil, err
+		}
+	}
+
+	years := make([]TaxTypes.ITaxYear, 0, len(byYear))
+	for _, entry := range byYear {
+		years = append(years, *entry)
+	}
+	sort.Slice(years, func(i, j int) bool { return years[i].Year < years[j].Year })
+	return years, nil
+}
+
+// addTotals adds the amounts of a disposal to totals in the same currency
+func addTotals(t *TaxTypes.IGainTotals, d TaxTypes.IDisposal) error {
+	var err error
+	if t.Proceeds, err = money.FromMinor(t.Proceeds.MinorUnits+d.Proceeds.MinorUnits, t.Proceeds.Currency); err != nil {
+		return err
+	}
+	if t.CostBasis, err = money.FromMinor(t.CostBasis.MinorUnits+d.CostBasis.MinorUnits, t.CostBasis.Currency); err != nil {
+		return err
+	}
+	t.Gain, err = money.FromMinor(t.Gain.MinorUnits+d.Gain.MinorUnits, t.Gain.Currency)
+	return err
+}
+
+// WriteReportCSV writes one row per matched disposal of the report
+func WriteReportCSV(w io.Writer, report *TaxTypes.ITaxReport) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(CSVHeader); err != nil {
+		return err
+	}
+
+	for _, year := range report.Years {
+		for _, d := range year.Disposals {
+			row := []string{
+				strconv.Itoa(year.Year),
+				d.TxID,
+				d.AcquiredTx,
+				d.Acquired,
+				d.Disposed,
+				strconv.FormatFloat(d.Amount, 'f', 8, 64),
+				d.Proceeds.Value,
+				d.CostBasis.Value,
+				d.Gain.Value,
+				d.Term,
+				strconv.Itoa(d.HoldingDays),
+			}
+			if err := writer.Write(row); err != nil {
+				return fmt.Errorf("failed to write CSV row: %w", err)
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,218 @@
+// Package services provides  wallet explorer services for external API interactions.
+package services
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+
+	"cry-api/app/bitcoin"
+	"cry-api/app/logger"
+	UserModel "cry-api/app/models"
+	repositorie "cry-api/app/repositories"
+	app_errors "cry-api/app/types/errors"
+	WalletExplorer "cry-api/app/types/wallet_explorer"
+)
+
+// maxAuditError is the length of the error kept in the audit trail
+const maxAuditError = 512
+
+// BroadcastService validates signed transactions locally and submits them to the configured
+// providers, falling back to the next one when a provider cannot be reached. Every request is
+// recorded in the broadcast audit trail.
+type BroadcastService struct {
+	Providers []Broadcaster
+	Mempool   MempoolServiceInterface
+	Audits    repositorie.BroadcastAuditRepository
+}
+
+// BroadcastServiceInterface defines the methods for the BroadcastService.
+type BroadcastServiceInterface interface {
+	Broadcast(ctx context.Context, userID int, clientIP, rawTx string) (*WalletExplorer.IBroadcastResult, error)
+}
+
+// NewBroadcastService initializes and returns a BroadcastService instance
+func NewBroadcastService(providers []Broadcaster, mempool MempoolServiceInterface, audits repositorie.BroadcastAuditRepository) *BroadcastService {
+	return &BroadcastService{
+		Providers: providers,
+		Mempool:   mempool,
+		Audits:    audits,
+	}
+}
+
+// Broadcast validates a raw transaction hex, broadcasts it and records the outcome
+func (s *BroadcastService) Broadcast(ctx context.Context, userID int, clientIP, rawTx string) (*WalletExplorer.IBroadcastResult, error) {
+	rawTx = strings.ToLower(strings.TrimSpace(rawTx))
+	audit := &UserModel.BroadcastAudit{UserID: userID, RawTx: rawTx, IPAddress: clientIP}
+
+	result, err := s.broadcast(ctx, rawTx, audit)
+	switch err.(type) {
+	case nil:
+		audit.Status = UserModel.BroadcastStatusBroadcast
+	case *app_errors.ValidationError, *app_errors.ConflictError:
+		audit.Status = UserModel.BroadcastStatusRejected
+	default:
+		audit.Status = UserModel.BroadcastStatusFailed
+	}
+	if err != nil && audit.Error == "" {
+		audit.Error = truncate(err.Error(), maxAuditError)
+	}
+
+	// The transaction may already be on the network, so a lost audit record does not fail the request
+	if saveErr := s.Audits.Save(audit); saveErr != nil {
+		logger.FromContext(ctx).WithError(saveErr).WithField("txid", audit.TxID).Error("Failed to record broadcast audit")
+	}
+
+	return result, err
+}
+
+// broadcast runs the local checks and submits the transaction, filling audit on the way
+func (s *BroadcastService) broadcast(ctx context.Context, rawTx string, audit *UserModel.BroadcastAudit) (*WalletExplorer.IBroadcastResult, error) {
+	raw, err := hex.DecodeString(rawTx)
+	if err != nil || len(raw) == 0 {
+		return nil, app_errors.NewValidationError("tx", "", "Transaction must be a raw transaction hex")
+	}
+	if bitcoin.IsPSBT(raw) {
+		return nil, app_errors.NewValidationError("tx", "", "PSBTs must be finalized and extracted before broadcasting")
+	}
+
+	tx, err := bitcoin.ParseTransaction(raw)
+	if err != nil {
+		return nil, app_errors.NewValidationError("tx", "", err.Error())
+	}
+	txid := tx.TxID()
+	audit.TxID = txid
+
+	if tx.IsCoinbase() {
+		return nil, app_errors.NewValidationError("tx", txid, "Coinbase transactions cannot be broadcast")
+	}
+	for i, in := range tx.Inputs {
+		if len(in.ScriptSig) == 0 && len(in.Witness) == 0 {
+			return nil, app_errors.NewValidationError("tx", txid, fmt.Sprintf("Input %d is not signed", i))
+		}
+	}
+
+	if err := s.checkNotConfirmed(ctx, txid); err != nil {
+		return nil, err
+	}
+
+	fee, err := s.fee(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	result := &WalletExplorer.IBroadcastResult{
+		TxID:    txid,
+		Fee:     fee,
+		VSize:   tx.VSize(),
+		FeeRate: math.Round(float64(fee)/float64(tx.VSize())*100) / 100,
+	}
+	audit.FeeRate = result.FeeRate
+
+	fees, err := s.Mempool.GetFeeEstimates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if result.FeeRate < fees.Minimum {
+		return nil, app_errors.NewValidationError("tx", txid, fmt.Sprintf(
+			"Fee rate of %.2f sat/vB is below the minimum relay fee of %.2f sat/vB", result.FeeRate, fees.Minimum))
+	}
+
+	provider, err := s.submit(ctx, rawTx, audit)
+	audit.Provider = provider
+	if err != nil {
+		return nil, err
+	}
+	result.Provider = provider
+	return result, nil
+}
+
+// checkNotConfirmed refuses transactions that are already in a block. Transactions unknown
+// upstream or waiting in the mempool may be (re)broadcast.
+func (s *BroadcastService) checkNotConfirmed(ctx context.Context, txid string) error {
+	status, err := s.Mempool.GetMempoolStatus(ctx, txid)
+	var notFound *app_errors.NotFoundError
+	switch {
+	case errors.As(err, &notFound):
+		return nil
+	case err != nil:
+		return err
+	case status.Status == WalletExplorer.MempoolStatusConfirmed:
+		return app_errors.NewConflictError("transaction", "Transaction is already confirmed")
+	}
+	return nil
+}
+
+// fee returns the fee of tx from the values of the outputs it spends
+func (s *BroadcastService) fee(ctx context.Context, tx *bitcoin.Tx) (int64, error) {
+	spent := make(map[string][]int64)
+	var inputTotal int64
+	for i, in := range tx.Inputs {
+		prevTxID := in.PrevTxIDHex()
+		values, ok := spent[prevTxID]
+		if !ok {
+			var err error
+			values, err = s.Mempool.GetOutputValues(ctx, prevTxID)
+			var notFound *app_errors.NotFoundError
+			if errors.As(err, &notFound) {
+				return 0, app_errors.NewValidationError("tx", tx.TxID(), fmt.Sprintf("Input %d spends unknown transaction %s", i, prevTxID))
+			}
+			if err != nil {
+				return 0, err
+			}
+			spent[prevTxID] = values
+		}
+		if int(in.PrevIndex) >= len(values) {
+			return 0, app_errors.NewValidationError("tx", tx.TxID(), fmt.Sprintf("Input %d spends missing output %s:%d", i, prevTxID, in.PrevIndex))
+		}
+		inputTotal += values[in.PrevIndex]
+	}
+
+	var outputTotal int64
+	for _, out := range tx.Outputs {
+		outputTotal += out.Value
+	}
+	if outputTotal > inputTotal {
+		return 0, app_errors.NewValidationError("tx", tx.TxID(), "Outputs spend more than the inputs")
+	}
+	return inputTotal - outputTotal, nil
+}
+
+// submit tries each provider in turn and returns the name of the one that accepted the
+// transaction. A rejection by a node ends the attempt, unreachable providers are skipped. Their
+// failures are recorded on audit only, as they can quote the providers' responses.
+func (s *BroadcastService) submit(ctx context.Context, rawTx string, audit *UserModel.BroadcastAudit) (string, error) {
+	var failures []string
+	var lastErr error
+	for _, provider := range s.Providers {
+		_, err := provider.Broadcast(ctx, rawTx)
+		if err == nil {
+			return provider.Name(), nil
+		}
+
+		var rejected *BroadcastRejectedError
+		if errors.As(err, &rejected) {
+			return provider.Name(), app_errors.NewValidationError("tx", "", "Transaction rejected by "+rejected.Provider+": "+rejected.Reason)
+		}
+		logger.FromContext(ctx).WithError(err).WithField("provider", provider.Name()).Warn("Broadcast provider failed, trying the next one")
+		failures = append(failures, err.Error())
+		lastErr = err
+	}
+
+	if lastErr == nil {
+		return "", app_errors.NewAppError(http.StatusServiceUnavailable, "No broadcast provider configured", "")
+	}
+	audit.Error = truncate(strings.Join(failures, "; "), maxAuditError)
+	return "", app_errors.NewUpstreamError("Failed to broadcast transaction", lastErr)
+}
+
+// truncate shortens s to at most n bytes
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
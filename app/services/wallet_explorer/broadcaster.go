@@ -0,0 +1,120 @@
+// Package services provides  wallet explorer services for external API interactions.
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"cry-api/app/httpclient"
+	EnvTypes "cry-api/app/types/env"
+)
+
+// Broadcaster submits signed transactions to the network through an upstream API
+type Broadcaster interface {
+	Name() string
+	// Broadcast submits a raw transaction hex and returns the txid reported by the
+	// provider, empty when it does not report one
+	Broadcast(ctx context.Context, rawTx string) (string, error)
+}
+
+// BroadcastRejectedError reports a transaction refused by the node behind a provider. The
+// other providers would refuse it too, so it is not retried elsewhere.
+type BroadcastRejectedError struct {
+	Provider string
+	Reason   string
+}
+
+func (e *BroadcastRejectedError) Error() string {
+	return fmt.Sprintf("%s rejected the transaction: %s", e.Provider, e.Reason)
+}
+
+// NewBroadcasters returns the configured broadcast providers in order of preference
+func NewBroadcasters(cfg *EnvTypes.EnvConfig, client httpclient.Doer) []Broadcaster {
+	var providers []Broadcaster
+	for _, name := range cfg.BroadcastConfig.Providers {
+		switch name {
+		case "mempool":
+			providers = append(providers, &MempoolBroadcaster{API: cfg.MempoolConfig.API, Client: client})
+		case "blockchain":
+			providers = append(providers, &BlockchainBroadcaster{API: cfg.BlockchainConfig.API, Client: client})
+		}
+	}
+	return providers
+}
+
+// MempoolBroadcaster broadcasts through a mempool.space compatible API
+type MempoolBroadcaster struct {
+	API    string
+	Client httpclient.Doer
+}
+
+// Name returns the provider name used in the configuration and the audit trail
+func (b *MempoolBroadcaster) Name() string {
+	return "mempool"
+}
+
+// Broadcast posts the raw transaction as plain text. The API answers with the txid.
+func (b *MempoolBroadcaster) Broadcast(ctx context.Context, rawTx string) (string, error) {
+	body, err := postBroadcast(ctx, b.Client, b.Name(), b.API+"/tx", "text/plain", rawTx)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(body), nil
+}
+
+// BlockchainBroadcaster broadcasts through the blockchain.info pushtx endpoint
+type BlockchainBroadcaster struct {
+	API    string
+	Client httpclient.Doer
+}
+
+// Name returns the provider name used in the configuration and the audit trail
+func (b *BlockchainBroadcaster) Name() string {
+	return "blockchain"
+}
+
+// Broadcast posts the raw transaction as a form. The API only answers "Transaction Submitted".
+func (b *BlockchainBroadcaster) Broadcast(ctx context.Context, rawTx string) (string, error) {
+	form := url.Values{"tx": {rawTx}}
+	if _, err := postBroadcast(ctx, b.Client, b.Name(), b.API+"/pushtx", "application/x-www-form-urlencoded", form.Encode()); err != nil {
+		return "", err
+	}
+	return "", nil
+}
+
+// postBroadcast sends a broadcast request and returns the body of a 200 response. A 400 response
+// means the node refused the transaction.
+func postBroadcast(ctx context.Context, client httpclient.Doer, provider, target, contentType, payload string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, strings.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach %s: %w", provider, err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return string(body), nil
+	case resp.StatusCode == http.StatusBadRequest:
+		return "", &BroadcastRejectedError{Provider: provider, Reason: strings.TrimSpace(string(body))}
+	default:
+		return "", fmt.Errorf("%s returned status %d", provider, resp.StatusCode)
+	}
+}
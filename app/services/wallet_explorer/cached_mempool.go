@@ -0,0 +1,68 @@
+// Package services provides  wallet explorer services for external API interactions.
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"cry-api/app/cache"
+	WalletExplorer "cry-api/app/types/wallet_explorer"
+)
+
+// Cache policies of the mempool lookups. Fee estimates and the tip move with every block,
+// unconfirmed transactions can be confirmed or replaced at any time.
+var (
+	FeeEstimatesPolicy  = cache.Policy{TTL: 30 * time.Second, StaleTTL: 5 * time.Minute}
+	BlockTipPolicy      = cache.Policy{TTL: 30 * time.Second, StaleTTL: 5 * time.Minute}
+	MempoolStatusPolicy = cache.Policy{TTL: 15 * time.Second, StaleTTL: 2 * time.Minute}
+)
+
+// CachedMempoolService caches the lookups of a MempoolServiceInterface
+type CachedMempoolService struct {
+	MempoolServiceInterface
+	cache *cache.Cache
+}
+
+// NewCachedMempoolService wraps upstream with c. A nil cache disables caching.
+func NewCachedMempoolService(upstream MempoolServiceInterface, c *cache.Cache) *CachedMempoolService {
+	return &CachedMempoolService{MempoolServiceInterface: upstream, cache: c}
+}
+
+// GetFeeEstimates returns the cached recommended fee rates
+func (s *CachedMempoolService) GetFeeEstimates(ctx context.Context) (*WalletExplorer.IFeeEstimates, error) {
+	return cache.Fetch(ctx, s.cache, "mempool:fees", cache.Fixed[*WalletExplorer.IFeeEstimates](FeeEstimatesPolicy), func(ctx context.Context) (*WalletExplorer.IFeeEstimates, error) {
+		return s.MempoolServiceInterface.GetFeeEstimates(ctx)
+	})
+}
+
+// GetTip returns the cached chain tip
+func (s *CachedMempoolService) GetTip(ctx context.Context) (*WalletExplorer.IBlockTip, error) {
+	return cache.Fetch(ctx, s.cache, "mempool:tip", cache.Fixed[*WalletExplorer.IBlockTip](BlockTipPolicy), func(ctx context.Context) (*WalletExplorer.IBlockTip, error) {
+		return s.MempoolServiceInterface.GetTip(ctx)
+	})
+}
+
+// GetMempoolStatus returns the cached status of a transaction, kept longer once confirmed
+func (s *CachedMempoolService) GetMempoolStatus(ctx context.Context, txid string) (*WalletExplorer.IMempoolStatus, error) {
+	return cache.Fetch(ctx, s.cache, "mempool:status:"+strings.ToLower(strings.TrimSpace(txid)), mempoolStatusPolicy, func(ctx context.Context) (*WalletExplorer.IMempoolStatus, error) {
+		return s.MempoolServiceInterface.GetMempoolStatus(ctx, txid)
+	})
+}
+
+// GetOutputValues returns the cached output values of a transaction. A txid commits to its
+// outputs, so they never change.
+func (s *CachedMempoolService) GetOutputValues(ctx context.Context, txid string) ([]int64, error) {
+	return cache.Fetch(ctx, s.cache, "mempool:outputs:"+strings.ToLower(strings.TrimSpace(txid)), cache.Fixed[[]int64](ImmutableTxPolicy), func(ctx context.Context) ([]int64, error) {
+		return s.MempoolServiceInterface.GetOutputValues(ctx, txid)
+	})
+}
+
+// mempoolStatusPolicy keeps pending and replaced transactions briefly. Confirmed ones are
+// refreshed every minute so their confirmation count follows the chain.
+func mempoolStatusPolicy(status *WalletExplorer.IMempoolStatus) cache.Policy {
+	if status != nil && status.Status == WalletExplorer.MempoolStatusConfirmed {
+		return ConfirmedTxPolicy
+	}
+	return MempoolStatusPolicy
+}
@@ -0,0 +1,97 @@
+// Package services provides  wallet explorer services for external API interactions.
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"cry-api/app/bitcoin"
+	"cry-api/app/cache"
+	app_errors "cry-api/app/types/errors"
+	WalletExplorer "cry-api/app/types/wallet_explorer"
+)
+
+// ImmutableConfirmations is the depth after which a transaction is cached as immutable
+const ImmutableConfirmations = 6
+
+// Cache policies of the wallet explorer lookups
+var (
+	UnconfirmedTxPolicy = cache.Policy{TTL: 30 * time.Second, StaleTTL: 5 * time.Minute}
+	ConfirmedTxPolicy   = cache.Policy{TTL: time.Minute, StaleTTL: time.Hour}
+	ImmutableTxPolicy   = cache.Policy{TTL: 30 * 24 * time.Hour, StaleTTL: 30 * 24 * time.Hour}
+	HistoryPolicy       = cache.Policy{TTL: 2 * time.Minute, StaleTTL: time.Hour}
+	BlockHeightPolicy   = cache.Policy{TTL: time.Minute, StaleTTL: 10 * time.Minute}
+)
+
+// CachedTransactionService caches the lookups of a TransactionServiceInterface. Transactions
+// are kept until they are deeply confirmed, after which they never change. Streaming address
+// history is passed through uncached. Txids, addresses and xpubs are validated before the
+// cache lookup, so malformed input never reaches the cache or upstream.
+type CachedTransactionService struct {
+	TransactionServiceInterface
+	cache *cache.Cache
+}
+
+// NewCachedTransactionService wraps upstream with c. A nil cache disables caching.
+func NewCachedTransactionService(upstream TransactionServiceInterface, c *cache.Cache) *CachedTransactionService {
+	return &CachedTransactionService{TransactionServiceInterface: upstream, cache: c}
+}
+
+// GetTransactionByTxID returns a cached transaction, refreshed until it has ImmutableConfirmations
+func (s *CachedTransactionService) GetTransactionByTxID(ctx context.Context, txid string) (*WalletExplorer.ITransactionData, error) {
+	txid = strings.ToLower(strings.TrimSpace(txid))
+	if !txidPattern.MatchString(txid) {
+		return nil, app_errors.NewValidationError("txid", txid, "Transaction id must be 64 hex characters")
+	}
+
+	return cache.Fetch(ctx, s.cache, "blockchain:tx:"+txid, func(tx *WalletExplorer.ITransactionData) cache.Policy {
+		return s.txPolicy(ctx, tx)
+	}, func(ctx context.Context) (*WalletExplorer.ITransactionData, error) {
+		return s.TransactionServiceInterface.GetTransactionByTxID(ctx, txid)
+	})
+}
+
+// GetTransactionByXPUB returns the cached history of an extended public key
+func (s *CachedTransactionService) GetTransactionByXPUB(ctx context.Context, xpub string) (*WalletExplorer.ITransactionXPUB, error) {
+	xpub = strings.TrimSpace(xpub)
+	if _, err := bitcoin.ParseExtendedKey(xpub); err != nil {
+		return nil, app_errors.NewValidationError("xpub", xpub, "Invalid extended public key")
+	}
+
+	return cache.Fetch(ctx, s.cache, "walletexplorer:xpub:"+xpub, cache.Fixed[*WalletExplorer.ITransactionXPUB](HistoryPolicy), func(ctx context.Context) (*WalletExplorer.ITransactionXPUB, error) {
+		return s.TransactionServiceInterface.GetTransactionByXPUB(ctx, xpub)
+	})
+}
+
+// GetTransactionByAddress returns the cached history of an address
+func (s *CachedTransactionService) GetTransactionByAddress(ctx context.Context, address string) (*WalletExplorer.ITransactionAddress, error) {
+	address = strings.TrimSpace(address)
+	if _, _, err := bitcoin.ScriptFromAddress(address); err != nil {
+		return nil, app_errors.NewValidationError("address", address, "Invalid Bitcoin address")
+	}
+
+	return cache.Fetch(ctx, s.cache, "blockchain:addr:"+address, cache.Fixed[*WalletExplorer.ITransactionAddress](HistoryPolicy), func(ctx context.Context) (*WalletExplorer.ITransactionAddress, error) {
+		return s.TransactionServiceInterface.GetTransactionByAddress(ctx, address)
+	})
+}
+
+// GetBlockHeight returns the cached height of the chain tip
+func (s *CachedTransactionService) GetBlockHeight(ctx context.Context) (int, error) {
+	return cache.Fetch(ctx, s.cache, "blockchain:tip", cache.Fixed[int](BlockHeightPolicy), func(ctx context.Context) (int, error) {
+		return s.TransactionServiceInterface.GetBlockHeight(ctx)
+	})
+}
+
+// txPolicy keeps unconfirmed and shallow transactions briefly, and deep ones for good
+func (s *CachedTransactionService) txPolicy(ctx context.Context, tx *WalletExplorer.ITransactionData) cache.Policy {
+	if tx == nil || tx.BlockHeight <= 0 {
+		return UnconfirmedTxPolicy
+	}
+
+	tip, err := s.GetBlockHeight(ctx)
+	if err != nil || tip-tx.BlockHeight+1 < ImmutableConfirmations {
+		return ConfirmedTxPolicy
+	}
+	return ImmutableTxPolicy
+}
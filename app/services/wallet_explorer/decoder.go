@@ -0,0 +1,442 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+
+	"cry-api/app/bitcoin"
+	WalletExplorer "cry-api/app/types/wallet_explorer"
+)
+
+// Signature states reported per decoded input.
+const (
+	SignatureStatusFinalized = "finalized"
+	SignatureStatusSigned    = "signed"
+	SignatureStatusPartial   = "partial"
+	SignatureStatusUnsigned  = "unsigned"
+	SignatureStatusUnknown   = "unknown"
+)
+
+// ErrUndecodablePayload is returned when a payload is neither a raw transaction nor a PSBT.
+var ErrUndecodablePayload = errors.New("payload is neither a raw transaction hex nor a PSBT")
+
+// DecoderService decodes raw transactions and PSBTs locally, without any upstream call.
+type DecoderService struct{}
+
+// DecoderServiceInterface defines the methods for the DecoderService.
+type DecoderServiceInterface interface {
+	DecodeTransaction(payload, network string) (*WalletExplorer.IDecodedTransaction, error)
+}
+
+// NewDecoderService initializes and returns a DecoderService instance
+func NewDecoderService() *DecoderService {
+	return &DecoderService{}
+}
+
+// DecodeTransaction decodes a raw transaction (hex) or a PSBT (base64 or hex, BIP174/BIP370).
+func (s *DecoderService) DecodeTransaction(payload, network string) (*WalletExplorer.IDecodedTransaction, error) {
+	net := bitcoin.MainNet
+	if network == bitcoin.TestNet.Name {
+		net = bitcoin.TestNet
+	}
+
+	raw, err := decodePayload(strings.TrimSpace(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	if bitcoin.IsPSBT(raw) {
+		psbt, err := bitcoin.ParsePSBT(raw)
+		if err != nil {
+			return nil, err
+		}
+		return decodePSBT(psbt, net), nil
+	}
+
+	tx, err := bitcoin.ParseTransaction(raw)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRawTransaction(tx, net), nil
+}
+
+func decodePayload(payload string) ([]byte, error) {
+	if raw, err := hex.DecodeString(payload); err == nil && len(raw) > 0 {
+		return raw, nil
+	}
+	if raw, err := base64.StdEncoding.DecodeString(payload); err == nil && bitcoin.IsPSBT(raw) {
+		return raw, nil
+	}
+	return nil, ErrUndecodablePayload
+}
+
+func decodeRawTransaction(tx *bitcoin.Tx, net *bitcoin.Network) *WalletExplorer.IDecodedTransaction {
+	result := newDecodedTransaction(tx, net, "raw")
+
+	complete := true
+	for i, in := range tx.Inputs {
+		decoded := &result.Inputs[i]
+		sigs := collectSignatures(in.ScriptSig, in.Witness)
+		decoded.Signatures = len(sigs)
+		for _, sig := range sigs {
+			decoded.Sighash = appendUnique(decoded.Sighash, bitcoin.SighashName(bitcoin.SignatureHashType(sig)))
+		}
+
+		// Without the spent outputs we can only tell whether unlocking data is present
+		if len(in.ScriptSig) > 0 || len(in.Witness) > 0 || tx.IsCoinbase() {
+			decoded.SignatureStatus = SignatureStatusSigned
+		} else {
+			decoded.SignatureStatus = SignatureStatusUnsigned
+			complete = false
+		}
+	}
+	result.Complete = complete
+	AnalyzeTransaction(&result.Transaction)
+	return result
+}
+
+func decodePSBT(psbt *bitcoin.PSBT, net *bitcoin.Network) *WalletExplorer.IDecodedTransaction {
+	result := newDecodedTransaction(psbt.Tx, net, "psbt")
+	version := int(psbt.Version)
+	result.PSBTVersion = &version
+
+	complete := true
+	inputTotal, haveAllValues := int64(0), true
+	for i, in := range psbt.Inputs {
+		decoded := &result.Inputs[i]
+		txInput := &result.Transaction.Inputs[i]
+
+		spent := psbt.SpentOutput(i)
+		var class bitcoin.ScriptClass
+		if spent != nil {
+			value := spent.Value
+			inputTotal += value
+			decoded.Value = &value
+			class = bitcoin.ClassifyScript(spent.Script)
+			decoded.ScriptType = string(class.Type)
+			if address, err := bitcoin.AddressFromScript(spent.Script, net); err == nil {
+				decoded.Address = &address
+			}
+
+			n := decoded.PrevVout
+			txInput.PrevOut = &WalletExplorer.PrevOut{
+				Value:             value,
+				N:                 n,
+				Script:            hex.EncodeToString(spent.Script),
+				SpendingOutpoints: []WalletExplorer.Outpoint{},
+			}
+		} else {
+			haveAllValues = false
+		}
+
+		if in.IsFinalized() {
+			txInput.Script = hex.EncodeToString(in.FinalScriptSig)
+			if in.FinalScriptWitness != nil {
+				witness := hex.EncodeToString(bitcoin.SerializeWitness(in.FinalScriptWitness))
+				txInput.Witness = &witness
+			}
+		}
+
+		var sigs [][]byte
+		if in.IsFinalized() {
+			sigs = collectSignatures(in.FinalScriptSig, in.FinalScriptWitness)
+		} else {
+			pubKeys := make([]string, 0, len(in.PartialSigs))
+			for pubKey := range in.PartialSigs {
+				pubKeys = append(pubKeys, pubKey)
+			}
+			sort.Strings(pubKeys)
+			for _, pubKey := range pubKeys {
+				sigs = append(sigs, in.PartialSigs[pubKey])
+			}
+			if in.TapKeySig != nil {
+				sigs = append(sigs, in.TapKeySig)
+			}
+			sigs = append(sigs, in.TapScriptSigs...)
+		}
+		decoded.Signatures = len(sigs)
+
+		if in.SighashType != nil {
+			decoded.Sighash = append(decoded.Sighash, bitcoin.SighashName(byte(*in.SighashType)))
+		}
+		for _, sig := range sigs {
+			if len(sig) > 0 {
+				decoded.Sighash = appendUnique(decoded.Sighash, bitcoin.SighashName(bitcoin.SignatureHashType(sig)))
+			}
+		}
+
+		decoded.RequiredSignatures = requiredSignatures(class, in)
+		switch {
+		case in.IsFinalized():
+			decoded.SignatureStatus = SignatureStatusFinalized
+		case decoded.RequiredSignatures == 0:
+			decoded.SignatureStatus = SignatureStatusUnknown
+		case decoded.Signatures >= decoded.RequiredSignatures:
+			decoded.SignatureStatus = SignatureStatusSigned
+		case decoded.Signatures > 0:
+			decoded.SignatureStatus = SignatureStatusPartial
+		default:
+			decoded.SignatureStatus = SignatureStatusUnsigned
+		}
+		if decoded.RequiredSignatures > decoded.Signatures && !in.IsFinalized() {
+			decoded.MissingSignatures = decoded.RequiredSignatures - decoded.Signatures
+		}
+		if decoded.SignatureStatus != SignatureStatusFinalized && decoded.SignatureStatus != SignatureStatusSigned {
+			complete = false
+		}
+	}
+	result.Complete = complete
+
+	if haveAllValues && len(psbt.Inputs) > 0 {
+		outputTotal := int64(0)
+		for _, out := range psbt.Tx.Outputs {
+			outputTotal += out.Value
+		}
+		fee := inputTotal - outputTotal
+		result.Transaction.Fee = &fee
+
+		// The unsigned transaction has no unlocking data, so its vsize understates the fee rate
+		// of the transaction that will be broadcast
+		vsize := result.VSize
+		if estimated, ok := signedVSize(psbt); ok {
+			result.EstimatedVSize = &estimated
+			vsize = estimated
+		} else {
+			result.FeeRateUpperBound = true
+		}
+		rate := math.Round(float64(fee)/float64(vsize)*100) / 100
+		result.FeeRate = &rate
+	}
+	AnalyzeTransaction(&result.Transaction)
+	return result
+}
+
+// Sizes of the placeholder unlocking data of inputs that are not finalized yet: a DER signature
+// with its sighash byte at its usual maximum, a Schnorr signature with the default sighash and a
+// compressed public key.
+const (
+	estimatedECDSASize   = 72
+	estimatedSchnorrSize = 64
+	estimatedPubKeySize  = 33
+)
+
+// signedVSize estimates the vsize of the PSBT once every input is finalized, from the final
+// scripts of the finalized inputs and the script type of the others. It reports false when the
+// unlocking data of an input cannot be predicted, such as a script it does not recognize.
+func signedVSize(psbt *bitcoin.PSBT) (int, bool) {
+	signed := *psbt.Tx
+	signed.Inputs = make([]*bitcoin.TxIn, len(psbt.Tx.Inputs))
+	for i, in := range psbt.Tx.Inputs {
+		scriptSig, witness, ok := unlockingData(psbt, i)
+		if !ok {
+			return 0, false
+		}
+		input := *in
+		input.ScriptSig, input.Witness = scriptSig, witness
+		signed.Inputs[i] = &input
+	}
+	return signed.VSize(), true
+}
+
+// unlockingData returns the scriptSig and witness input i will carry once finalized, with
+// placeholder signatures and keys of the expected sizes
+func unlockingData(psbt *bitcoin.PSBT, i int) ([]byte, [][]byte, bool) {
+	in := psbt.Inputs[i]
+	if in.IsFinalized() {
+		return in.FinalScriptSig, in.FinalScriptWitness, true
+	}
+	spent := psbt.SpentOutput(i)
+	if spent == nil {
+		return nil, nil, false
+	}
+
+	sig, pubKey := make([]byte, estimatedECDSASize), make([]byte, estimatedPubKeySize)
+	switch bitcoin.ClassifyScript(spent.Script).Type {
+	case bitcoin.ScriptP2PK:
+		return appendPush(nil, sig), nil, true
+	case bitcoin.ScriptP2PKH:
+		return appendPush(appendPush(nil, sig), pubKey), nil, true
+	case bitcoin.ScriptP2WPKH:
+		return nil, [][]byte{sig, pubKey}, true
+	case bitcoin.ScriptP2TR:
+		return nil, [][]byte{make([]byte, estimatedSchnorrSize)}, true
+	case bitcoin.ScriptP2WSH:
+		witness, ok := multisigWitness(in.WitnessScript)
+		return nil, witness, ok
+	case bitcoin.ScriptP2SH:
+		redeem := bitcoin.ClassifyScript(in.RedeemScript)
+		switch redeem.Type {
+		case bitcoin.ScriptP2WPKH:
+			return appendPush(nil, in.RedeemScript), [][]byte{sig, pubKey}, true
+		case bitcoin.ScriptP2WSH:
+			witness, ok := multisigWitness(in.WitnessScript)
+			return appendPush(nil, in.RedeemScript), witness, ok
+		case bitcoin.ScriptMultisig:
+			// OP_0, the signatures, then the redeem script
+			scriptSig := []byte{bitcoin.Op0}
+			for range redeem.RequiredSigs {
+				scriptSig = appendPush(scriptSig, sig)
+			}
+			return appendPush(scriptSig, in.RedeemScript), nil, true
+		}
+	}
+	return nil, nil, false
+}
+
+// multisigWitness returns the witness spending a multisig witness script: an empty item, the
+// signatures and the script
+func multisigWitness(witnessScript []byte) ([][]byte, bool) {
+	class := bitcoin.ClassifyScript(witnessScript)
+	if class.Type != bitcoin.ScriptMultisig {
+		return nil, false
+	}
+	witness := [][]byte{{}}
+	for range class.RequiredSigs {
+		witness = append(witness, make([]byte, estimatedECDSASize))
+	}
+	return append(witness, witnessScript), true
+}
+
+// appendPush appends the minimal push of data to script
+func appendPush(script, data []byte) []byte {
+	switch n := len(data); {
+	case n < 0x4c:
+		script = append(script, byte(n))
+	case n <= 0xff:
+		script = append(script, 0x4c, byte(n))
+	default:
+		script = append(script, 0x4d, byte(n), byte(n>>8))
+	}
+	return append(script, data...)
+}
+
+// newDecodedTransaction fills the parts of the result that only depend on the transaction itself.
+func newDecodedTransaction(tx *bitcoin.Tx, net *bitcoin.Network, format string) *WalletExplorer.IDecodedTransaction {
+	weight := tx.Weight()
+	result := &WalletExplorer.IDecodedTransaction{
+		Format:  format,
+		Network: net.Name,
+		TxID:    tx.TxID(),
+		WTxID:   tx.WTxID(),
+		VSize:   tx.VSize(),
+		Transaction: WalletExplorer.ITransactionData{
+			Hash:     tx.TxID(),
+			Ver:      int(tx.Version),
+			VinSz:    len(tx.Inputs),
+			VoutSz:   len(tx.Outputs),
+			LockTime: tx.LockTime,
+			Size:     tx.Size(),
+			Weight:   &weight,
+			Inputs:   make([]WalletExplorer.Input, len(tx.Inputs)),
+			Out:      make([]WalletExplorer.Output, len(tx.Outputs)),
+		},
+		Inputs:  make([]WalletExplorer.IDecodedInput, len(tx.Inputs)),
+		Outputs: make([]WalletExplorer.IDecodedOutput, len(tx.Outputs)),
+	}
+
+	for i, in := range tx.Inputs {
+		sequence := int64(in.Sequence)
+		index := i
+		input := WalletExplorer.Input{
+			Sequence: &sequence,
+			Script:   hex.EncodeToString(in.ScriptSig),
+			Index:    &index,
+		}
+		if len(in.Witness) > 0 {
+			witness := hex.EncodeToString(bitcoin.SerializeWitness(in.Witness))
+			input.Witness = &witness
+		}
+		result.Transaction.Inputs[i] = input
+		result.Inputs[i] = WalletExplorer.IDecodedInput{
+			Index:    i,
+			PrevTxID: in.PrevTxIDHex(),
+			PrevVout: in.PrevIndex,
+			Sighash:  []string{},
+		}
+	}
+
+	for i, out := range tx.Outputs {
+		n := i
+		spent := false
+		output := WalletExplorer.Output{
+			Value:  out.Value,
+			N:      &n,
+			Spent:  &spent,
+			Script: hex.EncodeToString(out.Script),
+		}
+		decoded := WalletExplorer.IDecodedOutput{
+			Index:      i,
+			Value:      out.Value,
+			ScriptType: string(bitcoin.ClassifyScript(out.Script).Type),
+		}
+		if address, err := bitcoin.AddressFromScript(out.Script, net); err == nil {
+			output.Addr = &address
+			decoded.Address = &address
+		}
+		result.Transaction.Out[i] = output
+		result.Outputs[i] = decoded
+	}
+	return result
+}
+
+// requiredSignatures works out how many signatures an unfinalized PSBT input needs,
+// looking through P2SH/P2WSH wrappers to the redeem or witness script.
+func requiredSignatures(class bitcoin.ScriptClass, in *bitcoin.PSBTInput) int {
+	switch class.Type {
+	case bitcoin.ScriptP2SH:
+		if in.RedeemScript == nil {
+			return 0
+		}
+		inner := bitcoin.ClassifyScript(in.RedeemScript)
+		if inner.Type == bitcoin.ScriptP2WSH {
+			return witnessScriptSignatures(in.WitnessScript)
+		}
+		return inner.RequiredSigs
+	case bitcoin.ScriptP2WSH:
+		return witnessScriptSignatures(in.WitnessScript)
+	default:
+		return class.RequiredSigs
+	}
+}
+
+func witnessScriptSignatures(script []byte) int {
+	if script == nil {
+		return 0
+	}
+	return bitcoin.ClassifyScript(script).RequiredSigs
+}
+
+// collectSignatures extracts signature-shaped pushes from unlocking data.
+func collectSignatures(scriptSig []byte, witness [][]byte) [][]byte {
+	var sigs [][]byte
+	ops, _ := bitcoin.ParseScript(scriptSig)
+	for _, op := range ops {
+		if bitcoin.IsSignature(op.Data, false) {
+			sigs = append(sigs, op.Data)
+		}
+	}
+
+	// A lone 64/65-byte witness element is a taproot key-path signature
+	if len(witness) == 1 && bitcoin.IsSignature(witness[0], true) {
+		return append(sigs, witness[0])
+	}
+	for _, item := range witness {
+		if bitcoin.IsSignature(item, false) {
+			sigs = append(sigs, item)
+		}
+	}
+	return sigs
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
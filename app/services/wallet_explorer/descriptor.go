@@ -0,0 +1,114 @@
+package services
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"cry-api/app/bitcoin"
+	app_errors "cry-api/app/types/errors"
+	WalletExplorer "cry-api/app/types/wallet_explorer"
+)
+
+const (
+	// DefaultDerivedAddressCount is the number of addresses derived when none is requested
+	DefaultDerivedAddressCount = 10
+	// MaxDerivedAddressCount caps the number of addresses derived per request
+	MaxDerivedAddressCount = 100
+	// MaxDerivedKeyCount caps the number of public keys derived per request, the keys of a
+	// descriptor times the number of addresses
+	MaxDerivedKeyCount = 500
+)
+
+// DescriptorService parses extended keys and output descriptors locally.
+type DescriptorService struct{}
+
+// DescriptorServiceInterface defines the methods for the DescriptorService.
+type DescriptorServiceInterface interface {
+	NormalizeDescriptor(input string, count int) (*WalletExplorer.INormalizedDescriptor, error)
+	ConvertExtendedKey(key, prefix string) (*WalletExplorer.IConvertedExtendedKey, error)
+}
+
+// NewDescriptorService initializes and returns a DescriptorService instance
+func NewDescriptorService() *DescriptorService {
+	return &DescriptorService{}
+}
+
+// NormalizeDescriptor accepts an xpub/ypub/zpub (or testnet equivalent) or an output
+// descriptor and returns its canonical descriptor plus the first count derived addresses.
+// A count above MaxDerivedAddressCount, or deriving more than MaxDerivedKeyCount keys, is
+// rejected with a validation error on count.
+func (s *DescriptorService) NormalizeDescriptor(input string, count int) (*WalletExplorer.INormalizedDescriptor, error) {
+	input = strings.TrimSpace(input)
+	if count < 1 {
+		count = DefaultDerivedAddressCount
+	}
+	if count > MaxDerivedAddressCount {
+		return nil, app_errors.NewValidationError("count", strconv.Itoa(count), fmt.Sprintf("Count must be at most %d", MaxDerivedAddressCount))
+	}
+
+	result := &WalletExplorer.INormalizedDescriptor{}
+
+	var desc *bitcoin.Descriptor
+	var err error
+	if strings.Contains(input, "(") {
+		result.InputType = "descriptor"
+		desc, err = bitcoin.ParseDescriptor(input)
+	} else {
+		result.InputType = "extended_key"
+		desc, err = bitcoin.DescriptorFromExtendedKey(input, false)
+		if err == nil {
+			change, _ := bitcoin.DescriptorFromExtendedKey(input, true)
+			changeDescriptor := change.StringWithChecksum()
+			result.ChangeDescriptor = &changeDescriptor
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.Descriptor = desc.StringWithChecksum()
+	result.ScriptType = string(desc.ScriptType)
+	result.Network = desc.Network.Name
+	result.IsRange = desc.IsRange()
+
+	if !result.IsRange {
+		count = 1
+	}
+	if len(desc.Keys)*count > MaxDerivedKeyCount {
+		return nil, app_errors.NewValidationError("count", strconv.Itoa(count),
+			fmt.Sprintf("Count must be at most %d for a descriptor with %d keys", MaxDerivedKeyCount/len(desc.Keys), len(desc.Keys)))
+	}
+	result.Addresses = make([]WalletExplorer.IDerivedAddress, 0, count)
+	for i := 0; i < count; i++ {
+		script, err := desc.ScriptAt(uint32(i))
+		if err != nil {
+			return nil, err
+		}
+		address, err := bitcoin.AddressFromScript(script, desc.Network)
+		if err != nil {
+			return nil, err
+		}
+		result.Addresses = append(result.Addresses, WalletExplorer.IDerivedAddress{
+			Index:        uint32(i),
+			Address:      address,
+			ScriptPubKey: hex.EncodeToString(script),
+		})
+	}
+
+	return result, nil
+}
+
+// ConvertExtendedKey re-encodes an extended public key with the SLIP-132 version bytes of prefix.
+func (s *DescriptorService) ConvertExtendedKey(key, prefix string) (*WalletExplorer.IConvertedExtendedKey, error) {
+	output, err := bitcoin.ConvertExtendedKey(key, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return &WalletExplorer.IConvertedExtendedKey{
+		Input:  strings.TrimSpace(key),
+		Prefix: prefix,
+		Output: output,
+	}, nil
+}
@@ -0,0 +1,201 @@
+// Package services provides  wallet explorer services for external API interactions.
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"cry-api/app/logger"
+	app_errors "cry-api/app/types/errors"
+	WalletExplorer "cry-api/app/types/wallet_explorer"
+)
+
+// rbfSequenceLimit is the first sequence number that does not signal BIP125 replaceability
+const rbfSequenceLimit = 0xfffffffe
+
+var txidPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// MempoolService reads fee estimates, the chain tip and unconfirmed transactions from the
+// configured providers, falling back to the next one when a provider cannot be reached.
+type MempoolService struct {
+	Providers []MempoolProvider
+}
+
+// MempoolServiceInterface defines the methods for the MempoolService.
+type MempoolServiceInterface interface {
+	GetFeeEstimates(ctx context.Context) (*WalletExplorer.IFeeEstimates, error)
+	GetTip(ctx context.Context) (*WalletExplorer.IBlockTip, error)
+	GetMempoolStatus(ctx context.Context, txid string) (*WalletExplorer.IMempoolStatus, error)
+	GetOutputValues(ctx context.Context, txid string) ([]int64, error)
+}
+
+// NewMempoolService initializes and returns a MempoolService instance
+func NewMempoolService(providers []MempoolProvider) *MempoolService {
+	return &MempoolService{
+		Providers: providers,
+	}
+}
+
+// GetFeeEstimates fetches the recommended fee rates
+func (s *MempoolService) GetFeeEstimates(ctx context.Context) (*WalletExplorer.IFeeEstimates, error) {
+	var fees *WalletExplorer.IFeeEstimates
+	err := s.failover(ctx, nil, func(provider MempoolProvider) (err error) {
+		fees, err = provider.FeeEstimates(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fees, nil
+}
+
+// GetTip fetches the height and hash of the block at the tip of the chain
+func (s *MempoolService) GetTip(ctx context.Context) (*WalletExplorer.IBlockTip, error) {
+	tip := &WalletExplorer.IBlockTip{}
+	err := s.failover(ctx, nil, func(provider MempoolProvider) (err error) {
+		if tip.Height, err = provider.TipHeight(ctx); err != nil {
+			return err
+		}
+		tip.Hash, err = provider.TipHash(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tip, nil
+}
+
+// GetMempoolStatus reports whether a transaction is confirmed, waiting in the mempool or was
+// replaced by another one (RBF). Transactions already evicted after a replacement are still
+// reported as replaced while the upstream remembers the replacement.
+func (s *MempoolService) GetMempoolStatus(ctx context.Context, txid string) (*WalletExplorer.IMempoolStatus, error) {
+	txid = strings.ToLower(strings.TrimSpace(txid))
+	if !txidPattern.MatchString(txid) {
+		return nil, app_errors.NewValidationError("txid", txid, "Transaction id must be 64 hex characters")
+	}
+
+	var status *WalletExplorer.IMempoolStatus
+	err := s.failover(ctx, errTransactionNotFound(), func(provider MempoolProvider) (err error) {
+		status, err = mempoolStatus(ctx, provider, txid)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// GetOutputValues fetches the value in sats of every output of a transaction, confirmed or not
+func (s *MempoolService) GetOutputValues(ctx context.Context, txid string) ([]int64, error) {
+	txid = strings.ToLower(strings.TrimSpace(txid))
+	if !txidPattern.MatchString(txid) {
+		return nil, app_errors.NewValidationError("txid", txid, "Transaction id must be 64 hex characters")
+	}
+
+	var tx *WalletExplorer.MempoolTransaction
+	err := s.failover(ctx, errTransactionNotFound(), func(provider MempoolProvider) (err error) {
+		tx, err = provider.Transaction(ctx, txid)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]int64, len(tx.Vout))
+	for i, out := range tx.Vout {
+		values[i] = out.Value
+	}
+	return values, nil
+}
+
+// errTransactionNotFound reports a transaction none of the providers know
+func errTransactionNotFound() error {
+	return app_errors.NewNotFoundError("transaction", "Transaction not found in the mempool or the chain")
+}
+
+// failover runs lookup against each provider in turn until one answers. When notFound is set,
+// a transaction the provider does not know is an answer: notFound is returned and the other
+// providers are not asked.
+func (s *MempoolService) failover(ctx context.Context, notFound error, lookup func(provider MempoolProvider) error) error {
+	var lastErr error
+	for _, provider := range s.Providers {
+		err := lookup(provider)
+		if err == nil {
+			return nil
+		}
+		if notFound != nil && errors.Is(err, errUpstreamNotFound) {
+			return notFound
+		}
+		logger.FromContext(ctx).WithError(err).WithField("provider", provider.Name()).Warn("Mempool provider failed, trying the next one")
+		lastErr = err
+	}
+
+	if lastErr == nil {
+		return app_errors.NewAppError(http.StatusServiceUnavailable, "No mempool provider configured", "")
+	}
+	return app_errors.NewUpstreamError("Failed to fetch mempool data", lastErr)
+}
+
+// mempoolStatus builds the status of txid from a single provider, so the transaction, the
+// tip and the replacements come from the same view of the chain
+func mempoolStatus(ctx context.Context, provider MempoolProvider, txid string) (*WalletExplorer.IMempoolStatus, error) {
+	tx, err := provider.Transaction(ctx, txid)
+	if err != nil && !errors.Is(err, errUpstreamNotFound) {
+		return nil, err
+	}
+
+	status := &WalletExplorer.IMempoolStatus{TxID: txid, Status: WalletExplorer.MempoolStatusPending}
+
+	if tx != nil && tx.Status.Confirmed {
+		tip, err := provider.TipHeight(ctx)
+		if err != nil {
+			return nil, err
+		}
+		status.Status = WalletExplorer.MempoolStatusConfirmed
+		status.Confirmations = tip - tx.Status.BlockHeight + 1
+		status.BlockHeight = tx.Status.BlockHeight
+		status.BlockHash = tx.Status.BlockHash
+		status.BlockTime = tx.Status.BlockTime
+		fillFees(status, tx)
+		return status, nil
+	}
+
+	rbf, err := provider.Replacements(ctx, txid)
+	if err != nil {
+		return nil, err
+	}
+	if rbf.Replacements != nil && rbf.Replacements.Tx.TxID != "" && rbf.Replacements.Tx.TxID != txid {
+		status.Status = WalletExplorer.MempoolStatusReplaced
+		status.ReplacedBy = rbf.Replacements.Tx.TxID
+	}
+	status.Replaces = rbf.Replaces
+
+	if tx == nil {
+		if status.ReplacedBy == "" {
+			return nil, errUpstreamNotFound
+		}
+		return status, nil
+	}
+
+	fillFees(status, tx)
+	return status, nil
+}
+
+// fillFees copies the fee, size and RBF signalling of tx into status
+func fillFees(status *WalletExplorer.IMempoolStatus, tx *WalletExplorer.MempoolTransaction) {
+	status.Fee = tx.Fee
+	status.VSize = math.Ceil(float64(tx.Weight) / 4)
+	if status.VSize > 0 {
+		status.FeeRate = math.Round(float64(tx.Fee)/status.VSize*100) / 100
+	}
+	for _, in := range tx.Vin {
+		if in.Sequence < rbfSequenceLimit {
+			status.SignalsRBF = true
+			break
+		}
+	}
+}
@@ -0,0 +1,214 @@
+// Package services provides  wallet explorer services for external API interactions.
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"cry-api/app/httpclient"
+	EnvTypes "cry-api/app/types/env"
+	WalletExplorer "cry-api/app/types/wallet_explorer"
+)
+
+// errUpstreamNotFound is returned by a provider when the upstream answers 404
+var errUpstreamNotFound = errors.New("not found upstream")
+
+// MempoolProvider reads fee estimates, the chain tip and transactions from an upstream API.
+// Lookups of an unknown transaction return errUpstreamNotFound.
+type MempoolProvider interface {
+	Name() string
+	FeeEstimates(ctx context.Context) (*WalletExplorer.IFeeEstimates, error)
+	TipHeight(ctx context.Context) (int, error)
+	TipHash(ctx context.Context) (string, error)
+	Transaction(ctx context.Context, txid string) (*WalletExplorer.MempoolTransaction, error)
+	// Replacements returns the RBF history of a transaction, empty when the provider does
+	// not track replacements
+	Replacements(ctx context.Context, txid string) (*WalletExplorer.MempoolRBFHistory, error)
+}
+
+// NewMempoolProviders returns the configured mempool providers in order of preference
+func NewMempoolProviders(cfg *EnvTypes.EnvConfig, client httpclient.Doer) []MempoolProvider {
+	var providers []MempoolProvider
+	for _, name := range cfg.MempoolConfig.Providers {
+		switch name {
+		case "mempool":
+			providers = append(providers, &MempoolSpaceProvider{esploraAPI{API: cfg.MempoolConfig.API, Client: client}})
+		case "esplora":
+			providers = append(providers, &EsploraProvider{esploraAPI{API: cfg.MempoolConfig.EsploraAPI, Client: client}})
+		}
+	}
+	return providers
+}
+
+// MempoolSpaceProvider reads from a mempool.space compatible API
+type MempoolSpaceProvider struct {
+	esploraAPI
+}
+
+// NewMempoolSpaceProvider returns a provider for the mempool.space compatible API at api
+func NewMempoolSpaceProvider(api string, client httpclient.Doer) *MempoolSpaceProvider {
+	return &MempoolSpaceProvider{esploraAPI{API: api, Client: client}}
+}
+
+// Name returns the provider name used in the configuration and the logs
+func (p *MempoolSpaceProvider) Name() string {
+	return "mempool"
+}
+
+// FeeEstimates fetches the recommended fee rates
+func (p *MempoolSpaceProvider) FeeEstimates(ctx context.Context) (*WalletExplorer.IFeeEstimates, error) {
+	var fees WalletExplorer.MempoolFees
+	if err := p.fetchJSON(ctx, "/v1/fees/recommended", &fees); err != nil {
+		return nil, err
+	}
+
+	return &WalletExplorer.IFeeEstimates{
+		Fastest:  fees.FastestFee,
+		HalfHour: fees.HalfHourFee,
+		Hour:     fees.HourFee,
+		Economy:  fees.EconomyFee,
+		Minimum:  fees.MinimumFee,
+	}, nil
+}
+
+// Replacements fetches the RBF history of a transaction. A 404 means it has none.
+func (p *MempoolSpaceProvider) Replacements(ctx context.Context, txid string) (*WalletExplorer.MempoolRBFHistory, error) {
+	var rbf WalletExplorer.MempoolRBFHistory
+	if err := p.fetchJSON(ctx, "/v1/tx/"+txid+"/rbf", &rbf); err != nil && !errors.Is(err, errUpstreamNotFound) {
+		return nil, err
+	}
+	return &rbf, nil
+}
+
+// EsploraProvider reads from an Esplora API such as blockstream.info. Esplora does not
+// track replacements, so replaced transactions it already evicted are reported as unknown.
+type EsploraProvider struct {
+	esploraAPI
+}
+
+// NewEsploraProvider returns a provider for the Esplora API at api
+func NewEsploraProvider(api string, client httpclient.Doer) *EsploraProvider {
+	return &EsploraProvider{esploraAPI{API: api, Client: client}}
+}
+
+// Name returns the provider name used in the configuration and the logs
+func (p *EsploraProvider) Name() string {
+	return "esplora"
+}
+
+// FeeEstimates maps the Esplora estimates, keyed by confirmation target in blocks, onto the
+// recommended rates. The minimum is the lowest estimate, which Esplora floors at the
+// mempool purge rate.
+func (p *EsploraProvider) FeeEstimates(ctx context.Context) (*WalletExplorer.IFeeEstimates, error) {
+	var estimates map[string]float64
+	if err := p.fetchJSON(ctx, "/fee-estimates", &estimates); err != nil {
+		return nil, err
+	}
+
+	fees := &WalletExplorer.IFeeEstimates{}
+	for target, rate := range map[string]*float64{"1": &fees.Fastest, "3": &fees.HalfHour, "6": &fees.Hour, "144": &fees.Economy} {
+		estimate, ok := estimates[target]
+		if !ok {
+			return nil, fmt.Errorf("fee estimates miss the %s block target", target)
+		}
+		*rate = estimate
+	}
+	fees.Minimum = fees.Economy
+	for _, estimate := range estimates {
+		if estimate < fees.Minimum {
+			fees.Minimum = estimate
+		}
+	}
+	return fees, nil
+}
+
+// Replacements always returns an empty history
+func (p *EsploraProvider) Replacements(ctx context.Context, txid string) (*WalletExplorer.MempoolRBFHistory, error) {
+	return &WalletExplorer.MempoolRBFHistory{}, nil
+}
+
+// esploraAPI holds the endpoints mempool.space shares with Esplora
+type esploraAPI struct {
+	API    string
+	Client httpclient.Doer
+}
+
+// TipHeight fetches the height of the chain tip
+func (a *esploraAPI) TipHeight(ctx context.Context) (int, error) {
+	body, err := a.fetch(ctx, "/blocks/tip/height")
+	if err != nil {
+		return 0, err
+	}
+
+	height, err := strconv.Atoi(strings.TrimSpace(string(body)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse block height: %w", err)
+	}
+	return height, nil
+}
+
+// TipHash fetches the hash of the block at the chain tip
+func (a *esploraAPI) TipHash(ctx context.Context) (string, error) {
+	body, err := a.fetch(ctx, "/blocks/tip/hash")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// Transaction fetches a confirmed or unconfirmed transaction
+func (a *esploraAPI) Transaction(ctx context.Context, txid string) (*WalletExplorer.MempoolTransaction, error) {
+	var tx WalletExplorer.MempoolTransaction
+	if err := a.fetchJSON(ctx, "/tx/"+txid, &tx); err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// fetchJSON fetches path and decodes its JSON body into out
+func (a *esploraAPI) fetchJSON(ctx context.Context, path string, out any) error {
+	body, err := a.fetch(ctx, path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return nil
+}
+
+// fetch performs a GET request on the API and returns the body of a 200 response
+func (a *esploraAPI) fetch(ctx context.Context, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.API+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := a.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch data: %w", err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errUpstreamNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("external API returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return body, nil
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"encoding/hex"
+
+	"cry-api/app/bitcoin"
+	WalletExplorer "cry-api/app/types/wallet_explorer"
+)
+
+// AnalyzeOutputScript classifies and disassembles a hex output script.
+// It returns nil when the script is not valid hex.
+func AnalyzeOutputScript(scriptHex string) *WalletExplorer.IScriptAnalysis {
+	script, err := hex.DecodeString(scriptHex)
+	if err != nil {
+		return nil
+	}
+
+	class := bitcoin.ClassifyScript(script)
+	analysis := &WalletExplorer.IScriptAnalysis{
+		Type: string(class.Type),
+		ASM:  bitcoin.Disassemble(script),
+	}
+	if class.Type == bitcoin.ScriptMultisig {
+		analysis.RequiredSigs = &class.RequiredSigs
+		analysis.TotalKeys = &class.TotalKeys
+	}
+	if payload, ok := bitcoin.NullDataPayload(script); ok {
+		analysis.OpReturn = &WalletExplorer.IOpReturnPayload{Hex: hex.EncodeToString(payload)}
+		if text, ok := bitcoin.PrintableText(payload); ok {
+			analysis.OpReturn.Text = &text
+		}
+	}
+	return analysis
+}
+
+// AnalyzeInput describes an input: the type of the output it spends (when known),
+// the ASM of its scriptSig, its witness stack and any inscription envelope.
+// It returns nil when the scriptSig is not valid hex.
+func AnalyzeInput(input *WalletExplorer.Input) *WalletExplorer.IScriptAnalysis {
+	scriptSig, err := hex.DecodeString(input.Script)
+	if err != nil {
+		return nil
+	}
+
+	analysis := &WalletExplorer.IScriptAnalysis{
+		Type: string(bitcoin.ScriptNonStandard),
+		ASM:  bitcoin.Disassemble(scriptSig),
+	}
+	if input.PrevOut != nil {
+		if spent := AnalyzeOutputScript(input.PrevOut.Script); spent != nil {
+			analysis.Type = spent.Type
+			analysis.RequiredSigs = spent.RequiredSigs
+			analysis.TotalKeys = spent.TotalKeys
+		}
+	}
+
+	if input.Witness != nil && *input.Witness != "" {
+		raw, err := hex.DecodeString(*input.Witness)
+		if err != nil {
+			return analysis
+		}
+		witness, err := bitcoin.ParseWitness(raw)
+		if err != nil {
+			return analysis
+		}
+		for _, item := range witness {
+			analysis.Witness = append(analysis.Witness, hex.EncodeToString(item))
+		}
+		if script := witnessScript(bitcoin.ScriptType(analysis.Type), scriptSig, witness); script != nil {
+			asm := bitcoin.Disassemble(script)
+			analysis.WitnessASM = &asm
+		}
+		if inscription := bitcoin.FindInscription(witness); inscription != nil {
+			analysis.Inscription = &WalletExplorer.IInscription{
+				Protocol:      inscription.Protocol,
+				ContentType:   inscription.ContentType,
+				ContentLength: len(inscription.Body),
+			}
+		}
+	}
+	return analysis
+}
+
+// witnessScript returns the script revealed by a script-path spend: the last witness
+// item for P2WSH and P2SH-P2WSH, or the leaf script preceding the control block for taproot.
+func witnessScript(spentType bitcoin.ScriptType, scriptSig []byte, witness [][]byte) []byte {
+	switch spentType {
+	case bitcoin.ScriptP2SH:
+		// The witness of a P2SH-P2WPKH spend is a signature and a key, not a script
+		if bitcoin.ClassifyScript(redeemScript(scriptSig)).Type != bitcoin.ScriptP2WSH {
+			return nil
+		}
+		fallthrough
+	case bitcoin.ScriptP2WSH:
+		if len(witness) > 1 {
+			return witness[len(witness)-1]
+		}
+	case bitcoin.ScriptP2TR:
+		if len(witness) > 1 && len(witness[len(witness)-1]) > 0 && witness[len(witness)-1][0] == 0x50 {
+			witness = witness[:len(witness)-1]
+		}
+		if len(witness) > 1 {
+			return witness[len(witness)-2]
+		}
+	}
+	return nil
+}
+
+// redeemScript returns the redeem script of a P2SH scriptSig, its last push, or nil when the
+// scriptSig is not push-only
+func redeemScript(scriptSig []byte) []byte {
+	ops, err := bitcoin.ParseScript(scriptSig)
+	if err != nil || len(ops) == 0 {
+		return nil
+	}
+	for _, op := range ops {
+		if !op.IsPush() {
+			return nil
+		}
+	}
+	return ops[len(ops)-1].Data
+}
+
+// AnalyzeTransaction attaches script analysis to every input and output of a transaction.
+func AnalyzeTransaction(data *WalletExplorer.ITransactionData) {
+	for i := range data.Inputs {
+		data.Inputs[i].ScriptAnalysis = AnalyzeInput(&data.Inputs[i])
+	}
+	for i := range data.Out {
+		data.Out[i].ScriptAnalysis = AnalyzeOutputScript(data.Out[i].Script)
+	}
+}
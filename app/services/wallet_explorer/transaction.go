@@ -2,12 +2,15 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"time"
+	"strconv"
+	"strings"
 
+	"cry-api/app/httpclient"
 	EnvTypes "cry-api/app/types/env"
 	WalletExplorer "cry-api/app/types/wallet_explorer"
 )
@@ -15,52 +18,107 @@ import (
 // TransactionService interacts with external wallet explorer APIs.
 type TransactionService struct {
 	Config *EnvTypes.EnvConfig
+	Client httpclient.Doer
 }
 
 // TransactionServiceInterface defines the methods for the TransactionService.
 type TransactionServiceInterface interface {
-	GetTransactionByXPUB(xpub string) (*WalletExplorer.ITransactionXPUB, error)
-	GetTransactionByTxID(txid string) (*WalletExplorer.ITransactionData, error)
+	GetTransactionByXPUB(ctx context.Context, xpub string) (*WalletExplorer.ITransactionXPUB, error)
+	GetTransactionByTxID(ctx context.Context, txid string) (*WalletExplorer.ITransactionData, error)
+	GetTransactionByAddress(ctx context.Context, address string) (*WalletExplorer.ITransactionAddress, error)
+	StreamAddressTransactions(ctx context.Context, address string, fn func(WalletExplorer.AddressTransaction) error) error
+	GetBlockHeight(ctx context.Context) (int, error)
 }
 
 // NewTransactionService initializes and returns an TransactionService instance
-func NewTransactionService(cfg *EnvTypes.EnvConfig) *TransactionService {
+func NewTransactionService(cfg *EnvTypes.EnvConfig, client httpclient.Doer) *TransactionService {
 	return &TransactionService{
 		Config: cfg,
+		Client: client,
 	}
 }
 
 // GetTransactionByTxID fetches transaction data from Blockchain API
-func (s *TransactionService) GetTransactionByTxID(txid string) (*WalletExplorer.ITransactionData, error) {
+func (s *TransactionService) GetTransactionByTxID(ctx context.Context, txid string) (*WalletExplorer.ITransactionData, error) {
 	// Use config URL
 	baseURL := s.Config.BlockchainConfig.API
 	url := fmt.Sprintf("%s/rawtx/%s", baseURL, txid)
 
-	req, err := http.NewRequest("GET", url, nil)
+	body, err := s.fetch(ctx, url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	var data WalletExplorer.ITransactionData
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	AnalyzeTransaction(&data)
+
+	return &data, nil
+}
+
+// GetTransactionByXPUB fetches transaction data from WalletExplorer API
+func (s *TransactionService) GetTransactionByXPUB(ctx context.Context, xpub string) (*WalletExplorer.ITransactionXPUB, error) {
+	// Use config URL
+	baseURL := s.Config.WalletExplorerConfig.API
+	url := fmt.Sprintf("%s/xpub-txs?pub=%s&gap_limit=5", baseURL, xpub)
+
+	body, err := s.fetch(ctx, url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch data: %w", err)
+		return nil, err
 	}
 
-	defer func() {
-		_ = resp.Body.Close()
-	}()
+	var data WalletExplorer.ITransactionXPUB
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("external API returned status %d", resp.StatusCode)
+	return &data, nil
+}
+
+// AddressPageSize is the number of transactions requested per page when streaming an address history
+const AddressPageSize = 50
+
+// GetTransactionByAddress fetches the balance and recent transactions of an address from Blockchain API
+func (s *TransactionService) GetTransactionByAddress(ctx context.Context, address string) (*WalletExplorer.ITransactionAddress, error) {
+	return s.fetchAddress(ctx, address, "")
+}
+
+// StreamAddressTransactions walks the full history of an address page by page, newest first,
+// calling fn for every transaction. It stops at the first error returned by fn.
+func (s *TransactionService) StreamAddressTransactions(ctx context.Context, address string, fn func(WalletExplorer.AddressTransaction) error) error {
+	for offset := 0; ; offset += AddressPageSize {
+		page, err := s.fetchAddress(ctx, address, fmt.Sprintf("?limit=%d&offset=%d", AddressPageSize, offset))
+		if err != nil {
+			return err
+		}
+
+		for _, tx := range page.Transactions {
+			if err := fn(tx); err != nil {
+				return err
+			}
+		}
+
+		if len(page.Transactions) < AddressPageSize || offset+len(page.Transactions) >= page.NTx {
+			return nil
+		}
 	}
+}
 
-	body, err := io.ReadAll(resp.Body)
+// fetchAddress fetches one page of the Blockchain API address endpoint
+func (s *TransactionService) fetchAddress(ctx context.Context, address, query string) (*WalletExplorer.ITransactionAddress, error) {
+	// Use config URL
+	baseURL := s.Config.BlockchainConfig.API
+	url := fmt.Sprintf("%s/rawaddr/%s%s", baseURL, address, query)
+
+	body, err := s.fetch(ctx, url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, err
 	}
 
-	var data WalletExplorer.ITransactionData
+	var data WalletExplorer.ITransactionAddress
 	if err := json.Unmarshal(body, &data); err != nil {
 		return nil, fmt.Errorf("failed to parse JSON: %w", err)
 	}
@@ -68,22 +126,30 @@ func (s *TransactionService) GetTransactionByTxID(txid string) (*WalletExplorer.
 	return &data, nil
 }
 
-// GetTransactionByXPUB fetches transaction data from WalletExplorer API
-func (s *TransactionService) GetTransactionByXPUB(xpub string) (*WalletExplorer.ITransactionXPUB, error) {
-	// Use config URL
-	baseURL := s.Config.WalletExplorerConfig.API
-	url := fmt.Sprintf("%s/xpub-txs?pub=%s&gap_limit=5", baseURL, xpub)
+// GetBlockHeight fetches the height of the chain tip from Blockchain API
+func (s *TransactionService) GetBlockHeight(ctx context.Context) (int, error) {
+	url := fmt.Sprintf("%s/q/getblockcount", s.Config.BlockchainConfig.API)
 
-	req, err := http.NewRequest("GET", url, nil)
+	body, err := s.fetch(ctx, url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return 0, err
 	}
 
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+	height, err := strconv.Atoi(strings.TrimSpace(string(body)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse block height: %w", err)
+	}
+	return height, nil
+}
+
+// fetch performs a GET request through the shared client and returns the body of a 200 response
+func (s *TransactionService) fetch(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := client.Do(req)
+	resp, err := s.Client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch data: %w", err)
 	}
@@ -100,11 +166,5 @@ func (s *TransactionService) GetTransactionByXPUB(xpub string) (*WalletExplorer.
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
-
-	var data WalletExplorer.ITransactionXPUB
-	if err := json.Unmarshal(body, &data); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON: %w", err)
-	}
-
-	return &data, nil
+	return body, nil
 }
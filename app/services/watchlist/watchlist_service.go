@@ -0,0 +1,533 @@
+// Package services provides the per-user watchlist of addresses, extended public keys
+// and output descriptors.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"cry-api/app/bitcoin"
+	"cry-api/app/logger"
+	UserModel "cry-api/app/models"
+	WatchedWalletRepository "cry-api/app/repositories"
+	WalletExplorerService "cry-api/app/services/wallet_explorer"
+	app_errors "cry-api/app/types/errors"
+	WalletExplorer "cry-api/app/types/wallet_explorer"
+	WatchlistTypes "cry-api/app/types/watchlist"
+)
+
+const (
+	// DefaultColor is used when an entry is created without a color
+	DefaultColor = "#8b5cf6"
+	// MaxLabelLength is the longest label accepted for an entry
+	MaxLabelLength = 100
+	// RecentActivityLimit caps the number of transactions returned by the summary
+	RecentActivityLimit = 20
+	satoshisPerBitcoin  = 1e8
+)
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// ErrIncompleteHistory is returned when the full history of a wallet does not match the
+// transaction count or final balance reported by the explorer
+var ErrIncompleteHistory = errors.New("wallet history is incomplete")
+
+// WatchlistService manages watched wallets and aggregates their balances.
+type WatchlistService struct {
+	repo               WatchedWalletRepository.WatchedWalletRepository
+	transactionService WalletExplorerService.TransactionServiceInterface
+}
+
+// WatchlistServiceInterface defines the methods for the WatchlistService.
+type WatchlistServiceInterface interface {
+	AddWallet(userID int, req WatchlistTypes.ICreateWatchedWalletRequest) (*UserModel.WatchedWallet, error)
+	ListWallets(userID int) ([]UserModel.WatchedWallet, error)
+	GetWallet(userID, id int) (*UserModel.WatchedWallet, error)
+	UpdateWallet(userID, id int, req WatchlistTypes.IUpdateWatchedWalletRequest) (*UserModel.WatchedWallet, error)
+	DeleteWallet(userID, id int) error
+	GetSummary(ctx context.Context, userID int) (*WatchlistTypes.IWatchlistSummary, error)
+	GetWalletHistory(ctx context.Context, wallet *UserModel.WatchedWallet) ([]WatchlistTypes.IWatchlistActivity, error)
+	GetRecentWalletActivity(ctx context.Context, wallet *UserModel.WatchedWallet) ([]WatchlistTypes.IWatchlistActivity, error)
+	GetHistory(ctx context.Context, userID int) (*WatchlistTypes.IWatchlistHistory, error)
+}
+
+// NewWatchlistService initializes and returns a WatchlistService instance
+func NewWatchlistService(
+	repo WatchedWalletRepository.WatchedWalletRepository,
+	transactionService WalletExplorerService.TransactionServiceInterface,
+) *WatchlistService {
+	return &WatchlistService{
+		repo:               repo,
+		transactionService: transactionService,
+	}
+}
+
+// AddWallet validates and stores a new watchlist entry for the user
+func (s *WatchlistService) AddWallet(userID int, req WatchlistTypes.ICreateWatchedWalletRequest) (*UserModel.WatchedWallet, error) {
+	kind := strings.ToLower(strings.TrimSpace(req.Kind))
+	value, err := normalizeValue(kind, strings.TrimSpace(req.Value))
+	if err != nil {
+		return nil, err
+	}
+
+	label, err := validateLabel(req.Label)
+	if err != nil {
+		return nil, err
+	}
+
+	color := DefaultColor
+	if strings.TrimSpace(req.Color) != "" {
+		if color, err = validateColor(req.Color); err != nil {
+			return nil, err
+		}
+	}
+
+	existing, err := s.repo.FindByValue(userID, value)
+	if err != nil {
+		return nil, app_errors.ErrDatabaseError
+	}
+	if existing != nil {
+		return nil, app_errors.NewConflictError("watched_wallet", "This wallet is already on your watchlist")
+	}
+
+	wallet := &UserModel.WatchedWallet{
+		UserID:    userID,
+		Kind:      kind,
+		Value:     value,
+		Label:     label,
+		Color:     color,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.Save(wallet); err != nil {
+		return nil, app_errors.ErrDatabaseError
+	}
+
+	return wallet, nil
+}
+
+// ListWallets returns every watchlist entry of the user
+func (s *WatchlistService) ListWallets(userID int) ([]UserModel.WatchedWallet, error) {
+	wallets, err := s.repo.FindByUserID(userID)
+	if err != nil {
+		return nil, app_errors.ErrDatabaseError
+	}
+	if wallets == nil {
+		wallets = []UserModel.WatchedWallet{}
+	}
+	return wallets, nil
+}
+
+// GetWallet returns a single watchlist entry owned by the user
+func (s *WatchlistService) GetWallet(userID, id int) (*UserModel.WatchedWallet, error) {
+	wallet, err := s.repo.FindByIDAndUserID(id, userID)
+	if err != nil {
+		return nil, app_errors.ErrDatabaseError
+	}
+	if wallet == nil {
+		return nil, app_errors.NewNotFoundError("watched_wallet", "Watched wallet not found")
+	}
+	return wallet, nil
+}
+
+// UpdateWallet changes the label and/or color of a watchlist entry
+func (s *WatchlistService) UpdateWallet(userID, id int, req WatchlistTypes.IUpdateWatchedWalletRequest) (*UserModel.WatchedWallet, error) {
+	if req.Label == nil && req.Color == nil {
+		return nil, app_errors.NewValidationError("label", "", "Nothing to update")
+	}
+
+	wallet, err := s.GetWallet(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Label != nil {
+		if wallet.Label, err = validateLabel(*req.Label); err != nil {
+			return nil, err
+		}
+	}
+	if req.Color != nil {
+		if wallet.Color, err = validateColor(*req.Color); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.Save(wallet); err != nil {
+		return nil, app_errors.ErrDatabaseError
+	}
+	return wallet, nil
+}
+
+// DeleteWallet removes a watchlist entry owned by the user
+func (s *WatchlistService) DeleteWallet(userID, id int) error {
+	if _, err := s.GetWallet(userID, id); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(id, userID); err != nil {
+		return app_errors.ErrDatabaseError
+	}
+	return nil
+}
+
+// GetSummary aggregates the balance and recent activity across everything the user watches.
+// A failing lookup is reported on the affected entry and does not fail the whole summary.
+// Entries looked up through the same address or account key, such as the receive and change
+// descriptors of an account, show the same balance but count once in the total.
+func (s *WatchlistService) GetSummary(ctx context.Context, userID int) (*WatchlistTypes.IWatchlistSummary, error) {
+	wallets, err := s.ListWallets(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &WatchlistTypes.IWatchlistSummary{
+		Wallets:        make([]WatchlistTypes.IWatchedWalletBalance, 0, len(wallets)),
+		RecentActivity: []WatchlistTypes.IWatchlistActivity{},
+		GeneratedAt:    time.Now().UTC(),
+	}
+
+	var total int64
+	counted := make(map[string]bool)
+	for _, wallet := range wallets {
+		entry := WatchlistTypes.IWatchedWalletBalance{
+			ID:    wallet.ID,
+			Kind:  wallet.Kind,
+			Label: wallet.Label,
+			Color: wallet.Color,
+		}
+
+		activity, err := s.lookup(ctx, &wallet, &entry, false)
+		if err != nil {
+			msg := lookupFailure(ctx, &wallet, err)
+			entry.Error = &msg
+		} else if target := lookupTarget(&wallet); !counted[target] {
+			counted[target] = true
+			total += btcToSats(entry.Balance)
+			summary.RecentActivity = append(summary.RecentActivity, activity...)
+		}
+
+		summary.Wallets = append(summary.Wallets, entry)
+	}
+
+	summary.TotalBalance = satsToBTC(total)
+
+	sort.SliceStable(summary.RecentActivity, func(i, j int) bool {
+		return summary.RecentActivity[i].Time > summary.RecentActivity[j].Time
+	})
+	if len(summary.RecentActivity) > RecentActivityLimit {
+		summary.RecentActivity = summary.RecentActivity[:RecentActivityLimit]
+	}
+
+	return summary, nil
+}
+
+// GetWalletHistory returns every transaction of a single watched wallet, oldest first. Address
+// histories are walked page by page; ErrIncompleteHistory is returned when the pages do not add
+// up to what the explorer reports for the address.
+func (s *WatchlistService) GetWalletHistory(ctx context.Context, wallet *UserModel.WatchedWallet) ([]WatchlistTypes.IWatchlistActivity, error) {
+	return s.walletActivity(ctx, wallet, true)
+}
+
+// GetRecentWalletActivity returns the latest transactions of a single watched wallet, oldest
+// first. Addresses only return the newest page of their history.
+func (s *WatchlistService) GetRecentWalletActivity(ctx context.Context, wallet *UserModel.WatchedWallet) ([]WatchlistTypes.IWatchlistActivity, error) {
+	return s.walletActivity(ctx, wallet, false)
+}
+
+// walletActivity looks up the transactions of a wallet and sorts them oldest first
+func (s *WatchlistService) walletActivity(ctx context.Context, wallet *UserModel.WatchedWallet, full bool) ([]WatchlistTypes.IWatchlistActivity, error) {
+	var entry WatchlistTypes.IWatchedWalletBalance
+	activity, err := s.lookup(ctx, wallet, &entry, full)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(activity, func(i, j int) bool {
+		return activity[i].Time < activity[j].Time
+	})
+	return activity, nil
+}
+
+// GetHistory merges the history of every watched wallet into watchlist-wide balance changes,
+// oldest first. Transactions seen from several watched wallets are merged by txid, so transfers
+// between the user's own wallets only count for their fee. Unconfirmed transactions are placed
+// at the current time. Wallets whose history cannot be fetched are reported as warnings.
+func (s *WatchlistService) GetHistory(ctx context.Context, userID int) (*WatchlistTypes.IWatchlistHistory, error) {
+	wallets, err := s.ListWallets(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	history := &WatchlistTypes.IWatchlistHistory{Transactions: []WatchlistTypes.IWatchlistActivity{}}
+	byTxID := make(map[string]int)
+	merged := make(map[string]bool)
+	for i := range wallets {
+		// A wallet looked up through the same address or account key as a previous one has
+		// the same transactions
+		target := lookupTarget(&wallets[i])
+		if merged[target] {
+			continue
+		}
+		activity, err := s.GetWalletHistory(ctx, &wallets[i])
+		if err != nil {
+			history.Warnings = append(history.Warnings, wallets[i].Label+": "+lookupFailure(ctx, &wallets[i], err))
+			continue
+		}
+		merged[target] = true
+
+		for _, tx := range activity {
+			if tx.Time == 0 {
+				tx.Time = now
+			}
+			idx, ok := byTxID[tx.TxID]
+			if !ok {
+				byTxID[tx.TxID] = len(history.Transactions)
+				history.Transactions = append(history.Transactions, tx)
+				continue
+			}
+			existing := &history.Transactions[idx]
+			existing.BalanceDiff = satsToBTC(btcToSats(existing.BalanceDiff) + btcToSats(tx.BalanceDiff))
+			if tx.Time < existing.Time {
+				existing.Time = tx.Time
+			}
+		}
+	}
+
+	sort.SliceStable(history.Transactions, func(i, j int) bool {
+		a, b := history.Transactions[i], history.Transactions[j]
+		if a.Time != b.Time {
+			return a.Time < b.Time
+		}
+		return a.TxID < b.TxID
+	})
+
+	return history, nil
+}
+
+// lookupFailure logs a failed lookup and returns the message shown for it. Upstream errors are
+// not shown, as they can quote the explorer's response.
+func lookupFailure(ctx context.Context, wallet *UserModel.WatchedWallet, err error) string {
+	logger.FromContext(ctx).WithError(err).WithField("watched_wallet_id", wallet.ID).Warn("Watched wallet lookup failed")
+	if errors.Is(err, ErrIncompleteHistory) {
+		return "History is incomplete"
+	}
+	var validationErr *app_errors.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.Message
+	}
+	return "Lookup failed"
+}
+
+// lookup fills the balance of a single entry and returns its transactions. With full set,
+// addresses return their whole history instead of the newest page.
+func (s *WatchlistService) lookup(ctx context.Context, wallet *UserModel.WatchedWallet, entry *WatchlistTypes.IWatchedWalletBalance, full bool) ([]WatchlistTypes.IWatchlistActivity, error) {
+	switch wallet.Kind {
+	case UserModel.WatchedWalletKindAddress:
+		return s.lookupAddress(ctx, wallet, wallet.Value, entry, full)
+	case UserModel.WatchedWalletKindXPUB:
+		return s.lookupXPUB(ctx, wallet, wallet.Value, entry)
+	case UserModel.WatchedWalletKindDescriptor:
+		target, isAddress, err := descriptorLookupTarget(wallet.Value)
+		if err != nil {
+			return nil, err
+		}
+		if isAddress {
+			return s.lookupAddress(ctx, wallet, target, entry, full)
+		}
+		return s.lookupXPUB(ctx, wallet, target, entry)
+	default:
+		return nil, fmt.Errorf("unsupported kind %q", wallet.Kind)
+	}
+}
+
+func (s *WatchlistService) lookupAddress(ctx context.Context, wallet *UserModel.WatchedWallet, address string, entry *WatchlistTypes.IWatchedWalletBalance, full bool) ([]WatchlistTypes.IWatchlistActivity, error) {
+	data, err := s.transactionService.GetTransactionByAddress(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.Balance = satsToBTC(data.FinalBalance)
+	entry.TxCount = data.NTx
+
+	transactions := data.Transactions
+	if full && len(transactions) < data.NTx {
+		if transactions, err = s.addressHistory(ctx, address, data); err != nil {
+			return nil, err
+		}
+	}
+
+	activity := make([]WatchlistTypes.IWatchlistActivity, 0, len(transactions))
+	for _, tx := range transactions {
+		activity = append(activity, WatchlistTypes.IWatchlistActivity{
+			WalletID:    wallet.ID,
+			Label:       wallet.Label,
+			TxID:        tx.Hash,
+			BlockHeight: tx.BlockHeight,
+			Time:        tx.Time,
+			BalanceDiff: satsToBTC(tx.Result),
+		})
+	}
+	return activity, nil
+}
+
+// addressHistory walks every page of the history of an address. Transactions shifted onto the
+// next page by a new one are skipped. The history must cover the transaction count of data, and
+// add up to its final balance when it covers exactly that count.
+func (s *WatchlistService) addressHistory(ctx context.Context, address string, data *WalletExplorer.ITransactionAddress) ([]WalletExplorer.AddressTransaction, error) {
+	var transactions []WalletExplorer.AddressTransaction
+	var total int64
+	seen := make(map[string]bool)
+	err := s.transactionService.StreamAddressTransactions(ctx, address, func(tx WalletExplorer.AddressTransaction) error {
+		if seen[tx.Hash] {
+			return nil
+		}
+		seen[tx.Hash] = true
+		transactions = append(transactions, tx)
+		total += tx.Result
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(transactions) < data.NTx {
+		return nil, fmt.Errorf("%w: got %d of %d transactions", ErrIncompleteHistory, len(transactions), data.NTx)
+	}
+	if len(transactions) == data.NTx && total != data.FinalBalance {
+		return nil, fmt.Errorf("%w: transactions add up to %d sats instead of the final balance of %d sats", ErrIncompleteHistory, total, data.FinalBalance)
+	}
+	return transactions, nil
+}
+
+func (s *WatchlistService) lookupXPUB(ctx context.Context, wallet *UserModel.WatchedWallet, xpub string, entry *WatchlistTypes.IWatchedWalletBalance) ([]WatchlistTypes.IWatchlistActivity, error) {
+	data, err := s.transactionService.GetTransactionByXPUB(ctx, xpub)
+	if err != nil {
+		return nil, err
+	}
+
+	activity := make([]WatchlistTypes.IWatchlistActivity, 0, len(data.Transactions))
+	var balance int64
+	for _, tx := range data.Transactions {
+		balance += btcToSats(tx.BalanceDiff)
+		activity = append(activity, WatchlistTypes.IWatchlistActivity{
+			WalletID:    wallet.ID,
+			Label:       wallet.Label,
+			TxID:        tx.TxID,
+			BlockHeight: tx.BlockHeight,
+			Time:        tx.Time,
+			BalanceDiff: tx.BalanceDiff,
+		})
+	}
+
+	entry.Balance = satsToBTC(balance)
+	entry.TxCount = len(data.Transactions)
+	return activity, nil
+}
+
+// normalizeValue validates a watched value for its kind and returns its canonical form
+func normalizeValue(kind, value string) (string, error) {
+	if value == "" {
+		return "", app_errors.NewValidationError("value", value, "Value is required")
+	}
+
+	switch kind {
+	case UserModel.WatchedWalletKindAddress:
+		if _, _, err := bitcoin.ScriptFromAddress(value); err != nil {
+			return "", app_errors.NewValidationError("value", value, "Invalid Bitcoin address")
+		}
+		return value, nil
+	case UserModel.WatchedWalletKindXPUB:
+		if _, err := bitcoin.ParseExtendedKey(value); err != nil {
+			return "", app_errors.NewValidationError("value", value, "Invalid extended public key")
+		}
+		return value, nil
+	case UserModel.WatchedWalletKindDescriptor:
+		desc, err := bitcoin.ParseDescriptor(value)
+		if err != nil {
+			return "", app_errors.NewValidationError("value", value, "Invalid output descriptor")
+		}
+		return desc.StringWithChecksum(), nil
+	default:
+		return "", app_errors.NewValidationError("kind", kind, "Kind must be one of address, xpub or descriptor")
+	}
+}
+
+// lookupTarget returns what a wallet is looked up through: its address or extended key, or
+// the lookup target of its descriptor. A descriptor that cannot be looked up is its own target.
+func lookupTarget(wallet *UserModel.WatchedWallet) string {
+	if wallet.Kind != UserModel.WatchedWalletKindDescriptor {
+		return wallet.Value
+	}
+	target, _, err := descriptorLookupTarget(wallet.Value)
+	if err != nil {
+		return wallet.Value
+	}
+	return target
+}
+
+// descriptorLookupTarget maps a descriptor to something the explorer APIs can look up:
+// either a single address, or a SLIP-132 account key for single-key KEY/<chain>/* descriptors.
+// The account key is looked up with its receive (0) and change (1) chains, so other chains
+// are not supported.
+func descriptorLookupTarget(value string) (string, bool, error) {
+	desc, err := bitcoin.ParseDescriptor(value)
+	if err != nil {
+		return "", false, err
+	}
+
+	if !desc.IsRange() {
+		address, err := desc.AddressAt(0)
+		if err != nil {
+			return "", false, err
+		}
+		return address, true, nil
+	}
+
+	unsupported := fmt.Errorf("balance lookup is not supported for %s descriptors", desc.ScriptType)
+	if len(desc.Keys) != 1 || desc.Keys[0].Extended == nil || len(desc.Keys[0].Path) != 1 || desc.Keys[0].Path[0] > 1 {
+		return "", false, unsupported
+	}
+
+	key := desc.Keys[0].Extended
+	format := bitcoin.FormatForScript(desc.ScriptType, key.Format().Network, false)
+	if format == nil {
+		return "", false, unsupported
+	}
+
+	converted := *key
+	converted.Version = format.Version
+	return converted.String(), false, nil
+}
+
+func validateLabel(label string) (string, error) {
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return "", app_errors.NewValidationError("label", label, "Label is required")
+	}
+	if len([]rune(label)) > MaxLabelLength {
+		return "", app_errors.NewValidationError("label", label, fmt.Sprintf("Label must be at most %d characters", MaxLabelLength))
+	}
+	return label, nil
+}
+
+func validateColor(color string) (string, error) {
+	color = strings.TrimSpace(color)
+	if !colorPattern.MatchString(color) {
+		return "", app_errors.NewValidationError("color", color, "Color must be a hex value like #1a2b3c")
+	}
+	return strings.ToLower(color), nil
+}
+
+func satsToBTC(sats int64) float64 {
+	return float64(sats) / satoshisPerBitcoin
+}
+
+// btcToSats returns the satoshis of an amount of bitcoin read from the explorer
+func btcToSats(btc float64) int64 {
+	return int64(math.Round(btc * satoshisPerBitcoin))
+}
@@ -0,0 +1,30 @@
+// Package types provides type definitions for the users' market data alerts.
+package types
+
+import "encoding/json"
+
+// ICreateAlertRuleRequest represents the payload for creating an alert rule. Which fields are
+// used depends on the condition: symbol and currency for the price conditions, window for
+// the percent change and classification for the fear and greed classification change. The
+// threshold is a decimal, sent as a JSON number or string.
+type ICreateAlertRuleRequest struct {
+	Condition       string       `json:"condition" binding:"required"`
+	Symbol          string       `json:"symbol"`
+	Currency        string       `json:"currency"`
+	Threshold       *json.Number `json:"threshold"`
+	Window          string       `json:"window"`
+	Classification  string       `json:"classification"`
+	Mode            string       `json:"mode"`
+	CooldownMinutes *int         `json:"cooldown_minutes"`
+}
+
+// IUpdateAlertRuleRequest represents the payload for updating an alert rule. The condition
+// and the asset are immutable; a rule is paused or resumed through active.
+type IUpdateAlertRuleRequest struct {
+	Threshold       *json.Number `json:"threshold"`
+	Window          *string      `json:"window"`
+	Classification  *string      `json:"classification"`
+	Mode            *string      `json:"mode"`
+	CooldownMinutes *int         `json:"cooldown_minutes"`
+	Active          *bool        `json:"active"`
+}
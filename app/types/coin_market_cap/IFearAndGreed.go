@@ -9,7 +9,8 @@ import (
 
 // FearGreedData represents the response with a single data object.
 type FearGreedData struct {
-	Data FearGreedEntry `json:"data"`
+	Data   FearGreedEntry `json:"data"`
+	Source string         `json:"source,omitempty"` // market data provider that served the data
 }
 
 // FearGreedEntry represents a single fear & greed data point.
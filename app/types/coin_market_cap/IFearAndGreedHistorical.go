@@ -3,7 +3,8 @@ package types
 
 // FearGreedHistorical represents the response with a single data object.
 type FearGreedHistorical struct {
-	Data []FearGreedDataPoint `json:"data"`
+	Data   []FearGreedDataPoint `json:"data"`
+	Source string               `json:"source,omitempty"` // market data provider that served the data
 }
 
 // FearGreedDataPoint represents a single historical data point of the Fear and Greed index.
@@ -0,0 +1,62 @@
+// Package types provides type definitions for CoinMarketCap responses
+package types
+
+// IFearGreedAnalyticsPoint represents a day of the joined sentiment and price series. Price
+// is nil when no price is stored for the day, and Return when the price of the day or of the
+// day before is missing.
+type IFearGreedAnalyticsPoint struct {
+	Date           string   `json:"date"` // YYYY-MM-DD (UTC)
+	Value          int      `json:"value"`
+	Classification string   `json:"classification"`
+	Price          *float64 `json:"price"`
+	Return         *float64 `json:"return_pct"` // change of the price since the day before, in %
+}
+
+// IForwardReturn represents the price changes over a number of days after the days of a
+// classification. The statistics are nil when no day has both prices.
+type IForwardReturn struct {
+	Days          int      `json:"days"`
+	Count         int      `json:"count"`
+	Avg           *float64 `json:"avg_pct"`
+	Median        *float64 `json:"median_pct"`
+	PositiveShare *float64 `json:"positive_share_pct"` // share of the changes above 0, in %
+}
+
+// IForwardReturns represents the forward returns after the days of a classification
+type IForwardReturns struct {
+	Classification string           `json:"classification"`
+	Days           int              `json:"days"` // days of the classification in the range
+	Horizons       []IForwardReturn `json:"horizons"`
+}
+
+// IRollingCorrelation represents the correlation between the index value and the daily price
+// return over the window ending on a day. Correlation is nil when the window holds too few
+// days with a return, or a constant series.
+type IRollingCorrelation struct {
+	Date        string   `json:"date"` // YYYY-MM-DD (UTC)
+	Correlation *float64 `json:"correlation"`
+}
+
+// IClassificationTime represents the time the index spent in a classification
+type IClassificationTime struct {
+	Classification string  `json:"classification"`
+	Days           int     `json:"days"`
+	Share          float64 `json:"share_pct"`
+	LongestStreak  int     `json:"longest_streak"` // most consecutive days in the classification
+}
+
+// IFearGreedAnalytics represents the relation between the fear and greed index and the price
+// of an asset between two days, computed from the locally stored series
+type IFearGreedAnalytics struct {
+	From                 string                     `json:"from"`
+	To                   string                     `json:"to"`
+	Symbol               string                     `json:"symbol"`
+	Currency             string                     `json:"currency"`
+	Window               int                        `json:"window"` // days of the rolling correlation
+	Series               []IFearGreedAnalyticsPoint `json:"series"`
+	ForwardReturns       []IForwardReturns          `json:"forward_returns"`
+	Correlation          *float64                   `json:"correlation"` // over the whole range
+	RollingCorrelation   []IRollingCorrelation      `json:"rolling_correlation"`
+	TimeInClassification []IClassificationTime      `json:"time_in_classification"`
+	MissingPrices        int                        `json:"missing_prices"` // days of the series without a price
+}
@@ -0,0 +1,36 @@
+// Package types provides type definitions for CoinMarketCap responses
+package types
+
+// Aggregation intervals of the local fear and greed history
+const (
+	IntervalDaily   = "daily"
+	IntervalWeekly  = "weekly"
+	IntervalMonthly = "monthly"
+)
+
+// IFearGreedStats represents statistics over a set of daily fear and greed values
+type IFearGreedStats struct {
+	Min    int     `json:"min"`
+	Max    int     `json:"max"`
+	Avg    float64 `json:"avg"`
+	Median float64 `json:"median"`
+	Count  int     `json:"count"`
+}
+
+// IFearGreedPoint represents the fear and greed index over one day, week or month. Date is the
+// first day of the period, and the classification is the one of the average value.
+type IFearGreedPoint struct {
+	Date           string `json:"date"` // YYYY-MM-DD (UTC)
+	Classification string `json:"classification"`
+	IFearGreedStats
+}
+
+// IFearGreedRange represents the fear and greed history between two days
+type IFearGreedRange struct {
+	From     string            `json:"from"`
+	To       string            `json:"to"`
+	Interval string            `json:"interval"` // daily, weekly or monthly
+	Points   []IFearGreedPoint `json:"points"`
+	Stats    *IFearGreedStats  `json:"stats"`   // nil when no value is stored for the range
+	Sources  []string          `json:"sources"` // market data providers the values were synced from
+}
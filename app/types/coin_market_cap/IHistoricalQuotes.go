@@ -0,0 +1,40 @@
+// Package types provides type definitions for CoinMarketCap responses
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"time"
+)
+
+// HistoricalQuotesResponse represents the CoinMarketCap historical quotes payload
+// (v2/cryptocurrency/quotes/historical) when queried by symbol.
+type HistoricalQuotesResponse struct {
+	Data map[string][]HistoricalQuotesAsset `json:"data"`
+}
+
+// HistoricalQuotesAsset represents the quotes of a single asset
+type HistoricalQuotesAsset struct {
+	ID     int               `json:"id"`
+	Symbol string            `json:"symbol"`
+	Quotes []HistoricalQuote `json:"quotes"`
+}
+
+// HistoricalQuote represents a single point in time, keyed by fiat currency
+type HistoricalQuote struct {
+	Timestamp time.Time                    `json:"timestamp"`
+	Quote     map[string]HistoricalQuoteFX `json:"quote"`
+}
+
+// HistoricalQuoteFX represents the price of an asset in one currency. The price is kept as
+// the number it was sent as, so that it can be read exactly.
+type HistoricalQuoteFX struct {
+	Price     json.Number `json:"price"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// IPricePoint represents a normalized daily closing price
+type IPricePoint struct {
+	Date  string   `json:"date"` // YYYY-MM-DD (UTC)
+	Price *big.Rat `json:"price"`
+}
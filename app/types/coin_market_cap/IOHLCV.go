@@ -0,0 +1,65 @@
+// Package types provides type definitions for CoinMarketCap responses
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Candle intervals of the OHLCV history
+const (
+	CandleHourly  = "hourly"
+	CandleDaily   = "daily"
+	CandleWeekly  = "weekly"
+	CandleMonthly = "monthly"
+)
+
+// OHLCVAsset represents an asset of the OHLCV history payload (v2/cryptocurrency/ohlcv/historical)
+type OHLCVAsset struct {
+	ID     int           `json:"id"`
+	Name   string        `json:"name"`
+	Symbol string        `json:"symbol"`
+	Quotes []OHLCVPeriod `json:"quotes"`
+}
+
+// OHLCVPeriod represents a single period, keyed by currency
+type OHLCVPeriod struct {
+	TimeOpen  time.Time          `json:"time_open"`
+	TimeClose time.Time          `json:"time_close"`
+	Quote     map[string]OHLCVFX `json:"quote"`
+}
+
+// OHLCVFX represents the prices of a period in one currency. Numbers are kept as they were
+// sent, so that they can be read exactly.
+type OHLCVFX struct {
+	Open      json.Number `json:"open"`
+	High      json.Number `json:"high"`
+	Low       json.Number `json:"low"`
+	Close     json.Number `json:"close"`
+	Volume    json.Number `json:"volume"`
+	MarketCap json.Number `json:"market_cap"`
+}
+
+// ICandle represents the open, high, low and close prices of an asset over one period, as
+// exact decimals
+type ICandle struct {
+	TimeOpen  time.Time `json:"time_open"`
+	TimeClose time.Time `json:"time_close"`
+	Open      string    `json:"open"`
+	High      string    `json:"high"`
+	Low       string    `json:"low"`
+	Close     string    `json:"close"`
+	Volume    string    `json:"volume"`
+	MarketCap string    `json:"market_cap"`
+}
+
+// IOHLCV represents the candles of an asset between two times, oldest first
+type IOHLCV struct {
+	Symbol   string    `json:"symbol"`
+	Currency string    `json:"currency"` // ISO-4217 code
+	Interval string    `json:"interval"` // hourly, daily, weekly or monthly
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Candles  []ICandle `json:"candles"`
+	Source   string    `json:"source"` // market data provider that served the candles
+}
@@ -0,0 +1,44 @@
+// Package types provides type definitions for CoinMarketCap responses
+package types
+
+import (
+	"encoding/json"
+	"time"
+
+	"cry-api/app/money"
+)
+
+// PriceConversionResponse represents the CoinMarketCap price conversion payload
+// (v2/tools/price-conversion). Queried by symbol, data is an array of the assets sharing it;
+// queried by id, a single asset.
+type PriceConversionResponse struct {
+	Data json.RawMessage `json:"data"`
+}
+
+// PriceConversionAsset represents an amount of an asset converted to other currencies
+type PriceConversionAsset struct {
+	ID          int                          `json:"id"`
+	Symbol      string                       `json:"symbol"`
+	Name        string                       `json:"name"`
+	Amount      json.Number                  `json:"amount"`
+	LastUpdated time.Time                    `json:"last_updated"`
+	Quote       map[string]PriceConversionFX `json:"quote"`
+}
+
+// PriceConversionFX represents the converted amount in one currency. The price is kept as
+// the number it was sent as, so that it can be read exactly.
+type PriceConversionFX struct {
+	Price       json.Number `json:"price"`
+	LastUpdated time.Time   `json:"last_updated"`
+}
+
+// IConversion represents an amount of an asset valued in a fiat currency
+type IConversion struct {
+	Symbol      string       `json:"symbol"`
+	Amount      string       `json:"amount"`   // decimal amount of the asset
+	Currency    string       `json:"currency"` // ISO-4217 code
+	Rate        string       `json:"rate"`     // decimal price of one unit
+	Value       money.Amount `json:"value"`    // Amount * Rate
+	LastUpdated time.Time    `json:"last_updated"`
+	Source      string       `json:"source"` // market data provider that served the rate
+}
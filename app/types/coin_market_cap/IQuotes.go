@@ -0,0 +1,56 @@
+// Package types provides type definitions for CoinMarketCap responses
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AssetsResponse represents the CoinMarketCap payloads keyed by symbol or id
+// (v2/cryptocurrency/quotes/latest, v2/cryptocurrency/ohlcv/historical). Queried by symbol,
+// each key holds an array of the assets sharing that symbol; queried by id, a single asset.
+type AssetsResponse struct {
+	Data map[string]json.RawMessage `json:"data"`
+}
+
+// LatestQuoteAsset represents an asset of the latest quotes payload
+type LatestQuoteAsset struct {
+	ID          int                      `json:"id"`
+	Name        string                   `json:"name"`
+	Symbol      string                   `json:"symbol"`
+	Slug        string                   `json:"slug"`
+	CMCRank     int                      `json:"cmc_rank"`
+	LastUpdated time.Time                `json:"last_updated"`
+	Quote       map[string]LatestQuoteFX `json:"quote"`
+}
+
+// LatestQuoteFX represents the market data of an asset in one currency. Numbers are kept as
+// they were sent, so that they can be read exactly.
+type LatestQuoteFX struct {
+	Price            json.Number `json:"price"`
+	Volume24h        json.Number `json:"volume_24h"`
+	MarketCap        json.Number `json:"market_cap"`
+	PercentChange1h  json.Number `json:"percent_change_1h"`
+	PercentChange24h json.Number `json:"percent_change_24h"`
+	PercentChange7d  json.Number `json:"percent_change_7d"`
+	LastUpdated      time.Time   `json:"last_updated"`
+}
+
+// IQuote represents the latest market data of an asset in a fiat currency. Prices, volumes
+// and changes are exact decimals, e.g. "61234.5".
+type IQuote struct {
+	ID               int       `json:"id"` // CoinMarketCap id, 0 when served by another provider
+	Symbol           string    `json:"symbol"`
+	Name             string    `json:"name"`
+	Slug             string    `json:"slug"`
+	Rank             int       `json:"rank"`
+	Currency         string    `json:"currency"` // ISO-4217 code
+	Price            string    `json:"price"`    // of one unit
+	Volume24h        string    `json:"volume_24h"`
+	MarketCap        string    `json:"market_cap"`
+	PercentChange1h  string    `json:"percent_change_1h"`
+	PercentChange24h string    `json:"percent_change_24h"`
+	PercentChange7d  string    `json:"percent_change_7d"`
+	LastUpdated      time.Time `json:"last_updated"`
+	Source           string    `json:"source"` // market data provider that served the quote
+}
@@ -0,0 +1,16 @@
+// Package types provides type definitions for CoinMarketCap responses
+package types
+
+// Status represents the status block CoinMarketCap attaches to every response. The v3
+// endpoints send error_code as a string, the older ones as a number.
+type Status struct {
+	Timestamp    string      `json:"timestamp"`
+	ErrorCode    FlexibleInt `json:"error_code"`
+	ErrorMessage string      `json:"error_message"`
+	CreditCount  int         `json:"credit_count"`
+}
+
+// ErrorResponse represents the payload of a failed CoinMarketCap request
+type ErrorResponse struct {
+	Status Status `json:"status"`
+}
@@ -22,10 +22,80 @@ type BlockchainConfig struct {
 	API string
 }
 
+// MempoolConfig holds external API configuration for the mempool and fee estimation services.
+type MempoolConfig struct {
+	API        string
+	EsploraAPI string
+	Providers  []string // "mempool" and/or "esplora", tried in order
+}
+
+// BroadcastConfig holds the providers signed transactions are submitted to, in order of preference.
+type BroadcastConfig struct {
+	Providers []string // "mempool" and/or "blockchain"
+}
+
+// EVMConfig holds the JSON-RPC nodes of the EVM chains served by the EVM explorer.
+type EVMConfig struct {
+	Nodes         map[string]string // chain name to node URL
+	LogBlockRange int               // blocks searched for token transfers, counting back from the latest
+}
+
+// WatcherConfig holds the settings of the job notifying users about their watched wallets.
+type WatcherConfig struct {
+	Interval          int   // seconds between two checks of the watched wallets, 0 disables the job
+	Confirmations     int   // confirmations after which a transaction is reported as confirmed
+	LargeMovementSats int64 // balance change, in satoshis, reported as a large movement
+}
+
+// AlertConfig holds the settings of the users' market data alerts and of the job evaluating them.
+type AlertConfig struct {
+	Interval        int // seconds between two evaluations of the alert rules, 0 disables the job
+	DefaultCooldown int // minutes before a recurring rule can fire again, when the rule sets none
+	MaxRules        int // alert rules a user can have
+}
+
+// RealtimeConfig holds the settings of the SSE and WebSocket realtime gateway.
+type RealtimeConfig struct {
+	HeartbeatInterval int // seconds between two heartbeats sent to an idle connection
+	BufferSize        int // events queued per connection before it is dropped as too slow
+	MaxSubscriptions  int // topics a connection can subscribe to
+	PriceInterval     int // seconds between two polls of the subscribed prices and index, 0 disables them
+}
+
 // CoinMarketCapConfig holds external API configuration for coin market cap services.
 type CoinMarketCapConfig struct {
-	API    string
-	APIKey string
+	API                      string
+	APIKey                   string
+	FearGreedSyncInterval    int // seconds between two syncs of the local fear and greed history, 0 disables them
+	PriceHistorySyncInterval int // seconds between two syncs of the local BTC price history, 0 disables them
+}
+
+// MarketDataConfig holds the market data providers, in order of preference, and the APIs of
+// the providers other than CoinMarketCap.
+type MarketDataConfig struct {
+	Providers        []string // "coinmarketcap", "coingecko" and/or "alternative"
+	CoinGeckoAPI     string
+	CoinGeckoAPIKey  string // optional demo API key
+	AlternativeMeAPI string
+}
+
+// CacheConfig holds the configuration of the external API response cache.
+type CacheConfig struct {
+	Backend string // "memory", "sql" or "none"
+	Size    int    // maximum number of entries of the memory backend
+}
+
+// HTTPClientConfig holds the retry and circuit breaker settings of the outbound HTTP client.
+type HTTPClientConfig struct {
+	MaxRetries       int // extra attempts of idempotent upstream requests
+	BreakerThreshold int // consecutive failures opening the circuit breaker of a host
+	BreakerCooldown  int // seconds an open circuit breaker waits before probing again
+}
+
+// MetricsConfig holds the settings of the Prometheus metrics endpoint.
+type MetricsConfig struct {
+	Enabled bool   // serves /metrics
+	Token   string // bearer token the scrapers must send
 }
 
 // EnvConfig maps environment variables to application configuration fields.
@@ -43,7 +113,17 @@ type EnvConfig struct {
 	NoReplyEmail         string
 	WalletExplorerConfig WalletExplorerConfig
 	BlockchainConfig     BlockchainConfig
+	MempoolConfig        MempoolConfig
+	BroadcastConfig      BroadcastConfig
+	EVMConfig            EVMConfig
+	WatcherConfig        WatcherConfig
+	AlertConfig          AlertConfig
+	RealtimeConfig       RealtimeConfig
 	CoinMarketCapConfig  CoinMarketCapConfig
+	MarketDataConfig     MarketDataConfig
+	CacheConfig          CacheConfig
+	HTTPClientConfig     HTTPClientConfig
+	MetricsConfig        MetricsConfig
 }
 
 // Validate validates the configuration
@@ -94,5 +174,87 @@ func (c *EnvConfig) Validate() error {
 		return errors.New("SMTP_PORT is required")
 	}
 
+	switch c.CacheConfig.Backend {
+	case "", "memory", "sql", "none":
+	default:
+		return fmt.Errorf("CACHE_BACKEND must be memory, sql or none, got %q", c.CacheConfig.Backend)
+	}
+
+	for _, provider := range c.BroadcastConfig.Providers {
+		if provider != "mempool" && provider != "blockchain" {
+			return fmt.Errorf("BROADCAST_PROVIDERS must list mempool and/or blockchain, got %q", provider)
+		}
+	}
+
+	for _, provider := range c.MempoolConfig.Providers {
+		if provider != "mempool" && provider != "esplora" {
+			return fmt.Errorf("MEMPOOL_PROVIDERS must list mempool and/or esplora, got %q", provider)
+		}
+	}
+
+	if c.WatcherConfig.Interval < 0 {
+		return fmt.Errorf("WATCHER_INTERVAL must not be negative, got %d", c.WatcherConfig.Interval)
+	}
+
+	if c.WatcherConfig.Confirmations < 1 {
+		return fmt.Errorf("WATCHER_CONFIRMATIONS must be at least 1, got %d", c.WatcherConfig.Confirmations)
+	}
+
+	if c.AlertConfig.Interval < 0 {
+		return fmt.Errorf("ALERT_INTERVAL must not be negative, got %d", c.AlertConfig.Interval)
+	}
+
+	if c.AlertConfig.DefaultCooldown < 1 {
+		return fmt.Errorf("ALERT_DEFAULT_COOLDOWN must be at least 1, got %d", c.AlertConfig.DefaultCooldown)
+	}
+
+	if c.AlertConfig.MaxRules < 1 {
+		return fmt.Errorf("ALERT_MAX_RULES must be at least 1, got %d", c.AlertConfig.MaxRules)
+	}
+
+	if c.RealtimeConfig.HeartbeatInterval < 1 {
+		return fmt.Errorf("REALTIME_HEARTBEAT_INTERVAL must be at least 1, got %d", c.RealtimeConfig.HeartbeatInterval)
+	}
+
+	if c.RealtimeConfig.BufferSize < 1 {
+		return fmt.Errorf("REALTIME_BUFFER_SIZE must be at least 1, got %d", c.RealtimeConfig.BufferSize)
+	}
+
+	if c.RealtimeConfig.MaxSubscriptions < 1 {
+		return fmt.Errorf("REALTIME_MAX_SUBSCRIPTIONS must be at least 1, got %d", c.RealtimeConfig.MaxSubscriptions)
+	}
+
+	if c.RealtimeConfig.PriceInterval < 0 {
+		return fmt.Errorf("REALTIME_PRICE_INTERVAL must not be negative, got %d", c.RealtimeConfig.PriceInterval)
+	}
+
+	if c.CoinMarketCapConfig.FearGreedSyncInterval < 0 {
+		return fmt.Errorf("FEAR_GREED_SYNC_INTERVAL must not be negative, got %d", c.CoinMarketCapConfig.FearGreedSyncInterval)
+	}
+
+	if c.CoinMarketCapConfig.PriceHistorySyncInterval < 0 {
+		return fmt.Errorf("PRICE_HISTORY_SYNC_INTERVAL must not be negative, got %d", c.CoinMarketCapConfig.PriceHistorySyncInterval)
+	}
+
+	for _, provider := range c.MarketDataConfig.Providers {
+		if provider != "coinmarketcap" && provider != "coingecko" && provider != "alternative" {
+			return fmt.Errorf("MARKET_DATA_PROVIDERS must list coinmarketcap, coingecko and/or alternative, got %q", provider)
+		}
+	}
+
+	for chain, node := range c.EVMConfig.Nodes {
+		if chain == "" || node == "" {
+			return fmt.Errorf("EVM_RPC_URLS entries must be chain=url, got %q=%q", chain, node)
+		}
+	}
+
+	if c.HTTPClientConfig.MaxRetries < 0 {
+		return fmt.Errorf("HTTP_MAX_RETRIES must not be negative, got %d", c.HTTPClientConfig.MaxRetries)
+	}
+
+	if c.MetricsConfig.Enabled && c.MetricsConfig.Token == "" {
+		return errors.New("METRICS_TOKEN is required when METRICS_ENABLED is true")
+	}
+
 	return nil
 }
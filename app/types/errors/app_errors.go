@@ -2,13 +2,19 @@
 package errors
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"strings"
 )
 
-// AppError represents a custom application error
+// AppError represents a custom application error. Status is the HTTP status of the response
+// and Code the stable machine-readable code clients localize the error by.
 type AppError struct {
-	Code    int    `json:"code"`
+	Status  int    `json:"-"`
+	Code    string `json:"code"`
 	Message string `json:"message"`
 	Details string `json:"details,omitempty"`
 }
@@ -21,28 +27,52 @@ func (e *AppError) Error() string {
 	return e.Message
 }
 
-// NewAppError creates a new application error
-func NewAppError(code int, message, details string) *AppError {
+// base returns the AppError embedded in the typed errors
+func (e *AppError) base() *AppError {
+	return e
+}
+
+// NewAppError creates a new application error, coded after its HTTP status
+func NewAppError(status int, message, details string) *AppError {
+	return NewCodedError(status, StatusCode(status), message, details)
+}
+
+// NewCodedError creates a new application error with a specific code
+func NewCodedError(status int, code, message, details string) *AppError {
 	return &AppError{
+		Status:  status,
 		Code:    code,
 		Message: message,
 		Details: details,
 	}
 }
 
-// ValidationError represents validation errors
+// NewUpstreamError creates the error of a failed upstream call, coded UPSTREAM_TIMEOUT when
+// the call timed out and UPSTREAM_ERROR otherwise. The upstream error is left out of the
+// response; callers log it.
+func NewUpstreamError(message string, err error) *AppError {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return NewCodedError(http.StatusGatewayTimeout, CodeUpstreamTimeout, message, "")
+	}
+	return NewCodedError(http.StatusBadGateway, CodeUpstreamError, message, "")
+}
+
+// ValidationError represents validation errors. Fields lists the invalid request fields.
 type ValidationError struct {
 	*AppError
-	Field string `json:"field,omitempty"`
-	Value string `json:"value,omitempty"`
+	Field  string       `json:"field,omitempty"`
+	Value  string       `json:"value,omitempty"`
+	Fields []FieldError `json:"fields,omitempty"`
 }
 
-// NewValidationError creates a new validation error
+// NewValidationError creates a new validation error on a single field
 func NewValidationError(field, value, message string) *ValidationError {
 	return &ValidationError{
-		AppError: NewAppError(http.StatusBadRequest, message, ""),
+		AppError: NewCodedError(http.StatusBadRequest, CodeValidationFailed, message, ""),
 		Field:    field,
 		Value:    value,
+		Fields:   []FieldError{{Field: field, Code: FieldInvalid, Message: message, Value: value}},
 	}
 }
 
@@ -52,13 +82,13 @@ type NotFoundError struct {
 	Resource string `json:"resource,omitempty"`
 }
 
-// NewNotFoundError creates a new not found error
+// NewNotFoundError creates a new not found error, coded <RESOURCE>_NOT_FOUND
 func NewNotFoundError(resource, message string) *NotFoundError {
 	if message == "" {
 		message = fmt.Sprintf("%s not found", resource)
 	}
 	return &NotFoundError{
-		AppError: NewAppError(http.StatusNotFound, message, ""),
+		AppError: NewCodedError(http.StatusNotFound, resourceCode(resource, "NOT_FOUND"), message, ""),
 		Resource: resource,
 	}
 }
@@ -84,13 +114,13 @@ type ConflictError struct {
 	Resource string `json:"resource,omitempty"`
 }
 
-// NewConflictError creates a new conflict error
+// NewConflictError creates a new conflict error, coded <RESOURCE>_CONFLICT
 func NewConflictError(resource, message string) *ConflictError {
 	if message == "" {
 		message = fmt.Sprintf("%s already exists", resource)
 	}
 	return &ConflictError{
-		AppError: NewAppError(http.StatusConflict, message, ""),
+		AppError: NewCodedError(http.StatusConflict, resourceCode(resource, "CONFLICT"), message, ""),
 		Resource: resource,
 	}
 }
@@ -110,12 +140,21 @@ func NewInternalServerError(message string) *InternalServerError {
 	}
 }
 
+// resourceCode builds the code of an error on a resource, e.g. WATCHED_WALLET_NOT_FOUND
+func resourceCode(resource, suffix string) string {
+	resource = strings.ToUpper(strings.NewReplacer(" ", "_", "-", "_").Replace(strings.TrimSpace(resource)))
+	if resource == "" {
+		return suffix
+	}
+	return resource + "_" + suffix
+}
+
 // Predefined common errors
 var (
-	ErrInvalidJSON     = NewAppError(http.StatusBadRequest, "Invalid JSON format", "")
-	ErrInvalidInput    = NewAppError(http.StatusBadRequest, "Invalid input", "")
-	ErrDatabaseError   = NewInternalServerError("Database operation failed")
-	ErrEmailSendFailed = NewInternalServerError("Failed to send email")
-	ErrTokenGeneration = NewInternalServerError("Failed to generate token")
-	ErrTokenValidation = NewUnauthorizedError("Invalid or expired token")
+	ErrInvalidJSON     = NewCodedError(http.StatusBadRequest, CodeInvalidJSON, "Invalid JSON format", "")
+	ErrInvalidInput    = NewCodedError(http.StatusBadRequest, CodeInvalidInput, "Invalid input", "")
+	ErrDatabaseError   = &InternalServerError{NewCodedError(http.StatusInternalServerError, CodeDatabaseError, "Database operation failed", "")}
+	ErrEmailSendFailed = &InternalServerError{NewCodedError(http.StatusInternalServerError, CodeEmailSendFailed, "Failed to send email", "")}
+	ErrTokenGeneration = &InternalServerError{NewCodedError(http.StatusInternalServerError, CodeTokenGenerationFailed, "Failed to generate token", "")}
+	ErrTokenValidation = &UnauthorizedError{NewCodedError(http.StatusUnauthorized, CodeAuthInvalidToken, "Invalid or expired token", "")}
 )
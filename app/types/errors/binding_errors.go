@@ -0,0 +1,87 @@
+// Package errors converts request binding failures to validation errors
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// NewBindingError converts the error of binding a request body into req to a validation error
+// listing the invalid fields by their JSON names
+func NewBindingError(err error, req any) *ValidationError {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var fieldErrs validator.ValidationErrors
+
+	switch {
+	case errors.As(err, &fieldErrs):
+		result := &ValidationError{AppError: NewCodedError(http.StatusBadRequest, CodeValidationFailed, "Invalid input", "")}
+		for _, fe := range fieldErrs {
+			name := jsonName(req, fe.StructNamespace())
+			result.Fields = append(result.Fields, fieldError(name, fe.Tag()))
+		}
+		if len(result.Fields) > 0 {
+			result.Field = result.Fields[0].Field
+		}
+		return result
+	case errors.As(err, &typeErr):
+		message := "Must be a " + typeErr.Type.String()
+		return &ValidationError{
+			AppError: NewCodedError(http.StatusBadRequest, CodeInvalidJSON, "Invalid JSON format", ""),
+			Field:    typeErr.Field,
+			Fields:   []FieldError{{Field: typeErr.Field, Code: FieldType, Message: message}},
+		}
+	case errors.As(err, &syntaxErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return &ValidationError{AppError: NewCodedError(http.StatusBadRequest, CodeInvalidJSON, "Invalid JSON format", "")}
+	default:
+		return &ValidationError{AppError: NewCodedError(http.StatusBadRequest, CodeInvalidInput, "Invalid input", "")}
+	}
+}
+
+// fieldError describes the failure of a validation tag on a field
+func fieldError(field, tag string) FieldError {
+	switch tag {
+	case "required":
+		return FieldError{Field: field, Code: FieldRequired, Message: "This field is required"}
+	case "email":
+		return FieldError{Field: field, Code: FieldEmail, Message: "Must be a valid email address"}
+	default:
+		return FieldError{Field: field, Code: FieldInvalid, Message: "This field is invalid"}
+	}
+}
+
+// jsonName returns the JSON path of the struct field at namespace, such as
+// "IUserSigninRequest.Email", falling back to the Go field names
+func jsonName(req any, namespace string) string {
+	parts := strings.Split(namespace, ".")
+	if len(parts) > 1 {
+		parts = parts[1:]
+	}
+
+	t := reflect.TypeOf(req)
+	names := make([]string, 0, len(parts))
+	for _, part := range parts {
+		for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			t = t.Elem()
+		}
+		name := part
+		if t != nil && t.Kind() == reflect.Struct {
+			if field, ok := t.FieldByName(strings.SplitN(part, "[", 2)[0]); ok {
+				if tag := strings.Split(field.Tag.Get("json"), ",")[0]; tag != "" && tag != "-" {
+					name = tag
+				}
+				t = field.Type
+			} else {
+				t = nil
+			}
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ".")
+}
@@ -0,0 +1,88 @@
+// Package errors defines the stable error codes of the API
+package errors
+
+import "net/http"
+
+// Error codes sent in the code field of error responses. They never change once released, so
+// clients can localize their messages. Errors on a resource are coded after it, such as
+// WATCHED_WALLET_NOT_FOUND or USERNAME_CONFLICT.
+const (
+	// Request errors
+	CodeBadRequest           = "BAD_REQUEST"
+	CodeInvalidJSON          = "INVALID_JSON"
+	CodeInvalidInput         = "INVALID_INPUT"
+	CodeValidationFailed     = "VALIDATION_FAILED"
+	CodeNotFound             = "NOT_FOUND"
+	CodeRouteNotFound        = "ROUTE_NOT_FOUND"
+	CodeMethodNotAllowed     = "METHOD_NOT_ALLOWED"
+	CodeConflict             = "CONFLICT"
+	CodePayloadTooLarge      = "PAYLOAD_TOO_LARGE"
+	CodeUnsupportedMediaType = "UNSUPPORTED_MEDIA_TYPE"
+	CodeRateLimited          = "RATE_LIMITED"
+	CodeExportTooLarge       = "EXPORT_TOO_LARGE"
+
+	// Authentication errors
+	CodeUnauthorized             = "UNAUTHORIZED"
+	CodeForbidden                = "FORBIDDEN"
+	CodeAuthMissingToken         = "AUTH_MISSING_TOKEN"
+	CodeAuthInvalidToken         = "AUTH_INVALID_TOKEN"
+	CodeAuthTwoFactorRequired    = "AUTH_TWO_FACTOR_REQUIRED"
+	CodeAuthInvalidCredentials   = "AUTH_INVALID_CREDENTIALS"
+	CodeAuthUserNotVerified      = "AUTH_USER_NOT_VERIFIED"
+	CodeOTPInvalid               = "OTP_INVALID"
+	CodeOTPExpired               = "OTP_EXPIRED"
+	CodeTwoFactorNotSetUp        = "TWO_FACTOR_NOT_SET_UP"
+	CodeTwoFactorNotEnabled      = "TWO_FACTOR_NOT_ENABLED"
+	CodeVerificationTokenInvalid = "VERIFICATION_TOKEN_INVALID"
+	CodeVerificationTokenExpired = "VERIFICATION_TOKEN_EXPIRED"
+	CodeUserAlreadyExists        = "USER_ALREADY_EXISTS"
+
+	// Server errors
+	CodeInternal              = "INTERNAL_ERROR"
+	CodeDatabaseError         = "DATABASE_ERROR"
+	CodeEmailSendFailed       = "EMAIL_SEND_FAILED"
+	CodeTokenGenerationFailed = "TOKEN_GENERATION_FAILED"
+	CodeUpstreamError         = "UPSTREAM_ERROR"
+	CodeUpstreamTimeout       = "UPSTREAM_TIMEOUT"
+	CodeServiceUnavailable    = "SERVICE_UNAVAILABLE"
+)
+
+// Codes of the field errors listed in validation errors
+const (
+	FieldRequired = "REQUIRED"
+	FieldInvalid  = "INVALID"
+	FieldEmail    = "EMAIL"
+	FieldType     = "TYPE"
+)
+
+// StatusCode returns the generic code of an HTTP error status
+func StatusCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusRequestEntityTooLarge:
+		return CodePayloadTooLarge
+	case http.StatusUnsupportedMediaType:
+		return CodeUnsupportedMediaType
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusBadGateway:
+		return CodeUpstreamError
+	case http.StatusServiceUnavailable:
+		return CodeServiceUnavailable
+	case http.StatusGatewayTimeout:
+		return CodeUpstreamTimeout
+	default:
+		return CodeInternal
+	}
+}
@@ -0,0 +1,61 @@
+// Package errors defines the envelope of error responses
+package errors
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrorResponse is the body of every error response
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+// ErrorBody describes an error. Details and Fields are only set when known, and RequestID
+// matches the X-Request-ID header of the response.
+type ErrorBody struct {
+	Code      string       `json:"code"`
+	Message   string       `json:"message"`
+	Details   string       `json:"details,omitempty"`
+	Fields    []FieldError `json:"fields,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+}
+
+// FieldError describes an invalid request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Value   string `json:"value,omitempty"`
+}
+
+// appError is implemented by AppError and the typed errors embedding it
+type appError interface {
+	base() *AppError
+}
+
+// Envelope returns the HTTP status and the body describing an error. Errors other than
+// application errors are reported as internal errors, without their text.
+func Envelope(err error) (int, ErrorBody) {
+	var app appError
+	if !errors.As(err, &app) {
+		return http.StatusInternalServerError, ErrorBody{Code: CodeInternal, Message: "Internal server error"}
+	}
+
+	e := app.base()
+	body := ErrorBody{Code: e.Code, Message: e.Message, Details: e.Details}
+	if body.Code == "" {
+		body.Code = StatusCode(e.Status)
+	}
+
+	var validation *ValidationError
+	if errors.As(err, &validation) {
+		body.Fields = validation.Fields
+	}
+
+	status := e.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	return status, body
+}
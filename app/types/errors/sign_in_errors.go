@@ -1,11 +1,13 @@
 // Package errors defines error msgs
 package errors
 
+import "net/http"
+
 var (
 	// ErrUserNotFound returns "user not found" as error
 	ErrUserNotFound = NewNotFoundError("User", "user not found")
 	// ErrInvalidPassword returns "invalid password" as error
-	ErrInvalidPassword = NewUnauthorizedError("invalid password")
+	ErrInvalidPassword = &UnauthorizedError{NewCodedError(http.StatusUnauthorized, CodeAuthInvalidCredentials, "invalid password", "")}
 	// ErrUserNotVerified returns "user not verified" as error
-	ErrUserNotVerified = NewUnauthorizedError("user not verified")
+	ErrUserNotVerified = &UnauthorizedError{NewCodedError(http.StatusUnauthorized, CodeAuthUserNotVerified, "user not verified", "")}
 )
@@ -1,5 +1,10 @@
 // Package errors defines error msgs
 package errors
 
+import "net/http"
+
 // ErrUserConflict returns "user already exists" as error
-var ErrUserConflict = NewConflictError("User", "User already exists")
+var ErrUserConflict = &ConflictError{
+	AppError: NewCodedError(http.StatusConflict, CodeUserAlreadyExists, "User already exists", ""),
+	Resource: "User",
+}
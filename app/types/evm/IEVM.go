@@ -0,0 +1,118 @@
+// Package types provides type definitions for EVM explorer responses.
+package types
+
+import "cry-api/app/money"
+
+// Execution statuses of an EVM transaction
+const (
+	TxStatusSuccess = "success"
+	TxStatusFailed  = "failed"
+	TxStatusPending = "pending"
+)
+
+// Directions of a token transfer relative to the queried address
+const (
+	DirectionIn   = "in"
+	DirectionOut  = "out"
+	DirectionSelf = "self"
+)
+
+// IEVMTransaction represents a transaction on an EVM chain with its receipt. Amounts are in
+// base units (wei or token units) as decimal strings, with a formatted copy when the
+// decimals are known.
+type IEVMTransaction struct {
+	Chain             string           `json:"chain"`
+	Hash              string           `json:"hash"`
+	Status            string           `json:"status"` // success, failed or pending
+	BlockNumber       *uint64          `json:"block_number"`
+	BlockHash         string           `json:"block_hash,omitempty"`
+	From              string           `json:"from"`
+	To                *string          `json:"to"` // nil for contract creations
+	ContractAddress   *string          `json:"contract_address,omitempty"`
+	Nonce             uint64           `json:"nonce"`
+	Value             string           `json:"value"`
+	ValueAmount       string           `json:"value_amount"`
+	Symbol            string           `json:"symbol"`
+	GasLimit          uint64           `json:"gas_limit"`
+	GasUsed           *uint64          `json:"gas_used,omitempty"`
+	EffectiveGasPrice string           `json:"effective_gas_price,omitempty"`
+	Fee               string           `json:"fee,omitempty"`
+	FeeAmount         string           `json:"fee_amount,omitempty"`
+	TokenTransfers    []ITokenTransfer `json:"token_transfers"`
+}
+
+// ITokenTransfer represents a decoded ERC-20 Transfer event
+type ITokenTransfer struct {
+	Token       string `json:"token"`
+	Symbol      string `json:"symbol,omitempty"`
+	Decimals    *int   `json:"decimals,omitempty"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Value       string `json:"value"`
+	Amount      string `json:"amount,omitempty"`
+	Direction   string `json:"direction,omitempty"` // in, out or self, relative to the queried address
+	TxHash      string `json:"tx_hash"`
+	BlockNumber uint64 `json:"block_number"`
+	LogIndex    uint64 `json:"log_index"`
+}
+
+// IAssetBalance represents the balance of the native coin or of an ERC-20 token
+type IAssetBalance struct {
+	Token    string        `json:"token,omitempty"` // empty for the native coin
+	Symbol   string        `json:"symbol,omitempty"`
+	Decimals *int          `json:"decimals,omitempty"`
+	Balance  string        `json:"balance"`
+	Amount   string        `json:"amount,omitempty"`
+	Fiat     *money.Amount `json:"fiat,omitempty"` // value of Amount, in the requested or the user's fiat currency
+}
+
+// IEVMBalances represents the native and token balances of an address
+type IEVMBalances struct {
+	Chain       string          `json:"chain"`
+	Address     string          `json:"address"`
+	BlockNumber uint64          `json:"block_number"`
+	Native      IAssetBalance   `json:"native"`
+	Tokens      []IAssetBalance `json:"tokens"`
+}
+
+// IEVMTransfers represents the ERC-20 transfers of an address over a range of blocks
+type IEVMTransfers struct {
+	Chain     string           `json:"chain"`
+	Address   string           `json:"address"`
+	FromBlock uint64           `json:"from_block"`
+	ToBlock   uint64           `json:"to_block"`
+	Transfers []ITokenTransfer `json:"transfers"`
+}
+
+// RPCTransaction represents the result of eth_getTransactionByHash
+type RPCTransaction struct {
+	Hash        string  `json:"hash"`
+	BlockHash   *string `json:"blockHash"`
+	BlockNumber *string `json:"blockNumber"`
+	From        string  `json:"from"`
+	To          *string `json:"to"`
+	Nonce       string  `json:"nonce"`
+	Value       string  `json:"value"`
+	Gas         string  `json:"gas"`
+	GasPrice    string  `json:"gasPrice"`
+}
+
+// RPCReceipt represents the result of eth_getTransactionReceipt
+type RPCReceipt struct {
+	Status            string   `json:"status"`
+	GasUsed           string   `json:"gasUsed"`
+	EffectiveGasPrice string   `json:"effectiveGasPrice"`
+	ContractAddress   *string  `json:"contractAddress"`
+	Logs              []RPCLog `json:"logs"`
+}
+
+// RPCLog represents an event log of eth_getLogs and of transaction receipts
+type RPCLog struct {
+	Address         string   `json:"address"`
+	Topics          []string `json:"topics"`
+	Data            string   `json:"data"`
+	BlockNumber     string   `json:"blockNumber"`
+	TransactionHash string   `json:"transactionHash"`
+	LogIndex        string   `json:"logIndex"`
+	Removed         bool     `json:"removed"`
+}
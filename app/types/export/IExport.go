@@ -0,0 +1,26 @@
+// Package types provides type definitions for transaction history exports.
+package types
+
+import "cry-api/app/money"
+
+// IExportOptions represents the validated options of an export request
+type IExportOptions struct {
+	Format   string   // "csv", "jsonl" or "ofx"
+	Columns  []string // CSV columns, in order
+	Currency string   // supported ISO-4217 fiat code used for prices and fiat values
+}
+
+// IExportRow represents a single transaction of an exported history
+type IExportRow struct {
+	Date        string       `json:"date"` // RFC3339, UTC
+	Time        int64        `json:"time"`
+	TxID        string       `json:"txid"`
+	BlockHeight int          `json:"block_height"`
+	Amount      float64      `json:"amount"`  // BTC, negative when funds left the wallet
+	Fee         *float64     `json:"fee"`     // BTC, only known for outgoing address transactions
+	Balance     float64      `json:"balance"` // BTC, running balance after the transaction
+	Price       money.Amount `json:"price"`   // fiat per BTC on the day of the transaction
+	FiatValue   money.Amount `json:"fiat_value"`
+	Currency    string       `json:"currency"`
+	Wallet      string       `json:"wallet,omitempty"` // watched wallet label, watchlist exports only
+}
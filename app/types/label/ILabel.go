@@ -0,0 +1,26 @@
+// Package types provides type definitions for BIP-329 transaction and address labels.
+package types
+
+// ILabelRecord is a BIP-329 label record. It is both the payload for setting a label and
+// one line of a BIP-329 JSON Lines import or export.
+type ILabelRecord struct {
+	Type      string `json:"type" binding:"required"`
+	Ref       string `json:"ref" binding:"required"`
+	Label     string `json:"label"`
+	Origin    string `json:"origin,omitempty"`
+	Spendable *bool  `json:"spendable,omitempty"` // outputs only
+}
+
+// IImportError reports a rejected line of a BIP-329 import
+type IImportError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// IImportResult summarizes a BIP-329 import
+type IImportResult struct {
+	Imported int            `json:"imported"` // new labels
+	Updated  int            `json:"updated"`  // existing labels overwritten
+	Skipped  int            `json:"skipped"`  // records of a type this API does not store
+	Errors   []IImportError `json:"errors"`
+}
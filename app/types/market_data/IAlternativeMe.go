@@ -0,0 +1,19 @@
+// Package types provides type definitions for the responses of the market data providers
+// other than CoinMarketCap
+package types
+
+// AlternativeMeFearGreed represents the alternative.me fear and greed payload (fng), newest first
+type AlternativeMeFearGreed struct {
+	Data     []AlternativeMeFearGreedPoint `json:"data"`
+	Metadata struct {
+		Error *string `json:"error"`
+	} `json:"metadata"`
+}
+
+// AlternativeMeFearGreedPoint represents a day of the alternative.me fear and greed index.
+// Numbers are sent as strings.
+type AlternativeMeFearGreedPoint struct {
+	Value               string `json:"value"`
+	ValueClassification string `json:"value_classification"`
+	Timestamp           string `json:"timestamp"` // UNIX seconds
+}
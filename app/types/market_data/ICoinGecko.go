@@ -0,0 +1,31 @@
+// Package types provides type definitions for the responses of the market data providers
+// other than CoinMarketCap
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CoinGeckoMarket represents an entry of the CoinGecko markets payload (coins/markets).
+// Numbers are kept as they were sent, so that they can be read exactly.
+type CoinGeckoMarket struct {
+	ID                                 string      `json:"id"`
+	Symbol                             string      `json:"symbol"`
+	Name                               string      `json:"name"`
+	CurrentPrice                       json.Number `json:"current_price"`
+	MarketCap                          json.Number `json:"market_cap"`
+	MarketCapRank                      int         `json:"market_cap_rank"`
+	TotalVolume                        json.Number `json:"total_volume"`
+	PriceChangePercentage1hInCurrency  json.Number `json:"price_change_percentage_1h_in_currency"`
+	PriceChangePercentage24hInCurrency json.Number `json:"price_change_percentage_24h_in_currency"`
+	PriceChangePercentage7dInCurrency  json.Number `json:"price_change_percentage_7d_in_currency"`
+	LastUpdated                        time.Time   `json:"last_updated"`
+}
+
+// CoinGeckoMarketChart represents the CoinGecko market chart payload (coins/{id}/market_chart/range).
+// Each sample is a [unix milliseconds, value] pair.
+type CoinGeckoMarketChart struct {
+	Prices     [][2]json.Number `json:"prices"`
+	MarketCaps [][2]json.Number `json:"market_caps"`
+}
@@ -0,0 +1,33 @@
+// Package types provides type definitions for portfolio valuation.
+package types
+
+import (
+	"time"
+
+	"cry-api/app/money"
+)
+
+// IPortfolioPoint represents the portfolio value at the end of a UTC day
+type IPortfolioPoint struct {
+	Date    string       `json:"date"`    // YYYY-MM-DD
+	Balance float64      `json:"balance"` // BTC
+	Price   money.Amount `json:"price"`   // per BTC
+	Value   money.Amount `json:"value"`
+}
+
+// IPortfolioValuation represents the value over time, cost basis and unrealized P&L
+// of everything on a user's watchlist. Money values are exact, in the minor units of Currency.
+type IPortfolioValuation struct {
+	Currency             string            `json:"currency"`
+	From                 string            `json:"from"`
+	To                   string            `json:"to"`
+	Balance              float64           `json:"balance"` // BTC
+	Price                money.Amount      `json:"price"`   // per BTC
+	Value                money.Amount      `json:"value"`
+	CostBasis            money.Amount      `json:"cost_basis"`
+	UnrealizedPnL        money.Amount      `json:"unrealized_pnl"`
+	UnrealizedPnLPercent *float64          `json:"unrealized_pnl_percent"`
+	Points               []IPortfolioPoint `json:"points"`
+	Warnings             []string          `json:"warnings,omitempty"`
+	GeneratedAt          time.Time         `json:"generated_at"`
+}
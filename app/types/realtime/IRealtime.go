@@ -0,0 +1,42 @@
+// Package types provides type definitions for the realtime gateway
+package types
+
+import "time"
+
+// Message types sent to the realtime clients
+const (
+	MessageEvent        = "event"
+	MessageSubscribed   = "subscribed"
+	MessageUnsubscribed = "unsubscribed"
+	MessageError        = "error"
+)
+
+// IEvent is an update pushed to the connections subscribed to its topic
+type IEvent struct {
+	Type  string    `json:"type"` // always "event"
+	ID    uint64    `json:"id"`   // increases with every event of the hub
+	Topic string    `json:"topic"`
+	Data  any       `json:"data"`
+	Time  time.Time `json:"time"`
+}
+
+// IClientMessage changes the subscriptions of a WebSocket connection
+type IClientMessage struct {
+	Action string   `json:"action"` // "subscribe" or "unsubscribe"
+	Topics []string `json:"topics"`
+}
+
+// IControlMessage answers a subscription change, or reports an error or why the server is
+// closing the connection
+type IControlMessage struct {
+	Type    string   `json:"type"`
+	Topics  []string `json:"topics,omitempty"`
+	Message string   `json:"message,omitempty"`
+}
+
+// IHubStats counts the connections of the realtime hub
+type IHubStats struct {
+	Connections   int    `json:"connections"`
+	Subscriptions int    `json:"subscriptions"`
+	Dropped       uint64 `json:"dropped"` // connections dropped for not keeping up with their events
+}
@@ -0,0 +1,55 @@
+// Package types provides type definitions for cost-basis and capital-gains reports.
+package types
+
+import (
+	"time"
+
+	"cry-api/app/money"
+)
+
+// ITransferTagRequest represents the payload for tagging a transaction as an own-wallet transfer
+type ITransferTagRequest struct {
+	TxID string `json:"txid" binding:"required"`
+	Note string `json:"note"`
+}
+
+// IDisposal represents the part of a disposal matched against a single acquisition lot
+type IDisposal struct {
+	TxID        string       `json:"txid"`
+	AcquiredTx  string       `json:"acquired_txid"`
+	Acquired    string       `json:"acquired"` // YYYY-MM-DD
+	Disposed    string       `json:"disposed"` // YYYY-MM-DD
+	Amount      float64      `json:"amount"`   // BTC
+	Proceeds    money.Amount `json:"proceeds"`
+	CostBasis   money.Amount `json:"cost_basis"`
+	Gain        money.Amount `json:"gain"`
+	Term        string       `json:"term"` // "short" or "long"
+	Unmatched   bool         `json:"unmatched,omitempty"`
+	HoldingDays int          `json:"holding_days"`
+}
+
+// IGainTotals represents the sums of a group of disposals, in the currency of the report
+type IGainTotals struct {
+	Proceeds  money.Amount `json:"proceeds"`
+	CostBasis money.Amount `json:"cost_basis"`
+	Gain      money.Amount `json:"gain"`
+}
+
+// ITaxYear represents the realized gains of one calendar year
+type ITaxYear struct {
+	Year      int         `json:"year"`
+	ShortTerm IGainTotals `json:"short_term"`
+	LongTerm  IGainTotals `json:"long_term"`
+	Total     IGainTotals `json:"total"`
+	Disposals []IDisposal `json:"disposals"`
+}
+
+// ITaxReport represents a capital-gains report for a watchlist or a single watched wallet
+type ITaxReport struct {
+	Method      string     `json:"method"`
+	Currency    string     `json:"currency"`
+	WalletID    *int       `json:"wallet_id,omitempty"`
+	Years       []ITaxYear `json:"years"`
+	Warnings    []string   `json:"warnings,omitempty"`
+	GeneratedAt time.Time  `json:"generated_at"`
+}
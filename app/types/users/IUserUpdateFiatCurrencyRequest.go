@@ -0,0 +1,7 @@
+// Package types provides type definitions for user settings update requests.
+package types
+
+// IUserUpdateFiatCurrencyRequest represents the payload required for changing the fiat currency money values are converted to.
+type IUserUpdateFiatCurrencyRequest struct {
+	FiatCurrency string `json:"fiat_currency" binding:"required"`
+}
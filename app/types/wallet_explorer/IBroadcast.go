@@ -0,0 +1,16 @@
+// Package types provides type definitions for wallet explorer responses.
+package types
+
+// IBroadcastRequest represents a request to broadcast a signed raw transaction
+type IBroadcastRequest struct {
+	Tx string `json:"tx" binding:"required"` // raw transaction hex
+}
+
+// IBroadcastResult represents a transaction accepted by a broadcast provider
+type IBroadcastResult struct {
+	TxID     string  `json:"txid"`
+	Provider string  `json:"provider"`
+	Fee      int64   `json:"fee"`      // sats
+	VSize    int     `json:"vsize"`    // vbytes
+	FeeRate  float64 `json:"fee_rate"` // sat/vB
+}
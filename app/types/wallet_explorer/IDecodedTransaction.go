@@ -0,0 +1,53 @@
+// Package types provides type definitions for wallet explorer requests and responses.
+package types
+
+// IDecodeTransactionRequest represents the payload for decoding a raw transaction or PSBT
+type IDecodeTransactionRequest struct {
+	Payload string `json:"payload" binding:"required"` // raw tx hex, or PSBT as base64/hex
+	Network string `json:"network,omitempty"`          // "mainnet" (default) or "testnet"
+}
+
+// IDecodedTransaction represents a locally decoded transaction alongside the
+// normalized ITransactionData model returned by /wallet-explorer/tx
+type IDecodedTransaction struct {
+	Format      string `json:"format"` // "raw" or "psbt"
+	PSBTVersion *int   `json:"psbt_version,omitempty"`
+	Network     string `json:"network"`
+	TxID        string `json:"txid"`
+	WTxID       string `json:"wtxid"`
+	VSize       int    `json:"vsize"`
+	// EstimatedVSize is the vsize of a PSBT once every input is finalized, estimated from the
+	// script types of the inputs not finalized yet
+	EstimatedVSize *int     `json:"estimated_vsize,omitempty"`
+	FeeRate        *float64 `json:"fee_rate,omitempty"` // sat/vB, when the fee is known; of the estimated vsize for PSBTs
+	// FeeRateUpperBound is set when the signed vsize of a PSBT could not be estimated and the fee
+	// rate is computed from the unsigned transaction, which overstates it
+	FeeRateUpperBound bool             `json:"fee_rate_upper_bound,omitempty"`
+	Complete          bool             `json:"complete"` // every input is signed or finalized
+	Transaction       ITransactionData `json:"transaction"`
+	Inputs            []IDecodedInput  `json:"inputs"`
+	Outputs           []IDecodedOutput `json:"outputs"`
+}
+
+// IDecodedInput represents the signing state of a single decoded input
+type IDecodedInput struct {
+	Index              int      `json:"index"`
+	PrevTxID           string   `json:"prev_txid"`
+	PrevVout           uint32   `json:"prev_vout"`
+	Value              *int64   `json:"value,omitempty"`
+	ScriptType         string   `json:"script_type,omitempty"`
+	Address            *string  `json:"address,omitempty"`
+	Sighash            []string `json:"sighash"`
+	SignatureStatus    string   `json:"signature_status"` // finalized, signed, partial, unsigned or unknown
+	Signatures         int      `json:"signatures"`
+	RequiredSignatures int      `json:"required_signatures"`
+	MissingSignatures  int      `json:"missing_signatures"`
+}
+
+// IDecodedOutput represents the decoded script of a single output
+type IDecodedOutput struct {
+	Index      int     `json:"index"`
+	Value      int64   `json:"value"`
+	ScriptType string  `json:"script_type"`
+	Address    *string `json:"address,omitempty"`
+}
@@ -0,0 +1,28 @@
+// Package types provides type definitions for wallet explorer requests and responses.
+package types
+
+// INormalizedDescriptor represents an xpub/ypub/zpub or output descriptor normalized
+// into a canonical descriptor with its first derived addresses
+type INormalizedDescriptor struct {
+	InputType        string            `json:"input_type"` // "descriptor" or "extended_key"
+	Descriptor       string            `json:"descriptor"`
+	ChangeDescriptor *string           `json:"change_descriptor,omitempty"`
+	ScriptType       string            `json:"script_type"`
+	Network          string            `json:"network"`
+	IsRange          bool              `json:"is_range"`
+	Addresses        []IDerivedAddress `json:"addresses"`
+}
+
+// IDerivedAddress represents a single address derived from a descriptor
+type IDerivedAddress struct {
+	Index        uint32 `json:"index"`
+	Address      string `json:"address"`
+	ScriptPubKey string `json:"script_pubkey"`
+}
+
+// IConvertedExtendedKey represents an extended public key re-encoded with other SLIP-132 version bytes
+type IConvertedExtendedKey struct {
+	Input  string `json:"input"`
+	Prefix string `json:"prefix"`
+	Output string `json:"output"`
+}
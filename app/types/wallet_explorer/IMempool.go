@@ -0,0 +1,92 @@
+// Package types provides type definitions for wallet explorer responses.
+package types
+
+// Mempool statuses of a transaction
+const (
+	MempoolStatusConfirmed = "confirmed"
+	MempoolStatusPending   = "mempool"
+	MempoolStatusReplaced  = "replaced"
+)
+
+// IFeeEstimates represents recommended fee rates in sat/vB
+type IFeeEstimates struct {
+	Fastest  float64 `json:"fastest"`   // next block
+	HalfHour float64 `json:"half_hour"` // within about 3 blocks
+	Hour     float64 `json:"hour"`      // within about 6 blocks
+	Economy  float64 `json:"economy"`
+	Minimum  float64 `json:"minimum"` // the mempool purge rate
+}
+
+// IBlockTip represents the block at the tip of the chain
+type IBlockTip struct {
+	Height int    `json:"height"`
+	Hash   string `json:"hash"`
+}
+
+// IMempoolStatus represents the confirmation state of a transaction
+type IMempoolStatus struct {
+	TxID          string   `json:"txid"`
+	Status        string   `json:"status"` // confirmed, mempool or replaced
+	Confirmations int      `json:"confirmations"`
+	BlockHeight   int      `json:"block_height,omitempty"`
+	BlockHash     string   `json:"block_hash,omitempty"`
+	BlockTime     int64    `json:"block_time,omitempty"`
+	Fee           int64    `json:"fee,omitempty"`      // sats
+	VSize         float64  `json:"vsize,omitempty"`    // vbytes
+	FeeRate       float64  `json:"fee_rate,omitempty"` // sat/vB
+	SignalsRBF    bool     `json:"signals_rbf"`        // BIP125 opt-in through an input sequence
+	ReplacedBy    string   `json:"replaced_by,omitempty"`
+	Replaces      []string `json:"replaces,omitempty"`
+}
+
+// MempoolFees represents the payload of the mempool.space recommended fees endpoint
+type MempoolFees struct {
+	FastestFee  float64 `json:"fastestFee"`
+	HalfHourFee float64 `json:"halfHourFee"`
+	HourFee     float64 `json:"hourFee"`
+	EconomyFee  float64 `json:"economyFee"`
+	MinimumFee  float64 `json:"minimumFee"`
+}
+
+// MempoolTransaction represents the payload of the mempool.space transaction endpoint
+type MempoolTransaction struct {
+	TxID   string               `json:"txid"`
+	Weight int                  `json:"weight"`
+	Fee    int64                `json:"fee"`
+	Vin    []MempoolInput       `json:"vin"`
+	Vout   []MempoolOutput      `json:"vout"`
+	Status MempoolConfirmations `json:"status"`
+}
+
+// MempoolInput represents an input in the mempool.space transaction payload
+type MempoolInput struct {
+	Sequence uint32 `json:"sequence"`
+}
+
+// MempoolOutput represents an output in the mempool.space transaction payload
+type MempoolOutput struct {
+	Value int64 `json:"value"` // sats
+}
+
+// MempoolConfirmations represents the status block of the mempool.space transaction payload
+type MempoolConfirmations struct {
+	Confirmed   bool   `json:"confirmed"`
+	BlockHeight int    `json:"block_height"`
+	BlockHash   string `json:"block_hash"`
+	BlockTime   int64  `json:"block_time"`
+}
+
+// MempoolRBFHistory represents the payload of the mempool.space RBF endpoint. Replacements is
+// the replacement tree rooted at the latest version of the transaction.
+type MempoolRBFHistory struct {
+	Replacements *MempoolRBFNode `json:"replacements"`
+	Replaces     []string        `json:"replaces"`
+}
+
+// MempoolRBFNode represents a transaction in the RBF replacement tree
+type MempoolRBFNode struct {
+	Tx struct {
+		TxID string `json:"txid"`
+	} `json:"tx"`
+	Replaces []MempoolRBFNode `json:"replaces"`
+}
@@ -0,0 +1,27 @@
+// Package types provides type definitions for wallet explorer requests and responses.
+package types
+
+// IScriptAnalysis represents the locally computed classification and disassembly of a script
+type IScriptAnalysis struct {
+	Type         string            `json:"type"` // p2pk, p2pkh, p2sh, p2wpkh, p2wsh, p2tr, op_return, multisig, nonstandard...
+	ASM          string            `json:"asm"`
+	RequiredSigs *int              `json:"required_sigs,omitempty"`
+	TotalKeys    *int              `json:"total_keys,omitempty"`
+	OpReturn     *IOpReturnPayload `json:"op_return,omitempty"`
+	Witness      []string          `json:"witness,omitempty"`            // witness stack items as hex
+	WitnessASM   *string           `json:"witness_script_asm,omitempty"` // ASM of the P2WSH witness script or taproot leaf script
+	Inscription  *IInscription     `json:"inscription,omitempty"`
+}
+
+// IOpReturnPayload represents the data carried by an OP_RETURN output
+type IOpReturnPayload struct {
+	Hex  string  `json:"hex"`
+	Text *string `json:"text,omitempty"` // set when the payload is printable UTF-8
+}
+
+// IInscription represents an inscription envelope detected in witness data
+type IInscription struct {
+	Protocol      string `json:"protocol"`
+	ContentType   string `json:"content_type,omitempty"`
+	ContentLength int    `json:"content_length"`
+}
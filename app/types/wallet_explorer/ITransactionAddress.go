@@ -0,0 +1,23 @@
+// Package types provides type definitions for wallet explorer responses.
+package types
+
+// ITransactionAddress represents the payload of the Blockchain API address endpoint.
+// All amounts are expressed in satoshis.
+type ITransactionAddress struct {
+	Address       string               `json:"address"`
+	NTx           int                  `json:"n_tx"`
+	TotalReceived int64                `json:"total_received"`
+	TotalSent     int64                `json:"total_sent"`
+	FinalBalance  int64                `json:"final_balance"`
+	Transactions  []AddressTransaction `json:"txs"`
+}
+
+// AddressTransaction represents a transaction in the address endpoint payload
+type AddressTransaction struct {
+	Hash        string `json:"hash"`
+	Time        int64  `json:"time"`
+	BlockHeight int    `json:"block_height"`
+	Result      int64  `json:"result"`
+	Balance     int64  `json:"balance"`
+	Fee         int64  `json:"fee"`
+}
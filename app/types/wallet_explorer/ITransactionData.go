@@ -19,15 +19,18 @@ type ITransactionData struct {
 	Time        int64    `json:"time"`
 	Inputs      []Input  `json:"inputs"`
 	Out         []Output `json:"out"`
+	Label       string   `json:"label,omitempty"` // the signed-in user's BIP-329 label
 }
 
 // Input represents the payload from external API response
 type Input struct {
-	Sequence *int64   `json:"sequence,omitempty"`
-	Witness  *string  `json:"witness,omitempty"`
-	Script   string   `json:"script"`
-	Index    *int     `json:"index,omitempty"`
-	PrevOut  *PrevOut `json:"prev_out,omitempty"`
+	Sequence       *int64           `json:"sequence,omitempty"`
+	Witness        *string          `json:"witness,omitempty"`
+	Script         string           `json:"script"`
+	Index          *int             `json:"index,omitempty"`
+	PrevOut        *PrevOut         `json:"prev_out,omitempty"`
+	ScriptAnalysis *IScriptAnalysis `json:"script_analysis,omitempty"` // type of the spent output, scriptSig ASM and witness data
+	AddressLabel   string           `json:"address_label,omitempty"`   // the signed-in user's label of the spent address
 }
 
 // PrevOut represents the payload from external API response
@@ -39,18 +42,22 @@ type PrevOut struct {
 	N                 any        `json:"n"`        // sometimes string or int
 	TxIndex           any        `json:"tx_index"` // can vary
 	Script            string     `json:"script"`
+	Addr              *string    `json:"addr,omitempty"`
 }
 
 // Output represents the payload from external API response
 type Output struct {
-	Type              *int       `json:"type,omitempty"`
-	Spent             *bool      `json:"spent,omitempty"`
-	Value             any        `json:"value"` // sometimes int, sometimes string
-	SpendingOutpoints []Outpoint `json:"spending_outpoints,omitempty"`
-	N                 *int       `json:"n,omitempty"`
-	TxIndex           any        `json:"tx_index"` // can vary
-	Script            string     `json:"script"`
-	Addr              *string    `json:"addr,omitempty"`
+	Type              *int             `json:"type,omitempty"`
+	Spent             *bool            `json:"spent,omitempty"`
+	Value             any              `json:"value"` // sometimes int, sometimes string
+	SpendingOutpoints []Outpoint       `json:"spending_outpoints,omitempty"`
+	N                 *int             `json:"n,omitempty"`
+	TxIndex           any              `json:"tx_index"` // can vary
+	Script            string           `json:"script"`
+	Addr              *string          `json:"addr,omitempty"`
+	ScriptAnalysis    *IScriptAnalysis `json:"script_analysis,omitempty"`
+	Label             string           `json:"label,omitempty"`         // the signed-in user's label of the output
+	AddressLabel      string           `json:"address_label,omitempty"` // the signed-in user's label of the address
 }
 
 // Outpoint represents the payload from external API response
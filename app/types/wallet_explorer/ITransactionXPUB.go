@@ -1,11 +1,15 @@
 // Package types provides type definitions for user signup requests.
 package types
 
+import "cry-api/app/money"
+
 // ITransactionXPUB represents the payload from external API response
 type ITransactionXPUB struct {
 	Found        bool              `json:"found"`
 	GapLimit     int               `json:"gap_limit"`
 	Transactions []XPUBTransaction `json:"txs"`
+	Label        string            `json:"label,omitempty"` // the signed-in user's BIP-329 label
+	Fiat         *money.Amount     `json:"fiat,omitempty"`  // value of the balance, in the requested or the user's fiat currency
 }
 
 // XPUBTransaction represents the payload from external API response
@@ -17,4 +21,5 @@ type XPUBTransaction struct {
 	BalanceDiff float64  `json:"balance_diff"`
 	WalletIDs   []string `json:"wallet_ids"`
 	Balance     float64  `json:"balance"`
+	Label       string   `json:"label,omitempty"` // the signed-in user's BIP-329 label
 }
@@ -0,0 +1,60 @@
+// Package types provides type definitions for the user watchlist.
+package types
+
+import (
+	"time"
+
+	"cry-api/app/money"
+)
+
+// ICreateWatchedWalletRequest represents the payload for adding an entry to the watchlist
+type ICreateWatchedWalletRequest struct {
+	Kind  string `json:"kind" binding:"required"`
+	Value string `json:"value" binding:"required"`
+	Label string `json:"label" binding:"required"`
+	Color string `json:"color"`
+}
+
+// IUpdateWatchedWalletRequest represents the payload for updating a watchlist entry.
+// Only the label and the color can be changed; the watched value itself is immutable.
+type IUpdateWatchedWalletRequest struct {
+	Label *string `json:"label"`
+	Color *string `json:"color"`
+}
+
+// IWatchedWalletBalance represents the balance of a single watchlist entry
+type IWatchedWalletBalance struct {
+	ID      int           `json:"id"`
+	Kind    string        `json:"kind"`
+	Label   string        `json:"label"`
+	Color   string        `json:"color"`
+	Balance float64       `json:"balance"`
+	Fiat    *money.Amount `json:"fiat,omitempty"` // value of the balance in the user's fiat currency
+	TxCount int           `json:"tx_count"`
+	Error   *string       `json:"error,omitempty"`
+}
+
+// IWatchlistActivity represents a single transaction touching a watched wallet
+type IWatchlistActivity struct {
+	WalletID    int     `json:"wallet_id"`
+	Label       string  `json:"label"`
+	TxID        string  `json:"txid"`
+	BlockHeight int     `json:"block_height"`
+	Time        int64   `json:"time"`
+	BalanceDiff float64 `json:"balance_diff"`
+}
+
+// IWatchlistSummary represents the aggregated balance and activity across a watchlist
+type IWatchlistSummary struct {
+	TotalBalance   float64                 `json:"total_balance"`
+	TotalFiat      *money.Amount           `json:"total_fiat,omitempty"` // value of the total balance in the user's fiat currency
+	Wallets        []IWatchedWalletBalance `json:"wallets"`
+	RecentActivity []IWatchlistActivity    `json:"recent_activity"`
+	GeneratedAt    time.Time               `json:"generated_at"`
+}
+
+// IWatchlistHistory represents the merged transaction history of a whole watchlist
+type IWatchlistHistory struct {
+	Transactions []IWatchlistActivity `json:"transactions"`
+	Warnings     []string             `json:"warnings,omitempty"`
+}
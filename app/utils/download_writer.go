@@ -0,0 +1,42 @@
+// Package utils provides utility functions for input sanitization and other helpers.
+package utils
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// DownloadWriter defers the download headers and the 200 status until the first byte is
+// written, so a handler can still answer with a JSON error if the export fails early.
+type DownloadWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+// NewDownloadWriter wraps a response writer for a file download
+func NewDownloadWriter(w http.ResponseWriter, contentType, filename string) *DownloadWriter {
+	return &DownloadWriter{w: w, contentType: contentType, filename: filename}
+}
+
+// Write sends the headers on first use and flushes every chunk to the client
+func (d *DownloadWriter) Write(p []byte) (int, error) {
+	if !d.started {
+		d.started = true
+		d.w.Header().Set("Content-Type", d.contentType)
+		d.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", d.filename))
+		d.w.WriteHeader(http.StatusOK)
+	}
+
+	n, err := d.w.Write(p)
+	if flusher, ok := d.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return n, err
+}
+
+// Started reports whether anything has been written to the client
+func (d *DownloadWriter) Started() bool {
+	return d.started
+}
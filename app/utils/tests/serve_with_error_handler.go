@@ -0,0 +1,21 @@
+// Package testutils provides utils for unit tests
+package testutils
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"cry-api/app/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ServeWithErrorHandler runs handler on req behind the ErrorHandler middleware, so that the
+// errors it aborts with are written to w
+func ServeWithErrorHandler(w *httptest.ResponseRecorder, req *http.Request, handler gin.HandlerFunc) {
+	gin.SetMode(gin.TestMode)
+	_, router := gin.CreateTestContext(w)
+	router.Use(middleware.ErrorHandler())
+	router.Handle(req.Method, req.URL.Path, handler)
+	router.ServeHTTP(w, req)
+}
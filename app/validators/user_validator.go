@@ -40,7 +40,7 @@ type UserVerifyResetPasswordValidator struct {
 func ValidateUserSignup(c *gin.Context) (*UserSignupValidator, error) {
 	var input UserSignupValidator
 	if err := c.ShouldBindJSON(&input); err != nil {
-		return nil, app_errors.ErrInvalidJSON
+		return nil, app_errors.NewBindingError(err, &input)
 	}
 
 	// Validate fullname
@@ -70,7 +70,7 @@ func ValidateUserSignup(c *gin.Context) (*UserSignupValidator, error) {
 func ValidateUserSignin(c *gin.Context) (*UserSigninValidator, error) {
 	var input UserSigninValidator
 	if err := c.ShouldBindJSON(&input); err != nil {
-		return nil, app_errors.ErrInvalidJSON
+		return nil, app_errors.NewBindingError(err, &input)
 	}
 
 	// Validate username
@@ -90,7 +90,7 @@ func ValidateUserSignin(c *gin.Context) (*UserSigninValidator, error) {
 func ValidateResetPassword(c *gin.Context) (*UserResetPasswordValidator, error) {
 	var input UserResetPasswordValidator
 	if err := c.ShouldBindJSON(&input); err != nil {
-		return nil, app_errors.ErrInvalidJSON
+		return nil, app_errors.NewBindingError(err, &input)
 	}
 
 	// Validate email
@@ -105,7 +105,7 @@ func ValidateResetPassword(c *gin.Context) (*UserResetPasswordValidator, error)
 func ValidateVerifyResetPassword(c *gin.Context) (*UserVerifyResetPasswordValidator, error) {
 	var input UserVerifyResetPasswordValidator
 	if err := c.ShouldBindJSON(&input); err != nil {
-		return nil, app_errors.ErrInvalidJSON
+		return nil, app_errors.NewBindingError(err, &input)
 	}
 
 	// Validate token
@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"cry-api/app/bitcoin"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescriptorChecksum(t *testing.T) {
+	// BIP380 test vector
+	checksum, err := bitcoin.DescriptorChecksum("raw(deadbeef)")
+	require.NoError(t, err)
+	assert.Equal(t, "89f8spxm", checksum)
+
+	_, err = bitcoin.DescriptorChecksum("raw(deadbeef)é")
+	assert.ErrorIs(t, err, bitcoin.ErrInvalidDescriptor)
+}
+
+func TestExtendedKeyPublicDerivation(t *testing.T) {
+	// BIP32 test vector 1: m/0H -> m/0H/1
+	parent, err := bitcoin.ParseExtendedKey("xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw")
+	require.NoError(t, err)
+
+	child, err := parent.Child(1)
+	require.NoError(t, err)
+	assert.Equal(t, "xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ", child.String())
+
+	_, err = parent.Child(bitcoin.HardenedOffset)
+	assert.ErrorIs(t, err, bitcoin.ErrHardenedFromPublic)
+}
+
+func TestSegwitAddressRoundTrip(t *testing.T) {
+	testCases := []string{
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0",
+		"1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2",
+		"3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy",
+	}
+
+	for _, address := range testCases {
+		script, net, err := bitcoin.ScriptFromAddress(address)
+		require.NoError(t, err, address)
+		assert.Equal(t, bitcoin.MainNet, net)
+
+		encoded, err := bitcoin.AddressFromScript(script, net)
+		require.NoError(t, err)
+		assert.Equal(t, address, encoded)
+	}
+
+	// A v1 program encoded with the v0 (bech32) checksum must be rejected
+	_, _, err := bitcoin.ScriptFromAddress("bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7k7grplx")
+	assert.Error(t, err)
+}
+
+func TestTaprootOutputKey(t *testing.T) {
+	// BIP86 test vector: internal key of m/86'/0'/0'/0/0
+	internal, _ := hex.DecodeString("cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115")
+	output, err := bitcoin.TaprootOutputKey(internal)
+	require.NoError(t, err)
+	assert.Equal(t, "a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c", hex.EncodeToString(output))
+}
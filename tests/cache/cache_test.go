@@ -0,0 +1,239 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cry-api/app/cache"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type quote struct {
+	Price float64 `json:"price"`
+}
+
+var policy = cache.Policy{TTL: time.Minute, StaleTTL: time.Hour}
+
+// newTestCache returns a memory backed cache and a function moving its clock forward
+func newTestCache() (*cache.Cache, func(time.Duration)) {
+	now := time.Now()
+	c := cache.New(cache.NewMemoryStore(10))
+	c.SetClock(func() time.Time { return now })
+	return c, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestFetch_CachesUntilExpiry(t *testing.T) {
+	c, advance := newTestCache()
+	calls := 0
+	load := func(context.Context) (*quote, error) {
+		calls++
+		return &quote{Price: float64(calls)}, nil
+	}
+
+	first, err := cache.Fetch(context.Background(), c, "btc", cache.Fixed[*quote](policy), load)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, first.Price)
+
+	advance(30 * time.Second)
+	second, err := cache.Fetch(context.Background(), c, "btc", cache.Fixed[*quote](policy), load)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, second.Price)
+	assert.Equal(t, 1, calls)
+
+	advance(2 * time.Hour)
+	third, err := cache.Fetch(context.Background(), c, "btc", cache.Fixed[*quote](policy), load)
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, third.Price)
+}
+
+func TestFetch_ReturnsIndependentCopies(t *testing.T) {
+	c, _ := newTestCache()
+	load := func(context.Context) (*quote, error) { return &quote{Price: 1}, nil }
+
+	first, _ := cache.Fetch(context.Background(), c, "btc", cache.Fixed[*quote](policy), load)
+	first.Price = 99
+
+	second, _ := cache.Fetch(context.Background(), c, "btc", cache.Fixed[*quote](policy), load)
+	assert.Equal(t, 1.0, second.Price)
+}
+
+func TestFetch_PolicyDependsOnValue(t *testing.T) {
+	c, _ := newTestCache()
+	calls := 0
+	load := func(context.Context) (*quote, error) {
+		calls++
+		return &quote{Price: 0}, nil
+	}
+	// Zero prices are not worth keeping
+	byPrice := func(q *quote) cache.Policy {
+		if q.Price == 0 {
+			return cache.Policy{}
+		}
+		return policy
+	}
+
+	_, _ = cache.Fetch(context.Background(), c, "btc", byPrice, load)
+	_, _ = cache.Fetch(context.Background(), c, "btc", byPrice, load)
+	assert.Equal(t, 2, calls)
+}
+
+func TestFetch_ServesStaleValueWhileRefreshing(t *testing.T) {
+	c, advance := newTestCache()
+
+	_, err := cache.Fetch(context.Background(), c, "btc", cache.Fixed[*quote](policy), func(context.Context) (*quote, error) {
+		return &quote{Price: 1}, nil
+	})
+	assert.NoError(t, err)
+
+	release := make(chan struct{})
+	var calls atomic.Int32
+	slow := func(context.Context) (*quote, error) {
+		calls.Add(1)
+		<-release
+		return &quote{Price: 2}, nil
+	}
+
+	// The stale value is returned without waiting for upstream, and refreshed once
+	advance(30 * time.Minute)
+	for range 3 {
+		stale, err := cache.Fetch(context.Background(), c, "btc", cache.Fixed[*quote](policy), slow)
+		assert.NoError(t, err)
+		assert.Equal(t, 1.0, stale.Price)
+	}
+	close(release)
+	c.Wait()
+	assert.Equal(t, int32(1), calls.Load())
+
+	fresh, err := cache.Fetch(context.Background(), c, "btc", cache.Fixed[*quote](policy), slow)
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, fresh.Price)
+}
+
+func TestFetch_FailedRefreshKeepsStaleValue(t *testing.T) {
+	c, advance := newTestCache()
+	down := errors.New("upstream down")
+
+	_, err := cache.Fetch(context.Background(), c, "btc", cache.Fixed[*quote](policy), func(context.Context) (*quote, error) {
+		return &quote{Price: 1}, nil
+	})
+	assert.NoError(t, err)
+
+	failing := func(context.Context) (*quote, error) { return nil, down }
+
+	advance(30 * time.Minute)
+	stale, err := cache.Fetch(context.Background(), c, "btc", cache.Fixed[*quote](policy), failing)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, stale.Price)
+	c.Wait()
+
+	stale, err = cache.Fetch(context.Background(), c, "btc", cache.Fixed[*quote](policy), failing)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, stale.Price)
+	c.Wait()
+
+	advance(2 * time.Hour)
+	_, err = cache.Fetch(context.Background(), c, "btc", cache.Fixed[*quote](policy), failing)
+	assert.Equal(t, down, err)
+}
+
+func TestFetch_CallerCancellationDoesNotCancelLoad(t *testing.T) {
+	c := cache.New(cache.NewMemoryStore(10))
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	loadErr := make(chan error, 1)
+	load := func(ctx context.Context) (*quote, error) {
+		close(started)
+		<-release
+		loadErr <- ctx.Err()
+		return &quote{Price: 1}, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := cache.Fetch(ctx, c, "btc", cache.Fixed[*quote](policy), load)
+		done <- err
+	}()
+
+	// The first caller gives up; a second one still gets the value loaded on its behalf
+	<-started
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+
+	second := make(chan *quote, 1)
+	go func() {
+		q, _ := cache.Fetch(context.Background(), c, "btc", cache.Fixed[*quote](policy), load)
+		second <- q
+	}()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	assert.NoError(t, <-loadErr)
+	assert.Equal(t, 1.0, (<-second).Price)
+}
+
+func TestFetch_CoalescesConcurrentLookups(t *testing.T) {
+	c := cache.New(cache.NewMemoryStore(10))
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	load := func(context.Context) (*quote, error) {
+		calls.Add(1)
+		<-release
+		return &quote{Price: 1}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q, err := cache.Fetch(context.Background(), c, "btc", cache.Fixed[*quote](policy), load)
+			assert.NoError(t, err)
+			assert.Equal(t, 1.0, q.Price)
+		}()
+	}
+
+	// Let every goroutine reach the shared call before upstream answers
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestFetch_ServesOversizedValuesUncached(t *testing.T) {
+	c, _ := newTestCache()
+	calls := 0
+	load := func(context.Context) (string, error) {
+		calls++
+		return strings.Repeat("a", cache.MaxEntrySize), nil
+	}
+
+	for i := 0; i < 2; i++ {
+		value, err := cache.Fetch(context.Background(), c, "history", cache.Fixed[string](policy), load)
+		assert.NoError(t, err)
+		assert.Len(t, value, cache.MaxEntrySize)
+	}
+	assert.Equal(t, 2, calls)
+}
+
+func TestFetch_NilCacheAlwaysLoads(t *testing.T) {
+	calls := 0
+	load := func(context.Context) (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	_, _ = cache.Fetch[int](context.Background(), nil, "tip", cache.Fixed[int](policy), load)
+	value, err := cache.Fetch[int](context.Background(), nil, "tip", cache.Fixed[int](policy), load)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, value)
+}
@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"cry-api/app/cache"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func liveEntry(value string) *cache.Entry {
+	return &cache.Entry{Value: []byte(value), ExpiresAt: time.Now().Add(time.Minute), StaleUntil: time.Now().Add(time.Hour)}
+}
+
+func TestMemoryStore_EvictsLeastRecentlyUsed(t *testing.T) {
+	store := cache.NewMemoryStore(2)
+
+	assert.NoError(t, store.Set("a", liveEntry("1")))
+	assert.NoError(t, store.Set("b", liveEntry("2")))
+
+	// Reading "a" makes "b" the least recently used entry
+	entry, _ := store.Get("a")
+	assert.Equal(t, "1", string(entry.Value))
+
+	assert.NoError(t, store.Set("c", liveEntry("3")))
+	assert.Equal(t, 2, store.Len())
+
+	entry, _ = store.Get("b")
+	assert.Nil(t, entry)
+	entry, _ = store.Get("a")
+	assert.NotNil(t, entry)
+}
+
+func TestMemoryStore_DropsDeadEntries(t *testing.T) {
+	store := cache.NewMemoryStore(2)
+	past := time.Now().Add(-time.Second)
+
+	assert.NoError(t, store.Set("a", &cache.Entry{Value: []byte("1"), ExpiresAt: past, StaleUntil: past}))
+
+	entry, err := store.Get("a")
+	assert.NoError(t, err)
+	assert.Nil(t, entry)
+	assert.Equal(t, 0, store.Len())
+}
+
+func TestMemoryStore_Delete(t *testing.T) {
+	store := cache.NewMemoryStore(2)
+	assert.NoError(t, store.Set("a", liveEntry("1")))
+	assert.NoError(t, store.Delete("a"))
+
+	entry, _ := store.Get("a")
+	assert.Nil(t, entry)
+}
@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"cry-api/app/cache"
+	mocks "cry-api/tests/mocks"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestSQLStore_Get(t *testing.T) {
+	db, mock, cleanup := mocks.SetupMockDB(t)
+	defer cleanup()
+	store := cache.NewSQLStore(db)
+
+	query := regexp.QuoteMeta(`SELECT * FROM "cache_entries" WHERE "key" = $1 AND stale_until > $2 LIMIT $3`)
+	expires := time.Now().Add(time.Minute)
+
+	mock.ExpectQuery(query).
+		WithArgs("blockchain:tip", sqlmock.AnyArg(), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"key", "value", "expires_at", "stale_until", "updated_at"}).
+			AddRow("blockchain:tip", []byte("840000"), expires, expires.Add(time.Hour), time.Now()))
+
+	entry, err := store.Get("blockchain:tip")
+	assert.NoError(t, err)
+	assert.Equal(t, "840000", string(entry.Value))
+	assert.True(t, entry.ExpiresAt.Equal(expires))
+
+	mock.ExpectQuery(query).
+		WithArgs("missing", sqlmock.AnyArg(), 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	entry, err = store.Get("missing")
+	assert.NoError(t, err)
+	assert.Nil(t, entry)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLStore_SetUpserts(t *testing.T) {
+	db, mock, cleanup := mocks.SetupMockDB(t)
+	defer cleanup()
+	store := cache.NewSQLStore(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "cache_entries" ("key","value","expires_at","stale_until","updated_at") VALUES ($1,$2,$3,$4,$5) ON CONFLICT ("key") DO UPDATE SET`)).
+		WithArgs("blockchain:tip", []byte("840000"), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := store.Set("blockchain:tip", &cache.Entry{Value: []byte("840000"), ExpiresAt: time.Now(), StaleUntil: time.Now()})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLStore_Delete(t *testing.T) {
+	db, mock, cleanup := mocks.SetupMockDB(t)
+	defer cleanup()
+	store := cache.NewSQLStore(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "cache_entries" WHERE "key" = $1`)).
+		WithArgs("blockchain:tip").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, store.Delete("blockchain:tip"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLStore_RefusesOversizedEntries(t *testing.T) {
+	db, mock, cleanup := mocks.SetupMockDB(t)
+	defer cleanup()
+	store := cache.NewSQLStore(db)
+
+	err := store.Set("blockchain:addr:"+strings.Repeat("a", cache.MaxKeyLength), liveEntry("1"))
+	assert.ErrorIs(t, err, cache.ErrEntryTooLarge)
+
+	err = store.Set("blockchain:tip", liveEntry(strings.Repeat("1", cache.MaxEntrySize+1)))
+	assert.ErrorIs(t, err, cache.ErrEntryTooLarge)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,296 @@
+// Package cassette records real upstream responses into fixture files and replays them through
+// an http.RoundTripper, so the external API services can be tested against real payloads
+// without network access.
+//
+// Tests replay by default. Run them with CASSETTE_MODE=record to call the real APIs and
+// rewrite the cassettes of the tests that ran.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Redacted replaces the value of scrubbed headers and query parameters
+const Redacted = "[REDACTED]"
+
+// ModeEnv is the environment variable switching the cassettes to record mode
+const ModeEnv = "CASSETTE_MODE"
+
+// sensitiveHeaders are never written to a cassette
+var sensitiveHeaders = []string{"X-CMC_PRO_API_KEY", "Authorization", "Cookie", "Set-Cookie"}
+
+// sensitiveParams are query parameters whose values are never written to a cassette
+var sensitiveParams = []string{"api_key", "apikey", "key", "token"}
+
+// keptResponseHeaders are the only response headers recorded, to keep fixtures stable
+var keptResponseHeaders = []string{"Content-Type", "Retry-After"}
+
+// Request is the recorded part of an outbound request
+type Request struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+}
+
+// Response is a recorded upstream response. JSON bodies are kept as JSON for readability.
+type Response struct {
+	Status  int             `json:"status"`
+	Headers http.Header     `json:"headers,omitempty"`
+	JSON    json.RawMessage `json:"json,omitempty"`
+	Body    string          `json:"body,omitempty"`
+}
+
+// Interaction is one request and its response
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Cassette is an http.RoundTripper replaying, or recording, the interactions of one fixture file
+type Cassette struct {
+	name      string
+	path      string
+	recording bool
+	transport http.RoundTripper
+
+	mu           sync.Mutex
+	Interactions []Interaction `json:"interactions"`
+	used         []bool
+}
+
+// New loads the cassette name from the checked-in fixtures directory
+func New(t testing.TB, name string) *Cassette {
+	t.Helper()
+	return Open(t, fixturesDir(), name)
+}
+
+// Open loads the cassette name from dir. In record mode the cassette starts empty, forwards
+// requests to the network and is saved when the test finishes.
+func Open(t testing.TB, dir, name string) *Cassette {
+	t.Helper()
+
+	c := &Cassette{
+		name:      name,
+		path:      filepath.Join(dir, name+".json"),
+		recording: os.Getenv(ModeEnv) == "record",
+		transport: http.DefaultTransport,
+	}
+
+	if c.recording {
+		t.Cleanup(func() {
+			if err := c.save(); err != nil {
+				t.Errorf("failed to save cassette %s: %v", name, err)
+			}
+		})
+		return c
+	}
+
+	raw, err := os.ReadFile(c.path)
+	if err != nil {
+		t.Fatalf("failed to read cassette %s: %v", name, err)
+	}
+	if err := json.Unmarshal(raw, c); err != nil {
+		t.Fatalf("failed to parse cassette %s: %v", name, err)
+	}
+	c.used = make([]bool, len(c.Interactions))
+	return c
+}
+
+// Recording reports whether the cassette calls the real upstream
+func (c *Cassette) Recording() bool {
+	return c.recording
+}
+
+// Client returns an HTTP client sending its requests through the cassette
+func (c *Cassette) Client() *http.Client {
+	return &http.Client{Transport: c}
+}
+
+// RoundTrip replays the first unused interaction matching the method, path and query of req,
+// falling back to the last matching one once all have been used. Hosts are not compared, so
+// fixtures do not depend on the configured base URLs.
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	if c.recording {
+		return c.record(req)
+	}
+
+	key := matchKey(req.Method, req.URL)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	found := -1
+	for i, interaction := range c.Interactions {
+		u, err := url.Parse(interaction.Request.URL)
+		if err != nil || matchKey(interaction.Request.Method, u) != key {
+			continue
+		}
+		found = i
+		if !c.used[i] {
+			break
+		}
+	}
+	if found < 0 {
+		return nil, fmt.Errorf("cassette %s has no interaction for %s", c.name, key)
+	}
+
+	c.used[found] = true
+	return c.Interactions[found].Response.toHTTP(req), nil
+}
+
+// record forwards req to the network and keeps a scrubbed copy of the exchange
+func (c *Cassette) record(req *http.Request) (*http.Response, error) {
+	resp, err := c.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	recorded := Interaction{
+		Request: Request{
+			Method:  req.Method,
+			URL:     scrubURL(req.URL),
+			Headers: scrubHeaders(req.Header),
+		},
+		Response: Response{
+			Status:  resp.StatusCode,
+			Headers: keepHeaders(resp.Header),
+		},
+	}
+	if json.Valid(body) && len(bytes.TrimSpace(body)) > 0 {
+		recorded.Response.JSON = json.RawMessage(body)
+	} else {
+		recorded.Response.Body = string(body)
+	}
+
+	c.mu.Lock()
+	c.Interactions = append(c.Interactions, recorded)
+	c.mu.Unlock()
+
+	return resp, nil
+}
+
+// save writes the recorded interactions to the fixture file
+func (c *Cassette) save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var raw bytes.Buffer
+	enc := json.NewEncoder(&raw)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(c); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, raw.Bytes(), 0o644)
+}
+
+// toHTTP builds the replayed response of req
+func (r Response) toHTTP(req *http.Request) *http.Response {
+	body := r.Body
+	if len(r.JSON) > 0 {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, r.JSON); err == nil {
+			body = compact.String()
+		} else {
+			body = string(r.JSON)
+		}
+	}
+
+	headers := r.Headers.Clone()
+	if headers == nil {
+		headers = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        headers,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// matchKey identifies a request by method, path and sorted query
+func matchKey(method string, u *url.URL) string {
+	if method == "" {
+		method = http.MethodGet
+	}
+	key := method + " " + u.Path
+	if query := scrubQuery(u.Query()).Encode(); query != "" {
+		key += "?" + query
+	}
+	return key
+}
+
+// scrubURL returns u without credentials and with sensitive query parameters redacted
+func scrubURL(u *url.URL) string {
+	clean := *u
+	clean.User = nil
+	clean.RawQuery = scrubQuery(u.Query()).Encode()
+	return clean.String()
+}
+
+func scrubQuery(query url.Values) url.Values {
+	for _, param := range sensitiveParams {
+		if query.Has(param) {
+			query.Set(param, Redacted)
+		}
+	}
+	return query
+}
+
+func scrubHeaders(headers http.Header) http.Header {
+	clean := headers.Clone()
+	for key := range clean {
+		for _, name := range sensitiveHeaders {
+			if strings.EqualFold(key, name) {
+				clean[key] = []string{Redacted}
+			}
+		}
+	}
+	if len(clean) == 0 {
+		return nil
+	}
+	return clean
+}
+
+func keepHeaders(headers http.Header) http.Header {
+	kept := http.Header{}
+	for _, name := range keptResponseHeaders {
+		if value := headers.Get(name); value != "" {
+			kept.Set(name, value)
+		}
+	}
+	return kept
+}
+
+// fixturesDir is the directory holding the cassette files, next to this package
+func fixturesDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "fixtures", "cassettes")
+}
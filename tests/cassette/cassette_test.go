@@ -0,0 +1,110 @@
+package cassette_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cry-api/tests/cassette"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func get(t *testing.T, client *http.Client, target string, header http.Header) (int, string, error) {
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	assert.NoError(t, err)
+	req.Header = header
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body), nil
+}
+
+func TestCassette_RecordsScrubbedInteractionsAndReplaysThem(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Header().Set("X-Request-Id", "abc")
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("not found"))
+			return
+		}
+		_, _ = w.Write([]byte(`{"value":"42"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	header := http.Header{"X-CMC_PRO_API_KEY": []string{"super-secret"}}
+
+	t.Run("record", func(t *testing.T) {
+		t.Setenv(cassette.ModeEnv, "record")
+		rec := cassette.Open(t, dir, "upstream")
+		assert.True(t, rec.Recording())
+
+		status, body, err := get(t, rec.Client(), server.URL+"/quote?symbol=BTC&api_key=super-secret", header)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, `{"value":"42"}`, body)
+
+		status, _, err = get(t, rec.Client(), server.URL+"/missing", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	raw, err := os.ReadFile(filepath.Join(dir, "upstream.json"))
+	assert.NoError(t, err)
+	fixture := string(raw)
+	assert.NotContains(t, fixture, "super-secret")
+	assert.NotContains(t, fixture, "session=secret")
+	assert.NotContains(t, fixture, "X-Request-Id")
+	assert.Contains(t, fixture, cassette.Redacted)
+	assert.Contains(t, fixture, `"value": "42"`)
+	assert.Contains(t, fixture, `"body": "not found"`)
+
+	t.Run("replay", func(t *testing.T) {
+		rec := cassette.Open(t, dir, "upstream")
+		assert.False(t, rec.Recording())
+
+		// Hosts and query order do not matter, only method, path and query
+		status, body, err := get(t, rec.Client(), "https://api.example.com/quote?api_key=other&symbol=BTC", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, `{"value":"42"}`, body)
+
+		status, body, err = get(t, rec.Client(), "https://api.example.com/missing", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, status)
+		assert.Equal(t, "not found", body)
+
+		_, _, err = get(t, rec.Client(), "https://api.example.com/quote?symbol=ETH", nil)
+		assert.ErrorContains(t, err, "cassette upstream has no interaction for GET /quote?symbol=ETH")
+	})
+}
+
+func TestCassette_ReplaysRepeatedRequestsInOrder(t *testing.T) {
+	dir := t.TempDir()
+	fixture := `{"interactions": [
+		{"request": {"method": "GET", "url": "https://x.test/tip"}, "response": {"status": 503, "body": "busy"}},
+		{"request": {"method": "GET", "url": "https://x.test/tip"}, "response": {"status": 200, "json": 868123}}
+	]}`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "tip.json"), []byte(fixture), 0o644))
+
+	rec := cassette.Open(t, dir, "tip")
+	var bodies []string
+	for i := 0; i < 3; i++ {
+		_, body, err := get(t, rec.Client(), "https://y.test/tip", nil)
+		assert.NoError(t, err)
+		bodies = append(bodies, body)
+	}
+	assert.Equal(t, "busy,868123,868123", strings.Join(bodies, ","))
+}
@@ -12,6 +12,7 @@ import (
 	controller "cry-api/app/controllers/2fa"
 	UserModel "cry-api/app/models"
 	TwoFactorTypes "cry-api/app/types/2fa"
+	app_errors "cry-api/app/types/errors"
 	TokenTypes "cry-api/app/types/token_purpose"
 	TestUtils "cry-api/app/utils/tests"
 	testmocks "cry-api/tests/mocks"
@@ -89,9 +90,7 @@ func TestAlternativeSendOtp_Success(t *testing.T) {
 	// Make HTTP request
 	req := httptest.NewRequest(http.MethodPost, "/2fa/alternative", bytes.NewReader(bodyBytes))
 	w := httptest.NewRecorder()
-	c := TestUtils.GetGinContext(w, req)
-
-	controller.AlternativeSendOtp(c)
+	TestUtils.ServeWithErrorHandler(w, req, controller.AlternativeSendOtp)
 
 	// Wait for async email to be called (or timeout)
 	select {
@@ -136,8 +135,7 @@ func TestAlternativeSendOtp_InvalidJSON(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/2fa/alternative", bytes.NewReader(invalidJSON))
 	w := httptest.NewRecorder()
 
-	c := TestUtils.GetGinContext(w, req)
-	controller.AlternativeSendOtp(c)
+	TestUtils.ServeWithErrorHandler(w, req, controller.AlternativeSendOtp)
 
 	res := w.Result()
 	defer func() {
@@ -146,10 +144,11 @@ func TestAlternativeSendOtp_InvalidJSON(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&respBody)
 	assert.NoError(t, err)
-	assert.Contains(t, respBody["error"], "Invalid request payload")
+	assert.Contains(t, respBody.Error.Message, "Invalid JSON format")
+	assert.Equal(t, "INVALID_JSON", respBody.Error.Code)
 
 	mockUserService.AssertNotCalled(t, "FindUserByEmail", mock.Anything)
 }
@@ -176,8 +175,7 @@ func TestAlternativeSendOtp_UserNotFound(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/2fa/alternative", bytes.NewReader(bodyBytes))
 	w := httptest.NewRecorder()
 
-	c := TestUtils.GetGinContext(w, req)
-	controller.AlternativeSendOtp(c)
+	TestUtils.ServeWithErrorHandler(w, req, controller.AlternativeSendOtp)
 
 	res := w.Result()
 	defer func() {
@@ -186,10 +184,11 @@ func TestAlternativeSendOtp_UserNotFound(t *testing.T) {
 
 	assert.Equal(t, http.StatusNotFound, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&respBody)
 	assert.NoError(t, err)
-	assert.Contains(t, respBody["error"], "User not found")
+	assert.Contains(t, respBody.Error.Message, "User not found")
+	assert.Equal(t, "USER_NOT_FOUND", respBody.Error.Code)
 
 	mockUserService.AssertExpectations(t)
 }
@@ -223,8 +222,7 @@ func TestAlternativeSendOtp_UserNotVerified(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/2fa/alternative", bytes.NewReader(bodyBytes))
 	w := httptest.NewRecorder()
 
-	c := TestUtils.GetGinContext(w, req)
-	controller.AlternativeSendOtp(c)
+	TestUtils.ServeWithErrorHandler(w, req, controller.AlternativeSendOtp)
 
 	res := w.Result()
 	defer func() {
@@ -233,10 +231,11 @@ func TestAlternativeSendOtp_UserNotVerified(t *testing.T) {
 
 	assert.Equal(t, http.StatusForbidden, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&respBody)
 	assert.NoError(t, err)
-	assert.Contains(t, respBody["error"], "User not verified")
+	assert.Contains(t, respBody.Error.Message, "User not verified")
+	assert.Equal(t, "AUTH_USER_NOT_VERIFIED", respBody.Error.Code)
 
 	mockUserService.AssertExpectations(t)
 }
@@ -12,6 +12,7 @@ import (
 	UserModel "cry-api/app/models"
 	JWT "cry-api/app/services/jwt"
 	TwoFactorTypes "cry-api/app/types/2fa"
+	app_errors "cry-api/app/types/errors"
 	TokenType "cry-api/app/types/token_purpose"
 	TestUtils "cry-api/app/utils/tests"
 	testmocks "cry-api/tests/mocks"
@@ -65,9 +66,7 @@ func TestAlternativeVerifyOTP_Success(t *testing.T) {
 
 	req := httptest.NewRequest(http.MethodPost, "/2fa/verify-alternative", bytes.NewReader(bodyBytes))
 	w := httptest.NewRecorder()
-	c := TestUtils.GetGinContext(w, req)
-
-	controller.AlternativeVerifyOTP(c)
+	TestUtils.ServeWithErrorHandler(w, req, controller.AlternativeVerifyOTP)
 
 	res := w.Result()
 	defer func() {
@@ -99,9 +98,7 @@ func TestAlternativeVerifyOTP_InvalidJSON(t *testing.T) {
 
 	req := httptest.NewRequest(http.MethodPost, "/2fa/verify-alternative", bytes.NewReader([]byte(`{invalid-json}`)))
 	w := httptest.NewRecorder()
-	c := TestUtils.GetGinContext(w, req)
-
-	controller.AlternativeVerifyOTP(c)
+	TestUtils.ServeWithErrorHandler(w, req, controller.AlternativeVerifyOTP)
 
 	res := w.Result()
 	defer func() {
@@ -112,11 +109,12 @@ func TestAlternativeVerifyOTP_InvalidJSON(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	if err := json.NewDecoder(res.Body).Decode(&respBody); err != nil {
 		t.Fatalf("failed to decode response body: %v", err)
 	}
-	assert.Contains(t, respBody["error"], "Invalid request")
+	assert.Contains(t, respBody.Error.Message, "Invalid JSON format")
+	assert.Equal(t, "INVALID_JSON", respBody.Error.Code)
 }
 
 func TestAlternativeVerifyOTP_MissingFields(t *testing.T) {
@@ -145,9 +143,7 @@ func TestAlternativeVerifyOTP_MissingFields(t *testing.T) {
 
 		req := httptest.NewRequest(http.MethodPost, "/2fa/verify-alternative", bytes.NewReader(bodyBytes))
 		w := httptest.NewRecorder()
-		c := TestUtils.GetGinContext(w, req)
-
-		controller.AlternativeVerifyOTP(c)
+		TestUtils.ServeWithErrorHandler(w, req, controller.AlternativeVerifyOTP)
 
 		res := w.Result()
 		defer func() {
@@ -157,11 +153,12 @@ func TestAlternativeVerifyOTP_MissingFields(t *testing.T) {
 		}()
 		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 
-		var respBody map[string]string
+		var respBody app_errors.ErrorResponse
 		if err := json.NewDecoder(res.Body).Decode(&respBody); err != nil {
 			t.Fatalf("failed to decode response body: %v", err)
 		}
-		assert.Contains(t, respBody["error"], tt.wantErrMsg)
+		assert.Contains(t, respBody.Error.Message, tt.wantErrMsg)
+		assert.Equal(t, "VALIDATION_FAILED", respBody.Error.Code)
 	}
 }
 
@@ -188,9 +185,7 @@ func TestAlternativeVerifyOTP_UserNotFound(t *testing.T) {
 
 	req := httptest.NewRequest(http.MethodPost, "/2fa/verify-alternative", bytes.NewReader(bodyBytes))
 	w := httptest.NewRecorder()
-	c := TestUtils.GetGinContext(w, req)
-
-	controller.AlternativeVerifyOTP(c)
+	TestUtils.ServeWithErrorHandler(w, req, controller.AlternativeVerifyOTP)
 
 	res := w.Result()
 	defer func() {
@@ -201,11 +196,12 @@ func TestAlternativeVerifyOTP_UserNotFound(t *testing.T) {
 
 	assert.Equal(t, http.StatusNotFound, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	if err := json.NewDecoder(res.Body).Decode(&respBody); err != nil {
 		t.Fatalf("failed to decode response body: %v", err)
 	}
-	assert.Contains(t, respBody["error"], "User not found")
+	assert.Contains(t, respBody.Error.Message, "User not found")
+	assert.Equal(t, "USER_NOT_FOUND", respBody.Error.Code)
 
 	mockUserService.AssertExpectations(t)
 }
@@ -239,9 +235,7 @@ func TestAlternativeVerifyOTP_User2FANotEnabled(t *testing.T) {
 
 	req := httptest.NewRequest(http.MethodPost, "/2fa/verify-alternative", bytes.NewReader(bodyBytes))
 	w := httptest.NewRecorder()
-	c := TestUtils.GetGinContext(w, req)
-
-	controller.AlternativeVerifyOTP(c)
+	TestUtils.ServeWithErrorHandler(w, req, controller.AlternativeVerifyOTP)
 
 	res := w.Result()
 	defer func() {
@@ -252,16 +246,13 @@ func TestAlternativeVerifyOTP_User2FANotEnabled(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	if err := json.NewDecoder(res.Body).Decode(&respBody); err != nil {
 		t.Fatalf("failed to decode response body: %v", err)
 	}
 
-	if val, ok := respBody["error"]; ok {
-		assert.Contains(t, val, "User has not enabled 2FA")
-	} else {
-		t.Fatalf("response body missing 'error' field")
-	}
+	assert.Contains(t, respBody.Error.Message, "User has not enabled 2FA")
+	assert.Equal(t, "TWO_FACTOR_NOT_ENABLED", respBody.Error.Code)
 
 	mockUserService.AssertExpectations(t)
 }
@@ -298,9 +289,7 @@ func TestAlternativeVerifyOTP_InvalidOrExpiredOTP(t *testing.T) {
 
 	req := httptest.NewRequest(http.MethodPost, "/2fa/verify-alternative", bytes.NewReader(bodyBytes))
 	w := httptest.NewRecorder()
-	c := TestUtils.GetGinContext(w, req)
-
-	controller.AlternativeVerifyOTP(c)
+	TestUtils.ServeWithErrorHandler(w, req, controller.AlternativeVerifyOTP)
 
 	res := w.Result()
 	defer func() {
@@ -311,12 +300,13 @@ func TestAlternativeVerifyOTP_InvalidOrExpiredOTP(t *testing.T) {
 
 	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	if err := json.NewDecoder(res.Body).Decode(&respBody); err != nil {
 		t.Fatalf("failed to decode response body: %v", err)
 	}
 
-	assert.Contains(t, respBody["error"], "Invalid or expired OTP")
+	assert.Contains(t, respBody.Error.Message, "Invalid or expired OTP")
+	assert.Equal(t, "OTP_EXPIRED", respBody.Error.Code)
 	mockUserService.AssertExpectations(t)
 	mockUserTokenService.AssertExpectations(t)
 }
@@ -357,9 +347,7 @@ func TestAlternativeVerifyOTP_WrongOTP(t *testing.T) {
 
 	req := httptest.NewRequest(http.MethodPost, "/2fa/verify-alternative", bytes.NewReader(bodyBytes))
 	w := httptest.NewRecorder()
-	c := TestUtils.GetGinContext(w, req)
-
-	controller.AlternativeVerifyOTP(c)
+	TestUtils.ServeWithErrorHandler(w, req, controller.AlternativeVerifyOTP)
 
 	res := w.Result()
 	defer func() {
@@ -370,12 +358,13 @@ func TestAlternativeVerifyOTP_WrongOTP(t *testing.T) {
 
 	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	if err := json.NewDecoder(res.Body).Decode(&respBody); err != nil {
 		t.Fatalf("failed to decode response body: %v", err)
 	}
 
-	assert.Contains(t, respBody["error"], "Invalid or expired OTP")
+	assert.Contains(t, respBody.Error.Message, "Invalid or expired OTP")
+	assert.Equal(t, "OTP_INVALID", respBody.Error.Code)
 	mockUserService.AssertExpectations(t)
 	mockUserTokenService.AssertExpectations(t)
 }
@@ -15,7 +15,6 @@ import (
 	TestUtils "cry-api/app/utils/tests"
 	testmocks "cry-api/tests/mocks"
 
-	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -31,20 +30,18 @@ func TestTwoFactorController_Setup(t *testing.T) {
 		TwoFactorService: mockTwoFactorService,
 	}
 
-	makeRequest := func(body interface{}) (*gin.Context, *httptest.ResponseRecorder) {
+	makeRequest := func(body interface{}) *httptest.ResponseRecorder {
 		b, _ := json.Marshal(body)
 		req := httptest.NewRequest(http.MethodPost, "/2fa/setup", bytes.NewReader(b))
 		w := httptest.NewRecorder()
-		c := TestUtils.GetGinContext(w, req)
-		return c, w
+		TestUtils.ServeWithErrorHandler(w, req, twoFactorController.Setup)
+		return w
 	}
 
 	t.Run("Invalid JSON", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/2fa/setup", bytes.NewReader([]byte("{invalid-json")))
 		w := httptest.NewRecorder()
-		c := TestUtils.GetGinContext(w, req)
-
-		twoFactorController.Setup(c)
+		TestUtils.ServeWithErrorHandler(w, req, twoFactorController.Setup)
 
 		resp := w.Result()
 		defer func() {
@@ -55,8 +52,7 @@ func TestTwoFactorController_Setup(t *testing.T) {
 	})
 
 	t.Run("Missing UserUUID", func(t *testing.T) {
-		c, w := makeRequest(TwoFactorType.ITwoFactorSetupRequest{UserUUID: ""})
-		twoFactorController.Setup(c)
+		w := makeRequest(TwoFactorType.ITwoFactorSetupRequest{UserUUID: ""})
 
 		resp := w.Result()
 		defer func() {
@@ -70,8 +66,7 @@ func TestTwoFactorController_Setup(t *testing.T) {
 		userUUID := "missing-uuid"
 		mockUserService.On("GetUserByUUID", userUUID).Return(nil, nil).Once()
 
-		c, w := makeRequest(TwoFactorType.ITwoFactorSetupRequest{UserUUID: userUUID})
-		twoFactorController.Setup(c)
+		w := makeRequest(TwoFactorType.ITwoFactorSetupRequest{UserUUID: userUUID})
 
 		resp := w.Result()
 		defer func() {
@@ -94,8 +89,7 @@ func TestTwoFactorController_Setup(t *testing.T) {
 		mockTwoFactorService.On("GenerateOtpauthURL", user.Email, secret).Return("otpauth://mockurl").Once()
 		mockTwoFactorService.On("GenerateQRCodeBase64", "otpauth://mockurl").Return("mockQRcodeBase64", nil).Once()
 
-		c, w := makeRequest(TwoFactorType.ITwoFactorSetupRequest{UserUUID: userUUID})
-		twoFactorController.Setup(c)
+		w := makeRequest(TwoFactorType.ITwoFactorSetupRequest{UserUUID: userUUID})
 
 		resp := w.Result()
 		defer func() {
@@ -132,8 +126,7 @@ func TestTwoFactorController_Setup(t *testing.T) {
 		})).Return(nil).Once()
 		mockTwoFactorService.On("GenerateQRCodeBase64", otpauthURL).Return("newMockQRcodeBase64", nil).Once()
 
-		c, w := makeRequest(TwoFactorType.ITwoFactorSetupRequest{UserUUID: userUUID})
-		twoFactorController.Setup(c)
+		w := makeRequest(TwoFactorType.ITwoFactorSetupRequest{UserUUID: userUUID})
 
 		resp := w.Result()
 		defer func() {
@@ -159,8 +152,7 @@ func TestTwoFactorController_Setup(t *testing.T) {
 		mockUserService.On("GetUserByUUID", userUUID).Return(user, nil).Once()
 		mockTwoFactorService.On("GenerateTOTP", user.Email).Return("", "", errors.New("fail generate totp")).Once()
 
-		c, w := makeRequest(TwoFactorType.ITwoFactorSetupRequest{UserUUID: userUUID})
-		twoFactorController.Setup(c)
+		w := makeRequest(TwoFactorType.ITwoFactorSetupRequest{UserUUID: userUUID})
 
 		resp := w.Result()
 		defer func() {
@@ -183,8 +175,7 @@ func TestTwoFactorController_Setup(t *testing.T) {
 		mockTwoFactorService.On("GenerateTOTP", user.Email).Return(secret, otpauthURL, nil).Once()
 		mockUserService.On("UpdateUser", mock.Anything).Return(errors.New("db update error")).Once()
 
-		c, w := makeRequest(TwoFactorType.ITwoFactorSetupRequest{UserUUID: userUUID})
-		twoFactorController.Setup(c)
+		w := makeRequest(TwoFactorType.ITwoFactorSetupRequest{UserUUID: userUUID})
 
 		resp := w.Result()
 		defer func() {
@@ -208,8 +199,7 @@ func TestTwoFactorController_Setup(t *testing.T) {
 		mockUserService.On("UpdateUser", mock.Anything).Return(nil).Once()
 		mockTwoFactorService.On("GenerateQRCodeBase64", otpauthURL).Return("", errors.New("qrcode error")).Once()
 
-		c, w := makeRequest(TwoFactorType.ITwoFactorSetupRequest{UserUUID: userUUID})
-		twoFactorController.Setup(c)
+		w := makeRequest(TwoFactorType.ITwoFactorSetupRequest{UserUUID: userUUID})
 
 		resp := w.Result()
 		defer func() {
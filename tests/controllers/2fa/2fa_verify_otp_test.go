@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	controller "cry-api/app/controllers/2fa"
+	"cry-api/app/middleware"
 	UserModel "cry-api/app/models"
 	JWT "cry-api/app/services/jwt"
 	testmocks "cry-api/tests/mocks"
@@ -29,42 +30,40 @@ func TestVerifyOTP(t *testing.T) {
 		AuthService: mockAuthService,
 	}
 
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.POST("/verify-otp", controller.VerifyOTP)
+
 	// Helper to perform requests
-	performRequest := func(body any) *httptest.ResponseRecorder {
+	send := func(body []byte) *httptest.ResponseRecorder {
 		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-
-		jsonBytes, _ := json.Marshal(body)
-		c.Request, _ = http.NewRequest("POST", "/verify-otp", bytes.NewReader(jsonBytes))
-		c.Request.Header.Set("Content-Type", "application/json")
-
-		controller.VerifyOTP(c)
+		req, _ := http.NewRequest("POST", "/verify-otp", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
 		return w
 	}
+	performRequest := func(body any) *httptest.ResponseRecorder {
+		jsonBytes, _ := json.Marshal(body)
+		return send(jsonBytes)
+	}
 
 	t.Run("Invalid JSON", func(t *testing.T) {
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-
-		c.Request, _ = http.NewRequest("POST", "/verify-otp", bytes.NewReader([]byte("{invalid-json")))
-		c.Request.Header.Set("Content-Type", "application/json")
-
-		controller.VerifyOTP(c)
+		w := send([]byte("{invalid-json"))
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
-		assert.JSONEq(t, `{"error":"Invalid request"}`, w.Body.String())
+		assert.JSONEq(t, `{"error":{"code":"INVALID_JSON","message":"Invalid JSON format"}}`, w.Body.String())
 	})
 
 	t.Run("Missing UserUUID", func(t *testing.T) {
 		resp := performRequest(map[string]string{"otp": "123456"})
 		assert.Equal(t, http.StatusBadRequest, resp.Code)
-		assert.JSONEq(t, `{"error":"User UUID is required"}`, resp.Body.String())
+		assert.JSONEq(t, `{"error":{"code":"VALIDATION_FAILED","message":"User UUID is required","fields":[{"field":"userUUID","code":"INVALID","message":"User UUID is required"}]}}`, resp.Body.String())
 	})
 
 	t.Run("Missing OTP", func(t *testing.T) {
 		resp := performRequest(map[string]string{"userUUID": "user-123"})
 		assert.Equal(t, http.StatusBadRequest, resp.Code)
-		assert.JSONEq(t, `{"error":"OTP is required for verification"}`, resp.Body.String())
+		assert.JSONEq(t, `{"error":{"code":"VALIDATION_FAILED","message":"OTP is required for verification","fields":[{"field":"otp","code":"INVALID","message":"OTP is required for verification"}]}}`, resp.Body.String())
 	})
 
 	t.Run("User retrieval error", func(t *testing.T) {
@@ -72,7 +71,7 @@ func TestVerifyOTP(t *testing.T) {
 
 		resp := performRequest(map[string]string{"userUUID": "user-123", "otp": "123456"})
 		assert.Equal(t, http.StatusInternalServerError, resp.Code)
-		assert.JSONEq(t, `{"error":"Failed to get user"}`, resp.Body.String())
+		assert.JSONEq(t, `{"error":{"code":"INTERNAL_ERROR","message":"Failed to get user"}}`, resp.Body.String())
 
 		mockUserService.AssertExpectations(t)
 	})
@@ -82,7 +81,7 @@ func TestVerifyOTP(t *testing.T) {
 
 		resp := performRequest(map[string]string{"userUUID": "user-123", "otp": "123456"})
 		assert.Equal(t, http.StatusNotFound, resp.Code)
-		assert.JSONEq(t, `{"error":"User not found"}`, resp.Body.String())
+		assert.JSONEq(t, `{"error":{"code":"USER_NOT_FOUND","message":"User not found"}}`, resp.Body.String())
 
 		mockUserService.AssertExpectations(t)
 	})
@@ -100,7 +99,7 @@ func TestVerifyOTP(t *testing.T) {
 
 		resp := performRequest(map[string]string{"userUUID": "user-123", "otp": "wrong-otp"})
 		assert.Equal(t, http.StatusUnauthorized, resp.Code)
-		assert.JSONEq(t, `{"error":"Invalid OTP"}`, resp.Body.String())
+		assert.JSONEq(t, `{"error":{"code":"OTP_INVALID","message":"Invalid OTP"}}`, resp.Body.String())
 
 		mockUserService.AssertExpectations(t)
 		mockAuthService.AssertExpectations(t)
@@ -148,7 +147,7 @@ func TestVerifyOTP(t *testing.T) {
 
 		resp := performRequest(map[string]string{"userUUID": "user-123", "otp": "valid-otp"})
 		assert.Equal(t, http.StatusInternalServerError, resp.Code)
-		assert.JSONEq(t, `{"error":"Failed to enable 2FA"}`, resp.Body.String())
+		assert.JSONEq(t, `{"error":{"code":"INTERNAL_ERROR","message":"Failed to enable 2FA"}}`, resp.Body.String())
 
 		mockUserService.AssertExpectations(t)
 		mockAuthService.AssertExpectations(t)
@@ -174,7 +173,7 @@ func TestVerifyOTP(t *testing.T) {
 
 		resp := performRequest(map[string]string{"userUUID": "user-123", "otp": "valid-otp"})
 		assert.Equal(t, http.StatusInternalServerError, resp.Code)
-		assert.JSONEq(t, `{"error":"Failed to generate JWT"}`, resp.Body.String())
+		assert.JSONEq(t, `{"error":{"code":"TOKEN_GENERATION_FAILED","message":"Failed to generate token"}}`, resp.Body.String())
 
 		mockUserService.AssertExpectations(t)
 		mockAuthService.AssertExpectations(t)
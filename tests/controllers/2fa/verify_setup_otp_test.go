@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	controller "cry-api/app/controllers/2fa"
+	"cry-api/app/middleware"
 	UserModel "cry-api/app/models"
 	JWT "cry-api/app/services/jwt"
 	types "cry-api/app/types/2fa"
@@ -30,29 +31,27 @@ func TestVerifySetUpOTP(t *testing.T) {
 		AuthService: mockAuthService,
 	}
 
-	performRequest := func(body any) *httptest.ResponseRecorder {
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-
-		jsonBytes, _ := json.Marshal(body)
-		c.Request, _ = http.NewRequest("POST", "/verify-setup-otp", bytes.NewReader(jsonBytes))
-		c.Request.Header.Set("Content-Type", "application/json")
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.POST("/verify-setup-otp", ctrl.VerifySetUpOTP)
 
-		ctrl.VerifySetUpOTP(c)
+	send := func(body []byte) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/verify-setup-otp", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
 		return w
 	}
+	performRequest := func(body any) *httptest.ResponseRecorder {
+		jsonBytes, _ := json.Marshal(body)
+		return send(jsonBytes)
+	}
 
 	t.Run("Invalid JSON", func(t *testing.T) {
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-
-		c.Request, _ = http.NewRequest("POST", "/verify-setup-otp", bytes.NewReader([]byte("{invalid-json")))
-		c.Request.Header.Set("Content-Type", "application/json")
-
-		ctrl.VerifySetUpOTP(c)
+		w := send([]byte("{invalid-json"))
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
-		assert.JSONEq(t, `{"error":"Invalid request"}`, w.Body.String())
+		assert.JSONEq(t, `{"error":{"code":"INVALID_JSON","message":"Invalid JSON format"}}`, w.Body.String())
 	})
 
 	t.Run("Missing UserUUID", func(t *testing.T) {
@@ -61,7 +60,7 @@ func TestVerifySetUpOTP(t *testing.T) {
 			Secret: stringPtr("secret123"),
 		})
 		assert.Equal(t, http.StatusBadRequest, resp.Code)
-		assert.JSONEq(t, `{"error":"User UUID is required"}`, resp.Body.String())
+		assert.JSONEq(t, `{"error":{"code":"VALIDATION_FAILED","message":"User UUID is required","fields":[{"field":"uuid","code":"INVALID","message":"User UUID is required"}]}}`, resp.Body.String())
 	})
 
 	t.Run("Missing OTP", func(t *testing.T) {
@@ -70,7 +69,7 @@ func TestVerifySetUpOTP(t *testing.T) {
 			Secret:   stringPtr("secret123"),
 		})
 		assert.Equal(t, http.StatusBadRequest, resp.Code)
-		assert.JSONEq(t, `{"error":"OTP is required for verification"}`, resp.Body.String())
+		assert.JSONEq(t, `{"error":{"code":"VALIDATION_FAILED","message":"OTP is required for verification","fields":[{"field":"otp","code":"INVALID","message":"OTP is required for verification"}]}}`, resp.Body.String())
 	})
 
 	t.Run("Missing Secret", func(t *testing.T) {
@@ -79,7 +78,7 @@ func TestVerifySetUpOTP(t *testing.T) {
 			OTP:      stringPtr("123456"),
 		})
 		assert.Equal(t, http.StatusBadRequest, resp.Code)
-		assert.JSONEq(t, `{"error":"TOTP secret is required"}`, resp.Body.String())
+		assert.JSONEq(t, `{"error":{"code":"VALIDATION_FAILED","message":"TOTP secret is required","fields":[{"field":"secret","code":"INVALID","message":"TOTP secret is required"}]}}`, resp.Body.String())
 	})
 
 	t.Run("OTP verification failure", func(t *testing.T) {
@@ -91,7 +90,7 @@ func TestVerifySetUpOTP(t *testing.T) {
 			Secret:   stringPtr("secret123"),
 		})
 		assert.Equal(t, http.StatusUnauthorized, resp.Code)
-		assert.JSONEq(t, `{"error":"Invalid OTP"}`, resp.Body.String())
+		assert.JSONEq(t, `{"error":{"code":"OTP_INVALID","message":"Invalid OTP"}}`, resp.Body.String())
 
 		mockAuthService.AssertExpectations(t)
 	})
@@ -105,7 +104,7 @@ func TestVerifySetUpOTP(t *testing.T) {
 			Secret:   stringPtr("secret123"),
 		})
 		assert.Equal(t, http.StatusUnauthorized, resp.Code)
-		assert.JSONEq(t, `{"error":"Invalid OTP"}`, resp.Body.String())
+		assert.JSONEq(t, `{"error":{"code":"OTP_INVALID","message":"Invalid OTP"}}`, resp.Body.String())
 
 		mockAuthService.AssertExpectations(t)
 	})
@@ -120,7 +119,7 @@ func TestVerifySetUpOTP(t *testing.T) {
 			Secret:   stringPtr("secret123"),
 		})
 		assert.Equal(t, http.StatusInternalServerError, resp.Code)
-		assert.JSONEq(t, `{"error":"Failed to get user"}`, resp.Body.String())
+		assert.JSONEq(t, `{"error":{"code":"INTERNAL_ERROR","message":"Failed to get user"}}`, resp.Body.String())
 
 		mockAuthService.AssertExpectations(t)
 		mockUserService.AssertExpectations(t)
@@ -136,7 +135,7 @@ func TestVerifySetUpOTP(t *testing.T) {
 			Secret:   stringPtr("secret123"),
 		})
 		assert.Equal(t, http.StatusNotFound, resp.Code)
-		assert.JSONEq(t, `{"error":"User not found"}`, resp.Body.String())
+		assert.JSONEq(t, `{"error":{"code":"USER_NOT_FOUND","message":"User not found"}}`, resp.Body.String())
 
 		mockAuthService.AssertExpectations(t)
 		mockUserService.AssertExpectations(t)
@@ -159,7 +158,7 @@ func TestVerifySetUpOTP(t *testing.T) {
 			Secret:   stringPtr("secret123"),
 		})
 		assert.Equal(t, http.StatusInternalServerError, resp.Code)
-		assert.JSONEq(t, `{"error":"Failed to enable 2FA"}`, resp.Body.String())
+		assert.JSONEq(t, `{"error":{"code":"INTERNAL_ERROR","message":"Failed to enable 2FA"}}`, resp.Body.String())
 
 		mockAuthService.AssertExpectations(t)
 		mockUserService.AssertExpectations(t)
@@ -188,7 +187,7 @@ func TestVerifySetUpOTP(t *testing.T) {
 		})
 
 		assert.Equal(t, http.StatusInternalServerError, resp.Code)
-		assert.JSONEq(t, `{"error":"Failed to generate JWT"}`, resp.Body.String())
+		assert.JSONEq(t, `{"error":{"code":"TOKEN_GENERATION_FAILED","message":"Failed to generate token"}}`, resp.Body.String())
 
 		mockAuthService.AssertExpectations(t)
 		mockUserService.AssertExpectations(t)
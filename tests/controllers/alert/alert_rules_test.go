@@ -0,0 +1,172 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	controllers "cry-api/app/controllers/alert"
+	"cry-api/app/middleware"
+	UserModel "cry-api/app/models"
+	FXService "cry-api/app/services/fx"
+	services "cry-api/app/services/jwt"
+	AlertTypes "cry-api/app/types/alert"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+	app_errors "cry-api/app/types/errors"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testUser = &UserModel.User{ID: 7, UUID: "user-uuid"}
+
+func setupAlertRouter() (*gin.Engine, *testmocks.MockUserService, *testmocks.MockAlertService) {
+	gin.SetMode(gin.TestMode)
+	userService := new(testmocks.MockUserService)
+	alertService := new(testmocks.MockAlertService)
+	ctrl := &controllers.AlertController{
+		UserService:  userService,
+		AlertService: alertService,
+	}
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("user", &services.Claims{UUID: "user-uuid"})
+		c.Next()
+	})
+
+	router.GET("/alerts", ctrl.ListRules)
+	router.POST("/alerts", ctrl.CreateRule)
+	router.GET("/alerts/triggers", ctrl.ListTriggers)
+	router.GET("/alerts/:id", ctrl.GetRule)
+	router.PUT("/alerts/:id", ctrl.UpdateRule)
+	router.DELETE("/alerts/:id", ctrl.DeleteRule)
+	router.GET("/alerts/:id/triggers", ctrl.ListTriggers)
+	return router, userService, alertService
+}
+
+func serve(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAlerts_CreateRule(t *testing.T) {
+	router, userService, alertService := setupAlertRouter()
+
+	w := serve(router, http.MethodPost, "/alerts", `{"symbol":"BTC"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	userService.On("GetUserByUUID", "user-uuid").Return(testUser, nil).Twice()
+	threshold := json.Number("70000")
+	alertService.On("CreateRule", 7, AlertTypes.ICreateAlertRuleRequest{Condition: "price_above", Symbol: "BTC", Threshold: &threshold}).
+		Return(&UserModel.AlertRule{ID: 3, UserID: 7, Condition: "price_above", Symbol: "BTC", Currency: "USD", Threshold: "70000", Mode: "once", Active: true}, nil).Once()
+
+	w = serve(router, http.MethodPost, "/alerts", `{"condition":"price_above","symbol":"BTC","threshold":70000}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"alert":{"id":3`)
+	assert.Contains(t, w.Body.String(), `"last_triggered_at":null`)
+
+	alertService.On("CreateRule", 7, mock.Anything).
+		Return(nil, app_errors.NewValidationError("condition", "price", "Condition must be one of ...")).Once()
+	w = serve(router, http.MethodPost, "/alerts", `{"condition":"price"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	alertService.AssertExpectations(t)
+}
+
+func TestAlerts_GetUpdateDeleteRule(t *testing.T) {
+	router, userService, alertService := setupAlertRouter()
+
+	w := serve(router, http.MethodGet, "/alerts/abc", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	userService.On("GetUserByUUID", "user-uuid").Return(testUser, nil)
+	alertService.On("GetRule", 7, 9).Return(nil, app_errors.NewNotFoundError("alert_rule", "Alert rule not found")).Once()
+	w = serve(router, http.MethodGet, "/alerts/9", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	active := false
+	alertService.On("UpdateRule", 7, 3, AlertTypes.IUpdateAlertRuleRequest{Active: &active}).
+		Return(&UserModel.AlertRule{ID: 3, UserID: 7, Active: false}, nil).Once()
+	w = serve(router, http.MethodPut, "/alerts/3", `{"active":false}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"active":false`)
+
+	alertService.On("DeleteRule", 7, 3).Return(nil).Once()
+	w = serve(router, http.MethodDelete, "/alerts/3", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"success":true,"message":"Alert rule removed"}`, w.Body.String())
+	alertService.AssertExpectations(t)
+}
+
+func TestAlerts_ListRulesAndTriggers(t *testing.T) {
+	router, userService, alertService := setupAlertRouter()
+
+	userService.On("GetUserByUUID", "user-uuid").Return(testUser, nil)
+	alertService.On("ListRules", 7).Return([]UserModel.AlertRule{}, nil).Once()
+	w := serve(router, http.MethodGet, "/alerts", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"alerts":[]}`, w.Body.String())
+
+	triggers := []UserModel.AlertTrigger{{ID: 5, AlertRuleID: 3, UserID: 7, Condition: "price_above", Title: "BTC is above 70000 USD", Value: "70100"}}
+	alertService.On("ListTriggers", 7, 0).Return(triggers, nil).Once()
+	w = serve(router, http.MethodGet, "/alerts/triggers", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"title":"BTC is above 70000 USD"`)
+
+	alertService.On("ListTriggers", 7, 3).Return(triggers, nil).Once()
+	w = serve(router, http.MethodGet, "/alerts/3/triggers", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"alert_rule_id":3`)
+	alertService.AssertExpectations(t)
+}
+
+func TestAlerts_ConvertsPricesToTheUserCurrency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userService := new(testmocks.MockUserService)
+	alertService := new(testmocks.MockAlertService)
+	market := new(testmocks.MockCoinMarketCapService)
+	ctrl := &controllers.AlertController{
+		UserService:  userService,
+		AlertService: alertService,
+		FXService:    FXService.NewFXService(market, nil),
+	}
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user", &services.Claims{UUID: "user-uuid"})
+		c.Next()
+	})
+	router.GET("/alerts", ctrl.ListRules)
+	router.GET("/alerts/triggers", ctrl.ListTriggers)
+
+	userService.On("GetUserByUUID", "user-uuid").Return(&UserModel.User{ID: 7, UUID: "user-uuid", FiatCurrency: "EUR"}, nil)
+	market.On("GetLatestQuotes", []string{"BTC"}, []int(nil), "USD").Return([]CoinMarketCap.IQuote{{Symbol: "BTC", Price: "50000"}}, nil)
+	market.On("GetLatestQuotes", []string{"BTC"}, []int(nil), "EUR").Return([]CoinMarketCap.IQuote{{Symbol: "BTC", Price: "45000"}}, nil)
+
+	alertService.On("ListRules", 7).Return([]UserModel.AlertRule{
+		{ID: 1, Condition: UserModel.AlertConditionPriceAbove, Symbol: "BTC", Currency: "USD", Threshold: "70000"},
+		{ID: 2, Condition: UserModel.AlertConditionFearGreedBelow, Threshold: "20"},
+	}, nil).Once()
+	w := serve(router, http.MethodGet, "/alerts", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"threshold":"70000"`)
+	assert.Contains(t, w.Body.String(), `"fiat":{"currency":"EUR","amount":"63000.00","minor_units":6300000}`)
+	assert.Equal(t, 1, strings.Count(w.Body.String(), `"fiat"`))
+
+	alertService.On("ListTriggers", 7, 0).Return([]UserModel.AlertTrigger{
+		{ID: 5, Condition: UserModel.AlertConditionPriceAbove, Value: "70100.5", Currency: "USD"},
+		{ID: 6, Condition: UserModel.AlertConditionPercentChange, Value: "-12.5"},
+	}, nil).Once()
+	w = serve(router, http.MethodGet, "/alerts/triggers", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	// 70100.5 * 0.9 = 63090.45
+	assert.Contains(t, w.Body.String(), `"fiat":{"currency":"EUR","amount":"63090.45","minor_units":6309045}`)
+	assert.Equal(t, 1, strings.Count(w.Body.String(), `"fiat"`))
+}
@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	controllers "cry-api/app/controllers/coin_market_cap"
+	"cry-api/app/middleware"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+	app_errors "cry-api/app/types/errors"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupFearGreedRouter() (*gin.Engine, *testmocks.MockFearGreedService) {
+	gin.SetMode(gin.TestMode)
+	fearGreedService := new(testmocks.MockFearGreedService)
+	ctrl := &controllers.CoinMarketCapController{FearGreedService: fearGreedService}
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.GET("/coin-market-cap/fear-and-greed", ctrl.GetFearAndGreed)
+	return router, fearGreedService
+}
+
+func TestGetFearAndGreed(t *testing.T) {
+	router, fearGreedService := setupFearGreedRouter()
+
+	stats := CoinMarketCap.IFearGreedStats{Min: 20, Max: 60, Avg: 36.67, Median: 30, Count: 3}
+	fearGreedService.On("GetRange", "2024-09-01", "2024-10-31", "weekly").Return(&CoinMarketCap.IFearGreedRange{
+		From:     "2024-09-01",
+		To:       "2024-10-31",
+		Interval: "weekly",
+		Points:   []CoinMarketCap.IFearGreedPoint{{Date: "2024-09-30", Classification: "Fear", IFearGreedStats: stats}},
+		Stats:    &stats,
+	}, nil).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/coin-market-cap/fear-and-greed?from=2024-09-01&to=2024-10-31&interval=weekly", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"fear_and_greed":{"from":"2024-09-01"`)
+	assert.Contains(t, w.Body.String(), `{"date":"2024-09-30","classification":"Fear","min":20,"max":60,"avg":36.67,"median":30,"count":3}`)
+	fearGreedService.AssertExpectations(t)
+}
+
+func TestGetFearAndGreed_InvalidInterval(t *testing.T) {
+	router, fearGreedService := setupFearGreedRouter()
+
+	fearGreedService.On("GetRange", "", "", "hourly").
+		Return(nil, app_errors.NewValidationError("interval", "hourly", "Interval must be one of daily, weekly or monthly")).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/coin-market-cap/fear-and-greed?interval=hourly", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Interval must be one of daily, weekly or monthly")
+	fearGreedService.AssertExpectations(t)
+}
+
+func TestGetFearAndGreedAnalytics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	analyticsService := new(testmocks.MockFearGreedAnalyticsService)
+	ctrl := &controllers.CoinMarketCapController{AnalyticsService: analyticsService}
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.GET("/coin-market-cap/fear-and-greed/analytics", ctrl.GetFearAndGreedAnalytics)
+
+	price, change := 110.0, 10.0
+	analyticsService.On("GetAnalytics", "2024-01-01", "2024-01-31", "14").Return(&CoinMarketCap.IFearGreedAnalytics{
+		From:     "2024-01-01",
+		To:       "2024-01-31",
+		Symbol:   "BTC",
+		Currency: "USD",
+		Window:   14,
+		Series:   []CoinMarketCap.IFearGreedAnalyticsPoint{{Date: "2024-01-01", Value: 10, Classification: "Extreme Fear", Price: &price, Return: &change}},
+	}, nil).Once()
+	analyticsService.On("GetAnalytics", "", "", "3").
+		Return(nil, app_errors.NewValidationError("window", "3", "Window must be a number of days between 7 and 365")).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/coin-market-cap/fear-and-greed/analytics?from=2024-01-01&to=2024-01-31&window=14", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"analytics":{"from":"2024-01-01","to":"2024-01-31","symbol":"BTC","currency":"USD","window":14`)
+	assert.Contains(t, w.Body.String(), `{"date":"2024-01-01","value":10,"classification":"Extreme Fear","price":110,"return_pct":10}`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/coin-market-cap/fear-and-greed/analytics?window=3", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Window must be a number of days between 7 and 365")
+	analyticsService.AssertExpectations(t)
+}
+
+func TestGetFearAndGreedLatestAndHistorical_UpstreamError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	coinMarketCapService := new(testmocks.MockCoinMarketCapService)
+	ctrl := &controllers.CoinMarketCapController{CoinMarketCapService: coinMarketCapService}
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.GET("/coin-market-cap/fear-and-greed/latest", ctrl.GetFearAndGreedLastest)
+	router.GET("/coin-market-cap/fear-and-greed/historical", ctrl.GetFearAndGreedHistorical)
+
+	upstream := errors.New("API request failed with status 401: invalid key abc123")
+	coinMarketCapService.On("GetFearAndGreedLastest").Return(nil, upstream).Once()
+	coinMarketCapService.On("GetFearAndGreedHistorical", 1, 500).Return(nil, upstream).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/coin-market-cap/fear-and-greed/latest", nil))
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	assert.JSONEq(t, `{"error":{"code":"UPSTREAM_ERROR","message":"Failed to fetch the fear and greed index"}}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/coin-market-cap/fear-and-greed/historical", nil))
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	assert.JSONEq(t, `{"error":{"code":"UPSTREAM_ERROR","message":"Failed to fetch the fear and greed history"}}`, w.Body.String())
+	coinMarketCapService.AssertExpectations(t)
+}
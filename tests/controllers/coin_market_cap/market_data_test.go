@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	controllers "cry-api/app/controllers/coin_market_cap"
+	"cry-api/app/middleware"
+	"cry-api/app/money"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+	app_errors "cry-api/app/types/errors"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupMarketDataRouter() (*gin.Engine, *testmocks.MockCoinMarketCapService) {
+	gin.SetMode(gin.TestMode)
+	coinMarketCapService := new(testmocks.MockCoinMarketCapService)
+	ctrl := &controllers.CoinMarketCapController{CoinMarketCapService: coinMarketCapService}
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.GET("/coin-market-cap/quotes", ctrl.GetQuotes)
+	router.GET("/coin-market-cap/ohlcv", ctrl.GetOHLCV)
+	router.GET("/coin-market-cap/convert", ctrl.ConvertPrice)
+	return router, coinMarketCapService
+}
+
+func get(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestGetQuotes(t *testing.T) {
+	router, coinMarketCapService := setupMarketDataRouter()
+
+	coinMarketCapService.On("GetLatestQuotes", []string{"BTC", "eth"}, []int(nil), "EUR").
+		Return([]CoinMarketCap.IQuote{{ID: 1, Symbol: "BTC", Currency: "EUR", Price: "61234.5"}}, nil).Once()
+
+	w := get(router, "/coin-market-cap/quotes?symbols=BTC,,eth&convert=EUR")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"quotes":[{"id":1,"symbol":"BTC"`)
+	assert.Contains(t, w.Body.String(), `"price":"61234.5"`)
+	coinMarketCapService.AssertExpectations(t)
+}
+
+func TestGetQuotes_InvalidIDs(t *testing.T) {
+	router, coinMarketCapService := setupMarketDataRouter()
+
+	w := get(router, "/coin-market-cap/quotes?ids=1,bitcoin")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	coinMarketCapService.AssertNotCalled(t, "GetLatestQuotes", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetOHLCV(t *testing.T) {
+	router, coinMarketCapService := setupMarketDataRouter()
+
+	from := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 10, 3, 12, 0, 0, 0, time.UTC)
+	coinMarketCapService.On("GetOHLCV", "BTC", "USD", "hourly", from, to).Return(&CoinMarketCap.IOHLCV{
+		Symbol: "BTC", Currency: "USD", Interval: "hourly", From: from, To: to,
+		Candles: []CoinMarketCap.ICandle{{TimeOpen: from, Open: "63000", High: "64000", Low: "60000", Close: "60800"}},
+	}, nil).Once()
+
+	w := get(router, "/coin-market-cap/ohlcv?symbol=BTC&convert=USD&interval=hourly&from=2024-10-01&to=2024-10-03T12:00:00Z")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"interval":"hourly"`)
+	assert.Contains(t, w.Body.String(), `"open":"63000","high":"64000","low":"60000","close":"60800"`)
+	coinMarketCapService.AssertExpectations(t)
+}
+
+func TestGetOHLCV_InvalidDate(t *testing.T) {
+	router, _ := setupMarketDataRouter()
+
+	w := get(router, "/coin-market-cap/ohlcv?symbol=BTC&from=01/10/2024")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "from must be a date like 2024-01-31")
+}
+
+func TestConvertPrice(t *testing.T) {
+	router, coinMarketCapService := setupMarketDataRouter()
+
+	coinMarketCapService.On("ConvertPrice", big.NewRat(1, 4), "BTC", "EUR").
+		Return(&CoinMarketCap.IConversion{
+			Symbol:   "BTC",
+			Amount:   "0.25",
+			Currency: "EUR",
+			Rate:     "60000",
+			Value:    money.Amount{Currency: "EUR", Value: "15000.00", MinorUnits: 1500000},
+		}, nil).Once()
+
+	w := get(router, "/coin-market-cap/convert?amount=0.25&symbol=BTC&convert=EUR")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"conversion":{"symbol":"BTC","amount":"0.25","currency":"EUR","rate":"60000","value":{"currency":"EUR","amount":"15000.00","minor_units":1500000}`)
+	coinMarketCapService.AssertExpectations(t)
+}
+
+func TestConvertPrice_Errors(t *testing.T) {
+	router, coinMarketCapService := setupMarketDataRouter()
+
+	w := get(router, "/coin-market-cap/convert?amount=lots&symbol=BTC")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	coinMarketCapService.On("ConvertPrice", big.NewRat(1, 1), "BTC", "").
+		Return(nil, app_errors.NewAppError(http.StatusBadGateway, "Failed to convert price", "API request failed with status 429")).Once()
+
+	w = get(router, "/coin-market-cap/convert?amount=1&symbol=BTC")
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	assert.Contains(t, w.Body.String(), "Failed to convert price")
+}
@@ -0,0 +1,227 @@
+package tests
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	controllers "cry-api/app/controllers/label"
+	"cry-api/app/metrics"
+	"cry-api/app/middleware"
+	UserModel "cry-api/app/models"
+	services "cry-api/app/services/jwt"
+	app_errors "cry-api/app/types/errors"
+	LabelTypes "cry-api/app/types/label"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var testUser = &UserModel.User{ID: 7, UUID: "user-uuid"}
+
+func setupLabelRouter(ctrl *controllers.LabelController) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("user", &services.Claims{UUID: "user-uuid"})
+		c.Next()
+	})
+
+	router.GET("/labels", ctrl.ListLabels)
+	router.PUT("/labels", ctrl.SetLabel)
+	router.GET("/labels/export", ctrl.ExportLabels)
+	router.POST("/labels/import", ctrl.ImportLabels)
+	router.DELETE("/labels/:id", ctrl.DeleteLabel)
+	return router
+}
+
+func newLabelController() (*controllers.LabelController, *testmocks.MockUserService, *testmocks.MockLabelService) {
+	userService := new(testmocks.MockUserService)
+	labelService := new(testmocks.MockLabelService)
+	return &controllers.LabelController{
+		UserService:  userService,
+		LabelService: labelService,
+	}, userService, labelService
+}
+
+func TestLabels_ListLabels(t *testing.T) {
+	ctrl, userService, labelService := newLabelController()
+	router := setupLabelRouter(ctrl)
+
+	userService.On("GetUserByUUID", "user-uuid").Return(testUser, nil).Once()
+	labelService.On("ListLabels", 7, "addr").Return([]UserModel.Label{{ID: 1, Type: "addr", Ref: "bc1q", Label: "savings"}}, nil).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/labels?type=addr", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"label":"savings"`)
+}
+
+func TestLabels_SetLabel(t *testing.T) {
+	ctrl, userService, labelService := newLabelController()
+	router := setupLabelRouter(ctrl)
+
+	t.Run("Success", func(t *testing.T) {
+		req := LabelTypes.ILabelRecord{Type: "tx", Ref: "abc", Label: "rent"}
+		userService.On("GetUserByUUID", "user-uuid").Return(testUser, nil).Once()
+		labelService.On("SetLabel", 7, req).Return(&UserModel.Label{ID: 2, Type: "tx", Ref: "abc", Label: "rent"}, nil).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/labels", strings.NewReader(`{"type":"tx","ref":"abc","label":"rent"}`)))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"id":2`)
+	})
+
+	t.Run("Missing ref", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/labels", strings.NewReader(`{"type":"tx"}`)))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Invalid reference", func(t *testing.T) {
+		req := LabelTypes.ILabelRecord{Type: "tx", Ref: "abc"}
+		userService.On("GetUserByUUID", "user-uuid").Return(testUser, nil).Once()
+		labelService.On("SetLabel", 7, req).Return(nil, app_errors.NewValidationError("ref", "abc", "Transaction id must be 64 hex characters")).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/labels", strings.NewReader(`{"type":"tx","ref":"abc"}`)))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Transaction id must be 64 hex characters")
+	})
+}
+
+func TestLabels_DeleteLabel_InvalidID(t *testing.T) {
+	ctrl, _, _ := newLabelController()
+	router := setupLabelRouter(ctrl)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/labels/abc", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLabels_ImportLabels(t *testing.T) {
+	ctrl, userService, labelService := newLabelController()
+	router := setupLabelRouter(ctrl)
+	content := `{"type":"tx","ref":"abc","label":"rent"}` + "\n"
+	result := &LabelTypes.IImportResult{Imported: 1, Errors: []LabelTypes.IImportError{}}
+
+	t.Run("Raw body", func(t *testing.T) {
+		userService.On("GetUserByUUID", "user-uuid").Return(testUser, nil).Once()
+		labelService.On("ImportLabels", 7, content).Return(result, nil).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/labels/import", strings.NewReader(content))
+		req.Header.Set("Content-Type", "application/jsonl")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"import":{"imported":1,"updated":0,"skipped":0,"errors":[]}}`, w.Body.String())
+	})
+
+	t.Run("Multipart file", func(t *testing.T) {
+		userService.On("GetUserByUUID", "user-uuid").Return(testUser, nil).Once()
+		labelService.On("ImportLabels", 7, content).Return(result, nil).Once()
+
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, _ := form.CreateFormFile("file", "labels.jsonl")
+		_, _ = part.Write([]byte(content))
+		_ = form.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/labels/import", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	labelService.AssertExpectations(t)
+}
+
+// TestLabels_ImportLabels_GlobalChain sends the import through the global middleware chain,
+// whose content type check must let multipart and JSON Lines bodies through.
+func TestLabels_ImportLabels_GlobalChain(t *testing.T) {
+	t.Setenv("JWT_SECRET", "testsecretkey1234567890")
+	token, err := services.GenerateJWT("user-uuid", "user@example.com", false, false)
+	assert.NoError(t, err)
+
+	ctrl, userService, labelService := newLabelController()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	middleware.UseGlobal(router, metrics.New())
+	labels := router.Group("/labels", middleware.JWTAuthMiddleware())
+	labels.POST("/import", ctrl.ImportLabels)
+
+	content := `{"type":"tx","ref":"abc","label":"rent"}` + "\n"
+	result := &LabelTypes.IImportResult{Imported: 1, Errors: []LabelTypes.IImportError{}}
+
+	send := func(body io.Reader, contentType string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/labels/import", body)
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Multipart file", func(t *testing.T) {
+		userService.On("GetUserByUUID", "user-uuid").Return(testUser, nil).Once()
+		labelService.On("ImportLabels", 7, content).Return(result, nil).Once()
+
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, _ := form.CreateFormFile("file", "labels.jsonl")
+		_, _ = part.Write([]byte(content))
+		_ = form.Close()
+
+		w := send(&body, form.FormDataContentType())
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"imported":1`)
+	})
+
+	t.Run("Raw JSON Lines", func(t *testing.T) {
+		userService.On("GetUserByUUID", "user-uuid").Return(testUser, nil).Once()
+		labelService.On("ImportLabels", 7, content).Return(result, nil).Once()
+
+		w := send(strings.NewReader(content), "application/x-ndjson")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Unsupported content type", func(t *testing.T) {
+		w := send(strings.NewReader(content), "text/plain")
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	})
+
+	labelService.AssertExpectations(t)
+}
+
+func TestLabels_ExportLabels(t *testing.T) {
+	ctrl, userService, labelService := newLabelController()
+	router := setupLabelRouter(ctrl)
+
+	userService.On("GetUserByUUID", "user-uuid").Return(testUser, nil).Once()
+	labelService.On("ExportLabels", 7).Return(`{"type":"tx","ref":"abc","label":"rent"}`+"\n", nil).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/labels/export", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/jsonl", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="labels.jsonl"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, `{"type":"tx","ref":"abc","label":"rent"}`+"\n", w.Body.String())
+}
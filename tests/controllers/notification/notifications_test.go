@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	controllers "cry-api/app/controllers/notification"
+	"cry-api/app/middleware"
+	UserModel "cry-api/app/models"
+	services "cry-api/app/services/jwt"
+	app_errors "cry-api/app/types/errors"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var testUser = &UserModel.User{ID: 7, UUID: "user-uuid"}
+
+func setupNotificationRouter() (*gin.Engine, *testmocks.MockUserService, *testmocks.MockNotificationService) {
+	gin.SetMode(gin.TestMode)
+	userService := new(testmocks.MockUserService)
+	notificationService := new(testmocks.MockNotificationService)
+	ctrl := &controllers.NotificationController{
+		UserService:         userService,
+		NotificationService: notificationService,
+	}
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("user", &services.Claims{UUID: "user-uuid"})
+		c.Next()
+	})
+
+	router.GET("/notifications", ctrl.ListNotifications)
+	router.POST("/notifications/read", ctrl.MarkAllRead)
+	router.POST("/notifications/:id/read", ctrl.MarkRead)
+	return router, userService, notificationService
+}
+
+func serve(router *gin.Engine, method, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w
+}
+
+func TestNotifications_ListNotifications(t *testing.T) {
+	router, userService, notificationService := setupNotificationRouter()
+
+	userService.On("GetUserByUUID", "user-uuid").Return(testUser, nil).Once()
+	notificationService.On("ListNotifications", 7, true).Return([]UserModel.Notification{
+		{ID: 2, UserID: 7, WatchedWalletID: 1, TxID: "abc", Type: UserModel.NotificationTypeIncoming, Title: "Received 0.5 BTC on Savings", Amount: 0.5},
+	}, nil).Once()
+
+	w := serve(router, http.MethodGet, "/notifications?unread=true")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"type":"incoming"`)
+	assert.Contains(t, w.Body.String(), `"read_at":null`)
+
+	w = serve(router, http.MethodGet, "/notifications?unread=maybe")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	notificationService.AssertExpectations(t)
+}
+
+func TestNotifications_MarkRead(t *testing.T) {
+	router, userService, notificationService := setupNotificationRouter()
+
+	w := serve(router, http.MethodPost, "/notifications/abc/read")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	userService.On("GetUserByUUID", "user-uuid").Return(testUser, nil).Twice()
+	notificationService.On("MarkRead", 7, 9).Return(nil, app_errors.NewNotFoundError("notification", "Notification not found")).Once()
+	w = serve(router, http.MethodPost, "/notifications/9/read")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	notificationService.On("MarkRead", 7, 2).Return(&UserModel.Notification{ID: 2, UserID: 7}, nil).Once()
+	w = serve(router, http.MethodPost, "/notifications/2/read")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"notification":{"id":2`)
+}
+
+func TestNotifications_MarkAllRead(t *testing.T) {
+	router, userService, notificationService := setupNotificationRouter()
+
+	userService.On("GetUserByUUID", "user-uuid").Return(testUser, nil).Once()
+	notificationService.On("MarkAllRead", 7).Return(int64(3), nil).Once()
+
+	w := serve(router, http.MethodPost, "/notifications/read")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"success":true,"updated":3}`, w.Body.String())
+}
@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	controllers "cry-api/app/controllers/portfolio"
+	"cry-api/app/middleware"
+	UserModel "cry-api/app/models"
+	"cry-api/app/money"
+	services "cry-api/app/services/jwt"
+	app_errors "cry-api/app/types/errors"
+	PortfolioTypes "cry-api/app/types/portfolio"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupPortfolioRouter(ctrl *controllers.PortfolioController) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("user", &services.Claims{UUID: "user-uuid"})
+		c.Next()
+	})
+	router.GET("/portfolio", ctrl.GetValuation)
+	return router
+}
+
+func TestPortfolioController_GetValuation(t *testing.T) {
+	userService := new(testmocks.MockUserService)
+	portfolioService := new(testmocks.MockPortfolioService)
+	router := setupPortfolioRouter(&controllers.PortfolioController{
+		UserService:      userService,
+		PortfolioService: portfolioService,
+	})
+
+	user := &UserModel.User{ID: 7, UUID: "user-uuid"}
+
+	t.Run("Success", func(t *testing.T) {
+		userService.On("GetUserByUUID", "user-uuid").Return(user, nil).Once()
+		portfolioService.On("GetValuation", 7, "EUR", 90).Return(&PortfolioTypes.IPortfolioValuation{
+			Currency: "EUR",
+			Value:    money.Amount{Currency: "EUR", Value: "1234.50", MinorUnits: 123450},
+			Points:   []PortfolioTypes.IPortfolioPoint{},
+		}, nil).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/portfolio?currency=EUR&days=90", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"value":{"currency":"EUR","amount":"1234.50","minor_units":123450}`)
+	})
+
+	t.Run("Defaults to the user's fiat currency", func(t *testing.T) {
+		userService.On("GetUserByUUID", "user-uuid").Return(&UserModel.User{ID: 7, UUID: "user-uuid", FiatCurrency: "JPY"}, nil).Once()
+		portfolioService.On("GetValuation", 7, "JPY", 0).Return(&PortfolioTypes.IPortfolioValuation{
+			Currency: "JPY",
+			Points:   []PortfolioTypes.IPortfolioPoint{},
+		}, nil).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/portfolio", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"currency":"JPY"`)
+	})
+
+	t.Run("Defaults to USD without a fiat currency", func(t *testing.T) {
+		userService.On("GetUserByUUID", "user-uuid").Return(&UserModel.User{ID: 7, UUID: "user-uuid"}, nil).Once()
+		portfolioService.On("GetValuation", 7, "USD", 0).Return(&PortfolioTypes.IPortfolioValuation{
+			Currency: "USD",
+			Points:   []PortfolioTypes.IPortfolioPoint{},
+		}, nil).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/portfolio", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"currency":"USD"`)
+	})
+
+	t.Run("Invalid days", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/portfolio?days=abc", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":{"code":"VALIDATION_FAILED","message":"Days must be a number","fields":[{"field":"days","code":"INVALID","message":"Days must be a number","value":"abc"}]}}`, w.Body.String())
+	})
+
+	t.Run("Service validation error", func(t *testing.T) {
+		userService.On("GetUserByUUID", "user-uuid").Return(user, nil).Once()
+		portfolioService.On("GetValuation", 7, "euro", 0).
+			Return(nil, app_errors.NewValidationError("currency", "EURO", "Currency must be an ISO-4217 code like USD")).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/portfolio?currency=euro", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	portfolioService.AssertExpectations(t)
+}
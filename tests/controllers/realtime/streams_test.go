@@ -0,0 +1,238 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	controllers "cry-api/app/controllers/realtime"
+	"cry-api/app/middleware"
+	UserModel "cry-api/app/models"
+	services "cry-api/app/services/jwt"
+	RealtimeService "cry-api/app/services/realtime"
+	EnvTypes "cry-api/app/types/env"
+	RealtimeTypes "cry-api/app/types/realtime"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testUser = &UserModel.User{ID: 7, UUID: "user-uuid"}
+
+func setupRealtimeServer(t *testing.T) (*httptest.Server, *RealtimeService.Hub) {
+	return setupRealtimeServerWithClaims(t, &services.Claims{UUID: "user-uuid"})
+}
+
+func setupRealtimeServerWithClaims(t *testing.T, claims *services.Claims) (*httptest.Server, *RealtimeService.Hub) {
+	gin.SetMode(gin.TestMode)
+	cfg := &EnvTypes.EnvConfig{
+		RealtimeConfig: EnvTypes.RealtimeConfig{HeartbeatInterval: 1, BufferSize: 8, MaxSubscriptions: 2, PriceInterval: 15},
+	}
+	hub := RealtimeService.NewHub(cfg)
+	userService := new(testmocks.MockUserService)
+	userService.On("GetUserByUUID", "user-uuid").Return(testUser, nil)
+	ctrl := &controllers.RealtimeController{
+		UserService: userService,
+		Hub:         hub,
+		Config:      cfg,
+	}
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("user", claims)
+		c.Next()
+	})
+	router.GET("/realtime/sse", ctrl.Stream)
+	router.GET("/realtime/ws", ctrl.WebSocket)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server, hub
+}
+
+// readSSE reads the next event of a stream, skipping heartbeats, as its name and data
+func readSSE(t *testing.T, reader *bufio.Reader) (string, string) {
+	var name, data string
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "" && name != "":
+			return name, data
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+// readControl reads the next control message of a WebSocket
+func readControl(t *testing.T, conn *websocket.Conn) RealtimeTypes.IControlMessage {
+	var control RealtimeTypes.IControlMessage
+	require.NoError(t, wsjson.Read(context.Background(), conn, &control))
+	return control
+}
+
+// dial opens a WebSocket to the test server
+func dial(t *testing.T, server *httptest.Server, path string) *websocket.Conn {
+	conn, _, err := websocket.Dial(context.Background(), server.URL+path, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.CloseNow() })
+	return conn
+}
+
+// expiringClaims returns claims whose token expires shortly
+func expiringClaims() *services.Claims {
+	return &services.Claims{
+		UUID:             "user-uuid",
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(300 * time.Millisecond))},
+	}
+}
+
+func TestRealtime_Stream(t *testing.T) {
+	server, hub := setupRealtimeServer(t)
+
+	resp, err := http.Get(server.URL + "/realtime/sse?topics=news")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/realtime/sse?topics=price:btc,wallets", nil)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(resp.Body)
+	name, data := readSSE(t, reader)
+	assert.Equal(t, "subscribed", name)
+	assert.JSONEq(t, `{"type":"subscribed","topics":["price:BTC","wallets"]}`, data)
+
+	hub.PublishToUser(8, RealtimeService.TopicWallets, "someone else's")
+	hub.PublishToUser(7, RealtimeService.TopicWallets, map[string]string{"txid": "abc"})
+	name, data = readSSE(t, reader)
+	assert.Equal(t, "wallets", name)
+	var event RealtimeTypes.IEvent
+	require.NoError(t, json.Unmarshal([]byte(data), &event))
+	assert.Equal(t, map[string]any{"txid": "abc"}, event.Data)
+
+	// Heartbeats are comment lines
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, ": ping\n", line)
+
+	cancel()
+	assert.Eventually(t, func() bool { return hub.Stats().Connections == 0 }, jsonTimeout, tick)
+}
+
+func TestRealtime_WebSocket(t *testing.T) {
+	server, hub := setupRealtimeServer(t)
+
+	resp, err := http.Get(server.URL + "/realtime/ws")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "plain requests are refused")
+
+	client := dial(t, server, "/realtime/ws?topics=fear_greed")
+	ctx := context.Background()
+
+	assert.Equal(t, RealtimeTypes.IControlMessage{Type: "subscribed", Topics: []string{"fear_greed"}}, readControl(t, client))
+
+	require.NoError(t, wsjson.Write(ctx, client, RealtimeTypes.IClientMessage{Action: "subscribe", Topics: []string{"price:eth"}}))
+	assert.Equal(t, RealtimeTypes.IControlMessage{Type: "subscribed", Topics: []string{"price:ETH"}}, readControl(t, client))
+
+	require.NoError(t, wsjson.Write(ctx, client, RealtimeTypes.IClientMessage{Action: "subscribe", Topics: []string{"alerts"}}))
+	assert.Equal(t, RealtimeTypes.IControlMessage{Type: "error", Message: "A connection can subscribe to at most 2 topics"}, readControl(t, client))
+
+	require.NoError(t, wsjson.Write(ctx, client, RealtimeTypes.IClientMessage{Action: "watch"}))
+	assert.Equal(t, "Action must be subscribe or unsubscribe", readControl(t, client).Message)
+
+	require.NoError(t, client.Write(ctx, websocket.MessageBinary, []byte("{}")))
+	assert.Equal(t, "Messages must be JSON objects with an action and topics", readControl(t, client).Message)
+
+	hub.Publish("price:ETH", 2500)
+	var event RealtimeTypes.IEvent
+	require.NoError(t, wsjson.Read(ctx, client, &event))
+	assert.Equal(t, "price:ETH", event.Topic)
+	assert.Equal(t, 2500.0, event.Data)
+
+	require.NoError(t, wsjson.Write(ctx, client, RealtimeTypes.IClientMessage{Action: "unsubscribe", Topics: []string{"price:ETH"}}))
+	assert.Equal(t, RealtimeTypes.IControlMessage{Type: "unsubscribed", Topics: []string{"price:ETH"}}, readControl(t, client))
+	assert.False(t, hub.HasSubscribers("price:ETH"))
+
+	// The client answers the pings while it reads, so the connection outlives a few heartbeats
+	readCtx, cancel := context.WithTimeout(ctx, 2500*time.Millisecond)
+	defer cancel()
+	_, _, err = client.Read(readCtx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, hub.Stats().Connections)
+}
+
+func TestRealtime_WebSocketClosedByClient(t *testing.T) {
+	server, hub := setupRealtimeServer(t)
+
+	client := dial(t, server, "/realtime/ws")
+	readControl(t, client)
+
+	require.NoError(t, client.Close(websocket.StatusNormalClosure, ""))
+	assert.Eventually(t, func() bool { return hub.Stats().Connections == 0 }, jsonTimeout, tick)
+}
+
+func TestRealtime_WebSocketRejectsForeignOrigin(t *testing.T) {
+	server, hub := setupRealtimeServer(t)
+
+	_, resp, err := websocket.Dial(context.Background(), server.URL+"/realtime/ws", &websocket.DialOptions{
+		HTTPHeader: http.Header{"Origin": []string{"https://evil.example"}},
+	})
+	require.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, 0, hub.Stats().Connections)
+}
+
+func TestRealtime_StreamEndsWhenTokenExpires(t *testing.T) {
+	server, hub := setupRealtimeServerWithClaims(t, expiringClaims())
+
+	resp, err := http.Get(server.URL + "/realtime/sse?topics=wallets")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	reader := bufio.NewReader(resp.Body)
+	name, _ := readSSE(t, reader)
+	assert.Equal(t, "subscribed", name)
+	name, data := readSSE(t, reader)
+	assert.Equal(t, "error", name)
+	assert.JSONEq(t, `{"type":"error","message":"Access token expired"}`, data)
+	assert.Eventually(t, func() bool { return hub.Stats().Connections == 0 }, jsonTimeout, tick)
+}
+
+func TestRealtime_WebSocketClosedWhenTokenExpires(t *testing.T) {
+	server, hub := setupRealtimeServerWithClaims(t, expiringClaims())
+
+	client := dial(t, server, "/realtime/ws")
+	readControl(t, client)
+	assert.Equal(t, RealtimeTypes.IControlMessage{Type: "error", Message: "Access token expired"}, readControl(t, client))
+
+	_, _, err := client.Read(context.Background())
+	assert.Equal(t, websocket.StatusPolicyViolation, websocket.CloseStatus(err))
+	assert.Eventually(t, func() bool { return hub.Stats().Connections == 0 }, jsonTimeout, tick)
+}
+
+const (
+	jsonTimeout = 2 * time.Second
+	tick        = 10 * time.Millisecond
+)
@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	controllers "cry-api/app/controllers/tax"
+	"cry-api/app/middleware"
+	UserModel "cry-api/app/models"
+	"cry-api/app/money"
+	services "cry-api/app/services/jwt"
+	TaxTypes "cry-api/app/types/tax"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupTaxRouter(ctrl *controllers.TaxController) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("user", &services.Claims{UUID: "user-uuid"})
+		c.Next()
+	})
+	router.GET("/tax/report", ctrl.GetReport)
+	router.DELETE("/tax/transfers/:id", ctrl.UntagTransfer)
+	return router
+}
+
+func usd(minor int64) money.Amount {
+	amount, _ := money.FromMinor(minor, "USD")
+	return amount
+}
+
+func sampleReport() *TaxTypes.ITaxReport {
+	return &TaxTypes.ITaxReport{
+		Method:   "fifo",
+		Currency: "USD",
+		Years: []TaxTypes.ITaxYear{{
+			Year: 2023,
+			Disposals: []TaxTypes.IDisposal{{
+				TxID: "sell", AcquiredTx: "buy", Acquired: "2022-01-10", Disposed: "2023-02-01",
+				Amount: 0.5, Proceeds: usd(1100000), CostBasis: usd(2000000), Gain: usd(-900000), Term: "long", HoldingDays: 387,
+			}},
+		}},
+	}
+}
+
+func TestTaxController_GetReport(t *testing.T) {
+	userService := new(testmocks.MockUserService)
+	taxService := new(testmocks.MockTaxService)
+	router := setupTaxRouter(&controllers.TaxController{UserService: userService, TaxService: taxService})
+
+	user := &UserModel.User{ID: 7, UUID: "user-uuid", FiatCurrency: "EUR"}
+
+	t.Run("JSON", func(t *testing.T) {
+		userService.On("GetUserByUUID", "user-uuid").Return(user, nil).Once()
+		taxService.On("GetReport", 7, 0, "fifo", "EUR", 0).Return(sampleReport(), nil).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tax/report?method=fifo", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"gain":{"currency":"USD","amount":"-9000.00","minor_units":-900000}`)
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		userService.On("GetUserByUUID", "user-uuid").Return(user, nil).Once()
+		taxService.On("GetReport", 7, 3, "", "USD", 2023).Return(sampleReport(), nil).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tax/report?format=csv&year=2023&wallet_id=3&currency=USD", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "capital-gains-2023-fifo.csv")
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		assert.Len(t, lines, 2)
+		assert.Equal(t, "2023,sell,buy,2022-01-10,2023-02-01,0.50000000,11000.00,20000.00,-9000.00,long,387", lines[1])
+	})
+
+	t.Run("Invalid format", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tax/report?format=xlsx", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":{"code":"VALIDATION_FAILED","message":"Format must be json or csv","fields":[{"field":"format","code":"INVALID","message":"Format must be json or csv","value":"xlsx"}]}}`, w.Body.String())
+	})
+
+	t.Run("Invalid year", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tax/report?year=last", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	taxService.AssertExpectations(t)
+}
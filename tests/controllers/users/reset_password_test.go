@@ -11,6 +11,7 @@ import (
 
 	controller "cry-api/app/controllers/users"
 	UserModel "cry-api/app/models"
+	app_errors "cry-api/app/types/errors"
 	UserTypes "cry-api/app/types/users"
 	TestUtils "cry-api/app/utils/tests"
 	testmocks "cry-api/tests/mocks"
@@ -87,9 +88,7 @@ func TestHandleResetPasswordRequest_Success(t *testing.T) {
 	// Make HTTP request
 	req := httptest.NewRequest(http.MethodPost, "/reset-password-request", bytes.NewReader(bodyBytes))
 	w := httptest.NewRecorder()
-	c := TestUtils.GetGinContext(w, req)
-
-	userController.HandleResetPasswordRequest(c)
+	TestUtils.ServeWithErrorHandler(w, req, userController.HandleResetPasswordRequest)
 
 	// wait until the goroutine fires (or timeout)
 	select {
@@ -131,8 +130,7 @@ func TestHandleResetPasswordRequest_InvalidJSON(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/reset-password-request", bytes.NewReader(invalidJSON))
 	w := httptest.NewRecorder()
 
-	c := TestUtils.GetGinContext(w, req)
-	userController.HandleResetPasswordRequest(c)
+	TestUtils.ServeWithErrorHandler(w, req, userController.HandleResetPasswordRequest)
 
 	res := w.Result()
 	defer func() {
@@ -141,10 +139,11 @@ func TestHandleResetPasswordRequest_InvalidJSON(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&respBody)
 	assert.NoError(t, err)
-	assert.Contains(t, respBody["error"], "Invalid JSON")
+	assert.Contains(t, respBody.Error.Message, "Invalid JSON")
+	assert.Equal(t, "INVALID_JSON", respBody.Error.Code)
 }
 
 func TestHandleResetPasswordRequest_UserNotFound(t *testing.T) {
@@ -168,8 +167,7 @@ func TestHandleResetPasswordRequest_UserNotFound(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/reset-password-request", bytes.NewReader(bodyBytes))
 	w := httptest.NewRecorder()
 
-	c := TestUtils.GetGinContext(w, req)
-	userController.HandleResetPasswordRequest(c)
+	TestUtils.ServeWithErrorHandler(w, req, userController.HandleResetPasswordRequest)
 
 	res := w.Result()
 	defer func() {
@@ -178,10 +176,11 @@ func TestHandleResetPasswordRequest_UserNotFound(t *testing.T) {
 
 	assert.Equal(t, http.StatusNotFound, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&respBody)
 	assert.NoError(t, err)
-	assert.Equal(t, "user not found", respBody["error"])
+	assert.Equal(t, "User not found", respBody.Error.Message)
+	assert.Equal(t, "USER_NOT_FOUND", respBody.Error.Code)
 }
 
 func TestHandleResetPasswordRequest_UserNotVerified(t *testing.T) {
@@ -211,8 +210,7 @@ func TestHandleResetPasswordRequest_UserNotVerified(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/reset-password-request", bytes.NewReader(bodyBytes))
 	w := httptest.NewRecorder()
 
-	c := TestUtils.GetGinContext(w, req)
-	userController.HandleResetPasswordRequest(c)
+	TestUtils.ServeWithErrorHandler(w, req, userController.HandleResetPasswordRequest)
 
 	res := w.Result()
 	defer func() {
@@ -221,10 +219,11 @@ func TestHandleResetPasswordRequest_UserNotVerified(t *testing.T) {
 
 	assert.Equal(t, http.StatusForbidden, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&respBody)
 	assert.NoError(t, err)
-	assert.Equal(t, "user not verified", respBody["error"])
+	assert.Equal(t, "User not verified", respBody.Error.Message)
+	assert.Equal(t, "AUTH_USER_NOT_VERIFIED", respBody.Error.Code)
 }
 
 func TestHandleResetPasswordRequest_InternalErrorFindingUser(t *testing.T) {
@@ -248,8 +247,7 @@ func TestHandleResetPasswordRequest_InternalErrorFindingUser(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/reset-password-request", bytes.NewReader(bodyBytes))
 	w := httptest.NewRecorder()
 
-	c := TestUtils.GetGinContext(w, req)
-	userController.HandleResetPasswordRequest(c)
+	TestUtils.ServeWithErrorHandler(w, req, userController.HandleResetPasswordRequest)
 
 	res := w.Result()
 	defer func() {
@@ -258,8 +256,9 @@ func TestHandleResetPasswordRequest_InternalErrorFindingUser(t *testing.T) {
 
 	assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&respBody)
 	assert.NoError(t, err)
-	assert.Equal(t, "internal error", respBody["error"])
+	assert.Equal(t, "Internal server error", respBody.Error.Message)
+	assert.Equal(t, "INTERNAL_ERROR", respBody.Error.Code)
 }
@@ -12,7 +12,7 @@ import (
 	controller "cry-api/app/controllers/users"
 	UserModel "cry-api/app/models"
 	JwtServices "cry-api/app/services/jwt"
-	SignInError "cry-api/app/types/errors"
+	app_errors "cry-api/app/types/errors"
 	UserTypes "cry-api/app/types/users"
 	TestUtils "cry-api/app/utils/tests"
 	testmocks "cry-api/tests/mocks"
@@ -61,8 +61,7 @@ func TestSignIn_Success(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/signin", bytes.NewReader(bodyBytes))
 	w := httptest.NewRecorder()
 
-	c := TestUtils.GetGinContext(w, req)
-	userController.SignIn(c)
+	TestUtils.ServeWithErrorHandler(w, req, userController.SignIn)
 
 	res := w.Result()
 	defer func() {
@@ -101,8 +100,7 @@ func TestSignIn_InvalidJSON(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/signin", bytes.NewReader(invalidJSON))
 	w := httptest.NewRecorder()
 
-	c := TestUtils.GetGinContext(w, req)
-	userController.SignIn(c)
+	TestUtils.ServeWithErrorHandler(w, req, userController.SignIn)
 
 	res := w.Result()
 	defer func() {
@@ -111,10 +109,11 @@ func TestSignIn_InvalidJSON(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&respBody)
 	assert.NoError(t, err)
-	assert.Contains(t, respBody["error"], "Invalid JSON")
+	assert.Contains(t, respBody.Error.Message, "Invalid JSON")
+	assert.Equal(t, "INVALID_JSON", respBody.Error.Code)
 
 	mockAuthService.AssertNotCalled(t, "AuthenticateUser", mock.Anything, mock.Anything)
 }
@@ -140,13 +139,12 @@ func TestSignIn_UserNotFound(t *testing.T) {
 	// Return ErrUserNotFound
 	mockAuthService.
 		On("AuthenticateUser", input.Username, input.Password).
-		Return((*UserModel.User)(nil), SignInError.ErrUserNotFound)
+		Return((*UserModel.User)(nil), app_errors.ErrUserNotFound)
 
 	req := httptest.NewRequest(http.MethodPost, "/signin", bytes.NewReader(bodyBytes))
 	w := httptest.NewRecorder()
 
-	c := TestUtils.GetGinContext(w, req)
-	userController.SignIn(c)
+	TestUtils.ServeWithErrorHandler(w, req, userController.SignIn)
 
 	res := w.Result()
 	defer func() {
@@ -155,10 +153,11 @@ func TestSignIn_UserNotFound(t *testing.T) {
 
 	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&respBody)
 	assert.NoError(t, err)
-	assert.Contains(t, respBody["error"], "Invalid username or password")
+	assert.Contains(t, respBody.Error.Message, "Invalid username or password")
+	assert.Equal(t, "AUTH_INVALID_CREDENTIALS", respBody.Error.Code)
 
 	mockAuthService.AssertExpectations(t)
 }
@@ -184,13 +183,12 @@ func TestSignIn_InvalidPassword(t *testing.T) {
 	// Return ErrInvalidPassword
 	mockAuthService.
 		On("AuthenticateUser", input.Username, input.Password).
-		Return((*UserModel.User)(nil), SignInError.ErrInvalidPassword)
+		Return((*UserModel.User)(nil), app_errors.ErrInvalidPassword)
 
 	req := httptest.NewRequest(http.MethodPost, "/signin", bytes.NewReader(bodyBytes))
 	w := httptest.NewRecorder()
 
-	c := TestUtils.GetGinContext(w, req)
-	userController.SignIn(c)
+	TestUtils.ServeWithErrorHandler(w, req, userController.SignIn)
 
 	res := w.Result()
 	defer func() {
@@ -199,10 +197,11 @@ func TestSignIn_InvalidPassword(t *testing.T) {
 
 	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&respBody)
 	assert.NoError(t, err)
-	assert.Contains(t, respBody["error"], "Invalid username or password")
+	assert.Contains(t, respBody.Error.Message, "Invalid username or password")
+	assert.Equal(t, "AUTH_INVALID_CREDENTIALS", respBody.Error.Code)
 
 	mockAuthService.AssertExpectations(t)
 }
@@ -13,6 +13,7 @@ import (
 	"cry-api/app/middleware"
 	UserModel "cry-api/app/models"
 	SignUpError "cry-api/app/types/errors"
+	app_errors "cry-api/app/types/errors"
 	UserTypes "cry-api/app/types/users"
 	TestUtils "cry-api/app/utils/tests"
 	testmocks "cry-api/tests/mocks"
@@ -75,9 +76,7 @@ func TestSignup_Success(t *testing.T) {
 
 	req := httptest.NewRequest(http.MethodPost, "/signup", bytes.NewReader(bodyBytes))
 	w := httptest.NewRecorder()
-	c := TestUtils.GetGinContext(w, req)
-
-	userController.Signup(c)
+	TestUtils.ServeWithErrorHandler(w, req, userController.Signup)
 
 	// wait for the async email goroutine
 	select {
@@ -133,10 +132,11 @@ func TestSignup_InvalidJSON(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&respBody)
 	assert.NoError(t, err)
-	assert.Contains(t, respBody["error"], "Invalid JSON")
+	assert.Contains(t, respBody.Error.Message, "Invalid JSON")
+	assert.Equal(t, "INVALID_JSON", respBody.Error.Code)
 }
 
 func TestSignup_UserConflict(t *testing.T) {
@@ -179,10 +179,11 @@ func TestSignup_UserConflict(t *testing.T) {
 
 	assert.Equal(t, http.StatusConflict, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&respBody)
 	assert.NoError(t, err)
-	assert.Equal(t, "User already exists", respBody["error"])
+	assert.Equal(t, "User already exists", respBody.Error.Message)
+	assert.Equal(t, "USER_ALREADY_EXISTS", respBody.Error.Code)
 
 	mockEmailService.AssertNotCalled(t, "SendVerifyAccountEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
@@ -227,10 +228,11 @@ func TestSignup_UserCreationFails(t *testing.T) {
 
 	assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&respBody)
 	assert.NoError(t, err)
-	assert.Equal(t, "Could not create user", respBody["error"])
+	assert.Equal(t, "Could not create user", respBody.Error.Message)
+	assert.Equal(t, "INTERNAL_ERROR", respBody.Error.Code)
 
 	mockEmailService.AssertNotCalled(t, "SendVerifyAccountEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
@@ -262,8 +264,9 @@ func TestSignup_EmptyRequestBody(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&respBody)
 	assert.NoError(t, err)
-	assert.Contains(t, respBody["error"], "Invalid JSON")
+	assert.Contains(t, respBody.Error.Message, "Invalid JSON")
+	assert.Equal(t, "INVALID_JSON", respBody.Error.Code)
 }
@@ -156,10 +156,11 @@ func TestUpdateAccountName_InvalidJSON(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&respBody)
 	assert.NoError(t, err)
-	assert.Contains(t, respBody["error"], "Invalid input")
+	assert.Contains(t, respBody.Error.Message, "Invalid JSON format")
+	assert.Equal(t, "INVALID_JSON", respBody.Error.Code)
 
 	mockUserService.AssertNotCalled(t, "GetUserByUUID", mock.Anything)
 	mockUserService.AssertNotCalled(t, "UpdateUser", mock.Anything)
@@ -194,10 +195,11 @@ func TestUpdateAccountName_MissingUserClaims(t *testing.T) {
 
 	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&respBody)
 	assert.NoError(t, err)
-	assert.Contains(t, respBody["error"], "User not authenticated")
+	assert.Contains(t, respBody.Error.Message, "User not authenticated")
+	assert.Equal(t, "UNAUTHORIZED", respBody.Error.Code)
 
 	mockUserService.AssertNotCalled(t, "GetUserByUUID", mock.Anything)
 }
@@ -231,12 +233,13 @@ func TestUpdateAccountName_InvalidUserClaims(t *testing.T) {
 
 	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&respBody)
 	assert.NoError(t, err)
 	// When claims are set but have the wrong type, the type assertion fails
 	// and the controller returns "Invalid user claims"
-	assert.Contains(t, respBody["error"], "Invalid user claims")
+	assert.Contains(t, respBody.Error.Message, "Invalid user claims")
+	assert.Equal(t, "UNAUTHORIZED", respBody.Error.Code)
 
 	mockUserService.AssertNotCalled(t, "GetUserByUUID", mock.Anything)
 }
@@ -280,10 +283,11 @@ func TestUpdateAccountName_UserNotFound(t *testing.T) {
 
 	assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&respBody)
 	assert.NoError(t, err)
-	assert.Contains(t, respBody["error"], "User not found")
+	assert.Contains(t, respBody.Error.Message, "User not found")
+	assert.Equal(t, "INTERNAL_ERROR", respBody.Error.Code)
 
 	mockUserService.AssertExpectations(t)
 	mockUserService.AssertNotCalled(t, "UpdateUser", mock.Anything)
@@ -347,10 +351,11 @@ func TestUpdateAccountName_UsernameAlreadyExists(t *testing.T) {
 
 	assert.Equal(t, http.StatusConflict, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&respBody)
 	assert.NoError(t, err)
-	assert.Contains(t, respBody["error"], "Username is already in use")
+	assert.Contains(t, respBody.Error.Message, "Username is already in use")
+	assert.Equal(t, "USERNAME_CONFLICT", respBody.Error.Code)
 
 	mockUserService.AssertExpectations(t)
 	mockUserService.AssertNotCalled(t, "UpdateUser", mock.Anything)
@@ -474,10 +479,11 @@ func TestUpdateAccountName_CheckUsernameAvailabilityError(t *testing.T) {
 
 	assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&respBody)
 	assert.NoError(t, err)
-	assert.Contains(t, respBody["error"], "Failed to check username availability")
+	assert.Contains(t, respBody.Error.Message, "Failed to check username availability")
+	assert.Equal(t, "INTERNAL_ERROR", respBody.Error.Code)
 
 	mockUserService.AssertExpectations(t)
 	mockUserService.AssertNotCalled(t, "UpdateUser", mock.Anything)
@@ -539,10 +545,11 @@ func TestUpdateAccountName_UpdateUserError(t *testing.T) {
 
 	assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&respBody)
 	assert.NoError(t, err)
-	assert.Contains(t, respBody["error"], "Failed to update username")
+	assert.Contains(t, respBody.Error.Message, "Failed to update username")
+	assert.Equal(t, "INTERNAL_ERROR", respBody.Error.Code)
 
 	mockUserService.AssertExpectations(t)
 }
@@ -576,10 +583,11 @@ func TestUpdateAccountName_EmptyUsername(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&respBody)
 	assert.NoError(t, err)
-	assert.Contains(t, respBody["error"], "Invalid input")
+	assert.Contains(t, respBody.Error.Message, "Invalid input")
+	assert.Equal(t, "VALIDATION_FAILED", respBody.Error.Code)
 
 	mockUserService.AssertNotCalled(t, "GetUserByUUID", mock.Anything)
 }
@@ -618,10 +626,11 @@ func TestUpdateAccountName_UsernameTooShort(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&respBody)
 	assert.NoError(t, err)
-	assert.Contains(t, respBody["error"], "Username must be at least 3 characters long")
+	assert.Contains(t, respBody.Error.Message, "Username must be at least 3 characters long")
+	assert.Equal(t, "VALIDATION_FAILED", respBody.Error.Code)
 
 	mockUserService.AssertNotCalled(t, "GetUserByUUID", mock.Anything)
 }
@@ -662,10 +671,11 @@ func TestUpdateAccountName_UsernameTooLong(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 
-	var respBody map[string]string
+	var respBody app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&respBody)
 	assert.NoError(t, err)
-	assert.Contains(t, respBody["error"], "Username must not exceed 50 characters")
+	assert.Contains(t, respBody.Error.Message, "Username must not exceed 50 characters")
+	assert.Equal(t, "VALIDATION_FAILED", respBody.Error.Code)
 
 	mockUserService.AssertNotCalled(t, "GetUserByUUID", mock.Anything)
 }
@@ -718,10 +728,11 @@ func TestUpdateAccountName_UsernameInvalidCharacters(t *testing.T) {
 
 			assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 
-			var respBody map[string]string
+			var respBody app_errors.ErrorResponse
 			err := json.NewDecoder(res.Body).Decode(&respBody)
 			assert.NoError(t, err)
-			assert.Contains(t, respBody["error"], "Username can only contain letters, numbers, and underscores")
+			assert.Contains(t, respBody.Error.Message, "Username can only contain letters, numbers, and underscores")
+			assert.Equal(t, "VALIDATION_FAILED", respBody.Error.Code)
 
 			mockUserService.AssertNotCalled(t, "GetUserByUUID", mock.Anything)
 		})
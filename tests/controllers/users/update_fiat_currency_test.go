@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	controller "cry-api/app/controllers/users"
+	"cry-api/app/middleware"
+	UserModel "cry-api/app/models"
+	services "cry-api/app/services/jwt"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupFiatCurrencyRouter(userController *controller.UserController) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("user", &services.Claims{UUID: "test-uuid-1234"})
+		c.Next()
+	})
+	router.PUT("/fiat-currency", userController.UpdateFiatCurrency)
+	return router
+}
+
+func putFiatCurrency(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/fiat-currency", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestUpdateFiatCurrency_Success(t *testing.T) {
+	mockUserService := new(testmocks.MockUserService)
+	router := setupFiatCurrencyRouter(&controller.UserController{UserService: mockUserService})
+
+	user := &UserModel.User{ID: 1, UUID: "test-uuid-1234", FiatCurrency: "USD"}
+	mockUserService.On("GetUserByUUID", "test-uuid-1234").Return(user, nil).Once()
+	mockUserService.On("UpdateUser", mock.MatchedBy(func(u *UserModel.User) bool {
+		return u.FiatCurrency == "EUR"
+	})).Return(nil).Once()
+
+	w := putFiatCurrency(router, `{"fiat_currency":" eur "}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"success":true,"message":"Fiat currency updated successfully","fiat_currency":"EUR"}`, w.Body.String())
+	mockUserService.AssertExpectations(t)
+}
+
+func TestUpdateFiatCurrency_Validation(t *testing.T) {
+	mockUserService := new(testmocks.MockUserService)
+	router := setupFiatCurrencyRouter(&controller.UserController{UserService: mockUserService})
+
+	w := putFiatCurrency(router, `{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = putFiatCurrency(router, `{"fiat_currency":"BTC"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"fiat_currency"`)
+	assert.Contains(t, w.Body.String(), "Currency must be one of the supported ISO-4217 codes")
+
+	mockUserService.AssertNotCalled(t, "UpdateUser", mock.Anything)
+}
+
+func TestUpdateFiatCurrency_UpdateFailure(t *testing.T) {
+	mockUserService := new(testmocks.MockUserService)
+	router := setupFiatCurrencyRouter(&controller.UserController{UserService: mockUserService})
+
+	mockUserService.On("GetUserByUUID", "test-uuid-1234").Return(&UserModel.User{ID: 1, UUID: "test-uuid-1234"}, nil).Once()
+	mockUserService.On("UpdateUser", mock.Anything).Return(assert.AnError).Once()
+
+	w := putFiatCurrency(router, `{"fiat_currency":"JPY"}`)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
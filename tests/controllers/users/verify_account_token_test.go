@@ -10,6 +10,7 @@ import (
 
 	controller "cry-api/app/controllers/users"
 	UserModel "cry-api/app/models"
+	app_errors "cry-api/app/types/errors"
 	TestUtils "cry-api/app/utils/tests"
 	testmocks "cry-api/tests/mocks"
 
@@ -38,8 +39,7 @@ func TestVerifyAccountToken_Success(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/verify-account-token", bytes.NewReader(bodyBytes))
 	w := httptest.NewRecorder()
 
-	c := TestUtils.GetGinContext(w, req)
-	userController.VerifyAccountToken(c)
+	TestUtils.ServeWithErrorHandler(w, req, userController.VerifyAccountToken)
 
 	res := w.Result()
 	defer func() {
@@ -65,8 +65,7 @@ func TestVerifyAccountToken_InvalidJSON(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/verify-account-token", bytes.NewReader([]byte(`{invalid-json}`)))
 	w := httptest.NewRecorder()
 
-	c := TestUtils.GetGinContext(w, req)
-	userController.VerifyAccountToken(c)
+	TestUtils.ServeWithErrorHandler(w, req, userController.VerifyAccountToken)
 
 	res := w.Result()
 	defer func() {
@@ -75,9 +74,10 @@ func TestVerifyAccountToken_InvalidJSON(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 
-	var resp map[string]string
+	var resp app_errors.ErrorResponse
 	_ = json.NewDecoder(res.Body).Decode(&resp)
-	assert.Contains(t, resp["error"], "Invalid request body")
+	assert.Contains(t, resp.Error.Message, "Invalid JSON format")
+	assert.Equal(t, "INVALID_JSON", resp.Error.Code)
 }
 
 func TestVerifyAccountToken_TokenNotFound(t *testing.T) {
@@ -96,8 +96,7 @@ func TestVerifyAccountToken_TokenNotFound(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/verify-account-token", bytes.NewReader(bodyBytes))
 	w := httptest.NewRecorder()
 
-	c := TestUtils.GetGinContext(w, req)
-	userController.VerifyAccountToken(c)
+	TestUtils.ServeWithErrorHandler(w, req, userController.VerifyAccountToken)
 
 	res := w.Result()
 	defer func() {
@@ -106,9 +105,10 @@ func TestVerifyAccountToken_TokenNotFound(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 
-	var resp map[string]string
+	var resp app_errors.ErrorResponse
 	_ = json.NewDecoder(res.Body).Decode(&resp)
-	assert.Contains(t, resp["error"], "Token is invalid or expired")
+	assert.Contains(t, resp.Error.Message, "Token is invalid or expired")
+	assert.Equal(t, "VERIFICATION_TOKEN_INVALID", resp.Error.Code)
 
 	mockUserTokenService.AssertExpectations(t)
 }
@@ -135,8 +135,7 @@ func TestVerifyAccountToken_ExpiredToken(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/verify-account-token", bytes.NewReader(bodyBytes))
 	w := httptest.NewRecorder()
 
-	c := TestUtils.GetGinContext(w, req)
-	userController.VerifyAccountToken(c)
+	TestUtils.ServeWithErrorHandler(w, req, userController.VerifyAccountToken)
 
 	res := w.Result()
 	defer func() {
@@ -145,9 +144,10 @@ func TestVerifyAccountToken_ExpiredToken(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 
-	var resp map[string]string
+	var resp app_errors.ErrorResponse
 	_ = json.NewDecoder(res.Body).Decode(&resp)
-	assert.Contains(t, resp["error"], "Token has expired")
+	assert.Contains(t, resp.Error.Message, "Token has expired")
+	assert.Equal(t, "VERIFICATION_TOKEN_EXPIRED", resp.Error.Code)
 
 	mockUserTokenService.AssertExpectations(t)
 }
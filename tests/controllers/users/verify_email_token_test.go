@@ -10,6 +10,7 @@ import (
 
 	controller "cry-api/app/controllers/users"
 	UserModel "cry-api/app/models"
+	app_errors "cry-api/app/types/errors"
 	UserTypes "cry-api/app/types/users"
 	TestUtils "cry-api/app/utils/tests"
 	testmocks "cry-api/tests/mocks"
@@ -65,8 +66,7 @@ func TestVerifyEmailToken_Success(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/verify-email-token", bytes.NewReader(bodyBytes))
 	w := httptest.NewRecorder()
 
-	c := TestUtils.GetGinContext(w, req)
-	userController.VerifyEmailToken(c)
+	TestUtils.ServeWithErrorHandler(w, req, userController.VerifyEmailToken)
 
 	res := w.Result()
 	defer func() {
@@ -95,8 +95,7 @@ func TestVerifyEmailToken_InvalidJSON(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/verify-email-token", bytes.NewReader([]byte(`{invalid-json}`)))
 	w := httptest.NewRecorder()
 
-	c := TestUtils.GetGinContext(w, req)
-	userController.VerifyEmailToken(c)
+	TestUtils.ServeWithErrorHandler(w, req, userController.VerifyEmailToken)
 
 	res := w.Result()
 	defer func() {
@@ -105,10 +104,11 @@ func TestVerifyEmailToken_InvalidJSON(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 
-	var resp map[string]string
+	var resp app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&resp)
 	assert.NoError(t, err)
-	assert.Contains(t, resp["error"], "Invalid request body")
+	assert.Contains(t, resp.Error.Message, "Invalid JSON format")
+	assert.Equal(t, "INVALID_JSON", resp.Error.Code)
 }
 
 func TestVerifyEmailToken_VerificationFails(t *testing.T) {
@@ -133,8 +133,7 @@ func TestVerifyEmailToken_VerificationFails(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/verify-email-token", bytes.NewReader(bodyBytes))
 	w := httptest.NewRecorder()
 
-	c := TestUtils.GetGinContext(w, req)
-	userController.VerifyEmailToken(c)
+	TestUtils.ServeWithErrorHandler(w, req, userController.VerifyEmailToken)
 
 	res := w.Result()
 	defer func() {
@@ -143,10 +142,11 @@ func TestVerifyEmailToken_VerificationFails(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 
-	var resp map[string]string
+	var resp app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&resp)
 	assert.NoError(t, err)
-	assert.Contains(t, resp["error"], "invalid or expired account verification link")
+	assert.Contains(t, resp.Error.Message, "Invalid or expired account verification link")
+	assert.Equal(t, "VERIFICATION_TOKEN_INVALID", resp.Error.Code)
 
 	mockUserService.AssertExpectations(t)
 	mockUserTokenService.AssertExpectations(t)
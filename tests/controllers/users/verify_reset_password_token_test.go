@@ -11,6 +11,7 @@ import (
 
 	controller "cry-api/app/controllers/users"
 	UserModel "cry-api/app/models"
+	app_errors "cry-api/app/types/errors"
 	UserTypes "cry-api/app/types/users"
 	TestUtils "cry-api/app/utils/tests"
 	testmocks "cry-api/tests/mocks"
@@ -58,8 +59,7 @@ func TestVerifyResetPasswordToken_Success(t *testing.T) {
 
 	req := httptest.NewRequest(http.MethodPost, "/verify-reset-password-token", bytes.NewReader(bodyBytes))
 	w := httptest.NewRecorder()
-	c := TestUtils.GetGinContext(w, req)
-	userController.VerifyResetPasswordToken(c)
+	TestUtils.ServeWithErrorHandler(w, req, userController.VerifyResetPasswordToken)
 
 	res := w.Result()
 	defer func() {
@@ -90,8 +90,7 @@ func TestVerifyResetPasswordToken_InvalidJSON(t *testing.T) {
 
 	req := httptest.NewRequest(http.MethodPost, "/verify-reset-password-token", bytes.NewReader([]byte(`{invalid-json}`)))
 	w := httptest.NewRecorder()
-	c := TestUtils.GetGinContext(w, req)
-	userController.VerifyResetPasswordToken(c)
+	TestUtils.ServeWithErrorHandler(w, req, userController.VerifyResetPasswordToken)
 
 	res := w.Result()
 	defer func() {
@@ -99,10 +98,11 @@ func TestVerifyResetPasswordToken_InvalidJSON(t *testing.T) {
 	}()
 
 	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
-	var resp map[string]string
+	var resp app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&resp)
 	assert.NoError(t, err)
-	assert.Equal(t, "Invalid JSON format", resp["message"])
+	assert.Equal(t, "Invalid JSON format", resp.Error.Message)
+	assert.Equal(t, "INVALID_JSON", resp.Error.Code)
 }
 
 func TestVerifyResetPasswordToken_TokenNotFound(t *testing.T) {
@@ -126,8 +126,7 @@ func TestVerifyResetPasswordToken_TokenNotFound(t *testing.T) {
 
 	req := httptest.NewRequest(http.MethodPost, "/verify-reset-password-token", bytes.NewReader(bodyBytes))
 	w := httptest.NewRecorder()
-	c := TestUtils.GetGinContext(w, req)
-	userController.VerifyResetPasswordToken(c)
+	TestUtils.ServeWithErrorHandler(w, req, userController.VerifyResetPasswordToken)
 
 	res := w.Result()
 	defer func() {
@@ -135,10 +134,11 @@ func TestVerifyResetPasswordToken_TokenNotFound(t *testing.T) {
 	}()
 
 	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
-	var resp map[string]string
+	var resp app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&resp)
 	assert.NoError(t, err)
-	assert.Equal(t, "Invalid or expired reset password token", resp["message"])
+	assert.Equal(t, "Invalid or expired reset password token", resp.Error.Message)
+	assert.Equal(t, "VERIFICATION_TOKEN_INVALID", resp.Error.Code)
 }
 
 func TestVerifyResetPasswordToken_UserNotVerified(t *testing.T) {
@@ -173,19 +173,19 @@ func TestVerifyResetPasswordToken_UserNotVerified(t *testing.T) {
 
 	req := httptest.NewRequest(http.MethodPost, "/verify-reset-password-token", bytes.NewReader(bodyBytes))
 	w := httptest.NewRecorder()
-	c := TestUtils.GetGinContext(w, req)
-	userController.VerifyResetPasswordToken(c)
+	TestUtils.ServeWithErrorHandler(w, req, userController.VerifyResetPasswordToken)
 
 	res := w.Result()
 	defer func() {
 		_ = res.Body.Close()
 	}()
 
-	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
-	var resp map[string]string
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	var resp app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&resp)
 	assert.NoError(t, err)
-	assert.Equal(t, "User is not verified", resp["message"])
+	assert.Equal(t, "User is not verified", resp.Error.Message)
+	assert.Equal(t, "AUTH_USER_NOT_VERIFIED", resp.Error.Code)
 }
 
 func TestVerifyResetPasswordToken_HashPasswordFailure(t *testing.T) {
@@ -222,8 +222,7 @@ func TestVerifyResetPasswordToken_HashPasswordFailure(t *testing.T) {
 
 	req := httptest.NewRequest(http.MethodPost, "/verify-reset-password-token", bytes.NewReader(bodyBytes))
 	w := httptest.NewRecorder()
-	c := TestUtils.GetGinContext(w, req)
-	userController.VerifyResetPasswordToken(c)
+	TestUtils.ServeWithErrorHandler(w, req, userController.VerifyResetPasswordToken)
 
 	res := w.Result()
 	defer func() {
@@ -231,8 +230,9 @@ func TestVerifyResetPasswordToken_HashPasswordFailure(t *testing.T) {
 	}()
 
 	assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
-	var resp map[string]string
+	var resp app_errors.ErrorResponse
 	err := json.NewDecoder(res.Body).Decode(&resp)
 	assert.NoError(t, err)
-	assert.Equal(t, "Failed to hash password", resp["message"])
+	assert.Equal(t, "Failed to hash password", resp.Error.Message)
+	assert.Equal(t, "INTERNAL_ERROR", resp.Error.Code)
 }
@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	controllers "cry-api/app/controllers/wallet_explorer"
+	"cry-api/app/middleware"
+	UserModel "cry-api/app/models"
+	services "cry-api/app/services/jwt"
+	app_errors "cry-api/app/types/errors"
+	WalletExplorerTypes "cry-api/app/types/wallet_explorer"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletExplorerController_BroadcastTransaction(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	user := &UserModel.User{ID: 7, UUID: "user-uuid"}
+	mockUserService := new(testmocks.MockUserService)
+	mockBroadcastService := new(testmocks.MockBroadcastService)
+	controller := &controllers.WalletExplorerController{
+		UserService:      mockUserService,
+		BroadcastService: mockBroadcastService,
+	}
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "" {
+			c.Set("user", &services.Claims{UUID: user.UUID})
+		}
+		c.Next()
+	})
+	router.POST("/broadcast", controller.BroadcastTransaction)
+
+	serve := func(body string, authenticated bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/broadcast", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = "203.0.113.9:4242"
+		if authenticated {
+			req.Header.Set("Authorization", "Bearer token")
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Missing tx", func(t *testing.T) {
+		w := serve(`{}`, true)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":{"code":"VALIDATION_FAILED","message":"Invalid input","fields":[{"field":"tx","code":"REQUIRED","message":"This field is required"}]}}`, w.Body.String())
+	})
+
+	t.Run("Unauthenticated", func(t *testing.T) {
+		w := serve(`{"tx":"0200"}`, false)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Rejected transaction", func(t *testing.T) {
+		mockUserService.On("GetUserByUUID", user.UUID).Return(user, nil).Once()
+		mockBroadcastService.On("Broadcast", user.ID, "203.0.113.9", "0200").
+			Return(nil, app_errors.NewValidationError("tx", "", "Input 0 is not signed")).Once()
+
+		w := serve(`{"tx":"0200"}`, true)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":{"code":"VALIDATION_FAILED","message":"Input 0 is not signed","fields":[{"field":"tx","code":"INVALID","message":"Input 0 is not signed"}]}}`, w.Body.String())
+	})
+
+	t.Run("Successful broadcast", func(t *testing.T) {
+		mockUserService.On("GetUserByUUID", user.UUID).Return(user, nil).Once()
+		mockBroadcastService.On("Broadcast", user.ID, "203.0.113.9", "0100").
+			Return(&WalletExplorerTypes.IBroadcastResult{TxID: "abc", Provider: "mempool", Fee: 1000, VSize: 200, FeeRate: 5}, nil).Once()
+
+		w := serve(`{"tx":"0100"}`, true)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"broadcast":{"txid":"abc","provider":"mempool","fee":1000,"vsize":200,"fee_rate":5}}`, w.Body.String())
+	})
+
+	mockBroadcastService.AssertExpectations(t)
+	mockUserService.AssertExpectations(t)
+}
@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	controllers "cry-api/app/controllers/wallet_explorer"
+	"cry-api/app/middleware"
+	WalletExplorerTypes "cry-api/app/types/wallet_explorer"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletExplorerController_DecodeTransaction(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockDecoderService := new(testmocks.MockDecoderService)
+
+	controller := &controllers.WalletExplorerController{
+		DecoderService: mockDecoderService,
+	}
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.POST("/wallet/decode", controller.DecodeTransaction)
+
+	makeRequest := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/wallet/decode", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Missing payload", func(t *testing.T) {
+		w := makeRequest(`{}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":{"code":"VALIDATION_FAILED","message":"Invalid input","fields":[{"field":"payload","code":"REQUIRED","message":"This field is required"}]}}`, w.Body.String())
+	})
+
+	t.Run("Invalid network", func(t *testing.T) {
+		w := makeRequest(`{"payload":"00","network":"regtest"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":{"code":"VALIDATION_FAILED","message":"Network must be mainnet or testnet","fields":[{"field":"network","code":"INVALID","message":"Network must be mainnet or testnet","value":"regtest"}]}}`, w.Body.String())
+	})
+
+	t.Run("Undecodable payload", func(t *testing.T) {
+		mockDecoderService.On("DecodeTransaction", "zz", "").
+			Return(nil, errors.New("payload is neither a raw transaction hex nor a PSBT")).Once()
+
+		w := makeRequest(`{"payload":"zz"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":{"code":"VALIDATION_FAILED","message":"payload is neither a raw transaction hex nor a PSBT","fields":[{"field":"payload","code":"INVALID","message":"payload is neither a raw transaction hex nor a PSBT"}]}}`, w.Body.String())
+		mockDecoderService.AssertExpectations(t)
+	})
+
+	t.Run("Successful call", func(t *testing.T) {
+		mockData := &WalletExplorerTypes.IDecodedTransaction{
+			Format:  "raw",
+			Network: "mainnet",
+			TxID:    "abc",
+			WTxID:   "abc",
+			VSize:   82,
+			Inputs:  []WalletExplorerTypes.IDecodedInput{},
+			Outputs: []WalletExplorerTypes.IDecodedOutput{},
+		}
+		mockDecoderService.On("DecodeTransaction", "0200", "mainnet").Return(mockData, nil).Once()
+
+		w := makeRequest(`{"payload":"0200","network":"mainnet"}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"decoded_transaction":{"format":"raw"`)
+		mockDecoderService.AssertExpectations(t)
+	})
+}
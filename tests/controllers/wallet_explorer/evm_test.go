@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	controllers "cry-api/app/controllers/wallet_explorer"
+	"cry-api/app/middleware"
+	app_errors "cry-api/app/types/errors"
+	EVMTypes "cry-api/app/types/evm"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletExplorerController_EVM(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockEVMService := new(testmocks.MockEVMService)
+	controller := &controllers.WalletExplorerController{EVMService: mockEVMService}
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.GET("/evm/:chain/tx/:hash", controller.GetEVMTransaction)
+	router.GET("/evm/:chain/address/:address", controller.GetEVMBalances)
+	router.GET("/evm/:chain/address/:address/transfers", controller.GetEVMTransfers)
+
+	serve := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w
+	}
+
+	t.Run("Transaction", func(t *testing.T) {
+		mockEVMService.On("GetTransaction", "ethereum", "0xabc").
+			Return(&EVMTypes.IEVMTransaction{Chain: "ethereum", Hash: "0xabc", Status: EVMTypes.TxStatusSuccess}, nil).Once()
+
+		w := serve("/evm/ethereum/tx/0xabc")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"transaction":{"chain":"ethereum","hash":"0xabc","status":"success"`)
+	})
+
+	t.Run("Unknown chain", func(t *testing.T) {
+		mockEVMService.On("GetTransaction", "solana", "0xabc").
+			Return(nil, app_errors.NewNotFoundError("chain", `Chain "solana" is not configured`)).Once()
+
+		w := serve("/evm/solana/tx/0xabc")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Balances with requested tokens", func(t *testing.T) {
+		mockEVMService.On("GetBalances", "base", "0x01", []string{"0x02", "0x03"}).
+			Return(&EVMTypes.IEVMBalances{Chain: "base", Address: "0x01", Tokens: []EVMTypes.IAssetBalance{}}, nil).Once()
+
+		w := serve("/evm/base/address/0x01?tokens=0x02,%200x03,")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"balances":{"chain":"base","address":"0x01"`)
+	})
+
+	t.Run("Transfers", func(t *testing.T) {
+		mockEVMService.On("GetTokenTransfers", "ethereum", "0x01").
+			Return(&EVMTypes.IEVMTransfers{Chain: "ethereum", Address: "0x01", Transfers: []EVMTypes.ITokenTransfer{}}, nil).Once()
+
+		w := serve("/evm/ethereum/address/0x01/transfers")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"transfers":{"chain":"ethereum","address":"0x01","from_block":0,"to_block":0,"transfers":[]}}`, w.Body.String())
+	})
+
+	mockEVMService.AssertExpectations(t)
+}
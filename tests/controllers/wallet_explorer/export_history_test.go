@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	controllers "cry-api/app/controllers/wallet_explorer"
+	"cry-api/app/middleware"
+	app_errors "cry-api/app/types/errors"
+	ExportTypes "cry-api/app/types/export"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupExportRouter(ctrl *controllers.WalletExplorerController) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.GET("/export/address", ctrl.ExportAddressHistory)
+	router.GET("/export/xpub", ctrl.ExportXPUBHistory)
+	return router
+}
+
+func TestWalletExplorerController_ExportAddressHistory(t *testing.T) {
+	mockExportService := new(testmocks.MockExportService)
+	router := setupExportRouter(&controllers.WalletExplorerController{ExportService: mockExportService})
+
+	serve := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/export/address?"+query, nil))
+		return w
+	}
+
+	t.Run("Missing address parameter", func(t *testing.T) {
+		w := serve("")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":{"code":"VALIDATION_FAILED","message":"Missing address parameter","fields":[{"field":"address","code":"INVALID","message":"Missing address parameter"}]}}`, w.Body.String())
+	})
+
+	t.Run("Invalid format", func(t *testing.T) {
+		w := serve("address=bc1q&format=xlsx")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Format must be one of csv, jsonl or ofx")
+	})
+
+	t.Run("Streams CSV download", func(t *testing.T) {
+		mockExportService.On("ExportAddress", "bc1q", mock.MatchedBy(func(opts *ExportTypes.IExportOptions) bool {
+			return opts.Format == "csv" && opts.Currency == "EUR"
+		})).Return("date,txid\n", nil).Once()
+
+		w := serve("address=bc1q&currency=eur")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="address-history.csv"`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, "date,txid\n", w.Body.String())
+	})
+
+	t.Run("Error before streaming returns JSON", func(t *testing.T) {
+		mockExportService.On("ExportAddress", "bc1qdown", mock.Anything).
+			Return("", app_errors.NewAppError(http.StatusBadGateway, "Failed to fetch address history", "timeout")).Once()
+
+		w := serve("address=bc1qdown")
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+		assert.Empty(t, w.Header().Get("Content-Disposition"))
+		assert.JSONEq(t, `{"error":{"code":"UPSTREAM_ERROR","message":"Failed to fetch address history","details":"timeout"}}`, w.Body.String())
+	})
+
+	t.Run("Error after streaming truncates download", func(t *testing.T) {
+		mockExportService.On("ExportAddress", "bc1qhalf", mock.Anything).Return("date,txid\n", errors.New("connection reset")).Once()
+
+		w := serve("address=bc1qhalf")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "date,txid\n", w.Body.String())
+	})
+
+	mockExportService.AssertExpectations(t)
+}
+
+func TestWalletExplorerController_ExportXPUBHistory(t *testing.T) {
+	mockExportService := new(testmocks.MockExportService)
+	router := setupExportRouter(&controllers.WalletExplorerController{ExportService: mockExportService})
+
+	t.Run("Missing xpub parameter", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/export/xpub", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":{"code":"VALIDATION_FAILED","message":"Missing xpub parameter","fields":[{"field":"xpub","code":"INVALID","message":"Missing xpub parameter"}]}}`, w.Body.String())
+	})
+
+	t.Run("Streams OFX download", func(t *testing.T) {
+		mockExportService.On("ExportXPUB", "zpub123", mock.Anything).Return("<OFX></OFX>\n", nil).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/export/xpub?xpub=zpub123&format=ofx", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/x-ofx", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="xpub-history.ofx"`, w.Header().Get("Content-Disposition"))
+	})
+
+	mockExportService.AssertExpectations(t)
+}
@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	controllers "cry-api/app/controllers/wallet_explorer"
+	"cry-api/app/middleware"
+	UserModel "cry-api/app/models"
+	FXService "cry-api/app/services/fx"
+	services "cry-api/app/services/jwt"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+	EVMTypes "cry-api/app/types/evm"
+	WalletExplorerTypes "cry-api/app/types/wallet_explorer"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletExplorerController_FiatValues(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockTransactionService := new(testmocks.MockTransactionService)
+	mockEVMService := new(testmocks.MockEVMService)
+	mockUserService := new(testmocks.MockUserService)
+	market := new(testmocks.MockCoinMarketCapService)
+
+	controller := &controllers.WalletExplorerController{
+		TransactionService: mockTransactionService,
+		EVMService:         mockEVMService,
+		UserService:        mockUserService,
+		FXService:          FXService.NewFXService(market, nil),
+	}
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "" {
+			c.Set("user", &services.Claims{UUID: "user-uuid"})
+		}
+		c.Next()
+	})
+	router.GET("/xpub", controller.GetTransactionByXPUB)
+	router.GET("/evm/:chain/address/:address", controller.GetEVMBalances)
+
+	xpub := func() *WalletExplorerTypes.ITransactionXPUB {
+		return &WalletExplorerTypes.ITransactionXPUB{
+			Found: true,
+			Transactions: []WalletExplorerTypes.XPUBTransaction{
+				{TxID: "a", BalanceDiff: 0.1},
+				{TxID: "b", BalanceDiff: 0.2},
+			},
+		}
+	}
+
+	t.Run("XPUB balance in the user's currency", func(t *testing.T) {
+		mockUserService.On("GetUserByUUID", "user-uuid").Return(&UserModel.User{ID: 7, UUID: "user-uuid", FiatCurrency: "EUR"}, nil).Once()
+		mockTransactionService.On("GetTransactionByXPUB", "xpub1").Return(xpub(), nil).Once()
+		market.On("GetLatestQuotes", []string{"BTC"}, []int(nil), "EUR").
+			Return([]CoinMarketCap.IQuote{{Symbol: "BTC", Price: "40000.05"}}, nil).Once()
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/xpub?xpub=xpub1", nil)
+		req.Header.Set("Authorization", "Bearer token")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		// 0.3 BTC * 40000.05 = 12000.015, rounded half away from zero
+		assert.Contains(t, w.Body.String(), `"fiat":{"currency":"EUR","amount":"12000.02","minor_units":1200002}`)
+	})
+
+	t.Run("Currency query parameter for anonymous requests", func(t *testing.T) {
+		mockTransactionService.On("GetTransactionByXPUB", "xpub1").Return(xpub(), nil).Once()
+		market.On("GetLatestQuotes", []string{"BTC"}, []int(nil), "JPY").
+			Return([]CoinMarketCap.IQuote{{Symbol: "BTC", Price: "9000000"}}, nil).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/xpub?xpub=xpub1&currency=jpy", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"fiat":{"currency":"JPY","amount":"2700000","minor_units":2700000}`)
+	})
+
+	t.Run("Unsupported currency", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/xpub?xpub=xpub1&currency=ABC", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("EVM balances of the native coin and known tokens are valued", func(t *testing.T) {
+		mockEVMService.On("GetBalances", "ethereum", "0xabc", []string(nil)).Return(&EVMTypes.IEVMBalances{
+			Chain:  "ethereum",
+			Native: EVMTypes.IAssetBalance{Symbol: "ETH", Balance: "1500000000000000000", Amount: "1.5"},
+			Tokens: []EVMTypes.IAssetBalance{
+				{Token: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", Symbol: "USDC", Balance: "2500000", Amount: "2.5"},
+				{Token: "0x1111111111111111111111111111111111111111", Symbol: "USDC", Balance: "9000000000", Amount: "9000"},
+				{Token: "0xdAC17F958D2ee523a2206206994597C13D831ec7", Symbol: "USDT", Balance: "0", Amount: "0"},
+			},
+		}, nil).Once()
+		market.On("GetLatestQuotes", []string{"ETH"}, []int(nil), "USD").
+			Return([]CoinMarketCap.IQuote{{Symbol: "ETH", Price: "2000.01"}}, nil).Once()
+		market.On("GetLatestQuotes", []string{"USDC"}, []int(nil), "USD").
+			Return([]CoinMarketCap.IQuote{{Symbol: "USDC", Price: "0.9999"}}, nil).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/evm/ethereum/address/0xabc", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"amount":"1.5","fiat":{"currency":"USD","amount":"3000.02","minor_units":300002}`)
+		assert.Contains(t, w.Body.String(), `"amount":"2.5","fiat":{"currency":"USD","amount":"2.50","minor_units":250}`)
+		assert.Contains(t, w.Body.String(), `"amount":"9000"}`, "a contract calling itself USDC is not valued")
+		assert.Contains(t, w.Body.String(), `"amount":"0","fiat":{"currency":"USD","amount":"0.00","minor_units":0}`)
+	})
+
+	market.AssertExpectations(t)
+	mockUserService.AssertExpectations(t)
+}
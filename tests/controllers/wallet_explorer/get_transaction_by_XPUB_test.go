@@ -1,12 +1,15 @@
 package tests
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	controllers "cry-api/app/controllers/wallet_explorer"
+	"cry-api/app/middleware"
 	WalletExplorerTypes "cry-api/app/types/wallet_explorer"
 	testmocks "cry-api/tests/mocks"
 
@@ -23,35 +26,46 @@ func TestWalletExplorerController_GetTransactionByXPUB(t *testing.T) {
 		TransactionService: mockTransactionService,
 	}
 
-	makeRequest := func(query string) (*gin.Context, *httptest.ResponseRecorder) {
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.GET("/wallet/xpub", controller.GetTransactionByXPUB)
+
+	makeRequest := func(query string) *httptest.ResponseRecorder {
 		req := httptest.NewRequest(http.MethodGet, "/wallet/xpub?"+query, nil)
 		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = req
-		return c, w
+		router.ServeHTTP(w, req)
+		return w
 	}
 
 	t.Run("Missing xpub parameter", func(t *testing.T) {
-		c, w := makeRequest("")
-		controller.GetTransactionByXPUB(c)
+		w := makeRequest("")
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
-		assert.JSONEq(t, `{"error":"Missing xpub parameter"}`, w.Body.String())
+		assert.JSONEq(t, `{"error":{"code":"VALIDATION_FAILED","message":"Missing xpub parameter","fields":[{"field":"xpub","code":"INVALID","message":"Missing xpub parameter"}]}}`, w.Body.String())
 	})
 
 	t.Run("TransactionService returns error", func(t *testing.T) {
 		xpub := "testxpub"
 		mockTransactionService.On("GetTransactionByXPUB", xpub).Return(nil, errors.New("service failure")).Once()
 
-		c, w := makeRequest("xpub=" + xpub)
-		controller.GetTransactionByXPUB(c)
+		w := makeRequest("xpub=" + xpub)
 
-		assert.Equal(t, http.StatusInternalServerError, w.Code)
-		assert.JSONEq(t, `{"error":"service failure"}`, w.Body.String())
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+		assert.JSONEq(t, `{"error":{"code":"UPSTREAM_ERROR","message":"Failed to fetch xpub transactions"}}`, w.Body.String())
 
 		mockTransactionService.AssertExpectations(t)
 	})
 
+	t.Run("TransactionService times out", func(t *testing.T) {
+		xpub := "slowxpub"
+		mockTransactionService.On("GetTransactionByXPUB", xpub).Return(nil, fmt.Errorf("request failed: %w", context.DeadlineExceeded)).Once()
+
+		w := makeRequest("xpub=" + xpub)
+
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+		assert.JSONEq(t, `{"error":{"code":"UPSTREAM_TIMEOUT","message":"Failed to fetch xpub transactions"}}`, w.Body.String())
+	})
+
 	t.Run("Successful call", func(t *testing.T) {
 		xpub := "validxpub"
 		mockData := &WalletExplorerTypes.ITransactionXPUB{
@@ -61,8 +75,7 @@ func TestWalletExplorerController_GetTransactionByXPUB(t *testing.T) {
 		}
 		mockTransactionService.On("GetTransactionByXPUB", xpub).Return(mockData, nil).Once()
 
-		c, w := makeRequest("xpub=" + xpub)
-		controller.GetTransactionByXPUB(c)
+		w := makeRequest("xpub=" + xpub)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.JSONEq(t, `{"xpub":{"found":false,"gap_limit":0,"txs":null}}`, w.Body.String())
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	controllers "cry-api/app/controllers/wallet_explorer"
+	"cry-api/app/middleware"
 	WalletExplorerTypes "cry-api/app/types/wallet_explorer"
 	testmocks "cry-api/tests/mocks"
 
@@ -22,20 +23,22 @@ func TestWalletExplorerController_GetTransactionInfo(t *testing.T) {
 		TransactionService: mockTransactionService,
 	}
 
-	makeRequest := func(query string) (*gin.Context, *httptest.ResponseRecorder) {
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.GET("/transaction", controller.GetTransactionInfo)
+
+	makeRequest := func(query string) *httptest.ResponseRecorder {
 		req := httptest.NewRequest(http.MethodGet, "/transaction?"+query, nil)
 		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request = req
-		return c, w
+		router.ServeHTTP(w, req)
+		return w
 	}
 
 	t.Run("Missing txid parameter", func(t *testing.T) {
-		c, w := makeRequest("")
-		controller.GetTransactionInfo(c)
+		w := makeRequest("")
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
-		assert.JSONEq(t, `{"error":"Missing txid parameter"}`, w.Body.String())
+		assert.JSONEq(t, `{"error":{"code":"VALIDATION_FAILED","message":"Missing txid parameter","fields":[{"field":"txid","code":"INVALID","message":"Missing txid parameter"}]}}`, w.Body.String())
 	})
 
 	t.Run("External service error", func(t *testing.T) {
@@ -44,10 +47,10 @@ func TestWalletExplorerController_GetTransactionInfo(t *testing.T) {
 			Return(nil, assert.AnError).
 			Once()
 
-		c, w := makeRequest("txid=" + txid)
-		controller.GetTransactionInfo(c)
+		w := makeRequest("txid=" + txid)
 
-		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+		assert.JSONEq(t, `{"error":{"code":"UPSTREAM_ERROR","message":"Failed to fetch transaction"}}`, w.Body.String())
 		mockTransactionService.AssertExpectations(t)
 	})
 
@@ -79,8 +82,7 @@ func TestWalletExplorerController_GetTransactionInfo(t *testing.T) {
 			Return(mockData, nil).
 			Once()
 
-		c, w := makeRequest("txid=" + txid)
-		controller.GetTransactionInfo(c)
+		w := makeRequest("txid=" + txid)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
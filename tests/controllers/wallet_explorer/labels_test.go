@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	controllers "cry-api/app/controllers/wallet_explorer"
+	UserModel "cry-api/app/models"
+	services "cry-api/app/services/jwt"
+	WalletExplorerTypes "cry-api/app/types/wallet_explorer"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWalletExplorerController_Labels(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockTransactionService := new(testmocks.MockTransactionService)
+	mockUserService := new(testmocks.MockUserService)
+	mockLabelService := new(testmocks.MockLabelService)
+
+	controller := &controllers.WalletExplorerController{
+		TransactionService: mockTransactionService,
+		UserService:        mockUserService,
+		LabelService:       mockLabelService,
+	}
+
+	makeRequest := func(query string, claims *services.Claims) (*gin.Context, *httptest.ResponseRecorder) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/tx?"+query, nil)
+		if claims != nil {
+			c.Set("user", claims)
+		}
+		return c, w
+	}
+
+	t.Run("Authenticated transaction lookup is labelled", func(t *testing.T) {
+		data := &WalletExplorerTypes.ITransactionData{Hash: "txid123"}
+		mockTransactionService.On("GetTransactionByTxID", "txid123").Return(data, nil).Once()
+		mockUserService.On("GetUserByUUID", "user-uuid").Return(&UserModel.User{ID: 7, UUID: "user-uuid"}, nil).Once()
+		mockLabelService.On("AnnotateTransaction", 7, data).Run(func(args mock.Arguments) {
+			args.Get(1).(*WalletExplorerTypes.ITransactionData).Label = "rent"
+		}).Return(nil).Once()
+
+		c, w := makeRequest("txid=txid123", &services.Claims{UUID: "user-uuid"})
+		controller.GetTransactionInfo(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"label":"rent"`)
+	})
+
+	t.Run("Anonymous lookup is not labelled", func(t *testing.T) {
+		data := &WalletExplorerTypes.ITransactionData{Hash: "txid123"}
+		mockTransactionService.On("GetTransactionByTxID", "txid123").Return(data, nil).Once()
+
+		c, w := makeRequest("txid=txid123", nil)
+		controller.GetTransactionInfo(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), `"label"`)
+	})
+
+	t.Run("Label failure does not fail the xpub lookup", func(t *testing.T) {
+		data := &WalletExplorerTypes.ITransactionXPUB{Found: true}
+		mockTransactionService.On("GetTransactionByXPUB", "zpub123").Return(data, nil).Once()
+		mockUserService.On("GetUserByUUID", "user-uuid").Return(&UserModel.User{ID: 7, UUID: "user-uuid"}, nil).Once()
+		mockLabelService.On("AnnotateXPUB", 7, "zpub123", data).Return(assert.AnError).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/xpub?xpub=zpub123", nil)
+		c.Set("user", &services.Claims{UUID: "user-uuid"})
+		controller.GetTransactionByXPUB(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"found":true`)
+	})
+
+	mockLabelService.AssertExpectations(t)
+	mockUserService.AssertExpectations(t)
+}
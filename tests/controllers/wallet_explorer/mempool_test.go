@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	controllers "cry-api/app/controllers/wallet_explorer"
+	"cry-api/app/middleware"
+	app_errors "cry-api/app/types/errors"
+	WalletExplorerTypes "cry-api/app/types/wallet_explorer"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletExplorerController_Mempool(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockMempoolService := new(testmocks.MockMempoolService)
+	controller := &controllers.WalletExplorerController{MempoolService: mockMempoolService}
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.GET("/fees", controller.GetFeeEstimates)
+	router.GET("/tip", controller.GetTip)
+	router.GET("/mempool", controller.GetMempoolStatus)
+
+	serve := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w
+	}
+
+	t.Run("Fee estimates", func(t *testing.T) {
+		mockMempoolService.On("GetFeeEstimates").
+			Return(&WalletExplorerTypes.IFeeEstimates{Fastest: 24, HalfHour: 18, Hour: 12, Economy: 6, Minimum: 3}, nil).Once()
+
+		w := serve("/fees")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"fees":{"fastest":24,"half_hour":18,"hour":12,"economy":6,"minimum":3}}`, w.Body.String())
+	})
+
+	t.Run("Tip upstream failure", func(t *testing.T) {
+		mockMempoolService.On("GetTip").
+			Return(nil, app_errors.NewAppError(http.StatusBadGateway, "Failed to fetch mempool data", "external API returned status 503")).Once()
+
+		w := serve("/tip")
+
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+	})
+
+	t.Run("Missing txid parameter", func(t *testing.T) {
+		w := serve("/mempool")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":{"code":"VALIDATION_FAILED","message":"Missing txid parameter","fields":[{"field":"txid","code":"INVALID","message":"Missing txid parameter"}]}}`, w.Body.String())
+	})
+
+	t.Run("Replaced transaction", func(t *testing.T) {
+		mockMempoolService.On("GetMempoolStatus", "abc").
+			Return(&WalletExplorerTypes.IMempoolStatus{TxID: "abc", Status: WalletExplorerTypes.MempoolStatusReplaced, ReplacedBy: "def"}, nil).Once()
+
+		w := serve("/mempool?txid=abc")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"mempool_status":{"txid":"abc","status":"replaced","confirmations":0,"signals_rbf":false,"replaced_by":"def"}}`, w.Body.String())
+	})
+
+	t.Run("Unknown transaction", func(t *testing.T) {
+		mockMempoolService.On("GetMempoolStatus", "zzz").
+			Return(nil, app_errors.NewNotFoundError("transaction", "Transaction not found in the mempool or the chain")).Once()
+
+		w := serve("/mempool?txid=zzz")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	mockMempoolService.AssertExpectations(t)
+}
@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	controllers "cry-api/app/controllers/wallet_explorer"
+	"cry-api/app/middleware"
+	app_errors "cry-api/app/types/errors"
+	WalletExplorerTypes "cry-api/app/types/wallet_explorer"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletExplorerController_NormalizeDescriptor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockDescriptorService := new(testmocks.MockDescriptorService)
+
+	controller := &controllers.WalletExplorerController{
+		DescriptorService: mockDescriptorService,
+	}
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.GET("/wallet/descriptor", controller.NormalizeDescriptor)
+
+	makeRequest := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/wallet/descriptor?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Missing input parameter", func(t *testing.T) {
+		w := makeRequest("")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":{"code":"VALIDATION_FAILED","message":"Missing input parameter","fields":[{"field":"input","code":"INVALID","message":"Missing input parameter"}]}}`, w.Body.String())
+	})
+
+	t.Run("Invalid count parameter", func(t *testing.T) {
+		w := makeRequest("input=zpub&count=abc")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":{"code":"VALIDATION_FAILED","message":"Invalid count parameter","fields":[{"field":"count","code":"INVALID","message":"Invalid count parameter","value":"abc"}]}}`, w.Body.String())
+	})
+
+	t.Run("Invalid descriptor", func(t *testing.T) {
+		mockDescriptorService.On("NormalizeDescriptor", "wpkh(bad)", 0).
+			Return(nil, errors.New("invalid descriptor")).Once()
+
+		w := makeRequest("input=wpkh(bad)")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":{"code":"VALIDATION_FAILED","message":"invalid descriptor","fields":[{"field":"input","code":"INVALID","message":"invalid descriptor","value":"wpkh(bad)"}]}}`, w.Body.String())
+		mockDescriptorService.AssertExpectations(t)
+	})
+
+	t.Run("Count above the limit", func(t *testing.T) {
+		mockDescriptorService.On("NormalizeDescriptor", "zpub123", 101).
+			Return(nil, app_errors.NewValidationError("count", "101", "Count must be at most 100")).Once()
+
+		w := makeRequest("input=zpub123&count=101")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":{"code":"VALIDATION_FAILED","message":"Count must be at most 100","fields":[{"field":"count","code":"INVALID","message":"Count must be at most 100","value":"101"}]}}`, w.Body.String())
+		mockDescriptorService.AssertExpectations(t)
+	})
+
+	t.Run("Successful call", func(t *testing.T) {
+		mockData := &WalletExplorerTypes.INormalizedDescriptor{
+			InputType:  "extended_key",
+			Descriptor: "wpkh(xpub/0/*)#abcdefgh",
+			ScriptType: "p2wpkh",
+			Network:    "mainnet",
+			IsRange:    true,
+			Addresses: []WalletExplorerTypes.IDerivedAddress{
+				{Index: 0, Address: "bc1qaddress", ScriptPubKey: "0014"},
+			},
+		}
+		mockDescriptorService.On("NormalizeDescriptor", "zpub123", 1).Return(mockData, nil).Once()
+
+		w := makeRequest("input=zpub123&count=1")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{
+			"descriptor": {
+				"input_type": "extended_key",
+				"descriptor": "wpkh(xpub/0/*)#abcdefgh",
+				"script_type": "p2wpkh",
+				"network": "mainnet",
+				"is_range": true,
+				"addresses": [{"index": 0, "address": "bc1qaddress", "script_pubkey": "0014"}]
+			}
+		}`, w.Body.String())
+		mockDescriptorService.AssertExpectations(t)
+	})
+}
+
+func TestWalletExplorerController_ConvertExtendedKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockDescriptorService := new(testmocks.MockDescriptorService)
+
+	controller := &controllers.WalletExplorerController{
+		DescriptorService: mockDescriptorService,
+	}
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.GET("/wallet/xpub/convert", controller.ConvertExtendedKey)
+
+	makeRequest := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/wallet/xpub/convert?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Missing parameters", func(t *testing.T) {
+		w := makeRequest("")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":{"code":"VALIDATION_FAILED","message":"Missing xpub parameter","fields":[{"field":"xpub","code":"INVALID","message":"Missing xpub parameter"}]}}`, w.Body.String())
+
+		w = makeRequest("xpub=zpub123")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":{"code":"VALIDATION_FAILED","message":"Missing to parameter","fields":[{"field":"to","code":"INVALID","message":"Missing to parameter"}]}}`, w.Body.String())
+	})
+
+	t.Run("Successful call", func(t *testing.T) {
+		mockDescriptorService.On("ConvertExtendedKey", "zpub123", "xpub").
+			Return(&WalletExplorerTypes.IConvertedExtendedKey{Input: "zpub123", Prefix: "xpub", Output: "xpub123"}, nil).Once()
+
+		w := makeRequest("xpub=zpub123&to=xpub")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"extended_key":{"input":"zpub123","prefix":"xpub","output":"xpub123"}}`, w.Body.String())
+		mockDescriptorService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,243 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	controllers "cry-api/app/controllers/watchlist"
+	"cry-api/app/middleware"
+	UserModel "cry-api/app/models"
+	FXService "cry-api/app/services/fx"
+	services "cry-api/app/services/jwt"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+	app_errors "cry-api/app/types/errors"
+	ExportTypes "cry-api/app/types/export"
+	WatchlistTypes "cry-api/app/types/watchlist"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testUser = &UserModel.User{ID: 7, UUID: "user-uuid"}
+
+// setupWatchlistRouter registers the watchlist handlers behind fake authentication
+func setupWatchlistRouter(ctrl *controllers.WatchlistController, claims *services.Claims) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	if claims != nil {
+		router.Use(func(c *gin.Context) {
+			c.Set("user", claims)
+			c.Next()
+		})
+	}
+
+	router.GET("/watchlist", ctrl.ListWallets)
+	router.POST("/watchlist", ctrl.AddWallet)
+	router.GET("/watchlist/summary", ctrl.GetSummary)
+	router.GET("/watchlist/export", ctrl.ExportHistory)
+	router.GET("/watchlist/:id", ctrl.GetWallet)
+	router.PUT("/watchlist/:id", ctrl.UpdateWallet)
+	router.DELETE("/watchlist/:id", ctrl.DeleteWallet)
+	return router
+}
+
+func newWatchlistController() (*controllers.WatchlistController, *testmocks.MockUserService, *testmocks.MockWatchlistService) {
+	userService := new(testmocks.MockUserService)
+	watchlistService := new(testmocks.MockWatchlistService)
+	return &controllers.WatchlistController{
+		UserService:      userService,
+		WatchlistService: watchlistService,
+	}, userService, watchlistService
+}
+
+func TestWatchlist_Unauthenticated(t *testing.T) {
+	ctrl, _, _ := newWatchlistController()
+	router := setupWatchlistRouter(ctrl, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/watchlist", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.JSONEq(t, `{"error":{"code":"UNAUTHORIZED","message":"User not authenticated"}}`, w.Body.String())
+}
+
+func TestWatchlist_AddWallet(t *testing.T) {
+	ctrl, userService, watchlistService := newWatchlistController()
+	router := setupWatchlistRouter(ctrl, &services.Claims{UUID: testUser.UUID})
+
+	input := WatchlistTypes.ICreateWatchedWalletRequest{Kind: "address", Value: "bc1qexample", Label: "Savings"}
+	userService.On("GetUserByUUID", testUser.UUID).Return(testUser, nil).Once()
+	watchlistService.On("AddWallet", testUser.ID, input).
+		Return(&UserModel.WatchedWallet{ID: 1, UserID: testUser.ID, Kind: "address", Value: "bc1qexample", Label: "Savings"}, nil).Once()
+
+	body, _ := json.Marshal(input)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/watchlist", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"label":"Savings"`)
+	watchlistService.AssertExpectations(t)
+}
+
+func TestWatchlist_AddWallet_InvalidBody(t *testing.T) {
+	ctrl, _, _ := newWatchlistController()
+	router := setupWatchlistRouter(ctrl, &services.Claims{UUID: testUser.UUID})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/watchlist", bytes.NewBufferString(`{"kind":"address"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":{"code":"VALIDATION_FAILED","message":"Invalid input","fields":[{"field":"value","code":"REQUIRED","message":"This field is required"},{"field":"label","code":"REQUIRED","message":"This field is required"}]}}`, w.Body.String())
+}
+
+func TestWatchlist_AddWallet_Conflict(t *testing.T) {
+	ctrl, userService, watchlistService := newWatchlistController()
+	router := setupWatchlistRouter(ctrl, &services.Claims{UUID: testUser.UUID})
+
+	input := WatchlistTypes.ICreateWatchedWalletRequest{Kind: "address", Value: "bc1qexample", Label: "Savings"}
+	userService.On("GetUserByUUID", testUser.UUID).Return(testUser, nil).Once()
+	watchlistService.On("AddWallet", testUser.ID, input).
+		Return(nil, app_errors.NewConflictError("watched_wallet", "This wallet is already on your watchlist")).Once()
+
+	body, _ := json.Marshal(input)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/watchlist", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.JSONEq(t, `{"error":{"code":"WATCHED_WALLET_CONFLICT","message":"This wallet is already on your watchlist"}}`, w.Body.String())
+}
+
+func TestWatchlist_GetWallet_InvalidID(t *testing.T) {
+	ctrl, _, _ := newWatchlistController()
+	router := setupWatchlistRouter(ctrl, &services.Claims{UUID: testUser.UUID})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/watchlist/abc", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":{"code":"VALIDATION_FAILED","message":"Invalid watched wallet id","fields":[{"field":"id","code":"INVALID","message":"Invalid watched wallet id","value":"abc"}]}}`, w.Body.String())
+}
+
+func TestWatchlist_DeleteWallet_NotFound(t *testing.T) {
+	ctrl, userService, watchlistService := newWatchlistController()
+	router := setupWatchlistRouter(ctrl, &services.Claims{UUID: testUser.UUID})
+
+	userService.On("GetUserByUUID", testUser.UUID).Return(testUser, nil).Once()
+	watchlistService.On("DeleteWallet", testUser.ID, 5).
+		Return(app_errors.NewNotFoundError("watched_wallet", "Watched wallet not found")).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/watchlist/5", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"error":{"code":"WATCHED_WALLET_NOT_FOUND","message":"Watched wallet not found"}}`, w.Body.String())
+}
+
+func TestWatchlist_GetSummary(t *testing.T) {
+	ctrl, userService, watchlistService := newWatchlistController()
+	router := setupWatchlistRouter(ctrl, &services.Claims{UUID: testUser.UUID})
+
+	userService.On("GetUserByUUID", testUser.UUID).Return(testUser, nil).Once()
+	watchlistService.On("GetSummary", testUser.ID).Return(&WatchlistTypes.IWatchlistSummary{
+		TotalBalance:   1.5,
+		Wallets:        []WatchlistTypes.IWatchedWalletBalance{},
+		RecentActivity: []WatchlistTypes.IWatchlistActivity{},
+	}, nil).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/watchlist/summary", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"total_balance":1.5`)
+	watchlistService.AssertExpectations(t)
+}
+
+func TestWatchlist_GetSummary_FiatValues(t *testing.T) {
+	ctrl, userService, watchlistService := newWatchlistController()
+	market := new(testmocks.MockCoinMarketCapService)
+	ctrl.FXService = FXService.NewFXService(market, nil)
+	router := setupWatchlistRouter(ctrl, &services.Claims{UUID: testUser.UUID})
+
+	failure := "upstream down"
+	userService.On("GetUserByUUID", testUser.UUID).Return(&UserModel.User{ID: 7, UUID: testUser.UUID, FiatCurrency: "GBP"}, nil).Once()
+	watchlistService.On("GetSummary", testUser.ID).Return(&WatchlistTypes.IWatchlistSummary{
+		TotalBalance: 0.3,
+		Wallets: []WatchlistTypes.IWatchedWalletBalance{
+			{ID: 1, Balance: 0.1},
+			{ID: 2, Balance: 0.2},
+			{ID: 3, Error: &failure},
+		},
+		RecentActivity: []WatchlistTypes.IWatchlistActivity{},
+	}, nil).Once()
+	market.On("GetLatestQuotes", []string{"BTC"}, []int(nil), "GBP").
+		Return([]CoinMarketCap.IQuote{{Symbol: "BTC", Price: "33333.33"}}, nil).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/watchlist/summary", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Summary WatchlistTypes.IWatchlistSummary `json:"summary"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "3333.33", body.Summary.Wallets[0].Fiat.Value)
+	assert.Equal(t, "6666.67", body.Summary.Wallets[1].Fiat.Value)
+	assert.Nil(t, body.Summary.Wallets[2].Fiat)
+	assert.Equal(t, "GBP", body.Summary.TotalFiat.Currency)
+	assert.Equal(t, int64(1000000), body.Summary.TotalFiat.MinorUnits) // 9999.999 rounds up to 10000.00
+}
+
+func TestWatchlist_ExportHistory(t *testing.T) {
+	ctrl, userService, _ := newWatchlistController()
+	exportService := new(testmocks.MockExportService)
+	ctrl.ExportService = exportService
+	router := setupWatchlistRouter(ctrl, &services.Claims{UUID: "user-uuid"})
+
+	t.Run("Invalid columns", func(t *testing.T) {
+		userService.On("GetUserByUUID", "user-uuid").Return(testUser, nil).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/watchlist/export?columns=memo", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Streams JSON Lines download", func(t *testing.T) {
+		userService.On("GetUserByUUID", "user-uuid").Return(testUser, nil).Once()
+		exportService.On("ExportWatchlist", 7, mock.MatchedBy(func(opts *ExportTypes.IExportOptions) bool {
+			return opts.Currency == "USD"
+		})).Return("{\"txid\":\"a\"}\n", nil).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/watchlist/export?format=jsonl", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="watchlist-history.jsonl"`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, "{\"txid\":\"a\"}\n", w.Body.String())
+	})
+
+	t.Run("Values in the user's fiat currency", func(t *testing.T) {
+		user := &UserModel.User{ID: 7, UUID: "user-uuid", FiatCurrency: "GBP"}
+		userService.On("GetUserByUUID", "user-uuid").Return(user, nil).Once()
+		exportService.On("ExportWatchlist", 7, mock.MatchedBy(func(opts *ExportTypes.IExportOptions) bool {
+			return opts.Currency == "GBP"
+		})).Return("date\n", nil).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/watchlist/export", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	exportService.AssertExpectations(t)
+}
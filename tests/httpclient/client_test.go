@@ -0,0 +1,312 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cry-api/app/httpclient"
+	"cry-api/app/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestClient returns a client with short backoffs so retries do not slow the tests down
+func newTestClient(host httpclient.HostConfig) *httpclient.Client {
+	cfg := httpclient.DefaultConfig()
+	cfg.Default = host
+	cfg.BaseBackoff = time.Millisecond
+	cfg.MaxBackoff = 5 * time.Millisecond
+	cfg.MaxRetryAfter = 2 * time.Second
+	return httpclient.New(cfg)
+}
+
+// statusSequence serves the given statuses in order, then 200 responses
+func statusSequence(statuses ...int) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		n := int(atomic.AddInt32(&calls, 1))
+		if n <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	return server, &calls
+}
+
+func get(t *testing.T, client *httpclient.Client, ctx context.Context, target string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	assert.NoError(t, err)
+	return client.Do(req)
+}
+
+func hostOf(target string) string {
+	u, _ := url.Parse(target)
+	return u.Host
+}
+
+func TestDo_RetriesIdempotentRequests(t *testing.T) {
+	server, calls := statusSequence(http.StatusServiceUnavailable, http.StatusBadGateway)
+	defer server.Close()
+
+	client := newTestClient(httpclient.HostConfig{Timeout: time.Second, MaxRetries: 2})
+	resp, err := get(t, client, context.Background(), server.URL)
+	assert.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "ok", string(body))
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+
+	stats := client.Stats()
+	assert.Len(t, stats, 1)
+	assert.Equal(t, hostOf(server.URL), stats[0].Host)
+	assert.Equal(t, int64(3), stats[0].Requests)
+	assert.Equal(t, int64(2), stats[0].Errors)
+	assert.Equal(t, int64(2), stats[0].Retries)
+	assert.Equal(t, httpclient.StateClosed, stats[0].Breaker)
+}
+
+func TestDo_ReturnsLastResponseWhenRetriesAreExhausted(t *testing.T) {
+	server, calls := statusSequence(http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError)
+	defer server.Close()
+
+	client := newTestClient(httpclient.HostConfig{Timeout: time.Second, MaxRetries: 1})
+	resp, err := get(t, client, context.Background(), server.URL)
+	assert.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestDo_DoesNotRetryClientErrorsOrPosts(t *testing.T) {
+	server, calls := statusSequence(http.StatusNotFound, http.StatusServiceUnavailable)
+	defer server.Close()
+
+	client := newTestClient(httpclient.HostConfig{Timeout: time.Second, MaxRetries: 3})
+
+	resp, err := get(t, client, context.Background(), server.URL)
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("{}"))
+	resp, err = client.Do(req)
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestDo_RetriesPostsWithIdempotencyKey(t *testing.T) {
+	server, calls := statusSequence(http.StatusBadGateway)
+	defer server.Close()
+
+	client := newTestClient(httpclient.HostConfig{Timeout: time.Second, MaxRetries: 2})
+
+	// http.NewRequest sets GetBody for a strings.Reader, so the body is replayed
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"method":"eth_blockNumber"}`))
+	req.Header.Set("Idempotency-Key", "eth_blockNumber")
+	resp, err := client.Do(req)
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestDo_StopsWhenTheBodyCannotBeRewound(t *testing.T) {
+	server, calls := statusSequence(http.StatusBadGateway)
+	defer server.Close()
+
+	client := newTestClient(httpclient.HostConfig{Timeout: time.Second, MaxRetries: 2})
+
+	req, _ := http.NewRequest(http.MethodPut, server.URL, strings.NewReader("payload"))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return nil, errors.New("body gone")
+	}
+	resp, err := client.Do(req)
+
+	assert.Nil(t, resp)
+	assert.ErrorContains(t, err, "failed to rewind request body")
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+	assert.Equal(t, httpclient.StateClosed, client.Stats()[0].Breaker)
+}
+
+func TestDo_HonorsRetryAfter(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := newTestClient(httpclient.HostConfig{Timeout: time.Second, MaxRetries: 1})
+	start := time.Now()
+	resp, err := get(t, client, context.Background(), server.URL)
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func TestDo_GivesUpOnLongRetryAfter(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := newTestClient(httpclient.HostConfig{Timeout: time.Second, MaxRetries: 3})
+	resp, err := get(t, client, context.Background(), server.URL)
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestDo_AppliesHostTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	cfg := httpclient.DefaultConfig()
+	cfg.Default = httpclient.HostConfig{Timeout: time.Second}
+	cfg.Hosts[hostOf(server.URL)] = httpclient.HostConfig{Timeout: 20 * time.Millisecond}
+	client := httpclient.New(cfg)
+
+	_, err := get(t, client, context.Background(), server.URL)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int64(1), client.Stats()[0].Errors)
+}
+
+func TestDo_StopsWhenTheCallerCancels(t *testing.T) {
+	server, calls := statusSequence(http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	defer server.Close()
+
+	cfg := httpclient.DefaultConfig()
+	cfg.Default = httpclient.HostConfig{Timeout: time.Second, MaxRetries: 5}
+	cfg.BaseBackoff = time.Minute
+	cfg.MaxBackoff = time.Minute
+	client := httpclient.New(cfg)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := get(t, client, ctx, server.URL)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.LessOrEqual(t, atomic.LoadInt32(calls), int32(2))
+}
+
+func TestDo_CircuitBreakerOpensAndRecovers(t *testing.T) {
+	var healthy atomic.Bool
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := newTestClient(httpclient.HostConfig{
+		Timeout:          time.Second,
+		BreakerThreshold: 2,
+		BreakerCooldown:  50 * time.Millisecond,
+	})
+
+	for i := 0; i < 2; i++ {
+		resp, err := get(t, client, context.Background(), server.URL)
+		assert.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+
+	_, err := get(t, client, context.Background(), server.URL)
+	assert.True(t, errors.Is(err, httpclient.ErrCircuitOpen))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	stats := client.Stats()[0]
+	assert.Equal(t, httpclient.StateOpen, stats.Breaker)
+	assert.Equal(t, int64(1), stats.Rejected)
+
+	// After the cooldown a successful probe closes the breaker again
+	healthy.Store(true)
+	time.Sleep(60 * time.Millisecond)
+	resp, err := get(t, client, context.Background(), server.URL)
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, httpclient.StateClosed, client.Stats()[0].Breaker)
+}
+
+func TestDo_FailedProbeReopensTheBreaker(t *testing.T) {
+	server, calls := statusSequence(http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+	defer server.Close()
+
+	client := newTestClient(httpclient.HostConfig{
+		Timeout:          time.Second,
+		BreakerThreshold: 1,
+		BreakerCooldown:  30 * time.Millisecond,
+	})
+
+	resp, err := get(t, client, context.Background(), server.URL)
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+
+	time.Sleep(40 * time.Millisecond)
+	resp, err = get(t, client, context.Background(), server.URL)
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+
+	_, err = get(t, client, context.Background(), server.URL)
+	assert.ErrorIs(t, err, httpclient.ErrCircuitOpen)
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestDo_ForwardsTheRequestID(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get(httpclient.RequestIDHeader))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := newTestClient(httpclient.HostConfig{Timeout: time.Second})
+
+	resp, err := get(t, client, logger.WithRequestID(context.Background(), "req-789"), server.URL)
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+
+	resp, err = get(t, client, context.Background(), server.URL)
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, []string{"req-789", ""}, received)
+}
@@ -0,0 +1,109 @@
+// Package jsonrpc provides a local JSON-RPC 2.0 node stand-in for tests of the EVM services.
+//
+//	node := jsonrpc.New(t)
+//	node.Result("eth_blockNumber", "0x10")
+//	node.Handle("eth_call", func(params []json.RawMessage) (any, *jsonrpc.Error) { ... })
+//	svc := services.NewEVMService(cfg(node.URL), http.DefaultClient)
+//
+// Methods without a handler answer with a "method not found" error.
+package jsonrpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// Error is a JSON-RPC error object
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Handler answers a call with a result or an error
+type Handler func(params []json.RawMessage) (any, *Error)
+
+// Server is a JSON-RPC node stand-in served over HTTP
+type Server struct {
+	URL string
+
+	mu       sync.Mutex
+	handlers map[string]Handler
+	calls    map[string][][]json.RawMessage
+}
+
+type request struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// New starts a stand-in node, stopped when the test ends
+func New(t *testing.T) *Server {
+	s := &Server{
+		handlers: make(map[string]Handler),
+		calls:    make(map[string][][]json.RawMessage),
+	}
+	server := httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(server.Close)
+	s.URL = server.URL
+	return s
+}
+
+// Handle registers the handler of a method
+func (s *Server) Handle(method string, handler Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method] = handler
+}
+
+// Result makes a method always answer with result. A nil result is sent as null.
+func (s *Server) Result(method string, result any) {
+	s.Handle(method, func([]json.RawMessage) (any, *Error) {
+		return result, nil
+	})
+}
+
+// Calls returns the params of every call of a method, in order
+func (s *Server) Calls(method string) [][]json.RawMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON-RPC request", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.calls[req.Method] = append(s.calls[req.Method], req.Params)
+	handler, ok := s.handlers[req.Method]
+	s.mu.Unlock()
+
+	resp := response{JSONRPC: "2.0", ID: req.ID}
+	if !ok {
+		resp.Error = &Error{Code: -32601, Message: "the method " + req.Method + " does not exist/is not available"}
+	} else {
+		resp.Result, resp.Error = handler(req.Params)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if resp.Error == nil && resp.Result == nil {
+		// omitempty would drop a null result
+		_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": nil})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	Email "cry-api/app/email"
+	"cry-api/app/metrics"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// scrape returns the exposition of m as served to Prometheus
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain; version=0.0.4"))
+	return w.Body.String()
+}
+
+type stubSender struct{ err error }
+
+func (s stubSender) Send(_ Email.EmailMessage) error { return s.err }
+
+type stubStats struct {
+	stats sql.DBStats
+	err   error
+}
+
+func (s stubStats) Stats() (sql.DBStats, error) { return s.stats, s.err }
+
+func TestMetrics_RuntimeCollectors(t *testing.T) {
+	body := scrape(t, metrics.New())
+
+	assert.Contains(t, body, "# TYPE go_goroutines gauge\n")
+	assert.Contains(t, body, "# TYPE go_memstats_heap_alloc_bytes gauge\n")
+	assert.Contains(t, body, `cry_rate_limit_rejections_total 0`+"\n")
+}
+
+func TestMetrics_ObserveRequest(t *testing.T) {
+	m := metrics.New()
+
+	m.ObserveRequest(http.MethodPost, "/api/v1/users/signup", http.StatusCreated, "", 20*time.Millisecond)
+	m.ObserveRequest(http.MethodPost, "/api/v1/users/signup", http.StatusConflict, "USER_ALREADY_EXISTS", time.Millisecond)
+	m.ObserveRequest(http.MethodPost, "/api/v1/users/verify-email-token", http.StatusOK, "", time.Millisecond)
+	m.ObserveRequest(http.MethodPost, "/api/v1/users/signin", http.StatusOK, "", time.Millisecond)
+	m.ObserveRequest(http.MethodPost, "/api/v1/users/signin", http.StatusUnauthorized, "AUTH_INVALID_CREDENTIALS", time.Millisecond)
+	m.ObserveRequest(http.MethodPost, "/api/v1/users/signin", http.StatusTooManyRequests, "RATE_LIMITED", time.Millisecond)
+	m.ObserveRequest(http.MethodPost, "/api/v1/2fa/auth/verify-otp", http.StatusUnauthorized, "OTP_INVALID", time.Millisecond)
+	m.ObserveRequest(http.MethodGet, "", http.StatusNotFound, "ROUTE_NOT_FOUND", time.Millisecond)
+	m.ObserveRequest(http.MethodGet, "/api/v1/realtime/sse", http.StatusOK, "", time.Hour)
+
+	body := scrape(t, m)
+	for _, line := range []string{
+		`cry_http_requests_total{method="POST",route="/api/v1/users/signup",status="201"} 1`,
+		`cry_http_request_duration_seconds_bucket{method="POST",route="/api/v1/users/signup",status="201",le="0.025"} 1`,
+		`cry_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`cry_http_errors_total{code="ROUTE_NOT_FOUND"} 1`,
+		`cry_signups_total{result="success"} 1`,
+		`cry_signups_total{result="failure"} 1`,
+		`cry_verifications_total{result="success"} 1`,
+		`cry_logins_total{result="success"} 1`,
+		`cry_logins_total{result="failure"} 1`,
+		`cry_otp_failures_total{code="OTP_INVALID"} 1`,
+		`cry_rate_limit_rejections_total 1`,
+		`cry_http_requests_total{method="GET",route="/api/v1/realtime/sse",status="200"} 1`,
+	} {
+		assert.Contains(t, body, line+"\n")
+	}
+	assert.NotContains(t, body, `cry_http_request_duration_seconds_count{method="GET",route="/api/v1/realtime/sse"`)
+}
+
+func TestMetrics_OpenStream(t *testing.T) {
+	m := metrics.New()
+
+	closeSSE := m.OpenStream("/api/v1/realtime/sse")
+	m.OpenStream("/api/v1/realtime/sse")
+	closeWS := m.OpenStream("/api/v1/realtime/ws")
+	closeSSE()
+	closeWS()
+
+	body := scrape(t, m)
+	assert.Contains(t, body, `cry_realtime_open_streams{route="/api/v1/realtime/sse"} 1`+"\n")
+	assert.Contains(t, body, `cry_realtime_open_streams{route="/api/v1/realtime/ws"} 0`+"\n")
+	assert.True(t, metrics.IsStream("/api/v1/realtime/ws"))
+	assert.False(t, metrics.IsStream("/api/v1/watchlist/:id"))
+}
+
+func TestInstrumentedTransport(t *testing.T) {
+	statuses := []int{http.StatusOK, http.StatusServiceUnavailable, http.StatusNotFound}
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(statuses[calls])
+		calls++
+	}))
+	defer server.Close()
+
+	m := metrics.New()
+	host := strings.TrimPrefix(server.URL, "http://")
+	client := &http.Client{Transport: metrics.NewInstrumentedTransport(nil, m, map[string]string{host: "coingecko"})}
+	for range statuses {
+		resp, err := client.Get(server.URL)
+		assert.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+
+	unnamed := &http.Client{Transport: metrics.NewInstrumentedTransport(nil, m, nil)}
+	_, err := unnamed.Get("http://127.0.0.1:1")
+	assert.Error(t, err)
+
+	body := scrape(t, m)
+	assert.Contains(t, body, `cry_upstream_request_duration_seconds_count{provider="coingecko"} 3`+"\n")
+	assert.Contains(t, body, `cry_upstream_errors_total{provider="coingecko"} 1`+"\n")
+	assert.Contains(t, body, `cry_upstream_errors_total{provider="127.0.0.1:1"} 1`+"\n")
+}
+
+func TestInstrumentedEmailSender(t *testing.T) {
+	m := metrics.New()
+
+	assert.NoError(t, metrics.NewInstrumentedEmailSender(stubSender{}, m).Send(Email.EmailMessage{}))
+	failure := errors.New("smtp unavailable")
+	assert.Equal(t, failure, metrics.NewInstrumentedEmailSender(stubSender{err: failure}, m).Send(Email.EmailMessage{}))
+
+	body := scrape(t, m)
+	assert.Contains(t, body, `cry_emails_total{result="success"} 1`+"\n")
+	assert.Contains(t, body, `cry_emails_total{result="failure"} 1`+"\n")
+}
+
+func TestMetrics_ObserveDatabase(t *testing.T) {
+	m := metrics.New()
+	m.ObserveDatabase(stubStats{stats: sql.DBStats{MaxOpenConnections: 25, OpenConnections: 4, InUse: 1, Idle: 3, WaitCount: 7, WaitDuration: 1500 * time.Millisecond}})
+
+	body := scrape(t, m)
+	for _, line := range []string{
+		"cry_db_max_open_connections 25",
+		"cry_db_open_connections 4",
+		"cry_db_in_use_connections 1",
+		"cry_db_idle_connections 3",
+		"cry_db_wait_count_total 7",
+		"cry_db_wait_duration_seconds_total 1.5",
+	} {
+		assert.Contains(t, body, line+"\n")
+	}
+
+	unavailable := metrics.New()
+	unavailable.ObserveDatabase(stubStats{err: errors.New("sql: database is closed")})
+	assert.NotContains(t, scrape(t, unavailable), "cry_db_")
+}
@@ -0,0 +1,147 @@
+package middleware_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"cry-api/app/middleware"
+	app_errors "cry-api/app/types/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type bindingRequest struct {
+	Email   string `json:"email" binding:"required,email"`
+	Address struct {
+		City string `json:"city" binding:"required"`
+	} `json:"address"`
+	Age int `json:"age"`
+}
+
+func newErrorRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.HandleMethodNotAllowed = true
+	router.Use(middleware.ErrorHandler())
+	router.NoRoute(middleware.NoRoute)
+	router.NoMethod(middleware.NoMethod)
+	return router
+}
+
+func TestErrorHandler_Envelope(t *testing.T) {
+	router := newErrorRouter()
+	router.GET("/not-found", func(c *gin.Context) {
+		middleware.AbortWithError(c, app_errors.NewNotFoundError("Watched wallet", ""))
+	})
+	router.GET("/upstream", func(c *gin.Context) {
+		middleware.AbortWithError(c, app_errors.NewAppError(http.StatusBadGateway, "Failed to fetch prices", "timeout"))
+	})
+	router.GET("/validation", func(c *gin.Context) {
+		middleware.AbortWithError(c, app_errors.NewValidationError("days", "abc", "Days must be a number"))
+	})
+	router.GET("/plain", func(c *gin.Context) {
+		middleware.AbortWithError(c, errors.New("dial tcp 10.0.0.1:5432: connection refused"))
+	})
+	router.GET("/written", func(c *gin.Context) {
+		c.JSON(http.StatusAccepted, gin.H{"ok": true})
+		middleware.AbortWithError(c, app_errors.ErrDatabaseError)
+	})
+
+	tests := []struct {
+		name         string
+		path         string
+		expectedCode int
+		expectedBody string
+	}{
+		{"Coded resource error", "/not-found", http.StatusNotFound,
+			`{"error":{"code":"WATCHED_WALLET_NOT_FOUND","message":"Watched wallet not found"}}`},
+		{"Status coded error with details", "/upstream", http.StatusBadGateway,
+			`{"error":{"code":"UPSTREAM_ERROR","message":"Failed to fetch prices","details":"timeout"}}`},
+		{"Validation error", "/validation", http.StatusBadRequest,
+			`{"error":{"code":"VALIDATION_FAILED","message":"Days must be a number","fields":[{"field":"days","code":"INVALID","message":"Days must be a number","value":"abc"}]}}`},
+		{"Unknown error does not leak", "/plain", http.StatusInternalServerError,
+			`{"error":{"code":"INTERNAL_ERROR","message":"Internal server error"}}`},
+		{"Written response is kept", "/written", http.StatusAccepted, `{"ok":true}`},
+		{"Unknown route", "/missing", http.StatusNotFound,
+			`{"error":{"code":"ROUTE_NOT_FOUND","message":"Route not found"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestErrorHandler_MethodNotAllowed(t *testing.T) {
+	router := newErrorRouter()
+	router.GET("/resource", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/resource", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.JSONEq(t, `{"error":{"code":"METHOD_NOT_ALLOWED","message":"Method not allowed"}}`, w.Body.String())
+}
+
+func TestErrorHandler_RequestID(t *testing.T) {
+	router := newErrorRouter()
+	router.GET("/fail", func(c *gin.Context) {
+		middleware.AbortWithError(c, app_errors.NewInternalServerError(""))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/fail", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-123")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"error":{"code":"INTERNAL_ERROR","message":"Internal server error","request_id":"req-123"}}`, w.Body.String())
+}
+
+func TestNewBindingError(t *testing.T) {
+	router := newErrorRouter()
+	router.POST("/bind", func(c *gin.Context) {
+		var req bindingRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.AbortWithError(c, app_errors.NewBindingError(err, &req))
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name         string
+		body         string
+		expectedBody string
+	}{
+		{"Malformed JSON", `{"email":`,
+			`{"error":{"code":"INVALID_JSON","message":"Invalid JSON format"}}`},
+		{"Wrong type", `{"email":"a@b.co","address":{"city":"Paris"},"age":"old"}`,
+			`{"error":{"code":"INVALID_JSON","message":"Invalid JSON format","fields":[{"field":"age","code":"TYPE","message":"Must be a int"}]}}`},
+		{"Invalid fields by JSON name", `{"email":"not-an-email"}`,
+			`{"error":{"code":"VALIDATION_FAILED","message":"Invalid input","fields":[` +
+				`{"field":"email","code":"EMAIL","message":"Must be a valid email address"},` +
+				`{"field":"address.city","code":"REQUIRED","message":"This field is required"}]}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/bind", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
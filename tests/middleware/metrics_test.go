@@ -0,0 +1,120 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cry-api/app/metrics"
+	"cry-api/app/middleware"
+	app_errors "cry-api/app/types/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMetricsRouter(m *metrics.Metrics) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.MetricsMiddleware(m))
+	router.Use(middleware.RecoveryMiddleware())
+	router.Use(middleware.ErrorHandler())
+	router.NoRoute(middleware.NoRoute)
+	router.GET("/metrics", middleware.MetricsAuthMiddleware("scrape-token"), gin.WrapH(m.Handler()))
+	return router
+}
+
+func serve(router *gin.Engine, method, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMetricsMiddleware(t *testing.T) {
+	m := metrics.New()
+	router := newMetricsRouter(m)
+	router.GET("/api/v1/watchlist/:id", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"id": c.Param("id")})
+	})
+	router.POST("/api/v1/users/signin", func(c *gin.Context) {
+		middleware.AbortWithError(c, app_errors.NewCodedError(http.StatusUnauthorized, app_errors.CodeAuthInvalidCredentials, "Invalid email or password", ""))
+	})
+	router.POST("/api/v1/2fa/auth/verify-otp", func(c *gin.Context) {
+		middleware.AbortWithError(c, app_errors.NewCodedError(http.StatusUnauthorized, app_errors.CodeOTPExpired, "OTP expired", ""))
+	})
+	router.GET("/panic", func(_ *gin.Context) {
+		panic("boom")
+	})
+
+	serve(router, http.MethodGet, "/api/v1/watchlist/1", "")
+	serve(router, http.MethodGet, "/api/v1/watchlist/2", "")
+	serve(router, http.MethodPost, "/api/v1/users/signin", "")
+	serve(router, http.MethodPost, "/api/v1/2fa/auth/verify-otp", "")
+	serve(router, http.MethodGet, "/panic", "")
+	serve(router, http.MethodGet, "/does-not-exist", "")
+
+	w := serve(router, http.MethodGet, "/metrics", "scrape-token")
+	assert.Equal(t, http.StatusOK, w.Code)
+	for _, line := range []string{
+		`cry_http_requests_total{method="GET",route="/api/v1/watchlist/:id",status="200"} 2`,
+		`cry_http_requests_total{method="POST",route="/api/v1/users/signin",status="401"} 1`,
+		`cry_http_requests_total{method="GET",route="/panic",status="500"} 1`,
+		`cry_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`cry_http_errors_total{code="AUTH_INVALID_CREDENTIALS"} 1`,
+		`cry_http_errors_total{code="INTERNAL_ERROR"} 1`,
+		`cry_logins_total{result="failure"} 1`,
+		`cry_otp_failures_total{code="OTP_EXPIRED"} 1`,
+	} {
+		assert.Contains(t, w.Body.String(), line+"\n")
+	}
+}
+
+func TestMetricsMiddleware_Streams(t *testing.T) {
+	m := metrics.New()
+	router := newMetricsRouter(m)
+	var during string
+	router.GET("/api/v1/realtime/sse", func(c *gin.Context) {
+		during = serve(router, http.MethodGet, "/metrics", "scrape-token").Body.String()
+		c.Status(http.StatusOK)
+	})
+
+	serve(router, http.MethodGet, "/api/v1/realtime/sse", "")
+
+	assert.Contains(t, during, `cry_realtime_open_streams{route="/api/v1/realtime/sse"} 1`+"\n")
+	after := serve(router, http.MethodGet, "/metrics", "scrape-token").Body.String()
+	assert.Contains(t, after, `cry_realtime_open_streams{route="/api/v1/realtime/sse"} 0`+"\n")
+	assert.Contains(t, after, `cry_http_requests_total{method="GET",route="/api/v1/realtime/sse",status="200"} 1`+"\n")
+	assert.NotContains(t, after, `cry_http_request_duration_seconds_count{method="GET",route="/api/v1/realtime/sse"`)
+}
+
+func TestMetricsAuthMiddleware(t *testing.T) {
+	router := newMetricsRouter(metrics.New())
+
+	tests := []struct {
+		name         string
+		token        string
+		expectedCode int
+	}{
+		{"Missing token", "", http.StatusUnauthorized},
+		{"Wrong token", "guess", http.StatusUnauthorized},
+		{"Valid token", "scrape-token", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(router, http.MethodGet, "/metrics", tt.token)
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusUnauthorized {
+				assert.JSONEq(t, `{"error":{"code":"UNAUTHORIZED","message":"Invalid metrics token"}}`, w.Body.String())
+			}
+		})
+	}
+
+	unprotected := gin.New()
+	unprotected.GET("/metrics", middleware.MetricsAuthMiddleware(""), func(c *gin.Context) { c.Status(http.StatusOK) })
+	assert.Equal(t, http.StatusUnauthorized, serve(unprotected, http.MethodGet, "/metrics", "anything").Code)
+}
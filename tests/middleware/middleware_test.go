@@ -1,11 +1,14 @@
 package middleware_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
 
+	"cry-api/app/logger"
 	"cry-api/app/middleware"
 	JwtServices "cry-api/app/services/jwt"
 
@@ -51,19 +54,19 @@ func TestJWTAuthMiddleware(t *testing.T) {
 			name:         "Missing Authorization header",
 			authHeader:   "",
 			expectedCode: http.StatusUnauthorized,
-			expectedBody: `{"error":"Authorization header missing"}`,
+			expectedBody: `{"error":{"code":"AUTH_MISSING_TOKEN","message":"Authorization header missing"}}`,
 		},
 		{
 			name:         "Malformed Authorization header",
 			authHeader:   "Basic abcdefg",
 			expectedCode: http.StatusUnauthorized,
-			expectedBody: `{"error":"Authorization header format must be Bearer {token}"}`,
+			expectedBody: `{"error":{"code":"AUTH_INVALID_TOKEN","message":"Authorization header format must be Bearer {token}"}}`,
 		},
 		{
 			name:         "Invalid token",
 			authHeader:   "Bearer invalid.token.here",
 			expectedCode: http.StatusUnauthorized,
-			expectedBody: `{"error":"Invalid or expired token"}`,
+			expectedBody: `{"error":{"code":"AUTH_INVALID_TOKEN","message":"Invalid or expired token"}}`,
 		},
 		{
 			name:            "Valid token",
@@ -94,3 +97,121 @@ func TestJWTAuthMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestOptionalJWTAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(middleware.OptionalJWTAuthMiddleware())
+	r.GET("/public", func(c *gin.Context) {
+		claims, ok := middleware.CurrentUserClaims(c)
+		if !ok {
+			c.JSON(http.StatusOK, gin.H{"user": nil})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"user": claims.UUID})
+	})
+
+	tests := []struct {
+		name         string
+		authHeader   string
+		expectedCode int
+		expectedBody string
+	}{
+		{"Anonymous", "", http.StatusOK, `{"user":null}`},
+		{"Valid token", "Bearer " + generateTestJWT(t, false, false), http.StatusOK, `{"user":"test-uuid-1234"}`},
+		{"Invalid token", "Bearer invalid.token.here", http.StatusUnauthorized, `{"error":{"code":"AUTH_INVALID_TOKEN","message":"Invalid or expired token"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/public", nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestStreamJWTAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(middleware.StreamJWTAuthMiddleware())
+	r.GET("/stream", func(c *gin.Context) {
+		claims, _ := middleware.CurrentUserClaims(c)
+		c.JSON(http.StatusOK, gin.H{"user": claims.UUID})
+	})
+
+	token := generateTestJWT(t, false, false)
+	tests := []struct {
+		name         string
+		query        string
+		authHeader   string
+		expectedCode int
+		expectedBody string
+	}{
+		{"Header", "", "Bearer " + token, http.StatusOK, `{"user":"test-uuid-1234"}`},
+		{"Query parameter", "?access_token=" + token, "", http.StatusOK, `{"user":"test-uuid-1234"}`},
+		{"Header wins over the query", "?access_token=" + token, "Bearer invalid.token.here", http.StatusUnauthorized, `{"error":{"code":"AUTH_INVALID_TOKEN","message":"Invalid or expired token"}}`},
+		{"Missing token", "", "", http.StatusUnauthorized, `{"error":{"code":"AUTH_MISSING_TOKEN","message":"Authorization header missing"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/stream"+tt.query, nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestRequestLoggerMiddleware_RedactsAccessToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var out bytes.Buffer
+	log := logger.NewLogger()
+	log.SetOutput(&out)
+	previous := logger.GetLogger()
+	logger.SetDefaultLogger(log)
+	t.Cleanup(func() { logger.SetDefaultLogger(previous) })
+
+	router := gin.New()
+	router.Use(middleware.RequestLoggerMiddleware())
+	router.GET("/realtime/sse", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{"Token is redacted", "access_token=secret&topics=wallets", "/realtime/sse?access_token=REDACTED&topics=wallets"},
+		{"Query without token is kept", "topics=wallets", "/realtime/sse?topics=wallets"},
+		{"Unparsable query is dropped", "topics=%zz&access_token=secret", "/realtime/sse?REDACTED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out.Reset()
+			req := httptest.NewRequest(http.MethodGet, "/realtime/sse?"+tt.query, nil)
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			var entry map[string]any
+			assert.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+			assert.Equal(t, tt.expected, entry["path"])
+			assert.NotContains(t, out.String(), "secret")
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"cry-api/app/logger"
+	"cry-api/app/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRequestIDRouter(handlers ...gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequestIDMiddleware())
+	router.Use(middleware.RecoveryMiddleware())
+	router.Use(middleware.ErrorHandler())
+	router.GET("/resource", handlers...)
+	return router
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var contextID string
+	router := newRequestIDRouter(func(c *gin.Context) {
+		contextID = logger.RequestID(c.Request.Context())
+		c.String(http.StatusOK, middleware.RequestID(c))
+	})
+
+	tests := []struct {
+		name       string
+		header     string
+		expectSame bool
+	}{
+		{"Client id is kept", "client-id_1.2:3", true},
+		{"Missing id is generated", "", false},
+		{"Id with unsafe characters is replaced", "bad id\r\n", false},
+		{"Oversized id is replaced", strings.Repeat("a", 129), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/resource", nil)
+			if tt.header != "" {
+				req.Header.Set(middleware.RequestIDHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			id := w.Header().Get(middleware.RequestIDHeader)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, id, w.Body.String())
+			assert.Equal(t, id, contextID)
+			if tt.expectSame {
+				assert.Equal(t, tt.header, id)
+			} else {
+				_, err := uuid.Parse(id)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRequestIDMiddleware_UserUUIDFromJWT(t *testing.T) {
+	var userUUID string
+	router := newRequestIDRouter(middleware.JWTAuthMiddleware(), func(c *gin.Context) {
+		userUUID = logger.UserUUID(c.Request.Context())
+		c.Status(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/resource", nil)
+	req.Header.Set("Authorization", "Bearer "+generateTestJWT(t, false, false))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "test-uuid-1234", userUUID)
+}
+
+func TestRecoveryMiddleware(t *testing.T) {
+	router := newRequestIDRouter(func(_ *gin.Context) {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/resource", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-456")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "req-456", w.Header().Get(middleware.RequestIDHeader))
+	assert.JSONEq(t, `{"error":{"code":"INTERNAL_ERROR","message":"Internal server error","request_id":"req-456"}}`, w.Body.String())
+}
@@ -0,0 +1,54 @@
+package mocks
+
+import (
+	UserModel "cry-api/app/models"
+	AlertTypes "cry-api/app/types/alert"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAlertService mocks AlertServiceInterface
+type MockAlertService struct {
+	mock.Mock
+}
+
+// CreateRule mocks CreateRule from AlertService
+func (m *MockAlertService) CreateRule(userID int, req AlertTypes.ICreateAlertRuleRequest) (*UserModel.AlertRule, error) {
+	args := m.Called(userID, req)
+	rule, _ := args.Get(0).(*UserModel.AlertRule)
+	return rule, args.Error(1)
+}
+
+// ListRules mocks ListRules from AlertService
+func (m *MockAlertService) ListRules(userID int) ([]UserModel.AlertRule, error) {
+	args := m.Called(userID)
+	rules, _ := args.Get(0).([]UserModel.AlertRule)
+	return rules, args.Error(1)
+}
+
+// GetRule mocks GetRule from AlertService
+func (m *MockAlertService) GetRule(userID, id int) (*UserModel.AlertRule, error) {
+	args := m.Called(userID, id)
+	rule, _ := args.Get(0).(*UserModel.AlertRule)
+	return rule, args.Error(1)
+}
+
+// UpdateRule mocks UpdateRule from AlertService
+func (m *MockAlertService) UpdateRule(userID, id int, req AlertTypes.IUpdateAlertRuleRequest) (*UserModel.AlertRule, error) {
+	args := m.Called(userID, id, req)
+	rule, _ := args.Get(0).(*UserModel.AlertRule)
+	return rule, args.Error(1)
+}
+
+// DeleteRule mocks DeleteRule from AlertService
+func (m *MockAlertService) DeleteRule(userID, id int) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+// ListTriggers mocks ListTriggers from AlertService
+func (m *MockAlertService) ListTriggers(userID, ruleID int) ([]UserModel.AlertTrigger, error) {
+	args := m.Called(userID, ruleID)
+	triggers, _ := args.Get(0).([]UserModel.AlertTrigger)
+	return triggers, args.Error(1)
+}
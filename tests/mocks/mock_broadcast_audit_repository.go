@@ -0,0 +1,25 @@
+package mocks
+
+import (
+	UserModel "cry-api/app/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockBroadcastAuditRepository mocks BroadcastAuditRepository
+type MockBroadcastAuditRepository struct {
+	mock.Mock
+}
+
+// Save mocks Save from BroadcastAuditRepository
+func (m *MockBroadcastAuditRepository) Save(audit *UserModel.BroadcastAudit) error {
+	args := m.Called(audit)
+	return args.Error(0)
+}
+
+// FindByUserID mocks FindByUserID from BroadcastAuditRepository
+func (m *MockBroadcastAuditRepository) FindByUserID(userID int) ([]UserModel.BroadcastAudit, error) {
+	args := m.Called(userID)
+	audits, _ := args.Get(0).([]UserModel.BroadcastAudit)
+	return audits, args.Error(1)
+}
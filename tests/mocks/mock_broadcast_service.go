@@ -0,0 +1,22 @@
+package mocks
+
+import (
+	"context"
+	WalletExplorer "cry-api/app/types/wallet_explorer"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockBroadcastService mocks the BroadcastService for testing purposes.
+type MockBroadcastService struct {
+	mock.Mock
+}
+
+// Broadcast mocks the Broadcast method of the BroadcastService.
+func (m *MockBroadcastService) Broadcast(_ context.Context, userID int, clientIP, rawTx string) (*WalletExplorer.IBroadcastResult, error) {
+	args := m.Called(userID, clientIP, rawTx)
+	if result := args.Get(0); result != nil {
+		return result.(*WalletExplorer.IBroadcastResult), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
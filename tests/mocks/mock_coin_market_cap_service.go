@@ -0,0 +1,58 @@
+package mocks
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCoinMarketCapService mocks CoinMarketCapServiceInterface
+type MockCoinMarketCapService struct {
+	mock.Mock
+}
+
+// GetFearAndGreedLastest mocks GetFearAndGreedLastest from CoinMarketCapService
+func (m *MockCoinMarketCapService) GetFearAndGreedLastest(_ context.Context) (*CoinMarketCap.FearGreedData, error) {
+	args := m.Called()
+	data, _ := args.Get(0).(*CoinMarketCap.FearGreedData)
+	return data, args.Error(1)
+}
+
+// GetFearAndGreedHistorical mocks GetFearAndGreedHistorical from CoinMarketCapService
+func (m *MockCoinMarketCapService) GetFearAndGreedHistorical(_ context.Context, start, limit int) (*CoinMarketCap.FearGreedHistorical, error) {
+	args := m.Called(start, limit)
+	data, _ := args.Get(0).(*CoinMarketCap.FearGreedHistorical)
+	return data, args.Error(1)
+}
+
+// GetHistoricalPrices mocks GetHistoricalPrices from CoinMarketCapService
+func (m *MockCoinMarketCapService) GetHistoricalPrices(_ context.Context, symbol, convert string, from, to time.Time) ([]CoinMarketCap.IPricePoint, error) {
+	args := m.Called(symbol, convert, from, to)
+	points, _ := args.Get(0).([]CoinMarketCap.IPricePoint)
+	return points, args.Error(1)
+}
+
+// GetLatestQuotes mocks GetLatestQuotes from CoinMarketCapService
+func (m *MockCoinMarketCapService) GetLatestQuotes(_ context.Context, symbols []string, ids []int, convert string) ([]CoinMarketCap.IQuote, error) {
+	args := m.Called(symbols, ids, convert)
+	quotes, _ := args.Get(0).([]CoinMarketCap.IQuote)
+	return quotes, args.Error(1)
+}
+
+// GetOHLCV mocks GetOHLCV from CoinMarketCapService
+func (m *MockCoinMarketCapService) GetOHLCV(_ context.Context, symbol, convert, interval string, from, to time.Time) (*CoinMarketCap.IOHLCV, error) {
+	args := m.Called(symbol, convert, interval, from, to)
+	data, _ := args.Get(0).(*CoinMarketCap.IOHLCV)
+	return data, args.Error(1)
+}
+
+// ConvertPrice mocks ConvertPrice from CoinMarketCapService
+func (m *MockCoinMarketCapService) ConvertPrice(_ context.Context, amount *big.Rat, symbol, convert string) (*CoinMarketCap.IConversion, error) {
+	args := m.Called(amount, symbol, convert)
+	data, _ := args.Get(0).(*CoinMarketCap.IConversion)
+	return data, args.Error(1)
+}
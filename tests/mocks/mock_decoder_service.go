@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	WalletExplorer "cry-api/app/types/wallet_explorer"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockDecoderService mocks the DecoderService for testing purposes.
+type MockDecoderService struct {
+	mock.Mock
+}
+
+// DecodeTransaction mocks the DecodeTransaction method of the DecoderService.
+func (m *MockDecoderService) DecodeTransaction(payload, network string) (*WalletExplorer.IDecodedTransaction, error) {
+	args := m.Called(payload, network)
+	if result := args.Get(0); result != nil {
+		return result.(*WalletExplorer.IDecodedTransaction), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,30 @@
+package mocks
+
+import (
+	WalletExplorer "cry-api/app/types/wallet_explorer"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockDescriptorService mocks the DescriptorService for testing purposes.
+type MockDescriptorService struct {
+	mock.Mock
+}
+
+// NormalizeDescriptor mocks the NormalizeDescriptor method of the DescriptorService.
+func (m *MockDescriptorService) NormalizeDescriptor(input string, count int) (*WalletExplorer.INormalizedDescriptor, error) {
+	args := m.Called(input, count)
+	if result := args.Get(0); result != nil {
+		return result.(*WalletExplorer.INormalizedDescriptor), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// ConvertExtendedKey mocks the ConvertExtendedKey method of the DescriptorService.
+func (m *MockDescriptorService) ConvertExtendedKey(key, prefix string) (*WalletExplorer.IConvertedExtendedKey, error) {
+	args := m.Called(key, prefix)
+	if result := args.Get(0); result != nil {
+		return result.(*WalletExplorer.IConvertedExtendedKey), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -30,6 +30,18 @@ func (m *MockEmailService) SendTwoFactorAlternativeEmail(to, from, username, otp
 	return args.Error(0)
 }
 
+// SendWalletNotificationEmail mocks SendWalletNotificationEmail from EmailService
+func (m *MockEmailService) SendWalletNotificationEmail(to, from, username, title, message, notificationsLink string) error {
+	args := m.Called(to, from, username, title, message, notificationsLink)
+	return args.Error(0)
+}
+
+// SendAlertEmail mocks SendAlertEmail from EmailService
+func (m *MockEmailService) SendAlertEmail(to, from, username, title, message, alertsLink string) error {
+	args := m.Called(to, from, username, title, message, alertsLink)
+	return args.Error(0)
+}
+
 // MockEmailSender mocks the EmailSender interface
 type MockEmailSender struct {
 	mock.Mock
@@ -63,3 +75,15 @@ func (m *MockEmailCreator) CreateTwoFactorAlternativeEmail(to, from, userName, o
 	args := m.Called(to, from, userName, otp, expiryMinutes)
 	return args.Get(0).(Email.EmailMessage), args.Error(1)
 }
+
+// CreateWalletNotificationEmail mocks CreateWalletNotificationEmail from EmailCreator
+func (m *MockEmailCreator) CreateWalletNotificationEmail(to, from, userName, title, message, notificationsLink string) (Email.EmailMessage, error) {
+	args := m.Called(to, from, userName, title, message, notificationsLink)
+	return args.Get(0).(Email.EmailMessage), args.Error(1)
+}
+
+// CreateAlertEmail mocks CreateAlertEmail from EmailCreator
+func (m *MockEmailCreator) CreateAlertEmail(to, from, userName, title, message, alertsLink string) (Email.EmailMessage, error) {
+	args := m.Called(to, from, userName, title, message, alertsLink)
+	return args.Get(0).(Email.EmailMessage), args.Error(1)
+}
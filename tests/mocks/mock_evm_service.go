@@ -0,0 +1,40 @@
+package mocks
+
+import (
+	"context"
+	EVMTypes "cry-api/app/types/evm"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockEVMService mocks the EVMService for testing purposes.
+type MockEVMService struct {
+	mock.Mock
+}
+
+// GetTransaction mocks the GetTransaction method of the EVMService.
+func (m *MockEVMService) GetTransaction(_ context.Context, chain, hash string) (*EVMTypes.IEVMTransaction, error) {
+	args := m.Called(chain, hash)
+	if result := args.Get(0); result != nil {
+		return result.(*EVMTypes.IEVMTransaction), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetBalances mocks the GetBalances method of the EVMService.
+func (m *MockEVMService) GetBalances(_ context.Context, chain, address string, tokens []string) (*EVMTypes.IEVMBalances, error) {
+	args := m.Called(chain, address, tokens)
+	if result := args.Get(0); result != nil {
+		return result.(*EVMTypes.IEVMBalances), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetTokenTransfers mocks the GetTokenTransfers method of the EVMService.
+func (m *MockEVMService) GetTokenTransfers(_ context.Context, chain, address string) (*EVMTypes.IEVMTransfers, error) {
+	args := m.Called(chain, address)
+	if result := args.Get(0); result != nil {
+		return result.(*EVMTypes.IEVMTransfers), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,43 @@
+package mocks
+
+import (
+	"context"
+	"io"
+
+	ExportTypes "cry-api/app/types/export"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockExportService mocks ExportServiceInterface. The string returned by an expectation is
+// written to the response before its error, to simulate failures after streaming started.
+type MockExportService struct {
+	mock.Mock
+}
+
+// ExportAddress mocks ExportAddress from ExportService
+func (m *MockExportService) ExportAddress(_ context.Context, w io.Writer, address string, opts *ExportTypes.IExportOptions) error {
+	args := m.Called(address, opts)
+	return writeExport(w, args)
+}
+
+// ExportXPUB mocks ExportXPUB from ExportService
+func (m *MockExportService) ExportXPUB(_ context.Context, w io.Writer, xpub string, opts *ExportTypes.IExportOptions) error {
+	args := m.Called(xpub, opts)
+	return writeExport(w, args)
+}
+
+// ExportWatchlist mocks ExportWatchlist from ExportService
+func (m *MockExportService) ExportWatchlist(_ context.Context, w io.Writer, userID int, opts *ExportTypes.IExportOptions) error {
+	args := m.Called(userID, opts)
+	return writeExport(w, args)
+}
+
+func writeExport(w io.Writer, args mock.Arguments) error {
+	if body := args.String(0); body != "" {
+		if _, err := io.WriteString(w, body); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
@@ -0,0 +1,44 @@
+package mocks
+
+import (
+	"context"
+
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockFearGreedService mocks FearGreedServiceInterface
+type MockFearGreedService struct {
+	mock.Mock
+}
+
+// Run mocks Run from FearGreedService
+func (m *MockFearGreedService) Run(_ context.Context) {
+	m.Called()
+}
+
+// Sync mocks Sync from FearGreedService
+func (m *MockFearGreedService) Sync(_ context.Context) (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+// GetRange mocks GetRange from FearGreedService
+func (m *MockFearGreedService) GetRange(from, to, interval string) (*CoinMarketCap.IFearGreedRange, error) {
+	args := m.Called(from, to, interval)
+	data, _ := args.Get(0).(*CoinMarketCap.IFearGreedRange)
+	return data, args.Error(1)
+}
+
+// MockFearGreedAnalyticsService mocks FearGreedAnalyticsServiceInterface
+type MockFearGreedAnalyticsService struct {
+	mock.Mock
+}
+
+// GetAnalytics mocks GetAnalytics from FearGreedAnalyticsService
+func (m *MockFearGreedAnalyticsService) GetAnalytics(from, to, window string) (*CoinMarketCap.IFearGreedAnalytics, error) {
+	args := m.Called(from, to, window)
+	data, _ := args.Get(0).(*CoinMarketCap.IFearGreedAnalytics)
+	return data, args.Error(1)
+}
@@ -0,0 +1,45 @@
+package mocks
+
+import (
+	UserModel "cry-api/app/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockLabelRepository mocks LabelRepository
+type MockLabelRepository struct {
+	mock.Mock
+}
+
+// Save mocks Save from LabelRepository
+func (m *MockLabelRepository) Save(label *UserModel.Label) error {
+	args := m.Called(label)
+	return args.Error(0)
+}
+
+// FindByUserID mocks FindByUserID from LabelRepository
+func (m *MockLabelRepository) FindByUserID(userID int, labelType string) ([]UserModel.Label, error) {
+	args := m.Called(userID, labelType)
+	labels, _ := args.Get(0).([]UserModel.Label)
+	return labels, args.Error(1)
+}
+
+// FindByRefs mocks FindByRefs from LabelRepository
+func (m *MockLabelRepository) FindByRefs(userID int, refs []string) ([]UserModel.Label, error) {
+	args := m.Called(userID, refs)
+	labels, _ := args.Get(0).([]UserModel.Label)
+	return labels, args.Error(1)
+}
+
+// FindByRef mocks FindByRef from LabelRepository
+func (m *MockLabelRepository) FindByRef(userID int, labelType, ref string) (*UserModel.Label, error) {
+	args := m.Called(userID, labelType, ref)
+	label, _ := args.Get(0).(*UserModel.Label)
+	return label, args.Error(1)
+}
+
+// Delete mocks Delete from LabelRepository
+func (m *MockLabelRepository) Delete(id, userID int) (bool, error) {
+	args := m.Called(id, userID)
+	return args.Bool(0), args.Error(1)
+}
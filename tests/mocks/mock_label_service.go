@@ -0,0 +1,69 @@
+package mocks
+
+import (
+	"io"
+
+	UserModel "cry-api/app/models"
+	LabelTypes "cry-api/app/types/label"
+	WalletExplorer "cry-api/app/types/wallet_explorer"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockLabelService mocks LabelServiceInterface
+type MockLabelService struct {
+	mock.Mock
+}
+
+// ListLabels mocks ListLabels from LabelService
+func (m *MockLabelService) ListLabels(userID int, labelType string) ([]UserModel.Label, error) {
+	args := m.Called(userID, labelType)
+	labels, _ := args.Get(0).([]UserModel.Label)
+	return labels, args.Error(1)
+}
+
+// SetLabel mocks SetLabel from LabelService
+func (m *MockLabelService) SetLabel(userID int, req LabelTypes.ILabelRecord) (*UserModel.Label, error) {
+	args := m.Called(userID, req)
+	label, _ := args.Get(0).(*UserModel.Label)
+	return label, args.Error(1)
+}
+
+// DeleteLabel mocks DeleteLabel from LabelService
+func (m *MockLabelService) DeleteLabel(userID, id int) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+// ImportLabels mocks ImportLabels from LabelService. The uploaded content is read and passed
+// to the expectation as a string.
+func (m *MockLabelService) ImportLabels(userID int, r io.Reader) (*LabelTypes.IImportResult, error) {
+	body, _ := io.ReadAll(r)
+	args := m.Called(userID, string(body))
+	result, _ := args.Get(0).(*LabelTypes.IImportResult)
+	return result, args.Error(1)
+}
+
+// ExportLabels mocks ExportLabels from LabelService. The string returned by the expectation
+// is written to w.
+func (m *MockLabelService) ExportLabels(userID int, w io.Writer) error {
+	args := m.Called(userID)
+	if body := args.String(0); body != "" {
+		if _, err := io.WriteString(w, body); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+// AnnotateTransaction mocks AnnotateTransaction from LabelService
+func (m *MockLabelService) AnnotateTransaction(userID int, tx *WalletExplorer.ITransactionData) error {
+	args := m.Called(userID, tx)
+	return args.Error(0)
+}
+
+// AnnotateXPUB mocks AnnotateXPUB from LabelService
+func (m *MockLabelService) AnnotateXPUB(userID int, xpub string, data *WalletExplorer.ITransactionXPUB) error {
+	args := m.Called(userID, xpub, data)
+	return args.Error(0)
+}
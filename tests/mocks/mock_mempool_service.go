@@ -0,0 +1,49 @@
+package mocks
+
+import (
+	"context"
+	WalletExplorer "cry-api/app/types/wallet_explorer"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockMempoolService mocks the MempoolService for testing purposes.
+type MockMempoolService struct {
+	mock.Mock
+}
+
+// GetFeeEstimates mocks the GetFeeEstimates method of the MempoolService.
+func (m *MockMempoolService) GetFeeEstimates(_ context.Context) (*WalletExplorer.IFeeEstimates, error) {
+	args := m.Called()
+	if result := args.Get(0); result != nil {
+		return result.(*WalletExplorer.IFeeEstimates), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetTip mocks the GetTip method of the MempoolService.
+func (m *MockMempoolService) GetTip(_ context.Context) (*WalletExplorer.IBlockTip, error) {
+	args := m.Called()
+	if result := args.Get(0); result != nil {
+		return result.(*WalletExplorer.IBlockTip), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetMempoolStatus mocks the GetMempoolStatus method of the MempoolService.
+func (m *MockMempoolService) GetMempoolStatus(_ context.Context, txid string) (*WalletExplorer.IMempoolStatus, error) {
+	args := m.Called(txid)
+	if result := args.Get(0); result != nil {
+		return result.(*WalletExplorer.IMempoolStatus), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// GetOutputValues mocks the GetOutputValues method of the MempoolService.
+func (m *MockMempoolService) GetOutputValues(_ context.Context, txid string) ([]int64, error) {
+	args := m.Called(txid)
+	if result := args.Get(0); result != nil {
+		return result.([]int64), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
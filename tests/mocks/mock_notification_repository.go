@@ -0,0 +1,46 @@
+package mocks
+
+import (
+	"time"
+
+	UserModel "cry-api/app/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockNotificationRepository mocks NotificationRepository
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+// Create mocks Create from NotificationRepository
+func (m *MockNotificationRepository) Create(notification *UserModel.Notification) (bool, error) {
+	args := m.Called(notification)
+	return args.Bool(0), args.Error(1)
+}
+
+// Save mocks Save from NotificationRepository
+func (m *MockNotificationRepository) Save(notification *UserModel.Notification) error {
+	args := m.Called(notification)
+	return args.Error(0)
+}
+
+// FindByUserID mocks FindByUserID from NotificationRepository
+func (m *MockNotificationRepository) FindByUserID(userID int, unreadOnly bool, limit int) ([]UserModel.Notification, error) {
+	args := m.Called(userID, unreadOnly, limit)
+	notifications, _ := args.Get(0).([]UserModel.Notification)
+	return notifications, args.Error(1)
+}
+
+// FindByIDAndUserID mocks FindByIDAndUserID from NotificationRepository
+func (m *MockNotificationRepository) FindByIDAndUserID(id, userID int) (*UserModel.Notification, error) {
+	args := m.Called(id, userID)
+	notification, _ := args.Get(0).(*UserModel.Notification)
+	return notification, args.Error(1)
+}
+
+// MarkAllRead mocks MarkAllRead from NotificationRepository
+func (m *MockNotificationRepository) MarkAllRead(userID int, at time.Time) (int64, error) {
+	args := m.Called(userID, at)
+	return args.Get(0).(int64), args.Error(1)
+}
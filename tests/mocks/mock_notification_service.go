@@ -0,0 +1,32 @@
+package mocks
+
+import (
+	UserModel "cry-api/app/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockNotificationService mocks NotificationServiceInterface
+type MockNotificationService struct {
+	mock.Mock
+}
+
+// ListNotifications mocks ListNotifications from NotificationService
+func (m *MockNotificationService) ListNotifications(userID int, unreadOnly bool) ([]UserModel.Notification, error) {
+	args := m.Called(userID, unreadOnly)
+	notifications, _ := args.Get(0).([]UserModel.Notification)
+	return notifications, args.Error(1)
+}
+
+// MarkRead mocks MarkRead from NotificationService
+func (m *MockNotificationService) MarkRead(userID, id int) (*UserModel.Notification, error) {
+	args := m.Called(userID, id)
+	notification, _ := args.Get(0).(*UserModel.Notification)
+	return notification, args.Error(1)
+}
+
+// MarkAllRead mocks MarkAllRead from NotificationService
+func (m *MockNotificationService) MarkAllRead(userID int) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
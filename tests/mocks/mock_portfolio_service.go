@@ -0,0 +1,20 @@
+package mocks
+
+import (
+	"context"
+	PortfolioTypes "cry-api/app/types/portfolio"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPortfolioService mocks PortfolioServiceInterface
+type MockPortfolioService struct {
+	mock.Mock
+}
+
+// GetValuation mocks GetValuation from PortfolioService
+func (m *MockPortfolioService) GetValuation(_ context.Context, userID int, currency string, days int) (*PortfolioTypes.IPortfolioValuation, error) {
+	args := m.Called(userID, currency, days)
+	valuation, _ := args.Get(0).(*PortfolioTypes.IPortfolioValuation)
+	return valuation, args.Error(1)
+}
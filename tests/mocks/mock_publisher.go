@@ -0,0 +1,15 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPublisher mocks the realtime Publisher
+type MockPublisher struct {
+	mock.Mock
+}
+
+// PublishToUser mocks PublishToUser from Hub
+func (m *MockPublisher) PublishToUser(userID int, topic string, data any) {
+	m.Called(userID, topic, data)
+}
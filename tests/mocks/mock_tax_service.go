@@ -0,0 +1,41 @@
+package mocks
+
+import (
+	"context"
+	UserModel "cry-api/app/models"
+	TaxTypes "cry-api/app/types/tax"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTaxService mocks TaxServiceInterface
+type MockTaxService struct {
+	mock.Mock
+}
+
+// ListTransfers mocks ListTransfers from TaxService
+func (m *MockTaxService) ListTransfers(userID int) ([]UserModel.TransferTag, error) {
+	args := m.Called(userID)
+	tags, _ := args.Get(0).([]UserModel.TransferTag)
+	return tags, args.Error(1)
+}
+
+// TagTransfer mocks TagTransfer from TaxService
+func (m *MockTaxService) TagTransfer(userID int, req TaxTypes.ITransferTagRequest) (*UserModel.TransferTag, error) {
+	args := m.Called(userID, req)
+	tag, _ := args.Get(0).(*UserModel.TransferTag)
+	return tag, args.Error(1)
+}
+
+// UntagTransfer mocks UntagTransfer from TaxService
+func (m *MockTaxService) UntagTransfer(userID, id int) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+// GetReport mocks GetReport from TaxService
+func (m *MockTaxService) GetReport(_ context.Context, userID, walletID int, method, currency string, year int) (*TaxTypes.ITaxReport, error) {
+	args := m.Called(userID, walletID, method, currency, year)
+	report, _ := args.Get(0).(*TaxTypes.ITaxReport)
+	return report, args.Error(1)
+}
@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"context"
 	WalletExplorer "cry-api/app/types/wallet_explorer"
 
 	"github.com/stretchr/testify/mock"
@@ -12,7 +13,7 @@ type MockTransactionService struct {
 }
 
 // GetTransactionByXPUB mocks the GetTransactionByXPUB method of the MockTransactionService.
-func (m *MockTransactionService) GetTransactionByXPUB(xpub string) (*WalletExplorer.ITransactionXPUB, error) {
+func (m *MockTransactionService) GetTransactionByXPUB(_ context.Context, xpub string) (*WalletExplorer.ITransactionXPUB, error) {
 	args := m.Called(xpub)
 	if result := args.Get(0); result != nil {
 		return result.(*WalletExplorer.ITransactionXPUB), args.Error(1)
@@ -21,10 +22,39 @@ func (m *MockTransactionService) GetTransactionByXPUB(xpub string) (*WalletExplo
 }
 
 // GetTransactionByTxID mocks the GetTransactionByTxID method of the MockTransactionService.
-func (m *MockTransactionService) GetTransactionByTxID(txid string) (*WalletExplorer.ITransactionData, error) {
+func (m *MockTransactionService) GetTransactionByTxID(_ context.Context, txid string) (*WalletExplorer.ITransactionData, error) {
 	args := m.Called(txid)
 	if result := args.Get(0); result != nil {
 		return result.(*WalletExplorer.ITransactionData), args.Error(1)
 	}
 	return nil, args.Error(1)
 }
+
+// GetTransactionByAddress mocks the GetTransactionByAddress method of the MockTransactionService.
+func (m *MockTransactionService) GetTransactionByAddress(_ context.Context, address string) (*WalletExplorer.ITransactionAddress, error) {
+	args := m.Called(address)
+	if result := args.Get(0); result != nil {
+		return result.(*WalletExplorer.ITransactionAddress), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// StreamAddressTransactions mocks the StreamAddressTransactions method of the MockTransactionService.
+// The transactions returned by the expectation are replayed through fn.
+func (m *MockTransactionService) StreamAddressTransactions(_ context.Context, address string, fn func(WalletExplorer.AddressTransaction) error) error {
+	args := m.Called(address)
+	if txs, ok := args.Get(0).([]WalletExplorer.AddressTransaction); ok {
+		for _, tx := range txs {
+			if err := fn(tx); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+// GetBlockHeight mocks the GetBlockHeight method of the MockTransactionService.
+func (m *MockTransactionService) GetBlockHeight(_ context.Context) (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
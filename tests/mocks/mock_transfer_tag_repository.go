@@ -0,0 +1,38 @@
+package mocks
+
+import (
+	UserModel "cry-api/app/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTransferTagRepository mocks TransferTagRepository
+type MockTransferTagRepository struct {
+	mock.Mock
+}
+
+// Save mocks Save from TransferTagRepository
+func (m *MockTransferTagRepository) Save(tag *UserModel.TransferTag) error {
+	args := m.Called(tag)
+	return args.Error(0)
+}
+
+// FindByUserID mocks FindByUserID from TransferTagRepository
+func (m *MockTransferTagRepository) FindByUserID(userID int) ([]UserModel.TransferTag, error) {
+	args := m.Called(userID)
+	tags, _ := args.Get(0).([]UserModel.TransferTag)
+	return tags, args.Error(1)
+}
+
+// FindByTxID mocks FindByTxID from TransferTagRepository
+func (m *MockTransferTagRepository) FindByTxID(userID int, txid string) (*UserModel.TransferTag, error) {
+	args := m.Called(userID, txid)
+	tag, _ := args.Get(0).(*UserModel.TransferTag)
+	return tag, args.Error(1)
+}
+
+// Delete mocks Delete from TransferTagRepository
+func (m *MockTransferTagRepository) Delete(id, userID int) (bool, error) {
+	args := m.Called(id, userID)
+	return args.Bool(0), args.Error(1)
+}
@@ -0,0 +1,52 @@
+package mocks
+
+import (
+	UserModel "cry-api/app/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWatchedWalletRepository mocks WatchedWalletRepository
+type MockWatchedWalletRepository struct {
+	mock.Mock
+}
+
+// Save mocks Save from WatchedWalletRepository
+func (m *MockWatchedWalletRepository) Save(wallet *UserModel.WatchedWallet) error {
+	args := m.Called(wallet)
+	return args.Error(0)
+}
+
+// FindByUserID mocks FindByUserID from WatchedWalletRepository
+func (m *MockWatchedWalletRepository) FindByUserID(userID int) ([]UserModel.WatchedWallet, error) {
+	args := m.Called(userID)
+	wallets, _ := args.Get(0).([]UserModel.WatchedWallet)
+	return wallets, args.Error(1)
+}
+
+// FindAll mocks FindAll from WatchedWalletRepository
+func (m *MockWatchedWalletRepository) FindAll() ([]UserModel.WatchedWallet, error) {
+	args := m.Called()
+	wallets, _ := args.Get(0).([]UserModel.WatchedWallet)
+	return wallets, args.Error(1)
+}
+
+// FindByIDAndUserID mocks FindByIDAndUserID from WatchedWalletRepository
+func (m *MockWatchedWalletRepository) FindByIDAndUserID(id, userID int) (*UserModel.WatchedWallet, error) {
+	args := m.Called(id, userID)
+	wallet, _ := args.Get(0).(*UserModel.WatchedWallet)
+	return wallet, args.Error(1)
+}
+
+// FindByValue mocks FindByValue from WatchedWalletRepository
+func (m *MockWatchedWalletRepository) FindByValue(userID int, value string) (*UserModel.WatchedWallet, error) {
+	args := m.Called(userID, value)
+	wallet, _ := args.Get(0).(*UserModel.WatchedWallet)
+	return wallet, args.Error(1)
+}
+
+// Delete mocks Delete from WatchedWalletRepository
+func (m *MockWatchedWalletRepository) Delete(id, userID int) error {
+	args := m.Called(id, userID)
+	return args.Error(0)
+}
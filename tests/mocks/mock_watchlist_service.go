@@ -0,0 +1,76 @@
+package mocks
+
+import (
+	"context"
+	UserModel "cry-api/app/models"
+	WatchlistTypes "cry-api/app/types/watchlist"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWatchlistService mocks WatchlistServiceInterface
+type MockWatchlistService struct {
+	mock.Mock
+}
+
+// AddWallet mocks AddWallet from WatchlistService
+func (m *MockWatchlistService) AddWallet(userID int, req WatchlistTypes.ICreateWatchedWalletRequest) (*UserModel.WatchedWallet, error) {
+	args := m.Called(userID, req)
+	wallet, _ := args.Get(0).(*UserModel.WatchedWallet)
+	return wallet, args.Error(1)
+}
+
+// ListWallets mocks ListWallets from WatchlistService
+func (m *MockWatchlistService) ListWallets(userID int) ([]UserModel.WatchedWallet, error) {
+	args := m.Called(userID)
+	wallets, _ := args.Get(0).([]UserModel.WatchedWallet)
+	return wallets, args.Error(1)
+}
+
+// GetWallet mocks GetWallet from WatchlistService
+func (m *MockWatchlistService) GetWallet(userID, id int) (*UserModel.WatchedWallet, error) {
+	args := m.Called(userID, id)
+	wallet, _ := args.Get(0).(*UserModel.WatchedWallet)
+	return wallet, args.Error(1)
+}
+
+// UpdateWallet mocks UpdateWallet from WatchlistService
+func (m *MockWatchlistService) UpdateWallet(userID, id int, req WatchlistTypes.IUpdateWatchedWalletRequest) (*UserModel.WatchedWallet, error) {
+	args := m.Called(userID, id, req)
+	wallet, _ := args.Get(0).(*UserModel.WatchedWallet)
+	return wallet, args.Error(1)
+}
+
+// DeleteWallet mocks DeleteWallet from WatchlistService
+func (m *MockWatchlistService) DeleteWallet(userID, id int) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+// GetSummary mocks GetSummary from WatchlistService
+func (m *MockWatchlistService) GetSummary(_ context.Context, userID int) (*WatchlistTypes.IWatchlistSummary, error) {
+	args := m.Called(userID)
+	summary, _ := args.Get(0).(*WatchlistTypes.IWatchlistSummary)
+	return summary, args.Error(1)
+}
+
+// GetWalletHistory mocks GetWalletHistory from WatchlistService
+func (m *MockWatchlistService) GetWalletHistory(_ context.Context, wallet *UserModel.WatchedWallet) ([]WatchlistTypes.IWatchlistActivity, error) {
+	args := m.Called(wallet)
+	activity, _ := args.Get(0).([]WatchlistTypes.IWatchlistActivity)
+	return activity, args.Error(1)
+}
+
+// GetRecentWalletActivity mocks GetRecentWalletActivity from WatchlistService
+func (m *MockWatchlistService) GetRecentWalletActivity(_ context.Context, wallet *UserModel.WatchedWallet) ([]WatchlistTypes.IWatchlistActivity, error) {
+	args := m.Called(wallet)
+	activity, _ := args.Get(0).([]WatchlistTypes.IWatchlistActivity)
+	return activity, args.Error(1)
+}
+
+// GetHistory mocks GetHistory from WatchlistService
+func (m *MockWatchlistService) GetHistory(_ context.Context, userID int) (*WatchlistTypes.IWatchlistHistory, error) {
+	args := m.Called(userID)
+	history, _ := args.Get(0).(*WatchlistTypes.IWatchlistHistory)
+	return history, args.Error(1)
+}
@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"cry-api/app/money"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromRat_RoundsHalfAwayFromZero(t *testing.T) {
+	tests := []struct {
+		value    *big.Rat
+		currency string
+		amount   string
+		minor    int64
+	}{
+		{big.NewRat(12345, 1000), "USD", "12.35", 1235},
+		{big.NewRat(-12345, 1000), "USD", "-12.35", -1235},
+		{big.NewRat(12344, 1000), "EUR", "12.34", 1234},
+		{big.NewRat(5, 1000), "EUR", "0.01", 1},
+		{big.NewRat(-4, 1000), "EUR", "0.00", 0},
+		{big.NewRat(-5, 100), "EUR", "-0.05", -5},
+		{big.NewRat(25, 2), "JPY", "13", 13},
+		{big.NewRat(12345, 10000), "KWD", "1.235", 1235},
+	}
+
+	for _, tt := range tests {
+		amount, err := money.FromRat(tt.value, tt.currency)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.currency, amount.Currency)
+		assert.Equal(t, tt.amount, amount.Value, tt.value.String())
+		assert.Equal(t, tt.minor, amount.MinorUnits, tt.value.String())
+	}
+}
+
+func TestFromRat_UnsupportedCurrency(t *testing.T) {
+	_, err := money.FromRat(big.NewRat(1, 1), "XXX")
+	assert.ErrorIs(t, err, money.ErrUnsupportedCurrency)
+	assert.False(t, money.IsSupported("usd"))
+	assert.True(t, money.IsSupported("USD"))
+}
+
+func TestAmount_JSONAndRat(t *testing.T) {
+	amount, err := money.FromMinor(123456, "EUR")
+	assert.NoError(t, err)
+
+	data, err := json.Marshal(amount)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"currency":"EUR","amount":"1234.56","minor_units":123456}`, string(data))
+	assert.Equal(t, 0, amount.Rat().Cmp(big.NewRat(123456, 100)))
+}
+
+func TestFromFloat_ReadsTheDecimal(t *testing.T) {
+	// 0.1 + 0.2 is not 0.3 in float64, but the decimals the floats were written as add up
+	sum := new(big.Rat).Add(money.FromFloat(0.1), money.FromFloat(0.2))
+	assert.Equal(t, 0, sum.Cmp(big.NewRat(3, 10)))
+
+	assert.Equal(t, 0, money.Sats(150_000_000).Cmp(big.NewRat(3, 2)))
+}
+
+func TestParseDecimal(t *testing.T) {
+	r, err := money.ParseDecimal("0.00012")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, r.Cmp(big.NewRat(12, 100000)))
+
+	for _, invalid := range []string{"", "1/3", "1e5", "abc", "0x10", "."} {
+		_, err := money.ParseDecimal(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestFormatDecimal(t *testing.T) {
+	assert.Equal(t, "65000.5", money.FormatDecimal(big.NewRat(130001, 2)))
+	assert.Equal(t, "-42", money.FormatDecimal(big.NewRat(-42, 1)))
+	assert.Equal(t, "0.00012", money.FormatDecimal(big.NewRat(12, 100000)))
+	assert.Equal(t, "0.333333333333333333", money.FormatDecimal(big.NewRat(1, 3)))
+}
@@ -0,0 +1,52 @@
+package tests
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	UserModel "cry-api/app/models"
+	repositorie "cry-api/app/repositories"
+	mocks "cry-api/tests/mocks"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGormBroadcastAuditRepository_Save(t *testing.T) {
+	db, mock, cleanup := mocks.SetupMockDB(t)
+	defer cleanup()
+	repo := repositorie.NewGormBroadcastAuditRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "broadcast_audits"`)).
+		WithArgs(7, "abc", "0200", UserModel.BroadcastStatusBroadcast, "mempool", 5.5, "", "203.0.113.9", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	audit := &UserModel.BroadcastAudit{
+		UserID: 7, TxID: "abc", RawTx: "0200", Status: UserModel.BroadcastStatusBroadcast,
+		Provider: "mempool", FeeRate: 5.5, IPAddress: "203.0.113.9", CreatedAt: time.Now(),
+	}
+	assert.NoError(t, repo.Save(audit))
+	assert.Equal(t, 1, audit.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormBroadcastAuditRepository_FindByUserID(t *testing.T) {
+	db, mock, cleanup := mocks.SetupMockDB(t)
+	defer cleanup()
+	repo := repositorie.NewGormBroadcastAuditRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "broadcast_audits" WHERE user_id = $1 ORDER BY id DESC`)).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "tx_id", "status"}).
+			AddRow(2, 7, "def", UserModel.BroadcastStatusRejected).
+			AddRow(1, 7, "abc", UserModel.BroadcastStatusBroadcast))
+
+	audits, err := repo.FindByUserID(7)
+	assert.NoError(t, err)
+	assert.Len(t, audits, 2)
+	assert.Equal(t, "def", audits[0].TxID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"regexp"
+	"testing"
+
+	UserModel "cry-api/app/models"
+	repositorie "cry-api/app/repositories"
+	mocks "cry-api/tests/mocks"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGormFearGreedRepository_Upsert(t *testing.T) {
+	db, mock, cleanup := mocks.SetupMockDB(t)
+	defer cleanup()
+	repo := repositorie.NewGormFearGreedRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "fear_greed_index"`) + `.*` +
+		regexp.QuoteMeta(`ON CONFLICT ("date") DO UPDATE SET "value"="excluded"."value","classification"="excluded"."classification","source"="excluded"."source","updated_at"="excluded"."updated_at"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	mock.ExpectCommit()
+
+	err := repo.Upsert([]UserModel.FearGreedIndex{
+		{Date: "2024-10-18", Value: 40, Classification: "Fear"},
+		{Date: "2024-10-17", Value: 38, Classification: "Fear"},
+	})
+	assert.NoError(t, err)
+
+	// Nothing to store
+	assert.NoError(t, repo.Upsert(nil))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormFearGreedRepository_FindRange(t *testing.T) {
+	db, mock, cleanup := mocks.SetupMockDB(t)
+	defer cleanup()
+	repo := repositorie.NewGormFearGreedRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "fear_greed_index" WHERE date >= $1 AND date <= $2 ORDER BY date ASC`)).
+		WithArgs("2024-10-01", "2024-10-31").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date", "value", "classification"}).
+			AddRow(1, "2024-10-01", 40, "Fear").
+			AddRow(2, "2024-10-02", 50, "Neutral"))
+
+	points, err := repo.FindRange("2024-10-01", "2024-10-31")
+	assert.NoError(t, err)
+	assert.Len(t, points, 2)
+	assert.Equal(t, "Neutral", points[1].Classification)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormFearGreedRepository_Latest(t *testing.T) {
+	db, mock, cleanup := mocks.SetupMockDB(t)
+	defer cleanup()
+	repo := repositorie.NewGormFearGreedRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "fear_greed_index" ORDER BY date DESC`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date", "value"}).AddRow(9, "2024-10-18", 40))
+
+	point, err := repo.Latest()
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-10-18", point.Date)
+
+	// Empty history
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "fear_greed_index" ORDER BY date DESC`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	point, err = repo.Latest()
+	assert.NoError(t, err)
+	assert.Nil(t, point)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
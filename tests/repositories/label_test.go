@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	repositorie "cry-api/app/repositories"
+	mocks "cry-api/tests/mocks"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var labelColumns = []string{"id", "user_id", "type", "ref", "label", "origin", "spendable", "created_at", "updated_at"}
+
+func TestGormLabelRepository_FindByUserID(t *testing.T) {
+	db, mock, cleanup := mocks.SetupMockDB(t)
+	defer cleanup()
+	repo := repositorie.NewGormLabelRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "labels" WHERE user_id = $1 AND type = $2 ORDER BY id ASC`)).
+		WithArgs(1, "tx").
+		WillReturnRows(sqlmock.NewRows(labelColumns).
+			AddRow(3, 1, "tx", "abc", "rent", "", nil, time.Now(), time.Now()))
+
+	labels, err := repo.FindByUserID(1, "tx")
+	assert.NoError(t, err)
+	assert.Len(t, labels, 1)
+	assert.Equal(t, "rent", labels[0].Label)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormLabelRepository_FindByRefs(t *testing.T) {
+	db, mock, cleanup := mocks.SetupMockDB(t)
+	defer cleanup()
+	repo := repositorie.NewGormLabelRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "labels" WHERE user_id = $1 AND ref IN ($2,$3)`)).
+		WithArgs(1, "abc", "bc1q").
+		WillReturnRows(sqlmock.NewRows(labelColumns).
+			AddRow(3, 1, "addr", "bc1q", "savings", "", nil, time.Now(), time.Now()))
+
+	labels, err := repo.FindByRefs(1, []string{"abc", "bc1q"})
+	assert.NoError(t, err)
+	assert.Len(t, labels, 1)
+
+	// No query is issued without references
+	labels, err = repo.FindByRefs(1, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, labels)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormLabelRepository_FindByRef(t *testing.T) {
+	db, mock, cleanup := mocks.SetupMockDB(t)
+	defer cleanup()
+	repo := repositorie.NewGormLabelRepository(db)
+
+	query := `SELECT \* FROM "labels" WHERE user_id = \$1 AND type = \$2 AND ref = \$3 ORDER BY "labels"\."id"`
+
+	mock.ExpectQuery(query).
+		WithArgs(1, "addr", "missing", sqlmock.AnyArg()).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	label, err := repo.FindByRef(1, "addr", "missing")
+	assert.NoError(t, err)
+	assert.Nil(t, label)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormLabelRepository_Delete(t *testing.T) {
+	db, mock, cleanup := mocks.SetupMockDB(t)
+	defer cleanup()
+	repo := repositorie.NewGormLabelRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "labels" WHERE id = $1 AND user_id = $2`)).
+		WithArgs(3, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	deleted, err := repo.Delete(3, 1)
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	UserModel "cry-api/app/models"
+	repositorie "cry-api/app/repositories"
+	mocks "cry-api/tests/mocks"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGormNotificationRepository_Create(t *testing.T) {
+	db, mock, cleanup := mocks.SetupMockDB(t)
+	defer cleanup()
+	repo := repositorie.NewGormNotificationRepository(db)
+
+	notification := func() *UserModel.Notification {
+		return &UserModel.Notification{
+			UserID: 7, WatchedWalletID: 3, TxID: "abc", Type: UserModel.NotificationTypeIncoming,
+			Title: "Received 0.5 BTC on Savings", Message: "details", Amount: 0.5, CreatedAt: time.Now(),
+		}
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "notifications"`) + `.*` + regexp.QuoteMeta(`ON CONFLICT DO NOTHING`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	created, err := repo.Create(notification())
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	// The wallet already has this notification
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "notifications"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+
+	created, err = repo.Create(notification())
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormNotificationRepository_FindByUserID(t *testing.T) {
+	db, mock, cleanup := mocks.SetupMockDB(t)
+	defer cleanup()
+	repo := repositorie.NewGormNotificationRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "notifications" WHERE user_id = $1 AND read_at IS NULL ORDER BY id DESC LIMIT $2`)).
+		WithArgs(7, 100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "tx_id", "type"}).
+			AddRow(2, 7, "def", UserModel.NotificationTypeConfirmed).
+			AddRow(1, 7, "abc", UserModel.NotificationTypeIncoming))
+
+	notifications, err := repo.FindByUserID(7, true, 100)
+	assert.NoError(t, err)
+	assert.Len(t, notifications, 2)
+	assert.Equal(t, "def", notifications[0].TxID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormNotificationRepository_MarkAllRead(t *testing.T) {
+	db, mock, cleanup := mocks.SetupMockDB(t)
+	defer cleanup()
+	repo := repositorie.NewGormNotificationRepository(db)
+	at := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "notifications" SET "read_at"=$1 WHERE user_id = $2 AND read_at IS NULL`)).
+		WithArgs(at, 7).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	count, err := repo.MarkAllRead(7, at)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormWalletCursorRepository_FindByWalletID(t *testing.T) {
+	db, mock, cleanup := mocks.SetupMockDB(t)
+	defer cleanup()
+	repo := repositorie.NewGormWalletCursorRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "wallet_cursors" WHERE watched_wallet_id = $1`)).
+		WithArgs(3, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "watched_wallet_id", "tip_height", "last_tx_time", "last_tx_ids"}).
+			AddRow(1, 3, 850000, 1700000000, "abc,def"))
+
+	cursor, err := repo.FindByWalletID(3)
+	assert.NoError(t, err)
+	assert.Equal(t, 850000, cursor.TipHeight)
+	assert.Equal(t, "abc,def", cursor.LastTxIDs)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "wallet_cursors" WHERE watched_wallet_id = $1`)).
+		WithArgs(4, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	cursor, err = repo.FindByWalletID(4)
+	assert.NoError(t, err)
+	assert.Nil(t, cursor)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
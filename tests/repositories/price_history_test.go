@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"regexp"
+	"testing"
+
+	UserModel "cry-api/app/models"
+	repositorie "cry-api/app/repositories"
+	mocks "cry-api/tests/mocks"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGormPriceHistoryRepository_Upsert(t *testing.T) {
+	db, mock, cleanup := mocks.SetupMockDB(t)
+	defer cleanup()
+	repo := repositorie.NewGormPriceHistoryRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "price_history"`) + `.*` +
+		regexp.QuoteMeta(`ON CONFLICT ("symbol","currency","date") DO UPDATE SET "price"="excluded"."price","updated_at"="excluded"."updated_at"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	err := repo.Upsert([]UserModel.PriceHistory{{Symbol: "BTC", Currency: "USD", Date: "2024-10-18", Price: 68000}})
+	assert.NoError(t, err)
+
+	// Nothing to store
+	assert.NoError(t, repo.Upsert(nil))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormPriceHistoryRepository_FindRange(t *testing.T) {
+	db, mock, cleanup := mocks.SetupMockDB(t)
+	defer cleanup()
+	repo := repositorie.NewGormPriceHistoryRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "price_history" WHERE symbol = $1 AND currency = $2 AND date >= $3 AND date <= $4 ORDER BY date ASC`)).
+		WithArgs("BTC", "USD", "2024-10-01", "2024-10-31").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "symbol", "currency", "date", "price"}).
+			AddRow(1, "BTC", "USD", "2024-10-01", 60000.5).
+			AddRow(2, "BTC", "USD", "2024-10-02", 61000))
+
+	points, err := repo.FindRange("BTC", "USD", "2024-10-01", "2024-10-31")
+	assert.NoError(t, err)
+	assert.Len(t, points, 2)
+	assert.Equal(t, 60000.5, points[0].Price)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormPriceHistoryRepository_LatestAndEarliest(t *testing.T) {
+	db, mock, cleanup := mocks.SetupMockDB(t)
+	defer cleanup()
+	repo := repositorie.NewGormPriceHistoryRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "price_history" WHERE symbol = $1 AND currency = $2 ORDER BY date DESC`)).
+		WithArgs("BTC", "USD", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date", "price"}).AddRow(9, "2024-10-18", 68000))
+
+	point, err := repo.Latest("BTC", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-10-18", point.Date)
+
+	// Nothing stored
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "price_history" WHERE symbol = $1 AND currency = $2 ORDER BY date ASC`)).
+		WithArgs("BTC", "USD", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	point, err = repo.Earliest("BTC", "USD")
+	assert.NoError(t, err)
+	assert.Nil(t, point)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
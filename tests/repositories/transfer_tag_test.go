@@ -0,0 +1,57 @@
+package tests
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	repositorie "cry-api/app/repositories"
+	mocks "cry-api/tests/mocks"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestGormTransferTagRepository_FindByTxID(t *testing.T) {
+	db, mock, cleanup := mocks.SetupMockDB(t)
+	defer cleanup()
+	repo := repositorie.NewGormTransferTagRepository(db)
+
+	query := `SELECT \* FROM "transfer_tags" WHERE user_id = \$1 AND tx_id = \$2 ORDER BY "transfer_tags"\."id"`
+
+	mock.ExpectQuery(query).
+		WithArgs(1, "abc", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "tx_id", "note", "created_at"}).
+			AddRow(4, 1, "abc", "cold storage", time.Now()))
+
+	tag, err := repo.FindByTxID(1, "abc")
+	assert.NoError(t, err)
+	assert.Equal(t, "cold storage", tag.Note)
+
+	mock.ExpectQuery(query).
+		WithArgs(1, "missing", sqlmock.AnyArg()).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	tag, err = repo.FindByTxID(1, "missing")
+	assert.NoError(t, err)
+	assert.Nil(t, tag)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormTransferTagRepository_Delete(t *testing.T) {
+	db, mock, cleanup := mocks.SetupMockDB(t)
+	defer cleanup()
+	repo := repositorie.NewGormTransferTagRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "transfer_tags" WHERE id = $1 AND user_id = $2`)).
+		WithArgs(4, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	deleted, err := repo.Delete(4, 1)
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
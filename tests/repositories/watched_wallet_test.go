@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	UserModel "cry-api/app/models"
+	repositorie "cry-api/app/repositories"
+	mocks "cry-api/tests/mocks"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var watchedWalletColumns = []string{"id", "user_id", "kind", "value", "label", "color", "created_at", "updated_at"}
+
+func TestGormWatchedWalletRepository_FindByUserID(t *testing.T) {
+	db, mock, cleanup := mocks.SetupMockDB(t)
+	defer cleanup()
+	repo := repositorie.NewGormWatchedWalletRepository(db)
+
+	now := time.Now()
+	rows := sqlmock.NewRows(watchedWalletColumns).
+		AddRow(1, 7, "address", "bc1qexample", "Savings", "#8b5cf6", now, now).
+		AddRow(2, 7, "xpub", "xpub6example", "Cold", "#000000", now, now)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "watched_wallets" WHERE user_id = $1 ORDER BY id ASC`)).
+		WithArgs(7).
+		WillReturnRows(rows)
+
+	wallets, err := repo.FindByUserID(7)
+	assert.NoError(t, err)
+	assert.Len(t, wallets, 2)
+	assert.Equal(t, "Savings", wallets[0].Label)
+	assert.Equal(t, "xpub", wallets[1].Kind)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormWatchedWalletRepository_FindAll(t *testing.T) {
+	db, mock, cleanup := mocks.SetupMockDB(t)
+	defer cleanup()
+	repo := repositorie.NewGormWatchedWalletRepository(db)
+
+	now := time.Now()
+	rows := sqlmock.NewRows(watchedWalletColumns).
+		AddRow(1, 7, "address", "bc1qexample", "Savings", "#8b5cf6", now, now).
+		AddRow(2, 8, "xpub", "xpub6example", "Cold", "#000000", now, now)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "watched_wallets" ORDER BY id ASC`)).
+		WillReturnRows(rows)
+
+	wallets, err := repo.FindAll()
+	assert.NoError(t, err)
+	assert.Len(t, wallets, 2)
+	assert.Equal(t, 8, wallets[1].UserID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormWatchedWalletRepository_FindByIDAndUserID(t *testing.T) {
+	db, mock, cleanup := mocks.SetupMockDB(t)
+	defer cleanup()
+	repo := repositorie.NewGormWatchedWalletRepository(db)
+
+	query := `SELECT \* FROM "watched_wallets" WHERE id = \$1 AND user_id = \$2 ORDER BY "watched_wallets"\."id"`
+
+	mock.ExpectQuery(query).
+		WithArgs(3, 7, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(watchedWalletColumns).
+			AddRow(3, 7, "address", "bc1qexample", "Savings", "#8b5cf6", time.Now(), time.Now()))
+
+	wallet, err := repo.FindByIDAndUserID(3, 7)
+	assert.NoError(t, err)
+	assert.NotNil(t, wallet)
+	assert.Equal(t, 3, wallet.ID)
+
+	// Another user's entry is reported as missing
+	mock.ExpectQuery(query).
+		WithArgs(3, 8, sqlmock.AnyArg()).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	wallet, err = repo.FindByIDAndUserID(3, 8)
+	assert.NoError(t, err)
+	assert.Nil(t, wallet)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormWatchedWalletRepository_Save(t *testing.T) {
+	db, mock, cleanup := mocks.SetupMockDB(t)
+	defer cleanup()
+	repo := repositorie.NewGormWatchedWalletRepository(db)
+
+	wallet := &UserModel.WatchedWallet{
+		UserID:    7,
+		Kind:      "address",
+		Value:     "bc1qexample",
+		Label:     "Savings",
+		Color:     "#8b5cf6",
+		CreatedAt: time.Now(),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "watched_wallets"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	err := repo.Save(wallet)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, wallet.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormWatchedWalletRepository_Delete(t *testing.T) {
+	db, mock, cleanup := mocks.SetupMockDB(t)
+	defer cleanup()
+	repo := repositorie.NewGormWatchedWalletRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "watched_wallets" WHERE id = $1 AND user_id = $2`)).
+		WithArgs(3, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.Delete(3, 7))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockAlertController mocks the alert controller methods
+type MockAlertController struct{}
+
+func (m *MockAlertController) ListRules(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "list rules called"})
+}
+
+func (m *MockAlertController) CreateRule(c *gin.Context) {
+	c.JSON(http.StatusCreated, gin.H{"message": "create rule called"})
+}
+
+func (m *MockAlertController) GetRule(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "get rule " + c.Param("id") + " called"})
+}
+
+func (m *MockAlertController) UpdateRule(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "update rule " + c.Param("id") + " called"})
+}
+
+func (m *MockAlertController) DeleteRule(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "delete rule " + c.Param("id") + " called"})
+}
+
+func (m *MockAlertController) ListTriggers(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "list triggers " + c.Param("id") + " called"})
+}
+
+// helper function to register routes with mock controller and middleware
+func registerMockAlertRoutes(rg *gin.RouterGroup, ctrl *MockAlertController) {
+	rg.Use(mockJWTMiddleware())
+
+	rg.GET("", ctrl.ListRules)
+	rg.POST("", ctrl.CreateRule)
+	rg.GET("/triggers", ctrl.ListTriggers)
+	rg.GET("/:id", ctrl.GetRule)
+	rg.PUT("/:id", ctrl.UpdateRule)
+	rg.DELETE("/:id", ctrl.DeleteRule)
+	rg.GET("/:id/triggers", ctrl.ListTriggers)
+}
+
+func TestAlertRegisterRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	rg := router.Group("/alerts")
+
+	registerMockAlertRoutes(rg, &MockAlertController{})
+
+	testCases := []struct {
+		method       string
+		endpoint     string
+		expectedCode int
+		expectedBody string
+	}{
+		{"GET", "/alerts", http.StatusOK, `{"message":"list rules called"}`},
+		{"POST", "/alerts", http.StatusCreated, `{"message":"create rule called"}`},
+		{"GET", "/alerts/triggers", http.StatusOK, `{"message":"list triggers  called"}`},
+		{"GET", "/alerts/2", http.StatusOK, `{"message":"get rule 2 called"}`},
+		{"PUT", "/alerts/2", http.StatusOK, `{"message":"update rule 2 called"}`},
+		{"DELETE", "/alerts/2", http.StatusOK, `{"message":"delete rule 2 called"}`},
+		{"GET", "/alerts/2/triggers", http.StatusOK, `{"message":"list triggers 2 called"}`},
+	}
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest(tc.method, tc.endpoint, nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, tc.expectedCode, resp.Code)
+		assert.JSONEq(t, tc.expectedBody, resp.Body.String())
+	}
+}
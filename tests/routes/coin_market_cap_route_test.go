@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockCoinMarketCapController mocks the coin market cap controller methods
+type MockCoinMarketCapController struct{}
+
+func (m *MockCoinMarketCapController) GetFearAndGreedLastest(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "latest called"})
+}
+
+func (m *MockCoinMarketCapController) GetFearAndGreedHistorical(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "historical called"})
+}
+
+func (m *MockCoinMarketCapController) GetFearAndGreed(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "range " + c.Query("interval") + " called"})
+}
+
+func (m *MockCoinMarketCapController) GetFearAndGreedAnalytics(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "analytics " + c.Query("window") + " called"})
+}
+
+func (m *MockCoinMarketCapController) GetQuotes(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "quotes called"})
+}
+
+func (m *MockCoinMarketCapController) GetOHLCV(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "ohlcv called"})
+}
+
+func (m *MockCoinMarketCapController) ConvertPrice(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "convert called"})
+}
+
+// helper function to register the public routes with mock controller
+func registerMockCoinMarketCapRoutes(rg *gin.RouterGroup, ctrl *MockCoinMarketCapController) {
+	rg.GET("/fear-and-greed-lastest", ctrl.GetFearAndGreedLastest)
+	rg.GET("/fear-and-greed-historical", ctrl.GetFearAndGreedHistorical)
+	rg.GET("/fear-and-greed", ctrl.GetFearAndGreed)
+	rg.GET("/fear-and-greed/analytics", ctrl.GetFearAndGreedAnalytics)
+	rg.GET("/quotes", ctrl.GetQuotes)
+	rg.GET("/ohlcv", ctrl.GetOHLCV)
+	rg.GET("/convert", ctrl.ConvertPrice)
+}
+
+func TestCoinMarketCapRegisterRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	rg := router.Group("/coin-market-cap")
+
+	registerMockCoinMarketCapRoutes(rg, &MockCoinMarketCapController{})
+
+	testCases := []struct {
+		method       string
+		endpoint     string
+		expectedCode int
+		expectedBody string
+	}{
+		{"GET", "/coin-market-cap/fear-and-greed-lastest", http.StatusOK, `{"message":"latest called"}`},
+		{"GET", "/coin-market-cap/fear-and-greed-historical", http.StatusOK, `{"message":"historical called"}`},
+		{"GET", "/coin-market-cap/fear-and-greed?interval=weekly", http.StatusOK, `{"message":"range weekly called"}`},
+		{"GET", "/coin-market-cap/fear-and-greed/analytics?window=14", http.StatusOK, `{"message":"analytics 14 called"}`},
+		{"GET", "/coin-market-cap/quotes?symbols=BTC", http.StatusOK, `{"message":"quotes called"}`},
+		{"GET", "/coin-market-cap/ohlcv?symbol=BTC", http.StatusOK, `{"message":"ohlcv called"}`},
+		{"GET", "/coin-market-cap/convert?amount=1&symbol=BTC", http.StatusOK, `{"message":"convert called"}`},
+	}
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest(tc.method, tc.endpoint, nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, tc.expectedCode, resp.Code)
+		assert.JSONEq(t, tc.expectedBody, resp.Body.String())
+	}
+}
@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockLabelController mocks the label controller methods
+type MockLabelController struct{}
+
+func (m *MockLabelController) ListLabels(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "list labels called"})
+}
+
+func (m *MockLabelController) SetLabel(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "set label called"})
+}
+
+func (m *MockLabelController) ExportLabels(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "export labels called"})
+}
+
+func (m *MockLabelController) ImportLabels(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "import labels called"})
+}
+
+func (m *MockLabelController) DeleteLabel(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "delete label " + c.Param("id") + " called"})
+}
+
+// helper function to register routes with mock controller and middleware
+func registerMockLabelRoutes(rg *gin.RouterGroup, ctrl *MockLabelController) {
+	rg.Use(mockJWTMiddleware())
+
+	rg.GET("", ctrl.ListLabels)
+	rg.PUT("", ctrl.SetLabel)
+	rg.GET("/export", ctrl.ExportLabels)
+	rg.POST("/import", ctrl.ImportLabels)
+	rg.DELETE("/:id", ctrl.DeleteLabel)
+}
+
+func TestLabelRegisterRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	rg := router.Group("/labels")
+
+	registerMockLabelRoutes(rg, &MockLabelController{})
+
+	testCases := []struct {
+		method       string
+		endpoint     string
+		expectedCode int
+		expectedBody string
+	}{
+		{"GET", "/labels", http.StatusOK, `{"message":"list labels called"}`},
+		{"PUT", "/labels", http.StatusOK, `{"message":"set label called"}`},
+		{"GET", "/labels/export", http.StatusOK, `{"message":"export labels called"}`},
+		{"POST", "/labels/import", http.StatusOK, `{"message":"import labels called"}`},
+		{"DELETE", "/labels/5", http.StatusOK, `{"message":"delete label 5 called"}`},
+	}
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest(tc.method, tc.endpoint, nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, tc.expectedCode, resp.Code)
+		assert.JSONEq(t, tc.expectedBody, resp.Body.String())
+	}
+}
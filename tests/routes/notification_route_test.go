@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockNotificationController mocks the notification controller methods
+type MockNotificationController struct{}
+
+func (m *MockNotificationController) ListNotifications(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "list notifications called"})
+}
+
+func (m *MockNotificationController) MarkAllRead(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "mark all read called"})
+}
+
+func (m *MockNotificationController) MarkRead(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "mark read " + c.Param("id") + " called"})
+}
+
+// helper function to register routes with mock controller and middleware
+func registerMockNotificationRoutes(rg *gin.RouterGroup, ctrl *MockNotificationController) {
+	rg.Use(mockJWTMiddleware())
+
+	rg.GET("", ctrl.ListNotifications)
+	rg.POST("/read", ctrl.MarkAllRead)
+	rg.POST("/:id/read", ctrl.MarkRead)
+}
+
+func TestNotificationRegisterRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	rg := router.Group("/notifications")
+
+	registerMockNotificationRoutes(rg, &MockNotificationController{})
+
+	testCases := []struct {
+		method       string
+		endpoint     string
+		expectedCode int
+		expectedBody string
+	}{
+		{"GET", "/notifications", http.StatusOK, `{"message":"list notifications called"}`},
+		{"POST", "/notifications/read", http.StatusOK, `{"message":"mark all read called"}`},
+		{"POST", "/notifications/4/read", http.StatusOK, `{"message":"mark read 4 called"}`},
+	}
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest(tc.method, tc.endpoint, nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, tc.expectedCode, resp.Code)
+		assert.JSONEq(t, tc.expectedBody, resp.Body.String())
+	}
+}
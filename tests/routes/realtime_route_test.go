@@ -0,0 +1,57 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockRealtimeController mocks the realtime controller methods
+type MockRealtimeController struct{}
+
+func (m *MockRealtimeController) Stream(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "stream called"})
+}
+
+func (m *MockRealtimeController) WebSocket(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "websocket called"})
+}
+
+// helper function to register routes with mock controller and middleware
+func registerMockRealtimeRoutes(rg *gin.RouterGroup, ctrl *MockRealtimeController) {
+	rg.Use(mockJWTMiddleware())
+
+	rg.GET("/sse", ctrl.Stream)
+	rg.GET("/ws", ctrl.WebSocket)
+}
+
+func TestRealtimeRegisterRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	rg := router.Group("/realtime")
+
+	registerMockRealtimeRoutes(rg, &MockRealtimeController{})
+
+	testCases := []struct {
+		method       string
+		endpoint     string
+		expectedCode int
+		expectedBody string
+	}{
+		{"GET", "/realtime/sse", http.StatusOK, `{"message":"stream called"}`},
+		{"GET", "/realtime/ws", http.StatusOK, `{"message":"websocket called"}`},
+	}
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest(tc.method, tc.endpoint, nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, tc.expectedCode, resp.Code)
+		assert.JSONEq(t, tc.expectedBody, resp.Body.String())
+	}
+}
@@ -20,6 +20,54 @@ func (m *MockWalletExplorerController) GetTransactionByXPUB(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "get transaction by xpub called"})
 }
 
+func (m *MockWalletExplorerController) ConvertExtendedKey(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "convert extended key called"})
+}
+
+func (m *MockWalletExplorerController) NormalizeDescriptor(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "normalize descriptor called"})
+}
+
+func (m *MockWalletExplorerController) DecodeTransaction(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "decode transaction called"})
+}
+
+func (m *MockWalletExplorerController) ExportAddressHistory(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "export address called"})
+}
+
+func (m *MockWalletExplorerController) ExportXPUBHistory(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "export xpub called"})
+}
+
+func (m *MockWalletExplorerController) GetMempoolStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "get mempool status called"})
+}
+
+func (m *MockWalletExplorerController) GetFeeEstimates(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "get fee estimates called"})
+}
+
+func (m *MockWalletExplorerController) GetTip(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "get tip called"})
+}
+
+func (m *MockWalletExplorerController) BroadcastTransaction(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "broadcast transaction called"})
+}
+
+func (m *MockWalletExplorerController) GetEVMTransaction(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "get evm transaction called"})
+}
+
+func (m *MockWalletExplorerController) GetEVMBalances(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "get evm balances called"})
+}
+
+func (m *MockWalletExplorerController) GetEVMTransfers(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "get evm transfers called"})
+}
+
 // mock middleware that simply calls next handler (bypass real JWT)
 func mockJWTMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -34,6 +82,18 @@ func registerMockWalletExplorerRoutes(rg *gin.RouterGroup, ctrl *MockWalletExplo
 
 	authGroup.GET("/tx", ctrl.GetTransactionInfo)
 	authGroup.GET("/xpub", ctrl.GetTransactionByXPUB)
+	authGroup.GET("/mempool", ctrl.GetMempoolStatus)
+	authGroup.GET("/fees", ctrl.GetFeeEstimates)
+	authGroup.GET("/tip", ctrl.GetTip)
+	authGroup.GET("/xpub/convert", ctrl.ConvertExtendedKey)
+	authGroup.GET("/descriptor", ctrl.NormalizeDescriptor)
+	authGroup.POST("/decode", ctrl.DecodeTransaction)
+	authGroup.POST("/broadcast", ctrl.BroadcastTransaction)
+	authGroup.GET("/export/address", ctrl.ExportAddressHistory)
+	authGroup.GET("/export/xpub", ctrl.ExportXPUBHistory)
+	authGroup.GET("/evm/:chain/tx/:hash", ctrl.GetEVMTransaction)
+	authGroup.GET("/evm/:chain/address/:address", ctrl.GetEVMBalances)
+	authGroup.GET("/evm/:chain/address/:address/transfers", ctrl.GetEVMTransfers)
 }
 
 func TestWalletExplorerRegisterRoutes(t *testing.T) {
@@ -52,6 +112,18 @@ func TestWalletExplorerRegisterRoutes(t *testing.T) {
 	}{
 		{"GET", "/wallet/tx", http.StatusOK, `{"message":"get transaction info called"}`},
 		{"GET", "/wallet/xpub", http.StatusOK, `{"message":"get transaction by xpub called"}`},
+		{"GET", "/wallet/mempool", http.StatusOK, `{"message":"get mempool status called"}`},
+		{"GET", "/wallet/fees", http.StatusOK, `{"message":"get fee estimates called"}`},
+		{"GET", "/wallet/tip", http.StatusOK, `{"message":"get tip called"}`},
+		{"GET", "/wallet/xpub/convert", http.StatusOK, `{"message":"convert extended key called"}`},
+		{"GET", "/wallet/descriptor", http.StatusOK, `{"message":"normalize descriptor called"}`},
+		{"POST", "/wallet/decode", http.StatusOK, `{"message":"decode transaction called"}`},
+		{"POST", "/wallet/broadcast", http.StatusOK, `{"message":"broadcast transaction called"}`},
+		{"GET", "/wallet/export/address", http.StatusOK, `{"message":"export address called"}`},
+		{"GET", "/wallet/export/xpub", http.StatusOK, `{"message":"export xpub called"}`},
+		{"GET", "/wallet/evm/ethereum/tx/0xabc", http.StatusOK, `{"message":"get evm transaction called"}`},
+		{"GET", "/wallet/evm/ethereum/address/0x01", http.StatusOK, `{"message":"get evm balances called"}`},
+		{"GET", "/wallet/evm/ethereum/address/0x01/transfers", http.StatusOK, `{"message":"get evm transfers called"}`},
 	}
 
 	for _, tc := range testCases {
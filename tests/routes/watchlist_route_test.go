@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockWatchlistController mocks the watchlist controller methods
+type MockWatchlistController struct{}
+
+func (m *MockWatchlistController) ListWallets(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "list wallets called"})
+}
+
+func (m *MockWatchlistController) AddWallet(c *gin.Context) {
+	c.JSON(http.StatusCreated, gin.H{"message": "add wallet called"})
+}
+
+func (m *MockWatchlistController) GetSummary(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "get summary called"})
+}
+
+func (m *MockWatchlistController) ExportHistory(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "export history called"})
+}
+
+func (m *MockWatchlistController) GetWallet(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "get wallet " + c.Param("id") + " called"})
+}
+
+func (m *MockWatchlistController) UpdateWallet(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "update wallet called"})
+}
+
+func (m *MockWatchlistController) DeleteWallet(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "delete wallet called"})
+}
+
+// helper function to register routes with mock controller and middleware
+func registerMockWatchlistRoutes(rg *gin.RouterGroup, ctrl *MockWatchlistController) {
+	rg.Use(mockJWTMiddleware())
+
+	rg.GET("", ctrl.ListWallets)
+	rg.POST("", ctrl.AddWallet)
+	rg.GET("/summary", ctrl.GetSummary)
+	rg.GET("/export", ctrl.ExportHistory)
+	rg.GET("/:id", ctrl.GetWallet)
+	rg.PUT("/:id", ctrl.UpdateWallet)
+	rg.DELETE("/:id", ctrl.DeleteWallet)
+}
+
+func TestWatchlistRegisterRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	rg := router.Group("/watchlist")
+
+	registerMockWatchlistRoutes(rg, &MockWatchlistController{})
+
+	testCases := []struct {
+		method       string
+		endpoint     string
+		expectedCode int
+		expectedBody string
+	}{
+		{"GET", "/watchlist", http.StatusOK, `{"message":"list wallets called"}`},
+		{"POST", "/watchlist", http.StatusCreated, `{"message":"add wallet called"}`},
+		{"GET", "/watchlist/summary", http.StatusOK, `{"message":"get summary called"}`},
+		{"GET", "/watchlist/export", http.StatusOK, `{"message":"export history called"}`},
+		{"GET", "/watchlist/3", http.StatusOK, `{"message":"get wallet 3 called"}`},
+		{"PUT", "/watchlist/3", http.StatusOK, `{"message":"update wallet called"}`},
+		{"DELETE", "/watchlist/3", http.StatusOK, `{"message":"delete wallet called"}`},
+	}
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest(tc.method, tc.endpoint, nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, tc.expectedCode, resp.Code)
+		assert.JSONEq(t, tc.expectedBody, resp.Body.String())
+	}
+}
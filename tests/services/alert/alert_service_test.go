@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	UserModel "cry-api/app/models"
+	repositorie "cry-api/app/repositories"
+	services "cry-api/app/services/alert"
+	AlertTypes "cry-api/app/types/alert"
+	EnvTypes "cry-api/app/types/env"
+	app_errors "cry-api/app/types/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newAlertDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	require.NoError(t, db.AutoMigrate(&UserModel.User{}, &UserModel.AlertRule{}, &UserModel.AlertTrigger{}))
+	return db
+}
+
+func newAlertConfig() *EnvTypes.EnvConfig {
+	return &EnvTypes.EnvConfig{
+		CryAppURL:    "https://app.test",
+		NoReplyEmail: "no-reply@test.com",
+		AlertConfig:  EnvTypes.AlertConfig{Interval: 60, DefaultCooldown: 60, MaxRules: 3},
+	}
+}
+
+func newAlertService(db *gorm.DB) *services.AlertService {
+	return services.NewAlertService(newAlertConfig(), repositorie.NewGormAlertRuleRepository(db), repositorie.NewGormAlertTriggerRepository(db))
+}
+
+func number(v string) *json.Number { n := json.Number(v); return &n }
+
+func TestAlertService_CreateRuleNormalizes(t *testing.T) {
+	svc := newAlertService(newAlertDB(t))
+
+	rule, err := svc.CreateRule(7, AlertTypes.ICreateAlertRuleRequest{
+		Condition: "Price_Above", Symbol: " btc ", Threshold: number("70000"), Window: "1h", Classification: "Fear",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, UserModel.AlertConditionPriceAbove, rule.Condition)
+	assert.Equal(t, "BTC", rule.Symbol)
+	assert.Equal(t, "USD", rule.Currency)
+	assert.Empty(t, rule.Window, "only percent change rules have a window")
+	assert.Empty(t, rule.Classification)
+	assert.Equal(t, UserModel.AlertModeOnce, rule.Mode)
+	assert.Equal(t, 60, rule.CooldownMinutes)
+	assert.True(t, rule.Active)
+
+	rule, err = svc.CreateRule(7, AlertTypes.ICreateAlertRuleRequest{
+		Condition: UserModel.AlertConditionFearGreedClassification, Symbol: "BTC", Classification: "extreme fear", Mode: "recurring",
+	})
+	require.NoError(t, err)
+	assert.Empty(t, rule.Symbol)
+	assert.Equal(t, "Extreme Fear", rule.Classification)
+	assert.Equal(t, UserModel.AlertModeRecurring, rule.Mode)
+
+	rule, err = svc.CreateRule(7, AlertTypes.ICreateAlertRuleRequest{
+		Condition: UserModel.AlertConditionPercentChange, Symbol: "ETH", Currency: "eur", Threshold: number("-10"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "24h", rule.Window)
+	assert.Equal(t, "EUR", rule.Currency)
+}
+
+func TestAlertService_CreateRuleValidation(t *testing.T) {
+	svc := newAlertService(newAlertDB(t))
+
+	testCases := []struct {
+		field string
+		req   AlertTypes.ICreateAlertRuleRequest
+	}{
+		{"condition", AlertTypes.ICreateAlertRuleRequest{Condition: "volume_above", Symbol: "BTC", Threshold: number("1")}},
+		{"symbol", AlertTypes.ICreateAlertRuleRequest{Condition: "price_above", Threshold: number("1")}},
+		{"currency", AlertTypes.ICreateAlertRuleRequest{Condition: "price_below", Symbol: "BTC", Currency: "EURO", Threshold: number("1")}},
+		{"threshold", AlertTypes.ICreateAlertRuleRequest{Condition: "price_above", Symbol: "BTC"}},
+		{"threshold", AlertTypes.ICreateAlertRuleRequest{Condition: "percent_change", Symbol: "BTC", Threshold: number("0")}},
+		{"window", AlertTypes.ICreateAlertRuleRequest{Condition: "percent_change", Symbol: "BTC", Threshold: number("5"), Window: "30d"}},
+		{"threshold", AlertTypes.ICreateAlertRuleRequest{Condition: "fear_greed_below", Threshold: number("100")}},
+		{"classification", AlertTypes.ICreateAlertRuleRequest{Condition: "fear_greed_classification", Classification: "Panic"}},
+		{"mode", AlertTypes.ICreateAlertRuleRequest{Condition: "fear_greed_below", Threshold: number("20"), Mode: "twice"}},
+		{"cooldown_minutes", AlertTypes.ICreateAlertRuleRequest{Condition: "fear_greed_below", Threshold: number("20"), CooldownMinutes: new(int)}},
+	}
+
+	for _, tc := range testCases {
+		_, err := svc.CreateRule(7, tc.req)
+		var validationErr *app_errors.ValidationError
+		require.ErrorAs(t, err, &validationErr, tc.field)
+		assert.Equal(t, tc.field, validationErr.Field)
+	}
+}
+
+func TestAlertService_CreateRuleLimit(t *testing.T) {
+	svc := newAlertService(newAlertDB(t))
+	req := AlertTypes.ICreateAlertRuleRequest{Condition: "fear_greed_below", Threshold: number("20")}
+
+	for range 3 {
+		_, err := svc.CreateRule(7, req)
+		require.NoError(t, err)
+	}
+	_, err := svc.CreateRule(7, req)
+	var conflictErr *app_errors.ConflictError
+	require.ErrorAs(t, err, &conflictErr)
+
+	_, err = svc.CreateRule(8, req)
+	assert.NoError(t, err, "the limit is per user")
+}
+
+func TestAlertService_UpdateAndDeleteRule(t *testing.T) {
+	db := newAlertDB(t)
+	svc := newAlertService(db)
+	rule, err := svc.CreateRule(7, AlertTypes.ICreateAlertRuleRequest{Condition: "price_below", Symbol: "BTC", Threshold: number("50000")})
+	require.NoError(t, err)
+
+	_, err = svc.UpdateRule(8, rule.ID, AlertTypes.IUpdateAlertRuleRequest{Threshold: number("1")})
+	var notFound *app_errors.NotFoundError
+	require.ErrorAs(t, err, &notFound, "rules are scoped to their owner")
+
+	paused := false
+	recurring := UserModel.AlertModeRecurring
+	updated, err := svc.UpdateRule(7, rule.ID, AlertTypes.IUpdateAlertRuleRequest{Threshold: number("45000"), Mode: &recurring, Active: &paused})
+	require.NoError(t, err)
+	assert.Equal(t, "45000", updated.Threshold)
+	assert.Equal(t, UserModel.AlertModeRecurring, updated.Mode)
+	assert.False(t, updated.Active)
+
+	_, err = svc.UpdateRule(7, rule.ID, AlertTypes.IUpdateAlertRuleRequest{Threshold: number("-1")})
+	var validationErr *app_errors.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+
+	require.NoError(t, db.Create(&UserModel.AlertTrigger{AlertRuleID: rule.ID, UserID: 7, Condition: rule.Condition, Title: "BTC is below 45000 USD", Message: "details"}).Error)
+	require.NoError(t, svc.DeleteRule(7, rule.ID))
+	err = svc.DeleteRule(7, rule.ID)
+	require.ErrorAs(t, err, &notFound)
+
+	triggers, err := svc.ListTriggers(7, 0)
+	require.NoError(t, err)
+	assert.Len(t, triggers, 1, "the history outlives the rule")
+
+	_, err = svc.ListTriggers(7, rule.ID)
+	var appErr *app_errors.NotFoundError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusNotFound, appErr.Status)
+}
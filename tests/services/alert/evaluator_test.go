@@ -0,0 +1,215 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	UserModel "cry-api/app/models"
+	repositorie "cry-api/app/repositories"
+	services "cry-api/app/services/alert"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+const alertsLink = "https://app.test/alerts"
+
+type evaluatorFixture struct {
+	db     *gorm.DB
+	user   UserModel.User
+	market *testmocks.MockCoinMarketCapService
+	email  *testmocks.MockEmailService
+	now    time.Time
+}
+
+func newEvaluatorFixture(t *testing.T) *evaluatorFixture {
+	db := newAlertDB(t)
+	user := UserModel.User{UUID: "user-uuid", Username: "satoshi", Email: "satoshi@example.com", Password: "x", IsVerified: true}
+	require.NoError(t, db.Create(&user).Error)
+
+	return &evaluatorFixture{
+		db:     db,
+		user:   user,
+		market: new(testmocks.MockCoinMarketCapService),
+		email:  new(testmocks.MockEmailService),
+		now:    time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func (f *evaluatorFixture) evaluator() *services.AlertEvaluatorService {
+	evaluator := services.NewAlertEvaluatorService(
+		newAlertConfig(),
+		repositorie.NewGormAlertRuleRepository(f.db),
+		repositorie.NewGormAlertTriggerRepository(f.db),
+		repositorie.NewGormUserRepository(f.db),
+		f.market,
+		f.email,
+	)
+	evaluator.SetClock(func() time.Time { return f.now })
+	return evaluator
+}
+
+func (f *evaluatorFixture) rule(t *testing.T, rule UserModel.AlertRule) UserModel.AlertRule {
+	rule.UserID = f.user.ID
+	rule.Active = true
+	if rule.Mode == "" {
+		rule.Mode = UserModel.AlertModeOnce
+	}
+	if rule.CooldownMinutes == 0 {
+		rule.CooldownMinutes = 60
+	}
+	require.NoError(t, f.db.Create(&rule).Error)
+	return rule
+}
+
+func (f *evaluatorFixture) quote(symbol, currency string, quote CoinMarketCap.IQuote) {
+	quote.Symbol = symbol
+	quote.Source = "coinmarketcap"
+	f.market.On("GetLatestQuotes", []string{symbol}, []int(nil), currency).Return([]CoinMarketCap.IQuote{quote}, nil).Once()
+}
+
+func (f *evaluatorFixture) index(value int, classification string) {
+	f.market.On("GetFearAndGreedLastest").Return(&CoinMarketCap.FearGreedData{
+		Data:   CoinMarketCap.FearGreedEntry{Value: value, ValueClassification: classification},
+		Source: "alternative",
+	}, nil).Once()
+}
+
+func TestAlertEvaluator_OneShotPriceRule(t *testing.T) {
+	f := newEvaluatorFixture(t)
+	rule := f.rule(t, UserModel.AlertRule{Condition: UserModel.AlertConditionPriceAbove, Symbol: "BTC", Currency: "USD", Threshold: "70000"})
+
+	f.quote("BTC", "USD", CoinMarketCap.IQuote{Price: "69000"})
+	fired, err := f.evaluator().CheckAll(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, fired)
+
+	title := "BTC is above 70000 USD"
+	f.quote("BTC", "USD", CoinMarketCap.IQuote{Price: "70123.456"})
+	f.email.On("SendAlertEmail", "satoshi@example.com", "no-reply@test.com", "satoshi", title,
+		"BTC is at 70123.46 USD, above your alert at 70000 USD.", alertsLink).Return(nil).Once()
+	fired, err = f.evaluator().CheckAll(context.Background())
+	require.NoError(t, err)
+	require.Len(t, fired, 1)
+	assert.Equal(t, title, fired[0].Title)
+	assert.Equal(t, "70123.456", fired[0].Value)
+	assert.Equal(t, "USD", fired[0].Currency)
+	assert.Equal(t, "coinmarketcap", fired[0].Source)
+	assert.NotNil(t, fired[0].EmailedAt)
+
+	var stored UserModel.AlertRule
+	require.NoError(t, f.db.First(&stored, rule.ID).Error)
+	assert.False(t, stored.Active, "a one-shot rule is paused after firing")
+	assert.Equal(t, f.now, stored.LastTriggeredAt.UTC())
+
+	// Paused rules are not checked
+	fired, err = f.evaluator().CheckAll(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, fired)
+	f.market.AssertExpectations(t)
+	f.email.AssertExpectations(t)
+}
+
+func TestAlertEvaluator_RecurringRuleWaitsForItsCooldown(t *testing.T) {
+	f := newEvaluatorFixture(t)
+	f.rule(t, UserModel.AlertRule{Condition: UserModel.AlertConditionFearGreedBelow, Threshold: "20", Mode: UserModel.AlertModeRecurring, CooldownMinutes: 30})
+	f.email.On("SendAlertEmail", mock.Anything, mock.Anything, mock.Anything, "Fear & greed index is below 20",
+		"The fear and greed index is at 12 (Extreme Fear), below your alert at 20.", alertsLink).Return(nil).Twice()
+
+	f.index(12, "Extreme Fear")
+	fired, err := f.evaluator().CheckAll(context.Background())
+	require.NoError(t, err)
+	require.Len(t, fired, 1)
+	assert.Equal(t, "alternative", fired[0].Source)
+
+	f.now = f.now.Add(29 * time.Minute)
+	f.index(12, "Extreme Fear")
+	fired, err = f.evaluator().CheckAll(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, fired)
+
+	f.now = f.now.Add(time.Minute)
+	f.index(12, "Extreme Fear")
+	fired, err = f.evaluator().CheckAll(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, fired, 1)
+
+	var count int64
+	f.db.Model(&UserModel.AlertTrigger{}).Count(&count)
+	assert.Equal(t, int64(2), count)
+	f.email.AssertExpectations(t)
+}
+
+func TestAlertEvaluator_ClassificationChange(t *testing.T) {
+	f := newEvaluatorFixture(t)
+	rule := f.rule(t, UserModel.AlertRule{Condition: UserModel.AlertConditionFearGreedClassification, Classification: "Extreme Fear", Mode: UserModel.AlertModeRecurring, CooldownMinutes: 1})
+
+	// The first check only records the classification
+	f.index(30, "Fear")
+	fired, err := f.evaluator().CheckAll(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, fired)
+
+	// A change to another classification than the awaited one is recorded without firing
+	f.index(50, "Neutral")
+	fired, err = f.evaluator().CheckAll(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, fired)
+
+	f.index(18, "Extreme Fear")
+	f.email.On("SendAlertEmail", mock.Anything, mock.Anything, mock.Anything, "Fear & greed index is now Extreme Fear",
+		"The fear and greed index moved from Neutral to Extreme Fear (18).", alertsLink).Return(nil).Once()
+	fired, err = f.evaluator().CheckAll(context.Background())
+	require.NoError(t, err)
+	require.Len(t, fired, 1)
+
+	var stored UserModel.AlertRule
+	require.NoError(t, f.db.First(&stored, rule.ID).Error)
+	assert.Equal(t, "Extreme Fear", stored.LastClassification)
+	f.email.AssertExpectations(t)
+}
+
+func TestAlertEvaluator_PercentDropAndMissingData(t *testing.T) {
+	f := newEvaluatorFixture(t)
+	f.db.Model(&f.user).Update("is_verified", false)
+	f.rule(t, UserModel.AlertRule{Condition: UserModel.AlertConditionPercentChange, Symbol: "ETH", Currency: "EUR", Threshold: "-10", Window: "24h"})
+	f.rule(t, UserModel.AlertRule{Condition: UserModel.AlertConditionPriceBelow, Symbol: "BTC", Currency: "USD", Threshold: "50000"})
+
+	f.quote("ETH", "EUR", CoinMarketCap.IQuote{Price: "2000", PercentChange24h: "-12.345"})
+	f.market.On("GetLatestQuotes", []string{"BTC"}, []int(nil), "USD").Return(nil, errors.New("quota exceeded")).Once()
+
+	fired, err := f.evaluator().CheckAll(context.Background())
+	require.NoError(t, err)
+	require.Len(t, fired, 1, "the BTC rule waits for its quote")
+	assert.Equal(t, "ETH fell 12.35% in 24h", fired[0].Title)
+	assert.Equal(t, "ETH changed by -12.35% over the last 24h and is at 2000 EUR, past your alert at -10%.", fired[0].Message)
+	assert.Equal(t, "-12.345", fired[0].Value)
+	assert.Empty(t, fired[0].Currency, "a percent change is not a price")
+	assert.Nil(t, fired[0].EmailedAt, "unverified users get no email")
+	f.email.AssertNotCalled(t, "SendAlertEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAlertEvaluator_PublishesTriggers(t *testing.T) {
+	f := newEvaluatorFixture(t)
+	f.db.Model(&f.user).Update("is_verified", false)
+	rule := f.rule(t, UserModel.AlertRule{Condition: UserModel.AlertConditionFearGreedAbove, Threshold: "80"})
+	publisher := new(testmocks.MockPublisher)
+	publisher.On("PublishToUser", f.user.ID, "alerts", mock.MatchedBy(func(trigger UserModel.AlertTrigger) bool {
+		return trigger.AlertRuleID == rule.ID && trigger.Title == "Fear & greed index is above 80"
+	})).Once()
+
+	evaluator := f.evaluator()
+	evaluator.SetPublisher(publisher)
+	f.index(85, "Extreme Greed")
+	fired, err := evaluator.CheckAll(context.Background())
+
+	require.NoError(t, err)
+	assert.Len(t, fired, 1)
+	publisher.AssertExpectations(t)
+}
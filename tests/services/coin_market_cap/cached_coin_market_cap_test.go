@@ -0,0 +1,132 @@
+package tests
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"cry-api/app/cache"
+	services "cry-api/app/services/coin_market_cap"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newCachedCoinMarketCapService returns the service, its upstream, a function moving the clock
+// forward and one waiting for the background refreshes of stale values
+func newCachedCoinMarketCapService() (*services.CachedCoinMarketCapService, *testmocks.MockCoinMarketCapService, func(time.Duration), func()) {
+	upstream := new(testmocks.MockCoinMarketCapService)
+	now := time.Now()
+	c := cache.New(cache.NewMemoryStore(100))
+	c.SetClock(func() time.Time { return now })
+	return services.NewCachedCoinMarketCapService(upstream, c), upstream, func(d time.Duration) { now = now.Add(d) }, c.Wait
+}
+
+func TestCachedCoinMarketCapService_FearAndGreedLatest(t *testing.T) {
+	svc, upstream, advance, wait := newCachedCoinMarketCapService()
+
+	upstream.On("GetFearAndGreedLastest").Return(&CoinMarketCap.FearGreedData{}, nil).Twice()
+
+	for i := 0; i < 3; i++ {
+		_, err := svc.GetFearAndGreedLastest(context.Background())
+		assert.NoError(t, err)
+	}
+	upstream.AssertNumberOfCalls(t, "GetFearAndGreedLastest", 1)
+
+	advance(2 * time.Hour)
+	_, err := svc.GetFearAndGreedLastest(context.Background())
+	assert.NoError(t, err)
+	wait()
+	upstream.AssertNumberOfCalls(t, "GetFearAndGreedLastest", 2)
+}
+
+func TestCachedCoinMarketCapService_HistoricalPrices(t *testing.T) {
+	svc, upstream, advance, wait := newCachedCoinMarketCapService()
+
+	today := time.Now().UTC()
+	lastYear := today.AddDate(-1, 0, 0)
+	points := []CoinMarketCap.IPricePoint{{Date: "2024-01-01", Price: big.NewRat(42000, 1)}}
+
+	upstream.On("GetHistoricalPrices", "BTC", "USD", lastYear.AddDate(0, 0, -30), lastYear).Return(points, nil).Once()
+	upstream.On("GetHistoricalPrices", "BTC", "USD", today.AddDate(0, 0, -30), today).Return(points, nil).Twice()
+
+	for i := 0; i < 2; i++ {
+		closed, err := svc.GetHistoricalPrices(context.Background(), "BTC", "USD", lastYear.AddDate(0, 0, -30), lastYear)
+		assert.NoError(t, err)
+		assert.Equal(t, points, closed)
+
+		_, err = svc.GetHistoricalPrices(context.Background(), "BTC", "USD", today.AddDate(0, 0, -30), today)
+		assert.NoError(t, err)
+
+		wait()
+		// Ranges ending today expire within hours, closed ranges are kept
+		advance(2 * time.Hour)
+	}
+	upstream.AssertExpectations(t)
+}
+
+func TestCachedCoinMarketCapService_LatestQuotes(t *testing.T) {
+	svc, upstream, advance, wait := newCachedCoinMarketCapService()
+
+	quotes := []CoinMarketCap.IQuote{{ID: 1, Symbol: "BTC", Currency: "EUR", Price: "61234.5"}}
+	upstream.On("GetLatestQuotes", []string{"BTC", "ETH"}, []int(nil), "EUR").Return(quotes, nil).Twice()
+
+	// Symbols are normalized before the cache lookup
+	for _, symbols := range [][]string{{"BTC", "ETH"}, {"btc", "eth"}, {"BTC", "eth", "BTC"}} {
+		result, err := svc.GetLatestQuotes(context.Background(), symbols, nil, "eur")
+		assert.NoError(t, err)
+		assert.Equal(t, quotes, result)
+	}
+	upstream.AssertNumberOfCalls(t, "GetLatestQuotes", 1)
+
+	advance(2 * time.Minute)
+	_, err := svc.GetLatestQuotes(context.Background(), []string{"BTC", "ETH"}, nil, "EUR")
+	assert.NoError(t, err)
+	wait()
+	upstream.AssertNumberOfCalls(t, "GetLatestQuotes", 2)
+}
+
+func TestCachedCoinMarketCapService_ConvertPrice(t *testing.T) {
+	svc, upstream, _, _ := newCachedCoinMarketCapService()
+
+	upstream.On("ConvertPrice", big.NewRat(1, 1), "BTC", "EUR").
+		Return(&CoinMarketCap.IConversion{Symbol: "BTC", Amount: "1", Currency: "EUR", Rate: "60000.01"}, nil).Once()
+
+	quarter, err := svc.ConvertPrice(context.Background(), big.NewRat(1, 4), "btc", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.25", quarter.Amount)
+	assert.Equal(t, "60000.01", quarter.Rate)
+	assert.Equal(t, "15000.00", quarter.Value.Value)
+
+	// Every amount of the pair shares the cached rate
+	double, err := svc.ConvertPrice(context.Background(), big.NewRat(2, 1), "BTC", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, "120000.02", double.Value.Value)
+	upstream.AssertExpectations(t)
+}
+
+func TestCachedCoinMarketCapService_OHLCV(t *testing.T) {
+	svc, upstream, advance, wait := newCachedCoinMarketCapService()
+
+	closedFrom := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	closedTo := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	openTo := time.Now().UTC().Truncate(time.Hour)
+	openFrom := openTo.Add(-24 * time.Hour)
+
+	upstream.On("GetOHLCV", "BTC", "USD", "daily", closedFrom, closedTo).Return(&CoinMarketCap.IOHLCV{Symbol: "BTC"}, nil).Once()
+	upstream.On("GetOHLCV", "BTC", "USD", "hourly", openFrom, openTo).Return(&CoinMarketCap.IOHLCV{Symbol: "BTC"}, nil).Twice()
+
+	for i := 0; i < 2; i++ {
+		_, err := svc.GetOHLCV(context.Background(), "BTC", "USD", "daily", closedFrom, closedTo)
+		assert.NoError(t, err)
+		_, err = svc.GetOHLCV(context.Background(), "BTC", "USD", "hourly", openFrom, openTo)
+		assert.NoError(t, err)
+
+		wait()
+		// Ranges reaching the current period expire within minutes, closed ranges are kept
+		advance(10 * time.Minute)
+	}
+	upstream.AssertExpectations(t)
+}
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"cry-api/app/money"
+	services "cry-api/app/services/coin_market_cap"
+	"cry-api/tests/cassette"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newCassetteService returns a CoinMarketCapService pointed at the real API, replaying the
+// responses of the named cassette. Recording uses the key from COIN_MARKET_CAP_API_KEY.
+func newCassetteService(t *testing.T, name string) *services.CoinMarketCapService {
+	rec := cassette.New(t, name)
+
+	apiKey := "test-api-key"
+	if rec.Recording() {
+		apiKey = os.Getenv("COIN_MARKET_CAP_API_KEY")
+	}
+	cfg := makeTestEnvConfig("https://pro-api.coinmarketcap.com", apiKey)
+	return services.NewCoinMarketCapServiceService(cfg, rec.Client())
+}
+
+var (
+	cassetteFrom = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cassetteTo   = time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+)
+
+func TestCassette_GetFearAndGreedLastest(t *testing.T) {
+	svc := newCassetteService(t, "cmc_success")
+
+	data, err := svc.GetFearAndGreedLastest(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 73, data.Data.Value)
+	assert.Equal(t, "Greed", data.Data.ValueClassification)
+	assert.Equal(t, time.Date(2024, 10, 19, 10, 2, 51, 463000000, time.UTC), data.Data.UpdateTime)
+}
+
+func TestCassette_GetFearAndGreedHistorical(t *testing.T) {
+	svc := newCassetteService(t, "cmc_success")
+
+	data, err := svc.GetFearAndGreedHistorical(context.Background(), 1, 3)
+	assert.NoError(t, err)
+	assert.Len(t, data.Data, 3)
+	assert.Equal(t, "1729296000", data.Data[0].Timestamp)
+	assert.Equal(t, "Neutral", data.Data[2].ValueClassification)
+}
+
+func TestCassette_GetHistoricalPrices(t *testing.T) {
+	svc := newCassetteService(t, "cmc_success")
+
+	points, err := svc.GetHistoricalPrices(context.Background(), "btc", "usd", cassetteFrom, cassetteTo)
+	assert.NoError(t, err)
+	assert.Len(t, points, 3)
+	prices := map[string]string{}
+	for _, p := range points {
+		prices[p.Date] = money.FormatDecimal(p.Price)
+	}
+	assert.Equal(t, map[string]string{
+		"2024-01-01": "44167.33",
+		"2024-01-02": "44957.97",
+		"2024-01-03": "42848.18",
+	}, prices)
+}
+
+func TestCassette_ErrorPayloads(t *testing.T) {
+	svc := newCassetteService(t, "cmc_errors")
+
+	// The v3 endpoints send error_code as a string
+	_, err := svc.GetFearAndGreedLastest(context.Background())
+	assert.EqualError(t, err, "API request failed with status 401: This API Key is invalid. (error code 1001)")
+
+	_, err = svc.GetFearAndGreedHistorical(context.Background(), 1, 3)
+	assert.ErrorContains(t, err, "API request failed with status 429: You've exceeded your API Key's HTTP request rate limit.")
+	assert.ErrorContains(t, err, "(error code 1008)")
+
+	// The v2 endpoints send it as a number
+	_, err = svc.GetHistoricalPrices(context.Background(), "BTC", "USD", cassetteFrom, cassetteTo)
+	assert.EqualError(t, err, "API request failed with status 403: Your API Key subscription plan doesn't support this endpoint. (error code 1006)")
+}
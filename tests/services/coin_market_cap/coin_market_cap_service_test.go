@@ -1,12 +1,14 @@
 package tests
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"cry-api/app/money"
 	services "cry-api/app/services/coin_market_cap"
 	CoinMarketCap "cry-api/app/types/coin_market_cap"
 	EnvTypes "cry-api/app/types/env"
@@ -46,9 +48,9 @@ func TestGetFearAndGreedLastest_Success(t *testing.T) {
 	defer server.Close()
 
 	cfg := makeTestEnvConfig(server.URL, "test-api-key")
-	svc := services.NewCoinMarketCapServiceService(cfg)
+	svc := services.NewCoinMarketCapServiceService(cfg, http.DefaultClient)
 
-	data, err := svc.GetFearAndGreedLastest()
+	data, err := svc.GetFearAndGreedLastest(context.Background())
 	assert.NoError(t, err)
 	assert.NotNil(t, data)
 	assert.Equal(t, 70, data.Data.Value)
@@ -68,9 +70,9 @@ func TestGetFearAndGreedLastest_Non200Status(t *testing.T) {
 	defer server.Close()
 
 	cfg := makeTestEnvConfig(server.URL, "test-api-key")
-	svc := services.NewCoinMarketCapServiceService(cfg)
+	svc := services.NewCoinMarketCapServiceService(cfg, http.DefaultClient)
 
-	data, err := svc.GetFearAndGreedLastest()
+	data, err := svc.GetFearAndGreedLastest(context.Background())
 	assert.Error(t, err)
 	assert.Nil(t, data)
 	assert.Contains(t, err.Error(), "API request failed with status 400")
@@ -86,9 +88,9 @@ func TestGetFearAndGreedLastest_InvalidJSON(t *testing.T) {
 	defer server.Close()
 
 	cfg := makeTestEnvConfig(server.URL, "test-api-key")
-	svc := services.NewCoinMarketCapServiceService(cfg)
+	svc := services.NewCoinMarketCapServiceService(cfg, http.DefaultClient)
 
-	data, err := svc.GetFearAndGreedLastest()
+	data, err := svc.GetFearAndGreedLastest(context.Background())
 	assert.Error(t, err)
 	assert.Nil(t, data)
 	assert.Contains(t, err.Error(), "failed to decode response body")
@@ -118,9 +120,9 @@ func TestGetFearAndGreedHistorical_Success(t *testing.T) {
 	defer server.Close()
 
 	cfg := makeTestEnvConfig(server.URL, "test-api-key")
-	svc := services.NewCoinMarketCapServiceService(cfg)
+	svc := services.NewCoinMarketCapServiceService(cfg, http.DefaultClient)
 
-	data, err := svc.GetFearAndGreedHistorical(1, 50)
+	data, err := svc.GetFearAndGreedHistorical(context.Background(), 1, 50)
 	assert.NoError(t, err)
 	assert.NotNil(t, data)
 	assert.Len(t, data.Data, 1)
@@ -139,9 +141,9 @@ func TestGetFearAndGreedHistorical_Non200Status(t *testing.T) {
 	defer server.Close()
 
 	cfg := makeTestEnvConfig(server.URL, "test-api-key")
-	svc := services.NewCoinMarketCapServiceService(cfg)
+	svc := services.NewCoinMarketCapServiceService(cfg, http.DefaultClient)
 
-	data, err := svc.GetFearAndGreedHistorical(1, 50)
+	data, err := svc.GetFearAndGreedHistorical(context.Background(), 1, 50)
 	assert.Error(t, err)
 	assert.Nil(t, data)
 	assert.Contains(t, err.Error(), "API request failed with status 500")
@@ -157,10 +159,55 @@ func TestGetFearAndGreedHistorical_InvalidJSON(t *testing.T) {
 	defer server.Close()
 
 	cfg := makeTestEnvConfig(server.URL, "test-api-key")
-	svc := services.NewCoinMarketCapServiceService(cfg)
+	svc := services.NewCoinMarketCapServiceService(cfg, http.DefaultClient)
 
-	data, err := svc.GetFearAndGreedHistorical(1, 50)
+	data, err := svc.GetFearAndGreedHistorical(context.Background(), 1, 50)
 	assert.Error(t, err)
 	assert.Nil(t, data)
 	assert.Contains(t, err.Error(), "failed to decode response body")
 }
+
+func TestGetHistoricalPrices_Success(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/cryptocurrency/quotes/historical", r.URL.Path)
+		assert.Equal(t, "BTC", r.URL.Query().Get("symbol"))
+		assert.Equal(t, "EUR", r.URL.Query().Get("convert"))
+		assert.Equal(t, "daily", r.URL.Query().Get("interval"))
+		assert.Equal(t, "2024-01-01", r.URL.Query().Get("time_start"))
+		assert.Equal(t, "test-api-key", r.Header.Get("X-CMC_PRO_API_KEY"))
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"data":{"BTC":[{"id":1,"symbol":"BTC","quotes":[
+			{"timestamp":"2024-01-02T23:59:59.999Z","quote":{"EUR":{"price":41000.5,"timestamp":"2024-01-02T23:59:59.999Z"}}},
+			{"timestamp":"2024-01-01T23:59:59.999Z","quote":{"EUR":{"price":40000,"timestamp":"2024-01-01T23:59:59.999Z"}}}
+		]}]}}`))
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	svc := services.NewCoinMarketCapServiceService(makeTestEnvConfig(server.URL, "test-api-key"), http.DefaultClient)
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	points, err := svc.GetHistoricalPrices(context.Background(), "btc", "eur", from, from.AddDate(0, 0, 1))
+	assert.NoError(t, err)
+	assert.Len(t, points, 2)
+	assert.Equal(t, "2024-01-01", points[0].Date)
+	assert.Equal(t, "40000", money.FormatDecimal(points[0].Price))
+	assert.Equal(t, "2024-01-02", points[1].Date)
+	assert.Equal(t, "41000.5", money.FormatDecimal(points[1].Price))
+}
+
+func TestGetHistoricalPrices_UnknownSymbol(t *testing.T) {
+	handler := func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	svc := services.NewCoinMarketCapServiceService(makeTestEnvConfig(server.URL, "test-api-key"), http.DefaultClient)
+
+	points, err := svc.GetHistoricalPrices(context.Background(), "NOPE", "USD", time.Now(), time.Now())
+	assert.Error(t, err)
+	assert.Nil(t, points)
+}
@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	UserModel "cry-api/app/models"
+	repositorie "cry-api/app/repositories"
+	services "cry-api/app/services/coin_market_cap"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+	app_errors "cry-api/app/types/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func seedPrices(t *testing.T, db *gorm.DB, prices map[string]float64) {
+	for date, price := range prices {
+		require.NoError(t, db.Create(&UserModel.PriceHistory{Symbol: "BTC", Currency: "USD", Date: date, Price: price}).Error)
+	}
+}
+
+func newAnalyticsService(db *gorm.DB) *services.FearGreedAnalyticsService {
+	return services.NewFearGreedAnalyticsService(repositorie.NewGormFearGreedRepository(db), repositorie.NewGormPriceHistoryRepository(db))
+}
+
+func requireFloat(t *testing.T, expected float64, actual *float64) {
+	t.Helper()
+	require.NotNil(t, actual)
+	assert.InDelta(t, expected, *actual, 0.0001)
+}
+
+func TestFearGreedAnalyticsService_GetAnalytics(t *testing.T) {
+	db := newPriceHistoryDB(t)
+	seedFearGreed(t, db, map[string]int{"2023-12-31": 50, "2024-01-01": 10, "2024-01-02": 20, "2024-01-03": 50, "2024-01-04": 80, "2024-01-05": 90})
+	// No price on 2024-01-03; the prices after the range give the forward returns
+	seedPrices(t, db, map[string]float64{
+		"2023-12-31": 100, "2024-01-01": 110, "2024-01-02": 99, "2024-01-04": 120, "2024-01-05": 132,
+		"2024-01-06": 118.8, "2024-01-08": 121, "2024-01-11": 108,
+	})
+
+	result, err := newAnalyticsService(db).GetAnalytics("2024-01-01", "2024-01-05", "7")
+	require.NoError(t, err)
+	assert.Equal(t, "BTC", result.Symbol)
+	assert.Equal(t, "USD", result.Currency)
+	assert.Equal(t, 7, result.Window)
+
+	// Joined series
+	require.Len(t, result.Series, 5)
+	assert.Equal(t, "2024-01-01", result.Series[0].Date)
+	assert.Equal(t, "Extreme Fear", result.Series[0].Classification)
+	requireFloat(t, 110, result.Series[0].Price)
+	requireFloat(t, 10, result.Series[0].Return)
+	requireFloat(t, -10, result.Series[1].Return)
+	assert.Nil(t, result.Series[2].Price)
+	requireFloat(t, 120, result.Series[3].Price)
+	assert.Nil(t, result.Series[3].Return, "the day before has no price")
+	assert.Equal(t, 1, result.MissingPrices)
+
+	// Forward returns
+	require.Len(t, result.ForwardReturns, 2)
+	fear := result.ForwardReturns[0]
+	assert.Equal(t, "Extreme Fear", fear.Classification)
+	assert.Equal(t, 2, fear.Days)
+	require.Len(t, fear.Horizons, len(services.ForwardHorizons))
+	assert.Equal(t, 1, fear.Horizons[0].Days)
+	assert.Equal(t, 1, fear.Horizons[0].Count)
+	requireFloat(t, -10, fear.Horizons[0].Avg)
+	requireFloat(t, 0, fear.Horizons[0].PositiveShare)
+	assert.Equal(t, 7, fear.Horizons[1].Days)
+	requireFloat(t, 10, fear.Horizons[1].Median)
+	requireFloat(t, 100, fear.Horizons[1].PositiveShare)
+	assert.Equal(t, CoinMarketCap.IForwardReturn{Days: 30}, fear.Horizons[2])
+
+	greed := result.ForwardReturns[1]
+	assert.Equal(t, "Extreme Greed", greed.Classification)
+	assert.Equal(t, 2, greed.Horizons[0].Count)
+	requireFloat(t, 0, greed.Horizons[0].Avg)
+	requireFloat(t, 50, greed.Horizons[0].PositiveShare)
+	requireFloat(t, -10, greed.Horizons[1].Avg)
+
+	// Correlation between the index and the daily return, over the 3 days that have one
+	requireFloat(t, 0.3974, result.Correlation)
+	require.Len(t, result.RollingCorrelation, 5)
+	assert.Nil(t, result.RollingCorrelation[3].Correlation, "a 7 day window needs 3 returns")
+	assert.Equal(t, "2024-01-05", result.RollingCorrelation[4].Date)
+	requireFloat(t, 0.3974, result.RollingCorrelation[4].Correlation)
+
+	// Time in each classification
+	assert.Equal(t, []CoinMarketCap.IClassificationTime{
+		{Classification: "Extreme Fear", Days: 2, Share: 40, LongestStreak: 2},
+		{Classification: "Fear"},
+		{Classification: "Neutral", Days: 1, Share: 20, LongestStreak: 1},
+		{Classification: "Greed"},
+		{Classification: "Extreme Greed", Days: 2, Share: 40, LongestStreak: 2},
+	}, result.TimeInClassification)
+}
+
+func TestFearGreedAnalyticsService_StreaksBreakOnMissingDays(t *testing.T) {
+	db := newPriceHistoryDB(t)
+	seedFearGreed(t, db, map[string]int{"2024-01-01": 10, "2024-01-02": 10, "2024-01-04": 10, "2024-01-05": 10, "2024-01-06": 10})
+
+	result, err := newAnalyticsService(db).GetAnalytics("2024-01-01", "2024-01-31", "")
+	require.NoError(t, err)
+	assert.Equal(t, services.DefaultCorrelationWindow, result.Window)
+	assert.Equal(t, 5, result.MissingPrices)
+	assert.Nil(t, result.Correlation)
+	assert.Equal(t, CoinMarketCap.IClassificationTime{Classification: "Extreme Fear", Days: 5, Share: 100, LongestStreak: 3}, result.TimeInClassification[0])
+}
+
+func TestFearGreedAnalyticsService_DefaultsAndEmpty(t *testing.T) {
+	svc := newAnalyticsService(newPriceHistoryDB(t))
+	svc.SetClock(func() time.Time { return time.Date(2024, 10, 18, 15, 4, 5, 0, time.UTC) })
+
+	result, err := svc.GetAnalytics("", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "2023-10-19", result.From)
+	assert.Equal(t, "2024-10-18", result.To)
+	assert.Empty(t, result.Series)
+	assert.Empty(t, result.RollingCorrelation)
+	assert.Nil(t, result.Correlation)
+	assert.Len(t, result.TimeInClassification, len(services.Classifications))
+}
+
+func TestFearGreedAnalyticsService_Validation(t *testing.T) {
+	svc := newAnalyticsService(newPriceHistoryDB(t))
+
+	for _, tc := range []struct{ from, to, window string }{
+		{"2024-13-01", "", ""},
+		{"2024-10-02", "2024-10-01", ""},
+		{"", "", "6"},
+		{"", "", "366"},
+		{"", "", "month"},
+	} {
+		_, err := svc.GetAnalytics(tc.from, tc.to, tc.window)
+		var validationErr *app_errors.ValidationError
+		assert.ErrorAs(t, err, &validationErr, "%+v", tc)
+	}
+}
@@ -0,0 +1,293 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	UserModel "cry-api/app/models"
+	repositorie "cry-api/app/repositories"
+	services "cry-api/app/services/coin_market_cap"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+	EnvTypes "cry-api/app/types/env"
+	app_errors "cry-api/app/types/errors"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// historyStub serves a fear and greed history newest first, paged like CoinMarketCap
+type historyStub struct {
+	*testmocks.MockCoinMarketCapService
+	days   []CoinMarketCap.FearGreedDataPoint
+	source string
+	starts []int
+	fail   map[int]bool
+}
+
+func (s *historyStub) GetFearAndGreedHistorical(_ context.Context, start, limit int) (*CoinMarketCap.FearGreedHistorical, error) {
+	s.starts = append(s.starts, start)
+	if s.fail[start] {
+		delete(s.fail, start)
+		return nil, errors.New("upstream unavailable")
+	}
+	from := min(start-1, len(s.days))
+	to := min(from+limit, len(s.days))
+	return &CoinMarketCap.FearGreedHistorical{Data: s.days[from:to], Source: s.source}, nil
+}
+
+// newHistory returns n days ending on 2024-10-18, newest first, valued by their offset
+func newHistory(n int) []CoinMarketCap.FearGreedDataPoint {
+	newest := time.Date(2024, 10, 18, 0, 0, 0, 0, time.UTC)
+	days := make([]CoinMarketCap.FearGreedDataPoint, n)
+	for i := range days {
+		days[i] = CoinMarketCap.FearGreedDataPoint{
+			Timestamp:           strconv.FormatInt(newest.AddDate(0, 0, -i).Unix(), 10),
+			Value:               i % 100,
+			ValueClassification: services.Classify(i % 100),
+		}
+	}
+	return days
+}
+
+func newFearGreedDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	require.NoError(t, db.AutoMigrate(&UserModel.FearGreedIndex{}))
+	return db
+}
+
+func newFearGreedService(db *gorm.DB, stub *historyStub) *services.FearGreedService {
+	cfg := &EnvTypes.EnvConfig{CoinMarketCapConfig: EnvTypes.CoinMarketCapConfig{FearGreedSyncInterval: 60}}
+	return services.NewFearGreedService(cfg, stub, repositorie.NewGormFearGreedRepository(db))
+}
+
+func TestFearGreedService_SyncBackfillsThenPullsNewDays(t *testing.T) {
+	db := newFearGreedDB(t)
+	stub := &historyStub{days: newHistory(1200)}
+	svc := newFearGreedService(db, stub)
+
+	stored, err := svc.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1200, stored)
+	assert.Equal(t, []int{1, 501, 1001}, stub.starts)
+
+	var count int64
+	db.Model(&UserModel.FearGreedIndex{}).Count(&count)
+	assert.Equal(t, int64(1200), count)
+
+	// Once backfilled, a sync only pulls the first page again
+	stub.starts = nil
+	stub.days = append(newHistory(0), stub.days...)
+	stored, err = svc.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored, "the latest day is refreshed")
+	assert.Equal(t, []int{1}, stub.starts)
+
+	// Two new days are published and today's value changes
+	latest := time.Date(2024, 10, 18, 0, 0, 0, 0, time.UTC)
+	stub.days[0].Value = 80
+	stub.days = append([]CoinMarketCap.FearGreedDataPoint{
+		{Timestamp: strconv.FormatInt(latest.AddDate(0, 0, 2).Unix(), 10), Value: 61, ValueClassification: "Greed"},
+		{Timestamp: strconv.FormatInt(latest.AddDate(0, 0, 1).Unix(), 10), Value: 58, ValueClassification: "Greed"},
+	}, stub.days...)
+	stub.starts = nil
+	stored, err = svc.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, stored)
+	assert.Equal(t, []int{1}, stub.starts)
+
+	var day UserModel.FearGreedIndex
+	require.NoError(t, db.Where("date = ?", "2024-10-18").First(&day).Error)
+	assert.Equal(t, 80, day.Value)
+	var added UserModel.FearGreedIndex
+	require.NoError(t, db.Where("date = ?", "2024-10-20").First(&added).Error)
+	assert.Equal(t, 61, added.Value)
+}
+
+func TestFearGreedService_SyncRecordsTheSource(t *testing.T) {
+	db := newFearGreedDB(t)
+	stub := &historyStub{days: newHistory(3), source: "alternative"}
+	svc := newFearGreedService(db, stub)
+
+	_, err := svc.Sync(context.Background())
+	require.NoError(t, err)
+
+	var day UserModel.FearGreedIndex
+	require.NoError(t, db.Where("date = ?", "2024-10-18").First(&day).Error)
+	assert.Equal(t, "alternative", day.Source)
+
+	result, err := svc.GetRange("2024-10-16", "2024-10-18", CoinMarketCap.IntervalWeekly)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alternative"}, result.Sources)
+}
+
+func TestFearGreedService_SyncResumesAnInterruptedBackfill(t *testing.T) {
+	db := newFearGreedDB(t)
+	stub := &historyStub{days: newHistory(1200), fail: map[int]bool{1001: true}}
+
+	stored, err := newFearGreedService(db, stub).Sync(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 1000, stored)
+
+	// After a restart the backfill resumes after the oldest stored day
+	stub.starts = nil
+	stored, err = newFearGreedService(db, stub).Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 201, stored)
+	assert.Equal(t, []int{1, 1001}, stub.starts)
+
+	var count int64
+	db.Model(&UserModel.FearGreedIndex{}).Count(&count)
+	assert.Equal(t, int64(1200), count)
+}
+
+func TestFearGreedService_SyncKeepsNewDaysUntilEveryPageIsFetched(t *testing.T) {
+	db := newFearGreedDB(t)
+	stub := &historyStub{days: newHistory(1200)}
+	_, err := newFearGreedService(db, stub).Sync(context.Background())
+	require.NoError(t, err)
+
+	// 600 days are published while the sync is down; the second page of them fails
+	latest := time.Date(2024, 10, 18, 0, 0, 0, 0, time.UTC)
+	var added []CoinMarketCap.FearGreedDataPoint
+	for i := 600; i >= 1; i-- {
+		added = append(added, CoinMarketCap.FearGreedDataPoint{Timestamp: strconv.FormatInt(latest.AddDate(0, 0, i).Unix(), 10), Value: 50})
+	}
+	stub.days = append(added, stub.days...)
+	stub.fail = map[int]bool{501: true}
+	stub.starts = nil
+
+	stored, err := newFearGreedService(db, stub).Sync(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 0, stored)
+
+	var count int64
+	db.Model(&UserModel.FearGreedIndex{}).Count(&count)
+	assert.Equal(t, int64(1200), count, "no new day is stored ahead of the missing page")
+
+	// The next sync fetches the new days again and the stored history stays gapless
+	stub.starts = nil
+	stored, err = newFearGreedService(db, stub).Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 601, stored)
+	assert.Equal(t, []int{1, 501, 1801}, stub.starts)
+
+	db.Model(&UserModel.FearGreedIndex{}).Count(&count)
+	assert.Equal(t, int64(1800), count)
+}
+
+func TestFearGreedService_SyncSkipsUnreadableTimestamps(t *testing.T) {
+	db := newFearGreedDB(t)
+	stub := &historyStub{days: []CoinMarketCap.FearGreedDataPoint{
+		{Timestamp: "2024-10-18T00:00:00Z", Value: 40, ValueClassification: "Fear"},
+		{Timestamp: "yesterday", Value: 41, ValueClassification: "Fear"},
+	}}
+
+	stored, err := newFearGreedService(db, stub).Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored)
+}
+
+func seedFearGreed(t *testing.T, db *gorm.DB, values map[string]int) {
+	for date, value := range values {
+		require.NoError(t, db.Create(&UserModel.FearGreedIndex{Date: date, Value: value, Classification: services.Classify(value)}).Error)
+	}
+}
+
+func TestFearGreedService_GetRangeDaily(t *testing.T) {
+	db := newFearGreedDB(t)
+	seedFearGreed(t, db, map[string]int{"2024-09-30": 10, "2024-10-01": 20, "2024-10-02": 60, "2024-10-03": 30, "2024-10-04": 90})
+	svc := newFearGreedService(db, &historyStub{})
+
+	result, err := svc.GetRange("2024-10-01", "2024-10-03", "")
+	require.NoError(t, err)
+	assert.Equal(t, CoinMarketCap.IntervalDaily, result.Interval)
+	require.Len(t, result.Points, 3)
+	assert.Equal(t, "2024-10-01", result.Points[0].Date)
+	assert.Equal(t, "Fear", result.Points[2].Classification)
+	assert.Equal(t, 30, result.Points[2].Min)
+	assert.Equal(t, &CoinMarketCap.IFearGreedStats{Min: 20, Max: 60, Avg: 36.67, Median: 30, Count: 3}, result.Stats)
+}
+
+func TestFearGreedService_GetRangeAggregates(t *testing.T) {
+	db := newFearGreedDB(t)
+	// 2024-09-30 is a Monday
+	seedFearGreed(t, db, map[string]int{"2024-09-29": 10, "2024-09-30": 20, "2024-10-01": 60, "2024-10-06": 30, "2024-10-07": 90})
+	svc := newFearGreedService(db, &historyStub{})
+
+	weekly, err := svc.GetRange("2024-09-01", "2024-10-31", CoinMarketCap.IntervalWeekly)
+	require.NoError(t, err)
+	require.Len(t, weekly.Points, 3)
+	assert.Equal(t, "2024-09-23", weekly.Points[0].Date)
+	assert.Equal(t, "2024-09-30", weekly.Points[1].Date)
+	assert.Equal(t, CoinMarketCap.IFearGreedStats{Min: 20, Max: 60, Avg: 36.67, Median: 30, Count: 3}, weekly.Points[1].IFearGreedStats)
+	assert.Equal(t, "Fear", weekly.Points[1].Classification)
+	assert.Equal(t, "2024-10-07", weekly.Points[2].Date)
+	assert.Equal(t, "Extreme Greed", weekly.Points[2].Classification)
+
+	monthly, err := svc.GetRange("2024-09-01", "2024-10-31", CoinMarketCap.IntervalMonthly)
+	require.NoError(t, err)
+	require.Len(t, monthly.Points, 2)
+	assert.Equal(t, "2024-09-01", monthly.Points[0].Date)
+	assert.Equal(t, 15.0, monthly.Points[0].Median)
+	assert.Equal(t, "2024-10-01", monthly.Points[1].Date)
+	assert.Equal(t, 3, monthly.Points[1].Count)
+	assert.Equal(t, 5, monthly.Stats.Count)
+	assert.Equal(t, 30.0, monthly.Stats.Median)
+}
+
+func TestFearGreedService_GetRangeDefaultsAndEmpty(t *testing.T) {
+	svc := newFearGreedService(newFearGreedDB(t), &historyStub{})
+	svc.SetClock(func() time.Time { return time.Date(2024, 10, 18, 15, 4, 5, 0, time.UTC) })
+
+	result, err := svc.GetRange("", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-09-18", result.From)
+	assert.Equal(t, "2024-10-18", result.To)
+	assert.Empty(t, result.Points)
+	assert.Nil(t, result.Stats)
+}
+
+func TestFearGreedService_GetRangeValidation(t *testing.T) {
+	svc := newFearGreedService(newFearGreedDB(t), &historyStub{})
+
+	for _, tc := range []struct{ from, to, interval string }{
+		{"2024-13-01", "", ""},
+		{"", "18/10/2024", ""},
+		{"2024-10-02", "2024-10-01", ""},
+		{"2024-10-01", "2024-10-02", "hourly"},
+	} {
+		_, err := svc.GetRange(tc.from, tc.to, tc.interval)
+		var validationErr *app_errors.ValidationError
+		assert.ErrorAs(t, err, &validationErr, "%+v", tc)
+	}
+}
+
+func TestFearGreedService_SyncPagesByTheRawPointCount(t *testing.T) {
+	db := newFearGreedDB(t)
+	stub := &historyStub{days: newHistory(1200)}
+	stub.days[10].Timestamp = "yesterday"
+	svc := newFearGreedService(db, stub)
+
+	// The first page only yields 499 days but is full, so the backfill goes on past it
+	stored, err := svc.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1199, stored)
+	assert.Equal(t, []int{1, 501, 1001}, stub.starts)
+
+	// The backfill is done: the next sync only pulls the first page
+	stub.starts = nil
+	_, err = svc.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, stub.starts)
+}
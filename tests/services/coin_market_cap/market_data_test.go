@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	services "cry-api/app/services/coin_market_cap"
+	app_errors "cry-api/app/types/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMarketDataServer serves body on path and records the query of each request
+func newMarketDataServer(t *testing.T, path string, status int, body string) (*services.CoinMarketCapService, *[]map[string]string) {
+	var queries []map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, path, r.URL.Path)
+		assert.Equal(t, "test-api-key", r.Header.Get("X-CMC_PRO_API_KEY"))
+		query := map[string]string{}
+		for k := range r.URL.Query() {
+			query[k] = r.URL.Query().Get(k)
+		}
+		queries = append(queries, query)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return services.NewCoinMarketCapServiceService(makeTestEnvConfig(server.URL, "test-api-key"), http.DefaultClient), &queries
+}
+
+const quotesBySymbol = `{"data":{
+	"BTC":[{"id":1,"name":"Bitcoin","symbol":"BTC","slug":"bitcoin","cmc_rank":1,"quote":{"EUR":{"price":61234.5,"volume_24h":1.5e10,"market_cap":1.2e12,"percent_change_1h":0.1,"percent_change_24h":-1.2,"percent_change_7d":3.4,"last_updated":"2024-10-18T12:00:00Z"}}}],
+	"ETH":[
+		{"id":99999,"name":"Ethereum Scam","symbol":"ETH","slug":"eth-scam","cmc_rank":0,"quote":{"EUR":{"price":0.01}}},
+		{"id":1027,"name":"Ethereum","symbol":"ETH","slug":"ethereum","cmc_rank":2,"quote":{"EUR":{"price":2400}}}
+	]}}`
+
+func TestGetLatestQuotes_BySymbol(t *testing.T) {
+	svc, queries := newMarketDataServer(t, "/v2/cryptocurrency/quotes/latest", http.StatusOK, quotesBySymbol)
+
+	quotes, err := svc.GetLatestQuotes(context.Background(), []string{"eth", " BTC", "ETH", "DOGE"}, nil, "eur")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"symbol": "ETH,BTC,DOGE", "convert": "EUR", "skip_invalid": "true"}, (*queries)[0])
+
+	// Request order, best ranked asset of a shared symbol, unknown symbols left out
+	require.Len(t, quotes, 2)
+	assert.Equal(t, 1027, quotes[0].ID)
+	assert.Equal(t, "2400", quotes[0].Price)
+	assert.Equal(t, "BTC", quotes[1].Symbol)
+	assert.Equal(t, "EUR", quotes[1].Currency)
+	assert.Equal(t, "61234.5", quotes[1].Price)
+	assert.Equal(t, "-1.2", quotes[1].PercentChange24h)
+	assert.Equal(t, time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC), quotes[1].LastUpdated.UTC())
+}
+
+func TestGetLatestQuotes_ByID(t *testing.T) {
+	svc, queries := newMarketDataServer(t, "/v2/cryptocurrency/quotes/latest", http.StatusOK,
+		`{"data":{"1":{"id":1,"name":"Bitcoin","symbol":"BTC","cmc_rank":1,"quote":{"USD":{"price":67000}}}}}`)
+
+	quotes, err := svc.GetLatestQuotes(context.Background(), nil, []int{1, 1}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "1", (*queries)[0]["id"])
+	assert.Equal(t, "USD", (*queries)[0]["convert"])
+	require.Len(t, quotes, 1)
+	assert.Equal(t, "67000", quotes[0].Price)
+}
+
+func TestGetLatestQuotes_KeepsNumbersExact(t *testing.T) {
+	svc, _ := newMarketDataServer(t, "/v2/cryptocurrency/quotes/latest", http.StatusOK,
+		`{"data":{"SHIB":[{"id":5994,"symbol":"SHIB","cmc_rank":12,"quote":{"USD":{"price":61234.123456789012345678,"market_cap":1.2e10,"volume_24h":null}}}]}}`)
+
+	quotes, err := svc.GetLatestQuotes(context.Background(), []string{"SHIB"}, nil, "USD")
+	require.NoError(t, err)
+	require.Len(t, quotes, 1)
+	assert.Equal(t, "61234.123456789012345678", quotes[0].Price)
+	assert.Equal(t, "12000000000", quotes[0].MarketCap)
+	assert.Equal(t, "0", quotes[0].Volume24h)
+}
+
+func TestGetLatestQuotes_Validation(t *testing.T) {
+	svc, queries := newMarketDataServer(t, "/v2/cryptocurrency/quotes/latest", http.StatusOK, `{}`)
+
+	for _, tc := range []struct {
+		symbols []string
+		ids     []int
+		convert string
+	}{
+		{nil, nil, ""},
+		{[]string{"BTC"}, []int{1}, ""},
+		{[]string{"BTC$"}, nil, ""},
+		{nil, []int{0}, ""},
+		{[]string{"BTC"}, nil, "EURO"},
+	} {
+		_, err := svc.GetLatestQuotes(context.Background(), tc.symbols, tc.ids, tc.convert)
+		var validationErr *app_errors.ValidationError
+		assert.ErrorAs(t, err, &validationErr, "%+v", tc)
+	}
+	assert.Empty(t, *queries)
+}
+
+func TestGetLatestQuotes_UpstreamError(t *testing.T) {
+	svc, _ := newMarketDataServer(t, "/v2/cryptocurrency/quotes/latest", http.StatusTooManyRequests,
+		`{"status":{"error_code":1008,"error_message":"You've exceeded your API Key's HTTP request rate limit."}}`)
+
+	_, err := svc.GetLatestQuotes(context.Background(), []string{"BTC"}, nil, "USD")
+	var appErr *app_errors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadGateway, appErr.Status)
+	assert.Equal(t, app_errors.CodeUpstreamError, appErr.Code)
+	assert.NotContains(t, appErr.Error(), "rate limit")
+}
+
+func TestGetOHLCV(t *testing.T) {
+	svc, queries := newMarketDataServer(t, "/v2/cryptocurrency/ohlcv/historical", http.StatusOK, `{"data":{"BTC":[{"id":1,"symbol":"BTC","quotes":[
+		{"time_open":"2024-10-01T00:00:00.000Z","time_close":"2024-10-01T23:59:59.999Z","quote":{"USD":{"open":63000,"high":64000,"low":60000,"close":60800,"volume":3.1e10,"market_cap":1.2e12}}},
+		{"time_open":"2024-10-02T00:00:00.000Z","time_close":"2024-10-02T23:59:59.999Z","quote":{"USD":{"open":60800,"high":62000,"low":60000,"close":61000,"volume":2.9e10,"market_cap":1.2e12}}}
+	]}]}}`)
+
+	from := time.Date(2024, 10, 1, 15, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 10, 3, 0, 0, 0, 0, time.UTC)
+	ohlcv, err := svc.GetOHLCV(context.Background(), "btc", "", "", from, to)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"symbol": "BTC", "convert": "USD", "time_period": "daily", "interval": "daily",
+		"time_start": "2024-10-01T00:00:00Z", "time_end": "2024-10-03T00:00:00Z",
+	}, (*queries)[0])
+	assert.Equal(t, "daily", ohlcv.Interval)
+	require.Len(t, ohlcv.Candles, 2)
+	assert.Equal(t, "63000", ohlcv.Candles[0].Open)
+	assert.Equal(t, "60800", ohlcv.Candles[0].Close)
+	assert.Equal(t, time.Date(2024, 10, 2, 0, 0, 0, 0, time.UTC), ohlcv.Candles[1].TimeOpen)
+}
+
+func TestGetOHLCV_Hourly(t *testing.T) {
+	svc, queries := newMarketDataServer(t, "/v2/cryptocurrency/ohlcv/historical", http.StatusOK, `{"data":{"BTC":[{"id":1,"quotes":[]}]}}`)
+
+	to := time.Date(2024, 10, 3, 10, 30, 0, 0, time.UTC)
+	ohlcv, err := svc.GetOHLCV(context.Background(), "BTC", "EUR", "hourly", to.Add(-6*time.Hour), to)
+	require.NoError(t, err)
+	assert.Equal(t, "hourly", (*queries)[0]["time_period"])
+	assert.Equal(t, "2024-10-03T04:00:00Z", (*queries)[0]["time_start"])
+	assert.Empty(t, ohlcv.Candles)
+
+	// Hourly candles span a month at most
+	_, err = svc.GetOHLCV(context.Background(), "BTC", "EUR", "hourly", to.AddDate(0, 0, -40), to)
+	var validationErr *app_errors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestGetOHLCV_UnknownSymbol(t *testing.T) {
+	svc, _ := newMarketDataServer(t, "/v2/cryptocurrency/ohlcv/historical", http.StatusOK, `{"data":{}}`)
+
+	_, err := svc.GetOHLCV(context.Background(), "NOPE", "USD", "weekly", time.Time{}, time.Time{})
+	var notFound *app_errors.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+}
+
+func TestConvertPrice(t *testing.T) {
+	svc, queries := newMarketDataServer(t, "/v2/tools/price-conversion", http.StatusOK,
+		`{"data":[{"id":1,"symbol":"BTC","name":"Bitcoin","amount":0.25,"last_updated":"2024-10-18T12:00:00Z","quote":{"EUR":{"price":15308.625,"last_updated":"2024-10-18T12:00:00Z"}}}]}`)
+
+	conversion, err := svc.ConvertPrice(context.Background(), big.NewRat(1, 4), "btc", "eur")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"amount": "0.25", "symbol": "BTC", "convert": "EUR"}, (*queries)[0])
+	assert.Equal(t, "0.25", conversion.Amount)
+	assert.Equal(t, "15308.63", conversion.Value.Value)
+	assert.Equal(t, "61234.5", conversion.Rate)
+	assert.Equal(t, "EUR", conversion.Currency)
+
+	_, err = svc.ConvertPrice(context.Background(), big.NewRat(-1, 1), "BTC", "EUR")
+	var validationErr *app_errors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Len(t, *queries, 1)
+}
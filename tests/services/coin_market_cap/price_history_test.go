@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	UserModel "cry-api/app/models"
+	repositorie "cry-api/app/repositories"
+	services "cry-api/app/services/coin_market_cap"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+	EnvTypes "cry-api/app/types/env"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// priceStub serves a daily price for every requested day, recording the requested ranges
+type priceStub struct {
+	*testmocks.MockCoinMarketCapService
+	ranges [][2]string
+	fail   bool
+}
+
+func (s *priceStub) GetHistoricalPrices(_ context.Context, symbol, convert string, from, to time.Time) ([]CoinMarketCap.IPricePoint, error) {
+	if s.fail {
+		return nil, errors.New("upstream unavailable")
+	}
+	if symbol != "BTC" || convert != "USD" {
+		return nil, errors.New("unexpected series")
+	}
+	s.ranges = append(s.ranges, [2]string{from.Format(time.DateOnly), to.Format(time.DateOnly)})
+
+	var points []CoinMarketCap.IPricePoint
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		points = append(points, CoinMarketCap.IPricePoint{Date: day.Format(time.DateOnly), Price: big.NewRat(int64(60000+day.YearDay()), 1)})
+	}
+	return points, nil
+}
+
+func newPriceHistoryDB(t *testing.T) *gorm.DB {
+	db := newFearGreedDB(t)
+	require.NoError(t, db.AutoMigrate(&UserModel.PriceHistory{}))
+	return db
+}
+
+func newPriceHistoryService(db *gorm.DB, stub *priceStub, today time.Time) *services.PriceHistoryService {
+	cfg := &EnvTypes.EnvConfig{CoinMarketCapConfig: EnvTypes.CoinMarketCapConfig{PriceHistorySyncInterval: 60}}
+	svc := services.NewPriceHistoryService(cfg, stub, repositorie.NewGormPriceHistoryRepository(db), repositorie.NewGormFearGreedRepository(db))
+	svc.SetClock(func() time.Time { return today })
+	return svc
+}
+
+func countPrices(t *testing.T, db *gorm.DB) int64 {
+	var count int64
+	require.NoError(t, db.Model(&UserModel.PriceHistory{}).Count(&count).Error)
+	return count
+}
+
+func TestPriceHistoryService_SyncCoversTheFearGreedHistory(t *testing.T) {
+	db := newPriceHistoryDB(t)
+	seedFearGreed(t, db, map[string]int{"2024-06-01": 40, "2024-10-18": 60})
+	stub := &priceStub{}
+	svc := newPriceHistoryService(db, stub, time.Date(2024, 10, 18, 15, 0, 0, 0, time.UTC))
+
+	stored, err := svc.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 140, stored)
+	assert.Equal(t, int64(140), countPrices(t, db))
+	assert.Equal(t, [][2]string{{"2024-06-01", "2024-08-29"}, {"2024-08-30", "2024-10-18"}}, stub.ranges)
+
+	// The next day, only the latest stored day and the new one are pulled
+	stub.ranges = nil
+	svc.SetClock(func() time.Time { return time.Date(2024, 10, 19, 1, 0, 0, 0, time.UTC) })
+	stored, err = svc.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, stored)
+	assert.Equal(t, int64(141), countPrices(t, db))
+	assert.Equal(t, [][2]string{{"2024-10-18", "2024-10-19"}}, stub.ranges)
+}
+
+func TestPriceHistoryService_SyncBackfillsOlderFearGreedDays(t *testing.T) {
+	db := newPriceHistoryDB(t)
+	today := time.Date(2024, 10, 18, 0, 0, 0, 0, time.UTC)
+	seedFearGreed(t, db, map[string]int{"2024-10-01": 40})
+	_, err := newPriceHistoryService(db, &priceStub{}, today).Sync(context.Background())
+	require.NoError(t, err)
+
+	// The fear and greed backfill reached older days since
+	seedFearGreed(t, db, map[string]int{"2024-09-20": 30})
+	stub := &priceStub{}
+	stored, err := newPriceHistoryService(db, stub, today).Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 12, stored)
+	assert.Equal(t, [][2]string{{"2024-10-18", "2024-10-18"}, {"2024-09-20", "2024-09-30"}}, stub.ranges)
+	assert.Equal(t, int64(29), countPrices(t, db))
+}
+
+func TestPriceHistoryService_SyncDefaultsToAYear(t *testing.T) {
+	db := newPriceHistoryDB(t)
+	stub := &priceStub{}
+
+	stored, err := newPriceHistoryService(db, stub, time.Date(2024, 10, 18, 0, 0, 0, 0, time.UTC)).Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, services.DefaultPriceBackfillDays+1, stored)
+	assert.Equal(t, "2023-10-19", stub.ranges[0][0])
+}
+
+func TestPriceHistoryService_SyncReportsUpstreamFailures(t *testing.T) {
+	db := newPriceHistoryDB(t)
+
+	stored, err := newPriceHistoryService(db, &priceStub{fail: true}, time.Date(2024, 10, 18, 0, 0, 0, 0, time.UTC)).Sync(context.Background())
+	assert.ErrorContains(t, err, "failed to fetch the price history")
+	assert.Equal(t, 0, stored)
+	assert.Equal(t, int64(0), countPrices(t, db))
+}
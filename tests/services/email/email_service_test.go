@@ -185,3 +185,83 @@ func TestSendResetPasswordEmail_SendEmailError(t *testing.T) {
 	mockCreator.AssertExpectations(t)
 	mockSender.AssertExpectations(t)
 }
+
+func TestSendWalletNotificationEmail_Success(t *testing.T) {
+	mockSender := new(testmocks.MockEmailSender)
+	mockCreator := new(testmocks.MockEmailCreator)
+
+	service := Services.NewEmailService(mockSender, mockCreator)
+
+	expectedEmail := Email.EmailMessage{
+		To:      "user@example.com",
+		From:    "no-reply@example.com",
+		Subject: "Received 0.5 BTC on Savings",
+		Body:    "<html>Wallet notification body</html>",
+	}
+
+	mockCreator.
+		On("CreateWalletNotificationEmail", "user@example.com", "no-reply@example.com", "testuser", "Received 0.5 BTC on Savings", "details", "https://example.com/notifications").
+		Return(expectedEmail, nil).
+		Once()
+
+	mockSender.
+		On("Send", expectedEmail).
+		Return(nil).
+		Once()
+
+	err := service.SendWalletNotificationEmail("user@example.com", "no-reply@example.com", "testuser", "Received 0.5 BTC on Savings", "details", "https://example.com/notifications")
+	assert.NoError(t, err)
+
+	mockCreator.AssertExpectations(t)
+	mockSender.AssertExpectations(t)
+}
+
+func TestSendWalletNotificationEmail_SendEmailError(t *testing.T) {
+	mockSender := new(testmocks.MockEmailSender)
+	mockCreator := new(testmocks.MockEmailCreator)
+
+	service := Services.NewEmailService(mockSender, mockCreator)
+
+	mockCreator.
+		On("CreateWalletNotificationEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(Email.EmailMessage{}, nil).
+		Once()
+
+	mockSender.
+		On("Send", mock.Anything).
+		Return(errors.New("send error")).
+		Once()
+
+	err := service.SendWalletNotificationEmail("to", "from", "user", "title", "message", "link")
+	assert.EqualError(t, err, "send error")
+}
+
+func TestSendAlertEmail_Success(t *testing.T) {
+	mockSender := new(testmocks.MockEmailSender)
+	mockCreator := new(testmocks.MockEmailCreator)
+
+	service := Services.NewEmailService(mockSender, mockCreator)
+
+	expectedEmail := Email.EmailMessage{
+		To:      "user@example.com",
+		From:    "no-reply@example.com",
+		Subject: "BTC is above 70000 USD",
+		Body:    "<html>Alert body</html>",
+	}
+
+	mockCreator.
+		On("CreateAlertEmail", "user@example.com", "no-reply@example.com", "testuser", "BTC is above 70000 USD", "details", "https://example.com/alerts").
+		Return(expectedEmail, nil).
+		Once()
+
+	mockSender.
+		On("Send", expectedEmail).
+		Return(nil).
+		Once()
+
+	err := service.SendAlertEmail("user@example.com", "no-reply@example.com", "testuser", "BTC is above 70000 USD", "details", "https://example.com/alerts")
+	assert.NoError(t, err)
+
+	mockCreator.AssertExpectations(t)
+	mockSender.AssertExpectations(t)
+}
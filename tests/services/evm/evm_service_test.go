@@ -0,0 +1,328 @@
+package tests
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+
+	services "cry-api/app/services/evm"
+	EnvTypes "cry-api/app/types/env"
+	app_errors "cry-api/app/types/errors"
+	EVMTypes "cry-api/app/types/evm"
+	"cry-api/tests/jsonrpc"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	alice       = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	bob         = "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"
+	usdc        = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	mkr         = "0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2"
+	kitties     = "0x06012c8cf97BEaD5deAe237070F9587f8E7A266d"
+	nonToken    = "0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB"
+	transferSig = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+	latestBlock = 65536
+)
+
+var (
+	minedHash   = "0x" + strings.Repeat("ab", 32)
+	pendingHash = "0x" + strings.Repeat("cd", 32)
+	failedHash  = "0x" + strings.Repeat("ef", 32)
+)
+
+func topic(address string) string {
+	return "0x" + strings.Repeat("0", 24) + strings.ToLower(address[2:])
+}
+
+func word(n int64) string {
+	return fmt.Sprintf("0x%064x", n)
+}
+
+// abiString encodes a string return value
+func abiString(s string) string {
+	data := hex.EncodeToString([]byte(s))
+	padded := data + strings.Repeat("0", (64-len(data)%64)%64)
+	return fmt.Sprintf("0x%064x%064x%s", 32, len(s), padded)
+}
+
+func transferLog(token, from, to string, value int64, block, index int) map[string]any {
+	return map[string]any{
+		"address":         strings.ToLower(token),
+		"topics":          []string{transferSig, topic(from), topic(to)},
+		"data":            word(value),
+		"blockNumber":     fmt.Sprintf("0x%x", block),
+		"transactionHash": fmt.Sprintf("0x%064x", block*100+index),
+		"logIndex":        fmt.Sprintf("0x%x", index),
+		"removed":         false,
+	}
+}
+
+// newNode starts a stand-in Ethereum node holding a few transactions, tokens and logs
+func newNode(t *testing.T) *jsonrpc.Server {
+	node := jsonrpc.New(t)
+	node.Result("eth_blockNumber", fmt.Sprintf("0x%x", latestBlock))
+	node.Result("eth_getBalance", "0x14d1120d7b160000") // 1.5 ETH
+
+	node.Handle("eth_getTransactionByHash", func(params []json.RawMessage) (any, *jsonrpc.Error) {
+		var hash string
+		_ = json.Unmarshal(params[0], &hash)
+		tx := map[string]any{
+			"hash": hash, "from": strings.ToLower(alice), "to": strings.ToLower(usdc), "nonce": "0x2a",
+			"value": "0x0", "gas": "0x186a0", "gasPrice": "0x3b9aca00", "blockNumber": "0x64", "blockHash": "0x" + strings.Repeat("11", 32),
+		}
+		switch hash {
+		case minedHash:
+			return tx, nil
+		case pendingHash:
+			tx["blockNumber"], tx["blockHash"] = nil, nil
+			return tx, nil
+		case failedHash:
+			tx["to"], tx["value"] = strings.ToLower(bob), "0x14d1120d7b160000"
+			return tx, nil
+		}
+		return nil, nil
+	})
+
+	node.Handle("eth_getTransactionReceipt", func(params []json.RawMessage) (any, *jsonrpc.Error) {
+		var hash string
+		_ = json.Unmarshal(params[0], &hash)
+		switch hash {
+		case minedHash:
+			return map[string]any{
+				"status": "0x1", "gasUsed": "0xb411", "effectiveGasPrice": "0x4a817c800", "contractAddress": nil,
+				"logs": []any{transferLog(usdc, alice, bob, 250000000, 100, 3)},
+			}, nil
+		case failedHash:
+			// pre-London receipts have no effectiveGasPrice
+			return map[string]any{"status": "0x0", "gasUsed": "0x5208", "contractAddress": nil, "logs": []any{}}, nil
+		}
+		return nil, nil
+	})
+
+	node.Handle("eth_call", func(params []json.RawMessage) (any, *jsonrpc.Error) {
+		var call struct{ To, Data string }
+		_ = json.Unmarshal(params[0], &call)
+		switch {
+		case call.To == strings.ToLower(usdc) && call.Data == "0x95d89b41":
+			return abiString("USDC"), nil
+		case call.To == strings.ToLower(usdc) && call.Data == "0x313ce567":
+			return word(6), nil
+		case call.To == strings.ToLower(usdc) && strings.HasPrefix(call.Data, "0x70a08231"):
+			return word(1234500000), nil
+		case call.To == strings.ToLower(mkr) && call.Data == "0x95d89b41":
+			return "0x" + hex.EncodeToString([]byte("MKR")) + strings.Repeat("0", 58), nil // bytes32
+		case call.To == strings.ToLower(mkr) && call.Data == "0x313ce567":
+			return word(18), nil
+		case call.To == strings.ToLower(mkr) && strings.HasPrefix(call.Data, "0x70a08231"):
+			return word(0), nil
+		}
+		return nil, &jsonrpc.Error{Code: -32000, Message: "execution reverted"}
+	})
+
+	node.Handle("eth_getLogs", func(params []json.RawMessage) (any, *jsonrpc.Error) {
+		var filter struct {
+			Topics []*string `json:"topics"`
+		}
+		_ = json.Unmarshal(params[0], &filter)
+		self := transferLog(mkr, alice, alice, 5e17, 101, 0)
+		if len(filter.Topics) == 2 {
+			return []any{transferLog(usdc, alice, bob, 250000000, 100, 3), self}, nil
+		}
+		nft := transferLog(kitties, bob, alice, 0, 99, 7)
+		nft["topics"] = append(nft["topics"].([]string), word(1))
+		return []any{transferLog(usdc, bob, alice, 1484500000, 99, 5), self, nft}, nil
+	})
+	return node
+}
+
+func newEVMService(node *jsonrpc.Server) *services.EVMService {
+	cfg := &EnvTypes.EnvConfig{EVMConfig: EnvTypes.EVMConfig{
+		Nodes:         map[string]string{"ethereum": node.URL, "polygon": node.URL},
+		LogBlockRange: 5000,
+	}}
+	return services.NewEVMService(cfg, http.DefaultClient)
+}
+
+func TestEVMService_GetTransaction_WithTokenTransfer(t *testing.T) {
+	svc := newEVMService(newNode(t))
+
+	tx, err := svc.GetTransaction(context.Background(), "ethereum", "0x"+strings.ToUpper(minedHash[2:]))
+	assert.NoError(t, err)
+	assert.Equal(t, minedHash, tx.Hash)
+	assert.Equal(t, EVMTypes.TxStatusSuccess, tx.Status)
+	assert.Equal(t, uint64(100), *tx.BlockNumber)
+	assert.Equal(t, alice, tx.From)
+	assert.Equal(t, usdc, *tx.To)
+	assert.Equal(t, uint64(42), tx.Nonce)
+	assert.Equal(t, "0", tx.ValueAmount)
+	assert.Equal(t, "ETH", tx.Symbol)
+	assert.Equal(t, uint64(46097), *tx.GasUsed)
+	assert.Equal(t, "20000000000", tx.EffectiveGasPrice)
+	assert.Equal(t, "921940000000000", tx.Fee)
+	assert.Equal(t, "0.00092194", tx.FeeAmount)
+
+	assert.Len(t, tx.TokenTransfers, 1)
+	transfer := tx.TokenTransfers[0]
+	assert.Equal(t, usdc, transfer.Token)
+	assert.Equal(t, "USDC", transfer.Symbol)
+	assert.Equal(t, 6, *transfer.Decimals)
+	assert.Equal(t, alice, transfer.From)
+	assert.Equal(t, bob, transfer.To)
+	assert.Equal(t, "250000000", transfer.Value)
+	assert.Equal(t, "250", transfer.Amount)
+	assert.Equal(t, uint64(3), transfer.LogIndex)
+}
+
+func TestEVMService_GetTransaction_PendingAndFailed(t *testing.T) {
+	node := newNode(t)
+	svc := newEVMService(node)
+
+	pending, err := svc.GetTransaction(context.Background(), "ethereum", pendingHash)
+	assert.NoError(t, err)
+	assert.Equal(t, EVMTypes.TxStatusPending, pending.Status)
+	assert.Nil(t, pending.BlockNumber)
+	assert.Nil(t, pending.GasUsed)
+	assert.Len(t, node.Calls("eth_getTransactionReceipt"), 0)
+
+	failed, err := svc.GetTransaction(context.Background(), "polygon", failedHash)
+	assert.NoError(t, err)
+	assert.Equal(t, EVMTypes.TxStatusFailed, failed.Status)
+	assert.Equal(t, "1.5", failed.ValueAmount)
+	assert.Equal(t, "POL", failed.Symbol)
+	assert.Equal(t, "21000000000000", failed.Fee) // 21000 gas at the legacy gas price of 1 gwei
+	assert.Empty(t, failed.TokenTransfers)
+}
+
+func TestEVMService_GetTransaction_Errors(t *testing.T) {
+	svc := newEVMService(newNode(t))
+
+	_, err := svc.GetTransaction(context.Background(), "ethereum", "0x"+strings.Repeat("00", 32))
+	var notFound *app_errors.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+	assert.Equal(t, "transaction", notFound.Resource)
+
+	_, err = svc.GetTransaction(context.Background(), "solana", minedHash)
+	assert.ErrorAs(t, err, &notFound)
+	assert.Equal(t, "chain", notFound.Resource)
+
+	_, err = svc.GetTransaction(context.Background(), "ethereum", "0x1234")
+	var invalid *app_errors.ValidationError
+	assert.ErrorAs(t, err, &invalid)
+}
+
+func TestEVMService_GetBalances(t *testing.T) {
+	node := newNode(t)
+	svc := newEVMService(node)
+
+	balances, err := svc.GetBalances(context.Background(), "ethereum", strings.ToLower(alice), []string{mkr, nonToken})
+	assert.NoError(t, err)
+	assert.Equal(t, alice, balances.Address)
+	assert.Equal(t, uint64(latestBlock), balances.BlockNumber)
+	assert.Equal(t, "1500000000000000000", balances.Native.Balance)
+	assert.Equal(t, "1.5", balances.Native.Amount)
+	assert.Equal(t, "ETH", balances.Native.Symbol)
+
+	// The requested MKR is kept at zero, the discovered USDC is added, the non-token and the
+	// ERC-721 contract are skipped
+	assert.Len(t, balances.Tokens, 2)
+	assert.Equal(t, EVMTypes.IAssetBalance{Token: mkr, Symbol: "MKR", Decimals: balances.Tokens[0].Decimals, Balance: "0", Amount: "0"}, balances.Tokens[0])
+	assert.Equal(t, 18, *balances.Tokens[0].Decimals)
+	assert.Equal(t, usdc, balances.Tokens[1].Token)
+	assert.Equal(t, "1234.5", balances.Tokens[1].Amount)
+
+	// Balances are read at the block the window ends at
+	for _, params := range node.Calls("eth_getBalance") {
+		assert.JSONEq(t, `"0x10000"`, string(params[1]))
+	}
+}
+
+func TestEVMService_GetTokenTransfers(t *testing.T) {
+	node := newNode(t)
+	svc := newEVMService(node)
+
+	transfers, err := svc.GetTokenTransfers(context.Background(), "ethereum", alice)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(latestBlock-5000+1), transfers.FromBlock)
+	assert.Equal(t, uint64(latestBlock), transfers.ToBlock)
+
+	// Newest first, the self transfer seen by both filters once, the ERC-721 transfer skipped
+	assert.Len(t, transfers.Transfers, 3)
+	assert.Equal(t, EVMTypes.DirectionSelf, transfers.Transfers[0].Direction)
+	assert.Equal(t, "MKR", transfers.Transfers[0].Symbol)
+	assert.Equal(t, "0.5", transfers.Transfers[0].Amount)
+	assert.Equal(t, EVMTypes.DirectionOut, transfers.Transfers[1].Direction)
+	assert.Equal(t, EVMTypes.DirectionIn, transfers.Transfers[2].Direction)
+	assert.Equal(t, "1484.5", transfers.Transfers[2].Amount)
+
+	calls := node.Calls("eth_getLogs")
+	assert.Len(t, calls, 2)
+	assert.JSONEq(t, fmt.Sprintf(`{"fromBlock":"0xec79","toBlock":"0x10000","topics":[%q,%q]}`, transferSig, topic(alice)), string(calls[0][0]))
+	assert.JSONEq(t, fmt.Sprintf(`{"fromBlock":"0xec79","toBlock":"0x10000","topics":[%q,null,%q]}`, transferSig, topic(alice)), string(calls[1][0]))
+}
+
+func TestEVMService_NodeErrors(t *testing.T) {
+	node := jsonrpc.New(t)
+	node.Handle("eth_blockNumber", func([]json.RawMessage) (any, *jsonrpc.Error) {
+		return nil, &jsonrpc.Error{Code: -32005, Message: "limit exceeded"}
+	})
+	svc := newEVMService(node)
+
+	_, err := svc.GetTokenTransfers(context.Background(), "ethereum", alice)
+	var appErr *app_errors.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadGateway, appErr.Status)
+	assert.Equal(t, app_errors.CodeUpstreamError, appErr.Code)
+	assert.Empty(t, appErr.Details)
+
+	_, err = svc.GetBalances(context.Background(), "ethereum", "0xnope", nil)
+	var invalid *app_errors.ValidationError
+	assert.ErrorAs(t, err, &invalid)
+}
+
+func TestEVMService_UnreachableNodeHidesItsURL(t *testing.T) {
+	cfg := &EnvTypes.EnvConfig{EVMConfig: EnvTypes.EVMConfig{
+		Nodes:         map[string]string{"ethereum": "http://127.0.0.1:1/v3/secret-api-key"},
+		LogBlockRange: 5000,
+	}}
+	svc := services.NewEVMService(cfg, http.DefaultClient)
+
+	_, err := svc.GetTokenTransfers(context.Background(), "ethereum", alice)
+	var appErr *app_errors.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadGateway, appErr.Status)
+	assert.NotContains(t, appErr.Error(), "secret-api-key")
+}
+
+func TestChecksumAddress(t *testing.T) {
+	// EIP-55 test vectors
+	for _, address := range []string{alice, bob, nonToken, "0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb"} {
+		assert.Equal(t, address, services.ChecksumAddress(strings.ToLower(address)))
+	}
+}
+
+func TestFormatUnits(t *testing.T) {
+	testCases := []struct {
+		value    string
+		decimals int
+		expected string
+	}{
+		{"0", 18, "0"},
+		{"1", 18, "0.000000000000000001"},
+		{"1500000000000000000", 18, "1.5"},
+		{"250000000", 6, "250"},
+		{"-1234500", 6, "-1.2345"},
+		{"115792089237316195423570985008687907853269984665640564039457584007913129639935", 18, "115792089237316195423570985008687907853269984665640564039457.584007913129639935"},
+		{"42", 0, "42"},
+	}
+
+	for _, tc := range testCases {
+		value, _ := new(big.Int).SetString(tc.value, 10)
+		assert.Equal(t, tc.expected, services.FormatUnits(value, tc.decimals))
+	}
+}
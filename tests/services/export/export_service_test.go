@@ -0,0 +1,273 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	services "cry-api/app/services/export"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+	app_errors "cry-api/app/types/errors"
+	ExportTypes "cry-api/app/types/export"
+	WalletExplorer "cry-api/app/types/wallet_explorer"
+	WatchlistTypes "cry-api/app/types/watchlist"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	bip84Zpub    = "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs"
+	bip84Address = "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu"
+)
+
+func newExportService() (*services.ExportService, *testmocks.MockTransactionService, *testmocks.MockWatchlistService, *testmocks.MockCoinMarketCapService) {
+	transactions := new(testmocks.MockTransactionService)
+	watchlist := new(testmocks.MockWatchlistService)
+	market := new(testmocks.MockCoinMarketCapService)
+	return services.NewExportService(transactions, watchlist, market), transactions, watchlist, market
+}
+
+// daysAgo returns noon UTC, n days before today
+func daysAgo(n int) time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, time.UTC).AddDate(0, 0, -n)
+}
+
+func pricePoint(n int, price int64) CoinMarketCap.IPricePoint {
+	return CoinMarketCap.IPricePoint{Date: daysAgo(n).Format("2006-01-02"), Price: big.NewRat(price, 1)}
+}
+
+func TestParseExportOptions(t *testing.T) {
+	opts, err := services.ParseExportOptions("", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, &ExportTypes.IExportOptions{Format: "csv", Columns: services.DefaultColumns, Currency: "USD"}, opts)
+
+	opts, err = services.ParseExportOptions("JSONL", " txid, Amount ", "eur")
+	assert.NoError(t, err)
+	assert.Equal(t, "jsonl", opts.Format)
+	assert.Equal(t, []string{"txid", "amount"}, opts.Columns)
+	assert.Equal(t, "EUR", opts.Currency)
+
+	for _, tc := range []struct{ format, columns, currency, field string }{
+		{"xlsx", "", "", "format"},
+		{"csv", "txid,memo", "", "columns"},
+		{"csv", "", "euro", "currency"},
+		{"csv", "", "XYZ", "currency"},
+	} {
+		_, err := services.ParseExportOptions(tc.format, tc.columns, tc.currency)
+		var validationErr *app_errors.ValidationError
+		if assert.ErrorAs(t, err, &validationErr) {
+			assert.Equal(t, tc.field, validationErr.Field)
+		}
+	}
+}
+
+func TestExportAddress_CSV(t *testing.T) {
+	svc, transactions, _, market := newExportService()
+
+	market.On("GetHistoricalPrices", "BTC", "USD", mock.Anything, mock.Anything).Return([]CoinMarketCap.IPricePoint{
+		pricePoint(5, 20000),
+		pricePoint(1, 30000),
+	}, nil).Once()
+	transactions.On("StreamAddressTransactions", bip84Address).Return([]WalletExplorer.AddressTransaction{
+		{Hash: "out", Time: daysAgo(1).Unix(), BlockHeight: 800100, Result: -25000000, Balance: 75000000, Fee: 1000},
+		{Hash: "in", Time: daysAgo(5).Unix(), BlockHeight: 800000, Result: 100000000, Balance: 100000000, Fee: 2000},
+	}, nil).Once()
+
+	opts, _ := services.ParseExportOptions("csv", "txid,block_height,amount,fee,balance,price,fiat_value", "usd")
+
+	var buf bytes.Buffer
+	assert.NoError(t, svc.ExportAddress(context.Background(), &buf, bip84Address, opts))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, []string{
+		"txid,block_height,amount,fee,balance,price,fiat_value",
+		"out,800100,-0.25000000,0.00001000,0.75000000,30000.00,-7500.00",
+		"in,800000,1.00000000,,1.00000000,20000.00,20000.00",
+	}, lines)
+	transactions.AssertExpectations(t)
+	market.AssertExpectations(t)
+}
+
+func TestExportAddress_FetchesOlderPricesLazily(t *testing.T) {
+	svc, transactions, _, market := newExportService()
+
+	market.On("GetHistoricalPrices", "BTC", "USD", mock.Anything, mock.Anything).Return([]CoinMarketCap.IPricePoint{
+		pricePoint(1, 30000),
+	}, nil).Once()
+	market.On("GetHistoricalPrices", "BTC", "USD", mock.Anything, mock.Anything).Return([]CoinMarketCap.IPricePoint{
+		pricePoint(400, 10000),
+	}, nil).Once()
+	transactions.On("StreamAddressTransactions", bip84Address).Return([]WalletExplorer.AddressTransaction{
+		{Hash: "recent", Time: daysAgo(1).Unix(), Result: 100000000, Balance: 200000000},
+		{Hash: "old", Time: daysAgo(400).Unix(), Result: 100000000, Balance: 100000000},
+	}, nil).Once()
+
+	opts, _ := services.ParseExportOptions("jsonl", "", "")
+
+	var buf bytes.Buffer
+	assert.NoError(t, svc.ExportAddress(context.Background(), &buf, bip84Address, opts))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+
+	var row ExportTypes.IExportRow
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &row))
+	assert.Equal(t, "old", row.TxID)
+	assert.Equal(t, "10000.00", row.Price.Value)
+	assert.Nil(t, row.Fee)
+	market.AssertNumberOfCalls(t, "GetHistoricalPrices", 2)
+}
+
+func TestExportAddress_Errors(t *testing.T) {
+	t.Run("Invalid address", func(t *testing.T) {
+		svc, _, _, _ := newExportService()
+		opts, _ := services.ParseExportOptions("", "", "")
+
+		err := svc.ExportAddress(context.Background(), &bytes.Buffer{}, "not-an-address", opts)
+		var validationErr *app_errors.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("Upstream failure before streaming", func(t *testing.T) {
+		svc, transactions, _, market := newExportService()
+		market.On("GetHistoricalPrices", "BTC", "USD", mock.Anything, mock.Anything).Return([]CoinMarketCap.IPricePoint{}, nil).Once()
+		transactions.On("StreamAddressTransactions", bip84Address).Return(nil, errors.New("timeout")).Once()
+		opts, _ := services.ParseExportOptions("", "", "")
+
+		var buf bytes.Buffer
+		err := svc.ExportAddress(context.Background(), &buf, bip84Address, opts)
+		var appErr *app_errors.AppError
+		if assert.ErrorAs(t, err, &appErr) {
+			assert.Equal(t, http.StatusBadGateway, appErr.Status)
+		}
+		assert.Zero(t, buf.Len())
+	})
+
+	t.Run("Price failure", func(t *testing.T) {
+		svc, _, _, market := newExportService()
+		market.On("GetHistoricalPrices", "BTC", "USD", mock.Anything, mock.Anything).Return(nil, errors.New("quota exceeded")).Once()
+		opts, _ := services.ParseExportOptions("", "", "")
+
+		err := svc.ExportAddress(context.Background(), &bytes.Buffer{}, bip84Address, opts)
+		var appErr *app_errors.AppError
+		if assert.ErrorAs(t, err, &appErr) {
+			assert.Equal(t, http.StatusBadGateway, appErr.Status)
+		}
+	})
+}
+
+func TestExportXPUB_JSONLNewestFirst(t *testing.T) {
+	svc, transactions, _, market := newExportService()
+
+	market.On("GetHistoricalPrices", "BTC", "EUR", mock.Anything, mock.Anything).Return([]CoinMarketCap.IPricePoint{
+		pricePoint(3, 25000),
+	}, nil).Once()
+	transactions.On("GetTransactionByXPUB", bip84Zpub).Return(&WalletExplorer.ITransactionXPUB{
+		Found: true,
+		Transactions: []WalletExplorer.XPUBTransaction{
+			{TxID: "first", Time: daysAgo(3).Unix(), BalanceDiff: 0.5, Balance: 0.5},
+			{TxID: "second", Time: daysAgo(2).Unix(), BalanceDiff: -0.1, Balance: 0.4},
+		},
+	}, nil).Once()
+
+	opts, _ := services.ParseExportOptions("jsonl", "", "EUR")
+
+	var buf bytes.Buffer
+	assert.NoError(t, svc.ExportXPUB(context.Background(), &buf, bip84Zpub, opts))
+
+	var rows []ExportTypes.IExportRow
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var row ExportTypes.IExportRow
+		assert.NoError(t, json.Unmarshal([]byte(line), &row))
+		rows = append(rows, row)
+	}
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "second", rows[0].TxID)
+	assert.Equal(t, "25000.00", rows[0].Price.Value)
+	assert.Equal(t, int64(-250000), rows[0].FiatValue.MinorUnits)
+	assert.Equal(t, "EUR", rows[0].FiatValue.Currency)
+	assert.Equal(t, "EUR", rows[0].Currency)
+	assert.Equal(t, "first", rows[1].TxID)
+}
+
+func TestExportWatchlist_OFX(t *testing.T) {
+	svc, _, watchlist, market := newExportService()
+
+	market.On("GetHistoricalPrices", "BTC", "USD", mock.Anything, mock.Anything).Return([]CoinMarketCap.IPricePoint{
+		pricePoint(10, 20000),
+	}, nil).Once()
+	watchlist.On("GetHistory", 7).Return(&WatchlistTypes.IWatchlistHistory{
+		Transactions: []WatchlistTypes.IWatchlistActivity{
+			{TxID: "buy", Label: "Cold & warm", Time: daysAgo(10).Unix(), BalanceDiff: 1},
+			{TxID: "spend", Label: "Hot", Time: daysAgo(2).Unix(), BalanceDiff: -0.25},
+		},
+	}, nil).Once()
+
+	opts, _ := services.ParseExportOptions("ofx", "", "")
+
+	var buf bytes.Buffer
+	assert.NoError(t, svc.ExportWatchlist(context.Background(), &buf, 7, opts))
+
+	body := buf.String()
+	assert.True(t, strings.HasPrefix(body, `<?xml version="1.0"`))
+	assert.Contains(t, body, "<CURDEF>XBT</CURDEF>")
+	assert.Contains(t, body, "<TRNTYPE>DEBIT</TRNTYPE>")
+	assert.Contains(t, body, "<TRNAMT>-0.25000000</TRNAMT><FITID>spend</FITID>")
+	assert.Contains(t, body, "<MEMO>Cold &amp; warm - 20000.00 USD @ 20000.00 USD</MEMO>")
+	assert.Contains(t, body, "<LEDGERBAL><BALAMT>0.75000000</BALAMT>")
+	assert.Less(t, strings.Index(body, "spend"), strings.Index(body, "buy"))
+}
+
+func TestExportWatchlist_CSVWalletColumn(t *testing.T) {
+	svc, _, watchlist, market := newExportService()
+
+	market.On("GetHistoricalPrices", "BTC", "USD", mock.Anything, mock.Anything).Return([]CoinMarketCap.IPricePoint{}, nil).Once()
+	watchlist.On("GetHistory", 7).Return(&WatchlistTypes.IWatchlistHistory{
+		Transactions: []WatchlistTypes.IWatchlistActivity{
+			{TxID: "a", Label: "Savings", Time: daysAgo(4).Unix(), BalanceDiff: 0.3},
+			{TxID: "b", Label: "Savings", Time: daysAgo(3).Unix(), BalanceDiff: 0.2},
+		},
+	}, nil).Once()
+
+	opts, _ := services.ParseExportOptions("csv", "wallet,txid,balance", "")
+
+	var buf bytes.Buffer
+	assert.NoError(t, svc.ExportWatchlist(context.Background(), &buf, 7, opts))
+	assert.Equal(t, "wallet,txid,balance\nSavings,b,0.50000000\nSavings,a,0.30000000\n", buf.String())
+}
+
+func TestExport_BufferedHistoryTooLarge(t *testing.T) {
+	svc, transactions, watchlist, _ := newExportService()
+
+	transactions.On("GetTransactionByXPUB", bip84Zpub).Return(&WalletExplorer.ITransactionXPUB{
+		Found:        true,
+		Transactions: make([]WalletExplorer.XPUBTransaction, services.MaxBufferedRows+1),
+	}, nil).Once()
+	watchlist.On("GetHistory", 7).Return(&WatchlistTypes.IWatchlistHistory{
+		Transactions: make([]WatchlistTypes.IWatchlistActivity, services.MaxBufferedRows+1),
+	}, nil).Once()
+
+	opts, _ := services.ParseExportOptions("csv", "", "")
+
+	var buf bytes.Buffer
+	for _, err := range []error{
+		svc.ExportXPUB(context.Background(), &buf, bip84Zpub, opts),
+		svc.ExportWatchlist(context.Background(), &buf, 7, opts),
+	} {
+		var appErr *app_errors.AppError
+		if assert.ErrorAs(t, err, &appErr) {
+			assert.Equal(t, http.StatusUnprocessableEntity, appErr.Status)
+			assert.Equal(t, app_errors.CodeExportTooLarge, appErr.Code)
+		}
+	}
+	assert.Zero(t, buf.Len())
+}
@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"cry-api/app/cache"
+	services "cry-api/app/services/fx"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+	app_errors "cry-api/app/types/errors"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func btcQuote(currency string, price string) []CoinMarketCap.IQuote {
+	return []CoinMarketCap.IQuote{{Symbol: "BTC", Currency: currency, Price: price}}
+}
+
+func TestFXService_ConvertsThroughBTCPrices(t *testing.T) {
+	market := new(testmocks.MockCoinMarketCapService)
+	svc := services.NewFXService(market, nil)
+
+	market.On("GetLatestQuotes", []string{"BTC"}, []int(nil), "USD").Return(btcQuote("USD", "50000"), nil).Once()
+	market.On("GetLatestQuotes", []string{"BTC"}, []int(nil), "EUR").Return(btcQuote("EUR", "40000"), nil).Once()
+
+	amount, err := svc.Convert(context.Background(), big.NewRat(10001, 100), "USD", "eur")
+	assert.NoError(t, err)
+	assert.Equal(t, "EUR", amount.Currency)
+	assert.Equal(t, "80.01", amount.Value) // 100.01 * 0.8 = 80.008
+	assert.Equal(t, int64(8001), amount.MinorUnits)
+	market.AssertExpectations(t)
+}
+
+func TestFXService_SameCurrencySkipsUpstream(t *testing.T) {
+	market := new(testmocks.MockCoinMarketCapService)
+	svc := services.NewFXService(market, nil)
+
+	amount, err := svc.Convert(context.Background(), big.NewRat(1005, 1000), "JPY", "JPY")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", amount.Value)
+	market.AssertNotCalled(t, "GetLatestQuotes")
+}
+
+func TestFXService_CachesPrices(t *testing.T) {
+	market := new(testmocks.MockCoinMarketCapService)
+	c := cache.New(cache.NewMemoryStore(10))
+	now := time.Now()
+	c.SetClock(func() time.Time { return now })
+	svc := services.NewFXService(market, c)
+
+	market.On("GetLatestQuotes", []string{"ETH"}, []int(nil), "GBP").Return([]CoinMarketCap.IQuote{{Symbol: "ETH", Price: "2500.5"}}, nil).Once()
+
+	for range 2 {
+		amount, err := svc.Value(context.Background(), "eth", big.NewRat(3, 2), "GBP")
+		assert.NoError(t, err)
+		assert.Equal(t, "3750.75", amount.Value)
+	}
+
+	// Past the TTL, the stale price is served while it is refreshed, even when upstream fails
+	now = now.Add(services.RatesPolicy.TTL + time.Second)
+	market.On("GetLatestQuotes", []string{"ETH"}, []int(nil), "GBP").Return(nil, errors.New("quota exceeded")).Once()
+	price, err := svc.Price(context.Background(), "ETH", "GBP")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, price.Cmp(big.NewRat(5001, 2)))
+	c.Wait()
+	market.AssertExpectations(t)
+}
+
+func TestFXService_Errors(t *testing.T) {
+	market := new(testmocks.MockCoinMarketCapService)
+	svc := services.NewFXService(market, nil)
+
+	_, err := svc.Rate(context.Background(), "USD", "XYZ")
+	var validationErr *app_errors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "currency", validationErr.Field)
+
+	market.On("GetLatestQuotes", []string{"DOGE"}, []int(nil), "USD").Return([]CoinMarketCap.IQuote{}, nil).Once()
+	_, err = svc.Price(context.Background(), "DOGE", "USD")
+	var appErr *app_errors.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, 502, appErr.Status)
+}
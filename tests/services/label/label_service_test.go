@@ -0,0 +1,190 @@
+package tests
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	UserModel "cry-api/app/models"
+	services "cry-api/app/services/label"
+	app_errors "cry-api/app/types/errors"
+	LabelTypes "cry-api/app/types/label"
+	WalletExplorer "cry-api/app/types/wallet_explorer"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const exampleTxID = "f91d0a8a78462bc59398f2c5d7a84fcff491c26ba54c4833478b202796c8aafd"
+
+// bip329Example holds the records of the example file from BIP-329
+var bip329Example = []string{
+	`{"type":"tx","ref":"f91d0a8a78462bc59398f2c5d7a84fcff491c26ba54c4833478b202796c8aafd","label":"Transaction","origin":"wpkh([d34db33f/84'/0'/0'])"}`,
+	`{"type":"addr","ref":"bc1q34aq5drpuwy3wgl9lhup9892qp6svr8ldzyy7c","label":"Address"}`,
+	`{"type":"pubkey","ref":"0283409659355b6d1cc3c32decd5d561abaac86c37a353b52895a5e6c196d6f448","label":"Public Key"}`,
+	`{"type":"input","ref":"f91d0a8a78462bc59398f2c5d7a84fcff491c26ba54c4833478b202796c8aafd:0","label":"Input"}`,
+	`{"type":"output","ref":"f91d0a8a78462bc59398f2c5d7a84fcff491c26ba54c4833478b202796c8aafd:1","label":"Output","spendable":false}`,
+	`{"type":"xpub","ref":"xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8","label":"Extended Public Key"}`,
+}
+
+func newLabelService() (*services.LabelService, *testmocks.MockLabelRepository) {
+	repo := new(testmocks.MockLabelRepository)
+	return services.NewLabelService(repo), repo
+}
+
+func TestImportExportLabels_RoundTrip(t *testing.T) {
+	svc, repo := newLabelService()
+
+	existing := UserModel.Label{ID: 1, UserID: 7, Type: "tx", Ref: exampleTxID, Label: "old"}
+	repo.On("FindByUserID", 7, "").Return([]UserModel.Label{existing}, nil).Once()
+
+	var saved []UserModel.Label
+	repo.On("Save", mock.AnythingOfType("*models.Label")).Run(func(args mock.Arguments) {
+		saved = append(saved, *args.Get(0).(*UserModel.Label))
+	}).Return(nil)
+
+	input := strings.Join(append(append([]string{}, bip329Example...),
+		`{"type":"tx","ref":"not-a-txid","label":"broken"}`,
+		`not json`,
+		`{"type":"future","ref":"x","label":"unknown type"}`,
+		``,
+	), "\n")
+
+	result, err := svc.ImportLabels(7, strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Equal(t, 5, result.Imported)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, 1, result.Skipped)
+	assert.Equal(t, []LabelTypes.IImportError{
+		{Line: 7, Error: "Transaction id must be 64 hex characters"},
+		{Line: 8, Error: "Invalid JSON"},
+	}, result.Errors)
+
+	assert.Len(t, saved, 6)
+	assert.Equal(t, 1, saved[0].ID)
+	assert.Equal(t, "Transaction", saved[0].Label)
+
+	// Exporting what was imported gives back the BIP-329 example
+	repo.On("FindByUserID", 7, "").Return(saved, nil).Once()
+	var buf bytes.Buffer
+	assert.NoError(t, svc.ExportLabels(7, &buf))
+	assert.Equal(t, strings.Join(bip329Example, "\n")+"\n", buf.String())
+}
+
+func TestImportLabels_TruncatesLongLabels(t *testing.T) {
+	svc, repo := newLabelService()
+	repo.On("FindByUserID", 7, "").Return([]UserModel.Label{}, nil).Once()
+	repo.On("Save", mock.MatchedBy(func(label *UserModel.Label) bool {
+		return len(label.Label) == services.MaxLabelLength
+	})).Return(nil).Once()
+
+	line := `{"type":"tx","ref":"` + exampleTxID + `","label":"` + strings.Repeat("a", 300) + `"}`
+	result, err := svc.ImportLabels(7, strings.NewReader(line))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Imported)
+	repo.AssertExpectations(t)
+}
+
+func TestSetLabel(t *testing.T) {
+	t.Run("Creates a label with a canonical reference", func(t *testing.T) {
+		svc, repo := newLabelService()
+		ref := strings.ToUpper(exampleTxID) + ":01"
+		repo.On("FindByRef", 7, "output", exampleTxID+":1").Return(nil, nil).Once()
+		repo.On("Save", mock.AnythingOfType("*models.Label")).Return(nil).Once()
+
+		spendable := true
+		label, err := svc.SetLabel(7, LabelTypes.ILabelRecord{Type: "OUTPUT", Ref: ref, Label: " change ", Spendable: &spendable})
+		assert.NoError(t, err)
+		assert.Equal(t, exampleTxID+":1", label.Ref)
+		assert.Equal(t, "change", label.Label)
+		assert.True(t, *label.Spendable)
+	})
+
+	t.Run("Replaces an existing label", func(t *testing.T) {
+		svc, repo := newLabelService()
+		existing := &UserModel.Label{ID: 4, UserID: 7, Type: "addr", Ref: "bc1q34aq5drpuwy3wgl9lhup9892qp6svr8ldzyy7c", Label: "old"}
+		repo.On("FindByRef", 7, "addr", existing.Ref).Return(existing, nil).Once()
+		repo.On("Save", existing).Return(nil).Once()
+
+		spendable := false
+		label, err := svc.SetLabel(7, LabelTypes.ILabelRecord{Type: "addr", Ref: existing.Ref, Label: "savings", Spendable: &spendable})
+		assert.NoError(t, err)
+		assert.Equal(t, 4, label.ID)
+		assert.Equal(t, "savings", label.Label)
+		assert.Nil(t, label.Spendable, "spendable only applies to outputs")
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		svc, _ := newLabelService()
+		for _, req := range []LabelTypes.ILabelRecord{
+			{Type: "wallet", Ref: exampleTxID},
+			{Type: "addr", Ref: "1NotAnAddress"},
+			{Type: "input", Ref: exampleTxID},
+			{Type: "pubkey", Ref: "02abcd"},
+			{Type: "xpub", Ref: "xpub123"},
+			{Type: "tx", Ref: exampleTxID, Label: strings.Repeat("a", 256)},
+		} {
+			_, err := svc.SetLabel(7, req)
+			var validationErr *app_errors.ValidationError
+			assert.ErrorAs(t, err, &validationErr, "%+v", req)
+		}
+	})
+}
+
+func TestAnnotateTransaction(t *testing.T) {
+	svc, repo := newLabelService()
+
+	spent := "bc1q34aq5drpuwy3wgl9lhup9892qp6svr8ldzyy7c"
+	paid := "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu"
+	zero, one := 0, 1
+	tx := &WalletExplorer.ITransactionData{
+		Hash:   exampleTxID,
+		Inputs: []WalletExplorer.Input{{PrevOut: &WalletExplorer.PrevOut{Addr: &spent}}},
+		Out: []WalletExplorer.Output{
+			{N: &zero, Addr: &paid},
+			{N: &one},
+		},
+	}
+
+	repo.On("FindByRefs", 7, []string{exampleTxID, spent, exampleTxID + ":0", paid, exampleTxID + ":1"}).Return([]UserModel.Label{
+		{Type: "tx", Ref: exampleTxID, Label: "rent"},
+		{Type: "addr", Ref: spent, Label: "savings"},
+		{Type: "output", Ref: exampleTxID + ":1", Label: "change"},
+		// An output label on the address ref must not leak onto the address
+		{Type: "output", Ref: paid, Label: "wrong"},
+	}, nil).Once()
+
+	assert.NoError(t, svc.AnnotateTransaction(7, tx))
+	assert.Equal(t, "rent", tx.Label)
+	assert.Equal(t, "savings", tx.Inputs[0].AddressLabel)
+	assert.Empty(t, tx.Out[0].AddressLabel)
+	assert.Empty(t, tx.Out[0].Label)
+	assert.Equal(t, "change", tx.Out[1].Label)
+}
+
+func TestAnnotateXPUB(t *testing.T) {
+	svc, repo := newLabelService()
+
+	data := &WalletExplorer.ITransactionXPUB{
+		Transactions: []WalletExplorer.XPUBTransaction{{TxID: exampleTxID}, {TxID: "other"}},
+	}
+	repo.On("FindByRefs", 7, []string{"zpub", exampleTxID, "other"}).Return([]UserModel.Label{
+		{Type: "xpub", Ref: "zpub", Label: "Cold storage"},
+		{Type: "tx", Ref: exampleTxID, Label: "deposit"},
+	}, nil).Once()
+
+	assert.NoError(t, svc.AnnotateXPUB(7, "zpub", data))
+	assert.Equal(t, "Cold storage", data.Label)
+	assert.Equal(t, "deposit", data.Transactions[0].Label)
+	assert.Empty(t, data.Transactions[1].Label)
+}
+
+func TestDeleteLabel_NotFound(t *testing.T) {
+	svc, repo := newLabelService()
+	repo.On("Delete", 9, 7).Return(false, nil).Once()
+
+	err := svc.DeleteLabel(7, 9)
+	var notFound *app_errors.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+}
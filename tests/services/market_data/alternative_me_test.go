@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	services "cry-api/app/services/market_data"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAlternativeMeServer(t *testing.T, body string) (*httptest.Server, *[]string) {
+	var limits []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/fng/", r.URL.Path)
+		limits = append(limits, r.URL.Query().Get("limit"))
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server, &limits
+}
+
+func TestAlternativeMeProvider_GetFearAndGreedLastest(t *testing.T) {
+	server, _ := newAlternativeMeServer(t, `{"data":[{"value":"72","value_classification":"Greed","timestamp":"1729209600"}],"metadata":{"error":null}}`)
+	provider := services.NewAlternativeMeProvider(server.URL, server.Client())
+
+	latest, err := provider.GetFearAndGreedLastest(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 72, latest.Data.Value)
+	assert.Equal(t, "Greed", latest.Data.ValueClassification)
+	assert.Equal(t, int64(1729209600), latest.Data.UpdateTime.Unix())
+	assert.Equal(t, services.AlternativeMeName, latest.Source)
+}
+
+func TestAlternativeMeProvider_GetFearAndGreedHistoricalSkipsToStart(t *testing.T) {
+	server, limits := newAlternativeMeServer(t, `{"data":[
+		{"value":"72","value_classification":"Greed","timestamp":"1729209600"},
+		{"value":"40","value_classification":"Fear","timestamp":"1729123200"},
+		{"value":"20","value_classification":"Extreme Fear","timestamp":"1729036800"}
+	]}`)
+	provider := services.NewAlternativeMeProvider(server.URL, server.Client())
+
+	page, err := provider.GetFearAndGreedHistorical(context.Background(), 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3"}, *limits)
+	require.Len(t, page.Data, 2)
+	assert.Equal(t, 40, page.Data[0].Value)
+	assert.Equal(t, "1729036800", page.Data[1].Timestamp)
+	assert.Equal(t, services.AlternativeMeName, page.Source)
+
+	_, err = provider.GetLatestQuotes(context.Background(), []string{"BTC"}, nil, "USD")
+	assert.ErrorIs(t, err, services.ErrUnsupported, "alternative.me has no prices")
+}
+
+func TestAlternativeMeProvider_ReportsAPIErrors(t *testing.T) {
+	server, _ := newAlternativeMeServer(t, `{"data":[],"metadata":{"error":"rate limited"}}`)
+	provider := services.NewAlternativeMeProvider(server.URL, server.Client())
+
+	_, err := provider.GetFearAndGreedLastest(context.Background())
+	assert.EqualError(t, err, "alternative.me error: rate limited")
+}
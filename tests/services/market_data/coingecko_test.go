@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	services "cry-api/app/services/market_data"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCoinGeckoServer serves the CoinGecko endpoints used by the provider. Two assets share
+// the BTC symbol; the best ranked one is bitcoin.
+func newCoinGeckoServer(t *testing.T) (*httptest.Server, *[]string) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		assert.Equal(t, "demo-key", r.Header.Get("x-cg-demo-api-key"))
+
+		switch r.URL.Path {
+		case "/coins/markets":
+			_, _ = w.Write([]byte(`[
+				{"id":"ethereum","symbol":"eth","name":"Ethereum","current_price":2400,"market_cap_rank":2},
+				{"id":"batcat","symbol":"btc","name":"Batcat","current_price":0.01,"market_cap_rank":3000},
+				{"id":"bitcoin","symbol":"btc","name":"Bitcoin","current_price":60000,"market_cap":1.2e12,"market_cap_rank":1,
+				 "price_change_percentage_24h_in_currency":-1.5,"last_updated":"2024-10-18T12:00:00Z"}
+			]`))
+		case "/coins/bitcoin/market_chart/range":
+			assert.Equal(t, "eur", r.URL.Query().Get("vs_currency"))
+			_, _ = w.Write([]byte(`{
+				"prices":[[1729036800000,100],[1729080000000,120],[1729123199000,90],[1729123200000,110],[1729166400000,130]],
+				"market_caps":[[1729036800000,1000],[1729123199000,900],[1729166400000,1300]]
+			}`))
+		case "/simple/price":
+			assert.Equal(t, "bitcoin", r.URL.Query().Get("ids"))
+			_, _ = w.Write([]byte(`{"bitcoin":{"eur":60000,"last_updated_at":1729252800}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &paths
+}
+
+func TestCoinGeckoProvider_GetLatestQuotes(t *testing.T) {
+	server, _ := newCoinGeckoServer(t)
+	provider := services.NewCoinGeckoProvider(server.URL, "demo-key", server.Client())
+
+	quotes, err := provider.GetLatestQuotes(context.Background(), []string{"btc", "eth"}, nil, "eur")
+	require.NoError(t, err)
+	require.Len(t, quotes, 2)
+	assert.Equal(t, "BTC", quotes[0].Symbol)
+	assert.Equal(t, "bitcoin", quotes[0].Slug)
+	assert.Equal(t, "60000", quotes[0].Price)
+	assert.Equal(t, "-1.5", quotes[0].PercentChange24h)
+	assert.Equal(t, "EUR", quotes[0].Currency)
+	assert.Equal(t, services.CoinGeckoName, quotes[0].Source)
+	assert.Equal(t, "ETH", quotes[1].Symbol)
+
+	_, err = provider.GetLatestQuotes(context.Background(), nil, []int{1}, "eur")
+	assert.ErrorIs(t, err, services.ErrUnsupported)
+}
+
+func TestCoinGeckoProvider_GetOHLCVBuildsCandlesFromPrices(t *testing.T) {
+	server, _ := newCoinGeckoServer(t)
+	provider := services.NewCoinGeckoProvider(server.URL, "demo-key", server.Client())
+	from := time.Date(2024, 10, 16, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 10, 17, 0, 0, 0, 0, time.UTC)
+
+	ohlcv, err := provider.GetOHLCV(context.Background(), "btc", "eur", CoinMarketCap.CandleDaily, from, to)
+	require.NoError(t, err)
+	assert.Equal(t, services.CoinGeckoName, ohlcv.Source)
+	require.Len(t, ohlcv.Candles, 2)
+
+	first := ohlcv.Candles[0]
+	assert.Equal(t, from, first.TimeOpen)
+	assert.Equal(t, from.AddDate(0, 0, 1).Add(-time.Millisecond), first.TimeClose)
+	assert.Equal(t, []string{"100", "120", "90", "90"}, []string{first.Open, first.High, first.Low, first.Close})
+	assert.Equal(t, "900", first.MarketCap)
+	assert.Equal(t, []string{"110", "130", "110", "130"}, []string{ohlcv.Candles[1].Open, ohlcv.Candles[1].High, ohlcv.Candles[1].Low, ohlcv.Candles[1].Close})
+}
+
+func TestCoinGeckoProvider_GetHistoricalPricesKeepsTheLastSampleOfEachDay(t *testing.T) {
+	server, paths := newCoinGeckoServer(t)
+	provider := services.NewCoinGeckoProvider(server.URL, "demo-key", server.Client())
+
+	// The id of BTC is resolved once, from the quotes
+	_, err := provider.GetLatestQuotes(context.Background(), []string{"BTC", "ETH"}, nil, "EUR")
+	require.NoError(t, err)
+
+	points, err := provider.GetHistoricalPrices(context.Background(), "BTC", "EUR",
+		time.Date(2024, 10, 16, 0, 0, 0, 0, time.UTC), time.Date(2024, 10, 17, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, []CoinMarketCap.IPricePoint{{Date: "2024-10-16", Price: big.NewRat(90, 1)}, {Date: "2024-10-17", Price: big.NewRat(130, 1)}}, points)
+	assert.Equal(t, []string{"/coins/markets", "/coins/bitcoin/market_chart/range"}, *paths)
+}
+
+func TestCoinGeckoProvider_ConvertPrice(t *testing.T) {
+	server, _ := newCoinGeckoServer(t)
+	provider := services.NewCoinGeckoProvider(server.URL, "demo-key", server.Client())
+	_, err := provider.GetLatestQuotes(context.Background(), []string{"BTC", "ETH"}, nil, "EUR")
+	require.NoError(t, err)
+
+	conversion, err := provider.ConvertPrice(context.Background(), big.NewRat(1, 2), "btc", "eur")
+	require.NoError(t, err)
+	assert.Equal(t, "60000", conversion.Rate)
+	assert.Equal(t, "30000.00", conversion.Value.Value)
+	assert.Equal(t, time.Unix(1729252800, 0).UTC(), conversion.LastUpdated)
+	assert.Equal(t, services.CoinGeckoName, conversion.Source)
+}
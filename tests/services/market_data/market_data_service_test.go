@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"testing"
+
+	CoinMarketCapService "cry-api/app/services/coin_market_cap"
+	services "cry-api/app/services/market_data"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+	EnvTypes "cry-api/app/types/env"
+	app_errors "cry-api/app/types/errors"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// namedProvider gives a mocked CoinMarketCap service a provider name
+type namedProvider struct {
+	*testmocks.MockCoinMarketCapService
+	name string
+}
+
+func (p namedProvider) Name() string {
+	return p.name
+}
+
+func newProvider(name string) namedProvider {
+	return namedProvider{MockCoinMarketCapService: new(testmocks.MockCoinMarketCapService), name: name}
+}
+
+func TestNewProviders_KeepsTheConfiguredOrder(t *testing.T) {
+	cfg := &EnvTypes.EnvConfig{MarketDataConfig: EnvTypes.MarketDataConfig{
+		Providers: []string{services.AlternativeMeName, CoinMarketCapService.ProviderName, services.CoinGeckoName},
+	}}
+
+	providers := services.NewProviders(cfg, http.DefaultClient)
+	require.Len(t, providers, 3)
+	assert.Equal(t, services.AlternativeMeName, providers[0].Name())
+	assert.Equal(t, CoinMarketCapService.ProviderName, providers[1].Name())
+	assert.Equal(t, services.CoinGeckoName, providers[2].Name())
+}
+
+func TestMarketDataService_FallsBackToTheNextProvider(t *testing.T) {
+	primary, secondary := newProvider("primary"), newProvider("secondary")
+	primary.On("GetLatestQuotes", []string{"BTC"}, []int(nil), "USD").
+		Return(nil, app_errors.NewAppError(http.StatusBadGateway, "Failed to fetch quotes", "quota exceeded"))
+	secondary.On("GetLatestQuotes", []string{"BTC"}, []int(nil), "USD").
+		Return([]CoinMarketCap.IQuote{{Symbol: "BTC", Price: "65000", Source: "secondary"}}, nil)
+
+	svc := services.NewMarketDataService([]services.MarketDataProvider{primary, secondary})
+	quotes, err := svc.GetLatestQuotes(context.Background(), []string{"BTC"}, nil, "USD")
+	require.NoError(t, err)
+	require.Len(t, quotes, 1)
+	assert.Equal(t, "secondary", quotes[0].Source)
+	primary.AssertExpectations(t)
+	secondary.AssertExpectations(t)
+}
+
+func TestMarketDataService_SkipsUnsupportedProviders(t *testing.T) {
+	prices, index := newProvider("prices"), newProvider("index")
+	prices.On("GetFearAndGreedLastest").Return(nil, services.ErrUnsupported)
+	index.On("GetFearAndGreedLastest").Return(nil, errors.New("timeout"))
+
+	svc := services.NewMarketDataService([]services.MarketDataProvider{prices, index})
+	_, err := svc.GetFearAndGreedLastest(context.Background())
+	require.Error(t, err)
+	assert.EqualError(t, err, "timeout", "the only failure is returned as is")
+}
+
+func TestMarketDataService_ReturnsValidationErrorsAtOnce(t *testing.T) {
+	primary, secondary := newProvider("primary"), newProvider("secondary")
+	primary.On("ConvertPrice", big.NewRat(-1, 1), "BTC", "USD").
+		Return(nil, app_errors.NewValidationError("amount", "-1", "amount must be positive"))
+
+	svc := services.NewMarketDataService([]services.MarketDataProvider{primary, secondary})
+	_, err := svc.ConvertPrice(context.Background(), big.NewRat(-1, 1), "BTC", "USD")
+	var validationErr *app_errors.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	secondary.AssertNotCalled(t, "ConvertPrice", big.NewRat(-1, 1), "BTC", "USD")
+}
+
+func TestMarketDataService_ReportsEveryFailure(t *testing.T) {
+	primary, secondary := newProvider("primary"), newProvider("secondary")
+	primary.On("GetFearAndGreedHistorical", 1, 10).Return(nil, errors.New("quota exceeded"))
+	secondary.On("GetFearAndGreedHistorical", 1, 10).Return(nil, errors.New("timeout"))
+
+	svc := services.NewMarketDataService([]services.MarketDataProvider{primary, secondary})
+	_, err := svc.GetFearAndGreedHistorical(context.Background(), 1, 10)
+	var appErr *app_errors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadGateway, appErr.Status)
+	assert.Equal(t, app_errors.CodeUpstreamError, appErr.Code)
+	assert.Empty(t, appErr.Details)
+}
+
+func TestMarketDataService_WithoutAProviderForTheData(t *testing.T) {
+	index := newProvider("index")
+	index.On("GetLatestQuotes", []string{"BTC"}, []int(nil), "USD").Return(nil, services.ErrUnsupported)
+
+	for _, providers := range [][]services.MarketDataProvider{nil, {index}} {
+		svc := services.NewMarketDataService(providers)
+		_, err := svc.GetLatestQuotes(context.Background(), []string{"BTC"}, nil, "USD")
+		var appErr *app_errors.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusServiceUnavailable, appErr.Status)
+	}
+}
@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	UserModel "cry-api/app/models"
+	services "cry-api/app/services/notification"
+	app_errors "cry-api/app/types/errors"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNotificationService_ListNotifications(t *testing.T) {
+	repo := new(testmocks.MockNotificationRepository)
+	svc := services.NewNotificationService(repo)
+
+	repo.On("FindByUserID", 7, true, services.FeedLimit).Return(nil, nil).Once()
+	notifications, err := svc.ListNotifications(7, true)
+	assert.NoError(t, err)
+	assert.NotNil(t, notifications)
+	assert.Empty(t, notifications)
+
+	repo.On("FindByUserID", 7, false, services.FeedLimit).Return(nil, errors.New("db down")).Once()
+	_, err = svc.ListNotifications(7, false)
+	assert.Equal(t, app_errors.ErrDatabaseError, err)
+}
+
+func TestNotificationService_MarkRead(t *testing.T) {
+	t.Run("Not found", func(t *testing.T) {
+		repo := new(testmocks.MockNotificationRepository)
+		repo.On("FindByIDAndUserID", 3, 7).Return(nil, nil).Once()
+
+		_, err := services.NewNotificationService(repo).MarkRead(7, 3)
+
+		var notFound *app_errors.NotFoundError
+		assert.ErrorAs(t, err, &notFound)
+	})
+
+	t.Run("Marks once", func(t *testing.T) {
+		repo := new(testmocks.MockNotificationRepository)
+		svc := services.NewNotificationService(repo)
+		notification := &UserModel.Notification{ID: 3, UserID: 7}
+		repo.On("FindByIDAndUserID", 3, 7).Return(notification, nil).Twice()
+		repo.On("Save", notification).Return(nil).Once()
+
+		marked, err := svc.MarkRead(7, 3)
+		assert.NoError(t, err)
+		assert.NotNil(t, marked.ReadAt)
+		first := *marked.ReadAt
+
+		marked, err = svc.MarkRead(7, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, first, *marked.ReadAt)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestNotificationService_MarkAllRead(t *testing.T) {
+	repo := new(testmocks.MockNotificationRepository)
+	repo.On("MarkAllRead", 7, mock.AnythingOfType("time.Time")).Return(int64(4), nil).Once()
+
+	count, err := services.NewNotificationService(repo).MarkAllRead(7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), count)
+}
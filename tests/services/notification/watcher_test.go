@@ -0,0 +1,370 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	UserModel "cry-api/app/models"
+	repositorie "cry-api/app/repositories"
+	services "cry-api/app/services/notification"
+	EnvTypes "cry-api/app/types/env"
+	WatchlistTypes "cry-api/app/types/watchlist"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const feedLink = "https://app.test/notifications"
+
+type watcherFixture struct {
+	db           *gorm.DB
+	cfg          *EnvTypes.EnvConfig
+	wallet       UserModel.WatchedWallet
+	watchlist    *testmocks.MockWatchlistService
+	transactions *testmocks.MockTransactionService
+	email        *testmocks.MockEmailService
+}
+
+func newWatcherFixture(t *testing.T) *watcherFixture {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	require.NoError(t, db.AutoMigrate(&UserModel.User{}, &UserModel.WatchedWallet{}, &UserModel.Notification{}, &UserModel.WalletCursor{}))
+
+	user := UserModel.User{UUID: "user-uuid", Username: "satoshi", Email: "satoshi@example.com", Password: "x", IsVerified: true}
+	require.NoError(t, db.Create(&user).Error)
+	wallet := UserModel.WatchedWallet{UserID: user.ID, Kind: UserModel.WatchedWalletKindAddress, Value: "bc1qexample", Label: "Savings", Color: "#8b5cf6"}
+	require.NoError(t, db.Create(&wallet).Error)
+
+	return &watcherFixture{
+		db: db,
+		cfg: &EnvTypes.EnvConfig{
+			CryAppURL:    "https://app.test",
+			NoReplyEmail: "no-reply@test.com",
+			WatcherConfig: EnvTypes.WatcherConfig{
+				Interval:          60,
+				Confirmations:     3,
+				LargeMovementSats: 100000000,
+			},
+		},
+		wallet:       wallet,
+		watchlist:    new(testmocks.MockWatchlistService),
+		transactions: new(testmocks.MockTransactionService),
+		email:        new(testmocks.MockEmailService),
+	}
+}
+
+// watcher returns a new watcher over the fixture's database, as after a restart
+func (f *watcherFixture) watcher() *services.WatcherService {
+	return services.NewWatcherService(
+		f.cfg,
+		repositorie.NewGormWatchedWalletRepository(f.db),
+		repositorie.NewGormWalletCursorRepository(f.db),
+		repositorie.NewGormNotificationRepository(f.db),
+		repositorie.NewGormUserRepository(f.db),
+		f.watchlist,
+		f.transactions,
+		f.email,
+	)
+}
+
+func (f *watcherFixture) history(txs ...WatchlistTypes.IWatchlistActivity) {
+	f.watchlist.On("GetRecentWalletActivity", mock.Anything).Return(txs, nil).Once()
+}
+
+// fullHistory serves the whole history, read when the recent activity has none of the
+// transactions of the last check
+func (f *watcherFixture) fullHistory(txs ...WatchlistTypes.IWatchlistActivity) {
+	f.watchlist.On("GetWalletHistory", mock.Anything).Return(txs, nil).Once()
+}
+
+func (f *watcherFixture) expectEmail(title, message string) {
+	f.email.On("SendWalletNotificationEmail", "satoshi@example.com", "no-reply@test.com", "satoshi", title, message, feedLink).Return(nil).Once()
+}
+
+func (f *watcherFixture) cursor(t *testing.T) UserModel.WalletCursor {
+	var cursor UserModel.WalletCursor
+	require.NoError(t, f.db.Where("watched_wallet_id = ?", f.wallet.ID).First(&cursor).Error)
+	return cursor
+}
+
+func tx(txid string, height int, time int64, diff float64) WatchlistTypes.IWatchlistActivity {
+	return WatchlistTypes.IWatchlistActivity{TxID: txid, BlockHeight: height, Time: time, BalanceDiff: diff}
+}
+
+func eventKeys(notifications []UserModel.Notification) []string {
+	var out []string
+	for _, n := range notifications {
+		out = append(out, n.TxID+":"+n.Type)
+	}
+	return out
+}
+
+func TestWatcher_FirstCheckOnlyRecordsCursor(t *testing.T) {
+	f := newWatcherFixture(t)
+	f.history(tx("old", 90, 1000, 0.2), tx("older", 80, 900, -0.1))
+
+	delivered, err := f.watcher().CheckWallet(context.Background(), &f.wallet, 100)
+
+	require.NoError(t, err)
+	assert.Empty(t, delivered)
+	cursor := f.cursor(t)
+	assert.Equal(t, 100, cursor.TipHeight)
+	assert.Equal(t, "old,older", cursor.LastTxIDs)
+	f.email.AssertNotCalled(t, "SendWalletNotificationEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWatcher_NotifiesNewAndConfirmedTransactions(t *testing.T) {
+	f := newWatcherFixture(t)
+	watcher := f.watcher()
+	ctx := context.Background()
+
+	f.history(tx("old", 95, 1000, 0.2))
+	_, err := watcher.CheckWallet(ctx, &f.wallet, 100)
+	require.NoError(t, err)
+
+	// A transaction seen in the same second as the last one is still new
+	f.history(tx("old", 95, 1000, 0.2), tx("same-second", 0, 1000, 0.01), tx("incoming", 0, 2000, 0.5))
+	f.expectEmail("Received 0.01 BTC on Savings", "Transaction same-second sent 0.01 BTC to Savings. It is not confirmed yet.")
+	f.expectEmail("Received 0.5 BTC on Savings", "Transaction incoming sent 0.5 BTC to Savings. It is not confirmed yet.")
+	delivered, err := watcher.CheckWallet(ctx, &f.wallet, 100)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"same-second:incoming", "incoming:incoming"}, eventKeys(delivered))
+
+	// Mined at 101, the transaction reaches 3 confirmations at 103
+	f.history(tx("old", 95, 1000, 0.2), tx("same-second", 101, 1000, 0.01), tx("incoming", 101, 2000, 0.5))
+	delivered, err = watcher.CheckWallet(ctx, &f.wallet, 102)
+	require.NoError(t, err)
+	assert.Empty(t, delivered)
+
+	f.history(tx("old", 95, 1000, 0.2), tx("same-second", 101, 1000, 0.01), tx("incoming", 101, 2000, 0.5))
+	f.expectEmail("Transaction confirmed on Savings", "Transaction same-second of Savings reached 3 confirmations in block 101.")
+	f.expectEmail("Transaction confirmed on Savings", "Transaction incoming of Savings reached 3 confirmations in block 101.")
+	delivered, err = watcher.CheckWallet(ctx, &f.wallet, 103)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"same-second:confirmed", "incoming:confirmed"}, eventKeys(delivered))
+
+	var feed []UserModel.Notification
+	require.NoError(t, f.db.Order("id").Find(&feed).Error)
+	require.Len(t, feed, 4)
+	assert.Equal(t, 0.5, feed[1].Amount)
+	assert.NotNil(t, feed[1].EmailedAt)
+	assert.Nil(t, feed[1].ReadAt)
+	assert.Equal(t, 101, feed[3].BlockHeight)
+	f.email.AssertExpectations(t)
+}
+
+func TestWatcher_NotifiesTransactionsWithAnEarlierBlockTime(t *testing.T) {
+	f := newWatcherFixture(t)
+	watcher := f.watcher()
+
+	f.history(tx("old", 95, 1000, 0.2))
+	_, err := watcher.CheckWallet(context.Background(), &f.wallet, 100)
+	require.NoError(t, err)
+
+	// Block times are not monotonic: the next block can be timestamped before the last one
+	f.history(tx("earlier", 101, 990, 0.3), tx("old", 95, 1000, 0.2))
+	f.expectEmail("Received 0.3 BTC on Savings", "Transaction earlier sent 0.3 BTC to Savings. It was confirmed in block 101.")
+	delivered, err := watcher.CheckWallet(context.Background(), &f.wallet, 101)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"earlier:incoming"}, eventKeys(delivered))
+	f.email.AssertExpectations(t)
+}
+
+func TestWatcher_ReadsTheWholeHistoryAfterABurst(t *testing.T) {
+	f := newWatcherFixture(t)
+	watcher := f.watcher()
+
+	f.history(tx("old", 95, 1000, 0.2), tx("older", 90, 900, 0.1))
+	_, err := watcher.CheckWallet(context.Background(), &f.wallet, 100)
+	require.NoError(t, err)
+
+	// Three transactions arrived and the recent activity only holds two of them
+	f.history(tx("b", 101, 2100, 0.02), tx("c", 0, 0, 0.03))
+	f.fullHistory(tx("older", 90, 900, 0.1), tx("old", 95, 1000, 0.2), tx("a", 101, 2000, 0.01), tx("b", 101, 2100, 0.02), tx("c", 0, 0, 0.03))
+	f.email.On("SendWalletNotificationEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Times(3)
+	delivered, err := watcher.CheckWallet(context.Background(), &f.wallet, 101)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a:incoming", "b:incoming", "c:incoming"}, eventKeys(delivered))
+	assert.Equal(t, "b,c", f.cursor(t).LastTxIDs)
+	f.watchlist.AssertExpectations(t)
+}
+
+func TestWatcher_OutgoingLargeMovement(t *testing.T) {
+	f := newWatcherFixture(t)
+	watcher := f.watcher()
+
+	f.history()
+	_, err := watcher.CheckWallet(context.Background(), &f.wallet, 100)
+	require.NoError(t, err)
+
+	f.history(tx("spend", 100, 3000, -1.5))
+	f.fullHistory(tx("spend", 100, 3000, -1.5))
+	f.expectEmail("Sent 1.5 BTC from Savings", "Transaction spend spent 1.5 BTC from Savings. It was confirmed in block 100.")
+	f.expectEmail("Large movement of 1.5 BTC on Savings", "Transaction spend changed the balance of Savings by -1.5 BTC.")
+	delivered, err := watcher.CheckWallet(context.Background(), &f.wallet, 100)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"spend:outgoing", "spend:large_movement"}, eventKeys(delivered))
+	f.email.AssertExpectations(t)
+}
+
+func TestWatcher_NoDuplicatesAcrossRestarts(t *testing.T) {
+	f := newWatcherFixture(t)
+
+	f.history(tx("old", 95, 1000, 0.2))
+	_, err := f.watcher().CheckWallet(context.Background(), &f.wallet, 100)
+	require.NoError(t, err)
+	stale := f.cursor(t)
+
+	f.history(tx("old", 95, 1000, 0.2), tx("incoming", 0, 2000, 0.5))
+	f.expectEmail("Received 0.5 BTC on Savings", "Transaction incoming sent 0.5 BTC to Savings. It is not confirmed yet.")
+	delivered, err := f.watcher().CheckWallet(context.Background(), &f.wallet, 100)
+	require.NoError(t, err)
+	require.Len(t, delivered, 1)
+
+	// The process stopped before the cursor was saved: the next instance sees the transaction
+	// again but does not notify it twice
+	require.NoError(t, f.db.Save(&stale).Error)
+	f.history(tx("old", 95, 1000, 0.2), tx("incoming", 0, 2000, 0.5))
+	delivered, err = f.watcher().CheckWallet(context.Background(), &f.wallet, 100)
+
+	require.NoError(t, err)
+	assert.Empty(t, delivered)
+	assert.Equal(t, "old,incoming", f.cursor(t).LastTxIDs)
+	var count int64
+	f.db.Model(&UserModel.Notification{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+	f.email.AssertExpectations(t)
+}
+
+func TestWatcher_UnverifiedUserGetsFeedOnly(t *testing.T) {
+	f := newWatcherFixture(t)
+	require.NoError(t, f.db.Model(&UserModel.User{}).Where("id = ?", f.wallet.UserID).Update("is_verified", false).Error)
+	watcher := f.watcher()
+
+	f.history()
+	_, err := watcher.CheckWallet(context.Background(), &f.wallet, 100)
+	require.NoError(t, err)
+
+	f.history(tx("incoming", 0, 2000, 0.5))
+	f.fullHistory(tx("incoming", 0, 2000, 0.5))
+	delivered, err := watcher.CheckWallet(context.Background(), &f.wallet, 100)
+
+	require.NoError(t, err)
+	require.Len(t, delivered, 1)
+	assert.Nil(t, delivered[0].EmailedAt)
+	f.email.AssertNotCalled(t, "SendWalletNotificationEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWatcher_EmailFailureKeepsNotification(t *testing.T) {
+	f := newWatcherFixture(t)
+	watcher := f.watcher()
+
+	f.history()
+	_, err := watcher.CheckWallet(context.Background(), &f.wallet, 100)
+	require.NoError(t, err)
+
+	f.history(tx("incoming", 0, 2000, 0.5))
+	f.fullHistory(tx("incoming", 0, 2000, 0.5))
+	f.email.On("SendWalletNotificationEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(errors.New("smtp down")).Once()
+	delivered, err := watcher.CheckWallet(context.Background(), &f.wallet, 100)
+
+	require.NoError(t, err)
+	require.Len(t, delivered, 1)
+	assert.Nil(t, delivered[0].EmailedAt)
+	assert.Equal(t, "incoming", f.cursor(t).LastTxIDs)
+}
+
+func TestWatcher_CheckAll(t *testing.T) {
+	t.Run("Chain tip unavailable", func(t *testing.T) {
+		f := newWatcherFixture(t)
+		f.transactions.On("GetBlockHeight").Return(0, errors.New("upstream down")).Once()
+
+		err := f.watcher().CheckAll(context.Background())
+
+		assert.ErrorContains(t, err, "failed to fetch the chain tip")
+	})
+
+	t.Run("Failing wallet does not stop the others", func(t *testing.T) {
+		f := newWatcherFixture(t)
+		other := UserModel.WatchedWallet{UserID: f.wallet.UserID, Kind: UserModel.WatchedWalletKindAddress, Value: "bc1qother", Label: "Spending", Color: "#8b5cf6"}
+		require.NoError(t, f.db.Create(&other).Error)
+
+		f.transactions.On("GetBlockHeight").Return(100, nil).Once()
+		f.watchlist.On("GetRecentWalletActivity", mock.MatchedBy(func(w *UserModel.WatchedWallet) bool { return w.ID == f.wallet.ID })).
+			Return(nil, errors.New("upstream down")).Once()
+		f.watchlist.On("GetRecentWalletActivity", mock.MatchedBy(func(w *UserModel.WatchedWallet) bool { return w.ID == other.ID })).
+			Return([]WatchlistTypes.IWatchlistActivity{tx("a", 99, 1000, 0.1)}, nil).Once()
+
+		require.NoError(t, f.watcher().CheckAll(context.Background()))
+
+		var cursors []UserModel.WalletCursor
+		require.NoError(t, f.db.Find(&cursors).Error)
+		require.Len(t, cursors, 1)
+		assert.Equal(t, other.ID, cursors[0].WatchedWalletID)
+		f.watchlist.AssertExpectations(t)
+	})
+}
+
+func TestWatcher_RunStopsWithContext(t *testing.T) {
+	f := newWatcherFixture(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	f.transactions.On("GetBlockHeight").Return(100, nil).Run(func(mock.Arguments) { cancel() }).Once()
+	f.watchlist.On("GetRecentWalletActivity", mock.Anything).Return([]WatchlistTypes.IWatchlistActivity{}, nil).Maybe()
+
+	done := make(chan struct{})
+	go func() {
+		f.watcher().Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after the context was canceled")
+	}
+	f.transactions.AssertExpectations(t)
+}
+
+func TestWatcher_RunDisabled(t *testing.T) {
+	f := newWatcherFixture(t)
+	f.cfg.WatcherConfig.Interval = 0
+
+	f.watcher().Run(context.Background())
+
+	f.transactions.AssertNotCalled(t, "GetBlockHeight")
+}
+
+func TestWatcher_PublishesDeliveredNotifications(t *testing.T) {
+	f := newWatcherFixture(t)
+	watcher := f.watcher()
+	publisher := new(testmocks.MockPublisher)
+	watcher.SetPublisher(publisher)
+
+	f.history()
+	_, err := watcher.CheckWallet(context.Background(), &f.wallet, 100)
+	require.NoError(t, err)
+
+	f.history(tx("incoming", 0, 2000, 0.5))
+	f.fullHistory(tx("incoming", 0, 2000, 0.5))
+	f.expectEmail("Received 0.5 BTC on Savings", "Transaction incoming sent 0.5 BTC to Savings. It is not confirmed yet.")
+	publisher.On("PublishToUser", f.wallet.UserID, "wallets", mock.MatchedBy(func(n UserModel.Notification) bool {
+		return n.TxID == "incoming" && n.ID > 0 && n.EmailedAt != nil
+	})).Once()
+	_, err = watcher.CheckWallet(context.Background(), &f.wallet, 100)
+	require.NoError(t, err)
+	publisher.AssertExpectations(t)
+}
@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	services "cry-api/app/services/portfolio"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+	app_errors "cry-api/app/types/errors"
+	WatchlistTypes "cry-api/app/types/watchlist"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var today = func() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}()
+
+// daysAgo returns a unix timestamp at noon, n days before today
+func daysAgo(n int) int64 {
+	return today.AddDate(0, 0, -n).Add(12 * time.Hour).Unix()
+}
+
+// priceHistory returns 10000 per BTC until 10 days ago and 20000 afterwards
+func priceHistory() []CoinMarketCap.IPricePoint {
+	var points []CoinMarketCap.IPricePoint
+	for n := 60; n >= 0; n-- {
+		price := big.NewRat(10000, 1)
+		if n < 10 {
+			price = big.NewRat(20000, 1)
+		}
+		points = append(points, CoinMarketCap.IPricePoint{Date: today.AddDate(0, 0, -n).Format("2006-01-02"), Price: price})
+	}
+	return points
+}
+
+func TestGetValuation_ValueCostBasisAndPnL(t *testing.T) {
+	watchlist := new(testmocks.MockWatchlistService)
+	market := new(testmocks.MockCoinMarketCapService)
+	svc := services.NewPortfolioService(watchlist, market)
+
+	// The transfer between the user's own wallets was already merged into its 0.0001 BTC fee
+	watchlist.On("GetHistory", 7).Return(&WatchlistTypes.IWatchlistHistory{
+		Transactions: []WatchlistTypes.IWatchlistActivity{
+			{TxID: "buy", Time: daysAgo(40), BalanceDiff: 1.0},
+			{TxID: "move", Time: daysAgo(5), BalanceDiff: -0.0001},
+			{TxID: "in", Time: daysAgo(2), BalanceDiff: 0.2},
+		},
+	}, nil).Once()
+	market.On("GetHistoricalPrices", "BTC", "EUR", mock.Anything, mock.Anything).Return(priceHistory(), nil).Once()
+
+	valuation, err := svc.GetValuation(context.Background(), 7, "eur", 10)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "EUR", valuation.Currency)
+	assert.Len(t, valuation.Points, 10)
+	assert.Equal(t, today.AddDate(0, 0, -9).Format("2006-01-02"), valuation.From)
+	assert.InDelta(t, 1.0, valuation.Points[0].Balance, 1e-9)
+	assert.Equal(t, "20000.00", valuation.Points[0].Value.Value)
+	assert.Equal(t, "20000.00", valuation.Points[0].Price.Value)
+
+	assert.InDelta(t, 1.1999, valuation.Balance, 1e-9)
+	assert.Equal(t, "23998.00", valuation.Value.Value)
+	assert.Equal(t, int64(2399800), valuation.Value.MinorUnits)
+	assert.Equal(t, "13999.00", valuation.CostBasis.Value)
+	assert.Equal(t, "9999.00", valuation.UnrealizedPnL.Value)
+	assert.Equal(t, "20000.00", valuation.Price.Value)
+	assert.InDelta(t, 71.43, *valuation.UnrealizedPnLPercent, 1e-9)
+	assert.Empty(t, valuation.Warnings)
+}
+
+func TestGetValuation_ExactMinorUnits(t *testing.T) {
+	watchlist := new(testmocks.MockWatchlistService)
+	market := new(testmocks.MockCoinMarketCapService)
+	svc := services.NewPortfolioService(watchlist, market)
+
+	// Three buys of 0.1 BTC sum to 0.30000000000000004 in float64
+	watchlist.On("GetHistory", 7).Return(&WatchlistTypes.IWatchlistHistory{
+		Transactions: []WatchlistTypes.IWatchlistActivity{
+			{TxID: "a", Time: daysAgo(3), BalanceDiff: 0.1},
+			{TxID: "b", Time: daysAgo(2), BalanceDiff: 0.1},
+			{TxID: "c", Time: daysAgo(1), BalanceDiff: 0.1},
+		},
+	}, nil).Once()
+	market.On("GetHistoricalPrices", "BTC", "JPY", mock.Anything, mock.Anything).Return([]CoinMarketCap.IPricePoint{
+		{Date: today.AddDate(0, 0, -3).Format("2006-01-02"), Price: big.NewRat(19999999, 2)},
+	}, nil).Once()
+
+	valuation, err := svc.GetValuation(context.Background(), 7, "JPY", 1)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 0.3, valuation.Balance)
+	assert.Equal(t, "3000000", valuation.Value.Value) // 2999999.85 rounded to whole yen
+	assert.Equal(t, "3000000", valuation.CostBasis.Value)
+	assert.Equal(t, "0", valuation.UnrealizedPnL.Value)
+}
+
+func TestGetValuation_ReportsFailingWallets(t *testing.T) {
+	watchlist := new(testmocks.MockWatchlistService)
+	market := new(testmocks.MockCoinMarketCapService)
+	svc := services.NewPortfolioService(watchlist, market)
+
+	watchlist.On("GetHistory", 7).Return(&WatchlistTypes.IWatchlistHistory{
+		Transactions: []WatchlistTypes.IWatchlistActivity{},
+		Warnings:     []string{"Broken: upstream down"},
+	}, nil).Once()
+	market.On("GetHistoricalPrices", "BTC", "USD", mock.Anything, mock.Anything).Return(priceHistory(), nil).Once()
+
+	valuation, err := svc.GetValuation(context.Background(), 7, "", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", valuation.Currency)
+	assert.Len(t, valuation.Points, services.DefaultDays)
+	assert.Equal(t, []string{"Broken: upstream down"}, valuation.Warnings)
+	assert.Equal(t, int64(0), valuation.Value.MinorUnits)
+	assert.Nil(t, valuation.UnrealizedPnLPercent)
+}
+
+func TestGetValuation_Validation(t *testing.T) {
+	svc := services.NewPortfolioService(new(testmocks.MockWatchlistService), new(testmocks.MockCoinMarketCapService))
+
+	_, err := svc.GetValuation(context.Background(), 7, "euro", 10)
+	var validationErr *app_errors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "currency", validationErr.Field)
+
+	_, err = svc.GetValuation(context.Background(), 7, "XYZ", 10)
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "currency", validationErr.Field)
+
+	_, err = svc.GetValuation(context.Background(), 7, "USD", services.MaxDays+1)
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "days", validationErr.Field)
+}
+
+func TestGetValuation_PriceFailure(t *testing.T) {
+	watchlist := new(testmocks.MockWatchlistService)
+	market := new(testmocks.MockCoinMarketCapService)
+	svc := services.NewPortfolioService(watchlist, market)
+
+	watchlist.On("GetHistory", 7).Return(&WatchlistTypes.IWatchlistHistory{}, nil).Once()
+	market.On("GetHistoricalPrices", "BTC", "USD", mock.Anything, mock.Anything).Return(nil, errors.New("quota exceeded")).Once()
+
+	_, err := svc.GetValuation(context.Background(), 7, "USD", 5)
+	var appErr *app_errors.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, 502, appErr.Status)
+}
@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"testing"
+
+	services "cry-api/app/services/realtime"
+	EnvTypes "cry-api/app/types/env"
+	app_errors "cry-api/app/types/errors"
+	RealtimeTypes "cry-api/app/types/realtime"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRealtimeConfig() *EnvTypes.EnvConfig {
+	return &EnvTypes.EnvConfig{
+		RealtimeConfig: EnvTypes.RealtimeConfig{HeartbeatInterval: 1, BufferSize: 2, MaxSubscriptions: 3, PriceInterval: 15},
+	}
+}
+
+// received drains the events queued for a client
+func received(client *services.Client) []RealtimeTypes.IEvent {
+	var events []RealtimeTypes.IEvent
+	for {
+		select {
+		case event := <-client.Events():
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestNormalizeTopic(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected string
+		valid    bool
+	}{
+		{"price:btc", "price:BTC", true},
+		{" PRICE:Eth ", "price:ETH", true},
+		{"Fear_Greed", "fear_greed", true},
+		{"wallets", "wallets", true},
+		{"alerts", "alerts", true},
+		{"price:", "", false},
+		{"price:BTC-USD", "", false},
+		{"news", "", false},
+	}
+
+	for _, tt := range tests {
+		topic, err := services.NormalizeTopic(tt.raw)
+		if !tt.valid {
+			var validation *app_errors.ValidationError
+			assert.ErrorAs(t, err, &validation, tt.raw)
+			continue
+		}
+		require.NoError(t, err, tt.raw)
+		assert.Equal(t, tt.expected, topic)
+	}
+}
+
+func TestHub_SubscriptionLimit(t *testing.T) {
+	hub := services.NewHub(newRealtimeConfig())
+	client := hub.Register(7)
+
+	topics, err := hub.Subscribe(client, []string{"price:btc", "price:BTC", "fear_greed"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"price:BTC", "fear_greed"}, topics, "duplicates are dropped")
+
+	// Subscribing again to a topic does not count twice
+	_, err = hub.Subscribe(client, []string{"price:BTC", "wallets"})
+	require.NoError(t, err)
+
+	_, err = hub.Subscribe(client, []string{"alerts"})
+	var validation *app_errors.ValidationError
+	require.ErrorAs(t, err, &validation)
+	assert.Equal(t, "A connection can subscribe to at most 3 topics", validation.Message)
+
+	_, err = hub.Unsubscribe(client, []string{"wallets"})
+	require.NoError(t, err)
+	_, err = hub.Subscribe(client, []string{"alerts"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alerts", "fear_greed", "price:BTC"}, hub.Topics(client))
+
+	_, err = hub.Subscribe(client, nil)
+	assert.ErrorAs(t, err, &validation)
+}
+
+func TestHub_FansEventsOut(t *testing.T) {
+	hub := services.NewHub(newRealtimeConfig())
+	alice, bob, bobElsewhere := hub.Register(1), hub.Register(2), hub.Register(2)
+	for _, client := range []*services.Client{alice, bob, bobElsewhere} {
+		_, err := hub.Subscribe(client, []string{"price:BTC", "wallets"})
+		require.NoError(t, err)
+	}
+	_, err := hub.Subscribe(alice, []string{"fear_greed"})
+	require.NoError(t, err)
+
+	hub.Publish("price:BTC", map[string]float64{"price": 70000})
+	hub.PublishToUser(2, services.TopicWallets, "bob's notification")
+	hub.PublishToUser(2, services.TopicAlerts, "nobody subscribed")
+
+	aliceEvents := received(alice)
+	require.Len(t, aliceEvents, 1)
+	assert.Equal(t, "price:BTC", aliceEvents[0].Topic)
+	assert.Equal(t, RealtimeTypes.MessageEvent, aliceEvents[0].Type)
+
+	for _, client := range []*services.Client{bob, bobElsewhere} {
+		events := received(client)
+		require.Len(t, events, 2)
+		assert.Equal(t, "wallets", events[1].Topic)
+		assert.Equal(t, "bob's notification", events[1].Data)
+		assert.Greater(t, events[1].ID, events[0].ID)
+	}
+
+	assert.True(t, hub.HasSubscribers(services.TopicFearGreed))
+	assert.Equal(t, []string{"BTC"}, hub.SubscribedSymbols())
+	assert.Equal(t, RealtimeTypes.IHubStats{Connections: 3, Subscriptions: 7}, hub.Stats())
+
+	hub.Unregister(alice)
+	assert.False(t, hub.HasSubscribers(services.TopicFearGreed))
+	assert.Equal(t, "", alice.Reason())
+}
+
+func TestHub_ReplaysTheLastSharedEvent(t *testing.T) {
+	hub := services.NewHub(newRealtimeConfig())
+	hub.Publish(services.TopicFearGreed, 25)
+	hub.Publish(services.TopicFearGreed, 30)
+
+	client := hub.Register(1)
+	_, err := hub.Subscribe(client, []string{"fear_greed"})
+	require.NoError(t, err)
+
+	events := received(client)
+	require.Len(t, events, 1)
+	assert.Equal(t, 30, events[0].Data)
+}
+
+func TestHub_DropsSlowConnections(t *testing.T) {
+	hub := services.NewHub(newRealtimeConfig())
+	slow, fast := hub.Register(1), hub.Register(2)
+	for _, client := range []*services.Client{slow, fast} {
+		_, err := hub.Subscribe(client, []string{"price:BTC"})
+		require.NoError(t, err)
+	}
+
+	hub.Publish("price:BTC", 1)
+	hub.Publish("price:BTC", 2)
+	received(fast)
+	hub.Publish("price:BTC", 3)
+
+	select {
+	case <-slow.Done():
+	default:
+		t.Fatal("the connection with a full buffer should be dropped")
+	}
+	assert.NotEmpty(t, slow.Reason())
+	assert.Len(t, received(fast), 1)
+	assert.Equal(t, uint64(1), hub.Stats().Dropped)
+
+	select {
+	case <-fast.Done():
+		t.Fatal("the connection keeping up should stay open")
+	default:
+	}
+}
+
+func TestHub_CloseDropsEveryConnection(t *testing.T) {
+	hub := services.NewHub(newRealtimeConfig())
+	alice := hub.Register(1)
+	bob := hub.Register(2)
+
+	hub.Close()
+	for _, client := range []*services.Client{alice, bob} {
+		select {
+		case <-client.Done():
+		default:
+			t.Fatal("every connection should be dropped")
+		}
+		assert.Equal(t, services.ReasonShutdown, client.Reason())
+	}
+}
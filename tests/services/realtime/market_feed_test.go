@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	services "cry-api/app/services/realtime"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarketFeed_PublishesSubscribedChanges(t *testing.T) {
+	cfg := newRealtimeConfig()
+	cfg.RealtimeConfig.BufferSize = 8
+	hub := services.NewHub(cfg)
+	market := new(testmocks.MockCoinMarketCapService)
+	feed := services.NewMarketFeedService(cfg, hub, market)
+	ctx := context.Background()
+
+	// Nothing is fetched without subscribers
+	feed.Tick(ctx)
+
+	client := hub.Register(1)
+	_, err := hub.Subscribe(client, []string{"price:ETH", "price:BTC", "fear_greed"})
+	require.NoError(t, err)
+
+	updated := time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC)
+	quotes := []CoinMarketCap.IQuote{
+		{Symbol: "BTC", Price: "70000", LastUpdated: updated},
+		{Symbol: "ETH", Price: "2500", LastUpdated: updated},
+	}
+	index := &CoinMarketCap.FearGreedData{Data: CoinMarketCap.FearGreedEntry{Value: 40, ValueClassification: "Fear", UpdateTime: updated}}
+	market.On("GetLatestQuotes", []string{"BTC", "ETH"}, []int(nil), "USD").Return(quotes, nil).Once()
+	market.On("GetFearAndGreedLastest").Return(index, nil).Once()
+
+	feed.Tick(ctx)
+	events := received(client)
+	require.Len(t, events, 3)
+	assert.Equal(t, "price:BTC", events[0].Topic)
+	assert.Equal(t, "price:ETH", events[1].Topic)
+	assert.Equal(t, "fear_greed", events[2].Topic)
+
+	// Only what changed is published again
+	moved := []CoinMarketCap.IQuote{quotes[0], {Symbol: "ETH", Price: "2510", LastUpdated: updated.Add(time.Minute)}}
+	market.On("GetLatestQuotes", []string{"BTC", "ETH"}, []int(nil), "USD").Return(moved, nil).Once()
+	market.On("GetFearAndGreedLastest").Return(index, nil).Once()
+
+	feed.Tick(ctx)
+	events = received(client)
+	require.Len(t, events, 1)
+	assert.Equal(t, "price:ETH", events[0].Topic)
+	assert.Equal(t, "2510", events[0].Data.(CoinMarketCap.IQuote).Price)
+
+	// Failures are retried on the next tick
+	market.On("GetLatestQuotes", []string{"BTC", "ETH"}, []int(nil), "USD").Return(nil, errors.New("quota exceeded")).Once()
+	market.On("GetFearAndGreedLastest").Return(nil, errors.New("timeout")).Once()
+	feed.Tick(ctx)
+	assert.Empty(t, received(client))
+	market.AssertExpectations(t)
+}
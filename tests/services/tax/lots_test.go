@@ -0,0 +1,130 @@
+package tests
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	services "cry-api/app/services/tax"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func day(s string) time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return t
+}
+
+func lotEvents() []services.Event {
+	return []services.Event{
+		{TxID: "a", Time: day("2022-01-10"), Sats: 100000000, Price: big.NewRat(10000, 1)},
+		{TxID: "b", Time: day("2023-03-01"), Sats: 100000000, Price: big.NewRat(30000, 1)},
+		{TxID: "c", Time: day("2023-06-01"), Sats: 100000000, Price: big.NewRat(20000, 1)},
+		{TxID: "d", Time: day("2023-07-01"), Sats: -150000000, Price: big.NewRat(25000, 1)},
+	}
+}
+
+func TestMatchLots_Methods(t *testing.T) {
+	cases := []struct {
+		method   services.Method
+		acquired []string
+		gains    []string
+		terms    []string
+	}{
+		{services.MethodFIFO, []string{"a", "b"}, []string{"15000.00", "-2500.00"}, []string{"long", "short"}},
+		{services.MethodLIFO, []string{"c", "b"}, []string{"5000.00", "-2500.00"}, []string{"short", "short"}},
+		{services.MethodHIFO, []string{"b", "c"}, []string{"-5000.00", "2500.00"}, []string{"short", "short"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(string(tc.method), func(t *testing.T) {
+			disposals, unmatched, err := services.MatchLots(lotEvents(), tc.method, "USD")
+			assert.NoError(t, err)
+			assert.False(t, unmatched)
+			assert.Len(t, disposals, 2)
+			for i, d := range disposals {
+				assert.Equal(t, "d", d.TxID)
+				assert.Equal(t, tc.acquired[i], d.AcquiredTx)
+				assert.Equal(t, tc.gains[i], d.Gain.Value)
+				assert.Equal(t, tc.terms[i], d.Term)
+			}
+			assert.Equal(t, 1.0, disposals[0].Amount)
+			assert.Equal(t, 0.5, disposals[1].Amount)
+		})
+	}
+}
+
+func TestMatchLots_TransferIsNotADisposal(t *testing.T) {
+	events := []services.Event{
+		{TxID: "a", Time: day("2023-01-01"), Sats: 100000000, Price: big.NewRat(10000, 1)},
+		{TxID: "move", Time: day("2023-02-01"), Sats: -40000000, Price: big.NewRat(20000, 1), Transfer: true},
+		{TxID: "sell", Time: day("2023-03-01"), Sats: -60000000, Price: big.NewRat(30000, 1)},
+	}
+
+	disposals, unmatched, err := services.MatchLots(events, services.MethodFIFO, "USD")
+	assert.NoError(t, err)
+	assert.False(t, unmatched)
+	assert.Len(t, disposals, 1)
+	assert.Equal(t, "sell", disposals[0].TxID)
+	assert.Equal(t, "18000.00", disposals[0].Proceeds.Value)
+	assert.Equal(t, "6000.00", disposals[0].CostBasis.Value)
+}
+
+func TestMatchLots_Unmatched(t *testing.T) {
+	events := []services.Event{
+		{TxID: "a", Time: day("2023-01-01"), Sats: 50000000, Price: big.NewRat(10000, 1)},
+		{TxID: "sell", Time: day("2023-03-01"), Sats: -100000000, Price: big.NewRat(30000, 1)},
+	}
+
+	disposals, unmatched, err := services.MatchLots(events, services.MethodFIFO, "USD")
+	assert.NoError(t, err)
+	assert.True(t, unmatched)
+	assert.Len(t, disposals, 2)
+	assert.True(t, disposals[1].Unmatched)
+	assert.Equal(t, "0.00", disposals[1].CostBasis.Value)
+	assert.Equal(t, "15000.00", disposals[1].Gain.Value)
+}
+
+func TestMatchLots_TransferCarriesLots(t *testing.T) {
+	events := []services.Event{
+		{TxID: "buy", Time: day("2022-01-10"), Sats: 100000000, Price: big.NewRat(10000, 1)},
+		{TxID: "move", Time: day("2022-06-01"), Sats: 99990000, Price: big.NewRat(20000, 1), Transfer: true},
+		{TxID: "move", Time: day("2022-06-01"), Sats: -100000000, Price: big.NewRat(20000, 1), Transfer: true},
+		{TxID: "sell", Time: day("2023-03-01"), Sats: -99990000, Price: big.NewRat(30000, 1)},
+	}
+
+	disposals, unmatched, err := services.MatchLots(events, services.MethodFIFO, "USD")
+	assert.NoError(t, err)
+	assert.False(t, unmatched)
+	assert.Len(t, disposals, 2)
+
+	// Only the network fee of the transfer is disposed of
+	fee := disposals[0]
+	assert.Equal(t, "move", fee.TxID)
+	assert.Equal(t, 0.0001, fee.Amount)
+	assert.Equal(t, "2.00", fee.Proceeds.Value)
+	assert.Equal(t, "1.00", fee.CostBasis.Value)
+
+	// The received coins keep their acquisition, more than a year before the sale
+	sale := disposals[1]
+	assert.Equal(t, "buy", sale.AcquiredTx)
+	assert.Equal(t, "2022-01-10", sale.Acquired)
+	assert.Equal(t, services.TermLong, sale.Term)
+	assert.Equal(t, "9999.00", sale.CostBasis.Value)
+	assert.Equal(t, 415, sale.HoldingDays)
+}
+
+func TestMatchLots_RoundsToMinorUnits(t *testing.T) {
+	events := []services.Event{
+		{TxID: "buy", Time: day("2023-01-01"), Sats: 100000000, Price: big.NewRat(3000000, 1)},
+		{TxID: "sell", Time: day("2023-03-01"), Sats: -33333, Price: big.NewRat(4500000, 1)},
+	}
+
+	disposals, _, err := services.MatchLots(events, services.MethodFIFO, "JPY")
+	assert.NoError(t, err)
+	assert.Len(t, disposals, 1)
+	assert.Equal(t, "1500", disposals[0].Proceeds.Value)
+	assert.Equal(t, "1000", disposals[0].CostBasis.Value)
+	assert.Equal(t, int64(500), disposals[0].Gain.MinorUnits)
+	assert.Equal(t, "JPY", disposals[0].Gain.Currency)
+}
@@ -0,0 +1,200 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+
+	UserModel "cry-api/app/models"
+	"cry-api/app/money"
+	services "cry-api/app/services/tax"
+	WatchlistService "cry-api/app/services/watchlist"
+	CoinMarketCap "cry-api/app/types/coin_market_cap"
+	app_errors "cry-api/app/types/errors"
+	TaxTypes "cry-api/app/types/tax"
+	WatchlistTypes "cry-api/app/types/watchlist"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const moveTxID = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+
+func newTaxService() (*services.TaxService, *testmocks.MockTransferTagRepository, *testmocks.MockWatchlistService, *testmocks.MockCoinMarketCapService) {
+	repo := new(testmocks.MockTransferTagRepository)
+	watchlist := new(testmocks.MockWatchlistService)
+	market := new(testmocks.MockCoinMarketCapService)
+	return services.NewTaxService(repo, watchlist, market), repo, watchlist, market
+}
+
+// totals builds the expected totals of a year from amounts in minor units
+func totals(currency string, proceeds, costBasis, gain int64) TaxTypes.IGainTotals {
+	amount := func(minor int64) money.Amount {
+		a, _ := money.FromMinor(minor, currency)
+		return a
+	}
+	return TaxTypes.IGainTotals{Proceeds: amount(proceeds), CostBasis: amount(costBasis), Gain: amount(gain)}
+}
+
+func TestGetReport_YearsAndTerms(t *testing.T) {
+	svc, repo, watchlist, market := newTaxService()
+
+	wallets := []UserModel.WatchedWallet{{ID: 1, UserID: 1}}
+	watchlist.On("ListWallets", 1).Return(wallets, nil).Once()
+	watchlist.On("GetWalletHistory", &wallets[0]).Return([]WatchlistTypes.IWatchlistActivity{
+		{TxID: "buy", Time: day("2022-01-10").Unix(), BalanceDiff: 1},
+		{TxID: moveTxID, Time: day("2022-06-01").Unix(), BalanceDiff: -0.25},
+		{TxID: "sell1", Time: day("2022-12-01").Unix(), BalanceDiff: -0.25},
+		{TxID: "sell2", Time: day("2023-02-01").Unix(), BalanceDiff: -0.5},
+	}, nil).Once()
+	repo.On("FindByUserID", 1).Return([]UserModel.TransferTag{{TxID: moveTxID}}, nil).Once()
+	market.On("GetHistoricalPrices", "BTC", "EUR", mock.Anything, mock.Anything).Return([]CoinMarketCap.IPricePoint{
+		{Date: "2022-01-10", Price: big.NewRat(40000, 1)},
+		{Date: "2022-06-01", Price: big.NewRat(30000, 1)},
+		{Date: "2022-12-01", Price: big.NewRat(16000, 1)},
+		{Date: "2023-02-01", Price: big.NewRat(22000, 1)},
+	}, nil).Once()
+
+	report, err := svc.GetReport(context.Background(), 1, 0, "", "eur", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "fifo", report.Method)
+	assert.Equal(t, "EUR", report.Currency)
+	assert.Len(t, report.Years, 2)
+
+	y2022 := report.Years[0]
+	assert.Equal(t, 2022, y2022.Year)
+	assert.Len(t, y2022.Disposals, 1)
+	assert.Equal(t, totals("EUR", 400000, 1000000, -600000), y2022.ShortTerm)
+	assert.Equal(t, totals("EUR", 0, 0, 0), y2022.LongTerm)
+
+	y2023 := report.Years[1]
+	assert.Equal(t, totals("EUR", 1100000, 2000000, -900000), y2023.LongTerm)
+	assert.Equal(t, y2023.LongTerm, y2023.Total)
+
+	var buf bytes.Buffer
+	assert.NoError(t, services.WriteReportCSV(&buf, report))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 3)
+	assert.Equal(t, strings.Join(services.CSVHeader, ","), lines[0])
+	assert.Equal(t, "2023,sell2,buy,2022-01-10,2023-02-01,0.50000000,11000.00,20000.00,-9000.00,long,387", lines[2])
+}
+
+func TestGetReport_SingleYearAndWallet(t *testing.T) {
+	svc, repo, watchlist, market := newTaxService()
+
+	wallet := &UserModel.WatchedWallet{ID: 3, UserID: 1}
+	watchlist.On("GetWallet", 1, 3).Return(wallet, nil).Once()
+	watchlist.On("ListWallets", 1).Return([]UserModel.WatchedWallet{*wallet}, nil).Once()
+	watchlist.On("GetWalletHistory", wallet).Return([]WatchlistTypes.IWatchlistActivity{
+		{TxID: "buy", Time: day("2022-01-10").Unix(), BalanceDiff: 1},
+		{TxID: "sell1", Time: day("2022-12-01").Unix(), BalanceDiff: -0.5},
+		{TxID: "sell2", Time: day("2023-02-01").Unix(), BalanceDiff: -0.5},
+	}, nil).Once()
+	repo.On("FindByUserID", 1).Return([]UserModel.TransferTag{}, nil).Once()
+	market.On("GetHistoricalPrices", "BTC", "USD", mock.Anything, mock.Anything).Return([]CoinMarketCap.IPricePoint{
+		{Date: "2022-01-10", Price: big.NewRat(40000, 1)},
+	}, nil).Once()
+
+	report, err := svc.GetReport(context.Background(), 1, 3, "hifo", "", 2023)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, *report.WalletID)
+	assert.Len(t, report.Years, 1)
+	assert.Equal(t, 2023, report.Years[0].Year)
+}
+
+func TestGetReport_TransferBetweenWatchedWallets(t *testing.T) {
+	svc, repo, watchlist, market := newTaxService()
+
+	wallets := []UserModel.WatchedWallet{{ID: 1, UserID: 1, Label: "hot"}, {ID: 2, UserID: 1, Label: "cold"}}
+	watchlist.On("GetWallet", 1, 2).Return(&wallets[1], nil).Once()
+	watchlist.On("ListWallets", 1).Return(wallets, nil).Once()
+	watchlist.On("GetWalletHistory", &wallets[0]).Return([]WatchlistTypes.IWatchlistActivity{
+		{TxID: "buy", Time: day("2022-01-10").Unix(), BalanceDiff: 1},
+		{TxID: moveTxID, Time: day("2022-06-01").Unix(), BalanceDiff: -1},
+	}, nil).Once()
+	watchlist.On("GetWalletHistory", &wallets[1]).Return([]WatchlistTypes.IWatchlistActivity{
+		{TxID: moveTxID, Time: day("2022-06-01").Unix(), BalanceDiff: 0.9999},
+		{TxID: "sell", Time: day("2023-02-01").Unix(), BalanceDiff: -0.9999},
+	}, nil).Once()
+	repo.On("FindByUserID", 1).Return([]UserModel.TransferTag{{TxID: moveTxID}}, nil).Once()
+	market.On("GetHistoricalPrices", "BTC", "USD", mock.Anything, mock.Anything).Return([]CoinMarketCap.IPricePoint{
+		{Date: "2022-01-10", Price: big.NewRat(40000, 1)},
+		{Date: "2022-06-01", Price: big.NewRat(30000, 1)},
+		{Date: "2023-02-01", Price: big.NewRat(22000, 1)},
+	}, nil).Once()
+
+	// The sale from the cold wallet keeps the acquisition of the hot wallet; the fee paid by the
+	// hot wallet is a disposal of its own and is left out of the cold wallet's report
+	report, err := svc.GetReport(context.Background(), 1, 2, "", "", 0)
+	assert.NoError(t, err)
+	assert.Len(t, report.Years, 1)
+	sale := report.Years[0].Disposals[0]
+	assert.Equal(t, "sell", sale.TxID)
+	assert.Equal(t, "buy", sale.AcquiredTx)
+	assert.Equal(t, services.TermLong, sale.Term)
+	assert.Equal(t, "39996.00", sale.CostBasis.Value)
+}
+
+func TestGetReport_IncompleteHistoryFailsTheReport(t *testing.T) {
+	svc, repo, watchlist, _ := newTaxService()
+
+	wallets := []UserModel.WatchedWallet{{ID: 1, UserID: 1, Label: "hot"}, {ID: 2, UserID: 1, Label: "busy"}}
+	watchlist.On("GetWallet", 1, 1).Return(&wallets[0], nil).Once()
+	watchlist.On("ListWallets", 1).Return(wallets, nil).Once()
+	watchlist.On("GetWalletHistory", &wallets[0]).Return([]WatchlistTypes.IWatchlistActivity{
+		{TxID: "buy", Time: day("2022-01-10").Unix(), BalanceDiff: 1},
+	}, nil).Once()
+	watchlist.On("GetWalletHistory", &wallets[1]).Return(nil, fmt.Errorf("%w: got 50 of 120 transactions", WatchlistService.ErrIncompleteHistory)).Once()
+	repo.On("FindByUserID", 1).Return([]UserModel.TransferTag{}, nil).Once()
+
+	_, err := svc.GetReport(context.Background(), 1, 1, "", "", 0)
+	var appErr *app_errors.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadGateway, appErr.Status)
+	assert.Contains(t, appErr.Details, "busy")
+}
+
+func TestGetReport_InvalidMethod(t *testing.T) {
+	svc, _, _, _ := newTaxService()
+
+	_, err := svc.GetReport(context.Background(), 1, 0, "average", "USD", 0)
+	var validationErr *app_errors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "method", validationErr.Field)
+}
+
+func TestTagTransfer(t *testing.T) {
+	svc, repo, _, _ := newTaxService()
+
+	repo.On("FindByTxID", 1, moveTxID).Return(nil, nil).Once()
+	repo.On("Save", mock.AnythingOfType("*models.TransferTag")).Return(nil).Once()
+
+	tag, err := svc.TagTransfer(1, TaxTypes.ITransferTagRequest{TxID: strings.ToUpper(moveTxID), Note: " to cold storage "})
+	assert.NoError(t, err)
+	assert.Equal(t, moveTxID, tag.TxID)
+	assert.Equal(t, "to cold storage", tag.Note)
+
+	_, err = svc.TagTransfer(1, TaxTypes.ITransferTagRequest{TxID: "nothex"})
+	var validationErr *app_errors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+
+	repo.On("FindByTxID", 1, moveTxID).Return(&UserModel.TransferTag{ID: 1}, nil).Once()
+	_, err = svc.TagTransfer(1, TaxTypes.ITransferTagRequest{TxID: moveTxID})
+	var conflictErr *app_errors.ConflictError
+	assert.ErrorAs(t, err, &conflictErr)
+}
+
+func TestUntagTransfer_NotFound(t *testing.T) {
+	svc, repo, _, _ := newTaxService()
+
+	repo.On("Delete", 9, 1).Return(false, nil).Once()
+
+	err := svc.UntagTransfer(1, 9)
+	var notFoundErr *app_errors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
@@ -0,0 +1,241 @@
+package tests
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"cry-api/app/bitcoin"
+	UserModel "cry-api/app/models"
+	services "cry-api/app/services/wallet_explorer"
+	app_errors "cry-api/app/types/errors"
+	WalletExplorer "cry-api/app/types/wallet_explorer"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var spentTxID = strings.Repeat("11", 32)
+
+// signedTx spends outputs 0 and 1 of spentTxID (50000 and 30000 sats) into a 79000 sats output
+func signedTx() *bitcoin.Tx {
+	var prev [32]byte
+	for i := range prev {
+		prev[i] = 0x11
+	}
+	scriptSig, _ := hex.DecodeString("47304402" + strings.Repeat("ab", 66) + "0121" + strings.Repeat("02", 33))
+	return &bitcoin.Tx{
+		Version: 2,
+		Inputs: []*bitcoin.TxIn{
+			{PrevTxID: prev, PrevIndex: 0, ScriptSig: scriptSig, Sequence: 0xfffffffd},
+			{PrevTxID: prev, PrevIndex: 1, ScriptSig: scriptSig, Sequence: 0xfffffffd},
+		},
+		Outputs: []*bitcoin.TxOut{{Value: 79000, Script: bitcoin.P2PKHScript(make([]byte, 20))}},
+	}
+}
+
+func txHex(tx *bitcoin.Tx) string {
+	return hex.EncodeToString(tx.Serialize(true))
+}
+
+// providerServer answers every broadcast with status and body, recording the posted payloads
+func providerServer(t *testing.T, status int, body string, payloads *[]string) string {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		*payloads = append(*payloads, r.URL.Path+" "+string(raw))
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+type broadcastFixture struct {
+	service *services.BroadcastService
+	mempool *testmocks.MockMempoolService
+	audits  *testmocks.MockBroadcastAuditRepository
+	saved   *UserModel.BroadcastAudit
+}
+
+func newBroadcastFixture(providers ...services.Broadcaster) *broadcastFixture {
+	f := &broadcastFixture{
+		mempool: new(testmocks.MockMempoolService),
+		audits:  new(testmocks.MockBroadcastAuditRepository),
+	}
+	f.audits.On("Save", mock.AnythingOfType("*models.BroadcastAudit")).Run(func(args mock.Arguments) {
+		f.saved = args.Get(0).(*UserModel.BroadcastAudit)
+	}).Return(nil).Once()
+	f.service = services.NewBroadcastService(providers, f.mempool, f.audits)
+	return f
+}
+
+// expectValidTx sets up the upstream lookups of a transaction that passes local validation
+func (f *broadcastFixture) expectValidTx(txid string, minimumFee float64) {
+	f.mempool.On("GetMempoolStatus", txid).Return(nil, app_errors.NewNotFoundError("transaction", "")).Once()
+	f.mempool.On("GetOutputValues", spentTxID).Return([]int64{50000, 30000}, nil).Once()
+	f.mempool.On("GetFeeEstimates").Return(&WalletExplorer.IFeeEstimates{Minimum: minimumFee}, nil).Once()
+}
+
+func TestBroadcastService_FallsBackToNextProvider(t *testing.T) {
+	tx := signedTx()
+	var mempoolPayloads, blockchainPayloads []string
+	f := newBroadcastFixture(
+		&services.MempoolBroadcaster{API: providerServer(t, http.StatusServiceUnavailable, "busy", &mempoolPayloads), Client: http.DefaultClient},
+		&services.BlockchainBroadcaster{API: providerServer(t, http.StatusOK, "Transaction Submitted", &blockchainPayloads), Client: http.DefaultClient},
+	)
+	f.expectValidTx(tx.TxID(), 1)
+
+	result, err := f.service.Broadcast(context.Background(), 7, "203.0.113.9", strings.ToUpper(txHex(tx)))
+	assert.NoError(t, err)
+	assert.Equal(t, tx.TxID(), result.TxID)
+	assert.Equal(t, "blockchain", result.Provider)
+	assert.Equal(t, int64(1000), result.Fee)
+	assert.Equal(t, tx.VSize(), result.VSize)
+
+	assert.Equal(t, []string{"/tx " + txHex(tx)}, mempoolPayloads)
+	assert.Equal(t, []string{"/pushtx " + url.Values{"tx": {txHex(tx)}}.Encode()}, blockchainPayloads)
+
+	assert.Equal(t, UserModel.BroadcastStatusBroadcast, f.saved.Status)
+	assert.Equal(t, 7, f.saved.UserID)
+	assert.Equal(t, tx.TxID(), f.saved.TxID)
+	assert.Equal(t, "blockchain", f.saved.Provider)
+	assert.Equal(t, "203.0.113.9", f.saved.IPAddress)
+	assert.Equal(t, result.FeeRate, f.saved.FeeRate)
+	f.mempool.AssertExpectations(t)
+	f.audits.AssertExpectations(t)
+}
+
+func TestBroadcastService_NodeRejectionIsNotRetried(t *testing.T) {
+	tx := signedTx()
+	var mempoolPayloads, blockchainPayloads []string
+	f := newBroadcastFixture(
+		&services.MempoolBroadcaster{API: providerServer(t, http.StatusBadRequest, "bad-txns-inputs-missingorspent", &mempoolPayloads), Client: http.DefaultClient},
+		&services.BlockchainBroadcaster{API: providerServer(t, http.StatusOK, "Transaction Submitted", &blockchainPayloads), Client: http.DefaultClient},
+	)
+	f.expectValidTx(tx.TxID(), 1)
+
+	_, err := f.service.Broadcast(context.Background(), 7, "", txHex(tx))
+	var invalid *app_errors.ValidationError
+	assert.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "Transaction rejected by mempool: bad-txns-inputs-missingorspent", invalid.Message)
+	assert.Empty(t, blockchainPayloads)
+
+	assert.Equal(t, UserModel.BroadcastStatusRejected, f.saved.Status)
+	assert.Equal(t, "mempool", f.saved.Provider)
+	assert.Contains(t, f.saved.Error, "bad-txns-inputs-missingorspent")
+}
+
+func TestBroadcastService_AllProvidersUnreachable(t *testing.T) {
+	tx := signedTx()
+	var payloads []string
+	f := newBroadcastFixture(
+		&services.MempoolBroadcaster{API: providerServer(t, http.StatusBadGateway, "", &payloads), Client: http.DefaultClient},
+		&services.BlockchainBroadcaster{API: providerServer(t, http.StatusInternalServerError, "", &payloads), Client: http.DefaultClient},
+	)
+	f.expectValidTx(tx.TxID(), 1)
+
+	_, err := f.service.Broadcast(context.Background(), 7, "", txHex(tx))
+	var appErr *app_errors.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadGateway, appErr.Status)
+	assert.Len(t, payloads, 2)
+
+	assert.Equal(t, UserModel.BroadcastStatusFailed, f.saved.Status)
+	assert.Contains(t, f.saved.Error, "mempool returned status 502; blockchain returned status 500")
+}
+
+func TestBroadcastService_RejectsFeeBelowMinimumRelayFee(t *testing.T) {
+	tx := signedTx()
+	var payloads []string
+	f := newBroadcastFixture(&services.MempoolBroadcaster{API: providerServer(t, http.StatusOK, tx.TxID(), &payloads), Client: http.DefaultClient})
+	f.expectValidTx(tx.TxID(), 5)
+
+	_, err := f.service.Broadcast(context.Background(), 7, "", txHex(tx))
+	var invalid *app_errors.ValidationError
+	assert.ErrorAs(t, err, &invalid)
+	assert.Contains(t, invalid.Message, "below the minimum relay fee of 5.00 sat/vB")
+	assert.Empty(t, payloads)
+	assert.Equal(t, UserModel.BroadcastStatusRejected, f.saved.Status)
+}
+
+func TestBroadcastService_RejectsConfirmedTransaction(t *testing.T) {
+	tx := signedTx()
+	f := newBroadcastFixture()
+	f.mempool.On("GetMempoolStatus", tx.TxID()).
+		Return(&WalletExplorer.IMempoolStatus{TxID: tx.TxID(), Status: WalletExplorer.MempoolStatusConfirmed, Confirmations: 3}, nil).Once()
+
+	_, err := f.service.Broadcast(context.Background(), 7, "", txHex(tx))
+	var conflict *app_errors.ConflictError
+	assert.ErrorAs(t, err, &conflict)
+	assert.Equal(t, UserModel.BroadcastStatusRejected, f.saved.Status)
+	f.mempool.AssertExpectations(t)
+}
+
+func TestBroadcastService_RejectsUnknownSpentOutput(t *testing.T) {
+	tx := signedTx()
+	tx.Inputs[1].PrevIndex = 4
+	f := newBroadcastFixture()
+	f.mempool.On("GetMempoolStatus", tx.TxID()).Return(nil, app_errors.NewNotFoundError("transaction", "")).Once()
+	f.mempool.On("GetOutputValues", spentTxID).Return([]int64{50000, 30000}, nil).Once()
+
+	_, err := f.service.Broadcast(context.Background(), 7, "", txHex(tx))
+	var invalid *app_errors.ValidationError
+	assert.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "Input 1 spends missing output "+spentTxID+":4", invalid.Message)
+}
+
+func TestBroadcastService_LocalValidation(t *testing.T) {
+	unsigned := signedTx()
+	unsigned.Inputs[0].ScriptSig = nil
+
+	testCases := []struct {
+		name    string
+		payload string
+		message string
+	}{
+		{"not hex", "zz", "Transaction must be a raw transaction hex"},
+		{"truncated", txHex(signedTx())[:40], "invalid transaction"},
+		{"psbt", "70736274ff01", "PSBTs must be finalized and extracted before broadcasting"},
+		{"unsigned input", txHex(unsigned), "Input 0 is not signed"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newBroadcastFixture()
+
+			_, err := f.service.Broadcast(context.Background(), 7, "", tc.payload)
+			var invalid *app_errors.ValidationError
+			assert.ErrorAs(t, err, &invalid)
+			assert.Contains(t, invalid.Message, tc.message)
+			assert.Equal(t, UserModel.BroadcastStatusRejected, f.saved.Status)
+			f.mempool.AssertNotCalled(t, "GetMempoolStatus", mock.Anything)
+		})
+	}
+}
+
+func TestBroadcastService_AuditFailureDoesNotFailBroadcast(t *testing.T) {
+	tx := signedTx()
+	var payloads []string
+	mempool := new(testmocks.MockMempoolService)
+	audits := new(testmocks.MockBroadcastAuditRepository)
+	audits.On("Save", mock.Anything).Return(errors.New("database is down")).Once()
+	svc := services.NewBroadcastService([]services.Broadcaster{
+		&services.MempoolBroadcaster{API: providerServer(t, http.StatusOK, tx.TxID(), &payloads), Client: http.DefaultClient},
+	}, mempool, audits)
+
+	mempool.On("GetMempoolStatus", tx.TxID()).Return(&WalletExplorer.IMempoolStatus{Status: WalletExplorer.MempoolStatusPending}, nil).Once()
+	mempool.On("GetOutputValues", spentTxID).Return([]int64{50000, 30000}, nil).Once()
+	mempool.On("GetFeeEstimates").Return(&WalletExplorer.IFeeEstimates{Minimum: 1}, nil).Once()
+
+	result, err := svc.Broadcast(context.Background(), 7, "", txHex(tx))
+	assert.NoError(t, err)
+	assert.Equal(t, "mempool", result.Provider)
+	audits.AssertExpectations(t)
+}
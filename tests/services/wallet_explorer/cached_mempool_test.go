@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cry-api/app/cache"
+	services "cry-api/app/services/wallet_explorer"
+	WalletExplorer "cry-api/app/types/wallet_explorer"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newCachedMempoolService returns the service, its upstream, a function moving the clock
+// forward and one waiting for the background refreshes of stale values
+func newCachedMempoolService() (*services.CachedMempoolService, *testmocks.MockMempoolService, func(time.Duration), func()) {
+	upstream := new(testmocks.MockMempoolService)
+	now := time.Now()
+	c := cache.New(cache.NewMemoryStore(100))
+	c.SetClock(func() time.Time { return now })
+	return services.NewCachedMempoolService(upstream, c), upstream, func(d time.Duration) { now = now.Add(d) }, c.Wait
+}
+
+func TestCachedMempoolService_FeesAndTipFollowTheChain(t *testing.T) {
+	svc, upstream, advance, wait := newCachedMempoolService()
+
+	upstream.On("GetFeeEstimates").Return(&WalletExplorer.IFeeEstimates{Fastest: 24}, nil).Once()
+	upstream.On("GetFeeEstimates").Return(&WalletExplorer.IFeeEstimates{Fastest: 30}, nil).Once()
+	upstream.On("GetTip").Return(&WalletExplorer.IBlockTip{Height: 868123}, nil).Once()
+
+	fees, err := svc.GetFeeEstimates(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, float64(24), fees.Fastest)
+
+	advance(20 * time.Second)
+	fees, _ = svc.GetFeeEstimates(context.Background())
+	assert.Equal(t, float64(24), fees.Fastest)
+	_, _ = svc.GetTip(context.Background())
+	_, _ = svc.GetTip(context.Background())
+
+	// Past the TTL the stale estimates are served while they are refreshed
+	advance(20 * time.Second)
+	fees, _ = svc.GetFeeEstimates(context.Background())
+	assert.Equal(t, float64(24), fees.Fastest)
+	wait()
+	fees, _ = svc.GetFeeEstimates(context.Background())
+	assert.Equal(t, float64(30), fees.Fastest)
+	upstream.AssertExpectations(t)
+}
+
+func TestCachedMempoolService_ConfirmedStatusIsKeptLonger(t *testing.T) {
+	svc, upstream, advance, wait := newCachedMempoolService()
+
+	upstream.On("GetMempoolStatus", "pending").Return(&WalletExplorer.IMempoolStatus{TxID: "pending", Status: WalletExplorer.MempoolStatusPending}, nil).Once()
+	upstream.On("GetMempoolStatus", "pending").Return(&WalletExplorer.IMempoolStatus{TxID: "pending", Status: WalletExplorer.MempoolStatusConfirmed, Confirmations: 1}, nil).Once()
+
+	status, err := svc.GetMempoolStatus(context.Background(), "pending")
+	assert.NoError(t, err)
+	assert.Equal(t, WalletExplorer.MempoolStatusPending, status.Status)
+
+	advance(20 * time.Second)
+	status, _ = svc.GetMempoolStatus(context.Background(), "pending")
+	assert.Equal(t, WalletExplorer.MempoolStatusPending, status.Status)
+	wait()
+	status, _ = svc.GetMempoolStatus(context.Background(), "pending")
+	assert.Equal(t, WalletExplorer.MempoolStatusConfirmed, status.Status)
+
+	advance(30 * time.Second)
+	status, _ = svc.GetMempoolStatus(context.Background(), "pending")
+	assert.Equal(t, 1, status.Confirmations)
+	upstream.AssertExpectations(t)
+}
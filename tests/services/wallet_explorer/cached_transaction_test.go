@@ -0,0 +1,166 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"cry-api/app/cache"
+	services "cry-api/app/services/wallet_explorer"
+	app_errors "cry-api/app/types/errors"
+	WalletExplorer "cry-api/app/types/wallet_explorer"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	deepTxID       = strings.Repeat("d1", 32)
+	shallowTxID    = strings.Repeat("5a", 32)
+	unconfTxID     = strings.Repeat("e0", 32)
+	historyZpub    = "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs"
+	historyAddress = "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu"
+)
+
+// newCachedTransactionService returns the service, its upstream, a function moving the clock
+// forward and one waiting for the background refreshes of stale values
+func newCachedTransactionService() (*services.CachedTransactionService, *testmocks.MockTransactionService, func(time.Duration), func()) {
+	upstream := new(testmocks.MockTransactionService)
+	now := time.Now()
+	c := cache.New(cache.NewMemoryStore(100))
+	c.SetClock(func() time.Time { return now })
+	return services.NewCachedTransactionService(upstream, c), upstream, func(d time.Duration) { now = now.Add(d) }, c.Wait
+}
+
+func TestCachedTransactionService_DeepTransactionIsImmutable(t *testing.T) {
+	svc, upstream, advance, _ := newCachedTransactionService()
+
+	upstream.On("GetTransactionByTxID", deepTxID).Return(&WalletExplorer.ITransactionData{Hash: deepTxID, BlockHeight: 800000}, nil).Once()
+	upstream.On("GetBlockHeight").Return(800010, nil).Once()
+
+	tx, err := svc.GetTransactionByTxID(context.Background(), deepTxID)
+	assert.NoError(t, err)
+	assert.Equal(t, deepTxID, tx.Hash)
+
+	advance(7 * 24 * time.Hour)
+	tx, err = svc.GetTransactionByTxID(context.Background(), deepTxID)
+	assert.NoError(t, err)
+	assert.Equal(t, 800000, tx.BlockHeight)
+	upstream.AssertExpectations(t)
+}
+
+func TestCachedTransactionService_ShallowTransactionIsRefreshed(t *testing.T) {
+	svc, upstream, advance, wait := newCachedTransactionService()
+
+	upstream.On("GetTransactionByTxID", shallowTxID).Return(&WalletExplorer.ITransactionData{Hash: shallowTxID, BlockHeight: 800008}, nil).Twice()
+	upstream.On("GetBlockHeight").Return(800010, nil).Twice()
+
+	_, err := svc.GetTransactionByTxID(context.Background(), shallowTxID)
+	assert.NoError(t, err)
+
+	// Within the TTL the transaction and the tip are served from cache
+	advance(30 * time.Second)
+	_, err = svc.GetTransactionByTxID(context.Background(), shallowTxID)
+	assert.NoError(t, err)
+
+	// Past the TTL the stale transaction is served while it is refreshed
+	advance(time.Minute)
+	_, err = svc.GetTransactionByTxID(context.Background(), shallowTxID)
+	assert.NoError(t, err)
+	wait()
+	upstream.AssertExpectations(t)
+}
+
+func TestCachedTransactionService_UnconfirmedSkipsTipLookup(t *testing.T) {
+	svc, upstream, _, _ := newCachedTransactionService()
+
+	upstream.On("GetTransactionByTxID", unconfTxID).Return(&WalletExplorer.ITransactionData{Hash: unconfTxID}, nil).Once()
+
+	_, err := svc.GetTransactionByTxID(context.Background(), unconfTxID)
+	assert.NoError(t, err)
+	upstream.AssertNotCalled(t, "GetBlockHeight")
+}
+
+func TestCachedTransactionService_ServesStaleHistory(t *testing.T) {
+	svc, upstream, advance, wait := newCachedTransactionService()
+
+	upstream.On("GetTransactionByXPUB", historyZpub).Return(&WalletExplorer.ITransactionXPUB{Found: true}, nil).Once()
+	upstream.On("GetTransactionByXPUB", historyZpub).Return(nil, errors.New("upstream down")).Once()
+
+	_, err := svc.GetTransactionByXPUB(context.Background(), historyZpub)
+	assert.NoError(t, err)
+
+	advance(10 * time.Minute)
+	data, err := svc.GetTransactionByXPUB(context.Background(), historyZpub)
+	assert.NoError(t, err)
+	assert.True(t, data.Found)
+	wait()
+	upstream.AssertExpectations(t)
+}
+
+func TestCachedTransactionService_NormalizesTxIDs(t *testing.T) {
+	svc, upstream, _, _ := newCachedTransactionService()
+
+	upstream.On("GetTransactionByTxID", unconfTxID).Return(&WalletExplorer.ITransactionData{Hash: unconfTxID}, nil).Once()
+
+	_, err := svc.GetTransactionByTxID(context.Background(), " "+strings.ToUpper(unconfTxID)+" ")
+	assert.NoError(t, err)
+	_, err = svc.GetTransactionByTxID(context.Background(), unconfTxID)
+	assert.NoError(t, err)
+	upstream.AssertExpectations(t)
+}
+
+func TestCachedTransactionService_RejectsMalformedInputBeforeTheCache(t *testing.T) {
+	svc, upstream, _, _ := newCachedTransactionService()
+
+	var invalid *app_errors.ValidationError
+	for _, txid := range []string{"", "deep", strings.Repeat("ab", 31), strings.Repeat("zz", 32), strings.Repeat("ab", 4096)} {
+		_, err := svc.GetTransactionByTxID(context.Background(), txid)
+		assert.ErrorAs(t, err, &invalid)
+		assert.Equal(t, "txid", invalid.Fields[0].Field)
+	}
+
+	_, err := svc.GetTransactionByAddress(context.Background(), historyAddress+"x")
+	assert.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "address", invalid.Fields[0].Field)
+
+	_, err = svc.GetTransactionByXPUB(context.Background(), "zpub")
+	assert.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "xpub", invalid.Fields[0].Field)
+
+	upstream.AssertNotCalled(t, "GetTransactionByTxID")
+	upstream.AssertNotCalled(t, "GetTransactionByAddress")
+	upstream.AssertNotCalled(t, "GetTransactionByXPUB")
+}
+
+func TestCachedTransactionService_CachesAddressHistory(t *testing.T) {
+	svc, upstream, _, _ := newCachedTransactionService()
+
+	upstream.On("GetTransactionByAddress", historyAddress).Return(&WalletExplorer.ITransactionAddress{Address: historyAddress, NTx: 2}, nil).Once()
+
+	for i := 0; i < 2; i++ {
+		data, err := svc.GetTransactionByAddress(context.Background(), historyAddress)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, data.NTx)
+	}
+	upstream.AssertExpectations(t)
+}
+
+func TestCachedTransactionService_StreamingIsNotCached(t *testing.T) {
+	svc, upstream, _, _ := newCachedTransactionService()
+
+	upstream.On("StreamAddressTransactions", "bc1q").Return([]WalletExplorer.AddressTransaction{{Hash: "a"}}, nil).Twice()
+
+	for i := 0; i < 2; i++ {
+		var hashes []string
+		err := svc.StreamAddressTransactions(context.Background(), "bc1q", func(tx WalletExplorer.AddressTransaction) error {
+			hashes = append(hashes, tx.Hash)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a"}, hashes)
+	}
+	upstream.AssertExpectations(t)
+}
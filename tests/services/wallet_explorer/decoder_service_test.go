@@ -0,0 +1,272 @@
+package tests
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"cry-api/app/bitcoin"
+	services "cry-api/app/services/wallet_explorer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// Bitcoin genesis block coinbase transaction
+	genesisTxHex = "01000000010000000000000000000000000000000000000000000000000000000000000000ffffffff4d04ffff001d0104455468652054696d65732030332f4a616e2f32303039204368616e63656c6c6f72206f6e206272696e6b206f66207365636f6e64206261696c6f757420666f722062616e6b73ffffffff0100f2052a01000000434104678afdb0fe5548271967f1a67130b7105cd6a828e03909a67962e0ea1f61deb649f6bc3f4cef38c4f35504e51ec112de5c384df7ba0b8d578a4c702b6bf11d5fac00000000"
+	genesisTxID  = "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b"
+)
+
+var (
+	testPrevTxID  = strings.Repeat("11", 32)
+	testOutScript = "0014" + strings.Repeat("22", 20)
+	testUTXO      = "0014" + strings.Repeat("33", 20)
+	testPubKey    = "02" + strings.Repeat("44", 32)
+	testSignature = "300602010102010101" // DER-shaped signature with SIGHASH_ALL
+)
+
+// unsigned 1-in/1-out transaction spending 100000 sats into a 90000 sat P2WPKH output
+func unsignedTxHex() string {
+	return unsignedTxSpending(testPrevTxID)
+}
+
+// unsignedTxSpending returns the unsigned transaction spending output 1 of prevTxID, given in
+// internal byte order
+func unsignedTxSpending(prevTxID string) string {
+	return "02000000" + "01" + prevTxID + "01000000" + "00" + "fdffffff" +
+		"01" + "905f010000000000" + "16" + testOutScript + "00000000"
+}
+
+// prevTxHex returns a transaction whose output 1 pays 100000 sats to testUTXO
+func prevTxHex() string {
+	return "02000000" + "01" + strings.Repeat("aa", 32) + "00000000" + "00" + "ffffffff" +
+		"02" + "e803000000000000" + "16" + testUTXO + witnessUTXO() + "00000000"
+}
+
+func psbtPair(key, value string) string {
+	return hex.EncodeToString([]byte{byte(len(key) / 2)}) + key + compactSize(len(value)/2) + value
+}
+
+func compactSize(n int) string {
+	return hex.EncodeToString([]byte{byte(n)})
+}
+
+func witnessUTXO() string {
+	return "a086010000000000" + "16" + testUTXO
+}
+
+func buildPSBTv0(inputPairs ...string) []byte {
+	return buildPSBTv0Spending(unsignedTxHex(), inputPairs...)
+}
+
+func buildPSBTv0Spending(unsignedTx string, inputPairs ...string) []byte {
+	raw := "70736274ff" +
+		psbtPair("00", unsignedTx) + "00" +
+		strings.Join(inputPairs, "") + "00" +
+		"00"
+	b, _ := hex.DecodeString(raw)
+	return b
+}
+
+func TestDecodeTransaction_RawLegacy(t *testing.T) {
+	svc := services.NewDecoderService()
+
+	data, err := svc.DecodeTransaction(genesisTxHex, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "raw", data.Format)
+	assert.Equal(t, genesisTxID, data.TxID)
+	assert.Equal(t, genesisTxID, data.Transaction.Hash)
+	assert.Equal(t, 204, data.Transaction.Size)
+	assert.Nil(t, data.Transaction.Fee)
+	require.Len(t, data.Outputs, 1)
+	assert.Equal(t, "p2pk", data.Outputs[0].ScriptType)
+	assert.Equal(t, int64(5000000000), data.Outputs[0].Value)
+	assert.Nil(t, data.Outputs[0].Address)
+	assert.True(t, data.Complete)
+}
+
+func TestDecodeTransaction_RawSegwit(t *testing.T) {
+	svc := services.NewDecoderService()
+
+	signed := "02000000" + "0001" + "01" + testPrevTxID + "01000000" + "00" + "fdffffff" +
+		"01" + "905f010000000000" + "16" + testOutScript +
+		"02" + "09" + testSignature + "21" + testPubKey + "00000000"
+
+	unsigned, err := svc.DecodeTransaction(unsignedTxHex(), "")
+	require.NoError(t, err)
+	data, err := svc.DecodeTransaction(signed, "")
+	require.NoError(t, err)
+
+	// Witness data changes the wtxid but not the txid
+	assert.Equal(t, unsigned.TxID, data.TxID)
+	assert.NotEqual(t, data.TxID, data.WTxID)
+	assert.Less(t, data.VSize, data.Transaction.Size)
+
+	assert.Equal(t, "unsigned", unsigned.Inputs[0].SignatureStatus)
+	assert.False(t, unsigned.Complete)
+
+	require.Len(t, data.Inputs, 1)
+	assert.Equal(t, testPrevTxID, data.Inputs[0].PrevTxID)
+	assert.Equal(t, uint32(1), data.Inputs[0].PrevVout)
+	assert.Equal(t, "signed", data.Inputs[0].SignatureStatus)
+	assert.Equal(t, []string{"ALL"}, data.Inputs[0].Sighash)
+	assert.True(t, data.Complete)
+
+	require.NotNil(t, data.Outputs[0].Address)
+	assert.Equal(t, "p2wpkh", data.Outputs[0].ScriptType)
+	assert.True(t, strings.HasPrefix(*data.Outputs[0].Address, "bc1q"))
+
+	testnet, err := svc.DecodeTransaction(signed, "testnet")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(*testnet.Outputs[0].Address, "tb1q"))
+}
+
+func TestDecodeTransaction_PSBTv0(t *testing.T) {
+	svc := services.NewDecoderService()
+
+	t.Run("Unsigned with witness utxo", func(t *testing.T) {
+		psbt := buildPSBTv0(psbtPair("01", witnessUTXO()))
+
+		data, err := svc.DecodeTransaction(base64.StdEncoding.EncodeToString(psbt), "")
+		require.NoError(t, err)
+
+		assert.Equal(t, "psbt", data.Format)
+		require.NotNil(t, data.PSBTVersion)
+		assert.Equal(t, 0, *data.PSBTVersion)
+		require.NotNil(t, data.Transaction.Fee)
+		assert.Equal(t, int64(10000), *data.Transaction.Fee)
+		assert.Equal(t, 82, data.VSize)
+		// signature and public key placeholders add 110 weight units of witness
+		require.NotNil(t, data.EstimatedVSize)
+		assert.Equal(t, 110, *data.EstimatedVSize)
+		require.NotNil(t, data.FeeRate)
+		assert.InDelta(t, 90.91, *data.FeeRate, 0.001)
+		assert.False(t, data.FeeRateUpperBound)
+
+		input := data.Inputs[0]
+		assert.Equal(t, "p2wpkh", input.ScriptType)
+		assert.Equal(t, int64(100000), *input.Value)
+		assert.Equal(t, "unsigned", input.SignatureStatus)
+		assert.Equal(t, 1, input.RequiredSignatures)
+		assert.Equal(t, 1, input.MissingSignatures)
+		assert.False(t, data.Complete)
+		assert.NotNil(t, data.Transaction.Inputs[0].PrevOut)
+	})
+
+	t.Run("Fee rate upper bound without redeem script", func(t *testing.T) {
+		p2shUTXO := "a086010000000000" + "17" + "a914" + strings.Repeat("55", 20) + "87"
+		psbt := buildPSBTv0(psbtPair("01", p2shUTXO))
+
+		data, err := svc.DecodeTransaction(base64.StdEncoding.EncodeToString(psbt), "")
+		require.NoError(t, err)
+
+		assert.Nil(t, data.EstimatedVSize)
+		assert.True(t, data.FeeRateUpperBound)
+		require.NotNil(t, data.FeeRate)
+		assert.InDelta(t, 121.95, *data.FeeRate, 0.001)
+	})
+
+	t.Run("Partially signed input", func(t *testing.T) {
+		psbt := buildPSBTv0(psbtPair("01", witnessUTXO()), psbtPair("02"+testPubKey, testSignature))
+
+		data, err := svc.DecodeTransaction(hex.EncodeToString(psbt), "")
+		require.NoError(t, err)
+
+		input := data.Inputs[0]
+		assert.Equal(t, "signed", input.SignatureStatus)
+		assert.Equal(t, 1, input.Signatures)
+		assert.Equal(t, 0, input.MissingSignatures)
+		assert.Equal(t, []string{"ALL"}, input.Sighash)
+		assert.True(t, data.Complete)
+	})
+
+	t.Run("Fee unknown without utxo", func(t *testing.T) {
+		data, err := svc.DecodeTransaction(base64.StdEncoding.EncodeToString(buildPSBTv0()), "")
+		require.NoError(t, err)
+
+		assert.Nil(t, data.Transaction.Fee)
+		assert.Nil(t, data.FeeRate)
+		assert.Equal(t, "unknown", data.Inputs[0].SignatureStatus)
+	})
+}
+
+func TestDecodeTransaction_PSBTNonWitnessUTXO(t *testing.T) {
+	svc := services.NewDecoderService()
+
+	prev, _ := hex.DecodeString(prevTxHex())
+	prevTxID := hex.EncodeToString(bitcoin.DoubleSHA256(prev))
+
+	t.Run("Matching previous transaction", func(t *testing.T) {
+		psbt := buildPSBTv0Spending(unsignedTxSpending(prevTxID), psbtPair("00", prevTxHex()))
+
+		data, err := svc.DecodeTransaction(base64.StdEncoding.EncodeToString(psbt), "")
+		require.NoError(t, err)
+		require.NotNil(t, data.Transaction.Fee)
+		assert.Equal(t, int64(10000), *data.Transaction.Fee)
+		assert.Equal(t, int64(100000), *data.Inputs[0].Value)
+	})
+
+	t.Run("Previous transaction of another txid", func(t *testing.T) {
+		psbt := buildPSBTv0(psbtPair("00", prevTxHex()))
+
+		data, err := svc.DecodeTransaction(base64.StdEncoding.EncodeToString(psbt), "")
+		assert.Nil(t, data)
+		assert.ErrorContains(t, err, "non-witness utxo does not match the previous txid")
+	})
+
+	t.Run("Witness utxo lying about the value", func(t *testing.T) {
+		lying := "a0bb0d0000000000" + "16" + testUTXO
+		psbt := buildPSBTv0Spending(unsignedTxSpending(prevTxID), psbtPair("00", prevTxHex()), psbtPair("01", lying))
+
+		data, err := svc.DecodeTransaction(base64.StdEncoding.EncodeToString(psbt), "")
+		assert.Nil(t, data)
+		assert.ErrorContains(t, err, "witness utxo does not match")
+	})
+}
+
+func TestDecodeTransaction_PSBTv2(t *testing.T) {
+	svc := services.NewDecoderService()
+
+	raw := "70736274ff" +
+		psbtPair("02", "02000000") + psbtPair("04", "01") + psbtPair("05", "01") + psbtPair("fb", "02000000") + "00" +
+		psbtPair("0e", testPrevTxID) + psbtPair("0f", "01000000") + psbtPair("10", "fdffffff") +
+		psbtPair("01", witnessUTXO()) + psbtPair("03", "83000000") + "00" +
+		psbtPair("03", "905f010000000000") + psbtPair("04", testOutScript) + "00"
+	psbt, _ := hex.DecodeString(raw)
+
+	v0, err := svc.DecodeTransaction(base64.StdEncoding.EncodeToString(buildPSBTv0()), "")
+	require.NoError(t, err)
+	data, err := svc.DecodeTransaction(base64.StdEncoding.EncodeToString(psbt), "")
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, *data.PSBTVersion)
+	assert.Equal(t, v0.TxID, data.TxID)
+	assert.Equal(t, int64(10000), *data.Transaction.Fee)
+	assert.Equal(t, []string{"SINGLE|ANYONECANPAY"}, data.Inputs[0].Sighash)
+}
+
+func TestDecodeTransaction_InvalidPayload(t *testing.T) {
+	svc := services.NewDecoderService()
+
+	testCases := []struct {
+		name    string
+		payload string
+		message string
+	}{
+		{"not hex or base64", "hello world", "neither a raw transaction"},
+		{"truncated transaction", unsignedTxHex()[:40], "invalid transaction"},
+		{"trailing bytes", unsignedTxHex() + "00", "trailing bytes"},
+		{"truncated psbt", "70736274ff0100", "invalid psbt"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := svc.DecodeTransaction(tc.payload, "")
+			assert.Nil(t, data)
+			assert.ErrorContains(t, err, tc.message)
+		})
+	}
+}
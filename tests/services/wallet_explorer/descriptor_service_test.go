@@ -0,0 +1,215 @@
+package tests
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	"cry-api/app/bitcoin"
+	services "cry-api/app/services/wallet_explorer"
+	app_errors "cry-api/app/types/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// BIP84 test vector account key (m/84'/0'/0')
+const bip84Zpub = "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs"
+
+// BIP86 test vector account key (m/86'/0'/0')
+const bip86Xpub = "xpub6BgBgsespWvERF3LHQu6CnqdvfEvtMcQjYrcRzx53QJjSxarj2afYWcLteoGVky7D3UKDP9QyrLprQ3VCECoY49yfdDEHGCtMMj92pReUsQ"
+
+func TestNormalizeDescriptor_Zpub(t *testing.T) {
+	svc := services.NewDescriptorService()
+
+	data, err := svc.NormalizeDescriptor(bip84Zpub, 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, "extended_key", data.InputType)
+	assert.Equal(t, "p2wpkh", data.ScriptType)
+	assert.Equal(t, "mainnet", data.Network)
+	assert.True(t, data.IsRange)
+	assert.Regexp(t, `^wpkh\(xpub[1-9A-HJ-NP-Za-km-z]+/0/\*\)#[a-z0-9]{8}$`, data.Descriptor)
+	require.NotNil(t, data.ChangeDescriptor)
+	assert.Contains(t, *data.ChangeDescriptor, "/1/*)#")
+
+	require.Len(t, data.Addresses, 2)
+	assert.Equal(t, "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", data.Addresses[0].Address)
+	assert.Equal(t, "bc1qnjg0jd8228aq7egyzacy8cys3knf9xvrerkf9g", data.Addresses[1].Address)
+	assert.Equal(t, "0014c0cebcd6c3d3ca8c75dc5ec62ebe55330ef910e2", data.Addresses[0].ScriptPubKey)
+}
+
+func TestNormalizeDescriptor_DescriptorRoundTrip(t *testing.T) {
+	svc := services.NewDescriptorService()
+
+	first, err := svc.NormalizeDescriptor(bip84Zpub, 1)
+	require.NoError(t, err)
+
+	// Feeding the canonical descriptor back in yields the same descriptor and addresses
+	second, err := svc.NormalizeDescriptor(first.Descriptor, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "descriptor", second.InputType)
+	assert.Equal(t, first.Descriptor, second.Descriptor)
+	assert.Equal(t, first.Addresses, second.Addresses)
+	assert.Nil(t, second.ChangeDescriptor)
+}
+
+func TestNormalizeDescriptor_Taproot(t *testing.T) {
+	svc := services.NewDescriptorService()
+
+	data, err := svc.NormalizeDescriptor("tr("+bip86Xpub+"/0/*)", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "p2tr", data.ScriptType)
+	assert.Equal(t, "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr", data.Addresses[0].Address)
+}
+
+func TestNormalizeDescriptor_SortedMultiIsOrderIndependent(t *testing.T) {
+	svc := services.NewDescriptorService()
+
+	keyA := "02e493dbf1c10d80f3581e4904930b1404cc6c13900ee0758474fa94abe8c4cd13"
+	keyB := "03a34b99f22c790c4e36b2b3c2c35a36db06226e41c692fc82b8b56ac1c540c5bd"
+
+	ab, err := svc.NormalizeDescriptor("wsh(sortedmulti(1,"+keyA+","+keyB+"))", 5)
+	require.NoError(t, err)
+	ba, err := svc.NormalizeDescriptor("wsh(sortedmulti(1,"+keyB+","+keyA+"))", 5)
+	require.NoError(t, err)
+
+	assert.False(t, ab.IsRange)
+	assert.Len(t, ab.Addresses, 1)
+	assert.Equal(t, "p2wsh", ab.ScriptType)
+	assert.Equal(t, ab.Addresses[0].Address, ba.Addresses[0].Address)
+}
+
+// multisigDescriptor returns script(multi(1,key0,...)) over n keys derived from the BIP86 account
+func multisigDescriptor(t *testing.T, script string, n int) string {
+	account, err := bitcoin.ParseExtendedKey(bip86Xpub)
+	require.NoError(t, err)
+
+	keys := make([]string, n)
+	for i := range keys {
+		child, err := account.Child(uint32(i))
+		require.NoError(t, err)
+		keys[i] = hex.EncodeToString(child.PublicKey)
+	}
+	return script + "(multi(1," + strings.Join(keys, ",") + "))"
+}
+
+func TestNormalizeDescriptor_MultisigKeyLimits(t *testing.T) {
+	svc := services.NewDescriptorService()
+
+	testCases := []struct {
+		script string
+		keys   int
+		valid  bool
+	}{
+		{"sh", 15, true},
+		{"sh", 16, false},
+		{"wsh", 20, true},
+		{"wsh", 21, false},
+		{"sh(wsh", 20, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s %d keys", tc.script, tc.keys), func(t *testing.T) {
+			descriptor := multisigDescriptor(t, tc.script, tc.keys)
+			if strings.HasPrefix(tc.script, "sh(") {
+				descriptor += ")"
+			}
+
+			data, err := svc.NormalizeDescriptor(descriptor, 1)
+			if !tc.valid {
+				assert.ErrorIs(t, err, bitcoin.ErrInvalidDescriptor)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, data.Addresses, 1)
+		})
+	}
+}
+
+func TestNormalizeDescriptor_DerivationLimits(t *testing.T) {
+	svc := services.NewDescriptorService()
+
+	_, err := svc.NormalizeDescriptor(bip84Zpub, services.MaxDerivedAddressCount+1)
+	var validationErr *app_errors.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "count", validationErr.Field)
+
+	keys := make([]string, 20)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("%s/%d/*", bip86Xpub, i)
+	}
+	descriptor := "wsh(sortedmulti(1," + strings.Join(keys, ",") + "))"
+
+	maxCount := services.MaxDerivedKeyCount / len(keys)
+	data, err := svc.NormalizeDescriptor(descriptor, maxCount)
+	require.NoError(t, err)
+	assert.Len(t, data.Addresses, maxCount)
+
+	_, err = svc.NormalizeDescriptor(descriptor, maxCount+1)
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "count", validationErr.Field)
+}
+
+func TestMultisigScript_MoreThanSixteenKeys(t *testing.T) {
+	keys := make([][]byte, 20)
+	for i := range keys {
+		keys[i] = append([]byte{0x02}, make([]byte, 32)...)
+		keys[i][32] = byte(i + 1)
+	}
+
+	script := bitcoin.MultisigScript(17, keys)
+	assert.Equal(t, []byte{0x01, 17}, script[:2])
+	assert.Equal(t, []byte{0x01, 20, 0xae}, script[len(script)-3:])
+
+	class := bitcoin.ClassifyScript(script)
+	assert.Equal(t, bitcoin.ScriptMultisig, class.Type)
+	assert.Equal(t, 17, class.RequiredSigs)
+	assert.Equal(t, 20, class.TotalKeys)
+}
+
+func TestNormalizeDescriptor_Errors(t *testing.T) {
+	svc := services.NewDescriptorService()
+
+	valid, err := svc.NormalizeDescriptor(bip84Zpub, 1)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name  string
+		input string
+		err   error
+	}{
+		{"bad checksum", valid.Descriptor[:len(valid.Descriptor)-1] + "q", bitcoin.ErrDescriptorChecksum},
+		{"hardened step after xpub", "wpkh(" + bip86Xpub + "/0'/*)", bitcoin.ErrHardenedFromPublic},
+		{"private key", "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi", bitcoin.ErrPrivateExtendedKey},
+		{"unsupported script", "combo(" + bip86Xpub + ")", bitcoin.ErrInvalidDescriptor},
+		{"garbage", "not-a-key", bitcoin.ErrInvalidExtendedKey},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := svc.NormalizeDescriptor(tc.input, 1)
+			assert.Nil(t, data)
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestConvertExtendedKey(t *testing.T) {
+	svc := services.NewDescriptorService()
+
+	xpub, err := svc.ConvertExtendedKey(bip84Zpub, "xpub")
+	require.NoError(t, err)
+	assert.Equal(t, "xpub6CatWdiZiodmUeTDp8LT5or8nmbKNcuyvz7WyksVFkKB4RHwCD3XyuvPEbvqAQY3rAPshWcMLoP2fMFMKHPJ4ZeZXYVUhLv1VMrjPC7PW6V", xpub.Output)
+
+	zpub, err := svc.ConvertExtendedKey(xpub.Output, "zpub")
+	require.NoError(t, err)
+	assert.Equal(t, bip84Zpub, zpub.Output)
+
+	_, err = svc.ConvertExtendedKey(bip84Zpub, "vpub")
+	assert.ErrorContains(t, err, "network mismatch")
+
+	_, err = svc.ConvertExtendedKey(bip84Zpub, "qpub")
+	assert.ErrorContains(t, err, "unknown extended key prefix")
+}
@@ -0,0 +1,204 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	services "cry-api/app/services/wallet_explorer"
+	EnvTypes "cry-api/app/types/env"
+	app_errors "cry-api/app/types/errors"
+	WalletExplorer "cry-api/app/types/wallet_explorer"
+	"cry-api/tests/cassette"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	confirmedTxID = "3b1c4a1f9d0e2b7c5a6e8f0d1c2b3a495867f6e5d4c3b2a1908f7e6d5c4b3a29"
+	pendingTxID   = "7e2d9c4b1a0f3e5d6c8b7a9f0e1d2c3b4a5968778695a4b3c2d1e0f9a8b7c6d5"
+	replacedTxID  = "0f1e2d3c4b5a69788796a5b4c3d2e1f00112233445566778899aabbccddeeff0"
+	evictedTxID   = "a9b8c7d6e5f40312213041526374859607a8b9cadbecfd0e1f2031425364758a"
+)
+
+// newMempoolService returns a MempoolService replaying the mempool.space cassette
+func newMempoolService(t *testing.T) *services.MempoolService {
+	rec := cassette.New(t, "mempool_space")
+	cfg := &EnvTypes.EnvConfig{MempoolConfig: EnvTypes.MempoolConfig{API: "https://mempool.space/api", Providers: []string{"mempool"}}}
+	return services.NewMempoolService(services.NewMempoolProviders(cfg, rec.Client()))
+}
+
+func TestMempoolService_GetFeeEstimates(t *testing.T) {
+	svc := newMempoolService(t)
+
+	fees, err := svc.GetFeeEstimates(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &WalletExplorer.IFeeEstimates{Fastest: 24, HalfHour: 18, Hour: 12, Economy: 6, Minimum: 3}, fees)
+}
+
+func TestMempoolService_GetTip(t *testing.T) {
+	svc := newMempoolService(t)
+
+	tip, err := svc.GetTip(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 868123, tip.Height)
+	assert.Equal(t, "00000000000000000001b5e7a1c8d7e2f0a9c6b4d3e2f1a0b9c8d7e6f5a4b3c2", tip.Hash)
+}
+
+func TestMempoolService_GetMempoolStatus_Confirmed(t *testing.T) {
+	svc := newMempoolService(t)
+
+	status, err := svc.GetMempoolStatus(context.Background(), confirmedTxID)
+	assert.NoError(t, err)
+	assert.Equal(t, WalletExplorer.MempoolStatusConfirmed, status.Status)
+	assert.Equal(t, 124, status.Confirmations)
+	assert.Equal(t, 868000, status.BlockHeight)
+	assert.Equal(t, int64(1729012345), status.BlockTime)
+	assert.Equal(t, float64(141), status.VSize)
+	assert.Equal(t, 20.0, status.FeeRate)
+	assert.False(t, status.SignalsRBF)
+}
+
+func TestMempoolService_GetMempoolStatus_PendingReplacement(t *testing.T) {
+	svc := newMempoolService(t)
+
+	// Upper case ids are accepted
+	status, err := svc.GetMempoolStatus(context.Background(), "7E2D9C4B1A0F3E5D6C8B7A9F0E1D2C3B4A5968778695A4B3C2D1E0F9A8B7C6D5")
+	assert.NoError(t, err)
+	assert.Equal(t, pendingTxID, status.TxID)
+	assert.Equal(t, WalletExplorer.MempoolStatusPending, status.Status)
+	assert.Equal(t, 0, status.Confirmations)
+	assert.Equal(t, float64(209), status.VSize)
+	assert.Equal(t, 20.08, status.FeeRate)
+	assert.True(t, status.SignalsRBF)
+	assert.Empty(t, status.ReplacedBy)
+	assert.Equal(t, []string{replacedTxID}, status.Replaces)
+}
+
+func TestMempoolService_GetMempoolStatus_Replaced(t *testing.T) {
+	svc := newMempoolService(t)
+
+	status, err := svc.GetMempoolStatus(context.Background(), replacedTxID)
+	assert.NoError(t, err)
+	assert.Equal(t, WalletExplorer.MempoolStatusReplaced, status.Status)
+	assert.Equal(t, pendingTxID, status.ReplacedBy)
+	assert.Equal(t, int64(2100), status.Fee)
+}
+
+func TestMempoolService_GetMempoolStatus_EvictedAfterReplacement(t *testing.T) {
+	svc := newMempoolService(t)
+
+	status, err := svc.GetMempoolStatus(context.Background(), evictedTxID)
+	assert.NoError(t, err)
+	assert.Equal(t, WalletExplorer.MempoolStatusReplaced, status.Status)
+	assert.Equal(t, "5d4c3b2a19087f6e5d4c3b2a19087f6e5d4c3b2a19087f6e5d4c3b2a19087f6e", status.ReplacedBy)
+	assert.Zero(t, status.Fee)
+}
+
+func TestMempoolService_GetMempoolStatus_Errors(t *testing.T) {
+	svc := newMempoolService(t)
+
+	_, err := svc.GetMempoolStatus(context.Background(), "0000000000000000000000000000000000000000000000000000000000000001")
+	var notFound *app_errors.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+
+	_, err = svc.GetMempoolStatus(context.Background(), "not-a-txid")
+	var invalid *app_errors.ValidationError
+	assert.ErrorAs(t, err, &invalid)
+
+	_, err = svc.GetMempoolStatus(context.Background(), "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	var upstream *app_errors.AppError
+	assert.ErrorAs(t, err, &upstream)
+	assert.Equal(t, http.StatusBadGateway, upstream.Status)
+	assert.Equal(t, app_errors.CodeUpstreamError, upstream.Code)
+	assert.NotContains(t, upstream.Error(), "503")
+}
+
+// esploraServer serves fee estimates, the tip and an unconfirmed transaction like an Esplora API
+func esploraServer(t *testing.T, requests *[]string) string {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.URL.Path)
+		switch r.URL.Path {
+		case "/fee-estimates":
+			_, _ = w.Write([]byte(`{"1": 30.5, "2": 25, "3": 20.1, "6": 14, "144": 4.2, "504": 2, "1008": 1.5}`))
+		case "/blocks/tip/height":
+			_, _ = w.Write([]byte("868124"))
+		case "/blocks/tip/hash":
+			_, _ = w.Write([]byte("0000000000000000000aa1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f6"))
+		case "/tx/" + pendingTxID:
+			_, _ = w.Write([]byte(`{"txid": "` + pendingTxID + `", "fee": 4200, "weight": 836, "vin": [{"sequence": 4294967295}], "vout": [{"value": 1000}, {"value": 2500}], "status": {"confirmed": false}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestMempoolService_FallsBackToNextProvider(t *testing.T) {
+	var down, esplora []string
+	svc := services.NewMempoolService([]services.MempoolProvider{
+		services.NewMempoolSpaceProvider(providerServer(t, http.StatusServiceUnavailable, "busy", &down), http.DefaultClient),
+		services.NewEsploraProvider(esploraServer(t, &esplora), http.DefaultClient),
+	})
+
+	fees, err := svc.GetFeeEstimates(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &WalletExplorer.IFeeEstimates{Fastest: 30.5, HalfHour: 20.1, Hour: 14, Economy: 4.2, Minimum: 1.5}, fees)
+
+	tip, err := svc.GetTip(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &WalletExplorer.IBlockTip{Height: 868124, Hash: "0000000000000000000aa1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f6"}, tip)
+
+	status, err := svc.GetMempoolStatus(context.Background(), pendingTxID)
+	assert.NoError(t, err)
+	assert.Equal(t, WalletExplorer.MempoolStatusPending, status.Status)
+	assert.Equal(t, int64(4200), status.Fee)
+	assert.False(t, status.SignalsRBF)
+
+	values, err := svc.GetOutputValues(context.Background(), pendingTxID)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1000, 2500}, values)
+
+	assert.Equal(t, []string{"/v1/fees/recommended ", "/blocks/tip/height ", "/tx/" + pendingTxID + " ", "/tx/" + pendingTxID + " "}, down)
+}
+
+func TestMempoolService_UnknownTransactionIsNotRetried(t *testing.T) {
+	var unknown, esplora []string
+	svc := services.NewMempoolService([]services.MempoolProvider{
+		services.NewMempoolSpaceProvider(providerServer(t, http.StatusNotFound, "Transaction not found", &unknown), http.DefaultClient),
+		services.NewEsploraProvider(esploraServer(t, &esplora), http.DefaultClient),
+	})
+
+	_, err := svc.GetMempoolStatus(context.Background(), pendingTxID)
+	var notFound *app_errors.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+
+	_, err = svc.GetOutputValues(context.Background(), pendingTxID)
+	assert.ErrorAs(t, err, &notFound)
+	assert.Empty(t, esplora)
+
+	// A missing fee endpoint is a broken provider, not an answer
+	_, err = svc.GetFeeEstimates(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/fee-estimates"}, esplora)
+}
+
+func TestMempoolService_AllProvidersUnreachable(t *testing.T) {
+	var requests []string
+	svc := services.NewMempoolService([]services.MempoolProvider{
+		services.NewMempoolSpaceProvider(providerServer(t, http.StatusBadGateway, "", &requests), http.DefaultClient),
+		services.NewEsploraProvider(providerServer(t, http.StatusInternalServerError, "", &requests), http.DefaultClient),
+	})
+
+	_, err := svc.GetTip(context.Background())
+	var upstream *app_errors.AppError
+	assert.ErrorAs(t, err, &upstream)
+	assert.Equal(t, http.StatusBadGateway, upstream.Status)
+	assert.Len(t, requests, 2)
+
+	_, err = services.NewMempoolService(nil).GetFeeEstimates(context.Background())
+	assert.ErrorAs(t, err, &upstream)
+	assert.Equal(t, http.StatusServiceUnavailable, upstream.Status)
+}
@@ -0,0 +1,152 @@
+package tests
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	services "cry-api/app/services/wallet_explorer"
+	WalletExplorerTypes "cry-api/app/types/wallet_explorer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeOutputScript_Classification(t *testing.T) {
+	key33 := "02" + strings.Repeat("ab", 32)
+	hash20 := strings.Repeat("cd", 20)
+	hash32 := strings.Repeat("ef", 32)
+
+	testCases := []struct {
+		name   string
+		script string
+		kind   string
+		asm    string
+	}{
+		{"p2pk", "21" + key33 + "ac", "p2pk", key33 + " OP_CHECKSIG"},
+		{"p2pkh", "76a914" + hash20 + "88ac", "p2pkh", "OP_DUP OP_HASH160 " + hash20 + " OP_EQUALVERIFY OP_CHECKSIG"},
+		{"p2sh", "a914" + hash20 + "87", "p2sh", "OP_HASH160 " + hash20 + " OP_EQUAL"},
+		{"p2wpkh", "0014" + hash20, "p2wpkh", "0 " + hash20},
+		{"p2wsh", "0020" + hash32, "p2wsh", "0 " + hash32},
+		{"p2tr", "5120" + hash32, "p2tr", "1 " + hash32},
+		{"nonstandard", "51", "nonstandard", "1"},
+		{"malformed push", "4c", "nonstandard", "[error]"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			analysis := services.AnalyzeOutputScript(tc.script)
+			require.NotNil(t, analysis)
+			assert.Equal(t, tc.kind, analysis.Type)
+			assert.Equal(t, tc.asm, analysis.ASM)
+		})
+	}
+
+	assert.Nil(t, services.AnalyzeOutputScript("not-hex"))
+}
+
+func TestAnalyzeOutputScript_Multisig(t *testing.T) {
+	keyA := "02" + strings.Repeat("aa", 32)
+	keyB := "03" + strings.Repeat("bb", 32)
+	keyC := "02" + strings.Repeat("cc", 32)
+
+	analysis := services.AnalyzeOutputScript("52" + "21" + keyA + "21" + keyB + "21" + keyC + "53ae")
+	require.NotNil(t, analysis)
+	assert.Equal(t, "multisig", analysis.Type)
+	require.NotNil(t, analysis.RequiredSigs)
+	assert.Equal(t, 2, *analysis.RequiredSigs)
+	assert.Equal(t, 3, *analysis.TotalKeys)
+	assert.Equal(t, "2 "+keyA+" "+keyB+" "+keyC+" 3 OP_CHECKMULTISIG", analysis.ASM)
+}
+
+func TestAnalyzeOutputScript_OpReturn(t *testing.T) {
+	text := services.AnalyzeOutputScript("6a0b" + hex.EncodeToString([]byte("hello world")))
+	require.NotNil(t, text.OpReturn)
+	assert.Equal(t, "op_return", text.Type)
+	require.NotNil(t, text.OpReturn.Text)
+	assert.Equal(t, "hello world", *text.OpReturn.Text)
+
+	binary := services.AnalyzeOutputScript("6a0400ff10fe")
+	require.NotNil(t, binary.OpReturn)
+	assert.Equal(t, "00ff10fe", binary.OpReturn.Hex)
+	assert.Nil(t, binary.OpReturn.Text)
+}
+
+func TestAnalyzeInput_Inscription(t *testing.T) {
+	tapscript := "20" + strings.Repeat("11", 32) + "ac" +
+		"0063" + "03" + hex.EncodeToString([]byte("ord")) +
+		"0101" + "0a" + hex.EncodeToString([]byte("text/plain")) +
+		"00" + "05" + hex.EncodeToString([]byte("hello")) + "68"
+	witness := "03" + "40" + strings.Repeat("22", 64) +
+		"3d" + tapscript +
+		"21" + "c0" + strings.Repeat("33", 32)
+
+	input := &WalletExplorerTypes.Input{
+		Script:  "",
+		Witness: &witness,
+		PrevOut: &WalletExplorerTypes.PrevOut{Script: "5120" + strings.Repeat("44", 32)},
+	}
+
+	analysis := services.AnalyzeInput(input)
+	require.NotNil(t, analysis)
+	assert.Equal(t, "p2tr", analysis.Type)
+	assert.Len(t, analysis.Witness, 3)
+	require.NotNil(t, analysis.WitnessASM)
+	assert.True(t, strings.HasSuffix(*analysis.WitnessASM, "OP_CHECKSIG 0 OP_IF 6f7264 01 746578742f706c61696e 0 68656c6c6f OP_ENDIF"))
+	require.NotNil(t, analysis.Inscription)
+	assert.Equal(t, "ord", analysis.Inscription.Protocol)
+	assert.Equal(t, "text/plain", analysis.Inscription.ContentType)
+	assert.Equal(t, 5, analysis.Inscription.ContentLength)
+}
+
+func TestAnalyzeInput_NestedSegwitWitnessScript(t *testing.T) {
+	p2sh := &WalletExplorerTypes.PrevOut{Script: "a914" + strings.Repeat("55", 20) + "87"}
+	signature := "47" + strings.Repeat("30", 71)
+	key := "21" + "02" + strings.Repeat("66", 32)
+
+	// P2SH-P2WPKH: the witness is a signature and a key, there is no script to disassemble
+	p2wpkhWitness := "02" + signature + key
+	p2wpkh := services.AnalyzeInput(&WalletExplorerTypes.Input{
+		Script:  "16" + "0014" + strings.Repeat("77", 20),
+		Witness: &p2wpkhWitness,
+		PrevOut: p2sh,
+	})
+	require.NotNil(t, p2wpkh)
+	assert.Equal(t, "p2sh", p2wpkh.Type)
+	assert.Len(t, p2wpkh.Witness, 2)
+	assert.Nil(t, p2wpkh.WitnessASM)
+
+	// P2SH-P2WSH: the last witness item is the witness script
+	multisig := "51" + key + "51ae"
+	p2wshWitness := "03" + "00" + signature + hex.EncodeToString([]byte{byte(len(multisig) / 2)}) + multisig
+	p2wsh := services.AnalyzeInput(&WalletExplorerTypes.Input{
+		Script:  "22" + "0020" + strings.Repeat("88", 32),
+		Witness: &p2wshWitness,
+		PrevOut: p2sh,
+	})
+	require.NotNil(t, p2wsh)
+	require.NotNil(t, p2wsh.WitnessASM)
+	assert.Equal(t, "1 02"+strings.Repeat("66", 32)+" 1 OP_CHECKMULTISIG", *p2wsh.WitnessASM)
+}
+
+func TestAnalyzeTransaction_AnnotatesEveryInputAndOutput(t *testing.T) {
+	data := &WalletExplorerTypes.ITransactionData{
+		Inputs: []WalletExplorerTypes.Input{
+			{Script: "", PrevOut: &WalletExplorerTypes.PrevOut{Script: "0014" + strings.Repeat("aa", 20)}},
+			{Script: "input-script"},
+		},
+		Out: []WalletExplorerTypes.Output{
+			{Script: "a914" + strings.Repeat("bb", 20) + "87"},
+			{Script: "6a"},
+		},
+	}
+
+	services.AnalyzeTransaction(data)
+
+	require.NotNil(t, data.Inputs[0].ScriptAnalysis)
+	assert.Equal(t, "p2wpkh", data.Inputs[0].ScriptAnalysis.Type)
+	assert.Nil(t, data.Inputs[1].ScriptAnalysis)
+	assert.Equal(t, "p2sh", data.Out[0].ScriptAnalysis.Type)
+	assert.Equal(t, "op_return", data.Out[1].ScriptAnalysis.Type)
+	assert.Equal(t, "OP_RETURN", data.Out[1].ScriptAnalysis.ASM)
+}
@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	services "cry-api/app/services/wallet_explorer"
+	WalletExplorer "cry-api/app/types/wallet_explorer"
+	"cry-api/tests/cassette"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newCassetteService returns a TransactionService pointed at the real APIs, replaying the
+// responses of the named cassette
+func newCassetteService(t *testing.T, name string) *services.TransactionService {
+	rec := cassette.New(t, name)
+	cfg := makeTestEnvConfig("https://blockchain.info", "https://www.walletexplorer.com/api/1")
+	return services.NewTransactionService(cfg, rec.Client())
+}
+
+func TestCassette_GetTransactionByTxID_Genesis(t *testing.T) {
+	svc := newCassetteService(t, "blockchain_rawtx")
+
+	data, err := svc.GetTransactionByTxID(context.Background(), "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, data.BlockHeight)
+	assert.Equal(t, float64(0), data.LockTime)
+	assert.Nil(t, data.Inputs[0].PrevOut)
+	assert.Equal(t, float64(5000000000), data.Out[0].Value)
+	assert.Equal(t, "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", *data.Out[0].Addr)
+	assert.NotNil(t, data.Out[0].ScriptAnalysis)
+}
+
+func TestCassette_GetTransactionByTxID_StringOrIntFields(t *testing.T) {
+	svc := newCassetteService(t, "blockchain_rawtx")
+
+	data, err := svc.GetTransactionByTxID(context.Background(), "b5f6c0e4a1fbd2c3a3e6d9a0f1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4")
+	assert.NoError(t, err)
+
+	// The same fields arrive as strings or numbers depending on the transaction
+	assert.Equal(t, "868120", data.LockTime)
+	assert.Equal(t, "7311986102755394", data.TxIndex)
+	assert.Equal(t, "1500000", data.Inputs[0].PrevOut.Value)
+	assert.Equal(t, "1", data.Inputs[0].PrevOut.N)
+	assert.Equal(t, "1000000", data.Out[0].Value)
+	assert.Equal(t, float64(497180), data.Out[1].Value)
+	assert.Equal(t, int64(2820), *data.Fee)
+	assert.NotNil(t, data.Inputs[0].ScriptAnalysis)
+}
+
+func TestCassette_GetTransactionByTxID_NotFound(t *testing.T) {
+	svc := newCassetteService(t, "blockchain_rawtx")
+
+	data, err := svc.GetTransactionByTxID(context.Background(), "0000000000000000000000000000000000000000000000000000000000000000")
+	assert.Nil(t, data)
+	assert.EqualError(t, err, "external API returned status 404")
+}
+
+func TestCassette_GetBlockHeight(t *testing.T) {
+	svc := newCassetteService(t, "blockchain_rawtx")
+
+	height, err := svc.GetBlockHeight(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 868123, height)
+}
+
+func TestCassette_GetTransactionByAddress(t *testing.T) {
+	svc := newCassetteService(t, "blockchain_rawaddr")
+
+	data, err := svc.GetTransactionByAddress(context.Background(), "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, data.NTx)
+	assert.Equal(t, int64(1000000), data.FinalBalance)
+	assert.Len(t, data.Transactions, 2)
+	assert.Equal(t, int64(1000000), data.Transactions[0].Result)
+	assert.Equal(t, int64(2820), data.Transactions[0].Fee)
+
+	_, err = svc.GetTransactionByAddress(context.Background(), "not-an-address")
+	assert.EqualError(t, err, "external API returned status 400")
+}
+
+func TestCassette_StreamAddressTransactions(t *testing.T) {
+	svc := newCassetteService(t, "blockchain_rawaddr")
+
+	var hashes []string
+	err := svc.StreamAddressTransactions(context.Background(), "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", func(tx WalletExplorer.AddressTransaction) error {
+		hashes = append(hashes, tx.Hash)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"b5f6c0e4a1fbd2c3a3e6d9a0f1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4",
+		"9d1c3e0f5b7a2c4e6f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6",
+	}, hashes)
+}
+
+func TestCassette_GetTransactionByXPUB(t *testing.T) {
+	svc := newCassetteService(t, "walletexplorer_xpub")
+
+	data, err := svc.GetTransactionByXPUB(context.Background(), "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8")
+	assert.NoError(t, err)
+	assert.True(t, data.Found)
+	assert.Equal(t, 5, data.GapLimit)
+	assert.Len(t, data.Transactions, 2)
+	assert.Equal(t, -0.0010282, data.Transactions[1].BalanceDiff)
+	assert.Equal(t, []string{"8b3c1f2a4d"}, data.Transactions[1].WalletIDs)
+}
+
+func TestCassette_GetTransactionByXPUB_ErrorPayloads(t *testing.T) {
+	svc := newCassetteService(t, "walletexplorer_xpub")
+
+	// Unknown keys are reported in a successful response
+	data, err := svc.GetTransactionByXPUB(context.Background(), "xpub-unknown")
+	assert.NoError(t, err)
+	assert.False(t, data.Found)
+	assert.Empty(t, data.Transactions)
+
+	data, err = svc.GetTransactionByXPUB(context.Background(), "xpub-overloaded")
+	assert.Nil(t, data)
+	assert.EqualError(t, err, "external API returned status 503")
+}
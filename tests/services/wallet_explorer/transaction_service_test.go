@@ -1,12 +1,19 @@
 package tests
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"cry-api/app/httpclient"
 	services "cry-api/app/services/wallet_explorer"
 	EnvTypes "cry-api/app/types/env"
+	WalletExplorer "cry-api/app/types/wallet_explorer"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -48,9 +55,9 @@ func TestGetTransactionByTxID_Success(t *testing.T) {
 	defer server.Close()
 
 	cfg := makeTestEnvConfig(server.URL, "")
-	svc := services.NewTransactionService(cfg)
+	svc := services.NewTransactionService(cfg, http.DefaultClient)
 
-	data, err := svc.GetTransactionByTxID("testtxid")
+	data, err := svc.GetTransactionByTxID(context.Background(), "testtxid")
 	assert.NoError(t, err)
 	assert.NotNil(t, data)
 	assert.Equal(t, "testtxid", data.Hash)
@@ -59,6 +66,45 @@ func TestGetTransactionByTxID_Success(t *testing.T) {
 	assert.Equal(t, "input-script", data.Inputs[0].Script)
 }
 
+func TestGetTransactionByTxID_RetriesThroughSharedClient(t *testing.T) {
+	calls := 0
+	handler := func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"hash": "testtxid", "block_height": 123}`))
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	clientCfg := httpclient.DefaultConfig()
+	clientCfg.Default.MaxRetries = 1
+	clientCfg.BaseBackoff = time.Millisecond
+	svc := services.NewTransactionService(makeTestEnvConfig(server.URL, ""), httpclient.New(clientCfg))
+
+	data, err := svc.GetTransactionByTxID(context.Background(), "testtxid")
+	assert.NoError(t, err)
+	assert.Equal(t, "testtxid", data.Hash)
+	assert.Equal(t, 2, calls)
+}
+
+func TestGetTransactionByTxID_CancelledContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	svc := services.NewTransactionService(makeTestEnvConfig(server.URL, ""), httpclient.New(httpclient.DefaultConfig()))
+	data, err := svc.GetTransactionByTxID(ctx, "testtxid")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, data)
+}
+
 func TestGetTransactionByTxID_Non200Status(t *testing.T) {
 	handler := func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
@@ -68,9 +114,9 @@ func TestGetTransactionByTxID_Non200Status(t *testing.T) {
 	defer server.Close()
 
 	cfg := makeTestEnvConfig(server.URL, "")
-	svc := services.NewTransactionService(cfg)
+	svc := services.NewTransactionService(cfg, http.DefaultClient)
 
-	data, err := svc.GetTransactionByTxID("testtxid")
+	data, err := svc.GetTransactionByTxID(context.Background(), "testtxid")
 	assert.Error(t, err)
 	assert.Nil(t, data)
 	assert.Contains(t, err.Error(), "external API returned status 400")
@@ -85,9 +131,9 @@ func TestGetTransactionByTxID_InvalidJSON(t *testing.T) {
 	defer server.Close()
 
 	cfg := makeTestEnvConfig(server.URL, "")
-	svc := services.NewTransactionService(cfg)
+	svc := services.NewTransactionService(cfg, http.DefaultClient)
 
-	data, err := svc.GetTransactionByTxID("testtxid")
+	data, err := svc.GetTransactionByTxID(context.Background(), "testtxid")
 	assert.Error(t, err)
 	assert.Nil(t, data)
 	assert.Contains(t, err.Error(), "failed to parse JSON")
@@ -110,9 +156,9 @@ func TestGetTransactionByXPUB_Success(t *testing.T) {
 	defer server.Close()
 
 	cfg := makeTestEnvConfig("", server.URL)
-	svc := services.NewTransactionService(cfg)
+	svc := services.NewTransactionService(cfg, http.DefaultClient)
 
-	data, err := svc.GetTransactionByXPUB("testxpub")
+	data, err := svc.GetTransactionByXPUB(context.Background(), "testxpub")
 	assert.NoError(t, err)
 	assert.NotNil(t, data)
 	assert.True(t, data.Found)
@@ -131,9 +177,9 @@ func TestGetTransactionByXPUB_Non200Status(t *testing.T) {
 	defer server.Close()
 
 	cfg := makeTestEnvConfig("", server.URL)
-	svc := services.NewTransactionService(cfg)
+	svc := services.NewTransactionService(cfg, http.DefaultClient)
 
-	data, err := svc.GetTransactionByXPUB("testxpub")
+	data, err := svc.GetTransactionByXPUB(context.Background(), "testxpub")
 	assert.Error(t, err)
 	assert.Nil(t, data)
 	assert.Contains(t, err.Error(), "external API returned status 500")
@@ -150,10 +196,143 @@ func TestGetTransactionByXPUB_InvalidJSON(t *testing.T) {
 	defer server.Close()
 
 	cfg := makeTestEnvConfig("", server.URL)
-	svc := services.NewTransactionService(cfg)
+	svc := services.NewTransactionService(cfg, http.DefaultClient)
 
-	data, err := svc.GetTransactionByXPUB("testxpub")
+	data, err := svc.GetTransactionByXPUB(context.Background(), "testxpub")
 	assert.Error(t, err)
 	assert.Nil(t, data)
 	assert.Contains(t, err.Error(), "failed to parse JSON")
 }
+
+func TestGetTransactionByTxID_AddsScriptAnalysis(t *testing.T) {
+	handler := func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{
+			"hash": "testtxid",
+			"inputs": [{"script": "", "prev_out": {"script": "0014c0cebcd6c3d3ca8c75dc5ec62ebe55330ef910e2", "value": 1000, "n": 0}}],
+			"out": [{"script": "6a0568656c6c6f", "value": 0}]
+		}`))
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	svc := services.NewTransactionService(makeTestEnvConfig(server.URL, ""), http.DefaultClient)
+
+	data, err := svc.GetTransactionByTxID(context.Background(), "testtxid")
+	assert.NoError(t, err)
+	assert.NotNil(t, data.Inputs[0].ScriptAnalysis)
+	assert.Equal(t, "p2wpkh", data.Inputs[0].ScriptAnalysis.Type)
+	assert.NotNil(t, data.Out[0].ScriptAnalysis)
+	assert.Equal(t, "op_return", data.Out[0].ScriptAnalysis.Type)
+	assert.Equal(t, "hello", *data.Out[0].ScriptAnalysis.OpReturn.Text)
+}
+
+func TestGetTransactionByAddress_Success(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rawaddr/bc1qexample", r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{
+			"address": "bc1qexample",
+			"n_tx": 1,
+			"total_received": 5000,
+			"total_sent": 0,
+			"final_balance": 5000,
+			"txs": [{"hash": "abc", "time": 1650000000, "block_height": 123, "result": 5000, "balance": 5000, "fee": 141}]
+		}`))
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	svc := services.NewTransactionService(makeTestEnvConfig(server.URL, ""), http.DefaultClient)
+
+	data, err := svc.GetTransactionByAddress(context.Background(), "bc1qexample")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5000), data.FinalBalance)
+	assert.Len(t, data.Transactions, 1)
+	assert.Equal(t, "abc", data.Transactions[0].Hash)
+	assert.Equal(t, int64(141), data.Transactions[0].Fee)
+}
+
+func TestGetTransactionByAddress_Non200Status(t *testing.T) {
+	handler := func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	svc := services.NewTransactionService(makeTestEnvConfig(server.URL, ""), http.DefaultClient)
+
+	data, err := svc.GetTransactionByAddress(context.Background(), "bc1qexample")
+	assert.Error(t, err)
+	assert.Nil(t, data)
+	assert.Contains(t, err.Error(), "external API returned status 404")
+}
+
+func TestStreamAddressTransactions_Pages(t *testing.T) {
+	var offsets []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "50", r.URL.Query().Get("limit"))
+		offset := r.URL.Query().Get("offset")
+		offsets = append(offsets, offset)
+
+		count := services.AddressPageSize
+		if offset != "0" {
+			count = 2
+		}
+		txs := make([]string, count)
+		for i := range txs {
+			txs[i] = fmt.Sprintf(`{"hash": "%s-%d", "result": 1000}`, offset, i)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintf(w, `{"n_tx": %d, "txs": [%s]}`, services.AddressPageSize+2, strings.Join(txs, ","))
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	svc := services.NewTransactionService(makeTestEnvConfig(server.URL, ""), http.DefaultClient)
+
+	var hashes []string
+	err := svc.StreamAddressTransactions(context.Background(), "bc1qexample", func(tx WalletExplorer.AddressTransaction) error {
+		hashes = append(hashes, tx.Hash)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0", "50"}, offsets)
+	assert.Len(t, hashes, services.AddressPageSize+2)
+	assert.Equal(t, "50-1", hashes[len(hashes)-1])
+}
+
+func TestStreamAddressTransactions_StopsOnCallbackError(t *testing.T) {
+	calls := 0
+	handler := func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"n_tx": 500, "txs": [{"hash": "a"}, {"hash": "b"}]}`))
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	svc := services.NewTransactionService(makeTestEnvConfig(server.URL, ""), http.DefaultClient)
+
+	stop := errors.New("stop")
+	err := svc.StreamAddressTransactions(context.Background(), "bc1qexample", func(WalletExplorer.AddressTransaction) error {
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestGetBlockHeight(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/q/getblockcount", r.URL.Path)
+		_, _ = w.Write([]byte("840000\n"))
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	svc := services.NewTransactionService(makeTestEnvConfig(server.URL, ""), http.DefaultClient)
+
+	height, err := svc.GetBlockHeight(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 840000, height)
+}
@@ -0,0 +1,391 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"cry-api/app/bitcoin"
+	UserModel "cry-api/app/models"
+	services "cry-api/app/services/watchlist"
+	app_errors "cry-api/app/types/errors"
+	WalletExplorer "cry-api/app/types/wallet_explorer"
+	WatchlistTypes "cry-api/app/types/watchlist"
+	testmocks "cry-api/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// BIP84 test vector account key (m/84'/0'/0') and its first receive address
+const (
+	bip84Zpub    = "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs"
+	bip84Address = "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu"
+)
+
+func newWatchlistService() (*services.WatchlistService, *testmocks.MockWatchedWalletRepository, *testmocks.MockTransactionService) {
+	repo := new(testmocks.MockWatchedWalletRepository)
+	txService := new(testmocks.MockTransactionService)
+	return services.NewWatchlistService(repo, txService), repo, txService
+}
+
+func TestAddWallet_Address(t *testing.T) {
+	svc, repo, _ := newWatchlistService()
+
+	repo.On("FindByValue", 1, bip84Address).Return(nil, nil).Once()
+	repo.On("Save", mock.AnythingOfType("*models.WatchedWallet")).Return(nil).Once()
+
+	wallet, err := svc.AddWallet(1, WatchlistTypes.ICreateWatchedWalletRequest{
+		Kind:  "Address",
+		Value: "  " + bip84Address + " ",
+		Label: " Savings ",
+		Color: "#A1B2C3",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, wallet.UserID)
+	assert.Equal(t, "address", wallet.Kind)
+	assert.Equal(t, bip84Address, wallet.Value)
+	assert.Equal(t, "Savings", wallet.Label)
+	assert.Equal(t, "#a1b2c3", wallet.Color)
+	repo.AssertExpectations(t)
+}
+
+func TestAddWallet_DescriptorIsNormalized(t *testing.T) {
+	svc, repo, _ := newWatchlistService()
+
+	desc, err := bitcoin.DescriptorFromExtendedKey(bip84Zpub, false)
+	assert.NoError(t, err)
+	normalized := desc.StringWithChecksum()
+
+	repo.On("FindByValue", 1, normalized).Return(nil, nil).Once()
+	repo.On("Save", mock.AnythingOfType("*models.WatchedWallet")).Return(nil).Once()
+
+	wallet, err := svc.AddWallet(1, WatchlistTypes.ICreateWatchedWalletRequest{
+		Kind:  "descriptor",
+		Value: desc.String(),
+		Label: "Descriptor",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, normalized, wallet.Value)
+	assert.Equal(t, services.DefaultColor, wallet.Color)
+}
+
+func TestAddWallet_ValidationErrors(t *testing.T) {
+	svc, _, _ := newWatchlistService()
+
+	cases := []struct {
+		name  string
+		req   WatchlistTypes.ICreateWatchedWalletRequest
+		field string
+	}{
+		{"unknown kind", WatchlistTypes.ICreateWatchedWalletRequest{Kind: "iban", Value: "x", Label: "x"}, "kind"},
+		{"bad address", WatchlistTypes.ICreateWatchedWalletRequest{Kind: "address", Value: "bc1qnotanaddress", Label: "x"}, "value"},
+		{"bad xpub", WatchlistTypes.ICreateWatchedWalletRequest{Kind: "xpub", Value: "xpubnope", Label: "x"}, "value"},
+		{"bad descriptor", WatchlistTypes.ICreateWatchedWalletRequest{Kind: "descriptor", Value: "wpkh(nope)", Label: "x"}, "value"},
+		{"blank label", WatchlistTypes.ICreateWatchedWalletRequest{Kind: "address", Value: bip84Address, Label: "   "}, "label"},
+		{"bad color", WatchlistTypes.ICreateWatchedWalletRequest{Kind: "address", Value: bip84Address, Label: "x", Color: "red"}, "color"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := svc.AddWallet(1, tc.req)
+			var validationErr *app_errors.ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tc.field, validationErr.Field)
+		})
+	}
+}
+
+func TestAddWallet_Duplicate(t *testing.T) {
+	svc, repo, _ := newWatchlistService()
+
+	repo.On("FindByValue", 1, bip84Zpub).Return(&UserModel.WatchedWallet{ID: 4}, nil).Once()
+
+	_, err := svc.AddWallet(1, WatchlistTypes.ICreateWatchedWalletRequest{Kind: "xpub", Value: bip84Zpub, Label: "Cold"})
+
+	var conflictErr *app_errors.ConflictError
+	assert.ErrorAs(t, err, &conflictErr)
+	repo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestUpdateWallet(t *testing.T) {
+	svc, repo, _ := newWatchlistService()
+
+	existing := &UserModel.WatchedWallet{ID: 3, UserID: 1, Label: "Old", Color: "#000000"}
+	repo.On("FindByIDAndUserID", 3, 1).Return(existing, nil).Once()
+	repo.On("Save", existing).Return(nil).Once()
+
+	label := "New"
+	wallet, err := svc.UpdateWallet(1, 3, WatchlistTypes.IUpdateWatchedWalletRequest{Label: &label})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "New", wallet.Label)
+	assert.Equal(t, "#000000", wallet.Color)
+	repo.AssertExpectations(t)
+}
+
+func TestDeleteWallet_NotFound(t *testing.T) {
+	svc, repo, _ := newWatchlistService()
+
+	repo.On("FindByIDAndUserID", 9, 1).Return(nil, nil).Once()
+
+	err := svc.DeleteWallet(1, 9)
+
+	var notFoundErr *app_errors.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestGetSummary_AggregatesAcrossWallets(t *testing.T) {
+	svc, repo, txService := newWatchlistService()
+
+	desc, err := bitcoin.DescriptorFromExtendedKey(bip84Zpub, false)
+	assert.NoError(t, err)
+
+	repo.On("FindByUserID", 1).Return([]UserModel.WatchedWallet{
+		{ID: 1, Kind: "address", Value: bip84Address, Label: "Hot", Color: "#ff0000"},
+		{ID: 2, Kind: "xpub", Value: "xpub-cold", Label: "Cold", Color: "#00ff00"},
+		{ID: 3, Kind: "descriptor", Value: desc.StringWithChecksum(), Label: "Desc", Color: "#0000ff"},
+		{ID: 4, Kind: "xpub", Value: "xpub-broken", Label: "Broken", Color: "#ffffff"},
+	}, nil).Once()
+
+	txService.On("GetTransactionByAddress", bip84Address).Return(&WalletExplorer.ITransactionAddress{
+		NTx:          1,
+		FinalBalance: 150000000,
+		Transactions: []WalletExplorer.AddressTransaction{{Hash: "a1", Time: 300, Result: 150000000}},
+	}, nil).Once()
+	txService.On("GetTransactionByXPUB", "xpub-cold").Return(&WalletExplorer.ITransactionXPUB{
+		Found: true,
+		Transactions: []WalletExplorer.XPUBTransaction{
+			{TxID: "c1", Time: 100, BalanceDiff: 0.5},
+			{TxID: "c2", Time: 400, BalanceDiff: -0.1},
+		},
+	}, nil).Once()
+	// The single-key wpkh descriptor is looked up through its zpub account key
+	txService.On("GetTransactionByXPUB", bip84Zpub).Return(&WalletExplorer.ITransactionXPUB{
+		Transactions: []WalletExplorer.XPUBTransaction{{TxID: "d1", Time: 200, BalanceDiff: 0.25}},
+	}, nil).Once()
+	txService.On("GetTransactionByXPUB", "xpub-broken").Return(nil, errors.New("upstream down")).Once()
+
+	summary, err := svc.GetSummary(context.Background(), 1)
+	assert.NoError(t, err)
+
+	assert.InDelta(t, 2.15, summary.TotalBalance, 1e-9)
+	assert.Len(t, summary.Wallets, 4)
+	assert.InDelta(t, 1.5, summary.Wallets[0].Balance, 1e-9)
+	assert.InDelta(t, 0.4, summary.Wallets[1].Balance, 1e-9)
+	assert.Equal(t, 2, summary.Wallets[1].TxCount)
+	assert.InDelta(t, 0.25, summary.Wallets[2].Balance, 1e-9)
+	assert.NotNil(t, summary.Wallets[3].Error)
+	assert.Equal(t, "Lookup failed", *summary.Wallets[3].Error)
+
+	var txids []string
+	for _, activity := range summary.RecentActivity {
+		txids = append(txids, activity.TxID)
+	}
+	assert.Equal(t, []string{"c2", "a1", "d1", "c1"}, txids)
+	txService.AssertExpectations(t)
+}
+
+func TestGetSummary_UnsupportedDescriptor(t *testing.T) {
+	svc, repo, _ := newWatchlistService()
+
+	repo.On("FindByUserID", 1).Return([]UserModel.WatchedWallet{
+		{ID: 1, Kind: "descriptor", Value: "tr(" + bip84Zpub + "/0/*)", Label: "Taproot"},
+	}, nil).Once()
+
+	summary, err := svc.GetSummary(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.NotNil(t, summary.Wallets[0].Error)
+	assert.Zero(t, summary.TotalBalance)
+	assert.Empty(t, summary.RecentActivity)
+}
+
+func TestGetSummary_AccountDescriptorsCountOnce(t *testing.T) {
+	svc, repo, txService := newWatchlistService()
+
+	receive, err := bitcoin.DescriptorFromExtendedKey(bip84Zpub, false)
+	assert.NoError(t, err)
+	change, err := bitcoin.DescriptorFromExtendedKey(bip84Zpub, true)
+	assert.NoError(t, err)
+
+	repo.On("FindByUserID", 1).Return([]UserModel.WatchedWallet{
+		{ID: 1, Kind: "descriptor", Value: receive.StringWithChecksum(), Label: "Receive"},
+		{ID: 2, Kind: "descriptor", Value: change.StringWithChecksum(), Label: "Change"},
+		{ID: 3, Kind: "xpub", Value: "xpub-cold", Label: "Cold"},
+	}, nil).Once()
+	txService.On("GetTransactionByXPUB", bip84Zpub).Return(&WalletExplorer.ITransactionXPUB{
+		Transactions: []WalletExplorer.XPUBTransaction{{TxID: "d1", Time: 200, BalanceDiff: 0.1}, {TxID: "d2", Time: 300, BalanceDiff: 0.2}},
+	}, nil).Twice()
+	txService.On("GetTransactionByXPUB", "xpub-cold").Return(&WalletExplorer.ITransactionXPUB{
+		Transactions: []WalletExplorer.XPUBTransaction{{TxID: "c1", Time: 100, BalanceDiff: 0.00000001}},
+	}, nil).Once()
+
+	summary, err := svc.GetSummary(context.Background(), 1)
+	assert.NoError(t, err)
+
+	// Both descriptors resolve to the same account key: each shows it, the total counts it once
+	assert.Equal(t, 0.3, summary.Wallets[0].Balance)
+	assert.Equal(t, 0.3, summary.Wallets[1].Balance)
+	assert.Equal(t, 0.30000001, summary.TotalBalance)
+	assert.Len(t, summary.RecentActivity, 3)
+	txService.AssertExpectations(t)
+}
+
+func TestGetSummary_DescriptorChainsBeyondChange(t *testing.T) {
+	svc, repo, _ := newWatchlistService()
+
+	receive, err := bitcoin.DescriptorFromExtendedKey(bip84Zpub, false)
+	assert.NoError(t, err)
+	other := strings.Replace(receive.String(), "/0/*", "/2/*", 1)
+	_, err = bitcoin.ParseDescriptor(other)
+	assert.NoError(t, err)
+
+	repo.On("FindByUserID", 1).Return([]UserModel.WatchedWallet{
+		{ID: 1, Kind: "descriptor", Value: other, Label: "Other chain"},
+	}, nil).Once()
+
+	summary, err := svc.GetSummary(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.NotNil(t, summary.Wallets[0].Error, "the account lookup only covers the receive and change chains")
+	assert.Zero(t, summary.TotalBalance)
+}
+
+func TestGetWalletHistory_OldestFirst(t *testing.T) {
+	svc, _, txService := newWatchlistService()
+
+	txService.On("GetTransactionByAddress", bip84Address).Return(&WalletExplorer.ITransactionAddress{
+		Transactions: []WalletExplorer.AddressTransaction{
+			{Hash: "newer", Time: 200, Result: -1000},
+			{Hash: "older", Time: 100, Result: 5000},
+		},
+	}, nil).Once()
+
+	history, err := svc.GetWalletHistory(context.Background(), &UserModel.WatchedWallet{ID: 1, Kind: "address", Value: bip84Address})
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, "older", history[0].TxID)
+	assert.InDelta(t, 0.00005, history[0].BalanceDiff, 1e-12)
+	assert.InDelta(t, -0.00001, history[1].BalanceDiff, 1e-12)
+}
+
+func TestGetWalletHistory_WalksEveryPage(t *testing.T) {
+	svc, _, txService := newWatchlistService()
+	wallet := &UserModel.WatchedWallet{ID: 1, Kind: "address", Value: bip84Address}
+
+	txService.On("GetTransactionByAddress", bip84Address).Return(&WalletExplorer.ITransactionAddress{
+		NTx:          3,
+		FinalBalance: 4500,
+		Transactions: []WalletExplorer.AddressTransaction{{Hash: "newest", Time: 300, Result: -1500}},
+	}, nil).Once()
+	txService.On("StreamAddressTransactions", bip84Address).Return([]WalletExplorer.AddressTransaction{
+		{Hash: "newest", Time: 300, Result: -1500},
+		{Hash: "middle", Time: 200, Result: 1000},
+		{Hash: "middle", Time: 200, Result: 1000},
+		{Hash: "oldest", Time: 100, Result: 5000},
+	}, nil).Once()
+
+	history, err := svc.GetWalletHistory(context.Background(), wallet)
+	assert.NoError(t, err)
+	assert.Len(t, history, 3)
+	assert.Equal(t, "oldest", history[0].TxID)
+	assert.Equal(t, "newest", history[2].TxID)
+}
+
+func TestGetWalletHistory_IncompleteHistory(t *testing.T) {
+	wallet := &UserModel.WatchedWallet{ID: 1, Kind: "address", Value: bip84Address}
+	page := &WalletExplorer.ITransactionAddress{
+		NTx:          2,
+		FinalBalance: 4000,
+		Transactions: []WalletExplorer.AddressTransaction{{Hash: "newest", Time: 200, Result: -1000}},
+	}
+
+	t.Run("Missing transactions", func(t *testing.T) {
+		svc, _, txService := newWatchlistService()
+		txService.On("GetTransactionByAddress", bip84Address).Return(page, nil).Once()
+		txService.On("StreamAddressTransactions", bip84Address).Return(page.Transactions, nil).Once()
+
+		_, err := svc.GetWalletHistory(context.Background(), wallet)
+		assert.ErrorIs(t, err, services.ErrIncompleteHistory)
+	})
+
+	t.Run("Balance mismatch", func(t *testing.T) {
+		svc, _, txService := newWatchlistService()
+		txService.On("GetTransactionByAddress", bip84Address).Return(page, nil).Once()
+		txService.On("StreamAddressTransactions", bip84Address).Return([]WalletExplorer.AddressTransaction{
+			{Hash: "newest", Time: 200, Result: -1000},
+			{Hash: "oldest", Time: 100, Result: 3000},
+		}, nil).Once()
+
+		_, err := svc.GetWalletHistory(context.Background(), wallet)
+		assert.ErrorIs(t, err, services.ErrIncompleteHistory)
+	})
+}
+
+func TestGetRecentWalletActivity_OnlyReadsTheNewestPage(t *testing.T) {
+	svc, _, txService := newWatchlistService()
+
+	txService.On("GetTransactionByAddress", bip84Address).Return(&WalletExplorer.ITransactionAddress{
+		NTx:          80,
+		Transactions: []WalletExplorer.AddressTransaction{{Hash: "newest", Time: 200, Result: -1000}},
+	}, nil).Once()
+
+	activity, err := svc.GetRecentWalletActivity(context.Background(), &UserModel.WatchedWallet{ID: 1, Kind: "address", Value: bip84Address})
+	assert.NoError(t, err)
+	assert.Len(t, activity, 1)
+	txService.AssertNotCalled(t, "StreamAddressTransactions", bip84Address)
+}
+
+func TestGetHistory_MergesTransfersBetweenOwnWallets(t *testing.T) {
+	svc, repo, txService := newWatchlistService()
+
+	repo.On("FindByUserID", 1).Return([]UserModel.WatchedWallet{
+		{ID: 1, Kind: "address", Value: bip84Address, Label: "Hot"},
+		{ID: 2, Kind: "xpub", Value: "xpub-cold", Label: "Cold"},
+		{ID: 3, Kind: "xpub", Value: "xpub-broken", Label: "Broken"},
+	}, nil).Once()
+	txService.On("GetTransactionByAddress", bip84Address).Return(&WalletExplorer.ITransactionAddress{
+		Transactions: []WalletExplorer.AddressTransaction{
+			{Hash: "move", Time: 200, Result: -50000000},
+			{Hash: "buy", Time: 100, Result: 100000000},
+		},
+	}, nil).Once()
+	txService.On("GetTransactionByXPUB", "xpub-cold").Return(&WalletExplorer.ITransactionXPUB{
+		Transactions: []WalletExplorer.XPUBTransaction{{TxID: "move", Time: 200, BalanceDiff: 0.4999}},
+	}, nil).Once()
+	txService.On("GetTransactionByXPUB", "xpub-broken").Return(nil, errors.New("upstream down")).Once()
+
+	history, err := svc.GetHistory(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, history.Transactions, 2)
+	assert.Equal(t, "buy", history.Transactions[0].TxID)
+	assert.Equal(t, "move", history.Transactions[1].TxID)
+	assert.InDelta(t, -0.0001, history.Transactions[1].BalanceDiff, 1e-12)
+	assert.Equal(t, []string{"Broken: Lookup failed"}, history.Warnings)
+}
+
+func TestGetHistory_AccountDescriptorsMergeOnce(t *testing.T) {
+	svc, repo, txService := newWatchlistService()
+
+	receive, err := bitcoin.DescriptorFromExtendedKey(bip84Zpub, false)
+	assert.NoError(t, err)
+	change, err := bitcoin.DescriptorFromExtendedKey(bip84Zpub, true)
+	assert.NoError(t, err)
+
+	repo.On("FindByUserID", 1).Return([]UserModel.WatchedWallet{
+		{ID: 1, Kind: "descriptor", Value: receive.StringWithChecksum(), Label: "Receive"},
+		{ID: 2, Kind: "descriptor", Value: change.StringWithChecksum(), Label: "Change"},
+	}, nil).Once()
+	txService.On("GetTransactionByXPUB", bip84Zpub).Return(&WalletExplorer.ITransactionXPUB{
+		Transactions: []WalletExplorer.XPUBTransaction{{TxID: "d1", Time: 200, BalanceDiff: 0.1}},
+	}, nil).Once()
+
+	history, err := svc.GetHistory(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, history.Transactions, 1)
+	assert.Equal(t, 0.1, history.Transactions[0].BalanceDiff)
+	txService.AssertExpectations(t)
+}